@@ -12,7 +12,15 @@ import (
 // Alert endpoint
 func createAlertEndpoint(svc alerts.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return genericResponse{}, nil
+		req := request.(createAlertReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		alert, err := svc.CreateAlert(ctx, req.token, req.alert)
+		if err != nil {
+			return nil, err
+		}
+		return alertResponse{Alert: alert}, nil
 	}
 }
 
@@ -24,13 +32,28 @@ func listAlertsEndpoint(svc alerts.Service) endpoint.Endpoint {
 
 func alertInfoEndpoint(svc alerts.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return genericResponse{}, nil
+		req := request.(alertRequestInfo)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		alert, err := svc.RetrieveAlert(ctx, req.token, req.alertName)
+		if err != nil {
+			return nil, err
+		}
+		return alertResponse{Alert: alert}, nil
 	}
 }
 
 func updateAlertEndpoint(svc alerts.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return genericResponse{}, nil
+		req := request.(updateAlertReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.UpdateAlert(ctx, req.token, req.alert); err != nil {
+			return nil, err
+		}
+		return updateAlertResponse{}, nil
 	}
 }
 
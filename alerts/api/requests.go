@@ -18,6 +18,12 @@ type createAlertReq struct {
 }
 
 func (req createAlertReq) validate() error {
+	if req.token == "" {
+		return alerts.ErrUnauthorizedAccess
+	}
+	if req.alert.Name == "" {
+		return alerts.ErrMalformedEntity
+	}
 	return nil
 }
 
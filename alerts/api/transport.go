@@ -48,7 +48,7 @@ func MakeHandler(svc alerts.Service, tracer opentracing.Tracer, l log.Logger) ht
 
 	mux.Post("/alerts", kithttp.NewServer(
 		kitot.TraceServer(tracer, "create")(createAlertEndpoint(svc)),
-		decodeNewAlertRequest,
+		decodeCreateAlert,
 		encodeResponse,
 		opts...,
 	))
@@ -61,15 +61,15 @@ func MakeHandler(svc alerts.Service, tracer opentracing.Tracer, l log.Logger) ht
 	))
 
 	mux.Get("/alerts/:alertName", kithttp.NewServer(
-		kitot.TraceServer(tracer, "realm_info")(alertInfoEndpoint(svc)),
-		decodeAlertRequest,
+		kitot.TraceServer(tracer, "alert_info")(alertInfoEndpoint(svc)),
+		decodeViewAlert,
 		encodeResponse,
 		opts...,
 	))
 
 	mux.Put("/alerts", kithttp.NewServer(
 		kitot.TraceServer(tracer, "update_alert")(updateAlertEndpoint(svc)),
-		decodeNewAlertRequest,
+		decodeUpdateAlert,
 		encodeResponse,
 		opts...,
 	))
@@ -129,6 +129,39 @@ func decodeNewAlertRequest(_ context.Context, r *http.Request) (interface{}, err
 	return nil, nil
 }
 
+func decodeCreateAlert(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, ErrUnsupportedContentType
+	}
+
+	req := createAlertReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req.alert); err != nil {
+		return nil, errors.Wrap(ErrFailedDecode, err)
+	}
+	return req, nil
+}
+
+func decodeViewAlert(_ context.Context, r *http.Request) (interface{}, error) {
+	req := alertRequestInfo{
+		token:     r.Header.Get("Authorization"),
+		alertName: bone.GetValue(r, "alertName"),
+	}
+	return req, nil
+}
+
+func decodeUpdateAlert(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, ErrUnsupportedContentType
+	}
+
+	req := updateAlertReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req.alert); err != nil {
+		return nil, errors.Wrap(ErrFailedDecode, err)
+	}
+	req.alertName = req.alert.Name
+	return req, nil
+}
+
 func decodeListAlertsRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, ErrUnsupportedContentType
@@ -208,7 +241,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		case errors.Contains(errorVal, io.EOF):
 			w.WriteHeader(http.StatusBadRequest)
 		case errors.Contains(errorVal, alerts.ErrAlertNotFound):
-			w.WriteHeader(http.StatusBadRequest)
+			w.WriteHeader(http.StatusNotFound)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
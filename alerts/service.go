@@ -126,6 +126,9 @@ func (svc alertService) RetrieveAlert(ctx context.Context, token, name string) (
 	}
 
 	alert, err := svc.alerts.Retrieve(ctx, owner, name)
+	if err == ErrNotFound {
+		return Alert{}, ErrAlertNotFound
+	}
 	if err != nil {
 		return Alert{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
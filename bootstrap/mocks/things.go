@@ -221,6 +221,22 @@ func (svc *mainfluxThings) Identify(context.Context, string) (string, error) {
 	panic("not implemented")
 }
 
+func (svc *mainfluxThings) SaveSchema(context.Context, string, string, things.Schema) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ViewSchema(context.Context, string, string) (things.Schema, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemoveSchema(context.Context, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ChannelSchema(context.Context, string) (things.Schema, error) {
+	panic("not implemented")
+}
+
 func findIndex(list []string, val string) int {
 	for i, v := range list {
 		if v == val {
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,7 +13,7 @@ import (
 
 var createCommand cobra.Command = cobra.Command{
 	Use:   "create",
-	Short: "create kuiper stream and rule",
+	Short: "create kuiper stream, rule and plugin",
 	Run:   func(cmd *cobra.Command, args []string) {},
 }
 
@@ -77,6 +78,97 @@ var createRuleCommand cobra.Command = cobra.Command{
 	},
 }
 
+var createPluginCommand cobra.Command = cobra.Command{
+	Use:   "plugin",
+	Short: "create plugin <plugin_type> <plugin_name> [zip_url | -f zip_file] <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 4 && len(args) != 5 {
+			logUsage("create plugin <plugin_type> <plugin_name> [zip_url | -f zip_file] <user_auth_token>\n")
+			return
+		}
+		ptype, err := getPluginType(args[0])
+		if err != nil {
+			logError(err)
+			return
+		}
+		if len(args) == 5 { // upload local zip file
+			err = sdk.UploadKuiperPlugin(ptype, args[1], args[3], args[4])
+		} else {
+			err = sdk.InstallKuiperPlugin(ptype, args[1], args[2], args[3])
+		}
+		if err != nil {
+			logError(err)
+			return
+		}
+		logCreated(args[1])
+	},
+}
+
+var createFunctionCommand cobra.Command = cobra.Command{
+	Use:   "function",
+	Short: "create function <create_function_sql> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("create function <create_function_sql> <user_auth_token>\n")
+			return
+		}
+		if err := sdk.CreateKuiperFunction(args[0], args[1]); err != nil {
+			logError(err)
+			return
+		}
+		logOK()
+	},
+}
+
+var createTemplateCommand cobra.Command = cobra.Command{
+	Use:   "template",
+	Short: "create template <template_name> [template_json | -f template_def_file] <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		var tmpl string
+		switch len(args) {
+		case 4: // using template definition file
+			def, err := readDef(args[2], "template")
+			if err != nil {
+				logError(err)
+				return
+			}
+			tmpl = string(def)
+		case 3:
+			tmpl = args[1]
+		default:
+			logUsage("create template <template_name> [template_json | -f template_def_file] <user_auth_token>\n")
+			return
+		}
+		if err := sdk.CreateKuiperRuleTemplate(args[0], tmpl, args[len(args)-1]); err != nil {
+			logError(err)
+			return
+		}
+		logCreated(args[0])
+	},
+}
+
+var instantiateCommand cobra.Command = cobra.Command{
+	Use:   "instantiate",
+	Short: "instantiate <template_name> <rule_name> <params_json> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 4 {
+			logUsage("instantiate <template_name> <rule_name> <params_json> <user_auth_token>\n")
+			return
+		}
+		params := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(args[2]), &params); err != nil {
+			logError(err)
+			return
+		}
+		id, err := sdk.InstantiateKuiperRuleTemplate(args[0], args[1], params, args[3])
+		if err != nil {
+			logError(err)
+			return
+		}
+		logCreated(id)
+	},
+}
+
 var describeCommand = cobra.Command{
 	Use:   "describe",
 	Short: "describe stream $stream_name | describe rule $rule_name | describe plugin $plugin_type $plugin_name",
@@ -122,6 +214,38 @@ var describePluginCommand cobra.Command = cobra.Command{
 	Run:   func(cmd *cobra.Command, args []string) {},
 }
 
+var describeFunctionCommand cobra.Command = cobra.Command{
+	Use:   "function",
+	Short: "describe function <function_name> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("describe function <function_name> <user_auth_token>.\n")
+			return
+		}
+		if reply, err := sdk.KuiperFunction(args[0], args[1]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
+var describeTemplateCommand cobra.Command = cobra.Command{
+	Use:   "template",
+	Short: "describe template <template_name> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("describe template <template_name> <user_auth_token>.\n")
+			return
+		}
+		if reply, err := sdk.KuiperRuleTemplate(args[0], args[1]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
 var dropCommand cobra.Command = cobra.Command{
 	Use:   "drop",
 	Short: "drop stream $stream_name | drop rule $rule_name | drop plugin $plugin_type $plugin_name -r $stop",
@@ -158,8 +282,55 @@ var dropRuleCommand cobra.Command = cobra.Command{
 
 var dropPluginCommand cobra.Command = cobra.Command{
 	Use:   "plugin",
-	Short: "drop plugin <plugin_type> <plugin_name> -s stop",
-	Run:   func(cmd *cobra.Command, args []string) {},
+	Short: "drop plugin <plugin_type> <plugin_name> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 3 {
+			logUsage("drop plugin <plugin_type> <plugin_name> <user_auth_token>")
+			return
+		}
+		ptype, err := getPluginType(args[0])
+		if err != nil {
+			logError(err)
+			return
+		}
+		if err := sdk.DeleteKuiperPlugin(ptype, args[1], args[2]); err != nil {
+			logError(err)
+			return
+		}
+		logOK()
+	},
+}
+
+var dropFunctionCommand cobra.Command = cobra.Command{
+	Use:   "function",
+	Short: "drop function <function_name> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("drop function <function_name> <user_auth_token>")
+			return
+		}
+		if err := sdk.DeleteKuiperFunction(args[0], args[1]); err != nil {
+			logError(err)
+			return
+		}
+		logOK()
+	},
+}
+
+var dropTemplateCommand cobra.Command = cobra.Command{
+	Use:   "template",
+	Short: "drop template <template_name> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("drop template <template_name> <user_auth_token>")
+			return
+		}
+		if err := sdk.DeleteKuiperRuleTemplate(args[0], args[1]); err != nil {
+			logError(err)
+			return
+		}
+		logOK()
+	},
 }
 
 var showStreamsCommand cobra.Command = cobra.Command{
@@ -215,6 +386,38 @@ var showPluginsCommand cobra.Command = cobra.Command{
 	},
 }
 
+var showFunctionsCommand cobra.Command = cobra.Command{
+	Use:   "functions",
+	Short: "functions <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			logUsage("functions <user_auth_token>")
+			return
+		}
+		if reply, err := sdk.KuiperFunctions(args[0]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
+var showTemplatesCommand cobra.Command = cobra.Command{
+	Use:   "templates",
+	Short: "templates <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			logUsage("templates <user_auth_token>")
+			return
+		}
+		if reply, err := sdk.KuiperRuleTemplates(args[0]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
 var statusCommand cobra.Command = cobra.Command{
 	Use:   "status",
 	Short: "list status rule <rule_id>",
@@ -225,11 +428,81 @@ var statusRuleCommand cobra.Command = cobra.Command{
 	Use:   "rule",
 	Short: "status rule <rule_id> <user_auth_token>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) != 3 {
+		if len(args) != 2 {
 			logUsage("status rule <rule_id> <user_auth_token>")
 			return
 		}
-		if reply, err := sdk.KuiperRuleStatus(args[1], args[2]); err != nil {
+		if reply, err := sdk.KuiperRuleStatus(args[0], args[1]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
+var topoCommand cobra.Command = cobra.Command{
+	Use:   "topo",
+	Short: "topo rule <rule_id>",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+var topoRuleCommand cobra.Command = cobra.Command{
+	Use:   "rule",
+	Short: "topo rule <rule_id> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("topo rule <rule_id> <user_auth_token>")
+			return
+		}
+		if reply, err := sdk.KuiperRuleTopo(args[0], args[1]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
+var explainCommand cobra.Command = cobra.Command{
+	Use:   "explain",
+	Short: "explain <select_sql> <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			logUsage("explain <select_sql> <user_auth_token>")
+			return
+		}
+		if reply, err := sdk.KuiperExplainRule("EXPLAIN "+args[0], args[1]); err != nil {
+			logError(err)
+		} else {
+			logJSON(reply)
+		}
+	},
+}
+
+var testCommand cobra.Command = cobra.Command{
+	Use:   "test",
+	Short: "test rule -f rule_test_file <user_auth_token>",
+	Run:   func(cmd *cobra.Command, args []string) {},
+}
+
+var testRuleCommand cobra.Command = cobra.Command{
+	Use:   "rule",
+	Short: "test rule -f rule_test_file <user_auth_token>",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 3 {
+			logUsage("test rule -f rule_test_file <user_auth_token>")
+			return
+		}
+		def, err := readDef(args[1], "rule test")
+		if err != nil {
+			logError(err)
+			return
+		}
+		var t kuiper.RuleTest
+		if err := json.Unmarshal(def, &t); err != nil {
+			logError(err)
+			return
+		}
+		if reply, err := sdk.KuiperTestRule(t, args[2]); err != nil {
 			logError(err)
 		} else {
 			logJSON(reply)
@@ -285,6 +558,8 @@ func getPluginType(arg string) (ptype mfxsdk.KuiperPluginType, err error) {
 		ptype = mfxsdk.KuiperPluginSource
 	case "sink":
 		ptype = mfxsdk.KuiperPluginSink
+	case "function":
+		ptype = mfxsdk.KuiperPluginFunction
 	default:
 		err = fmt.Errorf("Invalid plugin type %s, should be \"source\", \"sink\" or \"function\".\n", arg)
 	}
@@ -317,27 +592,49 @@ func NewKuiperCmd() *cobra.Command {
 	// Create
 	createCommand.AddCommand(&createStreamCommand)
 	createCommand.AddCommand(&createRuleCommand)
+	createCommand.AddCommand(&createPluginCommand)
+	createCommand.AddCommand(&createFunctionCommand)
+	createCommand.AddCommand(&createTemplateCommand)
+	cmd.AddCommand(&createCommand)
 
 	// Describe
 	describeCommand.AddCommand(&describeStreamCommand)
 	describeCommand.AddCommand(&describeRuleCommand)
 	describeCommand.AddCommand(&describePluginCommand)
+	describeCommand.AddCommand(&describeFunctionCommand)
+	describeCommand.AddCommand(&describeTemplateCommand)
 	cmd.AddCommand(&describeCommand)
 
 	// Drop
 	dropCommand.AddCommand(&dropStreamCommand)
 	dropCommand.AddCommand(&dropRuleCommand)
 	dropCommand.AddCommand(&dropPluginCommand)
+	dropCommand.AddCommand(&dropFunctionCommand)
+	dropCommand.AddCommand(&dropTemplateCommand)
 	cmd.AddCommand(&dropCommand)
 
 	// Show
 	cmd.AddCommand(&showStreamsCommand)
 	cmd.AddCommand(&showRulesCommand)
 	cmd.AddCommand(&showPluginsCommand)
+	cmd.AddCommand(&showFunctionsCommand)
+	cmd.AddCommand(&showTemplatesCommand)
 
 	// Status
 	statusCommand.AddCommand(&statusRuleCommand)
 	cmd.AddCommand(&statusCommand)
 
+	// Topo
+	topoCommand.AddCommand(&topoRuleCommand)
+	cmd.AddCommand(&topoCommand)
+
+	// Explain and test
+	cmd.AddCommand(&explainCommand)
+	testCommand.AddCommand(&testRuleCommand)
+	cmd.AddCommand(&testCommand)
+
+	// Rule templates
+	cmd.AddCommand(&instantiateCommand)
+
 	return &cmd
 }
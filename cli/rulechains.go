@@ -0,0 +1,427 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/cloustone/pandas/rulechain/manifest"
+	mfxsdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var errMissingRuleChainID = errors.New("missing rulechain id in manifest")
+
+var (
+	// Channel is the channel that an applied rulechain receives data from
+	Channel string = ""
+	// Subtopic is the subtopic that an applied rulechain receives data from
+	Subtopic string = ""
+	// Interval is the polling interval used to tail rulechain's debug events
+	Interval time.Duration = time.Second
+)
+
+var cmdRuleChains = []cobra.Command{
+	cobra.Command{
+		Use:   "get",
+		Short: "get [all | <rulechain_id>] <user_auth_token>",
+		Long:  `Get all rulechains or rulechain by id`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if args[0] == "all" {
+				l, err := sdk.RuleChains(args[1], uint64(Offset), uint64(Limit))
+				if err != nil {
+					logError(err)
+					return
+				}
+				logJSON(l)
+				return
+			}
+
+			rc, err := sdk.RuleChain(args[0], args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(rc)
+		},
+	},
+	cobra.Command{
+		Use:   "apply",
+		Short: "apply <manifest_file> <user_auth_token>",
+		Long:  `Create rulechain from manifest file or update the deployed one with the same id`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			rc, err := ruleChainFromFile(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			if reason, err := sdk.ValidateRuleChain(rc.Payload, args[1]); err != nil {
+				logError(fmt.Errorf("%s: %s", err, reason))
+				return
+			}
+
+			deployed, err := sdk.RuleChain(rc.ID, args[1])
+			switch {
+			case err == mfxsdk.ErrNotFound:
+				if err := sdk.CreateRuleChain(rc, args[1]); err != nil {
+					logError(err)
+					return
+				}
+				logCreated(rc.ID)
+				return
+			case err != nil:
+				logError(err)
+				return
+			}
+
+			if rc.Channel == "" && rc.SubTopic == "" {
+				rc.Channel = deployed.Channel
+				rc.SubTopic = deployed.SubTopic
+			}
+
+			// Running rulechain can not be updated, restart it with new manifest
+			running := deployed.Status == mfxsdk.RuleChainStarted
+			if running {
+				if err := sdk.StopRuleChain(rc.ID, args[1]); err != nil {
+					logError(err)
+					return
+				}
+			}
+			if err := sdk.UpdateRuleChain(rc, args[1]); err != nil {
+				logError(err)
+				return
+			}
+			if running {
+				if err := sdk.StartRuleChain(rc.ID, args[1]); err != nil {
+					logError(err)
+					return
+				}
+			}
+
+			logOK()
+		},
+	},
+	cobra.Command{
+		Use:   "diff",
+		Short: "diff <manifest_file> <user_auth_token>",
+		Long:  `Show differences between manifest file and the deployed rulechain`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			rc, err := ruleChainFromFile(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			var deployed []byte
+			d, err := sdk.RuleChain(rc.ID, args[1])
+			switch {
+			case err == nil:
+				deployed = d.Payload
+			case err != mfxsdk.ErrNotFound:
+				logError(err)
+				return
+			}
+
+			logDiff(deployed, rc.Payload)
+		},
+	},
+	cobra.Command{
+		Use:   "validate",
+		Short: "validate <manifest_file> <user_auth_token>",
+		Long:  `Validate rulechain manifest file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			data, err := ioutil.ReadFile(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			if reason, err := sdk.ValidateRuleChain(data, args[1]); err != nil {
+				logError(fmt.Errorf("%s: %s", err, reason))
+				return
+			}
+
+			logOK()
+		},
+	},
+	cobra.Command{
+		Use:   "delete",
+		Short: "delete <rulechain_id> <user_auth_token>",
+		Long:  `Removes rulechain`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if err := sdk.DeleteRuleChain(args[0], args[1]); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
+	cobra.Command{
+		Use:   "start",
+		Short: "start <rulechain_id> <user_auth_token>",
+		Long:  `Start rulechain`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if err := sdk.StartRuleChain(args[0], args[1]); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
+	cobra.Command{
+		Use:   "stop",
+		Short: "stop <rulechain_id> <user_auth_token>",
+		Long:  `Stop rulechain`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if err := sdk.StopRuleChain(args[0], args[1]); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	},
+	cobra.Command{
+		Use:   "nodes",
+		Short: "nodes [all | <node_type>] <user_auth_token>",
+		Long:  `List node types by category or get node's configuration description`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			if args[0] == "all" {
+				l, err := sdk.RuleChainNodes(args[1])
+				if err != nil {
+					logError(err)
+					return
+				}
+				logJSON(l)
+				return
+			}
+
+			c, err := sdk.RuleChainNode(args[0], args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			fmt.Printf("\n%s\n\n", c)
+		},
+	},
+	cobra.Command{
+		Use:   "events",
+		Short: "events <rulechain_id> <user_auth_token>",
+		Long:  `Tail debug events of a running rulechain`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			var after uint64
+			for {
+				events, err := sdk.RuleChainEvents(args[0], after, args[1])
+				if err != nil {
+					logError(err)
+					return
+				}
+				for _, e := range events {
+					logJSON(e)
+					after = e.Seq
+				}
+				time.Sleep(Interval)
+			}
+		},
+	},
+}
+
+// NewRuleChainsCmd returns rulechains command.
+func NewRuleChainsCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:   "rulechains",
+		Short: "Rulechains management",
+		Long:  `Rulechains management: apply, diff, validate, get, start, stop or delete rulechain, list nodes and tail debug events`,
+		Run: func(cmd *cobra.Command, args []string) {
+			logUsage("rulechains [get | apply | diff | validate | delete | start | stop | nodes | events]")
+		},
+	}
+
+	for i := range cmdRuleChains {
+		switch cmdRuleChains[i].Use {
+		case "apply":
+			cmdRuleChains[i].Flags().StringVar(&Channel, "channel", "", "channel that rulechain receives data from")
+			cmdRuleChains[i].Flags().StringVar(&Subtopic, "subtopic", "", "subtopic that rulechain receives data from")
+		case "events":
+			cmdRuleChains[i].Flags().DurationVar(&Interval, "interval", time.Second, "polling interval")
+		}
+		cmd.AddCommand(&cmdRuleChains[i])
+	}
+
+	return &cmd
+}
+
+func ruleChainFromFile(path string) (mfxsdk.RuleChain, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return mfxsdk.RuleChain{}, err
+	}
+
+	m, err := manifest.New(data)
+	if err != nil {
+		return mfxsdk.RuleChain{}, err
+	}
+	if m.RuleChain.Id == "" {
+		return mfxsdk.RuleChain{}, errMissingRuleChainID
+	}
+
+	return mfxsdk.RuleChain{
+		ID:        m.RuleChain.Id,
+		Name:      m.RuleChain.Name,
+		DebugMode: m.RuleChain.DebugMode,
+		Root:      m.RuleChain.Root,
+		Channel:   Channel,
+		SubTopic:  Subtopic,
+		Payload:   data,
+	}, nil
+}
+
+// logDiff prints line differences between two normalized JSON documents
+func logDiff(old, new []byte) {
+	a, err := normalizeJSON(old)
+	if err != nil {
+		logError(err)
+		return
+	}
+	b, err := normalizeJSON(new)
+	if err != nil {
+		logError(err)
+		return
+	}
+
+	changed := false
+	fmt.Println()
+	for _, l := range diffLines(a, b) {
+		switch l[0] {
+		case '-':
+			changed = true
+			fmt.Println(color.RedString(l))
+		case '+':
+			changed = true
+			fmt.Println(color.GreenString(l))
+		default:
+			fmt.Println(l)
+		}
+	}
+	if !changed {
+		fmt.Printf("%s\n", color.BlueString("no changes"))
+	}
+	fmt.Println()
+}
+
+func normalizeJSON(data []byte) ([]string, error) {
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(b), "\n"), nil
+}
+
+// diffLines returns lines of a and b prefixed with '-', '+' or ' ' based on
+// the longest common subsequence of both
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := []string{}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+"+b[j])
+	}
+	return lines
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeJSON(t *testing.T) {
+	cases := map[string]struct {
+		data  string
+		lines []string
+		err   bool
+	}{
+		"normalize empty data": {
+			data:  "",
+			lines: []string{},
+		},
+		"normalize object with unordered keys": {
+			data:  `{"b":1,"a":{"c":[true]}}`,
+			lines: []string{"{", `  "a": {`, `    "c": [`, "      true", "    ]", "  },", `  "b": 1`, "}"},
+		},
+		"normalize malformed data": {
+			data: `{"a":`,
+			err:  true,
+		},
+	}
+
+	for desc, tc := range cases {
+		lines, err := normalizeJSON([]byte(tc.data))
+		if tc.err {
+			assert.NotNil(t, err, fmt.Sprintf("%s: expected error", desc))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.lines, lines, fmt.Sprintf("%s: expected %v got %v", desc, tc.lines, lines))
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	cases := map[string]struct {
+		a    []string
+		b    []string
+		diff []string
+	}{
+		"diff equal lines": {
+			a:    []string{"a", "b"},
+			b:    []string{"a", "b"},
+			diff: []string{" a", " b"},
+		},
+		"diff added lines": {
+			a:    []string{},
+			b:    []string{"a", "b"},
+			diff: []string{"+a", "+b"},
+		},
+		"diff removed lines": {
+			a:    []string{"a", "b"},
+			b:    []string{},
+			diff: []string{"-a", "-b"},
+		},
+		"diff changed line": {
+			a:    []string{"a", "b", "c"},
+			b:    []string{"a", "x", "c"},
+			diff: []string{" a", "-b", "+x", " c"},
+		},
+		"diff moved line": {
+			a:    []string{"a", "b", "c"},
+			b:    []string{"b", "c", "a"},
+			diff: []string{"-a", " b", " c", "+a"},
+		},
+	}
+
+	for desc, tc := range cases {
+		diff := diffLines(tc.a, tc.b)
+		assert.Equal(t, tc.diff, diff, fmt.Sprintf("%s: expected %v got %v", desc, tc.diff, diff))
+	}
+}
+
+func TestDiffManifests(t *testing.T) {
+	a, err := normalizeJSON([]byte(`{"name":"a","nodes":[1,2]}`))
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	b, err := normalizeJSON([]byte(`{"nodes":[1,2],"name":"b"}`))
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	diff := diffLines(a, b)
+	changed := []string{}
+	for _, line := range diff {
+		if line[0] != ' ' {
+			changed = append(changed, line)
+		}
+	}
+	assert.Equal(t, []string{`-  "name": "a",`, `+  "name": "b",`}, changed, fmt.Sprintf("expected only the name to change got %v", changed))
+}
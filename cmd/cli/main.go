@@ -42,6 +42,7 @@ func main() {
 	messagesCmd := cli.NewMessagesCmd()
 	provisionCmd := cli.NewProvisionCmd()
 	kuiperCmd := cli.NewKuiperCmd()
+	rulechainsCmd := cli.NewRuleChainsCmd()
 
 	// Root Commands
 	rootCmd.AddCommand(versionCmd)
@@ -51,6 +52,7 @@ func main() {
 	rootCmd.AddCommand(messagesCmd)
 	rootCmd.AddCommand(provisionCmd)
 	rootCmd.AddCommand(kuiperCmd)
+	rootCmd.AddCommand(rulechainsCmd)
 
 	// Root Flags
 	rootCmd.PersistentFlags().StringVarP(
@@ -93,6 +95,14 @@ func main() {
 		"pandas kuiper prefix",
 	)
 
+	rootCmd.PersistentFlags().StringVarP(
+		&sdkConf.RuleChainPrefix,
+		"rulechain-prefix",
+		"r",
+		sdkConf.RuleChainPrefix,
+		"pandas rulechain prefix",
+	)
+
 	rootCmd.PersistentFlags().StringVarP(
 		&msgContentType,
 		"content-type",
@@ -20,6 +20,7 @@ import (
 	"github.com/cloustone/pandas/mainflux/broker"
 	"github.com/cloustone/pandas/mainflux/coap"
 	"github.com/cloustone/pandas/mainflux/coap/api"
+	"github.com/cloustone/pandas/mainflux/schema"
 	logger "github.com/cloustone/pandas/pkg/logger"
 	thingsapi "github.com/cloustone/pandas/things/api/auth/grpc"
 	gocoap "github.com/dustin/go-coap"
@@ -41,6 +42,7 @@ const (
 	defPingPeriod    = "12"
 	defJaegerURL     = ""
 	defThingsTimeout = "1" // in seconds
+	defSchemaTTL     = "1m"
 
 	envPort          = "PD_COAP_ADAPTER_PORT"
 	envNatsURL       = "PD_NATS_URL"
@@ -51,6 +53,7 @@ const (
 	envPingPeriod    = "PD_COAP_ADAPTER_PING_PERIOD"
 	envJaegerURL     = "PD_JAEGER_URL"
 	envThingsTimeout = "PD_COAP_ADAPTER_THINGS_TIMEOUT"
+	envSchemaTTL     = "PD_COAP_ADAPTER_SCHEMA_TTL"
 )
 
 type config struct {
@@ -63,6 +66,7 @@ type config struct {
 	pingPeriod    time.Duration
 	jaegerURL     string
 	thingsTimeout time.Duration
+	schemaTTL     time.Duration
 }
 
 func main() {
@@ -89,7 +93,16 @@ func main() {
 	}
 	defer b.Close()
 
-	svc := coap.New(b, logger, cc, respChan)
+	validator := schema.MetricsMiddleware(
+		schema.NewValidator(cc, cfg.schemaTTL),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "coap_adapter",
+			Subsystem: "api",
+			Name:      "invalid_message_count",
+			Help:      "Number of messages rejected by the channel schemas.",
+		}, []string{"protocol"}),
+	)
+	svc := coap.New(schema.NewPublisher(b, validator), logger, cc, respChan)
 
 	svc = api.LoggingMiddleware(svc, logger)
 
@@ -144,6 +157,11 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(pandas.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
 	return config{
 		thingsURL:     pandas.Env(envThingsURL, defThingsURL),
 		natsURL:       pandas.Env(envNatsURL, defNatsURL),
@@ -154,6 +172,7 @@ func loadConfig() config {
 		pingPeriod:    time.Duration(pp),
 		jaegerURL:     pandas.Env(envJaegerURL, defJaegerURL),
 		thingsTimeout: time.Duration(timeout) * time.Second,
+		schemaTTL:     schemaTTL,
 	}
 }
 
@@ -0,0 +1,265 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cloustone/pandas"
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/commands/api"
+	httpapi "github.com/cloustone/pandas/commands/api/http"
+	natspub "github.com/cloustone/pandas/commands/nats/publisher"
+	natssub "github.com/cloustone/pandas/commands/nats/subscriber"
+	"github.com/cloustone/pandas/commands/postgres"
+	"github.com/cloustone/pandas/commands/presence"
+	"github.com/cloustone/pandas/commands/uuid"
+	"github.com/cloustone/pandas/mainflux/broker"
+	mflog "github.com/cloustone/pandas/pkg/logger"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+)
+
+const (
+	defLogLevel          = "error"
+	defHTTPPort          = "8198"
+	defServerCert        = ""
+	defServerKey         = ""
+	defJaegerURL         = ""
+	defBaseURL           = "http://localhost"
+	defThingsPrefix      = ""
+	defNatsURL           = nats.DefaultURL
+	defPresenceURL       = ""
+	defPresenceChannelID = ""
+	defTTL               = "1h"
+	defCheckInterval     = "10s"
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDBName            = "commands"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+
+	envLogLevel          = "PD_COMMANDS_LOG_LEVEL"
+	envHTTPPort          = "PD_COMMANDS_HTTP_PORT"
+	envServerCert        = "PD_COMMANDS_SERVER_CERT"
+	envServerKey         = "PD_COMMANDS_SERVER_KEY"
+	envJaegerURL         = "PD_JAEGER_URL"
+	envBaseURL           = "PD_SDK_BASE_URL"
+	envThingsPrefix      = "PD_SDK_THINGS_PREFIX"
+	envNatsURL           = "PD_NATS_URL"
+	envPresenceURL       = "PD_COMMANDS_PRESENCE_URL"
+	envPresenceChannelID = "PD_COMMANDS_PRESENCE_CHANNEL_ID"
+	envTTL               = "PD_COMMANDS_TTL"
+	envCheckInterval     = "PD_COMMANDS_CHECK_INTERVAL"
+	envDBHost            = "PD_COMMANDS_DB_HOST"
+	envDBPort            = "PD_COMMANDS_DB_PORT"
+	envDBUser            = "PD_COMMANDS_DB_USER"
+	envDBPass            = "PD_COMMANDS_DB_PASS"
+	envDBName            = "PD_COMMANDS_DB"
+	envDBSSLMode         = "PD_COMMANDS_DB_SSL_MODE"
+	envDBSSLCert         = "PD_COMMANDS_DB_SSL_CERT"
+	envDBSSLKey          = "PD_COMMANDS_DB_SSL_KEY"
+	envDBSSLRootCert     = "PD_COMMANDS_DB_SSL_ROOT_CERT"
+)
+
+type config struct {
+	logLevel          string
+	httpPort          string
+	serverCert        string
+	serverKey         string
+	jaegerURL         string
+	baseURL           string
+	thingsPrefix      string
+	natsURL           string
+	presenceURL       string
+	presenceChannelID string
+	ttl               time.Duration
+	checkInterval     time.Duration
+	dbConfig          postgres.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	b, err := broker.New(cfg.natsURL)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	defer b.Close()
+
+	tracer, closer := initJaeger("commands", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	svc := newService(db, b, cfg, logger)
+	errs := make(chan error, 2)
+
+	if err := natssub.NewSubscriber(b, cfg.presenceChannelID, svc, logger).Subscribe(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	go checkExpiration(svc, cfg.checkInterval, logger)
+	go startHTTPServer(httpapi.MakeHandler(tracer, svc), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Commands service terminated: %s", err))
+}
+
+func loadConfig() config {
+	ttl, err := time.ParseDuration(pandas.Env(envTTL, defTTL))
+	if err != nil || ttl <= 0 {
+		log.Fatalf("Invalid %s value: %s", envTTL, pandas.Env(envTTL, defTTL))
+	}
+
+	interval, err := time.ParseDuration(pandas.Env(envCheckInterval, defCheckInterval))
+	if err != nil || interval <= 0 {
+		log.Fatalf("Invalid %s value: %s", envCheckInterval, pandas.Env(envCheckInterval, defCheckInterval))
+	}
+
+	dbConfig := postgres.Config{
+		Host:        pandas.Env(envDBHost, defDBHost),
+		Port:        pandas.Env(envDBPort, defDBPort),
+		User:        pandas.Env(envDBUser, defDBUser),
+		Pass:        pandas.Env(envDBPass, defDBPass),
+		Name:        pandas.Env(envDBName, defDBName),
+		SSLMode:     pandas.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     pandas.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      pandas.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: pandas.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	return config{
+		logLevel:          pandas.Env(envLogLevel, defLogLevel),
+		httpPort:          pandas.Env(envHTTPPort, defHTTPPort),
+		serverCert:        pandas.Env(envServerCert, defServerCert),
+		serverKey:         pandas.Env(envServerKey, defServerKey),
+		jaegerURL:         pandas.Env(envJaegerURL, defJaegerURL),
+		baseURL:           pandas.Env(envBaseURL, defBaseURL),
+		thingsPrefix:      pandas.Env(envThingsPrefix, defThingsPrefix),
+		natsURL:           pandas.Env(envNatsURL, defNatsURL),
+		presenceURL:       pandas.Env(envPresenceURL, defPresenceURL),
+		presenceChannelID: pandas.Env(envPresenceChannelID, defPresenceChannelID),
+		ttl:               ttl,
+		checkInterval:     interval,
+		dbConfig:          dbConfig,
+	}
+}
+
+func connectToDB(cfg postgres.Config, logger mflog.Logger) *sqlx.DB {
+	db, err := postgres.Connect(cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func initJaeger(svcName, url string, logger mflog.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func newService(db *sqlx.DB, b broker.Nats, cfg config, logger mflog.Logger) commands.Service {
+	repo := postgres.NewCommandRepository(db)
+	pub := natspub.NewPublisher(b)
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	svc := commands.New(repo, sdk, uuid.New(), presence.New(cfg.presenceURL), pub, cfg.ttl)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "commands",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "commands",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func checkExpiration(svc commands.Service, interval time.Duration, logger mflog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := svc.Expire(context.Background(), now); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to expire commands: %s", err))
+		}
+	}
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Commands service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("Commands service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/cloustone/pandas/mainflux/broker"
 	adapter "github.com/cloustone/pandas/mainflux/http"
 	"github.com/cloustone/pandas/mainflux/http/api"
+	"github.com/cloustone/pandas/mainflux/schema"
 	"github.com/cloustone/pandas/pkg/logger"
 	thingsapi "github.com/cloustone/pandas/things/api/auth/grpc"
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
@@ -38,6 +39,7 @@ const (
 	defThingsURL     = "localhost:8181"
 	defJaegerURL     = ""
 	defThingsTimeout = "1" // in seconds
+	defSchemaTTL     = "1m"
 
 	envClientTLS     = "PD_HTTP_ADAPTER_CLIENT_TLS"
 	envCACerts       = "PD_HTTP_ADAPTER_CA_CERTS"
@@ -47,6 +49,7 @@ const (
 	envThingsURL     = "PD_THINGS_URL"
 	envJaegerURL     = "PD_JAEGER_URL"
 	envThingsTimeout = "PD_HTTP_ADAPTER_THINGS_TIMEOUT"
+	envSchemaTTL     = "PD_HTTP_ADAPTER_SCHEMA_TTL"
 )
 
 type config struct {
@@ -58,6 +61,7 @@ type config struct {
 	caCerts       string
 	jaegerURL     string
 	thingsTimeout time.Duration
+	schemaTTL     time.Duration
 }
 
 func main() {
@@ -86,7 +90,16 @@ func main() {
 	defer b.Close()
 
 	cc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsTimeout)
-	svc := adapter.New(b, cc)
+	validator := schema.MetricsMiddleware(
+		schema.NewValidator(cc, cfg.schemaTTL),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "http_adapter",
+			Subsystem: "api",
+			Name:      "invalid_message_count",
+			Help:      "Number of messages rejected by the channel schemas.",
+		}, []string{"protocol"}),
+	)
+	svc := adapter.New(schema.NewPublisher(b, validator), cc)
 
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
@@ -134,6 +147,11 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(pandas.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
 	return config{
 		thingsURL:     pandas.Env(envThingsURL, defThingsURL),
 		natsURL:       pandas.Env(envNatsURL, defNatsURL),
@@ -143,6 +161,7 @@ func loadConfig() config {
 		caCerts:       pandas.Env(envCACerts, defCACerts),
 		jaegerURL:     pandas.Env(envJaegerURL, defJaegerURL),
 		thingsTimeout: time.Duration(timeout) * time.Second,
+		schemaTTL:     schemaTTL,
 	}
 }
 
@@ -9,11 +9,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"github.com/cloustone/pandas"
 	"github.com/cloustone/pandas/mainflux/broker"
-	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	"github.com/cloustone/pandas/mainflux/writers"
 	"github.com/cloustone/pandas/mainflux/writers/api"
 	"github.com/cloustone/pandas/mainflux/writers/influxdb"
@@ -26,37 +27,67 @@ import (
 const (
 	svcName = "influxdb-writer"
 
-	defNatsURL         = pandas.DefNatsURL
-	defLogLevel        = "error"
-	defPort            = "8180"
-	defDBName          = "mainflux"
-	defDBHost          = "localhost"
-	defDBPort          = "8086"
-	defDBUser          = "mainflux"
-	defDBPass          = "mainflux"
-	defSubjectsCfgPath = "/config/subjects.toml"
-
-	envNatsURL         = "PD_NATS_URL"
-	envLogLevel        = "PD_INFLUX_WRITER_LOG_LEVEL"
-	envPort            = "PD_INFLUX_WRITER_PORT"
-	envDBName          = "PD_INFLUX_WRITER_DB_NAME"
-	envDBHost          = "PD_INFLUX_WRITER_DB_HOST"
-	envDBPort          = "PD_INFLUX_WRITER_DB_PORT"
-	envDBUser          = "PD_INFLUX_WRITER_DB_USER"
-	envDBPass          = "PD_INFLUX_WRITER_DB_PASS"
-	envSubjectsCfgPath = "PD_INFLUX_WRITER_SUBJECTS_CONFIG"
+	defNatsURL             = pandas.DefNatsURL
+	defLogLevel            = "error"
+	defPort                = "8180"
+	defDBName              = "mainflux"
+	defDBHost              = "localhost"
+	defDBPort              = "8086"
+	defDBUser              = "mainflux"
+	defDBPass              = "mainflux"
+	defSubjectsCfgPath     = "/config/subjects.toml"
+	defTransformersCfgPath = "/config/transformers.toml"
+	defBatchSize           = "100"
+	defBatchLinger         = "500ms"
+	defBufferSize          = "10000"
+	defRetries             = "3"
+	defRetryBackoff        = "100ms"
+	defMaxBackoff          = "5s"
+	defDedupSize           = "10000"
+	defDeadLetterSubj      = ""
+	defDeadLetterFile      = ""
+	defRetentionInt        = "1h"
+	defAdminKey            = ""
+
+	envNatsURL             = "PD_NATS_URL"
+	envLogLevel            = "PD_INFLUX_WRITER_LOG_LEVEL"
+	envPort                = "PD_INFLUX_WRITER_PORT"
+	envDBName              = "PD_INFLUX_WRITER_DB_NAME"
+	envDBHost              = "PD_INFLUX_WRITER_DB_HOST"
+	envDBPort              = "PD_INFLUX_WRITER_DB_PORT"
+	envDBUser              = "PD_INFLUX_WRITER_DB_USER"
+	envDBPass              = "PD_INFLUX_WRITER_DB_PASS"
+	envSubjectsCfgPath     = "PD_INFLUX_WRITER_SUBJECTS_CONFIG"
+	envTransformersCfgPath = "PD_INFLUX_WRITER_TRANSFORMERS_CONFIG"
+	envBatchSize           = "PD_INFLUX_WRITER_BATCH_SIZE"
+	envBatchLinger         = "PD_INFLUX_WRITER_BATCH_LINGER"
+	envBufferSize          = "PD_INFLUX_WRITER_BUFFER_SIZE"
+	envRetries             = "PD_INFLUX_WRITER_RETRIES"
+	envRetryBackoff        = "PD_INFLUX_WRITER_RETRY_BACKOFF"
+	envMaxBackoff          = "PD_INFLUX_WRITER_MAX_BACKOFF"
+	envDedupSize           = "PD_INFLUX_WRITER_DEDUP_SIZE"
+	envDeadLetterSubj      = "PD_INFLUX_WRITER_DEAD_LETTER_SUBJECT"
+	envDeadLetterFile      = "PD_INFLUX_WRITER_DEAD_LETTER_FILE"
+	envRetentionInt        = "PD_INFLUX_WRITER_RETENTION_INTERVAL"
+	envAdminKey            = "PD_INFLUX_WRITER_ADMIN_KEY"
 )
 
 type config struct {
-	natsURL         string
-	logLevel        string
-	port            string
-	dbName          string
-	dbHost          string
-	dbPort          string
-	dbUser          string
-	dbPass          string
-	subjectsCfgPath string
+	natsURL             string
+	logLevel            string
+	port                string
+	dbName              string
+	dbHost              string
+	dbPort              string
+	dbUser              string
+	dbPass              string
+	subjectsCfgPath     string
+	transformersCfgPath string
+	writerCfg           writers.Config
+	deadLetterSubj      string
+	deadLetterFile      string
+	retentionInt        time.Duration
+	adminKey            string
 }
 
 func main() {
@@ -86,8 +117,30 @@ func main() {
 	counter, latency := makeMetrics()
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
-	st := senml.New()
-	if err := writers.Start(b, repo, st, svcName, cfg.subjectsCfgPath, logger); err != nil {
+
+	rs := newRetentionService(influxdb.NewPolicyRepository(client, cfg.dbName), influxdb.NewRetentionRepository(client, cfg.dbName), logger)
+	go writers.EnforcePolicies(rs, cfg.retentionInt, logger)
+
+	st, err := writers.NewTransformer(cfg.transformersCfgPath)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create transformer: %s", err))
+		os.Exit(1)
+	}
+
+	wcfg := cfg.writerCfg
+	wcfg.Latency = kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+		Namespace: "influxdb",
+		Subsystem: "message_writer",
+		Name:      "end_to_end_latency_seconds",
+		Help:      "Duration from the creation of the messages by the adapters to their saving in seconds.",
+	}, []string{})
+	wcfg.DeadLetter = newDeadLetter(cfg, logger)
+	if wcfg.DeadLetter != nil {
+		defer wcfg.DeadLetter.Close()
+	}
+
+	consumer, err := writers.Start(b, repo, st, svcName, cfg.subjectsCfgPath, wcfg, logger)
+	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to start InfluxDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -99,23 +152,32 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPService(cfg.port, logger, errs)
+	go startHTTPService(cfg.port, logger, errs, rs, cfg.adminKey)
 
 	err = <-errs
+	if cerr := consumer.Close(); cerr != nil {
+		logger.Error(fmt.Sprintf("Failed to close InfluxDB writer consumer: %s", cerr))
+	}
 	logger.Error(fmt.Sprintf("InfluxDB writer service terminated: %s", err))
 }
 
 func loadConfigs() (config, influxdata.HTTPConfig) {
 	cfg := config{
-		natsURL:         pandas.Env(envNatsURL, defNatsURL),
-		logLevel:        pandas.Env(envLogLevel, defLogLevel),
-		port:            pandas.Env(envPort, defPort),
-		dbName:          pandas.Env(envDBName, defDBName),
-		dbHost:          pandas.Env(envDBHost, defDBHost),
-		dbPort:          pandas.Env(envDBPort, defDBPort),
-		dbUser:          pandas.Env(envDBUser, defDBUser),
-		dbPass:          pandas.Env(envDBPass, defDBPass),
-		subjectsCfgPath: pandas.Env(envSubjectsCfgPath, defSubjectsCfgPath),
+		natsURL:             pandas.Env(envNatsURL, defNatsURL),
+		logLevel:            pandas.Env(envLogLevel, defLogLevel),
+		port:                pandas.Env(envPort, defPort),
+		dbName:              pandas.Env(envDBName, defDBName),
+		dbHost:              pandas.Env(envDBHost, defDBHost),
+		dbPort:              pandas.Env(envDBPort, defDBPort),
+		dbUser:              pandas.Env(envDBUser, defDBUser),
+		dbPass:              pandas.Env(envDBPass, defDBPass),
+		subjectsCfgPath:     pandas.Env(envSubjectsCfgPath, defSubjectsCfgPath),
+		transformersCfgPath: pandas.Env(envTransformersCfgPath, defTransformersCfgPath),
+		writerCfg:           loadWriterConfig(),
+		deadLetterSubj:      pandas.Env(envDeadLetterSubj, defDeadLetterSubj),
+		deadLetterFile:      pandas.Env(envDeadLetterFile, defDeadLetterFile),
+		retentionInt:        loadRetentionInterval(),
+		adminKey:            pandas.Env(envAdminKey, defAdminKey),
 	}
 
 	clientCfg := influxdata.HTTPConfig{
@@ -145,8 +207,106 @@ func makeMetrics() (*kitprometheus.Counter, *kitprometheus.Summary) {
 	return counter, latency
 }
 
-func startHTTPService(port string, logger logger.Logger, errs chan error) {
+func startHTTPService(port string, logger logger.Logger, errs chan error, rs writers.RetentionService, adminKey string) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("InfluxDB writer service started, exposed port %s", p))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+	errs <- http.ListenAndServe(p, api.MakeHandler(svcName, rs, adminKey))
+}
+
+func loadWriterConfig() writers.Config {
+	batchSize, err := strconv.Atoi(pandas.Env(envBatchSize, defBatchSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchSize, err)
+	}
+
+	batchLinger, err := time.ParseDuration(pandas.Env(envBatchLinger, defBatchLinger))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBatchLinger, err)
+	}
+
+	bufferSize, err := strconv.Atoi(pandas.Env(envBufferSize, defBufferSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBufferSize, err)
+	}
+
+	retries, err := strconv.Atoi(pandas.Env(envRetries, defRetries))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetries, err)
+	}
+
+	retryBackoff, err := time.ParseDuration(pandas.Env(envRetryBackoff, defRetryBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetryBackoff, err)
+	}
+
+	maxBackoff, err := time.ParseDuration(pandas.Env(envMaxBackoff, defMaxBackoff))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMaxBackoff, err)
+	}
+
+	dedupSize, err := strconv.Atoi(pandas.Env(envDedupSize, defDedupSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDedupSize, err)
+	}
+
+	return writers.Config{
+		BatchSize:    batchSize,
+		BatchLinger:  batchLinger,
+		BufferSize:   bufferSize,
+		Retries:      retries,
+		RetryBackoff: retryBackoff,
+		MaxBackoff:   maxBackoff,
+		DedupSize:    dedupSize,
+	}
+}
+
+func newDeadLetter(cfg config, logger logger.Logger) writers.DeadLetter {
+	switch {
+	case cfg.deadLetterSubj != "":
+		dl, err := writers.NewNatsDeadLetter(cfg.natsURL, cfg.deadLetterSubj)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to connect to NATS for dead letters: %s", err))
+			os.Exit(1)
+		}
+		return dl
+	case cfg.deadLetterFile != "":
+		dl, err := writers.NewFileDeadLetter(cfg.deadLetterFile)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to open dead letter file: %s", err))
+			os.Exit(1)
+		}
+		return dl
+	default:
+		return nil
+	}
+}
+
+func loadRetentionInterval() time.Duration {
+	interval, err := time.ParseDuration(pandas.Env(envRetentionInt, defRetentionInt))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRetentionInt, err)
+	}
+	return interval
+}
+
+func newRetentionService(policies writers.PolicyRepository, messages writers.RetentionRepository, logger logger.Logger) writers.RetentionService {
+	svc := writers.NewRetentionService(policies, messages)
+	svc = api.RetentionLoggingMiddleware(svc, logger)
+	svc = api.RetentionMetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "influxdb",
+			Subsystem: "retention",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "influxdb",
+			Subsystem: "retention",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
 }
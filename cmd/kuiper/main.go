@@ -25,6 +25,7 @@ import (
 	"github.com/cloustone/pandas/kuiper"
 	"github.com/cloustone/pandas/kuiper/api"
 	thhttpapi "github.com/cloustone/pandas/kuiper/api/http"
+	"github.com/cloustone/pandas/kuiper/kvstore"
 	"github.com/cloustone/pandas/kuiper/postgres"
 	rediscache "github.com/cloustone/pandas/kuiper/redis"
 	"github.com/cloustone/pandas/kuiper/uuid"
@@ -66,6 +67,8 @@ const (
 	defJaegerURL       = ""
 	defAuthURL         = "localhost:8181"
 	defAuthTimeout     = "1" // in seconds
+	defStateStore      = "file"
+	defInstance        = ""
 
 	envLogLevel        = "PD_KUIPER_LOG_LEVEL"
 	envDBHost          = "PD_KUIPER_DB_HOST"
@@ -95,6 +98,8 @@ const (
 	envJaegerURL       = "PD_JAEGER_URL"
 	envAuthURL         = "PD_AUTH_URL"
 	envAuthTimeout     = "PD_AUTH_TIMEOUT"
+	envStateStore      = "PD_KUIPER_STATE_STORE"
+	envInstance        = "PD_KUIPER_INSTANCE"
 )
 
 type config struct {
@@ -118,6 +123,8 @@ type config struct {
 	jaegerURL       string
 	authURL         string
 	authTimeout     time.Duration
+	stateStore      string
+	instance        string
 }
 
 func main() {
@@ -152,7 +159,7 @@ func main() {
 	cacheTracer, cacheCloser := initJaeger("kuiper_cache", cfg.jaegerURL, logger)
 	defer cacheCloser.Close()
 
-	svc := newService(auth, dbTracer, cacheTracer, db, cacheClient, esClient, logger)
+	svc := newService(cfg, auth, dbTracer, cacheTracer, db, cacheClient, esClient, logger)
 	errs := make(chan error, 2)
 
 	go startHTTPServer(thhttpapi.MakeHandler(kuiperTracer, svc), cfg.httpPort, cfg, logger, errs)
@@ -179,6 +186,19 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
 	}
 
+	stateStore := pandas.Env(envStateStore, defStateStore)
+	if stateStore != "file" && stateStore != "postgres" {
+		log.Fatalf("Invalid %s value: %s", envStateStore, stateStore)
+	}
+
+	// The pod name is used as the instance name on kubernetes
+	instance := pandas.Env(envInstance, defInstance)
+	if instance == "" {
+		if instance, err = os.Hostname(); err != nil {
+			log.Fatalf("Failed to get the instance name: %s", err.Error())
+		}
+	}
+
 	dbConfig := postgres.Config{
 		Host:        pandas.Env(envDBHost, defDBHost),
 		Port:        pandas.Env(envDBPort, defDBPort),
@@ -212,6 +232,8 @@ func loadConfig() config {
 		jaegerURL:       pandas.Env(envJaegerURL, defJaegerURL),
 		authURL:         pandas.Env(envAuthURL, defAuthURL),
 		authTimeout:     time.Duration(timeout) * time.Second,
+		stateStore:      stateStore,
+		instance:        instance,
 	}
 }
 
@@ -296,7 +318,7 @@ func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(auth mainflux.AuthNServiceClient, dbTracer opentracing.Tracer, cacheTracer opentracing.Tracer, db *sqlx.DB, cacheClient *redis.Client, esClient *redis.Client, logger logger.Logger) kuiper.Service {
+func newService(cfg config, auth mainflux.AuthNServiceClient, dbTracer opentracing.Tracer, cacheTracer opentracing.Tracer, db *sqlx.DB, cacheClient *redis.Client, esClient *redis.Client, logger logger.Logger) kuiper.Service {
 	database := postgres.NewDatabase(db)
 
 	ruleRepo := postgres.NewRuleRepository(database)
@@ -305,6 +327,15 @@ func newService(auth mainflux.AuthNServiceClient, dbTracer opentracing.Tracer, c
 	streamRepo := postgres.NewStreamRepository(database)
 	streamRepo = tracing.StreamRepositoryMiddleware(dbTracer, streamRepo)
 
+	pluginRepo := postgres.NewPluginRepository(database)
+	pluginRepo = tracing.PluginRepositoryMiddleware(dbTracer, pluginRepo)
+
+	functionRepo := postgres.NewFunctionRepository(database)
+	functionRepo = tracing.FunctionRepositoryMiddleware(dbTracer, functionRepo)
+
+	templateRepo := postgres.NewRuleTemplateRepository(database)
+	templateRepo = tracing.RuleTemplateRepositoryMiddleware(dbTracer, templateRepo)
+
 	ruleCache := rediscache.NewRuleCache(cacheClient)
 	ruleCache = tracing.RuleCacheMiddleware(cacheTracer, ruleCache)
 
@@ -317,7 +348,17 @@ func newService(auth mainflux.AuthNServiceClient, dbTracer opentracing.Tracer, c
 		log.Fatalf(err.Error())
 	}
 
-	svc := kuiper.New(auth, streamRepo, ruleRepo, streamCache, ruleCache, idp, pluginManager)
+	// The states are saved in the local files by default. To run more than
+	// one instance, they are saved in postgres and the rules are shared by
+	// the instances with the leases.
+	var leaseRepo kuiper.RuleLeaseRepository
+	if cfg.stateStore == "postgres" {
+		kvstore.SetStoreFactory(postgres.NewKvStoreFactory(database))
+		leaseRepo = postgres.NewRuleLeaseRepository(database)
+		leaseRepo = tracing.RuleLeaseRepositoryMiddleware(dbTracer, leaseRepo)
+	}
+
+	svc := kuiper.New(auth, streamRepo, ruleRepo, pluginRepo, streamCache, ruleCache, idp, pluginManager, leaseRepo, cfg.instance, functionRepo, templateRepo)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
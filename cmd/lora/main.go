@@ -17,6 +17,7 @@ import (
 	"github.com/cloustone/pandas/mainflux/lora"
 	"github.com/cloustone/pandas/mainflux/lora/api"
 	"github.com/cloustone/pandas/mainflux/lora/mqtt"
+	"github.com/cloustone/pandas/mainflux/lora/nats/subscriber"
 	"github.com/cloustone/pandas/pkg/logger"
 	mqttPaho "github.com/eclipse/paho.mqtt.golang"
 	r "github.com/go-redis/redis"
@@ -97,7 +98,7 @@ func main() {
 
 	mqttConn := connectToMQTTBroker(cfg.loraMsgURL, logger)
 
-	svc := lora.New(b, thingRM, chanRM)
+	svc := lora.New(b, mqtt.NewPublisher(mqttConn), thingRM, chanRM)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -117,6 +118,7 @@ func main() {
 
 	go subscribeToLoRaBroker(svc, mqttConn, logger)
 	go subscribeToThingsES(svc, esConn, cfg.esConsumerName, logger)
+	go subscribeToCommands(b, svc, logger)
 
 	errs := make(chan error, 2)
 
@@ -201,6 +203,14 @@ func subscribeToThingsES(svc lora.Service, client *r.Client, consumer string, lo
 	}
 }
 
+func subscribeToCommands(b broker.Nats, svc lora.Service, logger logger.Logger) {
+	if err := subscriber.NewSubscriber(b, svc, logger).Subscribe(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to commands: %s", err))
+		os.Exit(1)
+	}
+	logger.Info("Subscribed to commands")
+}
+
 func newRouteMapRepositoy(client *r.Client, prefix string, logger logger.Logger) lora.RouteMapRepository {
 	logger.Info(fmt.Sprintf("Connected to %s Redis Route-map", prefix))
 	return redis.NewRouteMapRepository(client, prefix)
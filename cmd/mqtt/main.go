@@ -16,12 +16,15 @@ import (
 	"github.com/cloustone/pandas/mainflux/broker"
 	mqtt "github.com/cloustone/pandas/mainflux/mqtt"
 	mr "github.com/cloustone/pandas/mainflux/mqtt/redis"
+	"github.com/cloustone/pandas/mainflux/schema"
 	"github.com/cloustone/pandas/pkg/logger"
 	thingsapi "github.com/cloustone/pandas/things/api/auth/grpc"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/go-redis/redis"
 	mp "github.com/mainflux/mproxy/pkg/mqtt"
 	ws "github.com/mainflux/mproxy/pkg/websocket"
 	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -79,6 +82,11 @@ const (
 	defESURL  = "localhost:6379"
 	defESPass = ""
 	defESDB   = "0"
+	// Schema
+	envSchemaTTL    = "PD_MQTT_ADAPTER_SCHEMA_TTL"
+	envSchemaPolicy = "PD_MQTT_ADAPTER_SCHEMA_POLICY"
+	defSchemaTTL    = "1m"
+	defSchemaPolicy = mqtt.PolicyIgnore
 )
 
 type config struct {
@@ -103,6 +111,8 @@ type config struct {
 	esURL          string
 	esPass         string
 	esDB           string
+	schemaTTL      time.Duration
+	schemaPolicy   string
 }
 
 func main() {
@@ -136,8 +146,18 @@ func main() {
 
 	es := mr.NewEventStore(rc, cfg.instance)
 
+	validator := schema.MetricsMiddleware(
+		schema.NewValidator(cc, cfg.schemaTTL),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "api",
+			Name:      "invalid_message_count",
+			Help:      "Number of messages rejected by the channel schemas.",
+		}, []string{"protocol"}),
+	)
+
 	// Event handler for MQTT hooks
-	evt := mqtt.New(b, cc, es, logger, tracer)
+	evt := mqtt.New(b, cc, es, validator, cfg.schemaPolicy, logger, tracer)
 
 	errs := make(chan error, 2)
 
@@ -168,6 +188,16 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(pandas.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
+	policy := pandas.Env(envSchemaPolicy, defSchemaPolicy)
+	if policy != mqtt.PolicyIgnore && policy != mqtt.PolicyDisconnect {
+		log.Fatalf("Invalid %s value: %s", envSchemaPolicy, policy)
+	}
+
 	return config{
 		mqttHost:       pandas.Env(envMQTTHost, defMQTTHost),
 		mqttPort:       pandas.Env(envMQTTPort, defMQTTPort),
@@ -190,6 +220,8 @@ func loadConfig() config {
 		esURL:          pandas.Env(envESURL, defESURL),
 		esPass:         pandas.Env(envESPass, defESPass),
 		esDB:           pandas.Env(envESDB, defESDB),
+		schemaTTL:      schemaTTL,
+		schemaPolicy:   policy,
 	}
 }
 
@@ -19,6 +19,7 @@ import (
 	"github.com/cloustone/pandas/mainflux/opcua/api"
 	"github.com/cloustone/pandas/mainflux/opcua/db"
 	"github.com/cloustone/pandas/mainflux/opcua/gopcua"
+	"github.com/cloustone/pandas/mainflux/opcua/nats/subscriber"
 	"github.com/cloustone/pandas/mainflux/opcua/redis"
 	"github.com/cloustone/pandas/pkg/logger"
 	r "github.com/go-redis/redis"
@@ -107,8 +108,9 @@ func main() {
 	ctx := context.Background()
 	sub := gopcua.NewSubscriber(ctx, b, thingRM, chanRM, connRM, logger)
 	browser := gopcua.NewBrowser(ctx, logger)
+	writer := gopcua.NewWriter(ctx, logger)
 
-	svc := opcua.New(sub, browser, thingRM, chanRM, connRM, cfg.opcuaConfig, logger)
+	svc := opcua.New(sub, browser, writer, thingRM, chanRM, connRM, cfg.opcuaConfig, logger)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -128,6 +130,7 @@ func main() {
 
 	go subscribeToStoredSubs(sub, cfg.opcuaConfig, logger)
 	go subscribeToThingsES(svc, esConn, cfg.esConsumerName, logger)
+	go subscribeToCommands(b, svc, logger)
 
 	errs := make(chan error, 2)
 
@@ -211,6 +214,14 @@ func subscribeToThingsES(svc opcua.Service, client *r.Client, prefix string, log
 	}
 }
 
+func subscribeToCommands(b broker.Nats, svc opcua.Service, logger logger.Logger) {
+	if err := subscriber.NewSubscriber(b, svc, logger).Subscribe(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to commands: %s", err))
+		os.Exit(1)
+	}
+	logger.Info("Subscribed to commands")
+}
+
 func newRouteMapRepositoy(client *r.Client, prefix string, logger logger.Logger) opcua.RouteMapRepository {
 	logger.Info(fmt.Sprintf("Connected to %s Redis Route-map", prefix))
 	return redis.NewRouteMapRepository(client, prefix)
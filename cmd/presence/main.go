@@ -0,0 +1,290 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/cloustone/pandas"
+	"github.com/cloustone/pandas/mainflux/broker"
+	mflog "github.com/cloustone/pandas/pkg/logger"
+	"github.com/cloustone/pandas/presence"
+	"github.com/cloustone/pandas/presence/api"
+	httpapi "github.com/cloustone/pandas/presence/api/http"
+	natspub "github.com/cloustone/pandas/presence/nats/publisher"
+	natssub "github.com/cloustone/pandas/presence/nats/subscriber"
+	redisrepo "github.com/cloustone/pandas/presence/redis"
+	rediscons "github.com/cloustone/pandas/presence/redis/consumer"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis"
+	nats "github.com/nats-io/nats.go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+)
+
+const (
+	defLogLevel       = "error"
+	defHTTPPort       = "8197"
+	defServerCert     = ""
+	defServerKey      = ""
+	defJaegerURL      = ""
+	defBaseURL        = "http://localhost"
+	defThingsPrefix   = ""
+	defNatsURL        = nats.DefaultURL
+	defChannelID      = ""
+	defTimeout        = "5m"
+	defCheckInterval  = "10s"
+	defDBURL          = "localhost:6379"
+	defDBPass         = ""
+	defDB             = "0"
+	defMQTTESURL      = "localhost:6379"
+	defMQTTESPass     = ""
+	defMQTTESDB       = "0"
+	defThingsESURL    = "localhost:6379"
+	defThingsESPass   = ""
+	defThingsESDB     = "0"
+	defESConsumerName = "presence"
+
+	envLogLevel       = "PD_PRESENCE_LOG_LEVEL"
+	envHTTPPort       = "PD_PRESENCE_HTTP_PORT"
+	envServerCert     = "PD_PRESENCE_SERVER_CERT"
+	envServerKey      = "PD_PRESENCE_SERVER_KEY"
+	envJaegerURL      = "PD_JAEGER_URL"
+	envBaseURL        = "PD_SDK_BASE_URL"
+	envThingsPrefix   = "PD_SDK_THINGS_PREFIX"
+	envNatsURL        = "PD_NATS_URL"
+	envChannelID      = "PD_PRESENCE_CHANNEL_ID"
+	envTimeout        = "PD_PRESENCE_TIMEOUT"
+	envCheckInterval  = "PD_PRESENCE_CHECK_INTERVAL"
+	envDBURL          = "PD_PRESENCE_DB_URL"
+	envDBPass         = "PD_PRESENCE_DB_PASS"
+	envDB             = "PD_PRESENCE_DB"
+	envMQTTESURL      = "PD_MQTT_ADAPTER_ES_URL"
+	envMQTTESPass     = "PD_MQTT_ADAPTER_ES_PASS"
+	envMQTTESDB       = "PD_MQTT_ADAPTER_ES_DB"
+	envThingsESURL    = "PD_THINGS_ES_URL"
+	envThingsESPass   = "PD_THINGS_ES_PASS"
+	envThingsESDB     = "PD_THINGS_ES_DB"
+	envESConsumerName = "PD_PRESENCE_EVENT_CONSUMER"
+)
+
+type config struct {
+	logLevel       string
+	httpPort       string
+	serverCert     string
+	serverKey      string
+	jaegerURL      string
+	baseURL        string
+	thingsPrefix   string
+	natsURL        string
+	channelID      string
+	timeout        time.Duration
+	checkInterval  time.Duration
+	dbURL          string
+	dbPass         string
+	db             string
+	mqttESURL      string
+	mqttESPass     string
+	mqttESDB       string
+	thingsESURL    string
+	thingsESPass   string
+	thingsESDB     string
+	esConsumerName string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToRedis(cfg.dbURL, cfg.dbPass, cfg.db, logger)
+	defer db.Close()
+
+	mqttESClient := connectToRedis(cfg.mqttESURL, cfg.mqttESPass, cfg.mqttESDB, logger)
+	defer mqttESClient.Close()
+
+	thingsESClient := connectToRedis(cfg.thingsESURL, cfg.thingsESPass, cfg.thingsESDB, logger)
+	defer thingsESClient.Close()
+
+	b, err := broker.New(cfg.natsURL)
+	if err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+	defer b.Close()
+
+	tracer, closer := initJaeger("presence", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	svc := newService(db, b, cfg, logger)
+	errs := make(chan error, 2)
+
+	if err := natssub.NewSubscriber(b, cfg.channelID, svc, logger).Subscribe(); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to NATS: %s", err))
+		os.Exit(1)
+	}
+
+	go subscribeToES(svc, mqttESClient, rediscons.MQTTStream, cfg.esConsumerName, logger)
+	go subscribeToES(svc, thingsESClient, rediscons.ThingsStream, cfg.esConsumerName, logger)
+	go checkInactivity(svc, cfg.checkInterval, logger)
+	go startHTTPServer(httpapi.MakeHandler(tracer, svc), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Presence service terminated: %s", err))
+}
+
+func loadConfig() config {
+	timeout, err := time.ParseDuration(pandas.Env(envTimeout, defTimeout))
+	if err != nil || timeout <= 0 {
+		log.Fatalf("Invalid %s value: %s", envTimeout, pandas.Env(envTimeout, defTimeout))
+	}
+
+	interval, err := time.ParseDuration(pandas.Env(envCheckInterval, defCheckInterval))
+	if err != nil || interval <= 0 {
+		log.Fatalf("Invalid %s value: %s", envCheckInterval, pandas.Env(envCheckInterval, defCheckInterval))
+	}
+
+	return config{
+		logLevel:       pandas.Env(envLogLevel, defLogLevel),
+		httpPort:       pandas.Env(envHTTPPort, defHTTPPort),
+		serverCert:     pandas.Env(envServerCert, defServerCert),
+		serverKey:      pandas.Env(envServerKey, defServerKey),
+		jaegerURL:      pandas.Env(envJaegerURL, defJaegerURL),
+		baseURL:        pandas.Env(envBaseURL, defBaseURL),
+		thingsPrefix:   pandas.Env(envThingsPrefix, defThingsPrefix),
+		natsURL:        pandas.Env(envNatsURL, defNatsURL),
+		channelID:      pandas.Env(envChannelID, defChannelID),
+		timeout:        timeout,
+		checkInterval:  interval,
+		dbURL:          pandas.Env(envDBURL, defDBURL),
+		dbPass:         pandas.Env(envDBPass, defDBPass),
+		db:             pandas.Env(envDB, defDB),
+		mqttESURL:      pandas.Env(envMQTTESURL, defMQTTESURL),
+		mqttESPass:     pandas.Env(envMQTTESPass, defMQTTESPass),
+		mqttESDB:       pandas.Env(envMQTTESDB, defMQTTESDB),
+		thingsESURL:    pandas.Env(envThingsESURL, defThingsESURL),
+		thingsESPass:   pandas.Env(envThingsESPass, defThingsESPass),
+		thingsESDB:     pandas.Env(envThingsESDB, defThingsESDB),
+		esConsumerName: pandas.Env(envESConsumerName, defESConsumerName),
+	}
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) *r.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return r.NewClient(&r.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
+func initJaeger(svcName, url string, logger mflog.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func newService(db *r.Client, b broker.Nats, cfg config, logger mflog.Logger) presence.Service {
+	repo := redisrepo.NewPresenceRepository(db)
+	pub := natspub.NewPublisher(b, cfg.channelID)
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		BaseURL:      cfg.baseURL,
+		ThingsPrefix: cfg.thingsPrefix,
+	})
+
+	svc := presence.New(repo, sdk, pub, cfg.timeout)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "presence",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "presence",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func subscribeToES(svc presence.Service, client *r.Client, stream, consumer string, logger mflog.Logger) {
+	eventStore := rediscons.NewEventStore(svc, client, consumer, logger)
+	logger.Info(fmt.Sprintf("Subscribed to Redis Event Store stream %s", stream))
+	if err := eventStore.Subscribe(stream); err != nil {
+		logger.Warn(fmt.Sprintf("Presence service failed to subscribe to event sourcing: %s", err))
+	}
+}
+
+func checkInactivity(svc presence.Service, interval time.Duration, logger mflog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if err := svc.CheckInactivity(context.Background(), now); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to check things inactivity: %s", err))
+		}
+	}
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Presence service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("Presence service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cloustone/pandas"
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/nats-io/nats.go"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	natsURL := pandas.DefNatsURL
+	subject := ""
+
+	var rootCmd = &cobra.Command{
+		Use:   "writer-replay <writer> [file...]",
+		Short: "Resubmit dead lettered messages to a writer",
+		Long: `Resubmit the messages a writer failed to save, e.g. postgres-writer.
+The dead letters are read from the files, or from the dead letter subject
+until interrupted if --subject is set.`,
+		Args: cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			conn, err := nats.Connect(natsURL)
+			if err != nil {
+				log.Fatalf("Failed to connect to NATS: %s", err)
+			}
+			defer conn.Close()
+
+			writer := args[0]
+			switch {
+			case subject != "":
+				forward(conn, writer, subject)
+			case len(args) > 1:
+				for _, path := range args[1:] {
+					replayFile(conn, writer, path)
+				}
+			default:
+				log.Fatal("Dead letter file or subject required")
+			}
+
+			if err := conn.Flush(); err != nil {
+				log.Fatalf("Failed to flush replayed letters: %s", err)
+			}
+		},
+	}
+
+	rootCmd.Flags().StringVarP(&natsURL, "nats-url", "n", natsURL, "NATS URL")
+	rootCmd.Flags().StringVarP(&subject, "subject", "s", subject, "Dead letter subject")
+
+	if err := rootCmd.Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func replayFile(conn *nats.Conn, writer, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open dead letter file: %s", err)
+	}
+	defer f.Close()
+
+	n, err := writers.Replay(conn, writer, f)
+	if err != nil {
+		log.Fatalf("Failed to replay %s after %d letters: %s", path, n, err)
+	}
+	fmt.Printf("Replayed %d letters from %s to %s\n", n, path, writer)
+}
+
+// forward resubmits the letters published to the dead letter subject until
+// interrupted.
+func forward(conn *nats.Conn, writer, subject string) {
+	replaySubject := writers.ReplaySubject(writer)
+	sub, err := conn.Subscribe(subject, func(m *nats.Msg) {
+		if err := conn.Publish(replaySubject, m.Data); err != nil {
+			log.Printf("Failed to replay letter: %s", err)
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to subscribe to %s: %s", subject, err)
+	}
+	defer sub.Unsubscribe()
+
+	fmt.Printf("Replaying letters from %s to %s\n", subject, writer)
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGINT, syscall.SIGTERM)
+	<-c
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/cloustone/pandas"
 	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/schema"
 	adapter "github.com/cloustone/pandas/mainflux/ws"
 	"github.com/cloustone/pandas/mainflux/ws/api"
 	"github.com/cloustone/pandas/pkg/logger"
@@ -38,6 +39,7 @@ const (
 	defThingsURL     = "localhost:8181"
 	defJaegerURL     = ""
 	defThingsTimeout = "1" // in seconds
+	defSchemaTTL     = "1m"
 
 	envClientTLS     = "PD_WS_ADAPTER_CLIENT_TLS"
 	envCACerts       = "PD_WS_ADAPTER_CA_CERTS"
@@ -47,6 +49,7 @@ const (
 	envThingsURL     = "PD_THINGS_URL"
 	envJaegerURL     = "PD_JAEGER_URL"
 	envThingsTimeout = "PD_WS_ADAPTER_THINGS_TIMEOUT"
+	envSchemaTTL     = "PD_WS_ADAPTER_SCHEMA_TTL"
 )
 
 type config struct {
@@ -58,6 +61,7 @@ type config struct {
 	port          string
 	jaegerURL     string
 	thingsTimeout time.Duration
+	schemaTTL     time.Duration
 }
 
 func main() {
@@ -83,7 +87,16 @@ func main() {
 	}
 	defer b.Close()
 
-	svc := newService(b, logger)
+	validator := schema.MetricsMiddleware(
+		schema.NewValidator(cc, cfg.schemaTTL),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "ws_adapter",
+			Subsystem: "api",
+			Name:      "invalid_message_count",
+			Help:      "Number of messages rejected by the channel schemas.",
+		}, []string{"protocol"}),
+	)
+	svc := newService(schema.NewPublisher(b, validator), logger)
 
 	errs := make(chan error, 2)
 
@@ -114,6 +127,11 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsTimeout, err.Error())
 	}
 
+	schemaTTL, err := time.ParseDuration(pandas.Env(envSchemaTTL, defSchemaTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envSchemaTTL, err.Error())
+	}
+
 	return config{
 		clientTLS:     tls,
 		caCerts:       pandas.Env(envCACerts, defCACerts),
@@ -123,6 +141,7 @@ func loadConfig() config {
 		port:          pandas.Env(envPort, defPort),
 		jaegerURL:     pandas.Env(envJaegerURL, defJaegerURL),
 		thingsTimeout: time.Duration(timeout) * time.Second,
+		schemaTTL:     schemaTTL,
 	}
 }
 
@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func sendCommandEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sendCommandReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cmd := commands.Command{
+			ThingID:   req.thingID,
+			ChannelID: req.Channel,
+			Name:      req.Name,
+			Params:    req.Params,
+		}
+		ttl := time.Duration(req.TTL) * time.Second
+
+		saved, err := svc.SendCommand(ctx, req.token, cmd, ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toCommandRes(saved)
+		res.created = true
+
+		return res, nil
+	}
+}
+
+func viewCommandEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewCommandReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cmd, err := svc.ViewCommand(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toCommandRes(cmd), nil
+	}
+}
+
+func listCommandsEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listCommandsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListCommands(ctx, req.token, req.thingID, req.offset, req.limit, req.status)
+		if err != nil {
+			return nil, err
+		}
+
+		res := commandsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Commands: []commandRes{},
+		}
+		for _, cmd := range page.Commands {
+			res.Commands = append(res.Commands, toCommandRes(cmd))
+		}
+
+		return res, nil
+	}
+}
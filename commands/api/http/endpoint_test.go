@@ -0,0 +1,409 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	httpapi "github.com/cloustone/pandas/commands/api/http"
+	"github.com/cloustone/pandas/commands/mocks"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/cloustone/pandas/things"
+	thingsapi "github.com/cloustone/pandas/things/api/things/http"
+	thmocks "github.com/cloustone/pandas/things/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType = "application/json"
+	token       = "token"
+	wrongValue  = "wrong_value"
+	email       = "user@example.com"
+	name        = "reboot"
+	ttl         = time.Minute
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+type commandRes struct {
+	ID        string                 `json:"id"`
+	ThingID   string                 `json:"thing_id"`
+	ChannelID string                 `json:"channel_id"`
+	Name      string                 `json:"name"`
+	Params    map[string]interface{} `json:"params"`
+	Status    string                 `json:"status"`
+}
+
+type commandsPageRes struct {
+	Total    uint64       `json:"total"`
+	Commands []commandRes `json:"commands"`
+}
+
+func newThingsService(tokens map[string]string) things.Service {
+	auth := thmocks.NewAuthService(tokens)
+	conns := make(chan thmocks.Connection)
+	thingsRepo := thmocks.NewThingRepository(conns)
+	channelsRepo := thmocks.NewChannelRepository(thingsRepo, conns)
+	chanCache := thmocks.NewChannelCache()
+	thingCache := thmocks.NewThingCache()
+	idp := thmocks.NewIdentityProvider()
+
+	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+}
+
+func newService(url string, presence commands.Presence) commands.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{BaseURL: url})
+	return commands.New(mocks.NewCommandRepository(), sdk, mocks.NewIdentityProvider(), presence, mocks.NewPublisher(), ttl)
+}
+
+func newServer(svc commands.Service) *httptest.Server {
+	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	return httptest.NewServer(mux)
+}
+
+func createThing(t *testing.T, svc things.Service) (string, string) {
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "thing"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "channel"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.Connect(context.Background(), token, []string{chs[0].ID}, []string{ths[0].ID})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return ths[0].ID, chs[0].ID
+}
+
+func TestSendCommand(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ths := httptest.NewServer(thingsapi.MakeHandler(mocktracer.New(), tsvc))
+	defer ths.Close()
+	presence := mocks.NewPresence()
+	svc := newService(ths.URL, presence)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	id, chID := createThing(t, tsvc)
+	presence.Set(id, true)
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+		res         commandRes
+	}{
+		{
+			desc:        "send command",
+			id:          id,
+			req:         `{"name":"reboot","params":{"delay":5},"ttl":60}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			res:         commandRes{ThingID: id, ChannelID: chID, Name: name, Params: map[string]interface{}{"delay": float64(5)}, Status: commands.StatusSent},
+		},
+		{
+			desc:        "send command through channel",
+			id:          id,
+			req:         fmt.Sprintf(`{"name":"reboot","channel":"%s"}`, chID),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+			res:         commandRes{ThingID: id, ChannelID: chID, Name: name, Status: commands.StatusSent},
+		},
+		{
+			desc:        "send command through channel thing isn't connected to",
+			id:          id,
+			req:         fmt.Sprintf(`{"name":"reboot","channel":"%s"}`, wrongValue),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusConflict,
+		},
+		{
+			desc:        "send command without name",
+			id:          id,
+			req:         `{"params":{"delay":5}}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "send command with negative TTL",
+			id:          id,
+			req:         `{"name":"reboot","ttl":-1}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "send command with malformed request",
+			id:          id,
+			req:         `{"name":`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "send command with invalid content type",
+			id:          id,
+			req:         `{"name":"reboot"}`,
+			contentType: "text/plain",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "send command with invalid token",
+			id:          id,
+			req:         `{"name":"reboot"}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "send command to non-existing thing",
+			id:          wrongValue,
+			req:         `{"name":"reboot"}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/things/%s/commands", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusCreated {
+			continue
+		}
+
+		var body commandRes
+		json.NewDecoder(res.Body).Decode(&body)
+		location := res.Header.Get("Location")
+		assert.Equal(t, fmt.Sprintf("/commands/%s", body.ID), location, fmt.Sprintf("%s: expected location /commands/%s got %s", tc.desc, body.ID, location))
+		tc.res.ID = body.ID
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body))
+	}
+}
+
+func TestViewCommand(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ths := httptest.NewServer(thingsapi.MakeHandler(mocktracer.New(), tsvc))
+	defer ths.Close()
+	svc := newService(ths.URL, mocks.NewPresence())
+	ts := newServer(svc)
+	defer ts.Close()
+
+	id, chID := createThing(t, tsvc)
+	cmd, err := svc.SendCommand(context.Background(), token, commands.Command{ThingID: id, Name: name}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    commandRes
+	}{
+		{
+			desc:   "view command",
+			id:     cmd.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    commandRes{ID: cmd.ID, ThingID: id, ChannelID: chID, Name: name, Status: commands.StatusQueued},
+		},
+		{
+			desc:   "view command with invalid token",
+			id:     cmd.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    commandRes{},
+		},
+		{
+			desc:   "view command with empty token",
+			id:     cmd.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+			res:    commandRes{},
+		},
+		{
+			desc:   "view non-existing command",
+			id:     wrongValue,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    commandRes{},
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/commands/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body commandRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body))
+	}
+}
+
+func TestListCommands(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ths := httptest.NewServer(thingsapi.MakeHandler(mocktracer.New(), tsvc))
+	defer ths.Close()
+	presence := mocks.NewPresence()
+	svc := newService(ths.URL, presence)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	id, _ := createThing(t, tsvc)
+	n := 4
+	for i := 0; i < n; i++ {
+		presence.Set(id, i%2 == 0)
+		_, err := svc.SendCommand(context.Background(), token, commands.Command{ThingID: id, Name: name}, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		query  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list commands",
+			id:     id,
+			auth:   token,
+			query:  "",
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list commands with offset and limit",
+			id:     id,
+			auth:   token,
+			query:  "?offset=1&limit=2",
+			status: http.StatusOK,
+			size:   2,
+		},
+		{
+			desc:   "list commands with status",
+			id:     id,
+			auth:   token,
+			query:  "?status=queued",
+			status: http.StatusOK,
+			size:   n / 2,
+		},
+		{
+			desc:   "list commands with invalid status",
+			id:     id,
+			auth:   token,
+			query:  "?status=unknown",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list commands with invalid token",
+			id:     id,
+			auth:   wrongValue,
+			query:  "",
+			status: http.StatusForbidden,
+			size:   0,
+		},
+		{
+			desc:   "list commands with zero limit",
+			id:     id,
+			auth:   token,
+			query:  "?limit=0",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list commands with limit greater than max",
+			id:     id,
+			auth:   token,
+			query:  "?limit=101",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list commands with invalid offset",
+			id:     id,
+			auth:   token,
+			query:  "?offset=e",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list commands of non-existing thing",
+			id:     wrongValue,
+			auth:   token,
+			query:  "",
+			status: http.StatusNotFound,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/things/%s/commands%s", ts.URL, tc.id, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body commandsPageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.size, len(body.Commands), fmt.Sprintf("%s: expected %d commands got %d", tc.desc, tc.size, len(body.Commands)))
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import "github.com/cloustone/pandas/commands"
+
+const (
+	maxLimitSize = 100
+	maxNameSize  = 256
+)
+
+var statuses = map[string]bool{
+	commands.StatusQueued:       true,
+	commands.StatusSent:         true,
+	commands.StatusDelivered:    true,
+	commands.StatusAcknowledged: true,
+	commands.StatusFailed:       true,
+	commands.StatusExpired:      true,
+}
+
+type apiReq interface {
+	validate() error
+}
+
+type sendCommandReq struct {
+	token   string
+	thingID string
+	Name    string                 `json:"name"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	TTL     int64                  `json:"ttl,omitempty"`
+	Channel string                 `json:"channel,omitempty"`
+}
+
+func (req sendCommandReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.thingID == "" || req.Name == "" || len(req.Name) > maxNameSize || req.TTL < 0 {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewCommandReq struct {
+	token string
+	id    string
+}
+
+func (req viewCommandReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listCommandsReq struct {
+	token   string
+	thingID string
+	offset  uint64
+	limit   uint64
+	status  string
+}
+
+func (req listCommandsReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.thingID == "" || req.limit == 0 || req.limit > maxLimitSize {
+		return commands.ErrMalformedEntity
+	}
+
+	if req.status != "" && !statuses[req.status] {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
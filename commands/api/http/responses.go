@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*commandRes)(nil)
+	_ mainflux.Response = (*commandsPageRes)(nil)
+)
+
+type commandRes struct {
+	ID        string                 `json:"id"`
+	ThingID   string                 `json:"thing_id"`
+	ChannelID string                 `json:"channel_id"`
+	Name      string                 `json:"name"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	Status    string                 `json:"status"`
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	Created   time.Time              `json:"created"`
+	Updated   time.Time              `json:"updated"`
+	Expires   time.Time              `json:"expires"`
+	created   bool
+}
+
+func (res commandRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res commandRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/commands/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res commandRes) Empty() bool {
+	return false
+}
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type commandsPageRes struct {
+	pageRes
+	Commands []commandRes `json:"commands"`
+}
+
+func (res commandsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res commandsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res commandsPageRes) Empty() bool {
+	return false
+}
+
+func toCommandRes(cmd commands.Command) commandRes {
+	return commandRes{
+		ID:        cmd.ID,
+		ThingID:   cmd.ThingID,
+		ChannelID: cmd.ChannelID,
+		Name:      cmd.Name,
+		Params:    cmd.Params,
+		Status:    cmd.Status,
+		Result:    cmd.Result,
+		Error:     cmd.Error,
+		Created:   cmd.Created,
+		Updated:   cmd.Updated,
+		Expires:   cmd.Expires,
+	}
+}
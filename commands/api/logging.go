@@ -0,0 +1,105 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	log "github.com/cloustone/pandas/pkg/logger"
+)
+
+var _ commands.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    commands.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc commands.Service, logger log.Logger) commands.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) SendCommand(ctx context.Context, token string, cmd commands.Command, ttl time.Duration) (saved commands.Command, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method send_command for token %s and thing %s with name %s took %s to complete", token, cmd.ThingID, cmd.Name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SendCommand(ctx, token, cmd, ttl)
+}
+
+func (lm *loggingMiddleware) ViewCommand(ctx context.Context, token, id string) (cmd commands.Command, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_command for token %s and command %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewCommand(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListCommands(ctx context.Context, token, thingID string, offset, limit uint64, status string) (page commands.Page, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_commands for token %s and thing %s took %s to complete", token, thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListCommands(ctx, token, thingID, offset, limit, status)
+}
+
+func (lm *loggingMiddleware) Reply(ctx context.Context, thingID string, r commands.Reply) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method reply for thing %s and command %s with status %s took %s to complete", thingID, r.ID, r.Status, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Reply(ctx, thingID, r)
+}
+
+func (lm *loggingMiddleware) Dispatch(ctx context.Context, thingID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method dispatch for thing %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Dispatch(ctx, thingID)
+}
+
+func (lm *loggingMiddleware) Expire(ctx context.Context, now time.Time) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method expire took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Expire(ctx, now)
+}
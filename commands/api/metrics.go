@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ commands.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     commands.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc commands.Service, counter metrics.Counter, latency metrics.Histogram) commands.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) SendCommand(ctx context.Context, token string, cmd commands.Command, ttl time.Duration) (commands.Command, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "send_command").Add(1)
+		ms.latency.With("method", "send_command").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SendCommand(ctx, token, cmd, ttl)
+}
+
+func (ms *metricsMiddleware) ViewCommand(ctx context.Context, token, id string) (commands.Command, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_command").Add(1)
+		ms.latency.With("method", "view_command").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewCommand(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListCommands(ctx context.Context, token, thingID string, offset, limit uint64, status string) (commands.Page, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_commands").Add(1)
+		ms.latency.With("method", "list_commands").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListCommands(ctx, token, thingID, offset, limit, status)
+}
+
+func (ms *metricsMiddleware) Reply(ctx context.Context, thingID string, r commands.Reply) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reply").Add(1)
+		ms.latency.With("method", "reply").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Reply(ctx, thingID, r)
+}
+
+func (ms *metricsMiddleware) Dispatch(ctx context.Context, thingID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "dispatch").Add(1)
+		ms.latency.With("method", "dispatch").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Dispatch(ctx, thingID)
+}
+
+func (ms *metricsMiddleware) Expire(ctx context.Context, now time.Time) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "expire").Add(1)
+		ms.latency.With("method", "expire").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Expire(ctx, now)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"time"
+)
+
+// The statuses the commands go through. The command is queued until the
+// thing is online, sent once it's published to the thing, and delivered once
+// the thing or its adapter confirms the receipt. The acknowledged, failed and
+// expired commands are final.
+const (
+	StatusQueued       = "queued"
+	StatusSent         = "sent"
+	StatusDelivered    = "delivered"
+	StatusAcknowledged = "acknowledged"
+	StatusFailed       = "failed"
+	StatusExpired      = "expired"
+)
+
+// Command represents the downlink command sent to the thing.
+type Command struct {
+	ID        string
+	ThingID   string
+	ChannelID string
+	Name      string
+	Params    map[string]interface{}
+	Status    string
+	Result    map[string]interface{}
+	Error     string
+	Created   time.Time
+	Updated   time.Time
+	Expires   time.Time
+}
+
+// Final returns true if the command status can't change anymore.
+func (c Command) Final() bool {
+	switch c.Status {
+	case StatusAcknowledged, StatusFailed, StatusExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+}
+
+// Page contains page related metadata as well as a list of commands that
+// belong to this page.
+type Page struct {
+	PageMetadata
+	Commands []Command
+}
+
+// Repository specifies a command persistence API.
+type Repository interface {
+	// Save persists the command.
+	Save(context.Context, Command) error
+
+	// Update updates the status, the result, the error and the update time
+	// of the command.
+	Update(context.Context, Command) error
+
+	// RetrieveByID retrieves the command having the provided identifier.
+	RetrieveByID(context.Context, string) (Command, error)
+
+	// RetrieveAll retrieves the subset of commands sent to the thing, the
+	// newest first. The commands are filtered by the status unless it's
+	// empty.
+	RetrieveAll(context.Context, string, uint64, uint64, string) (Page, error)
+
+	// RetrieveQueued retrieves the queued commands of the thing, the oldest
+	// first.
+	RetrieveQueued(context.Context, string) ([]Command, error)
+
+	// RetrieveExpired retrieves the commands which aren't final and expired
+	// before the given time.
+	RetrieveExpired(context.Context, time.Time) ([]Command, error)
+}
+
+// Publisher specifies the API for sending the commands to the things.
+type Publisher interface {
+	// Publish sends the command to the thing.
+	Publish(context.Context, Command) error
+}
+
+// Presence specifies the API for checking whether the things are online.
+type Presence interface {
+	// Online checks whether the thing identified by the provided ID, that
+	// belongs to the user identified by the provided key, is online.
+	Online(context.Context, string, string) (bool, error)
+}
+
+// IdentityProvider specifies an API for generating unique identifiers.
+type IdentityProvider interface {
+	// ID generates the unique identifier.
+	ID() (string, error)
+}
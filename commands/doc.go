@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package commands contains the domain concept definitions needed to support
+// the downlink commands functionality. The commands are sent to the things
+// through the channels they are connected to, and tracked until the things
+// acknowledge them, they fail or expire.
+package commands
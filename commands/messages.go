@@ -0,0 +1,73 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+)
+
+const (
+	subtopicPrefix = broker.SubtopicCommands
+	replySuffix    = "reply"
+
+	// CommandsSubject is the NATS subject the commands of all things are
+	// published to.
+	CommandsSubject = "channel.*.commands.*"
+
+	// RepliesSubject is the NATS subject the replies to the commands of all
+	// things are published to.
+	RepliesSubject = "channel.*.commands.*.reply"
+)
+
+// Request is the command as received by the thing.
+type Request struct {
+	ID      string                 `json:"id"`
+	Name    string                 `json:"name"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Expires int64                  `json:"expires"`
+}
+
+// Reply is the reply of the thing, or its adapter, to the command. The
+// status is one of delivered, acknowledged or failed.
+type Reply struct {
+	ID     string                 `json:"id"`
+	Status string                 `json:"status"`
+	Result map[string]interface{} `json:"result,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// Subtopic returns the subtopic the commands of the thing are published to,
+// i.e. the MQTT topic channels/<channel_id>/messages/commands/<thing_id>.
+func Subtopic(thingID string) string {
+	return fmt.Sprintf("%s.%s", subtopicPrefix, thingID)
+}
+
+// ReplySubtopic returns the subtopic the thing replies to its commands on,
+// i.e. the MQTT topic channels/<channel_id>/messages/commands/<thing_id>/reply.
+func ReplySubtopic(thingID string) string {
+	return fmt.Sprintf("%s.%s.%s", subtopicPrefix, thingID, replySuffix)
+}
+
+// ParseSubtopic returns the thing the commands subtopic belongs to, and
+// whether it's the reply subtopic.
+func ParseSubtopic(subtopic string) (thingID string, reply bool, ok bool) {
+	parts := strings.Split(subtopic, ".")
+	switch {
+	case len(parts) == 2 && parts[0] == subtopicPrefix && parts[1] != "":
+		return parts[1], false, true
+	case len(parts) == 3 && parts[0] == subtopicPrefix && parts[1] != "" && parts[2] == replySuffix:
+		return parts[1], true, true
+	default:
+		return "", false, false
+	}
+}
+
+// IsReply returns true if the subtopic is the commands reply subtopic.
+func IsReply(subtopic string) bool {
+	_, reply, ok := ParseSubtopic(subtopic)
+	return ok && reply
+}
@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+)
+
+var _ commands.Repository = (*commandRepositoryMock)(nil)
+
+type commandRepositoryMock struct {
+	mu       sync.Mutex
+	commands map[string]commands.Command
+}
+
+// NewCommandRepository creates in-memory command repository.
+func NewCommandRepository() commands.Repository {
+	return &commandRepositoryMock{
+		commands: make(map[string]commands.Command),
+	}
+}
+
+func (crm *commandRepositoryMock) Save(_ context.Context, cmd commands.Command) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	crm.commands[cmd.ID] = cmd
+	return nil
+}
+
+func (crm *commandRepositoryMock) Update(_ context.Context, cmd commands.Command) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	saved, ok := crm.commands[cmd.ID]
+	if !ok {
+		return commands.ErrNotFound
+	}
+	saved.Status = cmd.Status
+	saved.Result = cmd.Result
+	saved.Error = cmd.Error
+	saved.Updated = cmd.Updated
+	crm.commands[cmd.ID] = saved
+
+	return nil
+}
+
+func (crm *commandRepositoryMock) RetrieveByID(_ context.Context, id string) (commands.Command, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cmd, ok := crm.commands[id]
+	if !ok {
+		return commands.Command{}, commands.ErrNotFound
+	}
+
+	return cmd, nil
+}
+
+func (crm *commandRepositoryMock) RetrieveAll(_ context.Context, thingID string, offset, limit uint64, status string) (commands.Page, error) {
+	cmds := crm.filter(func(cmd commands.Command) bool {
+		return cmd.ThingID == thingID && (status == "" || cmd.Status == status)
+	})
+	// The newest commands first.
+	for i, j := 0, len(cmds)-1; i < j; i, j = i+1, j-1 {
+		cmds[i], cmds[j] = cmds[j], cmds[i]
+	}
+
+	page := commands.Page{
+		PageMetadata: commands.PageMetadata{
+			Total:  uint64(len(cmds)),
+			Offset: offset,
+			Limit:  limit,
+		},
+		Commands: []commands.Command{},
+	}
+	if offset >= uint64(len(cmds)) {
+		return page, nil
+	}
+	end := offset + limit
+	if end > uint64(len(cmds)) {
+		end = uint64(len(cmds))
+	}
+	page.Commands = cmds[offset:end]
+
+	return page, nil
+}
+
+func (crm *commandRepositoryMock) RetrieveQueued(_ context.Context, thingID string) ([]commands.Command, error) {
+	return crm.filter(func(cmd commands.Command) bool {
+		return cmd.ThingID == thingID && cmd.Status == commands.StatusQueued
+	}), nil
+}
+
+func (crm *commandRepositoryMock) RetrieveExpired(_ context.Context, now time.Time) ([]commands.Command, error) {
+	return crm.filter(func(cmd commands.Command) bool {
+		return !cmd.Final() && cmd.Expires.Before(now)
+	}), nil
+}
+
+// filter returns the matching commands, the oldest first.
+func (crm *commandRepositoryMock) filter(match func(commands.Command) bool) []commands.Command {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cmds := []commands.Command{}
+	for _, cmd := range crm.commands {
+		if match(cmd) {
+			cmds = append(cmds, cmd)
+		}
+	}
+	sort.Slice(cmds, func(i, j int) bool {
+		return cmds[i].ID < cmds[j].ID
+	})
+
+	return cmds
+}
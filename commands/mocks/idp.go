@@ -0,0 +1,34 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cloustone/pandas/commands"
+)
+
+const u4Pref = "123e4567-e89b-12d3-a456-"
+
+var _ commands.IdentityProvider = (*identityProviderMock)(nil)
+
+type identityProviderMock struct {
+	mu      sync.Mutex
+	counter int
+}
+
+// NewIdentityProvider creates the identity provider generating the
+// sequential UUIDs.
+func NewIdentityProvider() commands.IdentityProvider {
+	return &identityProviderMock{}
+}
+
+func (idp *identityProviderMock) ID() (string, error) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	idp.counter++
+	return fmt.Sprintf("%s%012d", u4Pref, idp.counter), nil
+}
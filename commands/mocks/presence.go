@@ -0,0 +1,42 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloustone/pandas/commands"
+)
+
+var _ commands.Presence = (*Presence)(nil)
+
+// Presence is the presence of the things set by the tests.
+type Presence struct {
+	mu     sync.Mutex
+	online map[string]bool
+}
+
+// NewPresence returns the presence with all things offline.
+func NewPresence() *Presence {
+	return &Presence{
+		online: make(map[string]bool),
+	}
+}
+
+// Online returns whether the thing is set online.
+func (p *Presence) Online(_ context.Context, _, thingID string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.online[thingID], nil
+}
+
+// Set sets whether the thing is online.
+func (p *Presence) Set(thingID string, online bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.online[thingID] = online
+}
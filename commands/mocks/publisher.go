@@ -0,0 +1,60 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cloustone/pandas/commands"
+)
+
+var (
+	_ commands.Publisher = (*Publisher)(nil)
+
+	errPublish = errors.New("failed to publish command")
+)
+
+// Publisher is the commands publisher which records the published commands.
+type Publisher struct {
+	mu       sync.Mutex
+	failing  bool
+	commands []commands.Command
+}
+
+// NewPublisher returns the recording commands publisher.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the command unless the publisher is failing.
+func (pub *Publisher) Publish(_ context.Context, cmd commands.Command) error {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	if pub.failing {
+		return errPublish
+	}
+	pub.commands = append(pub.commands, cmd)
+	return nil
+}
+
+// Fail makes the publisher fail to publish the commands.
+func (pub *Publisher) Fail(failing bool) {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	pub.failing = failing
+}
+
+// Commands returns the recorded commands and clears them.
+func (pub *Publisher) Commands() []commands.Command {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	cmds := pub.commands
+	pub.commands = nil
+	return cmds
+}
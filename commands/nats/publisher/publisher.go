@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/mainflux/broker"
+)
+
+const contentType = "application/json"
+
+var _ commands.Publisher = (*publisher)(nil)
+
+type publisher struct {
+	broker broker.Nats
+}
+
+// NewPublisher returns the publisher of the commands to the commands
+// subtopic of the channel the thing is connected to.
+func NewPublisher(b broker.Nats) commands.Publisher {
+	return publisher{broker: b}
+}
+
+// Publish publishes the command to the thing. The adapters deliver the
+// command to the things subscribed to the commands subtopic.
+func (pub publisher) Publish(ctx context.Context, cmd commands.Command) error {
+	req := commands.Request{
+		ID:      cmd.ID,
+		Name:    cmd.Name,
+		Params:  cmd.Params,
+		Expires: cmd.Expires.Unix(),
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	msg := broker.Message{
+		Channel:     cmd.ChannelID,
+		Subtopic:    commands.Subtopic(cmd.ThingID),
+		ContentType: contentType,
+		Payload:     payload,
+	}
+
+	return pub.broker.Publish(ctx, "", msg)
+}
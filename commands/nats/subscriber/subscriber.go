@@ -0,0 +1,101 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/mainflux/broker"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/cloustone/pandas/presence"
+	"github.com/gogo/protobuf/proto"
+	nats "github.com/nats-io/nats.go"
+)
+
+const queue = "commands"
+
+// Subscriber is used to intercept the replies of the things to their
+// commands, and the presence events of the things, to dispatch the queued
+// commands of the things coming online.
+type Subscriber struct {
+	broker    broker.Nats
+	logger    log.Logger
+	svc       commands.Service
+	channelID string
+}
+
+// NewSubscriber instances Subscriber strucure. The presence events aren't
+// subscribed to if the presence channel isn't set.
+func NewSubscriber(b broker.Nats, chID string, svc commands.Service, logger log.Logger) *Subscriber {
+	return &Subscriber{
+		broker:    b,
+		logger:    logger,
+		svc:       svc,
+		channelID: chID,
+	}
+}
+
+// Subscribe subscribes to the command replies of all channels and to the
+// presence events channel.
+func (s *Subscriber) Subscribe() error {
+	if _, err := s.broker.QueueSubscribe(commands.RepliesSubject, queue, s.handleReply); err != nil {
+		return err
+	}
+
+	if s.channelID == "" {
+		return nil
+	}
+	subject := fmt.Sprintf("channel.%s", s.channelID)
+	_, err := s.broker.QueueSubscribe(subject, queue, s.handleEvent)
+	return err
+}
+
+func (s *Subscriber) handleReply(m *nats.Msg) {
+	var msg broker.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
+		return
+	}
+
+	// Only the thing, or its adapter on its behalf, replies to its
+	// commands.
+	thingID, reply, ok := commands.ParseSubtopic(msg.Subtopic)
+	if !ok || !reply || msg.Publisher != thingID {
+		return
+	}
+
+	var r commands.Reply
+	if err := json.Unmarshal(msg.Payload, &r); err != nil {
+		s.logger.Warn(fmt.Sprintf("Malformed reply of thing %s: %s", thingID, err))
+		return
+	}
+
+	if err := s.svc.Reply(context.Background(), thingID, r); err != nil {
+		s.logger.Warn(fmt.Sprintf("Reply of thing %s to command %s failed: %s", thingID, r.ID, err))
+	}
+}
+
+func (s *Subscriber) handleEvent(m *nats.Msg) {
+	var msg broker.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
+		return
+	}
+
+	switch msg.Headers[broker.HeaderEventType] {
+	case presence.ConnectEvent, presence.ActivityEvent:
+	default:
+		return
+	}
+	if msg.Publisher == "" {
+		return
+	}
+
+	if err := s.svc.Dispatch(context.Background(), msg.Publisher); err != nil {
+		s.logger.Error(fmt.Sprintf("Dispatch of commands of thing %s failed: %s", msg.Publisher, err))
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	invalidTextErr = "invalid_text_representation"
+
+	fields = `id, thing_id, channel_id, name, params, status, result, error, created, updated, expires`
+)
+
+var (
+	errSaveDB        = errors.New("failed to save command to database")
+	errUpdateDB      = errors.New("failed to update command in database")
+	errRetrieveDB    = errors.New("failed to retrieve commands from database")
+	errMarshalJSON   = errors.New("failed to marshal command into json")
+	errUnmarshalJSON = errors.New("failed to unmarshal json to command")
+)
+
+var _ commands.Repository = (*commandRepository)(nil)
+
+type commandRepository struct {
+	db *sqlx.DB
+}
+
+// NewCommandRepository instantiates a PostgreSQL implementation of command
+// repository.
+func NewCommandRepository(db *sqlx.DB) commands.Repository {
+	return &commandRepository{db: db}
+}
+
+func (cr commandRepository) Save(ctx context.Context, cmd commands.Command) error {
+	q := `INSERT INTO commands (` + fields + `)
+		  VALUES (:id, :thing_id, :channel_id, :name, :params, :status, :result, :error, :created, :updated, :expires)`
+
+	dbc, err := toDBCommand(cmd)
+	if err != nil {
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	if _, err := cr.db.NamedExecContext(ctx, q, dbc); err != nil {
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (cr commandRepository) Update(ctx context.Context, cmd commands.Command) error {
+	q := `UPDATE commands SET status = :status, result = :result, error = :error, updated = :updated WHERE id = :id`
+
+	dbc, err := toDBCommand(cmd)
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	res, err := cr.db.NamedExecContext(ctx, q, dbc)
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+	if cnt == 0 {
+		return commands.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr commandRepository) RetrieveByID(ctx context.Context, id string) (commands.Command, error) {
+	q := `SELECT ` + fields + ` FROM commands WHERE id = $1`
+
+	var dbc dbCommand
+	if err := cr.db.QueryRowxContext(ctx, q, id).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return commands.Command{}, commands.ErrNotFound
+		}
+		// If the ID isn't a valid UUID, the command doesn't exist.
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == invalidTextErr {
+			return commands.Command{}, commands.ErrNotFound
+		}
+		return commands.Command{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toCommand(dbc)
+}
+
+func (cr commandRepository) RetrieveAll(ctx context.Context, thingID string, offset, limit uint64, status string) (commands.Page, error) {
+	q := `SELECT ` + fields + ` FROM commands
+		  WHERE thing_id = :thing_id AND (:status = '' OR status = :status)
+		  ORDER BY created DESC, id LIMIT :limit OFFSET :offset`
+	cq := `SELECT COUNT(*) FROM commands WHERE thing_id = :thing_id AND (:status = '' OR status = :status)`
+
+	params := map[string]interface{}{
+		"thing_id": thingID,
+		"status":   status,
+		"limit":    limit,
+		"offset":   offset,
+	}
+
+	cmds, err := cr.retrieve(ctx, q, params)
+	if err != nil {
+		return commands.Page{}, err
+	}
+
+	total, err := total(ctx, cr.db, cq, params)
+	if err != nil {
+		return commands.Page{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return commands.Page{
+		PageMetadata: commands.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+		Commands: cmds,
+	}, nil
+}
+
+func (cr commandRepository) RetrieveQueued(ctx context.Context, thingID string) ([]commands.Command, error) {
+	q := `SELECT ` + fields + ` FROM commands
+		  WHERE thing_id = :thing_id AND status = :status ORDER BY created, id`
+
+	params := map[string]interface{}{
+		"thing_id": thingID,
+		"status":   commands.StatusQueued,
+	}
+
+	return cr.retrieve(ctx, q, params)
+}
+
+func (cr commandRepository) RetrieveExpired(ctx context.Context, now time.Time) ([]commands.Command, error) {
+	q := `SELECT ` + fields + ` FROM commands
+		  WHERE status IN (:queued, :sent, :delivered) AND expires < :now ORDER BY expires, id`
+
+	params := map[string]interface{}{
+		"queued":    commands.StatusQueued,
+		"sent":      commands.StatusSent,
+		"delivered": commands.StatusDelivered,
+		"now":       now.UTC(),
+	}
+
+	return cr.retrieve(ctx, q, params)
+}
+
+func (cr commandRepository) retrieve(ctx context.Context, q string, params map[string]interface{}) ([]commands.Command, error) {
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	cmds := []commands.Command{}
+	for rows.Next() {
+		var dbc dbCommand
+		if err := rows.StructScan(&dbc); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+
+		cmd, err := toCommand(dbc)
+		if err != nil {
+			return nil, err
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	return cmds, nil
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params map[string]interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
+
+type dbCommand struct {
+	ID        string         `db:"id"`
+	ThingID   string         `db:"thing_id"`
+	ChannelID string         `db:"channel_id"`
+	Name      string         `db:"name"`
+	Params    []byte         `db:"params"`
+	Status    string         `db:"status"`
+	Result    []byte         `db:"result"`
+	Error     sql.NullString `db:"error"`
+	Created   time.Time      `db:"created"`
+	Updated   time.Time      `db:"updated"`
+	Expires   time.Time      `db:"expires"`
+}
+
+func toDBCommand(cmd commands.Command) (dbCommand, error) {
+	params, err := toJSON(cmd.Params)
+	if err != nil {
+		return dbCommand{}, errors.Wrap(errMarshalJSON, err)
+	}
+
+	result, err := toJSON(cmd.Result)
+	if err != nil {
+		return dbCommand{}, errors.Wrap(errMarshalJSON, err)
+	}
+
+	return dbCommand{
+		ID:        cmd.ID,
+		ThingID:   cmd.ThingID,
+		ChannelID: cmd.ChannelID,
+		Name:      cmd.Name,
+		Params:    params,
+		Status:    cmd.Status,
+		Result:    result,
+		Error:     sql.NullString{String: cmd.Error, Valid: cmd.Error != ""},
+		Created:   cmd.Created.UTC(),
+		Updated:   cmd.Updated.UTC(),
+		Expires:   cmd.Expires.UTC(),
+	}, nil
+}
+
+func toCommand(dbc dbCommand) (commands.Command, error) {
+	var params, result map[string]interface{}
+	if len(dbc.Params) > 0 {
+		if err := json.Unmarshal(dbc.Params, &params); err != nil {
+			return commands.Command{}, errors.Wrap(errUnmarshalJSON, err)
+		}
+	}
+	if len(dbc.Result) > 0 {
+		if err := json.Unmarshal(dbc.Result, &result); err != nil {
+			return commands.Command{}, errors.Wrap(errUnmarshalJSON, err)
+		}
+	}
+
+	return commands.Command{
+		ID:        dbc.ID,
+		ThingID:   dbc.ThingID,
+		ChannelID: dbc.ChannelID,
+		Name:      dbc.Name,
+		Params:    params,
+		Status:    dbc.Status,
+		Result:    result,
+		Error:     dbc.Error.String,
+		Created:   dbc.Created.UTC(),
+		Updated:   dbc.Updated.UTC(),
+		Expires:   dbc.Expires.UTC(),
+	}, nil
+}
+
+// toJSON marshals the map, the empty map is stored as NULL.
+func toJSON(m map[string]interface{}) ([]byte, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(m)
+}
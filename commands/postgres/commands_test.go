@@ -0,0 +1,260 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/commands/postgres"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wrongValue = "wrong-value"
+
+func newCommand(t *testing.T, thingID, status string, created time.Time) commands.Command {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	created = created.UTC().Truncate(time.Microsecond)
+	return commands.Command{
+		ID:        id.String(),
+		ThingID:   thingID,
+		ChannelID: "channel",
+		Name:      "reboot",
+		Params:    map[string]interface{}{"delay": float64(5)},
+		Status:    status,
+		Created:   created,
+		Updated:   created,
+		Expires:   created.Add(time.Minute),
+	}
+}
+
+func newThingID(t *testing.T) string {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	return id.String()
+}
+
+func TestCommandSave(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+	cmd := newCommand(t, newThingID(t), commands.StatusQueued, time.Now())
+
+	err := repo.Save(context.Background(), cmd)
+	assert.Nil(t, err, fmt.Sprintf("save command: unexpected error: %s", err))
+
+	saved, err := repo.RetrieveByID(context.Background(), cmd.ID)
+	require.Nil(t, err, fmt.Sprintf("retrieve command: unexpected error: %s", err))
+	assert.Equal(t, cmd, saved, fmt.Sprintf("save command: expected %v got %v", cmd, saved))
+
+	err = repo.Save(context.Background(), cmd)
+	assert.NotNil(t, err, "save command with existing ID: expected error")
+}
+
+func TestCommandUpdate(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+	cmd := newCommand(t, newThingID(t), commands.StatusSent, time.Now())
+	err := repo.Save(context.Background(), cmd)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	updated := cmd
+	updated.Status = commands.StatusFailed
+	updated.Error = "device busy"
+	updated.Result = map[string]interface{}{"code": float64(16)}
+	updated.Updated = cmd.Updated.Add(time.Second)
+	// The fields other than the status, result, error and update time
+	// aren't updated.
+	updated.Name = "shutdown"
+
+	cases := []struct {
+		desc string
+		cmd  commands.Command
+		err  error
+	}{
+		{
+			desc: "update existing command",
+			cmd:  updated,
+			err:  nil,
+		},
+		{
+			desc: "update non-existing command",
+			cmd:  newCommand(t, cmd.ThingID, commands.StatusSent, time.Now()),
+			err:  commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Update(context.Background(), tc.cmd)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	saved, err := repo.RetrieveByID(context.Background(), cmd.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	updated.Name = cmd.Name
+	assert.Equal(t, updated, saved, fmt.Sprintf("update command: expected %v got %v", updated, saved))
+}
+
+func TestCommandRetrieveByID(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+	cmd := newCommand(t, newThingID(t), commands.StatusQueued, time.Now())
+	err := repo.Save(context.Background(), cmd)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "retrieve existing command",
+			id:   cmd.ID,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing command",
+			id:   newThingID(t),
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "retrieve command with malformed ID",
+			id:   wrongValue,
+			err:  commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestCommandRetrieveAll(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+	thingID := newThingID(t)
+	start := time.Now()
+
+	n := 10
+	for i := 0; i < n; i++ {
+		status := commands.StatusSent
+		if i%2 == 0 {
+			status = commands.StatusQueued
+		}
+		err := repo.Save(context.Background(), newCommand(t, thingID, status, start.Add(time.Duration(i)*time.Second)))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	err := repo.Save(context.Background(), newCommand(t, newThingID(t), commands.StatusQueued, start))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		offset uint64
+		limit  uint64
+		status string
+		size   int
+		total  uint64
+	}{
+		{
+			desc:   "retrieve all commands of thing",
+			offset: 0,
+			limit:  uint64(n),
+			status: "",
+			size:   n,
+			total:  uint64(n),
+		},
+		{
+			desc:   "retrieve subset of commands of thing",
+			offset: 8,
+			limit:  5,
+			status: "",
+			size:   2,
+			total:  uint64(n),
+		},
+		{
+			desc:   "retrieve queued commands of thing",
+			offset: 0,
+			limit:  uint64(n),
+			status: commands.StatusQueued,
+			size:   n / 2,
+			total:  uint64(n / 2),
+		},
+		{
+			desc:   "retrieve commands of thing with status without commands",
+			offset: 0,
+			limit:  uint64(n),
+			status: commands.StatusExpired,
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveAll(context.Background(), thingID, tc.offset, tc.limit, tc.status)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(page.Commands), fmt.Sprintf("%s: expected %d commands got %d", tc.desc, tc.size, len(page.Commands)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, tc.total, page.Total))
+		for i := 1; i < len(page.Commands); i++ {
+			assert.True(t, page.Commands[i-1].Created.After(page.Commands[i].Created), fmt.Sprintf("%s: expected the newest commands first", tc.desc))
+		}
+	}
+}
+
+func TestCommandRetrieveQueued(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+	thingID := newThingID(t)
+	start := time.Now()
+
+	var queued []string
+	for i, status := range []string{commands.StatusQueued, commands.StatusSent, commands.StatusQueued, commands.StatusExpired} {
+		cmd := newCommand(t, thingID, status, start.Add(time.Duration(i)*time.Second))
+		err := repo.Save(context.Background(), cmd)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		if status == commands.StatusQueued {
+			queued = append(queued, cmd.ID)
+		}
+	}
+
+	cmds, err := repo.RetrieveQueued(context.Background(), thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var ids []string
+	for _, cmd := range cmds {
+		ids = append(ids, cmd.ID)
+	}
+	assert.Equal(t, queued, ids, fmt.Sprintf("retrieve queued commands: expected %v got %v", queued, ids))
+}
+
+func TestCommandRetrieveExpired(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+	thingID := newThingID(t)
+	past := time.Now().Add(-time.Hour)
+
+	expired := map[string]bool{}
+	for _, status := range []string{commands.StatusQueued, commands.StatusSent, commands.StatusDelivered, commands.StatusAcknowledged, commands.StatusFailed, commands.StatusExpired} {
+		cmd := newCommand(t, thingID, status, past)
+		err := repo.Save(context.Background(), cmd)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		if !cmd.Final() {
+			expired[cmd.ID] = true
+		}
+	}
+	fresh := newCommand(t, thingID, commands.StatusQueued, time.Now())
+	err := repo.Save(context.Background(), fresh)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cmds, err := repo.RetrieveExpired(context.Background(), time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	found := map[string]bool{}
+	for _, cmd := range cmds {
+		if cmd.ThingID == thingID {
+			found[cmd.ID] = true
+		}
+	}
+	assert.Equal(t, expired, found, fmt.Sprintf("retrieve expired commands: expected %v got %v", expired, found))
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" // required for SQL access
+	migrate "github.com/rubenv/sql-migrate"
+)
+
+// Config defines the options that are used when connecting to a PostgreSQL instance
+type Config struct {
+	Host        string
+	Port        string
+	User        string
+	Pass        string
+	Name        string
+	SSLMode     string
+	SSLCert     string
+	SSLKey      string
+	SSLRootCert string
+}
+
+// Connect creates a connection to the PostgreSQL instance and applies any
+// unapplied database migrations. A non-nil error is returned to indicate
+// failure.
+func Connect(cfg Config) (*sqlx.DB, error) {
+	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+
+	db, err := sqlx.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := migrateDB(db); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func migrateDB(db *sqlx.DB) error {
+	migrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "commands_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS commands (
+						id         UUID PRIMARY KEY,
+						thing_id   TEXT NOT NULL,
+						channel_id TEXT NOT NULL,
+						name       TEXT NOT NULL,
+						params     JSONB,
+						status     VARCHAR(16) NOT NULL,
+						result     JSONB,
+						error      TEXT,
+						created    TIMESTAMP NOT NULL,
+						updated    TIMESTAMP NOT NULL,
+						expires    TIMESTAMP NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS commands_thing_status_idx ON commands (thing_id, status)`,
+					`CREATE INDEX IF NOT EXISTS commands_status_expires_idx ON commands (status, expires)`,
+				},
+				Down: []string{
+					"DROP TABLE commands",
+				},
+			},
+		},
+	}
+
+	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+
+	return err
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package presence contains the client of the presence service used to
+// check whether the things are online.
+package presence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/pkg/errors"
+)
+
+const timeout = 5 * time.Second
+
+var errPresence = errors.New("failed to retrieve thing presence")
+
+var _ commands.Presence = (*client)(nil)
+
+type client struct {
+	url    string
+	client *http.Client
+}
+
+// New returns the client of the presence service available at the URL. If
+// the URL isn't set, all things are considered online.
+func New(url string) commands.Presence {
+	return client{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c client) Online(ctx context.Context, token, thingID string) (bool, error) {
+	if c.url == "" {
+		return true, nil
+	}
+
+	url := fmt.Sprintf("%s/presence/%s", c.url, thingID)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return false, errors.Wrap(errPresence, err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, errors.Wrap(errPresence, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, errors.Wrap(errPresence, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusForbidden:
+		return false, commands.ErrUnauthorizedAccess
+	case http.StatusNotFound:
+		return false, commands.ErrNotFound
+	default:
+		return false, errors.Wrap(errPresence, errors.New(http.StatusText(resp.StatusCode)))
+	}
+
+	var p presenceRes
+	if err := json.Unmarshal(body, &p); err != nil {
+		return false, errors.Wrap(errPresence, err)
+	}
+
+	return p.Online, nil
+}
+
+type presenceRes struct {
+	Online bool `json:"online"`
+}
@@ -0,0 +1,295 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+)
+
+const maxChannels = 100
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// command without name).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrNotConnected indicates that the thing isn't connected to the channel
+	// the command is sent through.
+	ErrNotConnected = errors.New("thing is not connected to the channel")
+
+	// ErrStatusTransition indicates the reply which can't change the status
+	// of the command, e.g. the reply to the expired command.
+	ErrStatusTransition = errors.New("invalid command status transition")
+
+	// ErrThings indicates failure to communicate with Mainflux Things service.
+	ErrThings = errors.New("failed to receive response from Things service")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// SendCommand sends the command to the thing identified by the command
+	// thing ID, that belongs to the user identified by the provided key. The
+	// command is queued until the thing is online. The command is sent
+	// through the first channel the thing is connected to unless its channel
+	// is set. The zero TTL is replaced by the default one.
+	SendCommand(context.Context, string, Command, time.Duration) (Command, error)
+
+	// ViewCommand retrieves the command identified by the provided ID, sent
+	// to the thing that belongs to the user identified by the provided key.
+	ViewCommand(context.Context, string, string) (Command, error)
+
+	// ListCommands retrieves the subset of commands sent to the thing
+	// identified by the provided ID, that belongs to the user identified by
+	// the provided key. The commands are filtered by the status unless it's
+	// empty.
+	ListCommands(context.Context, string, string, uint64, uint64, string) (Page, error)
+
+	// Reply updates the status of the command sent to the thing identified
+	// by the provided ID according to the thing reply.
+	Reply(context.Context, string, Reply) error
+
+	// Dispatch sends the queued commands to the thing identified by the
+	// provided ID once it's online.
+	Dispatch(context.Context, string) error
+
+	// Expire expires the commands which aren't final at the given time.
+	Expire(context.Context, time.Time) error
+}
+
+var _ Service = (*commandsService)(nil)
+
+type commandsService struct {
+	commands  Repository
+	sdk       mfsdk.SDK
+	idp       IdentityProvider
+	presence  Presence
+	publisher Publisher
+	ttl       time.Duration
+	mu        sync.Mutex
+}
+
+// New instantiates the commands service implementation. The commands expire
+// after the TTL unless their own TTL is set.
+func New(commands Repository, sdk mfsdk.SDK, idp IdentityProvider, presence Presence, publisher Publisher, ttl time.Duration) Service {
+	return &commandsService{
+		commands:  commands,
+		sdk:       sdk,
+		idp:       idp,
+		presence:  presence,
+		publisher: publisher,
+		ttl:       ttl,
+	}
+}
+
+func (cs *commandsService) SendCommand(ctx context.Context, token string, cmd Command, ttl time.Duration) (Command, error) {
+	if cmd.Name == "" || ttl < 0 {
+		return Command{}, ErrMalformedEntity
+	}
+
+	if _, err := cs.sdk.Thing(cmd.ThingID, token); err != nil {
+		return Command{}, thingsError(err)
+	}
+
+	chanID, err := cs.channel(token, cmd.ThingID, cmd.ChannelID)
+	if err != nil {
+		return Command{}, err
+	}
+
+	id, err := cs.idp.ID()
+	if err != nil {
+		return Command{}, err
+	}
+
+	if ttl == 0 {
+		ttl = cs.ttl
+	}
+	now := time.Now().UTC()
+	cmd.ID = id
+	cmd.ChannelID = chanID
+	cmd.Status = StatusQueued
+	cmd.Result = nil
+	cmd.Error = ""
+	cmd.Created = now
+	cmd.Updated = now
+	cmd.Expires = now.Add(ttl)
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if err := cs.commands.Save(ctx, cmd); err != nil {
+		return Command{}, err
+	}
+
+	// The command is sent if the presence of the thing is unknown, in the
+	// worst case it's lost and expires.
+	online, err := cs.presence.Online(ctx, token, cmd.ThingID)
+	if err == nil && !online {
+		return cmd, nil
+	}
+
+	return cs.send(ctx, cmd)
+}
+
+func (cs *commandsService) ViewCommand(ctx context.Context, token, id string) (Command, error) {
+	cmd, err := cs.commands.RetrieveByID(ctx, id)
+	if err != nil {
+		return Command{}, err
+	}
+
+	if _, err := cs.sdk.Thing(cmd.ThingID, token); err != nil {
+		return Command{}, thingsError(err)
+	}
+
+	return cmd, nil
+}
+
+func (cs *commandsService) ListCommands(ctx context.Context, token, thingID string, offset, limit uint64, status string) (Page, error) {
+	if _, err := cs.sdk.Thing(thingID, token); err != nil {
+		return Page{}, thingsError(err)
+	}
+
+	return cs.commands.RetrieveAll(ctx, thingID, offset, limit, status)
+}
+
+func (cs *commandsService) Reply(ctx context.Context, thingID string, r Reply) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cmd, err := cs.commands.RetrieveByID(ctx, r.ID)
+	if err != nil {
+		return err
+	}
+	// The things can't reply to the commands of the other things.
+	if cmd.ThingID != thingID {
+		return ErrNotFound
+	}
+
+	switch r.Status {
+	case StatusDelivered:
+		if cmd.Status != StatusQueued && cmd.Status != StatusSent {
+			return ErrStatusTransition
+		}
+	case StatusAcknowledged, StatusFailed:
+		if cmd.Final() {
+			return ErrStatusTransition
+		}
+		cmd.Result = r.Result
+		cmd.Error = r.Error
+	default:
+		return ErrMalformedEntity
+	}
+
+	cmd.Status = r.Status
+	cmd.Updated = time.Now().UTC()
+	return cs.commands.Update(ctx, cmd)
+}
+
+func (cs *commandsService) Dispatch(ctx context.Context, thingID string) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	queued, err := cs.commands.RetrieveQueued(ctx, thingID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, cmd := range queued {
+		if now.After(cmd.Expires) {
+			if err := cs.expire(ctx, cmd, now); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, err := cs.send(ctx, cmd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cs *commandsService) Expire(ctx context.Context, now time.Time) error {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	expired, err := cs.commands.RetrieveExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, cmd := range expired {
+		if err := cs.expire(ctx, cmd, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// send publishes the command and marks it sent, the command stays queued if
+// it isn't published.
+func (cs *commandsService) send(ctx context.Context, cmd Command) (Command, error) {
+	if err := cs.publisher.Publish(ctx, cmd); err != nil {
+		return Command{}, err
+	}
+
+	cmd.Status = StatusSent
+	cmd.Updated = time.Now().UTC()
+	if err := cs.commands.Update(ctx, cmd); err != nil {
+		return Command{}, err
+	}
+
+	return cmd, nil
+}
+
+func (cs *commandsService) expire(ctx context.Context, cmd Command, now time.Time) error {
+	cmd.Status = StatusExpired
+	cmd.Updated = now.UTC()
+	return cs.commands.Update(ctx, cmd)
+}
+
+// channel returns the channel the command is sent through, the thing has to
+// be connected to it.
+func (cs *commandsService) channel(token, thingID, chanID string) (string, error) {
+	page, err := cs.sdk.ChannelsByThing(token, thingID, 0, maxChannels)
+	if err != nil {
+		return "", thingsError(err)
+	}
+
+	for _, ch := range page.Channels {
+		if chanID == "" || ch.ID == chanID {
+			return ch.ID, nil
+		}
+	}
+
+	return "", ErrNotConnected
+}
+
+func thingsError(err error) error {
+	switch err {
+	case mfsdk.ErrInvalidArgs:
+		return ErrMalformedEntity
+	case mfsdk.ErrUnauthorized:
+		return ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return ErrNotFound
+	default:
+		return errors.Wrap(ErrThings, err)
+	}
+}
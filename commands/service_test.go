@@ -0,0 +1,528 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/commands/mocks"
+	"github.com/cloustone/pandas/pkg/errors"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/cloustone/pandas/things"
+	httpapi "github.com/cloustone/pandas/things/api/things/http"
+	thmocks "github.com/cloustone/pandas/things/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	name       = "reboot"
+	ttl        = time.Minute
+)
+
+var params = map[string]interface{}{"delay": float64(5)}
+
+type env struct {
+	things    things.Service
+	svc       commands.Service
+	presence  *mocks.Presence
+	publisher *mocks.Publisher
+	close     func()
+}
+
+func newThingsService(tokens map[string]string) things.Service {
+	auth := thmocks.NewAuthService(tokens)
+	conns := make(chan thmocks.Connection)
+	thingsRepo := thmocks.NewThingRepository(conns)
+	channelsRepo := thmocks.NewChannelRepository(thingsRepo, conns)
+	chanCache := thmocks.NewChannelCache()
+	thingCache := thmocks.NewThingCache()
+	idp := thmocks.NewIdentityProvider()
+
+	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+}
+
+func newEnv() env {
+	tsvc := newThingsService(map[string]string{token: email})
+	ts := httptest.NewServer(httpapi.MakeHandler(mocktracer.New(), tsvc))
+	sdk := mfsdk.NewSDK(mfsdk.Config{BaseURL: ts.URL})
+
+	presence := mocks.NewPresence()
+	pub := mocks.NewPublisher()
+	svc := commands.New(mocks.NewCommandRepository(), sdk, mocks.NewIdentityProvider(), presence, pub, ttl)
+
+	return env{
+		things:    tsvc,
+		svc:       svc,
+		presence:  presence,
+		publisher: pub,
+		close:     ts.Close,
+	}
+}
+
+// createThing creates the thing connected to the returned channels.
+func createThing(t *testing.T, svc things.Service, connected int) (string, []string) {
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "thing"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var chIDs []string
+	for i := 0; i < connected; i++ {
+		chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "channel"})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		chIDs = append(chIDs, chs[0].ID)
+	}
+	if connected > 0 {
+		err = svc.Connect(context.Background(), token, chIDs, []string{ths[0].ID})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	return ths[0].ID, chIDs
+}
+
+func TestSendCommand(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	onlineID, chIDs := createThing(t, e.things, 2)
+	offlineID, _ := createThing(t, e.things, 1)
+	unconnectedID, _ := createThing(t, e.things, 0)
+	e.presence.Set(onlineID, true)
+
+	cases := []struct {
+		desc      string
+		token     string
+		cmd       commands.Command
+		ttl       time.Duration
+		status    string
+		channelID string
+		published bool
+		err       error
+	}{
+		{
+			desc:      "send command to online thing",
+			token:     token,
+			cmd:       commands.Command{ThingID: onlineID, Name: name, Params: params},
+			status:    commands.StatusSent,
+			channelID: chIDs[0],
+			published: true,
+			err:       nil,
+		},
+		{
+			desc:      "send command to online thing through channel",
+			token:     token,
+			cmd:       commands.Command{ThingID: onlineID, ChannelID: chIDs[1], Name: name},
+			ttl:       time.Second,
+			status:    commands.StatusSent,
+			channelID: chIDs[1],
+			published: true,
+			err:       nil,
+		},
+		{
+			desc:   "send command to offline thing",
+			token:  token,
+			cmd:    commands.Command{ThingID: offlineID, Name: name},
+			status: commands.StatusQueued,
+			err:    nil,
+		},
+		{
+			desc:  "send command to thing through channel it isn't connected to",
+			token: token,
+			cmd:   commands.Command{ThingID: onlineID, ChannelID: wrongValue, Name: name},
+			err:   commands.ErrNotConnected,
+		},
+		{
+			desc:  "send command to thing not connected to any channel",
+			token: token,
+			cmd:   commands.Command{ThingID: unconnectedID, Name: name},
+			err:   commands.ErrNotConnected,
+		},
+		{
+			desc:  "send command without name",
+			token: token,
+			cmd:   commands.Command{ThingID: onlineID},
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command with negative TTL",
+			token: token,
+			cmd:   commands.Command{ThingID: onlineID, Name: name},
+			ttl:   -time.Second,
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command with wrong credentials",
+			token: wrongValue,
+			cmd:   commands.Command{ThingID: onlineID, Name: name},
+			err:   commands.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "send command to non-existing thing",
+			token: token,
+			cmd:   commands.Command{ThingID: wrongValue, Name: name},
+			err:   commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		cmd, err := e.svc.SendCommand(context.Background(), tc.token, tc.cmd, tc.ttl)
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		published := e.publisher.Commands()
+		if tc.err != nil {
+			assert.Empty(t, published, fmt.Sprintf("%s: expected no published commands", tc.desc))
+			continue
+		}
+
+		assert.NotEmpty(t, cmd.ID, fmt.Sprintf("%s: expected command ID", tc.desc))
+		assert.Equal(t, tc.status, cmd.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, tc.status, cmd.Status))
+		expTTL := tc.ttl
+		if expTTL == 0 {
+			expTTL = ttl
+		}
+		assert.Equal(t, expTTL, cmd.Expires.Sub(cmd.Created), fmt.Sprintf("%s: expected TTL %s got %s", tc.desc, expTTL, cmd.Expires.Sub(cmd.Created)))
+		if !tc.published {
+			assert.Empty(t, published, fmt.Sprintf("%s: expected no published commands", tc.desc))
+			continue
+		}
+		require.Len(t, published, 1, fmt.Sprintf("%s: expected published command", tc.desc))
+		assert.Equal(t, cmd.ID, published[0].ID, fmt.Sprintf("%s: expected published command %s got %s", tc.desc, cmd.ID, published[0].ID))
+		assert.Equal(t, tc.channelID, published[0].ChannelID, fmt.Sprintf("%s: expected channel %s got %s", tc.desc, tc.channelID, published[0].ChannelID))
+	}
+}
+
+func TestSendCommandPublishFailure(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	thingID, _ := createThing(t, e.things, 1)
+	e.presence.Set(thingID, true)
+	e.publisher.Fail(true)
+
+	_, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name}, 0)
+	assert.NotNil(t, err, "send command with failing publisher: expected error")
+
+	page, err := e.svc.ListCommands(context.Background(), token, thingID, 0, 10, commands.StatusQueued)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(1), page.Total, "send command with failing publisher: expected queued command")
+}
+
+func TestViewCommand(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	thingID, _ := createThing(t, e.things, 1)
+	cmd, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name, Params: params}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		cmd   commands.Command
+		err   error
+	}{
+		{
+			desc:  "view existing command",
+			token: token,
+			id:    cmd.ID,
+			cmd:   cmd,
+			err:   nil,
+		},
+		{
+			desc:  "view command with wrong credentials",
+			token: wrongValue,
+			id:    cmd.ID,
+			cmd:   commands.Command{},
+			err:   commands.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view non-existing command",
+			token: token,
+			id:    wrongValue,
+			cmd:   commands.Command{},
+			err:   commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		cmd, err := e.svc.ViewCommand(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.cmd, cmd, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.cmd, cmd))
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestListCommands(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	thingID, _ := createThing(t, e.things, 1)
+	n := 10
+	for i := 0; i < n; i++ {
+		e.presence.Set(thingID, i%2 == 0)
+		_, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name}, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		thingID string
+		offset  uint64
+		limit   uint64
+		status  string
+		size    int
+		err     error
+	}{
+		{
+			desc:    "list all commands",
+			token:   token,
+			thingID: thingID,
+			offset:  0,
+			limit:   uint64(n),
+			size:    n,
+			err:     nil,
+		},
+		{
+			desc:    "list subset of commands",
+			token:   token,
+			thingID: thingID,
+			offset:  8,
+			limit:   5,
+			size:    2,
+			err:     nil,
+		},
+		{
+			desc:    "list queued commands",
+			token:   token,
+			thingID: thingID,
+			offset:  0,
+			limit:   uint64(n),
+			status:  commands.StatusQueued,
+			size:    n / 2,
+			err:     nil,
+		},
+		{
+			desc:    "list commands with wrong credentials",
+			token:   wrongValue,
+			thingID: thingID,
+			offset:  0,
+			limit:   uint64(n),
+			size:    0,
+			err:     commands.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "list commands of non-existing thing",
+			token:   token,
+			thingID: wrongValue,
+			offset:  0,
+			limit:   uint64(n),
+			size:    0,
+			err:     commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := e.svc.ListCommands(context.Background(), tc.token, tc.thingID, tc.offset, tc.limit, tc.status)
+		assert.Equal(t, tc.size, len(page.Commands), fmt.Sprintf("%s: expected %d commands got %d", tc.desc, tc.size, len(page.Commands)))
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestReply(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	thingID, _ := createThing(t, e.things, 1)
+	otherID, _ := createThing(t, e.things, 1)
+	e.presence.Set(thingID, true)
+
+	send := func() commands.Command {
+		cmd, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name}, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		return cmd
+	}
+	delivered := send()
+	acknowledged := send()
+	failed := send()
+	final := send()
+	err := e.svc.Reply(context.Background(), thingID, commands.Reply{ID: final.ID, Status: commands.StatusAcknowledged})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	result := map[string]interface{}{"uptime": float64(100)}
+
+	cases := []struct {
+		desc    string
+		thingID string
+		reply   commands.Reply
+		status  string
+		result  map[string]interface{}
+		errMsg  string
+		err     error
+	}{
+		{
+			desc:    "deliver sent command",
+			thingID: thingID,
+			reply:   commands.Reply{ID: delivered.ID, Status: commands.StatusDelivered},
+			status:  commands.StatusDelivered,
+			err:     nil,
+		},
+		{
+			desc:    "deliver delivered command",
+			thingID: thingID,
+			reply:   commands.Reply{ID: delivered.ID, Status: commands.StatusDelivered},
+			status:  commands.StatusDelivered,
+			err:     commands.ErrStatusTransition,
+		},
+		{
+			desc:    "acknowledge delivered command",
+			thingID: thingID,
+			reply:   commands.Reply{ID: delivered.ID, Status: commands.StatusAcknowledged},
+			status:  commands.StatusAcknowledged,
+			err:     nil,
+		},
+		{
+			desc:    "acknowledge sent command with result",
+			thingID: thingID,
+			reply:   commands.Reply{ID: acknowledged.ID, Status: commands.StatusAcknowledged, Result: result},
+			status:  commands.StatusAcknowledged,
+			result:  result,
+			err:     nil,
+		},
+		{
+			desc:    "fail sent command",
+			thingID: thingID,
+			reply:   commands.Reply{ID: failed.ID, Status: commands.StatusFailed, Error: "device busy"},
+			status:  commands.StatusFailed,
+			errMsg:  "device busy",
+			err:     nil,
+		},
+		{
+			desc:    "fail acknowledged command",
+			thingID: thingID,
+			reply:   commands.Reply{ID: final.ID, Status: commands.StatusFailed},
+			status:  commands.StatusAcknowledged,
+			err:     commands.ErrStatusTransition,
+		},
+		{
+			desc:    "reply with invalid status",
+			thingID: thingID,
+			reply:   commands.Reply{ID: final.ID, Status: commands.StatusExpired},
+			status:  commands.StatusAcknowledged,
+			err:     commands.ErrMalformedEntity,
+		},
+		{
+			desc:    "reply to command of other thing",
+			thingID: otherID,
+			reply:   commands.Reply{ID: final.ID, Status: commands.StatusAcknowledged},
+			status:  commands.StatusAcknowledged,
+			err:     commands.ErrNotFound,
+		},
+		{
+			desc:    "reply to non-existing command",
+			thingID: thingID,
+			reply:   commands.Reply{ID: wrongValue, Status: commands.StatusAcknowledged},
+			err:     commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := e.svc.Reply(context.Background(), tc.thingID, tc.reply)
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.status == "" {
+			continue
+		}
+
+		cmd, err := e.svc.ViewCommand(context.Background(), token, tc.reply.ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.status, cmd.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, tc.status, cmd.Status))
+		if tc.err == nil {
+			assert.Equal(t, tc.result, cmd.Result, fmt.Sprintf("%s: expected result %v got %v", tc.desc, tc.result, cmd.Result))
+			assert.Equal(t, tc.errMsg, cmd.Error, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.errMsg, cmd.Error))
+		}
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	thingID, _ := createThing(t, e.things, 1)
+	var queued []string
+	for i := 0; i < 3; i++ {
+		cmd, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name}, 0)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		queued = append(queued, cmd.ID)
+	}
+	stale, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name}, time.Nanosecond)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	time.Sleep(time.Millisecond)
+
+	err = e.svc.Dispatch(context.Background(), thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var published []string
+	for _, cmd := range e.publisher.Commands() {
+		published = append(published, cmd.ID)
+	}
+	assert.Equal(t, queued, published, fmt.Sprintf("dispatch queued commands: expected %v got %v", queued, published))
+
+	for _, id := range queued {
+		cmd, err := e.svc.ViewCommand(context.Background(), token, id)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, commands.StatusSent, cmd.Status, fmt.Sprintf("dispatch queued commands: expected status %s got %s", commands.StatusSent, cmd.Status))
+	}
+
+	cmd, err := e.svc.ViewCommand(context.Background(), token, stale.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, commands.StatusExpired, cmd.Status, fmt.Sprintf("dispatch stale command: expected status %s got %s", commands.StatusExpired, cmd.Status))
+
+	err = e.svc.Dispatch(context.Background(), thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, e.publisher.Commands(), "dispatch without queued commands: expected no published commands")
+}
+
+func TestExpire(t *testing.T) {
+	e := newEnv()
+	defer e.close()
+
+	thingID, _ := createThing(t, e.things, 1)
+	e.presence.Set(thingID, true)
+
+	send := func(ttl time.Duration) commands.Command {
+		cmd, err := e.svc.SendCommand(context.Background(), token, commands.Command{ThingID: thingID, Name: name}, ttl)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		return cmd
+	}
+	sent := send(time.Second)
+	delivered := send(time.Second)
+	acknowledged := send(time.Second)
+	fresh := send(time.Hour)
+	err := e.svc.Reply(context.Background(), thingID, commands.Reply{ID: delivered.ID, Status: commands.StatusDelivered})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = e.svc.Reply(context.Background(), thingID, commands.Reply{ID: acknowledged.ID, Status: commands.StatusAcknowledged})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = e.svc.Expire(context.Background(), time.Now().Add(time.Minute))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]string{
+		sent.ID:         commands.StatusExpired,
+		delivered.ID:    commands.StatusExpired,
+		acknowledged.ID: commands.StatusAcknowledged,
+		fresh.ID:        commands.StatusSent,
+	}
+	for id, status := range cases {
+		cmd, err := e.svc.ViewCommand(context.Background(), token, id)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, status, cmd.Status, fmt.Sprintf("expire command %s: expected status %s got %s", id, status, cmd.Status))
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/cloustone/pandas/kuiper"
 	"github.com/go-kit/kit/endpoint"
@@ -239,24 +240,69 @@ func deleteRuleEndpoint(svc kuiper.Service) endpoint.Endpoint {
 
 func viewRuleStatusEndpoint(svc kuiper.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(viewResourceReq)
+		req := request.(ruleStatusReq)
 
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
 
-		r, err := svc.ViewRule(ctx, req.token, req.id)
+		s, err := svc.ViewRuleStatus(ctx, req.token, req.id)
 		if err != nil {
 			return nil, err
 		}
 
-		res := ruleRes{
-			ID:   r.ID,
-			Name: r.Name,
-			//Json:     r.Json,
-			Metadata: r.Metadata,
+		return ruleStatusRes{s}, nil
+	}
+}
+
+func viewRuleTopoEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(ruleStatusReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
 		}
-		return res, nil
+
+		t, err := svc.ViewRuleTopo(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleTopoRes{t}, nil
+	}
+}
+
+func explainRuleEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(explainRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.ExplainRule(ctx, req.token, req.SQL)
+		if err != nil {
+			return nil, err
+		}
+
+		return rulePlanRes{p}, nil
+	}
+}
+
+func testRuleEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(testRuleReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		r, err := svc.TestRule(ctx, req.token, req.RuleTest)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleTestRes{r}, nil
 	}
 }
 
@@ -282,26 +328,288 @@ func startRuleEndpoint(svc kuiper.Service) endpoint.Endpoint {
 }
 
 // Plugins
-func listPluginSourcesEndpoint(svc kuiper.Service) endpoint.Endpoint {
+func installPluginEndpoint(svc kuiper.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return nil, nil
+		req := request.(installPluginReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p := kuiper.Plugin{
+			Name:       req.Name,
+			Type:       req.pluginType,
+			File:       req.File,
+			ShellParas: req.ShellParas,
+		}
+		saved, err := svc.InstallPlugin(ctx, req.token, p)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toPluginRes(saved)
+		res.created = true
+		return res, nil
 	}
 }
 
-func viewPluginSourceEndpoint(svc kuiper.Service) endpoint.Endpoint {
+func uploadPluginEndpoint(svc kuiper.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return nil, nil
+		req := request.(uploadPluginReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p := kuiper.Plugin{
+			Name:       req.name,
+			Type:       req.pluginType,
+			File:       req.file,
+			ShellParas: req.shellParas,
+		}
+		saved, err := svc.UploadPlugin(ctx, req.token, p, req.data)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toPluginRes(saved)
+		res.created = true
+		return res, nil
 	}
 }
 
-func listPluginSinksEndpoint(svc kuiper.Service) endpoint.Endpoint {
+func removePluginEndpoint(svc kuiper.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return nil, nil
+		req := request.(pluginReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemovePlugin(ctx, req.token, req.pluginType, req.name); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
 	}
 }
 
-func viewPluginSinkEndpoint(svc kuiper.Service) endpoint.Endpoint {
+func viewPluginEndpoint(svc kuiper.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return nil, nil
+		req := request.(pluginReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.ViewPlugin(ctx, req.token, req.pluginType, req.name, req.language)
+		if err != nil {
+			return nil, err
+		}
+
+		return toPluginRes(p), nil
+	}
+}
+
+func listPluginsEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listPluginsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListPlugins(ctx, req.token, req.pluginType, req.offset, req.limit, req.name, req.language)
+		if err != nil {
+			return nil, err
+		}
+
+		res := pluginsPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Plugins: []pluginRes{},
+		}
+		for _, p := range page.Plugins {
+			res.Plugins = append(res.Plugins, toPluginRes(p))
+		}
+		return res, nil
+	}
+}
+
+func toPluginRes(p kuiper.Plugin) pluginRes {
+	res := pluginRes{
+		Name:    p.Name,
+		Type:    string(p.Type),
+		Version: p.Version,
+		BuiltIn: p.BuiltIn,
+	}
+	if p.Json != "" {
+		res.Metadata = json.RawMessage(p.Json)
+	}
+	return res
+}
+
+func createFunctionEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createFunctionReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		f, err := svc.CreateFunction(ctx, req.token, req.SQL)
+		if err != nil {
+			return nil, err
+		}
+
+		return functionRes{Name: f.Name, SQL: f.SQL, created: true}, nil
+	}
+}
+
+func viewFunctionEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		f, err := svc.ViewFunction(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return functionRes{Name: f.Name, SQL: f.SQL}, nil
+	}
+}
+
+func listFunctionsEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listOwnedReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		funcs, err := svc.ListFunctions(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := functionsRes{Functions: []functionRes{}}
+		for _, f := range funcs {
+			res.Functions = append(res.Functions, functionRes{Name: f.Name, SQL: f.SQL})
+		}
+		return res, nil
+	}
+}
+
+func removeFunctionEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveFunction(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func createRuleTemplateEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRuleTemplateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		t, err := svc.CreateRuleTemplate(ctx, req.token, kuiper.RuleTemplate{Name: req.Name, Json: req.Json})
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleTemplateRes{Name: t.Name, Json: t.Json, created: true}, nil
+	}
+}
+
+func viewRuleTemplateEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		t, err := svc.ViewRuleTemplate(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return ruleTemplateRes{Name: t.Name, Json: t.Json}, nil
+	}
+}
+
+func listRuleTemplatesEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listOwnedReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		templates, err := svc.ListRuleTemplates(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := ruleTemplatesRes{Templates: []ruleTemplateRes{}}
+		for _, t := range templates {
+			res.Templates = append(res.Templates, ruleTemplateRes{Name: t.Name, Json: t.Json})
+		}
+		return res, nil
+	}
+}
+
+func removeRuleTemplateEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRuleTemplate(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+		return removeRes{}, nil
+	}
+}
+
+func instantiateRuleTemplateEndpoint(svc kuiper.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(instantiateRuleTemplateReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		r, err := svc.InstantiateRuleTemplate(ctx, req.token, req.template, req.Name, req.Params)
+		if err != nil {
+			return nil, err
+		}
+
+		res := ruleRes{
+			ID:       r.ID,
+			Name:     r.Name,
+			SQL:      r.SQL,
+			Metadata: r.Metadata,
+		}
+		return templateRuleRes{res}, nil
 	}
 }
@@ -0,0 +1,197 @@
+package http_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper"
+	httpapi "github.com/cloustone/pandas/kuiper/api/http"
+	"github.com/cloustone/pandas/kuiper/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType = "application/json"
+	token       = "token"
+	wrongValue  = "wrong_value"
+	ruleID      = "1"
+)
+
+var (
+	status = kuiper.RuleStatus{
+		ID:    ruleID,
+		Name:  "rule1",
+		State: "Running",
+		Metrics: []kuiper.NodeMetrics{
+			{
+				Type:             "source",
+				Name:             "demo",
+				Instance:         0,
+				RecordsInTotal:   5,
+				RecordsOutTotal:  5,
+				ProcessLatencyMs: 1,
+				LastInvocation:   "2020-06-01T10:00:00.123456",
+			},
+			{
+				Type:             "op",
+				Name:             "window",
+				Instance:         0,
+				RecordsInTotal:   5,
+				RecordsOutTotal:  1,
+				ExceptionsTotal:  1,
+				BufferLength:     2,
+				LateRecordsTotal: 3,
+				LateDroppedTotal: 1,
+			},
+		},
+	}
+	statusJSON = `{
+		"id": "1",
+		"name": "rule1",
+		"state": "Running",
+		"metrics": [
+			{
+				"type": "source",
+				"name": "demo",
+				"instance": 0,
+				"records_in_total": 5,
+				"records_out_total": 5,
+				"exceptions_total": 0,
+				"process_latency_ms": 1,
+				"buffer_length": 0,
+				"last_invocation": "2020-06-01T10:00:00.123456"
+			},
+			{
+				"type": "op",
+				"name": "window",
+				"instance": 0,
+				"records_in_total": 5,
+				"records_out_total": 1,
+				"exceptions_total": 1,
+				"process_latency_ms": 0,
+				"buffer_length": 2,
+				"late_records_total": 3,
+				"late_dropped_total": 1
+			}
+		]
+	}`
+	topo = kuiper.RuleTopo{
+		Sources: []string{"source_demo"},
+		Edges: map[string][]string{
+			"source_demo":          {"op_preprocessor_demo"},
+			"op_preprocessor_demo": {"op_project"},
+			"op_project":           {"sink_log_0"},
+		},
+	}
+	topoJSON = `{
+		"sources": ["source_demo"],
+		"edges": {
+			"source_demo": ["op_preprocessor_demo"],
+			"op_preprocessor_demo": ["op_project"],
+			"op_project": ["sink_log_0"]
+		}
+	}`
+)
+
+func newServer() *httptest.Server {
+	svc := mocks.NewRuleStatusService(token,
+		map[string]kuiper.RuleStatus{ruleID: status},
+		map[string]kuiper.RuleTopo{ruleID: topo})
+	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	return httptest.NewServer(mux)
+}
+
+func get(client *http.Client, url, contentType, token string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return client.Do(req)
+}
+
+func TestViewRuleStatusAndTopo(t *testing.T) {
+	ts := newServer()
+	defer ts.Close()
+
+	cases := []struct {
+		desc        string
+		url         string
+		contentType string
+		auth        string
+		status      int
+		res         string
+	}{
+		{
+			desc:        "view status of existing rule",
+			url:         fmt.Sprintf("%s/rules/%s/status", ts.URL, ruleID),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res:         statusJSON,
+		},
+		{
+			desc:        "view status of non-existing rule",
+			url:         fmt.Sprintf("%s/rules/%s/status", ts.URL, wrongValue),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "view status of rule with invalid token",
+			url:         fmt.Sprintf("%s/rules/%s/status", ts.URL, ruleID),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "view status of rule without content type",
+			url:         fmt.Sprintf("%s/rules/%s/status", ts.URL, ruleID),
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "view topo of existing rule",
+			url:         fmt.Sprintf("%s/rules/%s/topo", ts.URL, ruleID),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+			res:         topoJSON,
+		},
+		{
+			desc:        "view topo of non-existing rule",
+			url:         fmt.Sprintf("%s/rules/%s/topo", ts.URL, wrongValue),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "view topo of rule with invalid token",
+			url:         fmt.Sprintf("%s/rules/%s/topo", ts.URL, ruleID),
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := get(ts.Client(), tc.url, tc.contentType, tc.auth)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.res != "" {
+			assert.JSONEq(t, tc.res, string(body), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+		}
+	}
+}
@@ -142,6 +142,40 @@ func (req updateRuleReq) validate() error {
 	return nil
 }
 
+type explainRuleReq struct {
+	token string
+	SQL   string `json:"sql"`
+}
+
+func (req explainRuleReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if req.SQL == "" {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type testRuleReq struct {
+	token string
+	kuiper.RuleTest
+}
+
+func (req testRuleReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if req.SQL == "" || len(req.Samples) == 0 {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type viewResourceReq struct {
 	token string
 	id    string
@@ -230,3 +264,174 @@ func (req ruleControlReq) validate() error {
 
 	return nil
 }
+
+func validPluginType(t kuiper.PluginType) bool {
+	switch t {
+	case kuiper.PluginSource, kuiper.PluginSink, kuiper.PluginFunction:
+		return true
+	}
+	return false
+}
+
+type installPluginReq struct {
+	token      string
+	pluginType kuiper.PluginType
+	Name       string   `json:"name"`
+	File       string   `json:"file"`
+	ShellParas []string `json:"shellParas,omitempty"`
+}
+
+func (req installPluginReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if !validPluginType(req.pluginType) || req.File == "" {
+		return kuiper.ErrMalformedEntity
+	}
+
+	if req.Name == "" || len(req.Name) > maxNameSize {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type uploadPluginReq struct {
+	token      string
+	pluginType kuiper.PluginType
+	name       string
+	file       string
+	shellParas []string
+	data       []byte
+}
+
+func (req uploadPluginReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if !validPluginType(req.pluginType) || len(req.data) == 0 {
+		return kuiper.ErrMalformedEntity
+	}
+
+	if req.name == "" || len(req.name) > maxNameSize {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type pluginReq struct {
+	token      string
+	pluginType kuiper.PluginType
+	name       string
+	language   string
+}
+
+func (req pluginReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if !validPluginType(req.pluginType) || req.name == "" {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listPluginsReq struct {
+	token      string
+	pluginType kuiper.PluginType
+	offset     uint64
+	limit      uint64
+	name       string
+	language   string
+}
+
+func (req listPluginsReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if !validPluginType(req.pluginType) {
+		return kuiper.ErrMalformedEntity
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return kuiper.ErrMalformedEntity
+	}
+
+	if len(req.name) > maxNameSize {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type createFunctionReq struct {
+	token string
+	SQL   string `json:"sql"`
+}
+
+func (req createFunctionReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if req.SQL == "" {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listOwnedReq struct {
+	token string
+}
+
+func (req listOwnedReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type createRuleTemplateReq struct {
+	token string
+	Name  string `json:"name"`
+	Json  string `json:"json"`
+}
+
+func (req createRuleTemplateReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" || len(req.Name) > maxNameSize || req.Json == "" {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type instantiateRuleTemplateReq struct {
+	token    string
+	template string
+	Name     string                 `json:"name"`
+	Params   map[string]interface{} `json:"params"`
+}
+
+func (req instantiateRuleTemplateReq) validate() error {
+	if req.token == "" {
+		return kuiper.ErrUnauthorizedAccess
+	}
+
+	if req.template == "" || req.Name == "" || len(req.Name) > maxNameSize {
+		return kuiper.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -1,9 +1,11 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
+	"github.com/cloustone/pandas/kuiper"
 	"github.com/cloustone/pandas/mainflux"
 )
 
@@ -15,6 +17,17 @@ var (
 	_ mainflux.Response = (*ruleRes)(nil)
 	_ mainflux.Response = (*rulesPageRes)(nil)
 	_ mainflux.Response = (*ruleControlRes)(nil)
+	_ mainflux.Response = (*ruleStatusRes)(nil)
+	_ mainflux.Response = (*ruleTopoRes)(nil)
+	_ mainflux.Response = (*rulePlanRes)(nil)
+	_ mainflux.Response = (*ruleTestRes)(nil)
+	_ mainflux.Response = (*pluginRes)(nil)
+	_ mainflux.Response = (*pluginsPageRes)(nil)
+	_ mainflux.Response = (*functionRes)(nil)
+	_ mainflux.Response = (*functionsRes)(nil)
+	_ mainflux.Response = (*ruleTemplateRes)(nil)
+	_ mainflux.Response = (*ruleTemplatesRes)(nil)
+	_ mainflux.Response = (*templateRuleRes)(nil)
 )
 
 type removeRes struct{}
@@ -189,6 +202,70 @@ func (res rulesPageRes) Empty() bool {
 	return false
 }
 
+type ruleStatusRes struct {
+	kuiper.RuleStatus
+}
+
+func (res ruleStatusRes) Code() int {
+	return http.StatusOK
+}
+
+func (res ruleStatusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ruleStatusRes) Empty() bool {
+	return false
+}
+
+type ruleTopoRes struct {
+	kuiper.RuleTopo
+}
+
+func (res ruleTopoRes) Code() int {
+	return http.StatusOK
+}
+
+func (res ruleTopoRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ruleTopoRes) Empty() bool {
+	return false
+}
+
+type rulePlanRes struct {
+	kuiper.RulePlan
+}
+
+func (res rulePlanRes) Code() int {
+	return http.StatusOK
+}
+
+func (res rulePlanRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res rulePlanRes) Empty() bool {
+	return false
+}
+
+type ruleTestRes struct {
+	kuiper.RuleTestResult
+}
+
+func (res ruleTestRes) Code() int {
+	return http.StatusOK
+}
+
+func (res ruleTestRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ruleTestRes) Empty() bool {
+	return false
+}
+
 type connectionRes struct{}
 
 func (res connectionRes) Code() int {
@@ -238,3 +315,157 @@ type pageRes struct {
 	Offset uint64 `json:"offset"`
 	Limit  uint64 `json:"limit"`
 }
+
+type pluginRes struct {
+	Name     string          `json:"name"`
+	Type     string          `json:"type"`
+	Version  string          `json:"version,omitempty"`
+	BuiltIn  bool            `json:"built_in"`
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+	created  bool
+}
+
+func (res pluginRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res pluginRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/plugins/%s/%s", res.Type, res.Name),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res pluginRes) Empty() bool {
+	return false
+}
+
+type pluginsPageRes struct {
+	pageRes
+	Plugins []pluginRes `json:"plugins"`
+}
+
+func (res pluginsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res pluginsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res pluginsPageRes) Empty() bool {
+	return false
+}
+
+type functionRes struct {
+	Name    string `json:"name"`
+	SQL     string `json:"sql"`
+	created bool
+}
+
+func (res functionRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res functionRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/functions/%s", res.Name),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res functionRes) Empty() bool {
+	return false
+}
+
+type functionsRes struct {
+	Functions []functionRes `json:"functions"`
+}
+
+func (res functionsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res functionsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res functionsRes) Empty() bool {
+	return false
+}
+
+type ruleTemplateRes struct {
+	Name    string `json:"name"`
+	Json    string `json:"json"`
+	created bool
+}
+
+func (res ruleTemplateRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res ruleTemplateRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/templates/%s", res.Name),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res ruleTemplateRes) Empty() bool {
+	return false
+}
+
+type ruleTemplatesRes struct {
+	Templates []ruleTemplateRes `json:"templates"`
+}
+
+func (res ruleTemplatesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res ruleTemplatesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ruleTemplatesRes) Empty() bool {
+	return false
+}
+
+type templateRuleRes struct {
+	ruleRes
+}
+
+func (res templateRuleRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res templateRuleRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/rules/%s", res.ID),
+	}
+}
+
+func (res templateRuleRes) Empty() bool {
+	return false
+}
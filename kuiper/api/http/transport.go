@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"strconv"
 	"strings"
@@ -27,8 +28,13 @@ const (
 	limit       = "limit"
 	name        = "name"
 	metadata    = "metadata"
+	language    = "language"
 	defOffset   = 0
 	defLimit    = 10
+	defLanguage = "en_US"
+
+	multipartContentType = "multipart/form-data"
+	maxPluginSize        = 64 << 20
 )
 
 var (
@@ -106,28 +112,49 @@ func MakeHandler(tracer opentracing.Tracer, svc kuiper.Service) http.Handler {
 		opts...,
 	))
 
-	r.Get("/rules/:name", kithttp.NewServer(
+	r.Post("/rules/explain", kithttp.NewServer(
+		kitot.TraceServer(tracer, "explain_rule")(explainRuleEndpoint(svc)),
+		decodeRuleExplain,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/rules/test", kithttp.NewServer(
+		kitot.TraceServer(tracer, "test_rule")(testRuleEndpoint(svc)),
+		decodeRuleTest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/rules/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "get_rule")(viewRuleEndpoint(svc)),
 		decodeRuleView,
 		encodeResponse,
 		opts...,
 	))
 
-	r.Get("/rules/:name", kithttp.NewServer(
+	r.Delete("/rules/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "delete_rule")(deleteRuleEndpoint(svc)),
 		decodeRuleDeletion,
 		encodeResponse,
 		opts...,
 	))
 
-	r.Get("/rules/:name/status", kithttp.NewServer(
+	r.Get("/rules/:id/status", kithttp.NewServer(
 		kitot.TraceServer(tracer, "rule_status")(viewRuleStatusEndpoint(svc)),
 		decodeRuleStatus,
 		encodeResponse,
 		opts...,
 	))
 
-	r.Post("/rules/:name/:action", kithttp.NewServer(
+	r.Get("/rules/:id/topo", kithttp.NewServer(
+		kitot.TraceServer(tracer, "rule_topo")(viewRuleTopoEndpoint(svc)),
+		decodeRuleStatus,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/rules/:id/:action", kithttp.NewServer(
 		kitot.TraceServer(tracer, "rule_start")(startRuleEndpoint(svc)),
 		decodeRuleControl,
 		encodeResponse,
@@ -135,30 +162,102 @@ func MakeHandler(tracer opentracing.Tracer, svc kuiper.Service) http.Handler {
 	))
 
 	// Plugins
-	r.Get("/plugins/sources", kithttp.NewServer(
-		kitot.TraceServer(tracer, "plugins_source_list")(listPluginSourcesEndpoint(svc)),
-		decodePluginSourcesListing,
+	r.Get("/plugins/:type", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_plugins")(listPluginsEndpoint(svc)),
+		decodePluginListing,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/plugins/:type", kithttp.NewServer(
+		kitot.TraceServer(tracer, "install_plugin")(installPluginEndpoint(svc)),
+		decodePluginInstall,
 		encodeResponse,
 		opts...,
 	))
 
-	r.Get("/plugins/sources/:name", kithttp.NewServer(
-		kitot.TraceServer(tracer, "plugins_source_view")(viewPluginSourceEndpoint(svc)),
-		decodePluginSourceView,
+	r.Post("/plugins/:type/upload", kithttp.NewServer(
+		kitot.TraceServer(tracer, "upload_plugin")(uploadPluginEndpoint(svc)),
+		decodePluginUpload,
 		encodeResponse,
 		opts...,
 	))
 
-	r.Get("/plugins/sinks", kithttp.NewServer(
-		kitot.TraceServer(tracer, "plugins_sinks_list")(listPluginSinksEndpoint(svc)),
-		decodePluginSourcesListing,
+	r.Get("/plugins/:type/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_plugin")(viewPluginEndpoint(svc)),
+		decodePluginView,
 		encodeResponse,
 		opts...,
 	))
 
-	r.Get("/plugins/sinks/:name", kithttp.NewServer(
-		kitot.TraceServer(tracer, "plugins_sinks_view")(viewPluginSinkEndpoint(svc)),
-		decodePluginSourceView,
+	r.Delete("/plugins/:type/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_plugin")(removePluginEndpoint(svc)),
+		decodePluginView,
+		encodeResponse,
+		opts...,
+	))
+
+	// Functions
+	r.Post("/functions", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_function")(createFunctionEndpoint(svc)),
+		decodeFunctionCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/functions", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_functions")(listFunctionsEndpoint(svc)),
+		decodeOwnedListing,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/functions/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_function")(viewFunctionEndpoint(svc)),
+		decodeNamedView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/functions/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_function")(removeFunctionEndpoint(svc)),
+		decodeNamedView,
+		encodeResponse,
+		opts...,
+	))
+
+	// Rule templates
+	r.Post("/templates", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_rule_template")(createRuleTemplateEndpoint(svc)),
+		decodeRuleTemplateCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/templates", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_rule_templates")(listRuleTemplatesEndpoint(svc)),
+		decodeOwnedListing,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/templates/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_rule_template")(viewRuleTemplateEndpoint(svc)),
+		decodeNamedView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/templates/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_rule_template")(removeRuleTemplateEndpoint(svc)),
+		decodeNamedView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/templates/:name/rules", kithttp.NewServer(
+		kitot.TraceServer(tracer, "instantiate_rule_template")(instantiateRuleTemplateEndpoint(svc)),
+		decodeRuleTemplateInstantiation,
 		encodeResponse,
 		opts...,
 	))
@@ -364,6 +463,28 @@ func decodeRuleCreation(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
+func decodeRuleExplain(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := explainRuleReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func decodeRuleTest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := testRuleReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req.RuleTest); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
 func decodeRuleView(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
@@ -390,7 +511,7 @@ func decodeRuleStatus(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
-	req := viewResourceReq{
+	req := ruleStatusReq{
 		token: r.Header.Get("Authorization"),
 		id:    bone.GetValue(r, "id"),
 	}
@@ -411,7 +532,7 @@ func decodeRuleControl(_ context.Context, r *http.Request) (interface{}, error)
 }
 
 // Plugin
-func decodePluginSourcesListing(_ context.Context, r *http.Request) (interface{}, error) {
+func decodePluginListing(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
@@ -430,73 +551,155 @@ func decodePluginSourcesListing(_ context.Context, r *http.Request) (interface{}
 		return nil, err
 	}
 
-	m, err := readMetadataQuery(r, "metadata")
+	lang, err := readLanguageQuery(r)
 	if err != nil {
 		return nil, err
 	}
 
-	req := listResourcesReq{
-		token:    r.Header.Get("Authorization"),
-		offset:   o,
-		limit:    l,
-		name:     n,
-		metadata: m,
+	req := listPluginsReq{
+		token:      r.Header.Get("Authorization"),
+		pluginType: kuiper.PluginType(bone.GetValue(r, "type")),
+		offset:     o,
+		limit:      l,
+		name:       n,
+		language:   lang,
 	}
 	return req, nil
 }
 
-func decodePluginSourceView(_ context.Context, r *http.Request) (interface{}, error) {
+func decodePluginInstall(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
-	req := viewResourceReq{
-		token: r.Header.Get("Authorization"),
-		id:    bone.GetValue(r, "id"),
+
+	req := installPluginReq{
+		token:      r.Header.Get("Authorization"),
+		pluginType: kuiper.PluginType(bone.GetValue(r, "type")),
 	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
 
-func decodePluginSinksListing(_ context.Context, r *http.Request) (interface{}, error) {
-	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+func decodePluginUpload(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), multipartContentType) {
 		return nil, errUnsupportedContentType
 	}
-	o, err := readUintQuery(r, offset, defOffset)
-	if err != nil {
-		return nil, err
+	if err := r.ParseMultipartForm(maxPluginSize); err != nil {
+		return nil, kuiper.ErrMalformedEntity
 	}
 
-	l, err := readUintQuery(r, limit, defLimit)
+	file, header, err := r.FormFile("file")
 	if err != nil {
-		return nil, err
+		return nil, kuiper.ErrMalformedEntity
 	}
+	defer file.Close()
 
-	n, err := readStringQuery(r, name)
+	data, err := ioutil.ReadAll(file)
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := readMetadataQuery(r, "metadata")
+	// Plugin is named after the zip file if no name is specified
+	n := r.FormValue("name")
+	if n == "" {
+		n = strings.TrimSuffix(header.Filename, ".zip")
+	}
+
+	req := uploadPluginReq{
+		token:      r.Header.Get("Authorization"),
+		pluginType: kuiper.PluginType(bone.GetValue(r, "type")),
+		name:       n,
+		file:       header.Filename,
+		shellParas: r.MultipartForm.Value["shellParas"],
+		data:       data,
+	}
+	return req, nil
+}
+
+func decodePluginView(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	lang, err := readLanguageQuery(r)
 	if err != nil {
 		return nil, err
 	}
 
-	req := listResourcesReq{
-		token:    r.Header.Get("Authorization"),
-		offset:   o,
-		limit:    l,
-		name:     n,
-		metadata: m,
+	req := pluginReq{
+		token:      r.Header.Get("Authorization"),
+		pluginType: kuiper.PluginType(bone.GetValue(r, "type")),
+		name:       bone.GetValue(r, "name"),
+		language:   lang,
+	}
+	return req, nil
+}
+
+// Functions and rule templates
+func decodeFunctionCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := createFunctionReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
 	}
 	return req, nil
 }
 
-func decodePluginSinkView(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeOwnedListing(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	return listOwnedReq{token: r.Header.Get("Authorization")}, nil
+}
+
+func decodeNamedView(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errUnsupportedContentType
 	}
 	req := viewResourceReq{
 		token: r.Header.Get("Authorization"),
-		id:    bone.GetValue(r, "id"),
+		id:    bone.GetValue(r, "name"),
+	}
+	return req, nil
+}
+
+func decodeRuleTemplateCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := createRuleTemplateReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
 	}
 	return req, nil
 }
+
+func decodeRuleTemplateInstantiation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := instantiateRuleTemplateReq{
+		token:    r.Header.Get("Authorization"),
+		template: bone.GetValue(r, "name"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func readLanguageQuery(r *http.Request) (string, error) {
+	lang, err := readStringQuery(r, language)
+	if err != nil {
+		return "", err
+	}
+	if lang == "" {
+		return defLanguage, nil
+	}
+	return lang, nil
+}
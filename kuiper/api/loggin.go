@@ -200,9 +200,9 @@ func (lm *loggingMiddleware) RestartRule(ctx context.Context, token string, rule
 	return lm.svc.StartRule(ctx, token, ruleId)
 }
 
-func (lm *loggingMiddleware) ViewPluginSource(ctx context.Context, token, id string) (source kuiper.PluginSource, err error) {
+func (lm *loggingMiddleware) ViewRuleStatus(ctx context.Context, token, id string) (status kuiper.RuleStatus, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method view_plugin_source for token %s and plugin source %s took %s to complete", token, id, time.Since(begin))
+		message := fmt.Sprintf("Method view_rule_status for token %s and rule %s took %s to complete", token, id, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -210,16 +210,51 @@ func (lm *loggingMiddleware) ViewPluginSource(ctx context.Context, token, id str
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ViewPluginSource(ctx, token, id)
+	return lm.svc.ViewRuleStatus(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) ListPluginSources(ctx context.Context, token string, offset, limit uint64, name string, metadata kuiper.Metadata) (_ kuiper.PluginSourcesPage, err error) {
+func (lm *loggingMiddleware) ViewRuleTopo(ctx context.Context, token, id string) (topo kuiper.RuleTopo, err error) {
 	defer func(begin time.Time) {
-		nlog := ""
-		if name != "" {
-			nlog = fmt.Sprintf("with name %s ", name)
+		message := fmt.Sprintf("Method view_rule_topo for token %s and rule %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewRuleTopo(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ExplainRule(ctx context.Context, token, sql string) (plan kuiper.RulePlan, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method explain_rule for token %s and sql %s took %s to complete", token, sql, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExplainRule(ctx, token, sql)
+}
+
+func (lm *loggingMiddleware) TestRule(ctx context.Context, token string, t kuiper.RuleTest) (result kuiper.RuleTestResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method test_rule for token %s and sql %s took %s to complete", token, t.SQL, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
 		}
-		message := fmt.Sprintf("Method list_plugin_sources %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.TestRule(ctx, token, t)
+}
+
+func (lm *loggingMiddleware) InstallPlugin(ctx context.Context, token string, p kuiper.Plugin) (saved kuiper.Plugin, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method install_plugin for token %s and %s plugin %s took %s to complete", token, p.Type, p.Name, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -227,12 +262,12 @@ func (lm *loggingMiddleware) ListPluginSources(ctx context.Context, token string
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListPluginSources(ctx, token, offset, limit, name, metadata)
+	return lm.svc.InstallPlugin(ctx, token, p)
 }
 
-func (lm *loggingMiddleware) ViewPluginSink(ctx context.Context, token, id string) (source kuiper.PluginSink, err error) {
+func (lm *loggingMiddleware) UploadPlugin(ctx context.Context, token string, p kuiper.Plugin, data []byte) (saved kuiper.Plugin, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method view_plugin_sink for token %s and plugin source %s took %s to complete", token, id, time.Since(begin))
+		message := fmt.Sprintf("Method upload_plugin for token %s and %s plugin %s took %s to complete", token, p.Type, p.Name, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -240,16 +275,159 @@ func (lm *loggingMiddleware) ViewPluginSink(ctx context.Context, token, id strin
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ViewPluginSink(ctx, token, id)
+	return lm.svc.UploadPlugin(ctx, token, p, data)
 }
 
-func (lm *loggingMiddleware) ListPluginSinks(ctx context.Context, token string, offset, limit uint64, name string, metadata kuiper.Metadata) (_ kuiper.PluginSinksPage, err error) {
+func (lm *loggingMiddleware) RemovePlugin(ctx context.Context, token string, pluginType kuiper.PluginType, name string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_plugin for token %s and %s plugin %s took %s to complete", token, pluginType, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemovePlugin(ctx, token, pluginType, name)
+}
+
+func (lm *loggingMiddleware) ViewPlugin(ctx context.Context, token string, pluginType kuiper.PluginType, name, language string) (p kuiper.Plugin, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_plugin for token %s and %s plugin %s took %s to complete", token, pluginType, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewPlugin(ctx, token, pluginType, name, language)
+}
+
+func (lm *loggingMiddleware) ListPlugins(ctx context.Context, token string, pluginType kuiper.PluginType, offset, limit uint64, name, language string) (_ kuiper.PluginsPage, err error) {
 	defer func(begin time.Time) {
 		nlog := ""
 		if name != "" {
 			nlog = fmt.Sprintf("with name %s ", name)
 		}
-		message := fmt.Sprintf("Method list_plugin_Sinks %sfor token %s took %s to complete", nlog, token, time.Since(begin))
+		message := fmt.Sprintf("Method list_plugins of %s %sfor token %s took %s to complete", pluginType, nlog, token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListPlugins(ctx, token, pluginType, offset, limit, name, language)
+}
+
+func (lm *loggingMiddleware) CreateFunction(ctx context.Context, token string, sql string) (f kuiper.Function, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_function for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateFunction(ctx, token, sql)
+}
+
+func (lm *loggingMiddleware) ViewFunction(ctx context.Context, token string, name string) (f kuiper.Function, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_function for token %s and function %s took %s to complete", token, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewFunction(ctx, token, name)
+}
+
+func (lm *loggingMiddleware) ListFunctions(ctx context.Context, token string) (_ []kuiper.Function, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_functions for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListFunctions(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveFunction(ctx context.Context, token string, name string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_function for token %s and function %s took %s to complete", token, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveFunction(ctx, token, name)
+}
+
+func (lm *loggingMiddleware) CreateRuleTemplate(ctx context.Context, token string, t kuiper.RuleTemplate) (saved kuiper.RuleTemplate, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_rule_template for token %s and template %s took %s to complete", token, t.Name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateRuleTemplate(ctx, token, t)
+}
+
+func (lm *loggingMiddleware) ViewRuleTemplate(ctx context.Context, token string, name string) (t kuiper.RuleTemplate, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_rule_template for token %s and template %s took %s to complete", token, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewRuleTemplate(ctx, token, name)
+}
+
+func (lm *loggingMiddleware) ListRuleTemplates(ctx context.Context, token string) (_ []kuiper.RuleTemplate, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_rule_templates for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRuleTemplates(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveRuleTemplate(ctx context.Context, token string, name string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_rule_template for token %s and template %s took %s to complete", token, name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveRuleTemplate(ctx, token, name)
+}
+
+func (lm *loggingMiddleware) InstantiateRuleTemplate(ctx context.Context, token string, name, ruleName string, params map[string]interface{}) (r kuiper.Rule, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method instantiate_rule_template for token %s and template %s took %s to complete", token, name, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -257,5 +435,5 @@ func (lm *loggingMiddleware) ListPluginSinks(ctx context.Context, token string,
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListPluginSinks(ctx, token, offset, limit, name, metadata)
+	return lm.svc.InstantiateRuleTemplate(ctx, token, name, ruleName, params)
 }
@@ -127,6 +127,42 @@ func (ms *metricsMiddleware) RestartRule(ctx context.Context, token, id string)
 	return ms.svc.RestartRule(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) ViewRuleStatus(ctx context.Context, token, id string) (kuiper.RuleStatus, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_rule_status").Add(1)
+		ms.latency.With("method", "view_rule_status").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRuleStatus(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ViewRuleTopo(ctx context.Context, token, id string) (kuiper.RuleTopo, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_rule_topo").Add(1)
+		ms.latency.With("method", "view_rule_topo").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewRuleTopo(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ExplainRule(ctx context.Context, token, sql string) (kuiper.RulePlan, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "explain_rule").Add(1)
+		ms.latency.With("method", "explain_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExplainRule(ctx, token, sql)
+}
+
+func (ms *metricsMiddleware) TestRule(ctx context.Context, token string, t kuiper.RuleTest) (kuiper.RuleTestResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "test_rule").Add(1)
+		ms.latency.With("method", "test_rule").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.TestRule(ctx, token, t)
+}
+
 func (ms *metricsMiddleware) ListRules(ctx context.Context, token string, offset, limit uint64, name string, metadata kuiper.Metadata) (kuiper.RulesPage, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "list_rules").Add(1)
@@ -145,38 +181,128 @@ func (ms *metricsMiddleware) RemoveRule(ctx context.Context, token, id string) e
 	return ms.svc.RemoveRule(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) ViewPluginSource(ctx context.Context, token, id string) (kuiper.PluginSource, error) {
+func (ms *metricsMiddleware) InstallPlugin(ctx context.Context, token string, p kuiper.Plugin) (kuiper.Plugin, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "install_plugin").Add(1)
+		ms.latency.With("method", "install_plugin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.InstallPlugin(ctx, token, p)
+}
+
+func (ms *metricsMiddleware) UploadPlugin(ctx context.Context, token string, p kuiper.Plugin, data []byte) (kuiper.Plugin, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "upload_plugin").Add(1)
+		ms.latency.With("method", "upload_plugin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UploadPlugin(ctx, token, p, data)
+}
+
+func (ms *metricsMiddleware) RemovePlugin(ctx context.Context, token string, pluginType kuiper.PluginType, name string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_plugin").Add(1)
+		ms.latency.With("method", "remove_plugin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemovePlugin(ctx, token, pluginType, name)
+}
+
+func (ms *metricsMiddleware) ViewPlugin(ctx context.Context, token string, pluginType kuiper.PluginType, name, language string) (kuiper.Plugin, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_plugin").Add(1)
+		ms.latency.With("method", "view_plugin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewPlugin(ctx, token, pluginType, name, language)
+}
+
+func (ms *metricsMiddleware) ListPlugins(ctx context.Context, token string, pluginType kuiper.PluginType, offset, limit uint64, name, language string) (kuiper.PluginsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_plugins").Add(1)
+		ms.latency.With("method", "list_plugins").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListPlugins(ctx, token, pluginType, offset, limit, name, language)
+}
+
+func (ms *metricsMiddleware) CreateFunction(ctx context.Context, token string, sql string) (kuiper.Function, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_function").Add(1)
+		ms.latency.With("method", "create_function").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateFunction(ctx, token, sql)
+}
+
+func (ms *metricsMiddleware) ViewFunction(ctx context.Context, token string, name string) (kuiper.Function, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_function").Add(1)
+		ms.latency.With("method", "view_function").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewFunction(ctx, token, name)
+}
+
+func (ms *metricsMiddleware) ListFunctions(ctx context.Context, token string) ([]kuiper.Function, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_functions").Add(1)
+		ms.latency.With("method", "list_functions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListFunctions(ctx, token)
+}
+
+func (ms *metricsMiddleware) RemoveFunction(ctx context.Context, token string, name string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_function").Add(1)
+		ms.latency.With("method", "remove_function").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveFunction(ctx, token, name)
+}
+
+func (ms *metricsMiddleware) CreateRuleTemplate(ctx context.Context, token string, t kuiper.RuleTemplate) (kuiper.RuleTemplate, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_rule_template").Add(1)
+		ms.latency.With("method", "create_rule_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateRuleTemplate(ctx, token, t)
+}
+
+func (ms *metricsMiddleware) ViewRuleTemplate(ctx context.Context, token string, name string) (kuiper.RuleTemplate, error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "view_plugin_source").Add(1)
-		ms.latency.With("method", "view_plugin_source").Observe(time.Since(begin).Seconds())
+		ms.counter.With("method", "view_rule_template").Add(1)
+		ms.latency.With("method", "view_rule_template").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ViewPluginSource(ctx, token, id)
+	return ms.svc.ViewRuleTemplate(ctx, token, name)
 }
 
-func (ms *metricsMiddleware) ListPluginSources(ctx context.Context, token string, offset, limit uint64, name string, metadata kuiper.Metadata) (kuiper.PluginSourcesPage, error) {
+func (ms *metricsMiddleware) ListRuleTemplates(ctx context.Context, token string) ([]kuiper.RuleTemplate, error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "list_plugin_sources").Add(1)
-		ms.latency.With("method", "list_plugin_sources").Observe(time.Since(begin).Seconds())
+		ms.counter.With("method", "list_rule_templates").Add(1)
+		ms.latency.With("method", "list_rule_templates").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListPluginSources(ctx, token, offset, limit, name, metadata)
+	return ms.svc.ListRuleTemplates(ctx, token)
 }
 
-func (ms *metricsMiddleware) ViewPluginSink(ctx context.Context, token, id string) (kuiper.PluginSink, error) {
+func (ms *metricsMiddleware) RemoveRuleTemplate(ctx context.Context, token string, name string) error {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "view_plugin_sink").Add(1)
-		ms.latency.With("method", "view_plugin_sink").Observe(time.Since(begin).Seconds())
+		ms.counter.With("method", "remove_rule_template").Add(1)
+		ms.latency.With("method", "remove_rule_template").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ViewPluginSink(ctx, token, id)
+	return ms.svc.RemoveRuleTemplate(ctx, token, name)
 }
 
-func (ms *metricsMiddleware) ListPluginSinks(ctx context.Context, token string, offset, limit uint64, name string, metadata kuiper.Metadata) (kuiper.PluginSinksPage, error) {
+func (ms *metricsMiddleware) InstantiateRuleTemplate(ctx context.Context, token string, name, ruleName string, params map[string]interface{}) (kuiper.Rule, error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "list_plugin_sources").Add(1)
-		ms.latency.With("method", "list_plugin_sources").Observe(time.Since(begin).Seconds())
+		ms.counter.With("method", "instantiate_rule_template").Add(1)
+		ms.latency.With("method", "instantiate_rule_template").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListPluginSinks(ctx, token, offset, limit, name, metadata)
+	return ms.svc.InstantiateRuleTemplate(ctx, token, name, ruleName, params)
 }
@@ -0,0 +1,57 @@
+package kuiper
+
+import "context"
+
+// Function is a user defined SQL function created by the CREATE FUNCTION
+// statement. Functions are usable in the rules of all users, so their names
+// are unique among all users, but only the owner can view or remove them.
+type Function struct {
+	Name  string
+	Owner string
+	SQL   string
+}
+
+// FunctionRepository specifies the persistence API of user defined
+// functions.
+type FunctionRepository interface {
+	// Save persists the function.
+	Save(context.Context, Function) error
+
+	// RetrieveByName retrieves the function having the provided name.
+	RetrieveByName(context.Context, string) (Function, error)
+
+	// RetrieveAll retrieves the functions owned by the specified user, the
+	// functions of all users are retrieved if the user is empty.
+	RetrieveAll(context.Context, string) ([]Function, error)
+
+	// Remove removes the function having the provided name, that is owned
+	// by the specified user.
+	Remove(context.Context, string, string) error
+}
+
+// RuleTemplate is a parameterized rule definition. The Json is a Go text
+// template of the rule json, the rules are instantiated from it with the
+// parameters as the template data.
+type RuleTemplate struct {
+	Name  string
+	Owner string
+	Json  string
+}
+
+// RuleTemplateRepository specifies the persistence API of rule templates.
+// Template names are unique per user.
+type RuleTemplateRepository interface {
+	// Save persists the rule template.
+	Save(context.Context, RuleTemplate) error
+
+	// RetrieveByName retrieves the rule template having the provided name,
+	// that is owned by the specified user.
+	RetrieveByName(context.Context, string, string) (RuleTemplate, error)
+
+	// RetrieveAll retrieves the rule templates owned by the specified user.
+	RetrieveAll(context.Context, string) ([]RuleTemplate, error)
+
+	// Remove removes the rule template having the provided name, that is
+	// owned by the specified user.
+	Remove(context.Context, string, string) error
+}
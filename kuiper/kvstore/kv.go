@@ -11,7 +11,19 @@ type KvStore interface {
 	Clean() error
 }
 
-func GetKvStore(path string) KvStore {
-	// return default kvstore now
+// StoreFactory creates the KvStore which saves the data of the path
+type StoreFactory func(path string) KvStore
+
+var factory StoreFactory = func(path string) KvStore {
 	return simpleStore.Load(path)
 }
+
+// SetStoreFactory replaces the default file store, so that the streams, sink
+// caches and checkpoints can be shared by the instances of the service.
+func SetStoreFactory(f StoreFactory) {
+	factory = f
+}
+
+func GetKvStore(path string) KvStore {
+	return factory(path)
+}
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/kuiper"
+)
+
+var _ kuiper.Service = (*serviceMock)(nil)
+
+// serviceMock serves the status and the topologies of the rules of the user
+// with the token. The other methods of the service are not implemented.
+type serviceMock struct {
+	kuiper.Service
+	token    string
+	statuses map[string]kuiper.RuleStatus
+	topos    map[string]kuiper.RuleTopo
+}
+
+// NewRuleStatusService creates a service which serves the provided status and
+// topologies of the rules by id.
+func NewRuleStatusService(token string, statuses map[string]kuiper.RuleStatus, topos map[string]kuiper.RuleTopo) kuiper.Service {
+	return &serviceMock{
+		token:    token,
+		statuses: statuses,
+		topos:    topos,
+	}
+}
+
+func (svc *serviceMock) ViewRuleStatus(_ context.Context, token, id string) (kuiper.RuleStatus, error) {
+	if token != svc.token {
+		return kuiper.RuleStatus{}, kuiper.ErrUnauthorizedAccess
+	}
+	s, ok := svc.statuses[id]
+	if !ok {
+		return kuiper.RuleStatus{}, kuiper.ErrNotFound
+	}
+	return s, nil
+}
+
+func (svc *serviceMock) ViewRuleTopo(_ context.Context, token, id string) (kuiper.RuleTopo, error) {
+	if token != svc.token {
+		return kuiper.RuleTopo{}, kuiper.ErrUnauthorizedAccess
+	}
+	t, ok := svc.topos[id]
+	if !ok {
+		return kuiper.RuleTopo{}, kuiper.ErrNotFound
+	}
+	return t, nil
+}
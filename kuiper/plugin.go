@@ -1,44 +1,55 @@
 package kuiper
 
-// Stream represents a Mainflux thing. Each thing is owned by one user, and
-// it is assigned with the unique identifier and (temporary) access key.
-type Plugin struct {
-	ID       string
-	Name     string
-	Json     string
-	Type     int
-	Stop     bool
-	Metadata Metadata
-}
+import "context"
+
+// PluginType is the kind of extension provided by a plugin.
+type PluginType string
 
-type PluginSource struct {
-	ID       string
-	Name     string
-	Json     string
-	Type     int
-	Stop     bool
-	Metadata Metadata
+const (
+	// PluginSource identifies source plugins.
+	PluginSource PluginType = "sources"
+	// PluginSink identifies sink plugins.
+	PluginSink PluginType = "sinks"
+	// PluginFunction identifies function plugins.
+	PluginFunction PluginType = "functions"
+)
+
+// Plugin represents a kuiper source, sink or function extension. Built-in
+// plugins are shared by all users. Installed plugins are loaded by the
+// process, so they are usable in the rules of all users as well, but only the
+// user who installed them can view or remove them.
+type Plugin struct {
+	Name       string
+	Type       PluginType
+	Owner      string
+	File       string
+	ShellParas []string
+	Version    string
+	BuiltIn    bool
+	Json       string
 }
 
-// PluginSourcesPage contains page related metadata as well as list of plugin
-// source that belong to this page.
-type PluginSourcesPage struct {
+// PluginsPage contains page related metadata as well as list of plugins
+// that belong to this page.
+type PluginsPage struct {
 	PageMetadata
-	Sources []PluginSource
+	Plugins []Plugin
 }
 
-type PluginSink struct {
-	ID       string
-	Name     string
-	Json     string
-	Type     int
-	Stop     bool
-	Metadata Metadata
-}
+// PluginRepository specifies the persistence API of installed plugins.
+// Plugin names are unique per plugin type among all users.
+type PluginRepository interface {
+	// Save persists the installed plugin.
+	Save(context.Context, Plugin) error
 
-// PluginSinksPage contains page related metadata as well as list of plugin
-// sink that belong to this page.
-type PluginSinksPage struct {
-	PageMetadata
-	Sinks []PluginSink
+	// RetrieveByName retrieves the installed plugin having the provided type
+	// and name.
+	RetrieveByName(context.Context, PluginType, string) (Plugin, error)
+
+	// RetrieveAll retrieves all installed plugins of the provided type.
+	RetrieveAll(context.Context, PluginType) ([]Plugin, error)
+
+	// Remove removes the installed plugin having the provided type and name,
+	// that is owned by the specified user.
+	Remove(context.Context, string, PluginType, string) error
 }
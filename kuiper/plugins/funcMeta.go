@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/cloustone/pandas/kuiper/util"
@@ -107,6 +108,29 @@ func (m *Manager) readFuncMetaFile(filePath string) error {
 	util.Log.Infof("funcMeta file : %s", fiName)
 	return nil
 }
+func GetFuncMeta(pluginName, language string) (*uiFuncs, error) {
+	if v, ok := g_funcMetadata[pluginName+".json"]; ok && nil != v {
+		return v, nil
+	}
+	return nil, fmt.Errorf(`%s%s`, getMsg(language, function, "not_found_plugin"), pluginName)
+}
+
+func GetFuncPlugins() (funcs []*pluginfo) {
+	for fileName, v := range g_funcMetadata {
+		if nil == v {
+			continue
+		}
+		node := new(pluginfo)
+		node.Name = strings.TrimSuffix(fileName, `.json`)
+		node.About = v.About
+		funcs = append(funcs, node)
+	}
+	sort.Slice(funcs, func(i, j int) bool {
+		return funcs[i].Name < funcs[j].Name
+	})
+	return funcs
+}
+
 func GetFunctions() (ret []*uiFuncs) {
 	for _, v := range g_funcMetadata {
 		ret = append(ret, v)
@@ -161,7 +161,41 @@ func GetSink(t string) (api.Sink, error) {
 	return s, nil
 }
 
+// The functions defined at runtime, such as the user defined SQL functions,
+// are looked up before the function plugins.
+var (
+	runtimeFuncs = make(map[string]func() api.Function)
+	funcMu       sync.RWMutex
+)
+
+// RegisterFunction makes the function defined at runtime available to the
+// rules. The name is case insensitive and can't be registered twice.
+func RegisterFunction(name string, f func() api.Function) error {
+	key := strings.ToLower(name)
+	funcMu.Lock()
+	defer funcMu.Unlock()
+	if _, ok := runtimeFuncs[key]; ok {
+		return fmt.Errorf("function %s already exists", name)
+	}
+	runtimeFuncs[key] = f
+	return nil
+}
+
+// UnregisterFunction removes the function defined at runtime, the rules
+// running already keep their instances of the function.
+func UnregisterFunction(name string) {
+	funcMu.Lock()
+	delete(runtimeFuncs, strings.ToLower(name))
+	funcMu.Unlock()
+}
+
 func GetFunction(t string) (api.Function, error) {
+	funcMu.RLock()
+	f, ok := runtimeFuncs[strings.ToLower(t)]
+	funcMu.RUnlock()
+	if ok {
+		return f(), nil
+	}
 	nf, err := getPlugin(t, FUNCTION)
 	if err != nil {
 		return nil, err
@@ -16,6 +16,7 @@ const (
 	baseOption   = `options`
 	sink         = `sink`
 	source       = `source`
+	function     = `function`
 )
 
 type (
@@ -88,7 +89,7 @@ type (
 )
 
 func isInternalSink(fiName string) bool {
-	internal := []string{`edgex.json`, `log.json`, `mqtt.json`, `nop.json`, `rest.json`}
+	internal := []string{`alerts.json`, `edgex.json`, `log.json`, `mainflux.json`, `memory.json`, `mqtt.json`, `nop.json`, `postgres.json`, `rest.json`}
 	for _, v := range internal {
 		if v == fiName {
 			return true
@@ -330,6 +331,8 @@ func (this *uiSinks) modifyOption(option *api.RuleOption) {
 			baseOption.Fields[i].Default = option.IsEventTime
 		case `lateTol`:
 			baseOption.Fields[i].Default = option.LateTol
+		case `allowedLateness`:
+			baseOption.Fields[i].Default = option.AllowedLateness
 		case `concurrency`:
 			baseOption.Fields[i].Default = option.Concurrency
 		case `bufferLength`:
@@ -29,7 +29,7 @@ type (
 )
 
 func isInternalSource(fiName string) bool {
-	internal := []string{`edgex.json`, `httppull.json`, `mqtt.json`}
+	internal := []string{`edgex.json`, `file.json`, `httppull.json`, `mainflux.json`, `memory.json`, `mqtt.json`, `things.json`}
 	for _, v := range internal {
 		if v == fiName {
 			return true
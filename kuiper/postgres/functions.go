@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cloustone/pandas/kuiper"
+	"github.com/lib/pq" // required for DB access
+)
+
+var _ kuiper.FunctionRepository = (*functionRepository)(nil)
+
+type functionRepository struct {
+	db Database
+}
+
+// NewFunctionRepository instantiates a PostgreSQL implementation of user
+// defined function repository.
+func NewFunctionRepository(db Database) kuiper.FunctionRepository {
+	return &functionRepository{
+		db: db,
+	}
+}
+
+func (fr functionRepository) Save(ctx context.Context, f kuiper.Function) error {
+	q := `INSERT INTO functions (name, owner, sql) VALUES (:name, :owner, :sql);`
+
+	if _, err := fr.db.NamedExecContext(ctx, q, toDBFunction(f)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return kuiper.ErrMalformedEntity
+			case errDuplicate:
+				return kuiper.ErrConflict
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (fr functionRepository) RetrieveByName(ctx context.Context, name string) (kuiper.Function, error) {
+	q := `SELECT name, owner, sql FROM functions WHERE name = $1;`
+
+	dbf := dbFunction{}
+	if err := fr.db.QueryRowxContext(ctx, q, name).StructScan(&dbf); err != nil {
+		if err == sql.ErrNoRows {
+			return kuiper.Function{}, kuiper.ErrNotFound
+		}
+		return kuiper.Function{}, err
+	}
+
+	return toFunction(dbf), nil
+}
+
+func (fr functionRepository) RetrieveAll(ctx context.Context, owner string) ([]kuiper.Function, error) {
+	q := `SELECT name, owner, sql FROM functions WHERE owner = :owner OR :owner = '' ORDER BY name;`
+
+	params := map[string]interface{}{
+		"owner": owner,
+	}
+
+	rows, err := fr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []kuiper.Function{}
+	for rows.Next() {
+		dbf := dbFunction{}
+		if err := rows.StructScan(&dbf); err != nil {
+			return nil, err
+		}
+		items = append(items, toFunction(dbf))
+	}
+
+	return items, nil
+}
+
+func (fr functionRepository) Remove(ctx context.Context, owner, name string) error {
+	dbf := dbFunction{
+		Name:  name,
+		Owner: owner,
+	}
+	q := `DELETE FROM functions WHERE name = :name AND owner = :owner;`
+	if _, err := fr.db.NamedExecContext(ctx, q, dbf); err != nil {
+		return err
+	}
+	return nil
+}
+
+type dbFunction struct {
+	Name  string `db:"name"`
+	Owner string `db:"owner"`
+	SQL   string `db:"sql"`
+}
+
+func toDBFunction(f kuiper.Function) dbFunction {
+	return dbFunction{
+		Name:  f.Name,
+		Owner: f.Owner,
+		SQL:   f.SQL,
+	}
+}
+
+func toFunction(f dbFunction) kuiper.Function {
+	return kuiper.Function{
+		Name:  f.Name,
+		Owner: f.Owner,
+		SQL:   f.SQL,
+	}
+}
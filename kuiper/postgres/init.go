@@ -95,6 +95,90 @@ func migrateDB(db *sqlx.DB) error {
 					`,
 				},
 			},
+			{
+				Id: "plugins_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS plugins (
+						type     VARCHAR(32),
+						name     VARCHAR(1024),
+						owner    VARCHAR(254) NOT NULL,
+						file     VARCHAR(4096),
+						version  VARCHAR(254),
+						PRIMARY KEY (type, name)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE plugins",
+				},
+			},
+			{
+				Id: "kuiper_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS streams (
+						id       UUID,
+						owner    VARCHAR(254),
+						name     VARCHAR(1024),
+						json     TEXT,
+						metadata JSONB,
+						PRIMARY KEY (id, owner)
+					)`,
+					`CREATE TABLE IF NOT EXISTS rules (
+						id       UUID,
+						owner    VARCHAR(254),
+						name     VARCHAR(1024),
+						sql      TEXT,
+						metadata JSONB,
+						PRIMARY KEY (id, owner)
+					)`,
+					`ALTER TABLE IF EXISTS rules ADD COLUMN IF NOT EXISTS
+					 state VARCHAR(32) NOT NULL DEFAULT 'stopped'
+					`,
+				},
+				Down: []string{
+					"DROP TABLE rules",
+					"DROP TABLE streams",
+				},
+			},
+			{
+				Id: "kuiper_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS kv_store (
+						namespace VARCHAR(1024),
+						key       VARCHAR(1024),
+						value     BYTEA,
+						PRIMARY KEY (namespace, key)
+					)`,
+					`CREATE TABLE IF NOT EXISTS rule_leases (
+						rule_id    UUID PRIMARY KEY,
+						holder     VARCHAR(254) NOT NULL,
+						expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE rule_leases",
+					"DROP TABLE kv_store",
+				},
+			},
+			{
+				Id: "kuiper_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS functions (
+						name  VARCHAR(1024) PRIMARY KEY,
+						owner VARCHAR(254) NOT NULL,
+						sql   TEXT NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS rule_templates (
+						name  VARCHAR(1024),
+						owner VARCHAR(254),
+						json  TEXT NOT NULL,
+						PRIMARY KEY (owner, name)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE rule_templates",
+					"DROP TABLE functions",
+				},
+			},
 		},
 	}
 
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloustone/pandas/kuiper/kvstore"
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/lib/pq" // required for DB access
+)
+
+var _ kvstore.KvStore = (*kvStore)(nil)
+
+// kvStore saves the values of a namespace in the kv_store table. The values
+// are gob encoded like the file store, so the types saved as interface must
+// be registered.
+type kvStore struct {
+	db        Database
+	namespace string
+}
+
+// NewKvStoreFactory creates the stores in PostgreSQL instead of the files
+// under the data directory. The path relative to the data directory is used
+// as the namespace, so that all instances of the service share the data.
+func NewKvStoreFactory(db Database) kvstore.StoreFactory {
+	return func(p string) kvstore.KvStore {
+		namespace := p
+		if dir, err := util.GetDataLoc(); err == nil {
+			if rel, err := filepath.Rel(dir, p); err == nil {
+				namespace = filepath.ToSlash(rel)
+			}
+		}
+		return &kvStore{db: db, namespace: namespace}
+	}
+}
+
+type kvValue struct {
+	Value interface{}
+}
+
+type dbKv struct {
+	Namespace string `db:"namespace"`
+	Key       string `db:"key"`
+	Value     []byte `db:"value"`
+}
+
+func (s *kvStore) Open() error {
+	return nil
+}
+
+func (s *kvStore) Close() error {
+	return nil
+}
+
+func (s *kvStore) encode(key string, value interface{}) (dbKv, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(kvValue{Value: value}); err != nil {
+		return dbKv{}, fmt.Errorf("fail to encode value of %s: %s", key, err)
+	}
+	return dbKv{Namespace: s.namespace, Key: key, Value: buf.Bytes()}, nil
+}
+
+func (s *kvStore) Set(key string, value interface{}) error {
+	q := `INSERT INTO kv_store (namespace, key, value) VALUES (:namespace, :key, :value);`
+
+	dbkv, err := s.encode(key, value)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.NamedExecContext(context.Background(), q, dbkv); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return fmt.Errorf("Item %s already exists", key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *kvStore) Replace(key string, value interface{}) error {
+	q := `INSERT INTO kv_store (namespace, key, value) VALUES (:namespace, :key, :value)
+		  ON CONFLICT (namespace, key) DO UPDATE SET value = EXCLUDED.value;`
+
+	dbkv, err := s.encode(key, value)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.NamedExecContext(context.Background(), q, dbkv)
+	return err
+}
+
+func (s *kvStore) Get(key string) (interface{}, bool) {
+	q := `SELECT value FROM kv_store WHERE namespace = $1 AND key = $2;`
+
+	var b []byte
+	if err := s.db.GetContext(context.Background(), &b, q, s.namespace, key); err != nil {
+		if err != sql.ErrNoRows {
+			util.Log.Errorf("fail to get %s of %s: %s", key, s.namespace, err)
+		}
+		return nil, false
+	}
+	var v kvValue
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v); err != nil {
+		util.Log.Errorf("fail to decode %s of %s: %s", key, s.namespace, err)
+		return nil, false
+	}
+	return v.Value, true
+}
+
+func (s *kvStore) Delete(key string) error {
+	q := `DELETE FROM kv_store WHERE namespace = :namespace AND key = :key;`
+
+	res, err := s.db.NamedExecContext(context.Background(), q, dbKv{Namespace: s.namespace, Key: key})
+	if err != nil {
+		return err
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return util.NewErrorWithCode(util.NOT_FOUND, fmt.Sprintf("%s is not found", key))
+	}
+	return nil
+}
+
+func (s *kvStore) Keys() ([]string, error) {
+	q := `SELECT key FROM kv_store WHERE namespace = :namespace ORDER BY key;`
+
+	rows, err := s.db.NamedQueryContext(context.Background(), q, dbKv{Namespace: s.namespace})
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *kvStore) Clean() error {
+	q := `DELETE FROM kv_store WHERE namespace = :namespace;`
+
+	_, err := s.db.NamedExecContext(context.Background(), q, dbKv{Namespace: s.namespace})
+	return err
+}
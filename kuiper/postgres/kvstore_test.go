@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKvStoreSet(t *testing.T) {
+	store := postgres.NewKvStoreFactory(postgres.NewDatabase(db))("kv-set")
+	require.Nil(t, store.Open(), "unexpected error opening store")
+	defer store.Close()
+
+	err := store.Set("key", "value")
+	assert.Nil(t, err, fmt.Sprintf("set new key: unexpected error: %s", err))
+	err = store.Set("key", "other")
+	assert.NotNil(t, err, "set existing key: expected error")
+
+	v, ok := store.Get("key")
+	assert.True(t, ok, "expected key found")
+	assert.Equal(t, "value", v, fmt.Sprintf("expected value got %v", v))
+
+	// The namespaces are isolated
+	other := postgres.NewKvStoreFactory(postgres.NewDatabase(db))("kv-set-other")
+	err = other.Set("key", "other")
+	assert.Nil(t, err, fmt.Sprintf("set key of other namespace: unexpected error: %s", err))
+	v, _ = store.Get("key")
+	assert.Equal(t, "value", v, fmt.Sprintf("expected value got %v", v))
+}
+
+func TestKvStoreReplace(t *testing.T) {
+	store := postgres.NewKvStoreFactory(postgres.NewDatabase(db))("kv-replace")
+
+	cases := []struct {
+		desc  string
+		value interface{}
+	}{
+		{desc: "replace new key", value: "value"},
+		{desc: "replace existing key", value: "other"},
+		{desc: "replace with value of other type", value: 3},
+	}
+
+	for _, tc := range cases {
+		err := store.Replace("key", tc.value)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		v, ok := store.Get("key")
+		assert.True(t, ok, fmt.Sprintf("%s: expected key found", tc.desc))
+		assert.Equal(t, tc.value, v, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.value, v))
+	}
+}
+
+func TestKvStoreKeysAndDelete(t *testing.T) {
+	store := postgres.NewKvStoreFactory(postgres.NewDatabase(db))("kv-keys")
+
+	for _, k := range []string{"b", "a", "c"} {
+		err := store.Set(k, k)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	keys, err := store.Keys()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []string{"a", "b", "c"}, keys, fmt.Sprintf("expected sorted keys got %v", keys))
+
+	err = store.Delete("b")
+	assert.Nil(t, err, fmt.Sprintf("delete existing key: unexpected error: %s", err))
+	err = store.Delete("b")
+	assert.NotNil(t, err, "delete missing key: expected error")
+	_, ok := store.Get("b")
+	assert.False(t, ok, "expected deleted key not found")
+
+	err = store.Clean()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	keys, err = store.Keys()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, keys, fmt.Sprintf("expected no keys after clean got %v", keys))
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper"
+)
+
+var _ kuiper.RuleLeaseRepository = (*ruleLeaseRepository)(nil)
+
+type ruleLeaseRepository struct {
+	db Database
+}
+
+// NewRuleLeaseRepository instantiates a PostgreSQL implementation of rule
+// lease repository. The expiry is computed with the clock of the database,
+// so the instances of the service need not to synchronize their clocks.
+func NewRuleLeaseRepository(db Database) kuiper.RuleLeaseRepository {
+	return &ruleLeaseRepository{
+		db: db,
+	}
+}
+
+func (lr ruleLeaseRepository) Acquire(ctx context.Context, id, holder string, ttl time.Duration) (bool, error) {
+	q := `INSERT INTO rule_leases (rule_id, holder, expires_at)
+		  VALUES (:rule_id, :holder, now() + CAST(:ttl AS BIGINT) * interval '1 millisecond')
+		  ON CONFLICT (rule_id) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+		  WHERE rule_leases.holder = EXCLUDED.holder OR rule_leases.expires_at < now();`
+
+	params := map[string]interface{}{
+		"rule_id": id,
+		"holder":  holder,
+		"ttl":     int64(ttl / time.Millisecond),
+	}
+	res, err := lr.db.NamedExecContext(ctx, q, params)
+	if err != nil {
+		return false, err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return cnt > 0, nil
+}
+
+func (lr ruleLeaseRepository) Release(ctx context.Context, id, holder string) error {
+	q := `DELETE FROM rule_leases WHERE rule_id = :rule_id AND holder = :holder;`
+
+	params := map[string]interface{}{
+		"rule_id": id,
+		"holder":  holder,
+	}
+	_, err := lr.db.NamedExecContext(ctx, q, params)
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/postgres"
+	"github.com/cloustone/pandas/kuiper/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	holder      = "instance-a"
+	otherHolder = "instance-b"
+)
+
+func TestRuleLeaseAcquire(t *testing.T) {
+	leaseRepo := postgres.NewRuleLeaseRepository(postgres.NewDatabase(db))
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	expiring, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = leaseRepo.Acquire(context.Background(), expiring, otherHolder, 100*time.Millisecond)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		holder string
+		wait   time.Duration
+		held   bool
+	}{
+		{
+			desc:   "acquire free lease",
+			id:     id,
+			holder: holder,
+			held:   true,
+		},
+		{
+			desc:   "renew held lease",
+			id:     id,
+			holder: holder,
+			held:   true,
+		},
+		{
+			desc:   "acquire lease held by another holder",
+			id:     id,
+			holder: otherHolder,
+			held:   false,
+		},
+		{
+			desc:   "acquire lease of another holder before it expires",
+			id:     expiring,
+			holder: holder,
+			held:   false,
+		},
+		{
+			desc:   "take over lease of another holder after it expires",
+			id:     expiring,
+			holder: holder,
+			wait:   200 * time.Millisecond,
+			held:   true,
+		},
+		{
+			desc:   "acquire taken over lease by the previous holder",
+			id:     expiring,
+			holder: otherHolder,
+			held:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		time.Sleep(tc.wait)
+		held, err := leaseRepo.Acquire(context.Background(), tc.id, tc.holder, time.Minute)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.held, held, fmt.Sprintf("%s: expected held %t got %t", tc.desc, tc.held, held))
+	}
+}
+
+func TestRuleLeaseRelease(t *testing.T) {
+	leaseRepo := postgres.NewRuleLeaseRepository(postgres.NewDatabase(db))
+
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	held, err := leaseRepo.Acquire(context.Background(), id, otherHolder, time.Minute)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.True(t, held, "expected lease held")
+
+	err = leaseRepo.Release(context.Background(), id, holder)
+	assert.Nil(t, err, fmt.Sprintf("release lease of another holder: unexpected error: %s", err))
+	held, err = leaseRepo.Acquire(context.Background(), id, holder, time.Minute)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.False(t, held, "expected lease kept when released by another holder")
+
+	err = leaseRepo.Release(context.Background(), id, otherHolder)
+	assert.Nil(t, err, fmt.Sprintf("release held lease: unexpected error: %s", err))
+	err = leaseRepo.Release(context.Background(), id, otherHolder)
+	assert.Nil(t, err, fmt.Sprintf("release free lease: unexpected error: %s", err))
+
+	held, err = leaseRepo.Acquire(context.Background(), id, holder, time.Minute)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, held, "expected released lease acquired by another holder")
+}
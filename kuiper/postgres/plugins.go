@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cloustone/pandas/kuiper"
+	"github.com/lib/pq" // required for DB access
+)
+
+var _ kuiper.PluginRepository = (*pluginRepository)(nil)
+
+type pluginRepository struct {
+	db Database
+}
+
+// NewPluginRepository instantiates a PostgreSQL implementation of plugin
+// repository.
+func NewPluginRepository(db Database) kuiper.PluginRepository {
+	return &pluginRepository{
+		db: db,
+	}
+}
+
+func (pr pluginRepository) Save(ctx context.Context, p kuiper.Plugin) error {
+	q := `INSERT INTO plugins (type, name, owner, file, version)
+		  VALUES (:type, :name, :owner, :file, :version);`
+
+	if _, err := pr.db.NamedExecContext(ctx, q, toDBPlugin(p)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return kuiper.ErrMalformedEntity
+			case errDuplicate:
+				return kuiper.ErrConflict
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (pr pluginRepository) RetrieveByName(ctx context.Context, pluginType kuiper.PluginType, name string) (kuiper.Plugin, error) {
+	q := `SELECT type, name, owner, file, version FROM plugins WHERE type = $1 AND name = $2;`
+
+	dbp := dbPlugin{}
+	if err := pr.db.QueryRowxContext(ctx, q, string(pluginType), name).StructScan(&dbp); err != nil {
+		if err == sql.ErrNoRows {
+			return kuiper.Plugin{}, kuiper.ErrNotFound
+		}
+		return kuiper.Plugin{}, err
+	}
+
+	return toPlugin(dbp), nil
+}
+
+func (pr pluginRepository) RetrieveAll(ctx context.Context, pluginType kuiper.PluginType) ([]kuiper.Plugin, error) {
+	q := `SELECT type, name, owner, file, version FROM plugins WHERE type = :type ORDER BY name;`
+
+	params := map[string]interface{}{
+		"type": string(pluginType),
+	}
+
+	rows, err := pr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []kuiper.Plugin{}
+	for rows.Next() {
+		dbp := dbPlugin{}
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, err
+		}
+		items = append(items, toPlugin(dbp))
+	}
+
+	return items, nil
+}
+
+func (pr pluginRepository) Remove(ctx context.Context, owner string, pluginType kuiper.PluginType, name string) error {
+	dbp := dbPlugin{
+		Type:  string(pluginType),
+		Name:  name,
+		Owner: owner,
+	}
+	q := `DELETE FROM plugins WHERE type = :type AND name = :name AND owner = :owner;`
+	if _, err := pr.db.NamedExecContext(ctx, q, dbp); err != nil {
+		return err
+	}
+	return nil
+}
+
+type dbPlugin struct {
+	Type    string `db:"type"`
+	Name    string `db:"name"`
+	Owner   string `db:"owner"`
+	File    string `db:"file"`
+	Version string `db:"version"`
+}
+
+func toDBPlugin(p kuiper.Plugin) dbPlugin {
+	return dbPlugin{
+		Type:    string(p.Type),
+		Name:    p.Name,
+		Owner:   p.Owner,
+		File:    p.File,
+		Version: p.Version,
+	}
+}
+
+func toPlugin(p dbPlugin) kuiper.Plugin {
+	return kuiper.Plugin{
+		Type:    kuiper.PluginType(p.Type),
+		Name:    p.Name,
+		Owner:   p.Owner,
+		File:    p.File,
+		Version: p.Version,
+	}
+}
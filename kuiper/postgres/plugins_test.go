@@ -0,0 +1,209 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/postgres"
+	"github.com/cloustone/pandas/kuiper/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pluginOwner = "plugin-owner@example.com"
+
+func newPlugin(t *testing.T, pluginType kuiper.PluginType, owner string) kuiper.Plugin {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return kuiper.Plugin{
+		Type:    pluginType,
+		Name:    "plugin-" + id,
+		Owner:   owner,
+		File:    "file:///var/plugins/plugin-" + id + ".zip",
+		Version: "1.0.0",
+	}
+}
+
+func TestPluginSave(t *testing.T) {
+	pluginRepo := postgres.NewPluginRepository(postgres.NewDatabase(db))
+	plugin := newPlugin(t, kuiper.PluginSink, pluginOwner)
+
+	other := plugin
+	other.Owner = "other@example.com"
+	source := plugin
+	source.Type = kuiper.PluginSource
+
+	cases := []struct {
+		desc   string
+		plugin kuiper.Plugin
+		err    error
+	}{
+		{
+			desc:   "save new plugin",
+			plugin: plugin,
+			err:    nil,
+		},
+		{
+			desc:   "save plugin with existing name",
+			plugin: plugin,
+			err:    kuiper.ErrConflict,
+		},
+		{
+			desc:   "save plugin with name of plugin of another user",
+			plugin: other,
+			err:    kuiper.ErrConflict,
+		},
+		{
+			desc:   "save plugin with existing name of another type",
+			plugin: source,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := pluginRepo.Save(context.Background(), tc.plugin)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestPluginRetrieveByName(t *testing.T) {
+	pluginRepo := postgres.NewPluginRepository(postgres.NewDatabase(db))
+	plugin := newPlugin(t, kuiper.PluginSink, pluginOwner)
+	err := pluginRepo.Save(context.Background(), plugin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc       string
+		pluginType kuiper.PluginType
+		name       string
+		plugin     kuiper.Plugin
+		err        error
+	}{
+		{
+			desc:       "retrieve existing plugin",
+			pluginType: plugin.Type,
+			name:       plugin.Name,
+			plugin:     plugin,
+			err:        nil,
+		},
+		{
+			desc:       "retrieve plugin of another type",
+			pluginType: kuiper.PluginFunction,
+			name:       plugin.Name,
+			plugin:     kuiper.Plugin{},
+			err:        kuiper.ErrNotFound,
+		},
+		{
+			desc:       "retrieve non-existing plugin",
+			pluginType: plugin.Type,
+			name:       "non-existing",
+			plugin:     kuiper.Plugin{},
+			err:        kuiper.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := pluginRepo.RetrieveByName(context.Background(), tc.pluginType, tc.name)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.plugin, p, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.plugin, p))
+	}
+}
+
+func TestPluginRetrieveAll(t *testing.T) {
+	pluginRepo := postgres.NewPluginRepository(postgres.NewDatabase(db))
+	functions := []kuiper.Plugin{
+		newPlugin(t, kuiper.PluginFunction, pluginOwner),
+		newPlugin(t, kuiper.PluginFunction, "other@example.com"),
+	}
+	source := newPlugin(t, kuiper.PluginSource, pluginOwner)
+	for _, p := range append(functions, source) {
+		err := pluginRepo.Save(context.Background(), p)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	cases := []struct {
+		desc       string
+		pluginType kuiper.PluginType
+		included   []kuiper.Plugin
+		excluded   []kuiper.Plugin
+	}{
+		{
+			desc:       "retrieve function plugins of all owners",
+			pluginType: kuiper.PluginFunction,
+			included:   functions,
+			excluded:   []kuiper.Plugin{source},
+		},
+		{
+			desc:       "retrieve source plugins",
+			pluginType: kuiper.PluginSource,
+			included:   []kuiper.Plugin{source},
+			excluded:   functions,
+		},
+	}
+
+	for _, tc := range cases {
+		plugins, err := pluginRepo.RetrieveAll(context.Background(), tc.pluginType)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		names := make(map[string]kuiper.Plugin)
+		for _, p := range plugins {
+			assert.Equal(t, tc.pluginType, p.Type, fmt.Sprintf("%s: expected type %s got %s\n", tc.desc, tc.pluginType, p.Type))
+			names[p.Name] = p
+		}
+		for _, p := range tc.included {
+			assert.Equal(t, p, names[p.Name], fmt.Sprintf("%s: expected plugin %v got %v\n", tc.desc, p, names[p.Name]))
+		}
+		for _, p := range tc.excluded {
+			_, ok := names[p.Name]
+			assert.False(t, ok, fmt.Sprintf("%s: unexpected plugin %s\n", tc.desc, p.Name))
+		}
+	}
+}
+
+func TestPluginRemove(t *testing.T) {
+	pluginRepo := postgres.NewPluginRepository(postgres.NewDatabase(db))
+	plugin := newPlugin(t, kuiper.PluginSink, pluginOwner)
+	err := pluginRepo.Save(context.Background(), plugin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		name  string
+		err   error
+		found bool
+	}{
+		{
+			desc:  "remove plugin of another owner",
+			owner: "other@example.com",
+			name:  plugin.Name,
+			err:   nil,
+			found: true,
+		},
+		{
+			desc:  "remove existing plugin",
+			owner: plugin.Owner,
+			name:  plugin.Name,
+			err:   nil,
+			found: false,
+		},
+		{
+			desc:  "remove removed plugin",
+			owner: plugin.Owner,
+			name:  plugin.Name,
+			err:   nil,
+			found: false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := pluginRepo.Remove(context.Background(), tc.owner, plugin.Type, tc.name)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		_, err = pluginRepo.RetrieveByName(context.Background(), plugin.Type, tc.name)
+		assert.Equal(t, tc.found, err == nil, fmt.Sprintf("%s: expected found %t got %s\n", tc.desc, tc.found, err))
+	}
+}
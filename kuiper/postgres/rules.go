@@ -38,8 +38,8 @@ func (rr ruleRepository) Save(ctx context.Context, ths ...kuiper.Rule) ([]kuiper
 		return nil, err
 	}
 
-	q := `INSERT INTO rules (id, owner, name, sql, metadata)
-		  VALUES (:id, :owner, :name, :sql, :metadata);`
+	q := `INSERT INTO rules (id, owner, name, sql, state, metadata)
+		  VALUES (:id, :owner, :name, :sql, :state, :metadata);`
 
 	for _, rule := range ths {
 		dbth, err := toDBRule(rule)
@@ -105,7 +105,7 @@ func (rr ruleRepository) Update(ctx context.Context, rule kuiper.Rule) error {
 }
 
 func (rr ruleRepository) RetrieveByID(ctx context.Context, owner, id string) (kuiper.Rule, error) {
-	q := `SELECT name, key, metadata FROM rules WHERE id = $1 AND owner = $2;`
+	q := `SELECT name, sql, state, metadata FROM rules WHERE id = $1 AND owner = $2;`
 
 	dbth := dbRule{
 		ID:    id,
@@ -133,7 +133,7 @@ func (rr ruleRepository) RetrieveAll(ctx context.Context, owner string, offset,
 		return kuiper.RulesPage{}, err
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM rules
+	q := fmt.Sprintf(`SELECT id, name, sql, state, metadata FROM rules
 		  WHERE owner = :owner %s%s ORDER BY id LIMIT :limit OFFSET :offset;`, mq, nq)
 
 	params := map[string]interface{}{
@@ -194,11 +194,69 @@ func (rr ruleRepository) Remove(ctx context.Context, owner, id string) error {
 	return nil
 }
 
+func (rr ruleRepository) UpdateState(ctx context.Context, owner, id, state string) error {
+	q := `UPDATE rules SET state = :state WHERE owner = :owner AND id = :id;`
+
+	dbth := dbRule{
+		ID:    id,
+		Owner: owner,
+		State: state,
+	}
+
+	res, err := rr.db.NamedExecContext(ctx, q, dbth)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt == 0 {
+		return kuiper.ErrNotFound
+	}
+
+	return nil
+}
+
+func (rr ruleRepository) RetrieveByState(ctx context.Context, state string) ([]kuiper.Rule, error) {
+	q := `SELECT id, owner, name, sql, state, metadata FROM rules WHERE state = :state ORDER BY id;`
+
+	params := map[string]interface{}{
+		"state": state,
+	}
+
+	rows, err := rr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []kuiper.Rule{}
+	for rows.Next() {
+		dbth := dbRule{}
+		if err := rows.StructScan(&dbth); err != nil {
+			return nil, err
+		}
+
+		th, err := toRule(dbth)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, th)
+	}
+
+	return items, nil
+}
+
 type dbRule struct {
 	ID       string `db:"id"`
 	Owner    string `db:"owner"`
 	Name     string `db:"name"`
 	Sql      string `db:"sql"`
+	State    string `db:"state"`
 	Metadata []byte `db:"metadata"`
 }
 
@@ -217,6 +275,7 @@ func toDBRule(r kuiper.Rule) (dbRule, error) {
 		Owner:    r.Owner,
 		Name:     r.Name,
 		Sql:      r.SQL,
+		State:    r.State,
 		Metadata: data,
 	}, nil
 }
@@ -232,6 +291,7 @@ func toRule(r dbRule) (kuiper.Rule, error) {
 		Owner:    r.Owner,
 		Name:     r.Name,
 		SQL:      r.Sql,
+		State:    r.State,
 		Metadata: metadata,
 	}, nil
 }
@@ -0,0 +1,130 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/postgres"
+	"github.com/cloustone/pandas/kuiper/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ruleOwner = "rule-owner@example.com"
+	ruleSQL   = `{"id": "rule1", "sql": "SELECT * FROM demo", "actions": [{"log": {}}]}`
+)
+
+func saveRule(t *testing.T, ruleRepo kuiper.RuleRepository, owner, state string) kuiper.Rule {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	rule := kuiper.Rule{
+		ID:       id,
+		Owner:    owner,
+		Name:     "rule-" + id,
+		SQL:      ruleSQL,
+		State:    state,
+		Metadata: kuiper.Metadata{"env": "test"},
+	}
+	_, err = ruleRepo.Save(context.Background(), rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return rule
+}
+
+func TestRuleUpdateState(t *testing.T) {
+	ruleRepo := postgres.NewRuleRepository(postgres.NewDatabase(db))
+	rule := saveRule(t, ruleRepo, ruleOwner, kuiper.RuleStopped)
+
+	nonexistent, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		state string
+		err   error
+	}{
+		{
+			desc:  "update state of existing rule",
+			owner: rule.Owner,
+			id:    rule.ID,
+			state: kuiper.RuleRunning,
+			err:   nil,
+		},
+		{
+			desc:  "update state of rule of another owner",
+			owner: "other@example.com",
+			id:    rule.ID,
+			state: kuiper.RuleStopped,
+			err:   kuiper.ErrNotFound,
+		},
+		{
+			desc:  "update state of non-existing rule",
+			owner: rule.Owner,
+			id:    nonexistent,
+			state: kuiper.RuleStopped,
+			err:   kuiper.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := ruleRepo.UpdateState(context.Background(), tc.owner, tc.id, tc.state)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := ruleRepo.RetrieveByID(context.Background(), rule.Owner, rule.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, kuiper.RuleRunning, saved.State, fmt.Sprintf("expected state %s got %s\n", kuiper.RuleRunning, saved.State))
+}
+
+func TestRuleRetrieveByState(t *testing.T) {
+	ruleRepo := postgres.NewRuleRepository(postgres.NewDatabase(db))
+	running := []kuiper.Rule{
+		saveRule(t, ruleRepo, ruleOwner, kuiper.RuleRunning),
+		saveRule(t, ruleRepo, "other@example.com", kuiper.RuleRunning),
+	}
+	stopped := saveRule(t, ruleRepo, ruleOwner, kuiper.RuleStopped)
+
+	cases := []struct {
+		desc     string
+		state    string
+		included []kuiper.Rule
+		excluded []kuiper.Rule
+	}{
+		{
+			desc:     "retrieve running rules of all owners",
+			state:    kuiper.RuleRunning,
+			included: running,
+			excluded: []kuiper.Rule{stopped},
+		},
+		{
+			desc:     "retrieve stopped rules",
+			state:    kuiper.RuleStopped,
+			included: []kuiper.Rule{stopped},
+			excluded: running,
+		},
+	}
+
+	for _, tc := range cases {
+		rules, err := ruleRepo.RetrieveByState(context.Background(), tc.state)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		ids := make(map[string]kuiper.Rule)
+		for _, r := range rules {
+			assert.Equal(t, tc.state, r.State, fmt.Sprintf("%s: expected state %s got %s\n", tc.desc, tc.state, r.State))
+			ids[r.ID] = r
+		}
+		for _, r := range tc.included {
+			assert.Equal(t, r, ids[r.ID], fmt.Sprintf("%s: expected rule %v got %v\n", tc.desc, r, ids[r.ID]))
+		}
+		for _, r := range tc.excluded {
+			_, ok := ids[r.ID]
+			assert.False(t, ok, fmt.Sprintf("%s: unexpected rule %s\n", tc.desc, r.ID))
+		}
+	}
+}
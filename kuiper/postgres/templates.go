@@ -0,0 +1,115 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cloustone/pandas/kuiper"
+	"github.com/lib/pq" // required for DB access
+)
+
+var _ kuiper.RuleTemplateRepository = (*ruleTemplateRepository)(nil)
+
+type ruleTemplateRepository struct {
+	db Database
+}
+
+// NewRuleTemplateRepository instantiates a PostgreSQL implementation of rule
+// template repository.
+func NewRuleTemplateRepository(db Database) kuiper.RuleTemplateRepository {
+	return &ruleTemplateRepository{
+		db: db,
+	}
+}
+
+func (tr ruleTemplateRepository) Save(ctx context.Context, t kuiper.RuleTemplate) error {
+	q := `INSERT INTO rule_templates (name, owner, json) VALUES (:name, :owner, :json);`
+
+	if _, err := tr.db.NamedExecContext(ctx, q, toDBRuleTemplate(t)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return kuiper.ErrMalformedEntity
+			case errDuplicate:
+				return kuiper.ErrConflict
+			}
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (tr ruleTemplateRepository) RetrieveByName(ctx context.Context, owner, name string) (kuiper.RuleTemplate, error) {
+	q := `SELECT name, owner, json FROM rule_templates WHERE owner = $1 AND name = $2;`
+
+	dbt := dbRuleTemplate{}
+	if err := tr.db.QueryRowxContext(ctx, q, owner, name).StructScan(&dbt); err != nil {
+		if err == sql.ErrNoRows {
+			return kuiper.RuleTemplate{}, kuiper.ErrNotFound
+		}
+		return kuiper.RuleTemplate{}, err
+	}
+
+	return toRuleTemplate(dbt), nil
+}
+
+func (tr ruleTemplateRepository) RetrieveAll(ctx context.Context, owner string) ([]kuiper.RuleTemplate, error) {
+	q := `SELECT name, owner, json FROM rule_templates WHERE owner = :owner ORDER BY name;`
+
+	params := map[string]interface{}{
+		"owner": owner,
+	}
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []kuiper.RuleTemplate{}
+	for rows.Next() {
+		dbt := dbRuleTemplate{}
+		if err := rows.StructScan(&dbt); err != nil {
+			return nil, err
+		}
+		items = append(items, toRuleTemplate(dbt))
+	}
+
+	return items, nil
+}
+
+func (tr ruleTemplateRepository) Remove(ctx context.Context, owner, name string) error {
+	dbt := dbRuleTemplate{
+		Name:  name,
+		Owner: owner,
+	}
+	q := `DELETE FROM rule_templates WHERE name = :name AND owner = :owner;`
+	if _, err := tr.db.NamedExecContext(ctx, q, dbt); err != nil {
+		return err
+	}
+	return nil
+}
+
+type dbRuleTemplate struct {
+	Name  string `db:"name"`
+	Owner string `db:"owner"`
+	Json  string `db:"json"`
+}
+
+func toDBRuleTemplate(t kuiper.RuleTemplate) dbRuleTemplate {
+	return dbRuleTemplate{
+		Name:  t.Name,
+		Owner: t.Owner,
+		Json:  t.Json,
+	}
+}
+
+func toRuleTemplate(t dbRuleTemplate) kuiper.RuleTemplate {
+	return kuiper.RuleTemplate{
+		Name:  t.Name,
+		Owner: t.Owner,
+		Json:  t.Json,
+	}
+}
@@ -1,6 +1,11 @@
 package kuiper
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
 
 // Rule represents a Mainflux thing. Each thing is owned by one user, and
 // it is assigned with the unique identifier and (temporary) access key.
@@ -9,9 +14,90 @@ type Rule struct {
 	ID       string
 	Name     string
 	SQL      string
+	State    string
 	Metadata Metadata
 }
 
+const (
+	// RuleRunning is the desired state of rules which should be running.
+	RuleRunning = "running"
+	// RuleStopped is the desired state of rules which are stopped by user.
+	RuleStopped = "stopped"
+)
+
+// RuleStatus represents the running state of a rule and the metrics of
+// each source, operator and sink instance in its topology.
+type RuleStatus struct {
+	ID      string        `json:"id"`
+	Name    string        `json:"name"`
+	State   string        `json:"state"`
+	Metrics []NodeMetrics `json:"metrics,omitempty"`
+}
+
+// NodeMetrics contains the record counters, latency and buffer length of
+// one instance of a source, operator or sink. The late counters are only
+// set for the event time window.
+type NodeMetrics struct {
+	Type             string `json:"type"`
+	Name             string `json:"name"`
+	Instance         int    `json:"instance"`
+	RecordsInTotal   int64  `json:"records_in_total"`
+	RecordsOutTotal  int64  `json:"records_out_total"`
+	ExceptionsTotal  int64  `json:"exceptions_total"`
+	ProcessLatencyMs int64  `json:"process_latency_ms"`
+	BufferLength     int64  `json:"buffer_length"`
+	LastInvocation   string `json:"last_invocation,omitempty"`
+	LateRecordsTotal int64  `json:"late_records_total,omitempty"`
+	LateDroppedTotal int64  `json:"late_dropped_total,omitempty"`
+}
+
+// RuleTopo represents the DAG of a rule, edges are indexed by the node
+// which emits data.
+type RuleTopo struct {
+	Sources []string            `json:"sources"`
+	Edges   map[string][]string `json:"edges"`
+}
+
+// RulePlan is the logical plan of a rule, the sources and operators are
+// listed in the order the data flows through them.
+type RulePlan struct {
+	Operators []PlanOperator `json:"operators"`
+}
+
+// PlanOperator is a source or an operator of the rule topology, named in the
+// same way as RuleTopo, along with its settings.
+type PlanOperator struct {
+	Name     string                 `json:"name"`
+	Inputs   []string               `json:"inputs,omitempty"`
+	Settings map[string]interface{} `json:"settings,omitempty"`
+}
+
+// RuleSample is a row sent to the rule under test. Stream can be omitted
+// if the rule reads one stream only. The rows of a stream are sent at the
+// intervals of their timestamps in milliseconds.
+type RuleSample struct {
+	Stream    string                 `json:"stream,omitempty"`
+	Timestamp int64                  `json:"timestamp,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+}
+
+// RuleTest runs the SQL against the sample rows in a throwaway topology
+// without deploying a rule. Wait is the milliseconds to collect outputs
+// after all rows are sent, the default rule options are used if Options is
+// nil.
+type RuleTest struct {
+	SQL     string          `json:"sql"`
+	Options *api.RuleOption `json:"options,omitempty"`
+	Samples []RuleSample    `json:"samples"`
+	Wait    int64           `json:"wait,omitempty"`
+}
+
+// RuleTestResult contains the outputs of the rule test, each output is the
+// rows emitted to the sink at once.
+type RuleTestResult struct {
+	Outputs [][]map[string]interface{} `json:"outputs"`
+}
+
 // RulesPage contains page related metadata as well as list of things that
 // belong to this page.
 type RulesPage struct {
@@ -40,6 +126,29 @@ type RuleRepository interface {
 	// Remove removes the thing having the provided identifier, that is owned
 	// by the specified user.
 	Remove(context.Context, string, string) error
+
+	// UpdateState persists the desired state of the rule having the provided
+	// identifier, that is owned by the specified user.
+	UpdateState(context.Context, string, string, string) error
+
+	// RetrieveByState retrieves rules of all users having the provided
+	// desired state.
+	RetrieveByState(context.Context, string) ([]Rule, error)
+}
+
+// RuleLeaseRepository specifies the leases of the running rules shared by the
+// instances of the service. A rule only runs on the instance holding its
+// lease, the other instances take it over after the lease expires.
+type RuleLeaseRepository interface {
+	// Acquire takes or renews the lease of the rule having the provided
+	// identifier for the holder. It returns false if the lease is held by
+	// another holder and not expired yet.
+	Acquire(context.Context, string, string, time.Duration) (bool, error)
+
+	// Release removes the lease of the rule having the provided identifier
+	// if it is held by the provided holder. The leases of other holders are
+	// kept.
+	Release(context.Context, string, string) error
 }
 
 // RuleCache contains thing caching interface.
@@ -0,0 +1,184 @@
+package kuiper
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/kvstore"
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/extensions"
+	"github.com/cloustone/pandas/kuiper/xstream/nodes"
+)
+
+const (
+	// DEFAULT_RULE_TEST_WAIT is the milliseconds to collect the outputs
+	// after all sample rows are sent if the wait is not specified.
+	DEFAULT_RULE_TEST_WAIT = 1000
+	// MAX_RULE_TEST_DURATION limits both the time span of the sample rows
+	// and the wait in milliseconds, so that a test does not block for long.
+	MAX_RULE_TEST_DURATION = 60000
+)
+
+// testRule feeds the sample rows through a throwaway topology made of the
+// sample sources and a collecting sink. The topology runs with at most once qos, so
+// nothing is saved in the checkpoints.
+func (rm *ruleManager) testRule(t RuleTest) (RuleTestResult, error) {
+	selectStmt, err := getStatementFromSql(t.SQL)
+	if err != nil {
+		return RuleTestResult{}, err
+	}
+	rule := rm.getDefaultRule(fmt.Sprintf("$$test_%d", time.Now().UnixNano()), t.SQL)
+	if t.Options != nil {
+		opt := *t.Options
+		rule.Options = &opt
+	} else {
+		opt := util.Config.Rule
+		rule.Options = &opt
+	}
+	rule.Options.Qos = api.AtMostOnce
+
+	wait := t.Wait
+	if wait == 0 {
+		wait = DEFAULT_RULE_TEST_WAIT
+	}
+	if wait < 0 || wait > MAX_RULE_TEST_DURATION {
+		return RuleTestResult{}, fmt.Errorf("wait %d is invalid, require a positive integer not larger than %d", wait, MAX_RULE_TEST_DURATION)
+	}
+
+	streams := xsql.GetStreams(selectStmt)
+	data := make(map[string][]*xsql.Tuple, len(streams))
+	for _, s := range streams {
+		data[s] = nil
+	}
+	for i, sample := range t.Samples {
+		name := sample.Stream
+		if name == "" {
+			if len(streams) != 1 {
+				return RuleTestResult{}, fmt.Errorf("stream of sample %d is required as the rule reads %d streams", i, len(streams))
+			}
+			name = streams[0]
+		}
+		rows, ok := data[name]
+		if !ok {
+			return RuleTestResult{}, fmt.Errorf("stream %s of sample %d is not used by the rule", name, i)
+		}
+		if len(rows) > 0 && sample.Timestamp-rows[0].Timestamp > MAX_RULE_TEST_DURATION {
+			return RuleTestResult{}, fmt.Errorf("timestamp of sample %d is over %d milliseconds later than the first row of stream %s", i, MAX_RULE_TEST_DURATION, name)
+		}
+		data[name] = append(rows, &xsql.Tuple{Emitter: name, Message: sample.Data, Timestamp: sample.Timestamp})
+	}
+
+	store := kvstore.GetKvStore(path.Join(rootDbDir, "stream"))
+	if err := store.Open(); err != nil {
+		return RuleTestResult{}, err
+	}
+	samples := make([]*extensions.SampleSource, len(streams))
+	sources := make([]*nodes.SourceNode, len(streams))
+	for i, s := range streams {
+		streamStmt, err := getStream(store, s)
+		if err != nil {
+			store.Close()
+			return RuleTestResult{}, fmt.Errorf("fail to get stream %s, please check if stream is created", s)
+		}
+		var timestampField string
+		if rule.Options.IsEventTime {
+			timestampField = streamStmt.Options["TIMESTAMP"]
+		}
+		samples[i] = extensions.NewSampleSource(data[s], timestampField)
+		sources[i] = nodes.NewSourceNodeWithSource(s, samples[i], streamStmt.Options)
+	}
+	store.Close()
+
+	tp, inputs, err := rm.createTopoWithSources(rule, sources)
+	if err != nil {
+		return RuleTestResult{}, err
+	}
+	sink := &collectSink{}
+	tp.AddSink(inputs, nodes.NewSinkNodeWithSink("collectSink", sink, nil))
+	errCh := tp.Open()
+	defer func() {
+		tp.Cancel()
+		rm.deleteRule(rule)
+	}()
+
+	// Wait for all rows to be sent, then collect the outputs for a while
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	deadline := time.After(time.Duration(MAX_RULE_TEST_DURATION+wait) * time.Millisecond)
+	var done <-chan time.Time
+	for done == nil {
+		select {
+		case err := <-errCh:
+			return RuleTestResult{}, err
+		case <-deadline:
+			return RuleTestResult{}, fmt.Errorf("timeout to send the sample rows")
+		case <-ticker.C:
+			sent := true
+			for i, ss := range samples {
+				if ss.Sent() < len(data[streams[i]]) {
+					sent = false
+					break
+				}
+			}
+			if sent {
+				done = time.After(time.Duration(wait) * time.Millisecond)
+			}
+		}
+	}
+	select {
+	case err := <-errCh:
+		return RuleTestResult{}, err
+	case <-done:
+	}
+
+	result := RuleTestResult{Outputs: [][]map[string]interface{}{}}
+	for _, r := range sink.results() {
+		var rows []map[string]interface{}
+		if err := json.Unmarshal(r, &rows); err != nil {
+			return RuleTestResult{}, fmt.Errorf("invalid output %s: %s", r, err)
+		}
+		result.Outputs = append(result.Outputs, rows)
+	}
+	return result, nil
+}
+
+// collectSink keeps the outputs of a rule test.
+type collectSink struct {
+	outputs [][]byte
+	mutex   sync.Mutex
+}
+
+func (cs *collectSink) Configure(_ map[string]interface{}) error {
+	return nil
+}
+
+func (cs *collectSink) Open(_ api.StreamContext) error {
+	return nil
+}
+
+func (cs *collectSink) Collect(ctx api.StreamContext, item interface{}) error {
+	v, ok := item.([]byte)
+	if !ok {
+		ctx.GetLogger().Warnf("collect sink receive non []byte data: %v", item)
+		return nil
+	}
+	cs.mutex.Lock()
+	cs.outputs = append(cs.outputs, v)
+	cs.mutex.Unlock()
+	return nil
+}
+
+func (cs *collectSink) Close(_ api.StreamContext) error {
+	return nil
+}
+
+func (cs *collectSink) results() [][]byte {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	return cs.outputs
+}
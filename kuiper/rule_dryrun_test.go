@@ -0,0 +1,77 @@
+package kuiper
+
+import (
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/kvstore"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestRule(t *testing.T) {
+	defer setupKuiper(t)()
+	store := kvstore.GetKvStore(path.Join(rootDbDir, "stream"))
+	require.Nil(t, store.Open(), "unexpected error opening stream store")
+	require.Nil(t, store.Replace("demoet", `CREATE STREAM demoet () WITH (DATASOURCE="demoet", TYPE="memory", FORMAT="json", TIMESTAMP="ts")`), "unexpected error saving stream")
+	store.Close()
+
+	rm := newRuleManager(&ruleRepositoryMock{rules: make(map[string]Rule)})
+	samples := []RuleSample{
+		{Timestamp: 1100, Data: map[string]interface{}{"color": "red", "size": 3}},
+		{Timestamp: 1200, Data: map[string]interface{}{"color": "red", "size": 5}},
+		{Timestamp: 1300, Data: map[string]interface{}{"color": "blue", "size": 2}},
+		{Timestamp: 2100, Data: map[string]interface{}{"color": "red", "size": 1}},
+	}
+
+	cases := []struct {
+		desc    string
+		test    RuleTest
+		outputs [][]map[string]interface{}
+		err     error
+	}{
+		{
+			desc: "test rule with samples",
+			test: RuleTest{
+				SQL:     "SELECT color FROM demo WHERE size > 2",
+				Samples: samples,
+				Wait:    100,
+			},
+			outputs: [][]map[string]interface{}{
+				{{"color": "red"}},
+				{{"color": "red"}},
+			},
+		},
+		{
+			desc: "test event time rule with sample timestamps",
+			test: RuleTest{
+				SQL:     "SELECT color, count(*) AS c FROM demoet GROUP BY color, TUMBLINGWINDOW(ss, 1)",
+				Options: &api.RuleOption{IsEventTime: true, Concurrency: 1, BufferLength: 1024},
+				Samples: samples,
+				Wait:    100,
+			},
+			outputs: [][]map[string]interface{}{
+				{{"color": "red", "c": float64(2)}, {"color": "blue", "c": float64(1)}},
+			},
+		},
+		{
+			desc: "test rule with sample of unknown stream",
+			test: RuleTest{
+				SQL:     "SELECT color FROM demo",
+				Samples: []RuleSample{{Stream: "demo1", Data: map[string]interface{}{"color": "red"}}},
+			},
+			err: fmt.Errorf("stream demo1 of sample 0 is not used by the rule"),
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := rm.testRule(tc.test)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %v got %v", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, tc.outputs, res.Outputs, fmt.Sprintf("%s: expected outputs %v got %v", tc.desc, tc.outputs, res.Outputs))
+	}
+}
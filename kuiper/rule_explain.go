@@ -0,0 +1,235 @@
+package kuiper
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/cloustone/pandas/kuiper/kvstore"
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+// explainRule returns the logical plan of the rule. The topology is created
+// in the same way as running the rule but never opened, so the plan shows
+// exactly the operators the rule would run.
+func (rm *ruleManager) explainRule(rule *api.Rule) (RulePlan, error) {
+	selectStmt, err := getStatementFromSql(rule.Sql)
+	if err != nil {
+		return RulePlan{}, err
+	}
+	tp, _, err := rm.createTopo(rule)
+	if err != nil {
+		return RulePlan{}, err
+	}
+
+	store := kvstore.GetKvStore(path.Join(rootDbDir, "stream"))
+	if err := store.Open(); err != nil {
+		return RulePlan{}, err
+	}
+	defer store.Close()
+	streams := make(map[string]*xsql.StreamStmt)
+	for _, s := range xsql.GetStreams(selectStmt) {
+		if streams[s], err = getStream(store, s); err != nil {
+			return RulePlan{}, fmt.Errorf("fail to get stream %s, please check if stream is created", s)
+		}
+	}
+
+	topo := tp.GetTopo()
+	inputs := make(map[string][]string)
+	for from, tos := range topo.Edges {
+		for _, to := range tos {
+			inputs[to] = append(inputs[to], from)
+		}
+	}
+	for _, ins := range inputs {
+		sort.Strings(ins)
+	}
+
+	// Visit the nodes after all of their inputs
+	plan := RulePlan{}
+	visited := make(map[string]bool)
+	queue := append([]string{}, topo.Sources...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		ready := true
+		for _, in := range inputs[name] {
+			if !visited[in] {
+				ready = false
+				break
+			}
+		}
+		if !ready {
+			continue
+		}
+		visited[name] = true
+		plan.Operators = append(plan.Operators, PlanOperator{
+			Name:     name,
+			Inputs:   inputs[name],
+			Settings: operatorSettings(name, selectStmt, streams, rule.Options),
+		})
+		queue = append(queue, topo.Edges[name]...)
+	}
+	return plan, nil
+}
+
+// operatorSettings describes the settings of the source or operator named
+// as in RuleTopo.
+func operatorSettings(name string, stmt *xsql.SelectStatement, streams map[string]*xsql.StreamStmt, opt *api.RuleOption) map[string]interface{} {
+	if strings.HasPrefix(name, "source_") {
+		s := streams[strings.TrimPrefix(name, "source_")]
+		t, ok := s.Options["TYPE"]
+		if !ok {
+			t = "mqtt"
+		}
+		return map[string]interface{}{
+			"kind":       xsql.StreamTypeMap[s.StreamType],
+			"type":       t,
+			"datasource": s.Options["DATASOURCE"],
+			"options":    s.Options,
+		}
+	}
+
+	op := strings.TrimPrefix(name, "op_")
+	if strings.HasPrefix(op, "preprocessor_") {
+		s := streams[strings.TrimPrefix(op, "preprocessor_")]
+		fields := make([]string, len(s.StreamFields))
+		for i, f := range s.StreamFields {
+			fields[i] = f.Name + " " + xsql.PrintFieldType(f.FieldType)
+		}
+		settings := map[string]interface{}{
+			"schemaless":  len(s.StreamFields) == 0,
+			"fields":      fields,
+			"isEventTime": opt.IsEventTime,
+		}
+		if ts, ok := s.Options["TIMESTAMP"]; ok {
+			settings["timestamp"] = ts
+		}
+		return settings
+	}
+
+	switch op {
+	case "windowFilter":
+		return map[string]interface{}{"condition": xsql.PrintExpr(stmt.Dimensions.GetWindow().Filter)}
+	case "window":
+		w := stmt.Dimensions.GetWindow()
+		settings := map[string]interface{}{
+			"type":        xsql.WindowTypeMap[w.WindowType],
+			"length":      w.Length.Val,
+			"isEventTime": opt.IsEventTime,
+		}
+		if w.Interval != nil && w.Interval.Val > 0 {
+			settings["interval"] = w.Interval.Val
+		}
+		if opt.IsEventTime {
+			settings["lateTolerance"] = opt.LateTol
+			if opt.AllowedLateness > 0 {
+				settings["allowedLateness"] = opt.AllowedLateness
+			}
+		}
+		return settings
+	case "join_aligner":
+		var tables []string
+		for name, s := range streams {
+			if s.StreamType == xsql.TypeTable {
+				tables = append(tables, name)
+			}
+		}
+		sort.Strings(tables)
+		return map[string]interface{}{"tables": tables}
+	case "join":
+		joins := make([]map[string]interface{}, len(stmt.Joins))
+		for i, j := range stmt.Joins {
+			joins[i] = map[string]interface{}{
+				"name":  j.Name,
+				"alias": j.Alias,
+				"type":  xsql.JoinTypeMap[j.JoinType],
+				"on":    xsql.PrintExpr(j.Expr),
+			}
+		}
+		return map[string]interface{}{"from": stmt.Sources[0].(*xsql.Table).Name, "joins": joins}
+	case "match":
+		mr := stmt.MatchRecognize
+		partitionBy := make([]string, len(mr.PartitionBy))
+		for i, e := range mr.PartitionBy {
+			partitionBy[i] = xsql.PrintExpr(e)
+		}
+		pattern := make([]string, len(mr.Pattern))
+		for i, e := range mr.Pattern {
+			pattern[i] = printPatternElement(e)
+		}
+		defines := make(map[string]string, len(mr.Defines))
+		for _, d := range mr.Defines {
+			defines[d.Variable] = xsql.PrintExpr(d.Condition)
+		}
+		return map[string]interface{}{
+			"partitionBy": partitionBy,
+			"measures":    printFields(mr.Measures),
+			"pattern":     pattern,
+			"within":      mr.Within,
+			"defines":     defines,
+		}
+	case "filter":
+		return map[string]interface{}{"condition": xsql.PrintExpr(stmt.Condition)}
+	case "aggregate":
+		var dimensions []string
+		for _, d := range stmt.Dimensions.GetGroups() {
+			dimensions = append(dimensions, xsql.PrintExpr(d.Expr))
+		}
+		return map[string]interface{}{"dimensions": dimensions}
+	case "having":
+		return map[string]interface{}{"condition": xsql.PrintExpr(stmt.Having)}
+	case "order":
+		sortFields := make([]string, len(stmt.SortFields))
+		for i, f := range stmt.SortFields {
+			if f.Ascending {
+				sortFields[i] = f.Name + " ASC"
+			} else {
+				sortFields[i] = f.Name + " DESC"
+			}
+		}
+		return map[string]interface{}{"sortFields": sortFields}
+	case "project":
+		return map[string]interface{}{
+			"fields":         printFields(stmt.Fields),
+			"isAggregate":    xsql.IsAggStatement(stmt),
+			"sendMetaToSink": opt.SendMetaToSink,
+		}
+	}
+	return nil
+}
+
+func printFields(fields xsql.Fields) []string {
+	result := make([]string, len(fields))
+	for i, f := range fields {
+		result[i] = xsql.PrintExpr(f.Expr)
+		if f.AName != "" {
+			result[i] += " AS " + f.AName
+		}
+	}
+	return result
+}
+
+func printPatternElement(e *xsql.PatternElement) string {
+	switch {
+	case e.Min == 1 && e.Max == 1:
+		return e.Variable
+	case e.Min == 0 && e.Max < 0:
+		return e.Variable + "*"
+	case e.Min == 1 && e.Max < 0:
+		return e.Variable + "+"
+	case e.Min == 0 && e.Max == 1:
+		return e.Variable + "?"
+	case e.Min == e.Max:
+		return fmt.Sprintf("%s{%d}", e.Variable, e.Min)
+	case e.Max < 0:
+		return fmt.Sprintf("%s{%d,}", e.Variable, e.Min)
+	default:
+		return fmt.Sprintf("%s{%d,%d}", e.Variable, e.Min, e.Max)
+	}
+}
@@ -1,7 +1,6 @@
 package kuiper
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,10 +12,11 @@ import (
 	"github.com/cloustone/pandas/kuiper/kvstore"
 	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xsql"
-	"github.com/cloustone/pandas/kuiper/xsql/plans"
+	"github.com/cloustone/pandas/kuiper/xsql/processors"
 	"github.com/cloustone/pandas/kuiper/xstream"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
 	"github.com/cloustone/pandas/kuiper/xstream/nodes"
+	"github.com/cloustone/pandas/kuiper/xstream/states"
 )
 
 var (
@@ -42,6 +42,7 @@ func (rm *ruleManager) createRuleState(rule *api.Rule) (*ruleState, error) {
 	}
 	rm.registry.store(rule.Id, rs)
 	if tp, err := rm.execInitRule(rule); err != nil {
+		rs.err = err
 		return rs, err
 	} else {
 		rs.topology = tp
@@ -87,134 +88,7 @@ func (rm *ruleManager) createTopo(rule *api.Rule) (*xstream.TopologyNew, []api.E
 }
 
 func (rm *ruleManager) createTopoWithSources(rule *api.Rule, sources []*nodes.SourceNode) (*xstream.TopologyNew, []api.Emitter, error) {
-	name := rule.Id
-	sql := rule.Sql
-
-	// log.Infof("Init rule with options %+v", rule.Options)
-	shouldCreateSource := sources == nil
-
-	if selectStmt, err := getStatementFromSql(sql); err != nil {
-		return nil, nil, err
-	} else {
-		tp, err := xstream.NewWithNameAndQos(name, rule.Options.Qos, rule.Options.CheckpointInterval)
-		if err != nil {
-			return nil, nil, err
-		}
-		var inputs []api.Emitter
-		streamsFromStmt := xsql.GetStreams(selectStmt)
-		dimensions := selectStmt.Dimensions
-		if !shouldCreateSource && len(streamsFromStmt) != len(sources) {
-			return nil, nil, fmt.Errorf("Invalid parameter sources or streams, the length cannot match the statement, expect %d sources.", len(streamsFromStmt))
-		}
-		if rule.Options.SendMetaToSink && (len(streamsFromStmt) > 1 || dimensions != nil) {
-			return nil, nil, fmt.Errorf("Invalid option sendMetaToSink, it can not be applied to window")
-		}
-		store := kvstore.GetKvStore(path.Join(rootDbDir, "stream"))
-		err = store.Open()
-		if err != nil {
-			return nil, nil, err
-		}
-		defer store.Close()
-
-		var alias, aggregateAlias xsql.Fields
-		for _, f := range selectStmt.Fields {
-			if f.AName != "" {
-				if !xsql.HasAggFuncs(f.Expr) {
-					alias = append(alias, f)
-				} else {
-					aggregateAlias = append(aggregateAlias, f)
-				}
-			}
-		}
-		for i, s := range streamsFromStmt {
-			streamStmt, err := getStream(store, s)
-			if err != nil {
-				return nil, nil, fmt.Errorf("fail to get stream %s, please check if stream is created", s)
-			}
-			pp, err := plans.NewPreprocessor(streamStmt, alias, rule.Options.IsEventTime)
-			if err != nil {
-				return nil, nil, err
-			}
-			var srcNode *nodes.SourceNode
-			if shouldCreateSource {
-				node := nodes.NewSourceNode(s, streamStmt.Options)
-				srcNode = node
-			} else {
-				srcNode = sources[i]
-			}
-			tp.AddSrc(srcNode)
-			preprocessorOp := xstream.Transform(pp, "preprocessor_"+s, rule.Options.BufferLength)
-			preprocessorOp.SetConcurrency(rule.Options.Concurrency)
-			tp.AddOperator([]api.Emitter{srcNode}, preprocessorOp)
-			inputs = append(inputs, preprocessorOp)
-		}
-
-		var w *xsql.Window
-		if dimensions != nil {
-			w = dimensions.GetWindow()
-			if w != nil {
-				if w.Filter != nil {
-					wfilterOp := xstream.Transform(&plans.FilterPlan{Condition: w.Filter}, "windowFilter", rule.Options.BufferLength)
-					wfilterOp.SetConcurrency(rule.Options.Concurrency)
-					tp.AddOperator(inputs, wfilterOp)
-					inputs = []api.Emitter{wfilterOp}
-				}
-				wop, err := nodes.NewWindowOp("window", w, rule.Options.IsEventTime, rule.Options.LateTol, streamsFromStmt, rule.Options.BufferLength)
-				if err != nil {
-					return nil, nil, err
-				}
-				tp.AddOperator(inputs, wop)
-				inputs = []api.Emitter{wop}
-			}
-		}
-
-		if w != nil && selectStmt.Joins != nil {
-			joinOp := xstream.Transform(&plans.JoinPlan{Joins: selectStmt.Joins, From: selectStmt.Sources[0].(*xsql.Table)}, "join", rule.Options.BufferLength)
-			joinOp.SetConcurrency(rule.Options.Concurrency)
-			tp.AddOperator(inputs, joinOp)
-			inputs = []api.Emitter{joinOp}
-		}
-
-		if selectStmt.Condition != nil {
-			filterOp := xstream.Transform(&plans.FilterPlan{Condition: selectStmt.Condition}, "filter", rule.Options.BufferLength)
-			filterOp.SetConcurrency(rule.Options.Concurrency)
-			tp.AddOperator(inputs, filterOp)
-			inputs = []api.Emitter{filterOp}
-		}
-
-		var ds xsql.Dimensions
-		if dimensions != nil || len(aggregateAlias) > 0 {
-			ds = dimensions.GetGroups()
-			if (ds != nil && len(ds) > 0) || len(aggregateAlias) > 0 {
-				aggregateOp := xstream.Transform(&plans.AggregatePlan{Dimensions: ds, Alias: aggregateAlias}, "aggregate", rule.Options.BufferLength)
-				aggregateOp.SetConcurrency(rule.Options.Concurrency)
-				tp.AddOperator(inputs, aggregateOp)
-				inputs = []api.Emitter{aggregateOp}
-			}
-		}
-
-		if selectStmt.Having != nil {
-			havingOp := xstream.Transform(&plans.HavingPlan{selectStmt.Having}, "having", rule.Options.BufferLength)
-			havingOp.SetConcurrency(rule.Options.Concurrency)
-			tp.AddOperator(inputs, havingOp)
-			inputs = []api.Emitter{havingOp}
-		}
-
-		if selectStmt.SortFields != nil {
-			orderOp := xstream.Transform(&plans.OrderPlan{SortFields: selectStmt.SortFields}, "order", rule.Options.BufferLength)
-			orderOp.SetConcurrency(rule.Options.Concurrency)
-			tp.AddOperator(inputs, orderOp)
-			inputs = []api.Emitter{orderOp}
-		}
-
-		if selectStmt.Fields != nil {
-			projectOp := xstream.Transform(&plans.ProjectPlan{Fields: selectStmt.Fields, IsAggregate: xsql.IsAggStatement(selectStmt), SendMeta: rule.Options.SendMetaToSink}, "project", rule.Options.BufferLength)
-			projectOp.SetConcurrency(rule.Options.Concurrency)
-			tp.AddOperator(inputs, projectOp)
-			inputs = []api.Emitter{projectOp}
-		}
-		return tp, inputs, nil
-	}
+	return processors.CreateTopoWithSources(rootDbDir, rule, sources)
 }
 
 func (rm *ruleManager) doStartRule(rs *ruleState) error {
@@ -268,6 +142,9 @@ func (rm *ruleManager) doGetruleState(rs *ruleState) (string, error) {
 	result := ""
 	if !rs.triggered {
 		result = "Stopped: canceled manually or by error."
+		if rs.err != nil {
+			result = fmt.Sprintf("Stopped: %v.", rs.err)
+		}
 		return result, nil
 	}
 	c := (*rs.topology).GetContext()
@@ -289,50 +166,57 @@ func (rm *ruleManager) doGetruleState(rs *ruleState) (string, error) {
 	return result, nil
 }
 
-func (rm *ruleManager) getRuleStatus(_ context.Context, token string, name string) (string, error) {
-	if rs, ok := rm.registry.load(name); ok {
-		result, err := rm.doGetruleState(rs)
-		if err != nil {
-			return "", err
+func (rm *ruleManager) getRuleStatus(name string) (RuleStatus, error) {
+	rs, ok := rm.registry.load(name)
+	if !ok {
+		return RuleStatus{ID: name, State: "Stopped: not started."}, nil
+	}
+	result, err := rm.doGetruleState(rs)
+	if err != nil {
+		return RuleStatus{}, err
+	}
+	status := RuleStatus{ID: name, State: result}
+	if result != "Running" {
+		return status, nil
+	}
+	for _, m := range rs.topology.GetNodeMetrics() {
+		metrics := NodeMetrics{
+			Type:     m.Type,
+			Name:     m.Name,
+			Instance: m.Instance,
 		}
-		if result == "Running" {
-			keys, values := (*rs.topology).GetMetrics()
-			metrics := "{"
-			for i, key := range keys {
-				value := values[i]
-				switch value.(type) {
-				case string:
-					metrics += fmt.Sprintf("\"%s\":%q,", key, value)
-				default:
-					metrics += fmt.Sprintf("\"%s\":%v,", key, value)
-				}
-			}
-			metrics = metrics[:len(metrics)-1] + "}"
-			dst := &bytes.Buffer{}
-			if err = json.Indent(dst, []byte(metrics), "", "  "); err != nil {
-				result = metrics
-			} else {
-				result = dst.String()
+		for i, v := range m.Values {
+			switch nodes.MetricNames[i] {
+			case nodes.RecordsInTotal:
+				metrics.RecordsInTotal, _ = v.(int64)
+			case nodes.RecordsOutTotal:
+				metrics.RecordsOutTotal, _ = v.(int64)
+			case nodes.ExceptionsTotal:
+				metrics.ExceptionsTotal, _ = v.(int64)
+			case nodes.ProcessLatencyMs:
+				metrics.ProcessLatencyMs, _ = v.(int64)
+			case nodes.BufferLength:
+				metrics.BufferLength, _ = v.(int64)
+			case nodes.LastInvocation:
+				metrics.LastInvocation, _ = v.(string)
+			case nodes.LateRecordsTotal:
+				metrics.LateRecordsTotal, _ = v.(int64)
+			case nodes.LateDroppedTotal:
+				metrics.LateDroppedTotal, _ = v.(int64)
 			}
 		}
-		return result, nil
-	} else {
-		return "", util.NewErrorWithCode(util.NOT_FOUND, fmt.Sprintf("Rule %s is not found", name))
+		status.Metrics = append(status.Metrics, metrics)
 	}
+	return status, nil
 }
 
-func (rm *ruleManager) getRuleTopo(_ context.Context, token string, name string) (string, error) {
-	if rs, ok := rm.registry.load(name); ok {
-		topo := rs.topology.GetTopo()
-		bytes, err := json.Marshal(topo)
-		if err != nil {
-			return "", util.NewError(fmt.Sprintf("Fail to encode rule %s's topo", name))
-		} else {
-			return string(bytes), nil
-		}
-	} else {
-		return "", util.NewErrorWithCode(util.NOT_FOUND, fmt.Sprintf("Rule %s is not found", name))
+func (rm *ruleManager) getRuleTopo(name string) (RuleTopo, error) {
+	rs, ok := rm.registry.load(name)
+	if !ok || rs.topology == nil {
+		return RuleTopo{}, ErrNotFound
 	}
+	topo := rs.topology.GetTopo()
+	return RuleTopo{Sources: topo.Sources, Edges: topo.Edges}, nil
 }
 
 func (rm *ruleManager) startRule(r *api.Rule) error {
@@ -349,9 +233,22 @@ func (rm *ruleManager) startRule(r *api.Rule) error {
 	return rm.doStartRule(rs)
 }
 
-func (rm *ruleManager) getRuleByName(name string) (*api.Rule, error) {
-	// p.getRuleByJson(name, s1)
-	return nil, nil //TODO
+func (rm *ruleManager) isRunning(name string) bool {
+	rs, ok := rm.registry.load(name)
+	return ok && rs.triggered
+}
+
+// runningRules returns the names of the rules started on this instance
+func (rm *ruleManager) runningRules() []string {
+	rm.registry.RLock()
+	defer rm.registry.RUnlock()
+	var names []string
+	for name, rs := range rm.registry.internal {
+		if rs.triggered {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
 func (rm *ruleManager) stopRule(r *api.Rule) (err error) {
@@ -359,7 +256,6 @@ func (rm *ruleManager) stopRule(r *api.Rule) (err error) {
 		(*rs.topology).Cancel()
 		rs.triggered = false
 		//rm.ruleProcessor.ExecReplaceruleState(name, false)
-		err = fmt.Errorf("Rule %s was stopped.", r.Id)
 	} else {
 		err = fmt.Errorf("Rule %s was not found.", r.Id)
 	}
@@ -371,26 +267,33 @@ func (rm *ruleManager) restartRule(r *api.Rule) error {
 	return rm.startRule(r)
 }
 
-func (rm *ruleManager) recoverRule(name string) string {
-	rule, err := rm.getRuleByName(name)
+// recoverRule brings the persisted rule back into the registry after the
+// service restarted. Rules whose desired state is running are started
+// again, checkpoints are restored for rules with qos >= AtLeastOnce.
+func (rm *ruleManager) recoverRule(r Rule) error {
+	rule, err := rm.getRuleByJson(r.Name, r.SQL)
 	if err != nil {
-		return fmt.Sprintf("%v", err)
+		// The status of the rule shows the error
+		rm.registry.store(r.Name, &ruleState{name: r.Name, err: err})
+		return err
 	}
 
-	if !rule.Triggered {
-		rs := &ruleState{
-			name: name,
-		}
-		rm.registry.store(name, rs)
-		return fmt.Sprintf("Rule %s was stoped.", name)
+	if r.State != RuleRunning {
+		rm.registry.store(rule.Id, &ruleState{name: rule.Id})
+		return nil
 	}
 
-	// TODO err = rm.startRule(name)
-	if err != nil {
-		return fmt.Sprintf("%v", err)
+	if rule.Options.Qos >= api.AtLeastOnce {
+		// The topology restores the checkpoints when opened, check them
+		// first so that corrupted data is reported for this rule at once
+		if _, err := states.CreateStore(rule.Id, rule.Options.Qos); err != nil {
+			err = fmt.Errorf("restore checkpoints error: %s", err)
+			rm.registry.store(rule.Id, &ruleState{name: rule.Id, err: err})
+			return err
+		}
 	}
-	return fmt.Sprintf("Rule %s was started.", name)
 
+	return rm.startRule(rule)
 }
 
 func getStatementFromSql(sql string) (*xsql.SelectStatement, error) {
@@ -451,6 +354,9 @@ func (rm *ruleManager) buileRuleByJson(name, ruleJson string) (*api.Rule, error)
 	if rule.Options.LateTol < 0 {
 		return nil, fmt.Errorf("rule option lateTolerance %d is invalid, require a positive integer", rule.Options.LateTol)
 	}
+	if rule.Options.AllowedLateness < 0 {
+		return nil, fmt.Errorf("rule option allowedLateness %d is invalid, require a positive integer", rule.Options.AllowedLateness)
+	}
 	return rule, nil
 }
 
@@ -532,6 +438,9 @@ func (rm *ruleManager) getRuleByJson(name, ruleJson string) (*api.Rule, error) {
 	if rule.Options.LateTol < 0 {
 		return nil, fmt.Errorf("rule option lateTolerance %d is invalid, require a positive integer", rule.Options.LateTol)
 	}
+	if rule.Options.AllowedLateness < 0 {
+		return nil, fmt.Errorf("rule option allowedLateness %d is invalid, require a positive integer", rule.Options.AllowedLateness)
+	}
 	return rule, nil
 }
 
@@ -562,7 +471,13 @@ func cleanSinkCache(rule *api.Rule) error {
 		return err
 	}
 	defer store.Close()
-	for d, m := range rule.Actions {
+	deleteSinkCache(store, rule.Id, "", rule.Actions)
+	deleteSinkCache(store, rule.Id, "late_", rule.LateActions)
+	return nil
+}
+
+func deleteSinkCache(store kvstore.KvStore, ruleId string, prefix string, actions []map[string]interface{}) {
+	for d, m := range actions {
 		con := 1
 		for name, action := range m {
 			props, _ := action.(map[string]interface{})
@@ -572,11 +487,10 @@ func cleanSinkCache(rule *api.Rule) error {
 				}
 			}
 			for i := 0; i < con; i++ {
-				key := fmt.Sprintf("%s%s_%d%d", rule.Id, name, d, i)
+				key := fmt.Sprintf("%s%s%s_%d%d", ruleId, prefix, name, d, i)
 				util.Log.Debugf("delete cache key %s", key)
 				store.Delete(key)
 			}
 		}
 	}
-	return nil
 }
@@ -0,0 +1,179 @@
+package kuiper
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const invalidRuleSQL = `{"id": "%s", "sql": "SELECT * FROM unknown", "actions": [{"log": {}}]}`
+
+func TestRecoverRule(t *testing.T) {
+	defer setupKuiper(t)()
+	ks, _, _ := newLeaseService()
+
+	cases := []struct {
+		desc    string
+		rule    Rule
+		err     bool
+		running bool
+		state   string
+	}{
+		{
+			desc: "recover running rule",
+			rule: Rule{
+				ID:    "1",
+				Name:  "rule1",
+				SQL:   `{"id": "rule1", "sql": "SELECT * FROM demo", "actions": [{"log": {}}]}`,
+				State: RuleRunning,
+			},
+			running: true,
+			state:   "Running",
+		},
+		{
+			desc: "recover stopped rule",
+			rule: Rule{
+				ID:    "2",
+				Name:  "rule2",
+				SQL:   `{"id": "rule2", "sql": "SELECT * FROM demo", "actions": [{"log": {}}]}`,
+				State: RuleStopped,
+			},
+			running: false,
+			state:   "Stopped: canceled manually or by error.",
+		},
+		{
+			desc: "recover rule of invalid sql",
+			rule: Rule{
+				ID:    "3",
+				Name:  "rule3",
+				SQL:   `{"id": "rule3", "sql": "SELECT FROM", "actions": [{"log": {}}]}`,
+				State: RuleRunning,
+			},
+			err:     true,
+			running: false,
+		},
+		{
+			desc: "recover rule of unknown stream",
+			rule: Rule{
+				ID:    "4",
+				Name:  "rule4",
+				SQL:   fmt.Sprintf(invalidRuleSQL, "rule4"),
+				State: RuleRunning,
+			},
+			err:     true,
+			running: false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := ks.ruleManager.recoverRule(tc.rule)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", tc.desc, tc.err, err))
+		running := ks.ruleManager.isRunning(tc.rule.Name)
+		assert.Equal(t, tc.running, running, fmt.Sprintf("%s: expected running %t got %t", tc.desc, tc.running, running))
+		if running {
+			waitOpened(t, ks, tc.rule.Name)
+		}
+		// The status shows the error of the failed rule
+		state := tc.state
+		if err != nil {
+			state = fmt.Sprintf("Stopped: %s.", err)
+		}
+		status, err := ks.ruleManager.getRuleStatus(tc.rule.Name)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, state, status.State, fmt.Sprintf("%s: expected state %s got %s", tc.desc, state, status.State))
+		if running {
+			ks.ruleManager.stopRule(&api.Rule{Id: tc.rule.Name})
+		}
+	}
+}
+
+func TestRecoverRules(t *testing.T) {
+	defer setupKuiper(t)()
+	ks, rules, leases := newLeaseService()
+
+	valid := Rule{
+		Owner: "user@example.com",
+		ID:    "1",
+		Name:  "rule1",
+		SQL:   `{"id": "rule1", "sql": "SELECT * FROM demo", "actions": [{"log": {}}]}`,
+		State: RuleRunning,
+	}
+	invalid := Rule{
+		Owner: "user@example.com",
+		ID:    "2",
+		Name:  "rule2",
+		SQL:   `{"id": "rule2", "sql": "SELECT FROM", "actions": [{"log": {}}]}`,
+		State: RuleRunning,
+	}
+	other := Rule{
+		Owner: "user@example.com",
+		ID:    "3",
+		Name:  "rule3",
+		SQL:   `{"id": "rule3", "sql": "SELECT * FROM demo", "actions": [{"log": {}}]}`,
+		State: RuleRunning,
+	}
+	stopped := Rule{
+		Owner: "user@example.com",
+		ID:    "4",
+		Name:  "rule4",
+		SQL:   fmt.Sprintf(invalidRuleSQL, "rule4"),
+		State: RuleStopped,
+	}
+	_, err := rules.Save(context.Background(), valid, invalid, other, stopped)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	leases.set(other.ID, "instance-b", nil)
+
+	failed := ks.recoverRules(context.Background())
+	assert.Equal(t, 1, len(failed), fmt.Sprintf("expected 1 failed rule got %v", failed))
+	assert.NotNil(t, failed[invalid.ID], fmt.Sprintf("expected rule %s failed got %v", invalid.ID, failed))
+
+	cases := []struct {
+		desc    string
+		rule    Rule
+		running bool
+		state   string
+	}{
+		{
+			desc:    "recover valid rule",
+			rule:    valid,
+			running: true,
+			state:   "Running",
+		},
+		{
+			desc:    "show error of failed rule",
+			rule:    invalid,
+			running: false,
+			state:   fmt.Sprintf("Stopped: %s.", failed[invalid.ID]),
+		},
+		{
+			desc:    "skip rule held by another instance",
+			rule:    other,
+			running: false,
+			state:   "Stopped: not started.",
+		},
+		{
+			desc:    "skip stopped rule",
+			rule:    stopped,
+			running: false,
+			state:   "Stopped: not started.",
+		},
+	}
+
+	for _, tc := range cases {
+		running := ks.ruleManager.isRunning(tc.rule.Name)
+		assert.Equal(t, tc.running, running, fmt.Sprintf("%s: expected running %t got %t", tc.desc, tc.running, running))
+		if running {
+			waitOpened(t, ks, tc.rule.Name)
+		}
+		status, err := ks.ruleManager.getRuleStatus(tc.rule.Name)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.state, status.State, fmt.Sprintf("%s: expected state %s got %s", tc.desc, tc.state, status.State))
+		if running {
+			ks.ruleManager.stopRule(&api.Rule{Id: tc.rule.Name})
+		}
+	}
+}
@@ -10,6 +10,7 @@ type ruleState struct {
 	name      string
 	topology  *xstream.TopologyNew
 	triggered bool
+	err       error
 }
 type ruleRegistry struct {
 	sync.RWMutex
@@ -2,14 +2,25 @@ package kuiper
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
 	"path"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/cloustone/pandas/kuiper/plugins"
+	"github.com/cloustone/pandas/kuiper/templates"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xsql"
 	"github.com/cloustone/pandas/kuiper/xsql/processors"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
 	"github.com/cloustone/pandas/mainflux"
 )
 
@@ -85,19 +96,80 @@ type Service interface {
 	// that belongs to the user
 	RestartRule(context.Context, string, string) error
 
-	// ListPluginSources retrieves data about subset of plugins sources
-	ListPluginSources(context.Context, string, uint64, uint64, string, Metadata) (PluginSourcesPage, error)
+	// ViewRuleStatus retrieves running state and per node metrics of the rule
+	// identified with the provided ID, that belongs to the user
+	ViewRuleStatus(context.Context, string, string) (RuleStatus, error)
 
-	// ViewPluginSource retrieves data about the plugin source identified with the provided
-	// ID.
-	ViewPluginSource(context.Context, string, string) (PluginSource, error)
+	// ViewRuleTopo retrieves topology of the rule identified with the provided
+	// ID, that belongs to the user
+	ViewRuleTopo(context.Context, string, string) (RuleTopo, error)
 
-	// ListPluginSinks retrieves data about subset of plugins sinks
-	ListPluginSinks(context.Context, string, uint64, uint64, string, Metadata) (PluginSinksPage, error)
+	// ExplainRule returns the logical plan of the EXPLAIN SELECT statement
+	// without creating a rule.
+	ExplainRule(context.Context, string, string) (RulePlan, error)
 
-	// ViewPluginSink retrieves data about the plugin sink identified with the provided
-	// ID.
-	ViewPluginSink(context.Context, string, string) (PluginSink, error)
+	// TestRule runs the SQL against the sample rows in a throwaway topology
+	// and returns the outputs, no rule is created.
+	TestRule(context.Context, string, RuleTest) (RuleTestResult, error)
+
+	// InstallPlugin downloads the plugin zip file from the provided url and
+	// installs it for the user identified by the provided key. The plugin is
+	// usable in the rules of all users.
+	InstallPlugin(context.Context, string, Plugin) (Plugin, error)
+
+	// UploadPlugin installs the plugin from the uploaded zip file for the
+	// user identified by the provided key.
+	UploadPlugin(context.Context, string, Plugin, []byte) (Plugin, error)
+
+	// RemovePlugin uninstalls the plugin identified with the provided type
+	// and name, that belongs to the user identified by the provided key.
+	RemovePlugin(context.Context, string, PluginType, string) error
+
+	// ViewPlugin retrieves the plugin identified with the provided type and
+	// name along with its metadata in the provided language.
+	ViewPlugin(context.Context, string, PluginType, string, string) (Plugin, error)
+
+	// ListPlugins retrieves subset of built-in plugins and plugins installed
+	// by the user identified by the provided key, along with their metadata
+	// in the provided language.
+	ListPlugins(context.Context, string, PluginType, uint64, uint64, string, string) (PluginsPage, error)
+
+	// CreateFunction defines the function of the CREATE FUNCTION statement
+	// for the user identified by the provided key. The function is usable
+	// in the rules of all users.
+	CreateFunction(context.Context, string, string) (Function, error)
+
+	// ViewFunction retrieves the function identified with the provided name,
+	// that belongs to the user identified by the provided key.
+	ViewFunction(context.Context, string, string) (Function, error)
+
+	// ListFunctions retrieves the functions that belong to the user
+	// identified by the provided key.
+	ListFunctions(context.Context, string) ([]Function, error)
+
+	// RemoveFunction removes the function identified with the provided name,
+	// that belongs to the user identified by the provided key.
+	RemoveFunction(context.Context, string, string) error
+
+	// CreateRuleTemplate adds the rule template to the user identified by
+	// the provided key.
+	CreateRuleTemplate(context.Context, string, RuleTemplate) (RuleTemplate, error)
+
+	// ViewRuleTemplate retrieves the rule template identified with the
+	// provided name, that belongs to the user identified by the provided key.
+	ViewRuleTemplate(context.Context, string, string) (RuleTemplate, error)
+
+	// ListRuleTemplates retrieves the rule templates that belong to the user
+	// identified by the provided key.
+	ListRuleTemplates(context.Context, string) ([]RuleTemplate, error)
+
+	// RemoveRuleTemplate removes the rule template identified with the
+	// provided name, that belongs to the user identified by the provided key.
+	RemoveRuleTemplate(context.Context, string, string) error
+
+	// InstantiateRuleTemplate creates a rule of the provided name from the
+	// rule template identified with the provided name and the parameters.
+	InstantiateRuleTemplate(context.Context, string, string, string, map[string]interface{}) (Rule, error)
 }
 
 // PageMetadata contains page metadata that helps navigation.
@@ -115,34 +187,276 @@ type kuiperService struct {
 	idp             IdentityProvider
 	streams         StreamRepository
 	rules           RuleRepository
+	pluginRepo      PluginRepository
 	streamCache     StreamCache
 	ruleCache       RuleCache
 	ruleProcessor   *processors.RuleProcessor
 	streamProcessor *processors.StreamProcessor
 	pluginManager   *plugins.Manager
 	ruleManager     *ruleManager
+	leases          RuleLeaseRepository
+	instance        string
+	functions       FunctionRepository
+	templates       RuleTemplateRepository
+
+	// The time of the last renewal of the leases held by this instance,
+	// indexed by the rule ID.
+	renewed map[string]time.Time
+	leaseMu sync.Mutex
+
+	// The SQL of the user defined functions registered by this instance,
+	// indexed by the name.
+	loadedFuncs map[string]string
+	funcMu      sync.Mutex
 }
 
-// New instantiates the things service implementation.
-func New(auth mainflux.AuthNServiceClient, streams StreamRepository, rules RuleRepository,
-	scache StreamCache, rcache RuleCache, idp IdentityProvider, pluginManager *plugins.Manager) Service {
+// The lease of a rule is renewed every third of the ttl by the instance
+// running it. Another instance takes over the rule after the ttl if the
+// instance fails.
+const ruleLeaseTTL = 15 * time.Second
+
+// New instantiates the things service implementation. If the leases are
+// provided, the rules are shared by the instances of the service identified
+// by the instance names.
+func New(auth mainflux.AuthNServiceClient, streams StreamRepository, rules RuleRepository, pluginRepo PluginRepository,
+	scache StreamCache, rcache RuleCache, idp IdentityProvider, pluginManager *plugins.Manager, leases RuleLeaseRepository, instance string,
+	functions FunctionRepository, ruleTemplates RuleTemplateRepository) Service {
 	dataDir := "./"
 	pluginManager, err := plugins.NewPluginManager()
 	if err != nil {
 		panic(err)
 	}
-	return &kuiperService{
+	ks := &kuiperService{
 		auth:            auth,
 		idp:             idp,
 		streams:         streams,
 		rules:           rules,
+		pluginRepo:      pluginRepo,
 		streamCache:     scache,
 		ruleCache:       rcache,
 		streamProcessor: processors.NewStreamProcessor(path.Join(path.Dir(dataDir), "stream")),
 		pluginManager:   pluginManager,
 		ruleManager:     newRuleManager(rules),
+		leases:          leases,
+		instance:        instance,
+		renewed:         make(map[string]time.Time),
+		functions:       functions,
+		templates:       ruleTemplates,
+		loadedFuncs:     make(map[string]string),
+	}
+	// The rules may call the user defined functions
+	ks.syncFunctions(context.Background())
+	if failed := ks.recoverRules(context.Background()); len(failed) > 0 {
+		util.Log.Warnf("Failed to recover %d rules, their status shows the errors", len(failed))
+	}
+	if leases != nil {
+		go ks.keepLeases()
+	}
+	return ks
+}
+
+// recoverRules restarts the rules which were running before the service
+// stopped. The returned map holds the startup error of each failed rule, which
+// is shown in the status of the rule as well.
+func (ks *kuiperService) recoverRules(ctx context.Context) map[string]error {
+	failed := map[string]error{}
+	rules, err := ks.rules.RetrieveByState(ctx, RuleRunning)
+	if err != nil {
+		util.Log.Errorf("Failed to retrieve rules to recover: %s", err)
+		return failed
+	}
+	for _, r := range rules {
+		if held, err := ks.acquireRule(ctx, r.ID); err != nil || !held {
+			if err != nil {
+				util.Log.Errorf("Failed to acquire the lease of rule %s of %s: %s", r.ID, r.Owner, err)
+				failed[r.ID] = err
+			}
+			continue
+		}
+		if err := ks.ruleManager.recoverRule(r); err != nil {
+			util.Log.Errorf("Failed to recover rule %s of %s: %s", r.ID, r.Owner, err)
+			failed[r.ID] = err
+			continue
+		}
+		util.Log.Infof("Rule %s of %s was recovered", r.ID, r.Owner)
+	}
+	return failed
+}
+
+// acquireRule checks if the rule identified by the provided ID runs on this
+// instance. Without the leases, all rules run on this instance.
+func (ks *kuiperService) acquireRule(ctx context.Context, id string) (bool, error) {
+	if ks.leases == nil {
+		return true, nil
+	}
+	// The lease expires ttl after the renewal in the database, which is
+	// later than now.
+	now := time.Now()
+	held, err := ks.leases.Acquire(ctx, id, ks.instance, ruleLeaseTTL)
+	ks.leaseMu.Lock()
+	defer ks.leaseMu.Unlock()
+	switch {
+	case err != nil:
+	case held:
+		ks.renewed[id] = now
+	default:
+		delete(ks.renewed, id)
+	}
+	return held, err
+}
+
+// leaseExpiring checks if the lease of the rule identified by the provided
+// ID may expire before the next renewal, so that another instance may take
+// over the rule.
+func (ks *kuiperService) leaseExpiring(id string) bool {
+	ks.leaseMu.Lock()
+	defer ks.leaseMu.Unlock()
+	renewed, ok := ks.renewed[id]
+	return !ok || time.Since(renewed)+ruleLeaseTTL/3 >= ruleLeaseTTL
+}
+
+// releaseRule allows other instances to run the rule identified by the
+// provided ID at once. The lease held by another instance is kept, that
+// instance stops the rule when it syncs the rules.
+func (ks *kuiperService) releaseRule(ctx context.Context, id string) error {
+	if ks.leases == nil {
+		return nil
+	}
+	ks.leaseMu.Lock()
+	delete(ks.renewed, id)
+	ks.leaseMu.Unlock()
+	return ks.leases.Release(ctx, id, ks.instance)
+}
+
+func (ks *kuiperService) keepLeases() {
+	ticker := time.NewTicker(ruleLeaseTTL / 3)
+	defer ticker.Stop()
+	for range ticker.C {
+		ks.syncFunctions(context.Background())
+		ks.syncRules(context.Background())
 	}
+}
+
+// syncFunctions registers the user defined functions created by all
+// instances, and unregisters the removed ones. A function may call the
+// functions created before it, so they are registered in several passes
+// until no more function can be registered.
+func (ks *kuiperService) syncFunctions(ctx context.Context) {
+	funcs, err := ks.functions.RetrieveAll(ctx, "")
+	if err != nil {
+		util.Log.Errorf("Failed to retrieve functions to sync: %s", err)
+		return
+	}
+	ks.funcMu.Lock()
+	defer ks.funcMu.Unlock()
 
+	desired := make(map[string]bool)
+	pending := []Function{}
+	for _, f := range funcs {
+		desired[f.Name] = true
+		if ks.loadedFuncs[f.Name] != f.SQL {
+			pending = append(pending, f)
+		}
+	}
+	for name := range ks.loadedFuncs {
+		if !desired[name] {
+			xsql.UnregisterFunction(name)
+			delete(ks.loadedFuncs, name)
+		}
+	}
+	for len(pending) > 0 {
+		failed := []Function{}
+		errs := make(map[string]error)
+		for _, f := range pending {
+			if err := ks.registerFunction(f); err != nil {
+				failed = append(failed, f)
+				errs[f.Name] = err
+			}
+		}
+		if len(failed) == len(pending) {
+			for _, f := range failed {
+				util.Log.Errorf("Failed to register function %s of %s: %s", f.Name, f.Owner, errs[f.Name])
+			}
+			return
+		}
+		pending = failed
+	}
+}
+
+// registerFunction parses the CREATE FUNCTION statement of the function and
+// registers it, the caller must hold the funcMu.
+func (ks *kuiperService) registerFunction(f Function) error {
+	stmt, err := parseFunction(f.SQL)
+	if err != nil {
+		return err
+	}
+	if _, ok := ks.loadedFuncs[f.Name]; ok {
+		xsql.UnregisterFunction(f.Name)
+		delete(ks.loadedFuncs, f.Name)
+	}
+	if err := xsql.RegisterFunction(stmt); err != nil {
+		return err
+	}
+	ks.loadedFuncs[f.Name] = f.SQL
+	return nil
+}
+
+func parseFunction(sql string) (*xsql.CreateFunctionStatement, error) {
+	stmt, err := xsql.Language.Parse(xsql.NewParser(strings.NewReader(sql)))
+	if err != nil {
+		return nil, err
+	}
+	fs, ok := stmt.(*xsql.CreateFunctionStatement)
+	if !ok {
+		return nil, fmt.Errorf("Invalid function statement: %s", sql)
+	}
+	return fs, nil
+}
+
+// syncRules renews the leases of the running rules. The rules whose lease is
+// taken over from a failed instance are started from the last checkpoint.
+// The local rules are stopped if the lease is lost, can't be renewed before
+// it expires, or they are stopped by another instance.
+func (ks *kuiperService) syncRules(ctx context.Context) {
+	rules, err := ks.rules.RetrieveByState(ctx, RuleRunning)
+	if err != nil {
+		util.Log.Errorf("Failed to retrieve rules to sync: %s", err)
+		return
+	}
+	desired := make(map[string]bool)
+	for _, r := range rules {
+		rule, err := ks.ruleManager.getRuleByJson(r.Name, r.SQL)
+		if err != nil {
+			continue
+		}
+		desired[rule.Id] = true
+		held, err := ks.acquireRule(ctx, r.ID)
+		running := ks.ruleManager.isRunning(rule.Id)
+		if err != nil {
+			util.Log.Errorf("Failed to renew the lease of rule %s of %s: %s", r.ID, r.Owner, err)
+			// Another instance takes over the rule once the lease expires
+			if running && ks.leaseExpiring(r.ID) {
+				util.Log.Warnf("The lease of rule %s of %s may expire, stop it", r.ID, r.Owner)
+				ks.ruleManager.stopRule(rule)
+			}
+			continue
+		}
+		if held && !running {
+			util.Log.Infof("Take over rule %s of %s", r.ID, r.Owner)
+			if err := ks.ruleManager.recoverRule(r); err != nil {
+				util.Log.Errorf("Failed to take over rule %s of %s: %s", r.ID, r.Owner, err)
+			}
+		} else if !held && running {
+			util.Log.Warnf("The lease of rule %s of %s is lost, stop it", r.ID, r.Owner)
+			ks.ruleManager.stopRule(rule)
+		}
+	}
+	for _, name := range ks.ruleManager.runningRules() {
+		if !desired[name] {
+			util.Log.Infof("Rule %s is stopped by another instance", name)
+			ks.ruleManager.stopRule(&api.Rule{Id: name})
+		}
+	}
 }
 
 // CreateStreams adds a list of streams to the user identified by the provided key.
@@ -162,13 +476,24 @@ func (ks *kuiperService) CreateStreams(ctx context.Context, token string, stream
 		if err != nil {
 			return nil, err
 		}
-		switch stmt.(type) {
+		switch st := stmt.(type) {
 		case *xsql.StreamStmt:
+			streams[i].Type = int(st.StreamType)
 		default:
 			return nil, fmt.Errorf("Invalid stsream statement: %s", streams[i].Json)
 		}
 	}
-	return ks.streams.Save(ctx, streams...)
+	saved, err := ks.streams.Save(ctx, streams...)
+	if err != nil {
+		return nil, err
+	}
+	// Register the streams and tables so that the rules are able to find them.
+	for _, s := range saved {
+		if _, err := ks.streamProcessor.ExecStmt(s.Json); err != nil {
+			util.Log.Warnf("register stream %s error: %s", s.ID, err)
+		}
+	}
+	return saved, nil
 }
 
 // UpdateStream updates the stream identified by the provided ID, that
@@ -239,7 +564,20 @@ func (ks *kuiperService) RemoveStream(ctx context.Context, token string, id stri
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
-	return ks.streams.Remove(ctx, res.GetValue(), id)
+	stream, err := ks.streams.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return err
+	}
+	if err := ks.streams.Remove(ctx, res.GetValue(), id); err != nil {
+		return err
+	}
+	if stmt, err := xsql.NewParser(strings.NewReader(stream.Json)).ParseCreateStreamStmt(); err == nil {
+		drop := fmt.Sprintf("DROP %s %s", strings.ToUpper(xsql.StreamTypeMap[stmt.StreamType]), stmt.Name)
+		if _, err := ks.streamProcessor.ExecStmt(drop); err != nil {
+			util.Log.Warnf("unregister stream %s error: %s", id, err)
+		}
+	}
+	return nil
 }
 
 // CreateRules adds a list of things to the user identified by the provided key.
@@ -254,6 +592,7 @@ func (ks *kuiperService) CreateRules(ctx context.Context, token string, rules ..
 			return []Rule{}, err
 		}
 		rules[i].Owner = res.GetValue()
+		rules[i].State = RuleRunning
 	}
 
 	// Save the created rules into repository at first
@@ -265,6 +604,11 @@ func (ks *kuiperService) CreateRules(ctx context.Context, token string, rules ..
 		if err != nil {
 			return []Rule{}, err
 		}
+		if held, err := ks.acquireRule(ctx, rules[i].ID); err != nil {
+			return []Rule{}, err
+		} else if !held {
+			continue
+		}
 		//Start the rule
 		rs, err := ks.ruleManager.createRuleState(r)
 		if err != nil {
@@ -328,7 +672,7 @@ func (ks *kuiperService) RemoveRule(ctx context.Context, token string, id string
 		return ErrUnauthorizedAccess
 	}
 	// Retrieve the rule with specified token and id
-	r, err := ks.rules.RetrieveByID(ctx, token, id)
+	r, err := ks.rules.RetrieveByID(ctx, res.GetValue(), id)
 	if err != nil {
 		return err
 	}
@@ -339,18 +683,21 @@ func (ks *kuiperService) RemoveRule(ctx context.Context, token string, id string
 	if err := ks.ruleManager.deleteRule(rule); err != nil {
 		return err
 	}
-	return ks.rules.Remove(ctx, res.GetValue(), id)
+	if err := ks.rules.Remove(ctx, res.GetValue(), id); err != nil {
+		return err
+	}
+	return ks.releaseRule(ctx, id)
 }
 
 // StartRule start an already existed rule identifier with the provided ID,
 // that belongs to the user
 func (ks *kuiperService) StartRule(ctx context.Context, token string, id string) error {
-	_, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 	// Retrieve the rule with specified token and id
-	r, err := ks.rules.RetrieveByID(ctx, token, id)
+	r, err := ks.rules.RetrieveByID(ctx, res.GetValue(), id)
 	if err != nil {
 		return err
 	}
@@ -358,19 +705,25 @@ func (ks *kuiperService) StartRule(ctx context.Context, token string, id string)
 	if err != nil {
 		return err
 	}
-	return ks.ruleManager.startRule(rule)
-
+	if held, err := ks.acquireRule(ctx, id); err != nil {
+		return err
+	} else if held {
+		if err := ks.ruleManager.startRule(rule); err != nil {
+			return err
+		}
+	}
+	return ks.rules.UpdateState(ctx, res.GetValue(), id, RuleRunning)
 }
 
 // StopRule stop an already existed rule identifier with the provided ID,
 // that belongs to the user
 func (ks *kuiperService) StopRule(ctx context.Context, token string, id string) error {
-	_, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 	// Retrieve the rule with specified token and id
-	r, err := ks.rules.RetrieveByID(ctx, token, id)
+	r, err := ks.rules.RetrieveByID(ctx, res.GetValue(), id)
 	if err != nil {
 		return err
 	}
@@ -378,18 +731,26 @@ func (ks *kuiperService) StopRule(ctx context.Context, token string, id string)
 	if err != nil {
 		return err
 	}
-	return ks.ruleManager.stopRule(rule)
+	// With the leases, the rule may run on another instance which stops it
+	// after the state is updated
+	if err := ks.ruleManager.stopRule(rule); err != nil && ks.leases == nil {
+		return err
+	}
+	if err := ks.rules.UpdateState(ctx, res.GetValue(), id, RuleStopped); err != nil {
+		return err
+	}
+	return ks.releaseRule(ctx, id)
 }
 
 // RestartRule restart an already existed rule identifier with the provided ID,
 // that belongs to the user
 func (ks *kuiperService) RestartRule(ctx context.Context, token string, id string) error {
-	_, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ErrUnauthorizedAccess
 	}
 	// Retrieve the rule with specified token and id
-	r, err := ks.rules.RetrieveByID(ctx, token, id)
+	r, err := ks.rules.RetrieveByID(ctx, res.GetValue(), id)
 	if err != nil {
 		return err
 	}
@@ -397,27 +758,544 @@ func (ks *kuiperService) RestartRule(ctx context.Context, token string, id strin
 	if err != nil {
 		return err
 	}
-	return ks.ruleManager.restartRule(rule)
+	if held, err := ks.acquireRule(ctx, id); err != nil {
+		return err
+	} else if held {
+		if err := ks.ruleManager.restartRule(rule); err != nil {
+			return err
+		}
+	}
+	return ks.rules.UpdateState(ctx, res.GetValue(), id, RuleRunning)
+}
+
+// ViewRuleStatus retrieves running state and per node metrics of the rule
+// identified with the provided ID, that belongs to the user
+func (ks *kuiperService) ViewRuleStatus(ctx context.Context, token string, id string) (RuleStatus, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return RuleStatus{}, ErrUnauthorizedAccess
+	}
+	// Retrieve the rule with specified token and id
+	r, err := ks.rules.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return RuleStatus{}, err
+	}
+	rule, err := ks.ruleManager.getRuleByJson(r.Name, r.SQL)
+	if err != nil {
+		return RuleStatus{}, err
+	}
+	status, err := ks.ruleManager.getRuleStatus(rule.Id)
+	if err != nil {
+		return RuleStatus{}, err
+	}
+	status.ID = r.ID
+	status.Name = r.Name
+	return status, nil
 }
 
-// ListPluginSources retrieves data about subset of plugins sources
-func (ks *kuiperService) ListPluginSources(context.Context, string, uint64, uint64, string, Metadata) (PluginSourcesPage, error) {
-	return PluginSourcesPage{}, nil
+// ViewRuleTopo retrieves topology of the rule identified with the provided
+// ID, that belongs to the user
+func (ks *kuiperService) ViewRuleTopo(ctx context.Context, token string, id string) (RuleTopo, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return RuleTopo{}, ErrUnauthorizedAccess
+	}
+	// Retrieve the rule with specified token and id
+	r, err := ks.rules.RetrieveByID(ctx, res.GetValue(), id)
+	if err != nil {
+		return RuleTopo{}, err
+	}
+	rule, err := ks.ruleManager.getRuleByJson(r.Name, r.SQL)
+	if err != nil {
+		return RuleTopo{}, err
+	}
+	return ks.ruleManager.getRuleTopo(rule.Id)
 }
 
-// ViewPluginSource retrieves data about the plugin source identified with the provided
-// ID.
-func (ks *kuiperService) ViewPluginSource(context.Context, string, string) (PluginSource, error) {
-	return PluginSource{}, nil
+// ExplainRule returns the logical plan of the EXPLAIN SELECT statement
+// without creating a rule.
+func (ks *kuiperService) ExplainRule(ctx context.Context, token string, sql string) (RulePlan, error) {
+	if _, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
+		return RulePlan{}, ErrUnauthorizedAccess
+	}
+	stmt, err := xsql.Language.Parse(xsql.NewParser(strings.NewReader(sql)))
+	if err != nil {
+		return RulePlan{}, err
+	}
+	if explain, ok := stmt.(*xsql.ExplainSelectStatement); !ok || explain.Select == nil {
+		return RulePlan{}, ErrMalformedEntity
+	}
+	// The statement starts with the EXPLAIN keyword, the rest is the query
+	rule := ks.ruleManager.getDefaultRule("$$explain", strings.TrimSpace(sql)[len(xsql.EXPLAIN.String()):])
+	opt := util.Config.Rule
+	rule.Options = &opt
+	return ks.ruleManager.explainRule(rule)
 }
 
-// ListPluginSinks retrieves data about subset of plugins sinks
-func (ks *kuiperService) ListPluginSinks(context.Context, string, uint64, uint64, string, Metadata) (PluginSinksPage, error) {
-	return PluginSinksPage{}, nil
+// TestRule runs the SQL against the sample rows in a throwaway topology and
+// returns the outputs, no rule is created.
+func (ks *kuiperService) TestRule(ctx context.Context, token string, t RuleTest) (RuleTestResult, error) {
+	if _, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
+		return RuleTestResult{}, ErrUnauthorizedAccess
+	}
+	if t.SQL == "" || len(t.Samples) == 0 {
+		return RuleTestResult{}, ErrMalformedEntity
+	}
+	return ks.ruleManager.testRule(t)
 }
 
-// ViewPluginSink retrieves data about the plugin sink identified with the provided
-// ID.
-func (ks *kuiperService) ViewPluginSink(context.Context, string, string) (PluginSink, error) {
-	return PluginSink{}, nil
+// InstallPlugin downloads the plugin zip file from the provided url and
+// installs it for the user identified by the provided key.
+func (ks *kuiperService) InstallPlugin(ctx context.Context, token string, p Plugin) (Plugin, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Plugin{}, ErrUnauthorizedAccess
+	}
+
+	u, err := url.ParseRequestURI(p.File)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !strings.HasSuffix(u.Path, ".zip") {
+		return Plugin{}, ErrMalformedEntity
+	}
+	return ks.installPlugin(ctx, res.GetValue(), p, p.File)
+}
+
+// UploadPlugin installs the plugin from the uploaded zip file for the
+// user identified by the provided key.
+func (ks *kuiperService) UploadPlugin(ctx context.Context, token string, p Plugin, data []byte) (Plugin, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Plugin{}, ErrUnauthorizedAccess
+	}
+
+	f, err := ioutil.TempFile("", p.Name+"-*.zip")
+	if err != nil {
+		return Plugin{}, err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Plugin{}, err
+	}
+	return ks.installPlugin(ctx, res.GetValue(), p, "file://"+f.Name())
+}
+
+func (ks *kuiperService) installPlugin(ctx context.Context, owner string, p Plugin, uri string) (Plugin, error) {
+	kind, err := pluginKind(p.Type)
+	if err != nil {
+		return Plugin{}, err
+	}
+
+	// Plugin names are shared by all users, the name of built-in plugins
+	// and plugins installed by others can't be reused
+	if _, err := ks.pluginRepo.RetrieveByName(ctx, p.Type, p.Name); err == nil {
+		return Plugin{}, ErrConflict
+	} else if err != ErrNotFound {
+		return Plugin{}, err
+	}
+	if _, ok := ks.pluginManager.Get(kind, p.Name); ok {
+		return Plugin{}, ErrConflict
+	}
+
+	pl := plugins.Plugin{
+		Name:       p.Name,
+		File:       uri,
+		ShellParas: p.ShellParas,
+	}
+	if err := ks.pluginManager.Register(kind, &pl); err != nil {
+		return Plugin{}, err
+	}
+
+	p.Owner = owner
+	if v, ok := ks.pluginManager.Get(kind, p.Name); ok {
+		p.Version = v["version"]
+	}
+	if err := ks.pluginRepo.Save(ctx, p); err != nil {
+		ks.pluginManager.Delete(kind, p.Name, false)
+		return Plugin{}, err
+	}
+	return p, nil
+}
+
+// RemovePlugin uninstalls the plugin identified with the provided type
+// and name, that belongs to the user identified by the provided key.
+func (ks *kuiperService) RemovePlugin(ctx context.Context, token string, pluginType PluginType, name string) error {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+	kind, err := pluginKind(pluginType)
+	if err != nil {
+		return err
+	}
+
+	p, err := ks.pluginRepo.RetrieveByName(ctx, pluginType, name)
+	switch {
+	case err == ErrNotFound:
+		// Built-in plugins can't be removed by users
+		if _, ok := ks.pluginManager.Get(kind, name); ok {
+			return ErrUnauthorizedAccess
+		}
+		return ErrNotFound
+	case err != nil:
+		return err
+	case p.Owner != res.GetValue():
+		return ErrNotFound
+	}
+
+	if err := ks.pluginManager.Delete(kind, name, false); err != nil {
+		return err
+	}
+	return ks.pluginRepo.Remove(ctx, p.Owner, pluginType, name)
+}
+
+// ViewPlugin retrieves the plugin identified with the provided type and
+// name along with its metadata in the provided language.
+func (ks *kuiperService) ViewPlugin(ctx context.Context, token string, pluginType PluginType, name, language string) (Plugin, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Plugin{}, ErrUnauthorizedAccess
+	}
+	kind, err := pluginKind(pluginType)
+	if err != nil {
+		return Plugin{}, err
+	}
+
+	p, err := ks.pluginRepo.RetrieveByName(ctx, pluginType, name)
+	switch {
+	case err == ErrNotFound:
+		p = Plugin{Name: name, Type: pluginType, BuiltIn: true}
+	case err != nil:
+		return Plugin{}, err
+	case p.Owner != res.GetValue():
+		return Plugin{}, ErrNotFound
+	}
+
+	p, ok := ks.describePlugin(kind, p, language)
+	if !ok {
+		return Plugin{}, ErrNotFound
+	}
+	return p, nil
+}
+
+// ListPlugins retrieves subset of built-in plugins and plugins installed
+// by the user identified by the provided key, along with their metadata
+// in the provided language.
+func (ks *kuiperService) ListPlugins(ctx context.Context, token string, pluginType PluginType, offset, limit uint64, name, language string) (PluginsPage, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return PluginsPage{}, ErrUnauthorizedAccess
+	}
+	kind, err := pluginKind(pluginType)
+	if err != nil {
+		return PluginsPage{}, err
+	}
+
+	installed, err := ks.pluginRepo.RetrieveAll(ctx, pluginType)
+	if err != nil {
+		return PluginsPage{}, err
+	}
+	owned := make(map[string]Plugin)
+	for _, p := range installed {
+		owned[p.Name] = p
+	}
+
+	items := []Plugin{}
+	for _, n := range ks.pluginNames(kind) {
+		if name != "" && !strings.Contains(strings.ToLower(n), strings.ToLower(name)) {
+			continue
+		}
+		p, ok := owned[n]
+		if ok && p.Owner != res.GetValue() {
+			continue
+		}
+		if !ok {
+			p = Plugin{Name: n, Type: pluginType, BuiltIn: true}
+		}
+		if p, ok = ks.describePlugin(kind, p, language); ok {
+			items = append(items, p)
+		}
+	}
+
+	page := PluginsPage{
+		PageMetadata: PageMetadata{
+			Total:  uint64(len(items)),
+			Offset: offset,
+			Limit:  limit,
+			Name:   name,
+		},
+		Plugins: []Plugin{},
+	}
+	if offset < uint64(len(items)) {
+		end := offset + limit
+		if end > uint64(len(items)) {
+			end = uint64(len(items))
+		}
+		page.Plugins = items[offset:end]
+	}
+	return page, nil
+}
+
+// pluginNames returns sorted names of plugins which have metadata or are
+// installed
+func (ks *kuiperService) pluginNames(kind plugins.PluginType) []string {
+	set := make(map[string]bool)
+	switch kind {
+	case plugins.SOURCE:
+		for _, p := range plugins.GetSources() {
+			set[p.Name] = true
+		}
+	case plugins.SINK:
+		for _, p := range plugins.GetSinks() {
+			set[p.Name] = true
+		}
+	case plugins.FUNCTION:
+		for _, p := range plugins.GetFuncPlugins() {
+			set[p.Name] = true
+		}
+	}
+	installed, _ := ks.pluginManager.List(kind)
+	for _, n := range installed {
+		set[n] = true
+	}
+
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// describePlugin fills plugin's version and metadata, false is returned if
+// the plugin is neither installed nor described by metadata
+func (ks *kuiperService) describePlugin(kind plugins.PluginType, p Plugin, language string) (Plugin, bool) {
+	installed := false
+	if v, ok := ks.pluginManager.Get(kind, p.Name); ok && v["version"] != plugins.DELETED {
+		p.Version = v["version"]
+		installed = true
+	}
+
+	var meta interface{}
+	var err error
+	switch kind {
+	case plugins.SOURCE:
+		meta, err = plugins.GetSourceMeta(p.Name, language)
+	case plugins.SINK:
+		meta, err = plugins.GetSinkMeta(p.Name, language, nil)
+	case plugins.FUNCTION:
+		meta, err = plugins.GetFuncMeta(p.Name, language)
+	}
+	if err != nil {
+		return p, installed
+	}
+	if b, err := json.Marshal(meta); err == nil {
+		p.Json = string(b)
+	}
+	return p, true
+}
+
+func pluginKind(pluginType PluginType) (plugins.PluginType, error) {
+	switch pluginType {
+	case PluginSource:
+		return plugins.SOURCE, nil
+	case PluginSink:
+		return plugins.SINK, nil
+	case PluginFunction:
+		return plugins.FUNCTION, nil
+	}
+	return 0, ErrMalformedEntity
+}
+
+// CreateFunction defines the function of the CREATE FUNCTION statement for
+// the user identified by the provided key.
+func (ks *kuiperService) CreateFunction(ctx context.Context, token string, sql string) (Function, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Function{}, ErrUnauthorizedAccess
+	}
+	stmt, err := parseFunction(sql)
+	if err != nil {
+		return Function{}, err
+	}
+	f := Function{
+		Name:  strings.ToLower(stmt.Name),
+		Owner: res.GetValue(),
+		SQL:   sql,
+	}
+
+	// Function names are shared by all users like the plugins
+	if _, err := ks.functions.RetrieveByName(ctx, f.Name); err == nil {
+		return Function{}, ErrConflict
+	} else if err != ErrNotFound {
+		return Function{}, err
+	}
+	if _, ok := ks.pluginManager.Get(plugins.FUNCTION, f.Name); ok {
+		return Function{}, ErrConflict
+	}
+
+	ks.funcMu.Lock()
+	defer ks.funcMu.Unlock()
+	if err := ks.registerFunction(f); err != nil {
+		return Function{}, err
+	}
+	if err := ks.functions.Save(ctx, f); err != nil {
+		xsql.UnregisterFunction(f.Name)
+		delete(ks.loadedFuncs, f.Name)
+		return Function{}, err
+	}
+	return f, nil
+}
+
+// ViewFunction retrieves the function identified with the provided name,
+// that belongs to the user identified by the provided key.
+func (ks *kuiperService) ViewFunction(ctx context.Context, token string, name string) (Function, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Function{}, ErrUnauthorizedAccess
+	}
+	f, err := ks.functions.RetrieveByName(ctx, strings.ToLower(name))
+	if err != nil {
+		return Function{}, err
+	}
+	if f.Owner != res.GetValue() {
+		return Function{}, ErrNotFound
+	}
+	return f, nil
+}
+
+// ListFunctions retrieves the functions that belong to the user identified
+// by the provided key.
+func (ks *kuiperService) ListFunctions(ctx context.Context, token string) ([]Function, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return []Function{}, ErrUnauthorizedAccess
+	}
+	return ks.functions.RetrieveAll(ctx, res.GetValue())
+}
+
+// RemoveFunction removes the function identified with the provided name,
+// that belongs to the user identified by the provided key. The running rules
+// keep calling the function until they are restarted.
+func (ks *kuiperService) RemoveFunction(ctx context.Context, token string, name string) error {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+	f, err := ks.functions.RetrieveByName(ctx, strings.ToLower(name))
+	if err != nil {
+		return err
+	}
+	if f.Owner != res.GetValue() {
+		return ErrNotFound
+	}
+	if err := ks.functions.Remove(ctx, f.Owner, f.Name); err != nil {
+		return err
+	}
+	ks.funcMu.Lock()
+	xsql.UnregisterFunction(f.Name)
+	delete(ks.loadedFuncs, f.Name)
+	ks.funcMu.Unlock()
+	return nil
+}
+
+// CreateRuleTemplate adds the rule template to the user identified by the
+// provided key.
+func (ks *kuiperService) CreateRuleTemplate(ctx context.Context, token string, t RuleTemplate) (RuleTemplate, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return RuleTemplate{}, ErrUnauthorizedAccess
+	}
+	if _, err := parseRuleTemplate(t); err != nil {
+		return RuleTemplate{}, err
+	}
+	t.Owner = res.GetValue()
+	if err := ks.templates.Save(ctx, t); err != nil {
+		return RuleTemplate{}, err
+	}
+	return t, nil
+}
+
+// ViewRuleTemplate retrieves the rule template identified with the provided
+// name, that belongs to the user identified by the provided key.
+func (ks *kuiperService) ViewRuleTemplate(ctx context.Context, token string, name string) (RuleTemplate, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return RuleTemplate{}, ErrUnauthorizedAccess
+	}
+	return ks.templates.RetrieveByName(ctx, res.GetValue(), name)
+}
+
+// ListRuleTemplates retrieves the rule templates that belong to the user
+// identified by the provided key.
+func (ks *kuiperService) ListRuleTemplates(ctx context.Context, token string) ([]RuleTemplate, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return []RuleTemplate{}, ErrUnauthorizedAccess
+	}
+	return ks.templates.RetrieveAll(ctx, res.GetValue())
+}
+
+// RemoveRuleTemplate removes the rule template identified with the provided
+// name, that belongs to the user identified by the provided key. The rules
+// instantiated from the template are kept.
+func (ks *kuiperService) RemoveRuleTemplate(ctx context.Context, token string, name string) error {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+	if _, err := ks.templates.RetrieveByName(ctx, res.GetValue(), name); err != nil {
+		return err
+	}
+	return ks.templates.Remove(ctx, res.GetValue(), name)
+}
+
+// InstantiateRuleTemplate creates a rule of the provided name from the rule
+// template identified with the provided name and the parameters.
+func (ks *kuiperService) InstantiateRuleTemplate(ctx context.Context, token string, name string, ruleName string, params map[string]interface{}) (Rule, error) {
+	res, err := ks.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Rule{}, ErrUnauthorizedAccess
+	}
+	t, err := ks.templates.RetrieveByName(ctx, res.GetValue(), name)
+	if err != nil {
+		return Rule{}, err
+	}
+	tmpl, err := parseRuleTemplate(t)
+	if err != nil {
+		return Rule{}, err
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, params); err != nil {
+		return Rule{}, fmt.Errorf("Instantiate rule template %s error: %s", name, err)
+	}
+	if _, err := ks.ruleManager.getRuleByJson(ruleName, buf.String()); err != nil {
+		return Rule{}, err
+	}
+	rule := Rule{
+		Name:     ruleName,
+		SQL:      buf.String(),
+		Metadata: Metadata{"template": name},
+	}
+	rules, err := ks.CreateRules(ctx, token, rule)
+	if err != nil {
+		return Rule{}, err
+	}
+	return rules[0], nil
+}
+
+// parseRuleTemplate parses the rule json template, all the parameters used
+// by the template must be provided to instantiate a rule.
+func parseRuleTemplate(t RuleTemplate) (*template.Template, error) {
+	if t.Name == "" || t.Json == "" {
+		return nil, ErrMalformedEntity
+	}
+	tmpl, err := template.New(t.Name).Funcs(templates.FuncMap()).Option("missingkey=error").Parse(t.Json)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid rule template %s: %s", t.Name, err)
+	}
+	return tmpl, nil
 }
@@ -0,0 +1,317 @@
+package kuiper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/kvstore"
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testInstance = "instance-a"
+	testStream   = `CREATE STREAM demo () WITH (DATASOURCE="demo", TYPE="memory", FORMAT="json")`
+)
+
+var errLeaseUnavailable = errors.New("lease repository unavailable")
+
+var _ RuleRepository = (*ruleRepositoryMock)(nil)
+
+// ruleRepositoryMock keeps the rules of all users in memory, only the
+// retrieval by state is used by the leases.
+type ruleRepositoryMock struct {
+	mu    sync.Mutex
+	rules map[string]Rule
+}
+
+func (rrm *ruleRepositoryMock) Save(_ context.Context, rules ...Rule) ([]Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+	for _, r := range rules {
+		rrm.rules[r.ID] = r
+	}
+	return rules, nil
+}
+
+func (rrm *ruleRepositoryMock) Update(_ context.Context, r Rule) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+	if _, ok := rrm.rules[r.ID]; !ok {
+		return ErrNotFound
+	}
+	rrm.rules[r.ID] = r
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+	r, ok := rrm.rules[id]
+	if !ok || r.Owner != owner {
+		return Rule{}, ErrNotFound
+	}
+	return r, nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveAll(context.Context, string, uint64, uint64, string, Metadata) (RulesPage, error) {
+	return RulesPage{}, nil
+}
+
+func (rrm *ruleRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+	delete(rrm.rules, id)
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) UpdateState(_ context.Context, owner, id, state string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+	r, ok := rrm.rules[id]
+	if !ok || r.Owner != owner {
+		return ErrNotFound
+	}
+	r.State = state
+	rrm.rules[id] = r
+	return nil
+}
+
+func (rrm *ruleRepositoryMock) RetrieveByState(_ context.Context, state string) ([]Rule, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+	rules := []Rule{}
+	for _, r := range rrm.rules {
+		if r.State == state {
+			rules = append(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+var _ RuleLeaseRepository = (*leaseRepositoryMock)(nil)
+
+// leaseRepositoryMock holds the leases without expiry, the leases of failed
+// instances are taken over by changing the holder.
+type leaseRepositoryMock struct {
+	mu      sync.Mutex
+	holders map[string]string
+	err     error
+}
+
+func (lrm *leaseRepositoryMock) Acquire(_ context.Context, id, holder string, _ time.Duration) (bool, error) {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+	if lrm.err != nil {
+		return false, lrm.err
+	}
+	if h, ok := lrm.holders[id]; ok && h != holder {
+		return false, nil
+	}
+	lrm.holders[id] = holder
+	return true, nil
+}
+
+func (lrm *leaseRepositoryMock) Release(_ context.Context, id, holder string) error {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+	if lrm.holders[id] == holder {
+		delete(lrm.holders, id)
+	}
+	return nil
+}
+
+func (lrm *leaseRepositoryMock) set(id, holder string, err error) {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+	if holder == "" {
+		delete(lrm.holders, id)
+	} else {
+		lrm.holders[id] = holder
+	}
+	lrm.err = err
+}
+
+// setupKuiper saves the demo stream in a temporary base of kuiper, in which
+// the topologies read the configurations and save their states. The returned
+// function removes the base.
+func setupKuiper(t *testing.T) func() {
+	if util.Config == nil {
+		util.Config = &util.KuiperConf{Rule: api.RuleOption{Concurrency: 1, BufferLength: 1024}}
+	}
+	dir, err := ioutil.TempDir("", "kuiper")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, d := range []string{"etc", "data"} {
+		require.Nil(t, os.MkdirAll(path.Join(dir, d), 0755), "unexpected error creating kuiper base")
+	}
+	base := os.Getenv(util.KuiperBaseKey)
+	os.Setenv(util.KuiperBaseKey, dir)
+	rootDbDir = dir
+
+	store := kvstore.GetKvStore(path.Join(rootDbDir, "stream"))
+	require.Nil(t, store.Open(), "unexpected error opening stream store")
+	require.Nil(t, store.Replace("demo", testStream), "unexpected error saving stream")
+	store.Close()
+
+	return func() {
+		os.Setenv(util.KuiperBaseKey, base)
+		os.RemoveAll(dir)
+	}
+}
+
+func newLeaseService() (*kuiperService, *ruleRepositoryMock, *leaseRepositoryMock) {
+	rules := &ruleRepositoryMock{rules: make(map[string]Rule)}
+	leases := &leaseRepositoryMock{holders: make(map[string]string)}
+	ks := &kuiperService{
+		rules:       rules,
+		ruleManager: newRuleManager(rules),
+		leases:      leases,
+		instance:    testInstance,
+		renewed:     make(map[string]time.Time),
+	}
+	return ks, rules, leases
+}
+
+// waitOpened waits until the topology of the running rule is opened, so that
+// it can be stopped.
+func waitOpened(t *testing.T, ks *kuiperService, name string) {
+	assert.Eventually(t, func() bool {
+		rs, ok := ks.ruleManager.registry.load(name)
+		return ok && rs.topology != nil && rs.topology.GetContext() != nil
+	}, time.Second, 10*time.Millisecond, fmt.Sprintf("expected rule %s opened", name))
+}
+
+func TestSyncRules(t *testing.T) {
+	defer setupKuiper(t)()
+	ks, rules, leases := newLeaseService()
+
+	rule := Rule{
+		Owner: "user@example.com",
+		ID:    "1",
+		Name:  "rule1",
+		SQL:   `{"id": "rule1", "sql": "SELECT * FROM demo", "actions": [{"log": {}}]}`,
+		State: RuleRunning,
+	}
+	_, err := rules.Save(context.Background(), rule)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		holder  string
+		err     error
+		renewed time.Duration
+		state   string
+		running bool
+	}{
+		{
+			desc:    "take over rule without lease",
+			holder:  "",
+			state:   RuleRunning,
+			running: true,
+		},
+		{
+			desc:    "keep rule whose lease is renewed",
+			holder:  testInstance,
+			state:   RuleRunning,
+			running: true,
+		},
+		{
+			desc:    "keep rule whose lease fails to renew before it expires",
+			holder:  testInstance,
+			err:     errLeaseUnavailable,
+			renewed: ruleLeaseTTL / 3,
+			state:   RuleRunning,
+			running: true,
+		},
+		{
+			desc:    "stop rule whose lease fails to renew until it expires",
+			holder:  testInstance,
+			err:     errLeaseUnavailable,
+			renewed: ruleLeaseTTL - ruleLeaseTTL/3,
+			state:   RuleRunning,
+			running: false,
+		},
+		{
+			desc:    "recover rule whose lease is renewed again",
+			holder:  testInstance,
+			state:   RuleRunning,
+			running: true,
+		},
+		{
+			desc:    "stop rule whose lease is taken over",
+			holder:  "instance-b",
+			state:   RuleRunning,
+			running: false,
+		},
+		{
+			desc:    "take over rule whose lease is released",
+			holder:  "",
+			state:   RuleRunning,
+			running: true,
+		},
+		{
+			desc:    "stop rule stopped by another instance",
+			holder:  testInstance,
+			state:   RuleStopped,
+			running: false,
+		},
+	}
+
+	for _, tc := range cases {
+		leases.set(rule.ID, tc.holder, tc.err)
+		err := rules.UpdateState(context.Background(), rule.Owner, rule.ID, tc.state)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		if tc.renewed > 0 {
+			ks.leaseMu.Lock()
+			ks.renewed[rule.ID] = time.Now().Add(-tc.renewed)
+			ks.leaseMu.Unlock()
+		}
+
+		ks.syncRules(context.Background())
+		running := ks.ruleManager.isRunning(rule.Name)
+		assert.Equal(t, tc.running, running, fmt.Sprintf("%s: expected running %t got %t", tc.desc, tc.running, running))
+		if running {
+			waitOpened(t, ks, rule.Name)
+		}
+	}
+}
+
+func TestReleaseRule(t *testing.T) {
+	ks, _, leases := newLeaseService()
+
+	cases := []struct {
+		desc   string
+		holder string
+		kept   string
+	}{
+		{
+			desc:   "release lease held by this instance",
+			holder: testInstance,
+			kept:   "",
+		},
+		{
+			desc:   "keep lease held by another instance",
+			holder: "instance-b",
+			kept:   "instance-b",
+		},
+	}
+
+	for _, tc := range cases {
+		leases.set("1", tc.holder, nil)
+		err := ks.releaseRule(context.Background(), "1")
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		leases.mu.Lock()
+		holder := leases.holders["1"]
+		leases.mu.Unlock()
+		assert.Equal(t, tc.kept, holder, fmt.Sprintf("%s: expected holder %s got %s", tc.desc, tc.kept, holder))
+	}
+}
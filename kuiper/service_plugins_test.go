@@ -0,0 +1,325 @@
+package kuiper
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/plugins"
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	pluginOwner = "owner@example.com"
+	ownerToken  = "owner-token"
+	otherToken  = "other-token"
+	wrongToken  = "wrong-token"
+)
+
+var _ mainflux.AuthNServiceClient = (*authServiceMock)(nil)
+
+type authServiceMock struct {
+	users map[string]string
+}
+
+func (svc authServiceMock) Identify(_ context.Context, in *mainflux.Token, _ ...grpc.CallOption) (*mainflux.UserID, error) {
+	if id, ok := svc.users[in.Value]; ok {
+		return &mainflux.UserID{Value: id}, nil
+	}
+	return nil, ErrUnauthorizedAccess
+}
+
+func (svc authServiceMock) Issue(context.Context, *mainflux.IssueReq, ...grpc.CallOption) (*mainflux.Token, error) {
+	return nil, ErrUnauthorizedAccess
+}
+
+var _ PluginRepository = (*pluginRepositoryMock)(nil)
+
+// pluginRepositoryMock keeps the installed plugins of all users in memory,
+// indexed by the type and the name.
+type pluginRepositoryMock struct {
+	mu      sync.Mutex
+	plugins map[string]Plugin
+}
+
+func (prm *pluginRepositoryMock) Save(_ context.Context, p Plugin) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+	key := fmt.Sprintf("%s/%s", p.Type, p.Name)
+	if _, ok := prm.plugins[key]; ok {
+		return ErrConflict
+	}
+	prm.plugins[key] = p
+	return nil
+}
+
+func (prm *pluginRepositoryMock) RetrieveByName(_ context.Context, pluginType PluginType, name string) (Plugin, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+	p, ok := prm.plugins[fmt.Sprintf("%s/%s", pluginType, name)]
+	if !ok {
+		return Plugin{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (prm *pluginRepositoryMock) RetrieveAll(_ context.Context, pluginType PluginType) ([]Plugin, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+	items := []Plugin{}
+	for _, p := range prm.plugins {
+		if p.Type == pluginType {
+			items = append(items, p)
+		}
+	}
+	return items, nil
+}
+
+func (prm *pluginRepositoryMock) Remove(_ context.Context, owner string, pluginType PluginType, name string) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+	key := fmt.Sprintf("%s/%s", pluginType, name)
+	if p, ok := prm.plugins[key]; ok && p.Owner == owner {
+		delete(prm.plugins, key)
+	}
+	return nil
+}
+
+func newPluginService() *kuiperService {
+	return &kuiperService{
+		auth: authServiceMock{users: map[string]string{
+			ownerToken: pluginOwner,
+			otherToken: "other@example.com",
+		}},
+		pluginRepo:    &pluginRepositoryMock{plugins: make(map[string]Plugin)},
+		pluginManager: pluginManager,
+	}
+}
+
+// zipPlugin returns the zip file of the sink plugin with the provided name.
+// The so file is not loaded by the tests, so it is empty.
+func zipPlugin(t *testing.T, name string) []byte {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	_, err := w.Create(name + ".so")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Nil(t, w.Close(), "unexpected error closing zip file")
+	return buf.Bytes()
+}
+
+// installSink installs the sink plugin in the plugin manager only, like the
+// plugins which are installed in the plugins folder of the server.
+func installSink(t *testing.T, name string) {
+	f, err := ioutil.TempFile("", name+"-*.zip")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.Remove(f.Name())
+	_, err = f.Write(zipPlugin(t, name))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Nil(t, f.Close(), "unexpected error closing zip file")
+
+	err = pluginManager.Register(plugins.SINK, &plugins.Plugin{Name: name, File: "file://" + f.Name()})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+}
+
+func TestUploadPlugin(t *testing.T) {
+	ks := newPluginService()
+	installSink(t, "uploadBuiltIn")
+
+	cases := []struct {
+		desc   string
+		token  string
+		plugin Plugin
+		err    error
+	}{
+		{
+			desc:   "upload plugin",
+			token:  ownerToken,
+			plugin: Plugin{Name: "upload", Type: PluginSink},
+			err:    nil,
+		},
+		{
+			desc:   "upload plugin with name of plugin of another user",
+			token:  otherToken,
+			plugin: Plugin{Name: "upload", Type: PluginSink},
+			err:    ErrConflict,
+		},
+		{
+			desc:   "upload plugin with name of built-in plugin",
+			token:  ownerToken,
+			plugin: Plugin{Name: "uploadBuiltIn", Type: PluginSink},
+			err:    ErrConflict,
+		},
+		{
+			desc:   "upload plugin with invalid token",
+			token:  wrongToken,
+			plugin: Plugin{Name: "uploadWrong", Type: PluginSink},
+			err:    ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := ks.UploadPlugin(context.Background(), tc.token, tc.plugin, zipPlugin(t, tc.plugin.Name))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, pluginOwner, p.Owner, fmt.Sprintf("%s: expected owner %s got %s", tc.desc, pluginOwner, p.Owner))
+		}
+	}
+}
+
+func TestViewPlugin(t *testing.T) {
+	ks := newPluginService()
+	installSink(t, "viewBuiltIn")
+	p, err := ks.UploadPlugin(context.Background(), ownerToken, Plugin{Name: "view", Type: PluginSink}, zipPlugin(t, "view"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		name   string
+		plugin Plugin
+		err    error
+	}{
+		{
+			desc:   "view plugin",
+			token:  ownerToken,
+			name:   p.Name,
+			plugin: p,
+			err:    nil,
+		},
+		{
+			desc:   "view plugin of another user",
+			token:  otherToken,
+			name:   p.Name,
+			plugin: Plugin{},
+			err:    ErrNotFound,
+		},
+		{
+			desc:   "view built-in plugin",
+			token:  otherToken,
+			name:   "viewBuiltIn",
+			plugin: Plugin{Name: "viewBuiltIn", Type: PluginSink, BuiltIn: true},
+			err:    nil,
+		},
+		{
+			desc:   "view non-existing plugin",
+			token:  ownerToken,
+			name:   "viewNonExisting",
+			plugin: Plugin{},
+			err:    ErrNotFound,
+		},
+		{
+			desc:   "view plugin with invalid token",
+			token:  wrongToken,
+			name:   p.Name,
+			plugin: Plugin{},
+			err:    ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		plugin, err := ks.ViewPlugin(context.Background(), tc.token, PluginSink, tc.name, "en_US")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.plugin, plugin, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.plugin, plugin))
+	}
+}
+
+func TestRemovePlugin(t *testing.T) {
+	ks := newPluginService()
+	installSink(t, "removeBuiltIn")
+	p, err := ks.UploadPlugin(context.Background(), ownerToken, Plugin{Name: "remove", Type: PluginSink}, zipPlugin(t, "remove"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		name  string
+		err   error
+	}{
+		{
+			desc:  "remove plugin of another user",
+			token: otherToken,
+			name:  p.Name,
+			err:   ErrNotFound,
+		},
+		{
+			desc:  "remove plugin with invalid token",
+			token: wrongToken,
+			name:  p.Name,
+			err:   ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove built-in plugin",
+			token: ownerToken,
+			name:  "removeBuiltIn",
+			err:   ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove non-existing plugin",
+			token: ownerToken,
+			name:  "removeNonExisting",
+			err:   ErrNotFound,
+		},
+		{
+			desc:  "remove plugin",
+			token: ownerToken,
+			name:  p.Name,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := ks.RemovePlugin(context.Background(), tc.token, PluginSink, tc.name)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+
+	_, err = ks.ViewPlugin(context.Background(), ownerToken, PluginSink, p.Name, "en_US")
+	assert.Equal(t, ErrNotFound, err, fmt.Sprintf("view removed plugin: expected %s got %s", ErrNotFound, err))
+}
+
+func TestListPlugins(t *testing.T) {
+	ks := newPluginService()
+	owned, err := ks.UploadPlugin(context.Background(), ownerToken, Plugin{Name: "listOwned", Type: PluginSink}, zipPlugin(t, "listOwned"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	other, err := ks.UploadPlugin(context.Background(), otherToken, Plugin{Name: "listOther", Type: PluginSink}, zipPlugin(t, "listOther"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		plugins []Plugin
+		err     error
+	}{
+		{
+			desc:    "list plugins installed by user",
+			token:   ownerToken,
+			plugins: []Plugin{owned},
+			err:     nil,
+		},
+		{
+			desc:    "list plugins installed by another user",
+			token:   otherToken,
+			plugins: []Plugin{other},
+			err:     nil,
+		},
+		{
+			desc:    "list plugins with invalid token",
+			token:   wrongToken,
+			plugins: nil,
+			err:     ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := ks.ListPlugins(context.Background(), tc.token, PluginSink, 0, 10, "list", "en_US")
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.plugins, page.Plugins, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.plugins, page.Plugins))
+	}
+}
@@ -0,0 +1,41 @@
+package kuiper
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/plugins"
+	"github.com/cloustone/pandas/kuiper/util"
+)
+
+// pluginManager is created once by the process, so it installs the plugins
+// of all tests in the same temporary kuiper base.
+var pluginManager *plugins.Manager
+
+func TestMain(m *testing.M) {
+	dir, err := ioutil.TempDir("", "kuiper-plugins")
+	if err != nil {
+		log.Fatalf("Could not create plugins base: %s", err)
+	}
+	for _, d := range []string{"etc", "plugins/sources", "plugins/sinks", "plugins/functions"} {
+		if err := os.MkdirAll(path.Join(dir, d), 0755); err != nil {
+			log.Fatalf("Could not create plugins base: %s", err)
+		}
+	}
+
+	base := os.Getenv(util.KuiperBaseKey)
+	os.Setenv(util.KuiperBaseKey, dir)
+	pluginManager, err = plugins.NewPluginManager()
+	os.Setenv(util.KuiperBaseKey, base)
+	if err != nil {
+		log.Fatalf("Could not create plugin manager: %s", err)
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
@@ -6,8 +6,19 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"text/template"
 )
 
+// FuncMap returns the functions available to the templates of the sink data,
+// the script functions and the rule templates.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"json":   JsonMarshal,
+		"base64": Base64Encode,
+		"add":    Add,
+	}
+}
+
 //Use the name json in func map
 func JsonMarshal(v interface{}) (string, error) {
 	if a, err := json.Marshal(v); err != nil {
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/kuiper"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveFunctionOp         = "save_function"
+	retrieveFunctionOp     = "retrieve_function_by_name"
+	retrieveAllFunctionsOp = "retrieve_all_functions"
+	removeFunctionOp       = "remove_function"
+)
+
+var _ kuiper.FunctionRepository = (*functionRepositoryMiddleware)(nil)
+
+type functionRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   kuiper.FunctionRepository
+}
+
+// FunctionRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func FunctionRepositoryMiddleware(tracer opentracing.Tracer, repo kuiper.FunctionRepository) kuiper.FunctionRepository {
+	return functionRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (frm functionRepositoryMiddleware) Save(ctx context.Context, f kuiper.Function) error {
+	span := createSpan(ctx, frm.tracer, saveFunctionOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return frm.repo.Save(ctx, f)
+}
+
+func (frm functionRepositoryMiddleware) RetrieveByName(ctx context.Context, name string) (kuiper.Function, error) {
+	span := createSpan(ctx, frm.tracer, retrieveFunctionOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return frm.repo.RetrieveByName(ctx, name)
+}
+
+func (frm functionRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string) ([]kuiper.Function, error) {
+	span := createSpan(ctx, frm.tracer, retrieveAllFunctionsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return frm.repo.RetrieveAll(ctx, owner)
+}
+
+func (frm functionRepositoryMiddleware) Remove(ctx context.Context, owner, name string) error {
+	span := createSpan(ctx, frm.tracer, removeFunctionOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return frm.repo.Remove(ctx, owner, name)
+}
@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	acquireRuleLeaseOp = "acquire_rule_lease"
+	releaseRuleLeaseOp = "release_rule_lease"
+)
+
+var _ kuiper.RuleLeaseRepository = (*ruleLeaseRepositoryMiddleware)(nil)
+
+type ruleLeaseRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   kuiper.RuleLeaseRepository
+}
+
+// RuleLeaseRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func RuleLeaseRepositoryMiddleware(tracer opentracing.Tracer, repo kuiper.RuleLeaseRepository) kuiper.RuleLeaseRepository {
+	return ruleLeaseRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (lrm ruleLeaseRepositoryMiddleware) Acquire(ctx context.Context, id, holder string, ttl time.Duration) (bool, error) {
+	span := createSpan(ctx, lrm.tracer, acquireRuleLeaseOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.Acquire(ctx, id, holder, ttl)
+}
+
+func (lrm ruleLeaseRepositoryMiddleware) Release(ctx context.Context, id, holder string) error {
+	span := createSpan(ctx, lrm.tracer, releaseRuleLeaseOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.Release(ctx, id, holder)
+}
@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/kuiper"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	savePluginOp         = "save_plugin"
+	retrievePluginOp     = "retrieve_plugin_by_name"
+	retrieveAllPluginsOp = "retrieve_all_plugins"
+	removePluginOp       = "remove_plugin"
+)
+
+var _ kuiper.PluginRepository = (*pluginRepositoryMiddleware)(nil)
+
+type pluginRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   kuiper.PluginRepository
+}
+
+// PluginRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func PluginRepositoryMiddleware(tracer opentracing.Tracer, repo kuiper.PluginRepository) kuiper.PluginRepository {
+	return pluginRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (prm pluginRepositoryMiddleware) Save(ctx context.Context, p kuiper.Plugin) error {
+	span := createSpan(ctx, prm.tracer, savePluginOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.Save(ctx, p)
+}
+
+func (prm pluginRepositoryMiddleware) RetrieveByName(ctx context.Context, pluginType kuiper.PluginType, name string) (kuiper.Plugin, error) {
+	span := createSpan(ctx, prm.tracer, retrievePluginOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.RetrieveByName(ctx, pluginType, name)
+}
+
+func (prm pluginRepositoryMiddleware) RetrieveAll(ctx context.Context, pluginType kuiper.PluginType) ([]kuiper.Plugin, error) {
+	span := createSpan(ctx, prm.tracer, retrieveAllPluginsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.RetrieveAll(ctx, pluginType)
+}
+
+func (prm pluginRepositoryMiddleware) Remove(ctx context.Context, owner string, pluginType kuiper.PluginType, name string) error {
+	span := createSpan(ctx, prm.tracer, removePluginOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return prm.repo.Remove(ctx, owner, pluginType, name)
+}
@@ -19,6 +19,8 @@ const (
 	retrieveRulesByChannelOp = "retrieve_rules_by_chan"
 	removeRuleOp             = "remove_rule"
 	retrieveRuleIDByKeyOp    = "retrieve_id_by_key"
+	updateRuleStateOp        = "update_rule_state"
+	retrieveRulesByStateOp   = "retrieve_rules_by_state"
 )
 
 var (
@@ -80,6 +82,22 @@ func (trm ruleRepositoryMiddleware) Remove(ctx context.Context, owner, id string
 	return trm.repo.Remove(ctx, owner, id)
 }
 
+func (trm ruleRepositoryMiddleware) UpdateState(ctx context.Context, owner, id, state string) error {
+	span := createSpan(ctx, trm.tracer, updateRuleStateOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.UpdateState(ctx, owner, id, state)
+}
+
+func (trm ruleRepositoryMiddleware) RetrieveByState(ctx context.Context, state string) ([]kuiper.Rule, error) {
+	span := createSpan(ctx, trm.tracer, retrieveRulesByStateOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveByState(ctx, state)
+}
+
 type ruleCacheMiddleware struct {
 	tracer opentracing.Tracer
 	cache  kuiper.RuleCache
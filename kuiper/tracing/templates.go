@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/kuiper"
+
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveRuleTemplateOp         = "save_rule_template"
+	retrieveRuleTemplateOp     = "retrieve_rule_template_by_name"
+	retrieveAllRuleTemplatesOp = "retrieve_all_rule_templates"
+	removeRuleTemplateOp       = "remove_rule_template"
+)
+
+var _ kuiper.RuleTemplateRepository = (*ruleTemplateRepositoryMiddleware)(nil)
+
+type ruleTemplateRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   kuiper.RuleTemplateRepository
+}
+
+// RuleTemplateRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func RuleTemplateRepositoryMiddleware(tracer opentracing.Tracer, repo kuiper.RuleTemplateRepository) kuiper.RuleTemplateRepository {
+	return ruleTemplateRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (trm ruleTemplateRepositoryMiddleware) Save(ctx context.Context, t kuiper.RuleTemplate) error {
+	span := createSpan(ctx, trm.tracer, saveRuleTemplateOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Save(ctx, t)
+}
+
+func (trm ruleTemplateRepositoryMiddleware) RetrieveByName(ctx context.Context, owner, name string) (kuiper.RuleTemplate, error) {
+	span := createSpan(ctx, trm.tracer, retrieveRuleTemplateOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveByName(ctx, owner, name)
+}
+
+func (trm ruleTemplateRepositoryMiddleware) RetrieveAll(ctx context.Context, owner string) ([]kuiper.RuleTemplate, error) {
+	span := createSpan(ctx, trm.tracer, retrieveAllRuleTemplatesOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.RetrieveAll(ctx, owner)
+}
+
+func (trm ruleTemplateRepositoryMiddleware) Remove(ctx context.Context, owner, name string) error {
+	span := createSpan(ctx, trm.tracer, removeRuleTemplateOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return trm.repo.Remove(ctx, owner, name)
+}
@@ -54,6 +54,14 @@ const (
 	CROSS_JOIN
 )
 
+var JoinTypeMap = map[JoinType]string{
+	LEFT_JOIN:  "LEFT",
+	INNER_JOIN: "INNER",
+	RIGHT_JOIN: "RIGHT",
+	FULL_JOIN:  "FULL",
+	CROSS_JOIN: "CROSS",
+}
+
 var AsteriskExpr = StringLiteral{Val: "*"}
 
 var COLUMN_SEPARATOR = tokens[COLSEP]
@@ -78,13 +86,14 @@ type Statement interface {
 }
 
 type SelectStatement struct {
-	Fields     Fields
-	Sources    Sources
-	Joins      Joins
-	Condition  Expr
-	Dimensions Dimensions
-	Having     Expr
-	SortFields SortFields
+	Fields         Fields
+	Sources        Sources
+	MatchRecognize *MatchRecognize
+	Joins          Joins
+	Condition      Expr
+	Dimensions     Dimensions
+	Having         Expr
+	SortFields     SortFields
 }
 
 func (ss *SelectStatement) Stmt() {}
@@ -241,6 +250,14 @@ const (
 	COUNT_WINDOW
 )
 
+var WindowTypeMap = map[WindowType]string{
+	TUMBLING_WINDOW: "TUMBLINGWINDOW",
+	HOPPING_WINDOW:  "HOPPINGWINDOW",
+	SLIDING_WINDOW:  "SLIDINGWINDOW",
+	SESSION_WINDOW:  "SESSIONWINDOW",
+	COUNT_WINDOW:    "COUNTWINDOW",
+}
+
 type Window struct {
 	WindowType WindowType
 	Length     *IntegerLiteral
@@ -252,6 +269,36 @@ func (w *Window) expr()    {}
 func (w *Window) literal() {}
 func (w *Window) node()    {}
 
+// MatchRecognize is the row pattern recognition clause. Each partition of
+// the rows is matched against the pattern, and a row made of the measures is
+// emitted when the pattern is matched.
+type MatchRecognize struct {
+	PartitionBy []Expr
+	Measures    Fields
+	Pattern     []*PatternElement
+	// Within is the maximum duration in milliseconds from the first row to
+	// the last row of a match, 0 means no limitation.
+	Within  int
+	Defines []*PatternDefine
+}
+
+func (m *MatchRecognize) node() {}
+
+// PatternElement is a pattern variable with its quantifier. Max is -1 if the
+// quantifier is unbounded.
+type PatternElement struct {
+	Variable string
+	Min      int
+	Max      int
+}
+
+// PatternDefine is the condition that a row must meet to be mapped to the
+// pattern variable.
+type PatternDefine struct {
+	Variable  string
+	Condition Expr
+}
+
 type SelectStatements []SelectStatement
 
 func (ss *SelectStatements) node() {}
@@ -294,10 +341,25 @@ type StreamName string
 
 func (sn *StreamName) node() {}
 
+// StreamType distinguishes the unbounded streams from the bounded lookup
+// tables defined by CREATE STREAM and CREATE TABLE statements.
+type StreamType int
+
+const (
+	TypeStream StreamType = iota
+	TypeTable
+)
+
+var StreamTypeMap = map[StreamType]string{
+	TypeStream: "stream",
+	TypeTable:  "table",
+}
+
 type StreamStmt struct {
 	Name         StreamName
 	StreamFields StreamFields
 	Options      Options
+	StreamType   StreamType
 }
 
 func (ss *StreamStmt) node() {}
@@ -350,18 +412,40 @@ func (rt *RecType) fieldType() {}
 func (rt *RecType) node()      {}
 
 type ShowStreamsStatement struct {
+	StreamType StreamType
 }
 
 type DescribeStreamStatement struct {
-	Name string
+	Name       string
+	StreamType StreamType
 }
 
 type ExplainStreamStatement struct {
-	Name string
+	Name       string
+	StreamType StreamType
 }
 
 type DropStreamStatement struct {
-	Name string
+	Name       string
+	StreamType StreamType
+}
+
+// ExplainSelectStatement asks for the logical plan of the select statement
+// instead of running it.
+type ExplainSelectStatement struct {
+	Select *SelectStatement
+}
+
+// CreateFunctionStatement defines a function by an expression of its
+// parameters, or by a script of the language which is run within the timeout
+// in milliseconds.
+type CreateFunctionStatement struct {
+	Name     string
+	Params   []string
+	Expr     Expr
+	Language string
+	Script   string
+	Timeout  int
 }
 
 func (ss *ShowStreamsStatement) Stmt() {}
@@ -376,6 +460,12 @@ func (ess *ExplainStreamStatement) node() {}
 func (dss *DropStreamStatement) Stmt() {}
 func (dss *DropStreamStatement) node() {}
 
+func (ess *ExplainSelectStatement) Stmt() {}
+func (ess *ExplainSelectStatement) node() {}
+
+func (cfs *CreateFunctionStatement) Stmt() {}
+func (cfs *CreateFunctionStatement) node() {}
+
 type Visitor interface {
 	Visit(Node) Visitor
 }
@@ -419,6 +509,9 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Fields)
 		Walk(v, n.Dimensions)
 		Walk(v, n.Sources)
+		if n.MatchRecognize != nil {
+			Walk(v, n.MatchRecognize)
+		}
 		Walk(v, n.Joins)
 		Walk(v, n.Condition)
 		Walk(v, n.SortFields)
@@ -437,6 +530,14 @@ func Walk(v Visitor, node Node) {
 		for _, s := range n {
 			Walk(v, &s)
 		}
+	case *MatchRecognize:
+		for _, e := range n.PartitionBy {
+			Walk(v, e)
+		}
+		Walk(v, n.Measures)
+		for _, d := range n.Defines {
+			Walk(v, d.Condition)
+		}
 	case *Join:
 		Walk(v, n.Expr)
 
@@ -465,6 +566,14 @@ func Walk(v Visitor, node Node) {
 
 	case *DropStreamStatement:
 		Walk(v, n)
+
+	case *ExplainSelectStatement:
+		Walk(v, n.Select)
+
+	case *CreateFunctionStatement:
+		if n.Expr != nil {
+			Walk(v, n.Expr)
+		}
 	}
 }
 
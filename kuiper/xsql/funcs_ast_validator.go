@@ -22,6 +22,10 @@ func validateFuncs(funcName string, args []Expr) error {
 		return validateHashFunc(lowerName, args)
 	} else if _, ok := jsonFuncMap[lowerName]; ok {
 		return validateJsonFunc(lowerName, args)
+	} else if _, ok := geoFuncMap[lowerName]; ok {
+		return validateGeoFunc(lowerName, args)
+	} else if _, ok := patternFuncMap[lowerName]; ok {
+		return validatePatternFunc(lowerName, args)
 	} else if _, ok := otherFuncMap[lowerName]; ok {
 		return validateOtherFunc(lowerName, args)
 	} else if _, ok := aggFuncMap[lowerName]; ok {
@@ -333,6 +337,69 @@ func validateJsonFunc(name string, args []Expr) error {
 	return nil
 }
 
+func validateGeoFunc(name string, args []Expr) error {
+	len := len(args)
+	switch name {
+	case "st_distance", "st_bearing":
+		if err := validateLen(name, 4, len); err != nil {
+			return err
+		}
+	case "st_within_circle":
+		if err := validateLen(name, 5, len); err != nil {
+			return err
+		}
+	case "st_within_polygon":
+		if err := validateLen(name, 3, len); err != nil {
+			return err
+		}
+		if isNumericArg(args[2]) || isTimeArg(args[2]) || isBooleanArg(args[2]) {
+			return produceErrInfo(name, 2, "string")
+		}
+		args = args[:2]
+	case "geohash_encode":
+		if len != 2 && len != 3 {
+			return fmt.Errorf("The arguments for %s should be 2 or 3.", name)
+		}
+		if len == 3 {
+			if isFloatArg(args[2]) || isStringArg(args[2]) || isTimeArg(args[2]) || isBooleanArg(args[2]) {
+				return produceErrInfo(name, 2, "int")
+			}
+			if v, ok := args[2].(*IntegerLiteral); ok && (v.Val < 1 || v.Val > geohashMaxPrecision) {
+				return fmt.Errorf("The precision of %s should be between 1 and %d.", name, geohashMaxPrecision)
+			}
+			args = args[:2]
+		}
+	case "geohash_decode":
+		if err := validateLen(name, 1, len); err != nil {
+			return err
+		}
+		if isNumericArg(args[0]) || isTimeArg(args[0]) || isBooleanArg(args[0]) {
+			return produceErrInfo(name, 0, "string")
+		}
+		return nil
+	default:
+		if err := validateLen(name, 2, len); err != nil {
+			return err
+		}
+	}
+	for i, arg := range args {
+		if isStringArg(arg) || isTimeArg(arg) || isBooleanArg(arg) {
+			return produceErrInfo(name, i, "number - float or int")
+		}
+	}
+	return nil
+}
+
+func validatePatternFunc(name string, args []Expr) error {
+	if err := validateLen(name, 1, len(args)); err != nil {
+		return err
+	}
+	if _, ok := args[0].(*FieldRef); !ok {
+		return produceErrInfo(name, 0, "field reference")
+	}
+	return nil
+}
+
 func validateAggFunc(name string, args []Expr) error {
 	len := len(args)
 	switch name {
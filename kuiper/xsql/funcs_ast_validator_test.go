@@ -465,6 +465,53 @@ func TestFuncValidator(t *testing.T) {
 			stmt: nil,
 			err:  "Expect bool type for 2 parameter of function deduplicate.",
 		},
+		{
+			s: `SELECT st_distance(lat, lng, 39.9, 116.4) FROM tbl`,
+			stmt: &SelectStatement{Fields: []Field{{AName: "", Name: "st_distance", Expr: &Call{Name: "st_distance", Args: []Expr{&FieldRef{Name: "lat"}, &FieldRef{Name: "lng"}, &NumberLiteral{Val: 39.9}, &NumberLiteral{Val: 116.4}}}}},
+				Sources: []Source{&Table{Name: "tbl"}},
+			},
+		},
+		{
+			s:    `SELECT st_distance(lat, lng, 39.9) FROM tbl`,
+			stmt: nil,
+			err:  "The arguments for st_distance should be 4.",
+		},
+		{
+			s:    `SELECT st_within_circle(lat, lng, 39.9, 116.4, "100") FROM tbl`,
+			stmt: nil,
+			err:  "Expect number - float or int type for 5 parameter of function st_within_circle.",
+		},
+		{
+			s: `SELECT st_within_polygon(lat, lng, "POLYGON((0 0, 0 1, 1 1, 0 0))") FROM tbl`,
+			stmt: &SelectStatement{Fields: []Field{{AName: "", Name: "st_within_polygon", Expr: &Call{Name: "st_within_polygon", Args: []Expr{&FieldRef{Name: "lat"}, &FieldRef{Name: "lng"}, &StringLiteral{Val: "POLYGON((0 0, 0 1, 1 1, 0 0))"}}}}},
+				Sources: []Source{&Table{Name: "tbl"}},
+			},
+		},
+		{
+			s:    `SELECT st_within_polygon(lat, lng, 1) FROM tbl`,
+			stmt: nil,
+			err:  "Expect string type for 3 parameter of function st_within_polygon.",
+		},
+		{
+			s:    `SELECT geohash_encode(lat, lng, 13) FROM tbl`,
+			stmt: nil,
+			err:  "The precision of geohash_encode should be between 1 and 12.",
+		},
+		{
+			s:    `SELECT geohash_encode(lat) FROM tbl`,
+			stmt: nil,
+			err:  "The arguments for geohash_encode should be 2 or 3.",
+		},
+		{
+			s:    `SELECT geohash_decode(1) FROM tbl`,
+			stmt: nil,
+			err:  "Expect string type for 1 parameter of function geohash_decode.",
+		},
+		{
+			s:    `SELECT wgs84_to_gcj02(true, lng) FROM tbl`,
+			stmt: nil,
+			err:  "Expect number - float or int type for 1 parameter of function wgs84_to_gcj02.",
+		},
 	}
 
 	fmt.Printf("The test bucket size is %d.\n\n", len(tests))
@@ -0,0 +1,521 @@
+package xsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The geospatial functions take the coordinates in the (lat, lng) order, in
+// decimal degrees. The distances are in meters.
+
+const (
+	earthRadius = 6371008.8
+
+	// Krasovsky 1940 ellipsoid used by GCJ-02
+	gcjA  = 6378245.0
+	gcjEE = 0.00669342162296594323
+	bdXPi = math.Pi * 3000.0 / 180.0
+
+	geohashBase32       = "0123456789bcdefghjkmnpqrstuvwxyz"
+	geohashMaxPrecision = 12
+
+	maxCachedPolygons = 256
+)
+
+func geoCall(name string, args []interface{}) (interface{}, bool) {
+	switch name {
+	case "st_distance", "st_bearing":
+		var ps [4]float64
+		for i := range ps {
+			if v, e := toF64(args[i]); e == nil {
+				ps[i] = v
+			} else {
+				return e, false
+			}
+		}
+		if name == "st_distance" {
+			return haversine(ps[0], ps[1], ps[2], ps[3]), true
+		}
+		return bearing(ps[0], ps[1], ps[2], ps[3]), true
+	case "st_within_circle":
+		var ps [5]float64
+		for i := range ps {
+			if v, e := toF64(args[i]); e == nil {
+				ps[i] = v
+			} else {
+				return e, false
+			}
+		}
+		return haversine(ps[0], ps[1], ps[2], ps[3]) <= ps[4], true
+	case "st_within_polygon":
+		lat, e := toF64(args[0])
+		if e != nil {
+			return e, false
+		}
+		lng, e := toF64(args[1])
+		if e != nil {
+			return e, false
+		}
+		polygons, e := toPolygons(args[2])
+		if e != nil {
+			return e, false
+		}
+		for _, p := range polygons {
+			if p.contains(lng, lat) {
+				return true, true
+			}
+		}
+		return false, true
+	case "geohash_encode":
+		lat, e := toF64(args[0])
+		if e != nil {
+			return e, false
+		}
+		lng, e := toF64(args[1])
+		if e != nil {
+			return e, false
+		}
+		precision := geohashMaxPrecision
+		if len(args) == 3 {
+			p, ok := args[2].(int)
+			if !ok || p < 1 || p > geohashMaxPrecision {
+				return fmt.Errorf("geohash precision must be an integer between 1 and %d", geohashMaxPrecision), false
+			}
+			precision = p
+		}
+		return geohashEncode(lat, lng, precision), true
+	case "geohash_decode":
+		h, ok := args[0].(string)
+		if !ok {
+			return fmt.Errorf("geohash must be a string"), false
+		}
+		lat, lng, e := geohashDecode(h)
+		if e != nil {
+			return e, false
+		}
+		return latLng(lat, lng), true
+	case "wgs84_to_gcj02", "gcj02_to_wgs84", "gcj02_to_bd09", "bd09_to_gcj02", "wgs84_to_bd09", "bd09_to_wgs84":
+		lat, e := toF64(args[0])
+		if e != nil {
+			return e, false
+		}
+		lng, e := toF64(args[1])
+		if e != nil {
+			return e, false
+		}
+		switch name {
+		case "wgs84_to_gcj02":
+			lat, lng = wgs84ToGcj02(lat, lng)
+		case "gcj02_to_wgs84":
+			lat, lng = gcj02ToWgs84(lat, lng)
+		case "gcj02_to_bd09":
+			lat, lng = gcj02ToBd09(lat, lng)
+		case "bd09_to_gcj02":
+			lat, lng = bd09ToGcj02(lat, lng)
+		case "wgs84_to_bd09":
+			lat, lng = gcj02ToBd09(wgs84ToGcj02(lat, lng))
+		case "bd09_to_wgs84":
+			lat, lng = gcj02ToWgs84(bd09ToGcj02(lat, lng))
+		}
+		return latLng(lat, lng), true
+	default:
+		return fmt.Errorf("unknown function name %s", name), false
+	}
+}
+
+func latLng(lat, lng float64) map[string]interface{} {
+	return map[string]interface{}{"lat": lat, "lng": lng}
+}
+
+func toRadians(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+func haversine(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRadians(lat2 - lat1)
+	dLng := toRadians(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// bearing returns the initial bearing from the first point to the second
+// point, in degrees clockwise from the north between 0 and 360.
+func bearing(lat1, lng1, lat2, lng2 float64) float64 {
+	phi1, phi2 := toRadians(lat1), toRadians(lat2)
+	dLng := toRadians(lng2 - lng1)
+	y := math.Sin(dLng) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLng)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// polygon is a list of rings in (lng, lat) order, the first ring is the
+// exterior and the others are the holes.
+type polygon [][][2]float64
+
+func (p polygon) contains(x, y float64) bool {
+	if len(p) == 0 || !ringContains(p[0], x, y) {
+		return false
+	}
+	for _, hole := range p[1:] {
+		if ringContains(hole, x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// ringContains uses the ray casting algorithm.
+func ringContains(ring [][2]float64, x, y float64) bool {
+	in := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			in = !in
+		}
+	}
+	return in
+}
+
+var polygonCache = struct {
+	sync.RWMutex
+	m map[string][]polygon
+}{m: make(map[string][]polygon)}
+
+// toPolygons converts a GeoJSON Polygon, MultiPolygon or Feature, or a WKT
+// POLYGON or MULTIPOLYGON into polygons. The parse results of the strings
+// are cached because the polygon is a constant in most rules.
+func toPolygons(arg interface{}) ([]polygon, error) {
+	switch v := arg.(type) {
+	case map[string]interface{}:
+		return geojsonPolygons(v)
+	case string:
+		polygonCache.RLock()
+		ps, ok := polygonCache.m[v]
+		polygonCache.RUnlock()
+		if ok {
+			return ps, nil
+		}
+		var err error
+		s := strings.TrimSpace(v)
+		if strings.HasPrefix(s, "{") {
+			m := make(map[string]interface{})
+			if err = json.Unmarshal([]byte(s), &m); err != nil {
+				return nil, fmt.Errorf("invalid GeoJSON polygon %s: %s", v, err)
+			}
+			ps, err = geojsonPolygons(m)
+		} else {
+			ps, err = wktPolygons(s)
+		}
+		if err != nil {
+			return nil, err
+		}
+		polygonCache.Lock()
+		if len(polygonCache.m) >= maxCachedPolygons {
+			polygonCache.m = make(map[string][]polygon)
+		}
+		polygonCache.m[v] = ps
+		polygonCache.Unlock()
+		return ps, nil
+	default:
+		return nil, fmt.Errorf("polygon must be a GeoJSON or WKT string, but got %v", arg)
+	}
+}
+
+func geojsonPolygons(m map[string]interface{}) ([]polygon, error) {
+	switch m["type"] {
+	case "Feature":
+		g, ok := m["geometry"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid GeoJSON feature, geometry is missing")
+		}
+		return geojsonPolygons(g)
+	case "Polygon":
+		p, err := geojsonPolygon(m["coordinates"])
+		if err != nil {
+			return nil, err
+		}
+		return []polygon{p}, nil
+	case "MultiPolygon":
+		cs, ok := m["coordinates"].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid GeoJSON coordinates %v", m["coordinates"])
+		}
+		result := make([]polygon, 0, len(cs))
+		for _, c := range cs {
+			p, err := geojsonPolygon(c)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, p)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported GeoJSON type %v, expect Polygon, MultiPolygon or Feature", m["type"])
+	}
+}
+
+func geojsonPolygon(c interface{}) (polygon, error) {
+	rings, ok := c.([]interface{})
+	if !ok || len(rings) == 0 {
+		return nil, fmt.Errorf("invalid GeoJSON coordinates %v", c)
+	}
+	p := make(polygon, 0, len(rings))
+	for _, r := range rings {
+		points, ok := r.([]interface{})
+		if !ok || len(points) < 3 {
+			return nil, fmt.Errorf("invalid GeoJSON linear ring %v", r)
+		}
+		ring := make([][2]float64, 0, len(points))
+		for _, pt := range points {
+			xy, ok := pt.([]interface{})
+			if !ok || len(xy) < 2 {
+				return nil, fmt.Errorf("invalid GeoJSON position %v", pt)
+			}
+			x, e1 := toF64(xy[0])
+			y, e2 := toF64(xy[1])
+			if e1 != nil || e2 != nil {
+				return nil, fmt.Errorf("invalid GeoJSON position %v", pt)
+			}
+			ring = append(ring, [2]float64{x, y})
+		}
+		p = append(p, ring)
+	}
+	return p, nil
+}
+
+func wktPolygons(s string) ([]polygon, error) {
+	upper := strings.ToUpper(s)
+	var body string
+	multi := false
+	switch {
+	case strings.HasPrefix(upper, "MULTIPOLYGON"):
+		body, multi = strings.TrimSpace(s[len("MULTIPOLYGON"):]), true
+	case strings.HasPrefix(upper, "POLYGON"):
+		body = strings.TrimSpace(s[len("POLYGON"):])
+	default:
+		return nil, fmt.Errorf("invalid polygon %s, expect GeoJSON or WKT POLYGON/MULTIPOLYGON", s)
+	}
+	groups, err := splitWktGroups(body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid WKT %s: %s", s, err)
+	}
+	if !multi {
+		groups = []string{body}
+	}
+	result := make([]polygon, 0, len(groups))
+	for _, g := range groups {
+		rings, err := splitWktGroups(g)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WKT %s: %s", s, err)
+		}
+		p := make(polygon, 0, len(rings))
+		for _, r := range rings {
+			ring, err := wktRing(strings.Trim(r, "() "))
+			if err != nil {
+				return nil, fmt.Errorf("invalid WKT %s: %s", s, err)
+			}
+			p = append(p, ring)
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// splitWktGroups splits "((a), (b))" into "(a)" and "(b)".
+func splitWktGroups(s string) ([]string, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	s = s[1 : len(s)-1]
+	var (
+		groups []string
+		depth  int
+		start  = -1
+	)
+	for i, c := range s {
+		switch c {
+		case '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+			if depth == 0 {
+				groups = append(groups, s[start:i+1])
+			}
+		}
+	}
+	if depth != 0 || len(groups) == 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	return groups, nil
+}
+
+func wktRing(s string) ([][2]float64, error) {
+	points := strings.Split(s, ",")
+	if len(points) < 3 {
+		return nil, fmt.Errorf("linear ring %s must have at least 3 points", s)
+	}
+	ring := make([][2]float64, 0, len(points))
+	for _, pt := range points {
+		xy := strings.Fields(pt)
+		if len(xy) < 2 {
+			return nil, fmt.Errorf("invalid point %s", pt)
+		}
+		x, e1 := strconv.ParseFloat(xy[0], 64)
+		y, e2 := strconv.ParseFloat(xy[1], 64)
+		if e1 != nil || e2 != nil {
+			return nil, fmt.Errorf("invalid point %s", pt)
+		}
+		ring = append(ring, [2]float64{x, y})
+	}
+	return ring, nil
+}
+
+func geohashEncode(lat, lng float64, precision int) string {
+	latRange, lngRange := [2]float64{-90, 90}, [2]float64{-180, 180}
+	var (
+		sb    strings.Builder
+		bit   uint
+		ch    int
+		isLng = true
+	)
+	for sb.Len() < precision {
+		if isLng {
+			mid := (lngRange[0] + lngRange[1]) / 2
+			if lng >= mid {
+				ch |= 1 << (4 - bit)
+				lngRange[0] = mid
+			} else {
+				lngRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat >= mid {
+				ch |= 1 << (4 - bit)
+				latRange[0] = mid
+			} else {
+				latRange[1] = mid
+			}
+		}
+		isLng = !isLng
+		if bit < 4 {
+			bit++
+		} else {
+			sb.WriteByte(geohashBase32[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return sb.String()
+}
+
+// geohashDecode returns the center of the geohash cell.
+func geohashDecode(h string) (float64, float64, error) {
+	if h == "" {
+		return 0, 0, fmt.Errorf("geohash must not be empty")
+	}
+	latRange, lngRange := [2]float64{-90, 90}, [2]float64{-180, 180}
+	isLng := true
+	for _, c := range strings.ToLower(h) {
+		idx := strings.IndexRune(geohashBase32, c)
+		if idx < 0 {
+			return 0, 0, fmt.Errorf("invalid geohash %s", h)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			on := idx&(1<<uint(bit)) != 0
+			if isLng {
+				mid := (lngRange[0] + lngRange[1]) / 2
+				if on {
+					lngRange[0] = mid
+				} else {
+					lngRange[1] = mid
+				}
+			} else {
+				mid := (latRange[0] + latRange[1]) / 2
+				if on {
+					latRange[0] = mid
+				} else {
+					latRange[1] = mid
+				}
+			}
+			isLng = !isLng
+		}
+	}
+	return (latRange[0] + latRange[1]) / 2, (lngRange[0] + lngRange[1]) / 2, nil
+}
+
+// GCJ-02 is only applied inside China, the coordinates outside are returned
+// as they are.
+func outOfChina(lat, lng float64) bool {
+	return lng < 72.004 || lng > 137.8347 || lat < 0.8293 || lat > 55.8271
+}
+
+func gcjDelta(lat, lng float64) (float64, float64) {
+	x, y := lng-105.0, lat-35.0
+	dLat := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	dLat += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	dLat += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	dLat += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	dLng := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	dLng += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	dLng += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	dLng += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+
+	radLat := toRadians(lat)
+	magic := math.Sin(radLat)
+	magic = 1 - gcjEE*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+	dLat = (dLat * 180.0) / ((gcjA * (1 - gcjEE)) / (magic * sqrtMagic) * math.Pi)
+	dLng = (dLng * 180.0) / (gcjA / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return dLat, dLng
+}
+
+func wgs84ToGcj02(lat, lng float64) (float64, float64) {
+	if outOfChina(lat, lng) {
+		return lat, lng
+	}
+	dLat, dLng := gcjDelta(lat, lng)
+	return lat + dLat, lng + dLng
+}
+
+// gcj02ToWgs84 inverts the offset iteratively, the error is below 1e-6
+// degrees after a few iterations.
+func gcj02ToWgs84(lat, lng float64) (float64, float64) {
+	if outOfChina(lat, lng) {
+		return lat, lng
+	}
+	wLat, wLng := lat, lng
+	for i := 0; i < 10; i++ {
+		gLat, gLng := wgs84ToGcj02(wLat, wLng)
+		eLat, eLng := gLat-lat, gLng-lng
+		if math.Abs(eLat) < 1e-9 && math.Abs(eLng) < 1e-9 {
+			break
+		}
+		wLat, wLng = wLat-eLat, wLng-eLng
+	}
+	return wLat, wLng
+}
+
+func gcj02ToBd09(lat, lng float64) (float64, float64) {
+	z := math.Sqrt(lng*lng+lat*lat) + 0.00002*math.Sin(lat*bdXPi)
+	theta := math.Atan2(lat, lng) + 0.000003*math.Cos(lng*bdXPi)
+	return z*math.Sin(theta) + 0.006, z*math.Cos(theta) + 0.0065
+}
+
+func bd09ToGcj02(lat, lng float64) (float64, float64) {
+	x, y := lng-0.0065, lat-0.006
+	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*bdXPi)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bdXPi)
+	return z * math.Sin(theta), z * math.Cos(theta)
+}
@@ -0,0 +1,271 @@
+package xsql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/plugins"
+	"github.com/cloustone/pandas/kuiper/templates"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+// The timeout in milliseconds of a script function if not specified
+const defaultScriptTimeout = 100
+
+// The maximum size in bytes of the output of a script function
+const maxScriptOutput = 1 << 20
+
+// The languages of the script functions, the template scripts can only call
+// the functions of the templates package so that they have no access to the
+// host.
+var scriptLanguages = map[string]bool{"template": true}
+
+// The user defined functions registered by this package, indexed by the lower
+// case name, they are used to check the calls between the functions.
+var (
+	userFuncs  = make(map[string]*CreateFunctionStatement)
+	userFuncMu sync.RWMutex
+)
+
+// RegisterFunction validates the function defined by the CREATE FUNCTION
+// statement and makes it available to the rules.
+func RegisterFunction(stmt *CreateFunctionStatement) error {
+	name := strings.ToLower(stmt.Name)
+	if isBuiltinFunc(name) {
+		return fmt.Errorf("function %s is a built-in function", stmt.Name)
+	}
+	var f api.Function
+	if stmt.Language == "" {
+		if err := validateFuncExpr(stmt); err != nil {
+			return err
+		}
+		f = &sqlFunc{stmt: stmt}
+	} else {
+		sf, err := newScriptFunc(stmt)
+		if err != nil {
+			return err
+		}
+		f = sf
+	}
+
+	userFuncMu.Lock()
+	defer userFuncMu.Unlock()
+	if err := plugins.RegisterFunction(name, func() api.Function { return f }); err != nil {
+		return err
+	}
+	userFuncs[name] = stmt
+	return nil
+}
+
+// UnregisterFunction removes the user defined function, the rules running
+// already keep using it.
+func UnregisterFunction(name string) {
+	userFuncMu.Lock()
+	defer userFuncMu.Unlock()
+	name = strings.ToLower(name)
+	if _, ok := userFuncs[name]; ok {
+		plugins.UnregisterFunction(name)
+		delete(userFuncs, name)
+	}
+}
+
+func isBuiltinFunc(name string) bool {
+	for _, m := range []map[string]string{aggFuncMap, mathFuncMap, strFuncMap, convFuncMap, hashFuncMap, jsonFuncMap, geoFuncMap, patternFuncMap, otherFuncMap} {
+		if _, ok := m[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFuncExpr checks the expression only refers to the parameters and
+// calls neither the aggregate functions nor the function itself through the
+// other user defined functions.
+func validateFuncExpr(stmt *CreateFunctionStatement) error {
+	params := make(map[string]bool)
+	for _, p := range stmt.Params {
+		if params[strings.ToLower(p)] {
+			return fmt.Errorf("duplicate parameter %s of function %s", p, stmt.Name)
+		}
+		params[strings.ToLower(p)] = true
+	}
+	var err error
+	WalkFunc(stmt.Expr, func(n Node) {
+		if err != nil {
+			return
+		}
+		switch e := n.(type) {
+		case *FieldRef:
+			if e.StreamName != "" || !params[strings.ToLower(e.Name)] {
+				err = fmt.Errorf("unknown parameter %s of function %s", e.Name, stmt.Name)
+			}
+		case *MetaRef, *Wildcard:
+			err = fmt.Errorf("function %s can only refer to its parameters", stmt.Name)
+		case *Call:
+			if isAggFunc(e) {
+				err = fmt.Errorf("aggregate function %s is not allowed in function %s", e.Name, stmt.Name)
+			} else if callsFunc(e, strings.ToLower(stmt.Name), make(map[string]bool)) {
+				err = fmt.Errorf("function %s is called recursively", stmt.Name)
+			}
+		}
+	})
+	return err
+}
+
+// callsFunc checks if the call reaches the function of the name through the
+// user defined functions.
+func callsFunc(c *Call, name string, visited map[string]bool) bool {
+	cn := strings.ToLower(c.Name)
+	if cn == name {
+		return true
+	}
+	if visited[cn] {
+		return false
+	}
+	visited[cn] = true
+	userFuncMu.RLock()
+	stmt, ok := userFuncs[cn]
+	userFuncMu.RUnlock()
+	if !ok || stmt.Expr == nil {
+		return false
+	}
+	found := false
+	WalkFunc(stmt.Expr, func(n Node) {
+		if nc, ok := n.(*Call); ok && !found {
+			found = callsFunc(nc, name, visited)
+		}
+	})
+	return found
+}
+
+func validateArgs(name string, params []string, args []interface{}) error {
+	if len(args) != len(params) {
+		return fmt.Errorf("function %s expects %d arguments but found %d", name, len(params), len(args))
+	}
+	return nil
+}
+
+// sqlFunc evaluates the expression with the parameters bound to the arguments.
+type sqlFunc struct {
+	stmt *CreateFunctionStatement
+}
+
+func (f *sqlFunc) Validate(args []interface{}) error {
+	return validateArgs(f.stmt.Name, f.stmt.Params, args)
+}
+
+func (f *sqlFunc) Exec(args []interface{}, ctx api.FunctionContext) (interface{}, bool) {
+	if err := validateArgs(f.stmt.Name, f.stmt.Params, args); err != nil {
+		return err, false
+	}
+	m := make(Message, len(args))
+	for i, p := range f.stmt.Params {
+		m[p] = args[i]
+	}
+	ve := &ValuerEval{Valuer: MultiValuer(m, NewFunctionValuer(NewFuncPlugins(ctx)))}
+	result := ve.Eval(f.stmt.Expr)
+	if e, ok := result.(error); ok {
+		return e, false
+	}
+	return result, true
+}
+
+func (f *sqlFunc) IsAggregate() bool {
+	return false
+}
+
+// scriptFunc runs the script with the parameters bound to the arguments. The
+// output is parsed as JSON, it is returned as a string if it is not JSON.
+type scriptFunc struct {
+	stmt    *CreateFunctionStatement
+	tmpl    *template.Template
+	timeout time.Duration
+}
+
+func newScriptFunc(stmt *CreateFunctionStatement) (*scriptFunc, error) {
+	if !scriptLanguages[stmt.Language] {
+		return nil, fmt.Errorf("unsupported language %s of function %s", stmt.Language, stmt.Name)
+	}
+	tmpl, err := template.New(stmt.Name).Funcs(templates.FuncMap()).Option("missingkey=zero").Parse(stmt.Script)
+	if err != nil {
+		return nil, fmt.Errorf("invalid script of function %s: %s", stmt.Name, err)
+	}
+	timeout := stmt.Timeout
+	if timeout <= 0 {
+		timeout = defaultScriptTimeout
+	}
+	return &scriptFunc{
+		stmt:    stmt,
+		tmpl:    tmpl,
+		timeout: time.Duration(timeout) * time.Millisecond,
+	}, nil
+}
+
+func (f *scriptFunc) Validate(args []interface{}) error {
+	return validateArgs(f.stmt.Name, f.stmt.Params, args)
+}
+
+func (f *scriptFunc) Exec(args []interface{}, _ api.FunctionContext) (interface{}, bool) {
+	if err := validateArgs(f.stmt.Name, f.stmt.Params, args); err != nil {
+		return err, false
+	}
+	data := make(map[string]interface{}, len(args))
+	for i, p := range f.stmt.Params {
+		data[p] = args[i]
+	}
+	// The script runs in the calling goroutine, the output writer fails once
+	// the deadline passes or the output grows too large so that the template
+	// stops at its next write.
+	w := &scriptWriter{deadline: time.Now().Add(f.timeout), max: maxScriptOutput}
+	if err := f.tmpl.Execute(w, data); err != nil {
+		switch {
+		case err == errScriptTimeout:
+			return fmt.Errorf("run function %s timeout after %s", f.stmt.Name, f.timeout), false
+		case err == errScriptOutput:
+			return fmt.Errorf("run function %s error: output exceeds %d bytes", f.stmt.Name, maxScriptOutput), false
+		}
+		return fmt.Errorf("run function %s error: %s", f.stmt.Name, err), false
+	}
+	if time.Now().After(w.deadline) {
+		return fmt.Errorf("run function %s timeout after %s", f.stmt.Name, f.timeout), false
+	}
+	out := strings.TrimSpace(w.buf.String())
+	var result interface{}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		return out, true
+	}
+	return result, true
+}
+
+func (f *scriptFunc) IsAggregate() bool {
+	return false
+}
+
+var (
+	errScriptTimeout = errors.New("script deadline exceeded")
+	errScriptOutput  = errors.New("script output limit exceeded")
+)
+
+// scriptWriter buffers the output of a script and fails when the deadline
+// passes or the output exceeds the maximum size.
+type scriptWriter struct {
+	buf      bytes.Buffer
+	deadline time.Time
+	max      int
+}
+
+func (w *scriptWriter) Write(p []byte) (int, error) {
+	if time.Now().After(w.deadline) {
+		return 0, errScriptTimeout
+	}
+	if w.buf.Len()+len(p) > w.max {
+		return 0, errScriptOutput
+	}
+	return w.buf.Write(p)
+}
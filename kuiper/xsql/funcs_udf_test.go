@@ -0,0 +1,135 @@
+package xsql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/plugins"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+)
+
+func parseFunction(t *testing.T, s string) *CreateFunctionStatement {
+	stmt, err := Language.Parse(NewParser(strings.NewReader(s)))
+	if err != nil {
+		t.Fatalf("parse %s error: %s", s, err)
+	}
+	return stmt.(*CreateFunctionStatement)
+}
+
+func TestUserFunctions(t *testing.T) {
+	defs := []string{
+		`CREATE FUNCTION fahrenheit(c) AS c * 1.8 + 32`,
+		`CREATE FUNCTION hot(c) AS fahrenheit(c) > 100`,
+		`CREATE FUNCTION level(t) LANGUAGE template AS "{{if gt .t 30.0}}\"hot\"{{else}}{\"t\":{{.t}}}{{end}}"`,
+	}
+	for _, d := range defs {
+		if err := RegisterFunction(parseFunction(t, d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		for _, n := range []string{"fahrenheit", "hot", "level"} {
+			UnregisterFunction(n)
+		}
+	}()
+
+	var tests = []struct {
+		name   string
+		args   []interface{}
+		result interface{}
+	}{
+		{"fahrenheit", []interface{}{100}, 212.0},
+		{"HOT", []interface{}{40}, true},
+		{"hot", []interface{}{20}, false},
+		{"level", []interface{}{40.5}, "hot"},
+		{"level", []interface{}{20.5}, map[string]interface{}{"t": 20.5}},
+	}
+	ctx := contexts.NewDefaultFuncContext(contexts.Background(), 0)
+	for i, tt := range tests {
+		f, err := plugins.GetFunction(tt.name)
+		if err != nil {
+			t.Errorf("%d. %s", i, err)
+			continue
+		}
+		r, ok := f.Exec(tt.args, ctx)
+		if !ok {
+			t.Errorf("%d. run %s error: %v", i, tt.name, r)
+			continue
+		}
+		if !reflect.DeepEqual(tt.result, r) {
+			t.Errorf("%d. %s result mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", i, tt.name, tt.result, r)
+		}
+	}
+
+	// The functions are usable in the rules
+	stmt, err := NewParser(strings.NewReader(`SELECT fahrenheit(temperature) AS f FROM demo WHERE hot(temperature)`)).Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ve := &ValuerEval{Valuer: MultiValuer(Message{"temperature": 50}, NewFunctionValuer(NewFuncPlugins(contexts.Background())))}
+	if r := ve.Eval(stmt.Condition); r != true {
+		t.Errorf("expect condition true but got %v", r)
+	}
+}
+
+func TestUserFunctionErrors(t *testing.T) {
+	if err := RegisterFunction(parseFunction(t, `CREATE FUNCTION double(a) AS a * 2`)); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterFunction("double")
+
+	var tests = []struct {
+		s   string
+		err string
+	}{
+		{`CREATE FUNCTION double(a) AS a * 3`, "function double already exists"},
+		{`CREATE FUNCTION abs(a) AS a`, "function abs is a built-in function"},
+		{`CREATE FUNCTION f(a) AS a + b`, "unknown parameter b of function f"},
+		{`CREATE FUNCTION f(a, a) AS a`, "duplicate parameter a of function f"},
+		{`CREATE FUNCTION f(a) AS sum(a)`, "aggregate function sum is not allowed in function f"},
+		{`CREATE FUNCTION f(a) LANGUAGE lua AS "return a"`, "unsupported language lua of function f"},
+		{`CREATE FUNCTION f(a) LANGUAGE template AS "{{.a"`, "invalid script of function f"},
+	}
+	for i, tt := range tests {
+		err := RegisterFunction(parseFunction(t, tt.s))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("%d. expect error %s but got %v", i, tt.err, err)
+			UnregisterFunction("f")
+		}
+	}
+
+	// Calling itself through other functions is rejected
+	if err := RegisterFunction(parseFunction(t, `CREATE FUNCTION quad(a) AS double(double(a))`)); err != nil {
+		t.Fatal(err)
+	}
+	defer UnregisterFunction("quad")
+	UnregisterFunction("double")
+	if err := RegisterFunction(parseFunction(t, `CREATE FUNCTION double(a) AS quad(a) / 2`)); err == nil || err.Error() != "function double is called recursively" {
+		t.Errorf("expect recursive call error but got %v", err)
+	}
+}
+
+func TestScriptFunctionTimeout(t *testing.T) {
+	stmt := parseFunction(t, `CREATE FUNCTION slow(a) LANGUAGE template TIMEOUT 1 AS "{{range .a}}{{range $.a}}{{range $.a}}{{.}}{{end}}{{end}}{{end}}"`)
+	f, err := newScriptFunc(stmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := make([]int, 300)
+	if r, ok := f.Exec([]interface{}{arr}, nil); ok || !strings.Contains(r.(error).Error(), "timeout") {
+		t.Errorf("expect timeout but got %v", r)
+	}
+}
+
+func TestScriptFunctionOutputLimit(t *testing.T) {
+	stmt := parseFunction(t, `CREATE FUNCTION big(a) LANGUAGE template TIMEOUT 10000 AS "{{range .a}}{{range $.a}}{{range $.a}}0123456789{{end}}{{end}}{{end}}"`)
+	f, err := newScriptFunc(stmt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	arr := make([]int, 300)
+	if r, ok := f.Exec([]interface{}{arr}, nil); ok || !strings.Contains(r.(error).Error(), "output exceeds") {
+		t.Errorf("expect output limit error but got %v", r)
+	}
+}
@@ -1,6 +1,7 @@
 package xsql
 
 import (
+	"fmt"
 	"github.com/cloustone/pandas/kuiper/plugins"
 	"strings"
 )
@@ -76,6 +77,15 @@ var jsonFuncMap = map[string]string{
 	"json_path_query": "", "json_path_query_first": "", "json_path_exists": "",
 }
 
+var geoFuncMap = map[string]string{"st_distance": "", "st_within_circle": "", "st_within_polygon": "", "st_bearing": "",
+	"geohash_encode": "", "geohash_decode": "",
+	"wgs84_to_gcj02": "", "gcj02_to_wgs84": "", "gcj02_to_bd09": "", "bd09_to_gcj02": "", "wgs84_to_bd09": "", "bd09_to_wgs84": "",
+}
+
+// patternFuncMap contains the navigation functions of MATCH_RECOGNIZE, they
+// are evaluated by the match plan.
+var patternFuncMap = map[string]string{"prev": "", "first": "", "last": ""}
+
 var otherFuncMap = map[string]string{"isnull": "",
 	"newuuid": "", "tstamp": "", "mqtt": "", "meta": "",
 }
@@ -92,8 +102,12 @@ func (fv *FunctionValuer) Call(name string, args []interface{}) (interface{}, bo
 		return hashCall(lowerName, args)
 	} else if _, ok := jsonFuncMap[lowerName]; ok {
 		return jsonCall(lowerName, args)
+	} else if _, ok := geoFuncMap[lowerName]; ok {
+		return geoCall(lowerName, args)
 	} else if _, ok := otherFuncMap[lowerName]; ok {
 		return otherCall(lowerName, args)
+	} else if _, ok := patternFuncMap[lowerName]; ok {
+		return fmt.Errorf("function %s is only allowed in MATCH_RECOGNIZE", name), false
 	} else if _, ok := aggFuncMap[lowerName]; ok {
 		return nil, false
 	} else {
@@ -143,6 +157,10 @@ func isAggFunc(f *Call) bool {
 		return false
 	} else if _, ok := otherFuncMap[fn]; ok {
 		return false
+	} else if _, ok := geoFuncMap[fn]; ok {
+		return false
+	} else if _, ok := patternFuncMap[fn]; ok {
+		return false
 	} else if _, ok := mathFuncMap[fn]; ok {
 		return false
 	} else {
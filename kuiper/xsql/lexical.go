@@ -64,6 +64,9 @@ const (
 	COLON     //:
 	SEMICOLON //;
 	COLSEP    //\007
+	LBRACE    //{
+	RBRACE    //}
+	QUESTION  //?
 
 	// Keywords
 	SELECT
@@ -83,6 +86,7 @@ const (
 	ASC
 	DESC
 	FILTER
+	MATCH_RECOGNIZE
 
 	TRUE
 	FALSE
@@ -94,6 +98,8 @@ const (
 	SHOW
 	STREAM
 	STREAMS
+	TABLE
+	TABLES
 	WITH
 
 	XBIGINT
@@ -112,6 +118,8 @@ const (
 	STRICT_VALIDATION
 	TIMESTAMP
 	TIMESTAMP_FORMAT
+	RETAIN_SIZE
+	SCHEMAID
 
 	DD
 	HH
@@ -161,6 +169,9 @@ var tokens = []string{
 	SEMICOLON: ";",
 	COLON:     ":",
 	COLSEP:    "\007",
+	LBRACE:    "{",
+	RBRACE:    "}",
+	QUESTION:  "?",
 
 	SELECT: "SELECT",
 	FROM:   "FROM",
@@ -176,6 +187,8 @@ var tokens = []string{
 	ASC:    "ASC",
 	DESC:   "DESC",
 
+	MATCH_RECOGNIZE: "MATCH_RECOGNIZE",
+
 	CREATE:   "CREATE",
 	DROP:     "RROP",
 	EXPLAIN:  "EXPLAIN",
@@ -183,6 +196,8 @@ var tokens = []string{
 	SHOW:     "SHOW",
 	STREAM:   "STREAM",
 	STREAMS:  "STREAMS",
+	TABLE:    "TABLE",
+	TABLES:   "TABLES",
 	WITH:     "WITH",
 
 	XBIGINT:   "BIGINT",
@@ -201,6 +216,8 @@ var tokens = []string{
 	STRICT_VALIDATION: "STRICT_VALIDATION",
 	TIMESTAMP:         "TIMESTAMP",
 	TIMESTAMP_FORMAT:  "TIMESTAMP_FORMAT",
+	RETAIN_SIZE:       "RETAIN_SIZE",
+	SCHEMAID:          "SCHEMAID",
 
 	AND:   "AND",
 	OR:    "OR",
@@ -345,6 +362,12 @@ func (s *Scanner) Scan() (tok Token, lit string) {
 		return HASH, tokens[HASH]
 	case ';':
 		return SEMICOLON, tokens[SEMICOLON]
+	case '{':
+		return LBRACE, tokens[LBRACE]
+	case '}':
+		return RBRACE, tokens[RBRACE]
+	case '?':
+		return QUESTION, tokens[QUESTION]
 	}
 	return ILLEGAL, ""
 }
@@ -390,6 +413,8 @@ func (s *Scanner) ScanIdent() (tok Token, lit string) {
 		return ASC, lit
 	case "FILTER":
 		return FILTER, lit
+	case "MATCH_RECOGNIZE":
+		return MATCH_RECOGNIZE, lit
 	case "INNER":
 		return INNER, lit
 	case "LEFT":
@@ -454,6 +479,8 @@ func (s *Scanner) ScanIdent() (tok Token, lit string) {
 		return TIMESTAMP, lit
 	case "TIMESTAMP_FORMAT":
 		return TIMESTAMP_FORMAT, lit
+	case "SCHEMAID":
+		return SCHEMAID, lit
 	case "DD":
 		return DD, lit
 	case "HH":
@@ -121,6 +121,12 @@ func (p *Parser) Parse() (*SelectStatement, error) {
 		selects.Sources = src
 	}
 
+	if mr, err := p.parseMatchRecognize(); err != nil {
+		return nil, err
+	} else {
+		selects.MatchRecognize = mr
+	}
+
 	if joins, err := p.parseJoins(); err != nil {
 		return nil, err
 	} else {
@@ -210,6 +216,231 @@ func (p *Parser) parseSourceLiteral() (string, string, error) {
 	return strings.Join(sourceSeg, ""), alias, nil
 }
 
+func (p *Parser) parseMatchRecognize() (*MatchRecognize, error) {
+	if tok, _ := p.scanIgnoreWhitespace(); tok != MATCH_RECOGNIZE {
+		p.unscan()
+		return nil, nil
+	}
+	if tok, lit := p.scanIgnoreWhitespace(); tok != LPAREN {
+		return nil, fmt.Errorf("found %q, expected left paren after MATCH_RECOGNIZE.", lit)
+	}
+	mr := &MatchRecognize{}
+	tok, lit := p.scanIgnoreWhitespace()
+	if tok == IDENT && strings.ToUpper(lit) == "PARTITION" {
+		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 != BY {
+			return nil, fmt.Errorf("found %q, expected BY keyword.", lit1)
+		}
+		for {
+			exp, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := exp.(*FieldRef); !ok {
+				return nil, fmt.Errorf("expect field name in PARTITION BY of MATCH_RECOGNIZE.")
+			}
+			mr.PartitionBy = append(mr.PartitionBy, exp)
+			if tok1, _ := p.scanIgnoreWhitespace(); tok1 != COMMA {
+				p.unscan()
+				break
+			}
+		}
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+
+	if tok != IDENT || strings.ToUpper(lit) != "MEASURES" {
+		return nil, fmt.Errorf("found %q, expected MEASURES keyword.", lit)
+	}
+	for {
+		field, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		if field.AName == "" {
+			return nil, fmt.Errorf("alias is required for the measure %s of MATCH_RECOGNIZE.", field.Name)
+		}
+		mr.Measures = append(mr.Measures, *field)
+		if tok1, _ := p.scanIgnoreWhitespace(); tok1 != COMMA {
+			p.unscan()
+			break
+		}
+	}
+
+	if tok, lit = p.scanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "PATTERN" {
+		return nil, fmt.Errorf("found %q, expected PATTERN keyword.", lit)
+	}
+	pattern, err := p.parsePattern()
+	if err != nil {
+		return nil, err
+	}
+	mr.Pattern = pattern
+
+	tok, lit = p.scanIgnoreWhitespace()
+	if tok == IDENT && strings.ToUpper(lit) == "WITHIN" {
+		if mr.Within, err = p.parseWithin(); err != nil {
+			return nil, err
+		}
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+
+	if tok != IDENT || strings.ToUpper(lit) != "DEFINE" {
+		return nil, fmt.Errorf("found %q, expected DEFINE keyword.", lit)
+	}
+	for {
+		tok1, lit1 := p.scanIgnoreWhitespace()
+		if tok1 != IDENT {
+			return nil, fmt.Errorf("found %q, expected pattern variable in DEFINE.", lit1)
+		}
+		if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 != AS {
+			return nil, fmt.Errorf("found %q, expected AS keyword.", lit2)
+		}
+		cond, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		mr.Defines = append(mr.Defines, &PatternDefine{Variable: lit1, Condition: cond})
+		if tok2, _ := p.scanIgnoreWhitespace(); tok2 != COMMA {
+			p.unscan()
+			break
+		}
+	}
+
+	if tok, lit = p.scanIgnoreWhitespace(); tok != RPAREN {
+		return nil, fmt.Errorf("found %q, expected right paren of MATCH_RECOGNIZE.", lit)
+	}
+	if err := validateMatchRecognize(mr); err != nil {
+		return nil, err
+	}
+	return mr, nil
+}
+
+// parsePattern parses the sequence of the pattern variables with the
+// optional quantifiers *, +, ?, {n}, {n,} and {n,m}.
+func (p *Parser) parsePattern() ([]*PatternElement, error) {
+	if tok, lit := p.scanIgnoreWhitespace(); tok != LPAREN {
+		return nil, fmt.Errorf("found %q, expected left paren after PATTERN.", lit)
+	}
+	var pattern []*PatternElement
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if tok == RPAREN {
+			break
+		} else if tok != IDENT {
+			return nil, fmt.Errorf("found %q, expected pattern variable.", lit)
+		}
+		e := &PatternElement{Variable: lit, Min: 1, Max: 1}
+		switch tok1, _ := p.scanIgnoreWhitespace(); tok1 {
+		case ASTERISK:
+			e.Min, e.Max = 0, -1
+		case ADD:
+			e.Min, e.Max = 1, -1
+		case QUESTION:
+			e.Min, e.Max = 0, 1
+		case LBRACE:
+			if err := p.parseQuantifier(e); err != nil {
+				return nil, err
+			}
+		default:
+			p.unscan()
+		}
+		pattern = append(pattern, e)
+	}
+	return pattern, nil
+}
+
+func (p *Parser) parseQuantifier(e *PatternElement) error {
+	tok, lit := p.scanIgnoreWhitespace()
+	e.Min, e.Max = 0, -1
+	if tok == INTEGER {
+		e.Min, _ = strconv.Atoi(lit)
+		tok, lit = p.scanIgnoreWhitespace()
+		if tok == RBRACE {
+			e.Max = e.Min
+			return nil
+		}
+	}
+	if tok != COMMA {
+		return fmt.Errorf("found %q, expected comma in quantifier of %s.", lit, e.Variable)
+	}
+	tok, lit = p.scanIgnoreWhitespace()
+	if tok == INTEGER {
+		e.Max, _ = strconv.Atoi(lit)
+		tok, lit = p.scanIgnoreWhitespace()
+	}
+	if tok != RBRACE {
+		return fmt.Errorf("found %q, expected right brace in quantifier of %s.", lit, e.Variable)
+	}
+	return nil
+}
+
+// parseWithin parses WITHIN(unit, length) and returns the milliseconds.
+func (p *Parser) parseWithin() (int, error) {
+	if tok, lit := p.scanIgnoreWhitespace(); tok != LPAREN {
+		return 0, fmt.Errorf("found %q, expected left paren after WITHIN.", lit)
+	}
+	tok, lit := p.scanIgnoreWhitespace()
+	if !tok.isTimeLiteral() {
+		return 0, fmt.Errorf("found %q, expected time unit [dd|hh|mi|ss|ms] in WITHIN.", lit)
+	}
+	unit := tok
+	if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 != COMMA {
+		return 0, fmt.Errorf("found %q, expected comma in WITHIN.", lit1)
+	}
+	tok, lit = p.scanIgnoreWhitespace()
+	length, err := strconv.Atoi(lit)
+	if tok != INTEGER || err != nil || length <= 0 {
+		return 0, fmt.Errorf("found %q, expected positive integer in WITHIN.", lit)
+	}
+	if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 != RPAREN {
+		return 0, fmt.Errorf("found %q, expected right paren of WITHIN.", lit1)
+	}
+	switch unit {
+	case DD:
+		return length * 24 * 3600 * 1000, nil
+	case HH:
+		return length * 3600 * 1000, nil
+	case MI:
+		return length * 60 * 1000, nil
+	case SS:
+		return length * 1000, nil
+	default:
+		return length, nil
+	}
+}
+
+func validateMatchRecognize(mr *MatchRecognize) error {
+	if len(mr.Pattern) == 0 {
+		return fmt.Errorf("PATTERN of MATCH_RECOGNIZE must not be empty.")
+	}
+	vars := make(map[string]bool)
+	empty := true
+	for _, e := range mr.Pattern {
+		if vars[e.Variable] {
+			return fmt.Errorf("pattern variable %s is used more than once.", e.Variable)
+		}
+		vars[e.Variable] = true
+		if e.Max == 0 || (e.Max > 0 && e.Max < e.Min) {
+			return fmt.Errorf("invalid quantifier of pattern variable %s.", e.Variable)
+		}
+		if e.Min > 0 {
+			empty = false
+		}
+	}
+	if empty {
+		return fmt.Errorf("PATTERN of MATCH_RECOGNIZE must not match empty rows.")
+	}
+	defined := make(map[string]bool)
+	for _, d := range mr.Defines {
+		if !vars[d.Variable] {
+			return fmt.Errorf("pattern variable %s in DEFINE is not used in PATTERN.", d.Variable)
+		}
+		if defined[d.Variable] {
+			return fmt.Errorf("pattern variable %s is defined more than once.", d.Variable)
+		}
+		defined[d.Variable] = true
+	}
+	return nil
+}
+
 func (p *Parser) parseFieldNameSections() ([]string, error) {
 	var fieldNameSects []string
 	for {
@@ -771,7 +1002,10 @@ func (p *Parser) ConvertToWindows(wtype WindowType, args []Expr) (*Window, error
 func (p *Parser) ParseCreateStreamStmt() (*StreamStmt, error) {
 	stmt := &StreamStmt{}
 	if tok, _ := p.scanIgnoreWhitespace(); tok == CREATE {
-		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == STREAM {
+		if tok1, lit1 := p.scanStreamKeyword(); tok1 == STREAM || tok1 == TABLE {
+			if tok1 == TABLE {
+				stmt.StreamType = TypeTable
+			}
 			if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 == IDENT {
 				stmt.Name = StreamName(lit2)
 				if fields, err := p.parseStreamFields(); err != nil {
@@ -784,6 +1018,9 @@ func (p *Parser) ParseCreateStreamStmt() (*StreamStmt, error) {
 				} else {
 					stmt.Options = opts
 				}
+				if _, ok := stmt.Options[tokens[RETAIN_SIZE]]; ok && stmt.StreamType != TypeTable {
+					return nil, fmt.Errorf("option RETAIN_SIZE is only supported by table.")
+				}
 				if tok3, lit3 := p.scanIgnoreWhitespace(); tok3 == SEMICOLON {
 					p.unscan()
 					return stmt, nil
@@ -795,10 +1032,10 @@ func (p *Parser) ParseCreateStreamStmt() (*StreamStmt, error) {
 				}
 
 			} else {
-				return nil, fmt.Errorf("found %q, expected stream name.", lit2)
+				return nil, fmt.Errorf("found %q, expected %s name.", lit2, StreamTypeMap[stmt.StreamType])
 			}
 		} else {
-			return nil, fmt.Errorf("found %q, expected keyword stream.", lit1)
+			return nil, fmt.Errorf("found %q, expected keyword stream or table.", lit1)
 		}
 	} else {
 		p.unscan()
@@ -807,17 +1044,143 @@ func (p *Parser) ParseCreateStreamStmt() (*StreamStmt, error) {
 	return stmt, nil
 }
 
+// parseCreateFunctionStmt parses the CREATE FUNCTION statement, nil is
+// returned for the other CREATE statements. FUNCTION, LANGUAGE and TIMEOUT
+// are not reserved so that they can still be used as field names.
+//
+//	CREATE FUNCTION name(param1, param2) AS expr
+//	CREATE FUNCTION name(param1) LANGUAGE template TIMEOUT 100 AS "script"
+func (p *Parser) parseCreateFunctionStmt() (*CreateFunctionStatement, error) {
+	if tok, _ := p.scanIgnoreWhitespace(); tok != CREATE {
+		p.unscan()
+		return nil, nil
+	}
+	if tok, lit := p.scanIgnoreWhitespace(); tok != IDENT || !strings.EqualFold(lit, "FUNCTION") {
+		p.unscan()
+		p.unscan()
+		return nil, nil
+	}
+	stmt := &CreateFunctionStatement{}
+	if tok, lit := p.scanIgnoreWhitespace(); tok == IDENT {
+		stmt.Name = lit
+	} else {
+		return nil, fmt.Errorf("found %q, expected function name.", lit)
+	}
+	if tok, lit := p.scanIgnoreWhitespace(); tok != LPAREN {
+		return nil, fmt.Errorf("found %q, expected (.", lit)
+	}
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if tok == RPAREN && len(stmt.Params) == 0 {
+			break
+		}
+		if tok != IDENT {
+			return nil, fmt.Errorf("found %q, expected parameter name.", lit)
+		}
+		stmt.Params = append(stmt.Params, lit)
+		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == RPAREN {
+			break
+		} else if tok1 != COMMA {
+			return nil, fmt.Errorf("found %q, expected comma or ).", lit1)
+		}
+	}
+	for {
+		tok, lit := p.scanIgnoreWhitespace()
+		if tok == AS {
+			break
+		}
+		if tok != IDENT {
+			return nil, fmt.Errorf("found %q, expected AS.", lit)
+		}
+		switch strings.ToUpper(lit) {
+		case "LANGUAGE":
+			if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == IDENT || tok1 == STRING {
+				stmt.Language = strings.ToLower(lit1)
+			} else {
+				return nil, fmt.Errorf("found %q, expected language name.", lit1)
+			}
+		case "TIMEOUT":
+			if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == INTEGER {
+				if t, err := strconv.Atoi(lit1); err != nil || t <= 0 {
+					return nil, fmt.Errorf("found %q, expected positive integer timeout.", lit1)
+				} else {
+					stmt.Timeout = t
+				}
+			} else {
+				return nil, fmt.Errorf("found %q, expected positive integer timeout.", lit1)
+			}
+		default:
+			return nil, fmt.Errorf("found %q, expected LANGUAGE, TIMEOUT or AS.", lit)
+		}
+	}
+	if stmt.Language == "" {
+		if stmt.Timeout > 0 {
+			return nil, fmt.Errorf("TIMEOUT is only supported by the script functions.")
+		}
+		expr, err := p.ParseExpr()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Expr = expr
+	} else if tok, lit := p.scanIgnoreWhitespace(); tok == STRING {
+		stmt.Script = lit
+	} else {
+		return nil, fmt.Errorf("found %q, expected script string.", lit)
+	}
+	if tok, lit := p.scanIgnoreWhitespace(); tok == SEMICOLON {
+		p.unscan()
+	} else if tok != EOF {
+		return nil, fmt.Errorf("found %q, expected semicolon or EOF.", lit)
+	}
+	return stmt, nil
+}
+
+// streamKeywords are only recognized after the CREATE, SHOW, DESCRIBE, EXPLAIN
+// and DROP keywords or in the stream options. The scanner returns them as
+// identifiers, so they remain valid names of fields and streams.
+var streamKeywords = map[string]Token{
+	tokens[TABLE]:       TABLE,
+	tokens[TABLES]:      TABLES,
+	tokens[RETAIN_SIZE]: RETAIN_SIZE,
+}
+
+// scanStreamKeyword scans the next non-whitespace token, the identifier of a
+// stream keyword is returned as the keyword.
+func (p *Parser) scanStreamKeyword() (tok Token, lit string) {
+	tok, lit = p.scanIgnoreWhitespace()
+	if tok == IDENT {
+		if t, ok := streamKeywords[strings.ToUpper(lit)]; ok {
+			return t, strings.ToUpper(lit)
+		}
+	}
+	return tok, lit
+}
+
+// parseStreamType returns the kind of the stream statement following the
+// keyword of SHOW, DESCRIBE, EXPLAIN and DROP statements.
+func parseStreamType(tok Token) (StreamType, bool) {
+	switch tok {
+	case STREAM, STREAMS:
+		return TypeStream, true
+	case TABLE, TABLES:
+		return TypeTable, true
+	default:
+		return TypeStream, false
+	}
+}
+
 func (p *Parser) parseShowStreamsStmt() (*ShowStreamsStatement, error) {
 	ss := &ShowStreamsStatement{}
 	if tok, _ := p.scanIgnoreWhitespace(); tok == SHOW {
-		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == STREAMS {
+		if tok1, lit1 := p.scanStreamKeyword(); tok1 == STREAMS || tok1 == TABLES {
+			ss.StreamType, _ = parseStreamType(tok1)
 			if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 == EOF || tok2 == SEMICOLON {
 				return ss, nil
 			} else {
 				return nil, fmt.Errorf("found %q, expected semecolon or EOF.", lit2)
 			}
 		} else {
-			return nil, fmt.Errorf("found %q, expected keyword streams.", lit1)
+			return nil, fmt.Errorf("found %q, expected keyword streams or tables.", lit1)
 		}
 	} else {
 		p.unscan()
@@ -829,15 +1192,16 @@ func (p *Parser) parseShowStreamsStmt() (*ShowStreamsStatement, error) {
 func (p *Parser) parseDescribeStreamStmt() (*DescribeStreamStatement, error) {
 	dss := &DescribeStreamStatement{}
 	if tok, _ := p.scanIgnoreWhitespace(); tok == DESCRIBE {
-		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == STREAM {
+		if tok1, lit1 := p.scanStreamKeyword(); tok1 == STREAM || tok1 == TABLE {
+			dss.StreamType, _ = parseStreamType(tok1)
 			if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 == IDENT {
 				dss.Name = lit2
 				return dss, nil
 			} else {
-				return nil, fmt.Errorf("found %q, expected stream name.", lit2)
+				return nil, fmt.Errorf("found %q, expected %s name.", lit2, StreamTypeMap[dss.StreamType])
 			}
 		} else {
-			return nil, fmt.Errorf("found %q, expected keyword stream.", lit1)
+			return nil, fmt.Errorf("found %q, expected keyword stream or table.", lit1)
 		}
 	} else {
 		p.unscan()
@@ -845,18 +1209,39 @@ func (p *Parser) parseDescribeStreamStmt() (*DescribeStreamStatement, error) {
 	}
 }
 
+// parseExplainStmt parses EXPLAIN SELECT for the logical plan of a query,
+// otherwise EXPLAIN STREAM or EXPLAIN TABLE.
+func (p *Parser) parseExplainStmt() (Statement, error) {
+	if tok, _ := p.scanIgnoreWhitespace(); tok != EXPLAIN {
+		p.unscan()
+		return nil, nil
+	}
+	tok, _ := p.scanIgnoreWhitespace()
+	p.unscan()
+	if tok != SELECT {
+		p.unscan()
+		return p.parseExplainStreamsStmt()
+	}
+	stmt, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+	return &ExplainSelectStatement{Select: stmt}, nil
+}
+
 func (p *Parser) parseExplainStreamsStmt() (*ExplainStreamStatement, error) {
 	ess := &ExplainStreamStatement{}
 	if tok, _ := p.scanIgnoreWhitespace(); tok == EXPLAIN {
-		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == STREAM {
+		if tok1, lit1 := p.scanStreamKeyword(); tok1 == STREAM || tok1 == TABLE {
+			ess.StreamType, _ = parseStreamType(tok1)
 			if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 == IDENT {
 				ess.Name = lit2
 				return ess, nil
 			} else {
-				return nil, fmt.Errorf("found %q, expected stream name.", lit2)
+				return nil, fmt.Errorf("found %q, expected %s name.", lit2, StreamTypeMap[ess.StreamType])
 			}
 		} else {
-			return nil, fmt.Errorf("found %q, expected keyword stream.", lit1)
+			return nil, fmt.Errorf("found %q, expected keyword stream or table.", lit1)
 		}
 	} else {
 		p.unscan()
@@ -867,15 +1252,16 @@ func (p *Parser) parseExplainStreamsStmt() (*ExplainStreamStatement, error) {
 func (p *Parser) parseDropStreamsStmt() (*DropStreamStatement, error) {
 	ess := &DropStreamStatement{}
 	if tok, _ := p.scanIgnoreWhitespace(); tok == DROP {
-		if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == STREAM {
+		if tok1, lit1 := p.scanStreamKeyword(); tok1 == STREAM || tok1 == TABLE {
+			ess.StreamType, _ = parseStreamType(tok1)
 			if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 == IDENT {
 				ess.Name = lit2
 				return ess, nil
 			} else {
-				return nil, fmt.Errorf("found %q, expected stream name.", lit2)
+				return nil, fmt.Errorf("found %q, expected %s name.", lit2, StreamTypeMap[ess.StreamType])
 			}
 		} else {
-			return nil, fmt.Errorf("found %q, expected keyword stream.", lit1)
+			return nil, fmt.Errorf("found %q, expected keyword stream or table.", lit1)
 		}
 	} else {
 		p.unscan()
@@ -1043,7 +1429,7 @@ func (p *Parser) parseStreamOptions() (map[string]string, error) {
 	if tok, lit := p.scanIgnoreWhitespace(); tok == LPAREN {
 		lStack.Push(LPAREN)
 		for {
-			if tok1, lit1 := p.scanIgnoreWhitespace(); tok1 == DATASOURCE || tok1 == FORMAT || tok1 == KEY || tok1 == CONF_KEY || tok1 == STRICT_VALIDATION || tok1 == TYPE || tok1 == TIMESTAMP || tok1 == TIMESTAMP_FORMAT {
+			if tok1, lit1 := p.scanStreamKeyword(); tok1 == DATASOURCE || tok1 == FORMAT || tok1 == KEY || tok1 == CONF_KEY || tok1 == STRICT_VALIDATION || tok1 == TYPE || tok1 == TIMESTAMP || tok1 == TIMESTAMP_FORMAT || tok1 == RETAIN_SIZE || tok1 == SCHEMAID {
 				if tok2, lit2 := p.scanIgnoreWhitespace(); tok2 == EQ {
 					if tok3, lit3 := p.scanIgnoreWhitespace(); tok3 == STRING {
 						if tok1 == STRICT_VALIDATION {
@@ -1051,6 +1437,11 @@ func (p *Parser) parseStreamOptions() (map[string]string, error) {
 								return nil, fmt.Errorf("found %q, expect TRUE/FALSE value in %s option.", lit3, tok1)
 							}
 						}
+						if tok1 == RETAIN_SIZE {
+							if val, err := strconv.Atoi(lit3); err != nil || val <= 0 {
+								return nil, fmt.Errorf("found %q, expect positive integer value in %s option.", lit3, tok1)
+							}
+						}
 						opts[lit1] = lit3
 					} else {
 						return nil, fmt.Errorf("found %q, expect string value in option.", lit3)
@@ -1068,7 +1459,7 @@ func (p *Parser) parseStreamOptions() (map[string]string, error) {
 					return nil, fmt.Errorf("Parenthesis is not matched in options definition.")
 				}
 			} else {
-				return nil, fmt.Errorf("found %q, unknown option keys(DATASOURCE|FORMAT|KEY|CONF_KEY|STRICT_VALIDATION|TYPE).", lit1)
+				return nil, fmt.Errorf("found %q, unknown option keys(DATASOURCE|FORMAT|KEY|CONF_KEY|STRICT_VALIDATION|TYPE|RETAIN_SIZE|SCHEMAID).", lit1)
 			}
 		}
 	} else {
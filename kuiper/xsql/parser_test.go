@@ -38,6 +38,43 @@ func TestParser_ParseStatement(t *testing.T) {
 				Sources: []Source{&Table{Name: "tbl"}},
 			},
 		},
+		{
+			s: `SELECT table, tables, retain_size FROM demo`,
+			stmt: &SelectStatement{
+				Fields: []Field{
+					{
+						Expr:  &FieldRef{Name: "table"},
+						Name:  "table",
+						AName: ""},
+					{
+						Expr:  &FieldRef{Name: "tables"},
+						Name:  "tables",
+						AName: ""},
+					{
+						Expr:  &FieldRef{Name: "retain_size"},
+						Name:  "retain_size",
+						AName: ""},
+				},
+				Sources: []Source{&Table{Name: "demo"}},
+			},
+		},
+		{
+			s: `SELECT name FROM table WHERE table.id = 1`,
+			stmt: &SelectStatement{
+				Fields: []Field{
+					{
+						Expr:  &FieldRef{Name: "name"},
+						Name:  "name",
+						AName: ""},
+				},
+				Sources: []Source{&Table{Name: "table"}},
+				Condition: &BinaryExpr{
+					LHS: &FieldRef{StreamName: StreamName("table"), Name: "id"},
+					OP:  EQ,
+					RHS: &IntegerLiteral{Val: 1},
+				},
+			},
+		},
 		{
 			s: `SELECT name FROM topic/sensor1`,
 			stmt: &SelectStatement{
@@ -2027,6 +2064,97 @@ func TestParser_ParseJoins(t *testing.T) {
 	}
 }
 
+func TestParser_ParseMatchRecognize(t *testing.T) {
+	var tests = []struct {
+		s    string
+		stmt *SelectStatement
+		err  string
+	}{
+		{
+			s: `SELECT * FROM demo MATCH_RECOGNIZE (
+					PARTITION BY id
+					MEASURES A.temperature AS startTemp, last(B.temperature) AS endTemp
+					PATTERN (A B{2,} C?)
+					WITHIN(ss, 10)
+					DEFINE B AS temperature > prev(temperature), C AS door = "open"
+				) WHERE id = "d1"`,
+			stmt: &SelectStatement{
+				Fields: []Field{
+					{
+						Expr:  &Wildcard{Token: ASTERISK},
+						Name:  "",
+						AName: ""},
+				},
+				Sources: []Source{&Table{Name: "demo"}},
+				MatchRecognize: &MatchRecognize{
+					PartitionBy: []Expr{&FieldRef{Name: "id"}},
+					Measures: Fields{
+						{Expr: &FieldRef{StreamName: StreamName("A"), Name: "temperature"}, Name: "temperature", AName: "startTemp"},
+						{Expr: &Call{Name: "last", Args: []Expr{&FieldRef{StreamName: StreamName("B"), Name: "temperature"}}}, Name: "last", AName: "endTemp"},
+					},
+					Pattern: []*PatternElement{
+						{Variable: "A", Min: 1, Max: 1},
+						{Variable: "B", Min: 2, Max: -1},
+						{Variable: "C", Min: 0, Max: 1},
+					},
+					Within: 10000,
+					Defines: []*PatternDefine{
+						{Variable: "B", Condition: &BinaryExpr{
+							LHS: &FieldRef{Name: "temperature"},
+							OP:  GT,
+							RHS: &Call{Name: "prev", Args: []Expr{&FieldRef{Name: "temperature"}}},
+						}},
+						{Variable: "C", Condition: &BinaryExpr{
+							LHS: &FieldRef{Name: "door"},
+							OP:  EQ,
+							RHS: &StringLiteral{Val: "open"},
+						}},
+					},
+				},
+				Condition: &BinaryExpr{
+					LHS: &FieldRef{Name: "id"},
+					OP:  EQ,
+					RHS: &StringLiteral{Val: "d1"},
+				},
+			},
+		}, {
+			s:    `SELECT * FROM demo MATCH_RECOGNIZE (MEASURES A.temperature PATTERN (A) DEFINE A AS temperature > 20)`,
+			stmt: nil,
+			err:  "alias is required for the measure temperature of MATCH_RECOGNIZE.",
+		}, {
+			s:    `SELECT * FROM demo MATCH_RECOGNIZE (MEASURES A.temperature AS t PATTERN (A* B?) DEFINE A AS temperature > 20)`,
+			stmt: nil,
+			err:  "PATTERN of MATCH_RECOGNIZE must not match empty rows.",
+		}, {
+			s:    `SELECT * FROM demo MATCH_RECOGNIZE (MEASURES A.temperature AS t PATTERN (A{3,2}) DEFINE A AS temperature > 20)`,
+			stmt: nil,
+			err:  "invalid quantifier of pattern variable A.",
+		}, {
+			s:    `SELECT * FROM demo MATCH_RECOGNIZE (MEASURES A.temperature AS t PATTERN (A) DEFINE B AS temperature > 20)`,
+			stmt: nil,
+			err:  "pattern variable B in DEFINE is not used in PATTERN.",
+		}, {
+			s:    `SELECT prev(temperature) FROM demo`,
+			stmt: nil,
+			err:  "Not allowed to call prev function outside MATCH_RECOGNIZE.",
+		}, {
+			s:    `SELECT * FROM demo MATCH_RECOGNIZE (MEASURES A.temperature AS t PATTERN (A) DEFINE A AS temperature > 20) GROUP BY TUMBLINGWINDOW(ss, 10)`,
+			stmt: nil,
+			err:  "Not allowed to use MATCH_RECOGNIZE with window or join.",
+		},
+	}
+
+	fmt.Printf("The test bucket size is %d.\n\n", len(tests))
+	for i, tt := range tests {
+		stmt, err := NewParser(strings.NewReader(tt.s)).Parse()
+		if !reflect.DeepEqual(tt.err, errstring(err)) {
+			t.Errorf("%d. %q: error mismatch:\n  exp=%s\n  got=%s\n\n", i, tt.s, tt.err, err)
+		} else if tt.err == "" && !reflect.DeepEqual(tt.stmt, stmt) {
+			t.Errorf("%d. %q\n\nstmt mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", i, tt.s, tt.stmt, stmt)
+		}
+	}
+}
+
 func TestParser_ParseStatements(t *testing.T) {
 	var tests = []struct {
 		s     string
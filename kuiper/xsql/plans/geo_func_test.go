@@ -0,0 +1,168 @@
+package plans
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+)
+
+func TestGeoFunc_Apply1(t *testing.T) {
+	var tests = []struct {
+		sql    string
+		data   *xsql.Tuple
+		result []map[string]interface{}
+	}{
+		{
+			sql: "SELECT st_distance(lat, lng, 31.2304, 121.4737) AS a FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 39.9042, "lng": 116.4074},
+			},
+			result: []map[string]interface{}{{
+				"a": 1.0673116451587263e+06,
+			}},
+		},
+		{
+			sql: "SELECT st_bearing(0, 0, 1, 0) AS a, st_bearing(0, 0, 0, -1) AS b FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: nil,
+			},
+			result: []map[string]interface{}{{
+				"a": float64(0),
+				"b": float64(270),
+			}},
+		},
+		{
+			sql: "SELECT st_within_circle(lat, lng, 39.91, 116.41, 1000) AS a, st_within_circle(lat, lng, 31.2304, 121.4737, 1000) AS b FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 39.9042, "lng": 116.4074},
+			},
+			result: []map[string]interface{}{{
+				"a": true,
+				"b": false,
+			}},
+		},
+		{
+			sql: `SELECT st_within_polygon(lat, lng, "POLYGON((0 0, 0 1, 1 1, 1 0, 0 0), (0.4 0.4, 0.4 0.6, 0.6 0.6, 0.6 0.4, 0.4 0.4))") AS a FROM test`,
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 0.2, "lng": 0.5},
+			},
+			result: []map[string]interface{}{{
+				"a": true,
+			}},
+		},
+		{
+			sql: `SELECT st_within_polygon(lat, lng, "POLYGON((0 0, 0 1, 1 1, 1 0, 0 0), (0.4 0.4, 0.4 0.6, 0.6 0.6, 0.6 0.4, 0.4 0.4))") AS a FROM test`,
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 0.5, "lng": 0.5},
+			},
+			result: []map[string]interface{}{{
+				"a": false,
+			}},
+		},
+		{
+			sql: `SELECT st_within_polygon(lat, lng, "MULTIPOLYGON(((0 0, 0 1, 1 1, 1 0, 0 0)), ((5 5, 5 6, 6 6, 6 5, 5 5)))") AS a FROM test`,
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 5.5, "lng": 5.5},
+			},
+			result: []map[string]interface{}{{
+				"a": true,
+			}},
+		},
+		{
+			sql: "SELECT st_within_polygon(lat, lng, area) AS a FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{
+					"lat":  1.5,
+					"lng":  0.5,
+					"area": `{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[1,0],[1,1],[0,1],[0,0]]]}}`,
+				},
+			},
+			result: []map[string]interface{}{{
+				"a": false,
+			}},
+		},
+		{
+			sql: "SELECT geohash_encode(lat, lng, 11) AS a, geohash_encode(lat, lng) AS b FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 57.64911, "lng": 10.40744},
+			},
+			result: []map[string]interface{}{{
+				"a": "u4pruydqqvj",
+				"b": "u4pruydqqvj8",
+			}},
+		},
+		{
+			sql: `SELECT geohash_decode("u4pruydqqvj") AS a FROM test`,
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: nil,
+			},
+			result: []map[string]interface{}{{
+				"a": map[string]interface{}{"lat": 57.64911063015461, "lng": 10.407439693808556},
+			}},
+		},
+		{
+			sql: "SELECT wgs84_to_gcj02(lat, lng) AS a, gcj02_to_bd09(lat, lng) AS b FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 39.9042, "lng": 116.4074},
+			},
+			result: []map[string]interface{}{{
+				"a": map[string]interface{}{"lat": 39.90560334316507, "lng": 116.41364225378803},
+				"b": map[string]interface{}{"lat": 39.91052191963314, "lng": 116.41378503407047},
+			}},
+		},
+		{
+			sql: "SELECT wgs84_to_gcj02(lat, lng) AS a FROM test",
+			data: &xsql.Tuple{
+				Emitter: "test",
+				Message: xsql.Message{"lat": 48.8566, "lng": 2.3522},
+			},
+			result: []map[string]interface{}{{
+				"a": map[string]interface{}{"lat": 48.8566, "lng": 2.3522},
+			}},
+		},
+	}
+
+	fmt.Printf("The test bucket size is %d.\n\n", len(tests))
+	contextLogger := util.Log.WithField("rule", "TestGeoFunc_Apply1")
+	ctx := contexts.WithValue(contexts.Background(), contexts.LoggerKey, contextLogger)
+	for i, tt := range tests {
+		stmt, err := xsql.NewParser(strings.NewReader(tt.sql)).Parse()
+		if err != nil {
+			t.Errorf("%d. %q: %s", i, tt.sql, err)
+			continue
+		}
+		pp := &ProjectPlan{Fields: stmt.Fields}
+		pp.isTest = true
+		fv, afv := xsql.NewFunctionValuersForOp(nil)
+		result := pp.Apply(ctx, tt.data, fv, afv)
+		var mapRes []map[string]interface{}
+		if v, ok := result.([]byte); ok {
+			err := json.Unmarshal(v, &mapRes)
+			if err != nil {
+				t.Errorf("Failed to parse the input into map.\n")
+				continue
+			}
+			if !reflect.DeepEqual(tt.result, mapRes) {
+				t.Errorf("%d. %q\n\nresult mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", i, tt.sql, tt.result, mapRes)
+			}
+		} else {
+			t.Errorf("The returned result is not type of []byte\n")
+		}
+	}
+}
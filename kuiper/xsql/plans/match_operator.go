@@ -0,0 +1,393 @@
+package plans
+
+import (
+	"encoding/gob"
+	"fmt"
+	"strings"
+
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+const MATCH_RUNS_KEY = "$$matchRuns"
+
+// The navigation functions are rewritten to field references whose stream
+// name is navPrefix + function + navSeparator + pattern variable.
+const (
+	navPrefix    = "$$"
+	navSeparator = "$"
+)
+
+func init() {
+	gob.Register(map[string][]*MatchRun{})
+}
+
+// MatchVar keeps the first and the last row mapped to a pattern variable.
+type MatchVar struct {
+	First *xsql.Tuple
+	Last  *xsql.Tuple
+}
+
+// MatchRun is a partial match, it is a state of the NFA compiled from the
+// pattern. Element is the index of the current pattern element, -1 if the
+// run is not started. Count is the rows mapped to the current element.
+type MatchRun struct {
+	Element  int
+	Count    int
+	Start    int64
+	First    *xsql.Tuple
+	Previous *xsql.Tuple
+	Last     *xsql.Tuple
+	Vars     map[string]*MatchVar
+}
+
+func (r *MatchRun) next(element int, count int, tuple *xsql.Tuple, variable string) *MatchRun {
+	nr := &MatchRun{
+		Element:  element,
+		Count:    count,
+		Start:    r.Start,
+		First:    r.First,
+		Previous: r.Last,
+		Last:     tuple,
+		Vars:     make(map[string]*MatchVar, len(r.Vars)+1),
+	}
+	if r.Element < 0 {
+		nr.Start = tuple.Timestamp
+		nr.First = tuple
+	}
+	for k, v := range r.Vars {
+		nr.Vars[k] = v
+	}
+	if v, ok := nr.Vars[variable]; ok {
+		nr.Vars[variable] = &MatchVar{First: v.First, Last: tuple}
+	} else {
+		nr.Vars[variable] = &MatchVar{First: tuple, Last: tuple}
+	}
+	return nr
+}
+
+// MatchPlan recognizes the row pattern of each partition. It keeps the
+// partial matches in the operator state, so that they are saved in the
+// checkpoints. A row made of the partition fields and the measures is emitted
+// as soon as the pattern is matched, then the partial matches of the
+// partition are discarded to continue after the last row of the match.
+type MatchPlan struct {
+	partitionBy []*xsql.FieldRef
+	measures    xsql.Fields
+	pattern     []*xsql.PatternElement
+	variables   map[string]bool
+	within      int64
+	defines     map[string]xsql.Expr
+
+	partitions map[string][]*MatchRun
+}
+
+func NewMatchPlan(mr *xsql.MatchRecognize) (*MatchPlan, error) {
+	p := &MatchPlan{
+		pattern:   mr.Pattern,
+		variables: make(map[string]bool, len(mr.Pattern)),
+		within:    int64(mr.Within),
+		defines:   make(map[string]xsql.Expr, len(mr.Defines)),
+	}
+	for _, e := range mr.Pattern {
+		p.variables[e.Variable] = true
+	}
+	for _, e := range mr.PartitionBy {
+		f, ok := e.(*xsql.FieldRef)
+		if !ok {
+			return nil, fmt.Errorf("expect field name in PARTITION BY")
+		}
+		p.partitionBy = append(p.partitionBy, f)
+	}
+	for _, f := range mr.Measures {
+		p.measures = append(p.measures, xsql.Field{Name: f.Name, AName: f.AName, Expr: rewriteNavigation(f.Expr)})
+	}
+	for _, d := range mr.Defines {
+		p.defines[d.Variable] = rewriteNavigation(d.Condition)
+	}
+	return p, nil
+}
+
+// rewriteNavigation replaces the prev, first and last calls with the field
+// references resolved by matchValuer.
+func rewriteNavigation(expr xsql.Expr) xsql.Expr {
+	switch e := expr.(type) {
+	case *xsql.BinaryExpr:
+		return &xsql.BinaryExpr{OP: e.OP, LHS: rewriteNavigation(e.LHS), RHS: rewriteNavigation(e.RHS)}
+	case *xsql.ParenExpr:
+		return &xsql.ParenExpr{Expr: rewriteNavigation(e.Expr)}
+	case *xsql.Call:
+		name := strings.ToLower(e.Name)
+		if name == "prev" || name == "first" || name == "last" {
+			if f, ok := e.Args[0].(*xsql.FieldRef); ok {
+				return &xsql.FieldRef{StreamName: xsql.StreamName(navPrefix + name + navSeparator + string(f.StreamName)), Name: f.Name}
+			}
+		}
+		args := make([]xsql.Expr, len(e.Args))
+		for i, a := range e.Args {
+			args[i] = rewriteNavigation(a)
+		}
+		return &xsql.Call{Name: e.Name, Args: args}
+	default:
+		return expr
+	}
+}
+
+/**
+ *  input: *xsql.Tuple from preprocessor
+ *  output: *xsql.Tuple
+ */
+func (p *MatchPlan) Apply(ctx api.StreamContext, data interface{}, fv *xsql.FunctionValuer, _ *xsql.AggregateFunctionValuer) interface{} {
+	log := ctx.GetLogger()
+	log.Debugf("match plan receive %s", data)
+	var input *xsql.Tuple
+	switch d := data.(type) {
+	case error:
+		return d
+	case *xsql.Tuple:
+		input = d
+	default:
+		return fmt.Errorf("run Match error: invalid input %[1]T(%[1]v)", data)
+	}
+	if p.partitions == nil {
+		p.restore(ctx)
+	}
+
+	key := p.partitionKey(input)
+	runs := append(p.partitions[key], &MatchRun{Element: -1})
+	var (
+		next []*MatchRun
+		seen = make(map[[3]int64]bool)
+	)
+	for _, r := range runs {
+		if p.within > 0 && r.Element >= 0 && input.Timestamp-r.Start > p.within {
+			continue
+		}
+		nrs, err := p.transit(r, input, fv)
+		if err != nil {
+			return fmt.Errorf("run Match error: %s", err)
+		}
+		for _, nr := range nrs {
+			if p.accepted(nr) {
+				delete(p.partitions, key)
+				ctx.PutState(MATCH_RUNS_KEY, p.partitions)
+				return p.measure(nr, fv)
+			}
+			s := p.stateKey(nr)
+			if !seen[s] {
+				seen[s] = true
+				next = append(next, nr)
+			}
+		}
+	}
+	if len(next) > 0 {
+		p.partitions[key] = next
+	} else {
+		delete(p.partitions, key)
+	}
+	ctx.PutState(MATCH_RUNS_KEY, p.partitions)
+	return nil
+}
+
+func (p *MatchPlan) restore(ctx api.StreamContext) {
+	p.partitions = make(map[string][]*MatchRun)
+	if s, err := ctx.GetState(MATCH_RUNS_KEY); err == nil && s != nil {
+		if st, ok := s.(map[string][]*MatchRun); ok {
+			p.partitions = st
+			ctx.GetLogger().Infof("Restore match state %+v", st)
+		} else {
+			ctx.GetLogger().Warnf("Restore match state %v error, invalid type", s)
+		}
+	}
+}
+
+func (p *MatchPlan) partitionKey(tuple *xsql.Tuple) string {
+	if len(p.partitionBy) == 0 {
+		return ""
+	}
+	values := make([]interface{}, len(p.partitionBy))
+	for i, f := range p.partitionBy {
+		values[i], _ = tuple.Value(f.Name)
+	}
+	return fmt.Sprintf("%v", values)
+}
+
+// transit returns the runs after mapping the tuple to the current element or
+// to one of the following elements that can be reached.
+func (p *MatchPlan) transit(r *MatchRun, tuple *xsql.Tuple, fv *xsql.FunctionValuer) ([]*MatchRun, error) {
+	var result []*MatchRun
+	if r.Element >= 0 {
+		e := p.pattern[r.Element]
+		if e.Max < 0 || r.Count < e.Max {
+			if ok, err := p.match(e.Variable, r, tuple, fv); err != nil {
+				return nil, err
+			} else if ok {
+				result = append(result, r.next(r.Element, r.Count+1, tuple, e.Variable))
+			}
+		}
+		if r.Count < e.Min {
+			return result, nil
+		}
+	}
+	for i := r.Element + 1; i < len(p.pattern); i++ {
+		e := p.pattern[i]
+		if ok, err := p.match(e.Variable, r, tuple, fv); err != nil {
+			return nil, err
+		} else if ok {
+			result = append(result, r.next(i, 1, tuple, e.Variable))
+		}
+		if e.Min > 0 {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (p *MatchPlan) match(variable string, r *MatchRun, tuple *xsql.Tuple, fv *xsql.FunctionValuer) (bool, error) {
+	cond, ok := p.defines[variable]
+	if !ok {
+		return true, nil
+	}
+	ve := &xsql.ValuerEval{Valuer: xsql.MultiValuer(&matchValuer{run: r, current: tuple, variable: variable, variables: p.variables}, fv)}
+	switch val := ve.Eval(cond).(type) {
+	case error:
+		return false, val
+	case bool:
+		return val, nil
+	case nil:
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid condition of %s that returns non-bool value %[2]T(%[2]v)", variable, val)
+	}
+}
+
+func (p *MatchPlan) accepted(r *MatchRun) bool {
+	if r.Count < p.pattern[r.Element].Min {
+		return false
+	}
+	for _, e := range p.pattern[r.Element+1:] {
+		if e.Min > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// stateKey identifies the NFA state of the run, the runs having the same
+// state behave the same, so only the earliest one is kept. With WITHIN, the
+// runs started at different time expire differently, so they are all kept.
+func (p *MatchPlan) stateKey(r *MatchRun) [3]int64 {
+	e := p.pattern[r.Element]
+	c := r.Count
+	if e.Max < 0 && c > e.Min {
+		c = e.Min
+	}
+	var start int64
+	if p.within > 0 {
+		start = r.Start
+	}
+	return [3]int64{int64(r.Element), int64(c), start}
+}
+
+func (p *MatchPlan) measure(r *MatchRun, fv *xsql.FunctionValuer) interface{} {
+	result := &xsql.Tuple{
+		Emitter:   r.Last.Emitter,
+		Message:   make(xsql.Message),
+		Timestamp: r.Last.Timestamp,
+		Metadata:  r.Last.Metadata,
+	}
+	for _, f := range p.partitionBy {
+		result.Message[f.Name], _ = r.Last.Value(f.Name)
+	}
+	ve := &xsql.ValuerEval{Valuer: xsql.MultiValuer(&matchValuer{run: r, current: r.Last, variables: p.variables}, fv)}
+	for _, f := range p.measures {
+		v := ve.Eval(f.Expr)
+		if e, ok := v.(error); ok {
+			return fmt.Errorf("run Match error: measure %s: %s", f.AName, e)
+		}
+		result.Message[f.AName] = v
+	}
+	return result
+}
+
+// matchValuer resolves the field references of DEFINE and MEASURES.
+//   - field or the variable being defined: the current row
+//   - var.field: the last row mapped to the pattern variable
+//   - prev(field): the row before the current row in the match
+//   - first(var.field), last(var.field): the first or last row mapped to the
+//     pattern variable, or of the match if the variable is not specified
+type matchValuer struct {
+	run       *MatchRun
+	current   *xsql.Tuple
+	variable  string
+	variables map[string]bool
+}
+
+func (v *matchValuer) Value(key string) (interface{}, bool) {
+	t, field := v.resolve(key)
+	if t == nil {
+		return nil, true
+	}
+	return t.Value(field)
+}
+
+func (v *matchValuer) Meta(key string) (interface{}, bool) {
+	t, field := v.resolve(key)
+	if t == nil {
+		return nil, true
+	}
+	return t.Meta(field)
+}
+
+func (v *matchValuer) resolve(key string) (*xsql.Tuple, string) {
+	keys := strings.SplitN(key, xsql.COLUMN_SEPARATOR, 2)
+	if len(keys) == 1 {
+		return v.current, key
+	}
+	qualifier, field := keys[0], keys[1]
+	if !strings.HasPrefix(qualifier, navPrefix) {
+		return v.row(qualifier, false), field
+	}
+	nav := strings.SplitN(strings.TrimPrefix(qualifier, navPrefix), navSeparator, 2)
+	variable := nav[1]
+	switch nav[0] {
+	case "prev":
+		if v.current == v.run.Last {
+			return v.run.Previous, field
+		}
+		return v.run.Last, field
+	case "first":
+		if variable == "" {
+			if v.run.First == nil {
+				return v.current, field
+			}
+			return v.run.First, field
+		}
+		return v.row(variable, true), field
+	default:
+		if variable == "" {
+			return v.current, field
+		}
+		return v.row(variable, false), field
+	}
+}
+
+// row returns the row of the pattern variable, the current row is the
+// latest row of the variable being defined.
+func (v *matchValuer) row(variable string, first bool) *xsql.Tuple {
+	if !v.variables[variable] {
+		// Not a pattern variable, such as the stream name
+		return v.current
+	}
+	mv, ok := v.run.Vars[variable]
+	if variable == v.variable && (!first || !ok) {
+		return v.current
+	}
+	if !ok {
+		return nil
+	}
+	if first {
+		return mv.First
+	}
+	return mv.Last
+}
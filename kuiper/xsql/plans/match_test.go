@@ -0,0 +1,145 @@
+package plans
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+	"github.com/cloustone/pandas/kuiper/xstream/states"
+)
+
+func TestMatchPlan_Apply(t *testing.T) {
+	tuple := func(ts int64, m map[string]interface{}) *xsql.Tuple {
+		return &xsql.Tuple{Emitter: "demo", Message: m, Timestamp: ts}
+	}
+	var tests = []struct {
+		sql    string
+		data   []*xsql.Tuple
+		result []map[string]interface{}
+	}{
+		{
+			sql: `SELECT * FROM demo MATCH_RECOGNIZE (
+					PARTITION BY id
+					MEASURES A.temperature AS startTemp, last(B.temperature) AS endTemp, C.door AS door
+					PATTERN (A B{2} C)
+					DEFINE B AS temperature > prev(temperature), C AS door = "open"
+				)`,
+			data: []*xsql.Tuple{
+				tuple(1000, map[string]interface{}{"id": "d1", "temperature": 20, "door": "closed"}),
+				tuple(2000, map[string]interface{}{"id": "d2", "temperature": 30, "door": "closed"}),
+				tuple(3000, map[string]interface{}{"id": "d1", "temperature": 21, "door": "closed"}),
+				tuple(4000, map[string]interface{}{"id": "d1", "temperature": 22, "door": "closed"}),
+				tuple(5000, map[string]interface{}{"id": "d2", "temperature": 29, "door": "open"}),
+				tuple(6000, map[string]interface{}{"id": "d1", "temperature": 22, "door": "open"}),
+				tuple(7000, map[string]interface{}{"id": "d1", "temperature": 22, "door": "open"}),
+			},
+			result: []map[string]interface{}{
+				nil, nil, nil, nil, nil,
+				{"id": "d1", "startTemp": 20, "endTemp": 22, "door": "open"},
+				nil,
+			},
+		}, {
+			sql: `SELECT * FROM demo MATCH_RECOGNIZE (
+					MEASURES first(B.temperature) AS first, last(B.temperature) AS last, C.temperature AS c
+					PATTERN (B+ C)
+					WITHIN(ss, 3)
+					DEFINE B AS temperature > 30, C AS temperature <= 30
+				)`,
+			data: []*xsql.Tuple{
+				tuple(1000, map[string]interface{}{"temperature": 31}),
+				tuple(2000, map[string]interface{}{"temperature": 32}),
+				tuple(3000, map[string]interface{}{"temperature": 33}),
+				tuple(5000, map[string]interface{}{"temperature": 25}),
+				tuple(6000, map[string]interface{}{"temperature": 35}),
+				tuple(7000, map[string]interface{}{"temperature": 20}),
+			},
+			result: []map[string]interface{}{
+				nil, nil, nil,
+				{"first": 32, "last": 33, "c": 25},
+				nil,
+				{"first": 35, "last": 35, "c": 20},
+			},
+		}, {
+			sql: `SELECT * FROM demo MATCH_RECOGNIZE (
+					MEASURES A.status AS a, B.status AS b, C.status AS c
+					PATTERN (A B? C)
+					DEFINE A AS status = "start", B AS status = "pause", C AS status = "stop"
+				)`,
+			data: []*xsql.Tuple{
+				tuple(1000, map[string]interface{}{"status": "start"}),
+				tuple(2000, map[string]interface{}{"status": "stop"}),
+				tuple(3000, map[string]interface{}{"status": "start"}),
+				tuple(4000, map[string]interface{}{"status": "pause"}),
+				tuple(5000, map[string]interface{}{"status": "pause"}),
+				tuple(6000, map[string]interface{}{"status": "stop"}),
+			},
+			result: []map[string]interface{}{
+				nil,
+				{"a": "start", "b": nil, "c": "stop"},
+				nil, nil, nil, nil,
+			},
+		}, {
+			sql: `SELECT * FROM demo MATCH_RECOGNIZE (
+					MEASURES A.temperature AS a
+					PATTERN (A)
+					DEFINE A AS temperature > prev(temperature)
+				)`,
+			data: []*xsql.Tuple{
+				tuple(1000, map[string]interface{}{"temperature": 20}),
+				tuple(2000, map[string]interface{}{"temperature": 21}),
+			},
+			result: []map[string]interface{}{
+				nil, nil,
+			},
+		},
+	}
+
+	fmt.Printf("The test bucket size is %d.\n\n", len(tests))
+	contextLogger := util.Log.WithField("rule", "TestMatchPlan_Apply")
+	store, err := states.CreateStore("TestMatchPlan_Apply", api.AtMostOnce)
+	if err != nil {
+		t.Fatalf("create store error: %s", err)
+	}
+	for i, tt := range tests {
+		ctx := contexts.WithValue(contexts.Background(), contexts.LoggerKey, contextLogger).WithMeta("TestMatchPlan_Apply", fmt.Sprintf("op%d", i), store)
+		stmt, err := xsql.NewParser(strings.NewReader(tt.sql)).Parse()
+		if err != nil {
+			t.Errorf("%d. %q: %s", i, tt.sql, err)
+			continue
+		}
+		pp, err := NewMatchPlan(stmt.MatchRecognize)
+		if err != nil {
+			t.Errorf("%d. %q: %s", i, tt.sql, err)
+			continue
+		}
+		fv, afv := xsql.NewFunctionValuersForOp(nil)
+		for j, d := range tt.data {
+			result := pp.Apply(ctx, d, fv, afv)
+			var msg map[string]interface{}
+			switch r := result.(type) {
+			case nil:
+			case *xsql.Tuple:
+				msg = r.Message
+			default:
+				t.Errorf("%d.%d unexpected result %v", i, j, r)
+				continue
+			}
+			if !reflect.DeepEqual(tt.result[j], msg) {
+				t.Errorf("%d.%d result mismatch:\n  exp=%v\n  got=%v", i, j, tt.result[j], msg)
+			}
+		}
+		// The partial matches must be able to save into checkpoint
+		s, _ := ctx.GetState(MATCH_RUNS_KEY)
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(map[string]interface{}{MATCH_RUNS_KEY: s}); err != nil {
+			t.Errorf("%d. encode state error: %s", i, err)
+		}
+	}
+}
@@ -3,7 +3,7 @@ package processors
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xsql"
 	"github.com/cloustone/pandas/kuiper/xstream"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
@@ -891,6 +891,35 @@ var testData = map[string][]*xsql.Tuple{
 			Timestamp: 1541152493400,
 		},
 	},
+	"colors": {
+		{
+			Emitter: "colors",
+			Message: map[string]interface{}{
+				"color": "red",
+				"hex":   "#f00",
+				"ts":    1541152485900,
+			},
+			Timestamp: 1541152485900,
+		},
+		{
+			Emitter: "colors",
+			Message: map[string]interface{}{
+				"color": "blue",
+				"hex":   "#00f",
+				"ts":    1541152486000,
+			},
+			Timestamp: 1541152486000,
+		},
+		{
+			Emitter: "colors",
+			Message: map[string]interface{}{
+				"color": "red",
+				"hex":   "#ff0000",
+				"ts":    1541152487000,
+			},
+			Timestamp: 1541152487000,
+		},
+	},
 }
 
 func commonResultFunc(result [][]byte) interface{} {
@@ -1094,9 +1123,17 @@ func handleStream(createOrDrop bool, names []string, t *testing.T) {
 				sql = "CREATE STREAM ext2 (count bigint) WITH (DATASOURCE=\"users\", FORMAT=\"JSON\", TYPE=\"random\", CONF_KEY=\"dedup\")"
 			case "text":
 				sql = "CREATE STREAM text (slogan string, brand string) WITH (DATASOURCE=\"users\", FORMAT=\"JSON\")"
+			case "colors":
+				sql = `CREATE TABLE colors (
+					color STRING,
+					hex STRING,
+					ts BIGINT
+				) WITH (DATASOURCE="colors", FORMAT="json", KEY="color");`
 			default:
 				t.Errorf("create stream %s fail", name)
 			}
+		} else if name == "colors" {
+			sql = `DROP TABLE ` + name
 		} else {
 			sql = `DROP STREAM ` + name
 		}
@@ -6,7 +6,7 @@ import (
 	"bufio"
 	"encoding/json"
 	"fmt"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
 	"github.com/cloustone/pandas/kuiper/xstream/test"
 	"os"
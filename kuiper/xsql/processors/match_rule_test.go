@@ -0,0 +1,75 @@
+package processors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+func TestMatchRecognize(t *testing.T) {
+	//Reset
+	streamList := []string{"demo"}
+	handleStream(false, streamList, t)
+	//Data setup
+	var tests = []ruleTest{
+		{
+			name: `TestMatchRecognizeRule1`,
+			sql: `SELECT redSize, blueSize FROM demo MATCH_RECOGNIZE (
+				MEASURES A.size AS redSize, B.size AS blueSize
+				PATTERN (A B)
+				DEFINE A AS color = "red", B AS color = "blue"
+			)`,
+			r: [][]map[string]interface{}{
+				{{
+					"redSize":  float64(3),
+					"blueSize": float64(6),
+				}},
+			},
+			m: map[string]interface{}{
+				"op_preprocessor_demo_0_exceptions_total":  int64(0),
+				"op_preprocessor_demo_0_records_in_total":  int64(5),
+				"op_preprocessor_demo_0_records_out_total": int64(5),
+
+				"op_match_0_exceptions_total":  int64(0),
+				"op_match_0_records_in_total":  int64(5),
+				"op_match_0_records_out_total": int64(1),
+
+				"sink_mockSink_0_exceptions_total":  int64(0),
+				"sink_mockSink_0_records_in_total":  int64(1),
+				"sink_mockSink_0_records_out_total": int64(1),
+
+				"source_demo_0_exceptions_total":  int64(0),
+				"source_demo_0_records_in_total":  int64(5),
+				"source_demo_0_records_out_total": int64(5),
+			},
+		},
+	}
+	handleStream(true, streamList, t)
+	options := []*api.RuleOption{
+		{
+			BufferLength: 100,
+			Concurrency:  1,
+		}, {
+			BufferLength: 100,
+			Concurrency:  4,
+		},
+	}
+	for j, opt := range options {
+		for i, tt := range tests {
+			datas, dataLength, tp, mockSink, errCh := createStream(t, tt, j, opt, nil)
+			if err := sendData(t, dataLength, tt.m, datas, errCh, tp, POSTLEAP); err != nil {
+				t.Errorf("send data error %s", err)
+				break
+			}
+			// The rows are read by a single preprocessor whatever the concurrency
+			keys, _ := tp.GetMetrics()
+			for _, k := range keys {
+				if strings.HasPrefix(k, "op_preprocessor_demo_") && !strings.HasPrefix(k, "op_preprocessor_demo_0_") {
+					t.Errorf("%d-%d. %q\n\nunexpected preprocessor instance %s", j, i, tt.sql, k)
+				}
+			}
+			compareResult(t, mockSink, commonResultFunc, tt, i, tp)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package processors
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/test"
+)
+
+func TestTableJoin(t *testing.T) {
+	//Reset
+	streamList := []string{"demo", "colors"}
+	handleStream(false, streamList, t)
+	//Data setup
+	var tests = []ruleTest{
+		{
+			name: `TestTableJoinRule1`,
+			sql:  `SELECT demo.color, size, hex FROM demo INNER JOIN colors ON demo.color = colors.color`,
+			r: [][]map[string]interface{}{
+				{{
+					"color": "red",
+					"size":  float64(3),
+					"hex":   "#f00",
+				}},
+				{{
+					"color": "blue",
+					"size":  float64(6),
+					"hex":   "#00f",
+				}},
+				{{
+					"color": "blue",
+					"size":  float64(2),
+					"hex":   "#00f",
+				}},
+				{{
+					"color": "red",
+					"size":  float64(1),
+					"hex":   "#ff0000",
+				}},
+			},
+			m: map[string]interface{}{
+				"op_preprocessor_demo_0_records_in_total":    int64(5),
+				"op_preprocessor_demo_0_records_out_total":   int64(5),
+				"op_preprocessor_colors_0_records_in_total":  int64(3),
+				"op_preprocessor_colors_0_records_out_total": int64(3),
+
+				"op_join_aligner_0_exceptions_total":  int64(0),
+				"op_join_aligner_0_records_in_total":  int64(8),
+				"op_join_aligner_0_records_out_total": int64(5),
+
+				"op_join_0_exceptions_total":  int64(0),
+				"op_join_0_records_in_total":  int64(5),
+				"op_join_0_records_out_total": int64(4),
+
+				"sink_mockSink_0_exceptions_total":  int64(0),
+				"sink_mockSink_0_records_in_total":  int64(4),
+				"sink_mockSink_0_records_out_total": int64(4),
+
+				"source_demo_0_exceptions_total":  int64(0),
+				"source_demo_0_records_in_total":  int64(5),
+				"source_demo_0_records_out_total": int64(5),
+
+				"source_colors_0_exceptions_total":  int64(0),
+				"source_colors_0_records_in_total":  int64(3),
+				"source_colors_0_records_out_total": int64(3),
+			},
+			t: &xstream.PrintableTopo{
+				Sources: []string{"source_demo", "source_colors"},
+				Edges: map[string][]string{
+					"source_demo":            {"op_preprocessor_demo"},
+					"source_colors":          {"op_preprocessor_colors"},
+					"op_preprocessor_demo":   {"op_join_aligner"},
+					"op_preprocessor_colors": {"op_join_aligner"},
+					"op_join_aligner":        {"op_join"},
+					"op_join":                {"op_project"},
+					"op_project":             {"sink_mockSink"},
+				},
+			},
+		},
+	}
+	handleStream(true, streamList, t)
+	defer handleStream(false, streamList, t)
+	options := []*api.RuleOption{
+		{
+			BufferLength: 100,
+		}, {
+			BufferLength:       100,
+			Qos:                api.AtLeastOnce,
+			CheckpointInterval: 5000,
+		},
+	}
+	for j, opt := range options {
+		fmt.Printf("The test bucket for option %d size is %d.\n\n", j, len(tests))
+		for i, tt := range tests {
+			datas, _, tp, mockSink, errCh := createStream(t, tt, j, opt, nil)
+			if err := sendTableData(t, tt.m, datas, errCh, tp); err != nil {
+				t.Errorf("send data error %s", err)
+				break
+			}
+			compareResult(t, mockSink, commonResultFunc, tt, i, tp)
+		}
+	}
+}
+
+// sendTableData sends the tuples of all the sources in the order of their
+// timestamps. The table rows and the stream tuples arrive at the join aligner
+// through different goroutines, so it waits for each tuple to reach the
+// aligner before moving the clock to the next one. The mock sources only see
+// the clock change after they start waiting for their next tuple, so it also
+// gives them a moment before each move.
+func sendTableData(t *testing.T, metrics map[string]interface{}, datas [][]*xsql.Tuple, errCh <-chan error, tp *xstream.TopologyNew) error {
+	var all []*xsql.Tuple
+	for _, d := range datas {
+		all = append(all, d...)
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Timestamp < all[j].Timestamp
+	})
+	mockClock := test.GetMockClock()
+	for i, d := range all {
+		time.Sleep(50 * time.Millisecond)
+		mockClock.Set(util.TimeFromUnixMilli(d.Timestamp))
+		util.Log.Debugf("Clock set to %d", util.GetNowInMilli())
+		expected := map[string]interface{}{
+			"op_join_aligner_0_records_in_total": int64(i + 1),
+		}
+		for retry := 100; ; retry-- {
+			select {
+			case err := <-errCh:
+				t.Log(err)
+				tp.Cancel()
+				return err
+			default:
+			}
+			err := compareMetrics(tp, expected)
+			if err == nil {
+				break
+			}
+			if retry == 0 {
+				return fmt.Errorf("tuple %d does not reach the join aligner: %s", i, err)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	// Check if stream done. Poll for metrics,
+	for retry := 100; retry > 0; retry-- {
+		time.Sleep(time.Duration(retry) * time.Millisecond)
+		if err := compareMetrics(tp, metrics); err == nil {
+			break
+		} else {
+			util.Log.Debugf("check metrics error at %d: %s", retry, err)
+		}
+	}
+	return nil
+}
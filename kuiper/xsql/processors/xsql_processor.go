@@ -66,14 +66,14 @@ func (p *StreamProcessor) ExecStmt(statement string) (result []string, err error
 func (p *StreamProcessor) execCreateStream(stmt *xsql.StreamStmt, statement string) (string, error) {
 	err := p.db.Open()
 	if err != nil {
-		return "", fmt.Errorf("Create stream fails, error when opening db: %v.", err)
+		return "", fmt.Errorf("Create %s fails, error when opening db: %v.", xsql.StreamTypeMap[stmt.StreamType], err)
 	}
 	defer p.db.Close()
 	err = p.db.Set(string(stmt.Name), statement)
 	if err != nil {
-		return "", fmt.Errorf("Create stream fails: %v.", err)
+		return "", fmt.Errorf("Create %s fails: %v.", xsql.StreamTypeMap[stmt.StreamType], err)
 	} else {
-		info := fmt.Sprintf("Stream %s is created.", stmt.Name)
+		info := fmt.Sprintf("%s %s is created.", strings.Title(xsql.StreamTypeMap[stmt.StreamType]), stmt.Name)
 		log.Printf("%s", info)
 		return info, nil
 	}
@@ -88,10 +88,10 @@ func (p *StreamProcessor) ExecStreamSql(statement string) (string, error) {
 	}
 }
 
-func (p *StreamProcessor) execShowStream(_ *xsql.ShowStreamsStatement) ([]string, error) {
-	keys, err := p.ShowStream()
+func (p *StreamProcessor) execShowStream(stmt *xsql.ShowStreamsStatement) ([]string, error) {
+	keys, err := p.ShowStreamsByType(stmt.StreamType)
 	if len(keys) == 0 {
-		keys = append(keys, "No stream definitions are found.")
+		keys = append(keys, fmt.Sprintf("No %s definitions are found.", xsql.StreamTypeMap[stmt.StreamType]))
 	}
 	return keys, err
 }
@@ -105,11 +105,34 @@ func (p *StreamProcessor) ShowStream() ([]string, error) {
 	return p.db.Keys()
 }
 
+// ShowStreamsByType lists the names of the streams or the tables.
+func (p *StreamProcessor) ShowStreamsByType(st xsql.StreamType) ([]string, error) {
+	err := p.db.Open()
+	if err != nil {
+		return nil, fmt.Errorf("Show %s fails, error when opening db: %v.", xsql.StreamTypeMap[st], err)
+	}
+	defer p.db.Close()
+	keys, err := p.db.Keys()
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, k := range keys {
+		if stmt, err := GetStream(p.db, k); err == nil && stmt.StreamType == st {
+			result = append(result, k)
+		}
+	}
+	return result, nil
+}
+
 func (p *StreamProcessor) execDescribeStream(stmt *xsql.DescribeStreamStatement) (string, error) {
 	streamStmt, err := p.DescStream(stmt.Name)
 	if err != nil {
 		return "", err
 	}
+	if streamStmt.StreamType != stmt.StreamType {
+		return "", util.NewErrorWithCode(util.NOT_FOUND, fmt.Sprintf("%s %s is not found.", strings.Title(xsql.StreamTypeMap[stmt.StreamType]), stmt.Name))
+	}
 	var buff bytes.Buffer
 	buff.WriteString("Fields\n--------------------------------------------------------------------------------\n")
 	for _, f := range streamStmt.StreamFields {
@@ -149,20 +172,25 @@ func (p *StreamProcessor) DescStream(name string) (*xsql.StreamStmt, error) {
 func (p *StreamProcessor) execExplainStream(stmt *xsql.ExplainStreamStatement) (string, error) {
 	err := p.db.Open()
 	if err != nil {
-		return "", fmt.Errorf("Explain stream fails, error when opening db: %v.", err)
+		return "", fmt.Errorf("Explain %s fails, error when opening db: %v.", xsql.StreamTypeMap[stmt.StreamType], err)
 	}
 	defer p.db.Close()
-	_, f := p.db.Get(stmt.Name)
-	if !f {
-		return "", fmt.Errorf("Stream %s is not found.", stmt.Name)
+	if s, err := GetStream(p.db, stmt.Name); err != nil || s.StreamType != stmt.StreamType {
+		return "", fmt.Errorf("%s %s is not found.", strings.Title(xsql.StreamTypeMap[stmt.StreamType]), stmt.Name)
 	}
 	return "TO BE SUPPORTED", nil
 }
 
 func (p *StreamProcessor) execDropStream(stmt *xsql.DropStreamStatement) (string, error) {
+	if s, err := p.DescStream(stmt.Name); err == nil && s.StreamType != stmt.StreamType {
+		return "", fmt.Errorf("Drop %s fails: %s %s is not found.", xsql.StreamTypeMap[stmt.StreamType], strings.Title(xsql.StreamTypeMap[stmt.StreamType]), stmt.Name)
+	}
 	s, err := p.DropStream(stmt.Name)
 	if err != nil {
-		return s, fmt.Errorf("Drop stream fails: %s.", err)
+		return s, fmt.Errorf("Drop %s fails: %s.", xsql.StreamTypeMap[stmt.StreamType], err)
+	}
+	if stmt.StreamType == xsql.TypeTable {
+		s = fmt.Sprintf("Table %s is dropped.", stmt.Name)
 	}
 	return s, nil
 }
@@ -346,6 +374,9 @@ func (p *RuleProcessor) getRuleByJson(name, ruleJson string) (*api.Rule, error)
 	if rule.Options.LateTol < 0 {
 		return nil, fmt.Errorf("rule option lateTolerance %d is invalid, require a positive integer", rule.Options.LateTol)
 	}
+	if rule.Options.AllowedLateness < 0 {
+		return nil, fmt.Errorf("rule option allowedLateness %d is invalid, require a positive integer", rule.Options.AllowedLateness)
+	}
 	return rule, nil
 }
 
@@ -455,7 +486,13 @@ func cleanSinkCache(rule *api.Rule) error {
 		return err
 	}
 	defer store.Close()
-	for d, m := range rule.Actions {
+	deleteSinkCache(store, rule.Id, "", rule.Actions)
+	deleteSinkCache(store, rule.Id, "late_", rule.LateActions)
+	return nil
+}
+
+func deleteSinkCache(store kvstore.KvStore, ruleId string, prefix string, actions []map[string]interface{}) {
+	for d, m := range actions {
 		con := 1
 		for name, action := range m {
 			props, _ := action.(map[string]interface{})
@@ -465,13 +502,12 @@ func cleanSinkCache(rule *api.Rule) error {
 				}
 			}
 			for i := 0; i < con; i++ {
-				key := fmt.Sprintf("%s%s_%d%d", rule.Id, name, d, i)
+				key := fmt.Sprintf("%s%s%s_%d%d", ruleId, prefix, name, d, i)
 				util.Log.Debugf("delete cache key %s", key)
 				store.Delete(key)
 			}
 		}
 	}
-	return nil
 }
 
 func (p *RuleProcessor) createTopo(rule *api.Rule) (*xstream.TopologyNew, []api.Emitter, error) {
@@ -480,6 +516,13 @@ func (p *RuleProcessor) createTopo(rule *api.Rule) (*xstream.TopologyNew, []api.
 
 //For test to mock source
 func (p *RuleProcessor) createTopoWithSources(rule *api.Rule, sources []*nodes.SourceNode) (*xstream.TopologyNew, []api.Emitter, error) {
+	return CreateTopoWithSources(p.rootDbDir, rule, sources)
+}
+
+// CreateTopoWithSources creates the topology of the rule with the streams
+// saved in the directory. The sources are created from the streams if they
+// are not provided, such as the mock sources of the tests.
+func CreateTopoWithSources(dbDir string, rule *api.Rule, sources []*nodes.SourceNode) (*xstream.TopologyNew, []api.Emitter, error) {
 	name := rule.Id
 	sql := rule.Sql
 
@@ -502,7 +545,7 @@ func (p *RuleProcessor) createTopoWithSources(rule *api.Rule, sources []*nodes.S
 		if rule.Options.SendMetaToSink && (len(streamsFromStmt) > 1 || dimensions != nil) {
 			return nil, nil, fmt.Errorf("Invalid option sendMetaToSink, it can not be applied to window")
 		}
-		store := kvstore.GetKvStore(path.Join(p.rootDbDir, "stream"))
+		store := kvstore.GetKvStore(path.Join(dbDir, "stream"))
 		err = store.Open()
 		if err != nil {
 			return nil, nil, err
@@ -519,6 +562,17 @@ func (p *RuleProcessor) createTopoWithSources(rule *api.Rule, sources []*nodes.S
 				}
 			}
 		}
+		var (
+			streams     []string
+			tables      []*xsql.StreamStmt
+			tableInputs []api.Emitter
+		)
+		// The rows must reach the match in order, so the preprocessors are not
+		// run concurrently
+		inputConcurrency := rule.Options.Concurrency
+		if selectStmt.MatchRecognize != nil {
+			inputConcurrency = 1
+		}
 		for i, s := range streamsFromStmt {
 			streamStmt, err := GetStream(store, s)
 			if err != nil {
@@ -537,9 +591,21 @@ func (p *RuleProcessor) createTopoWithSources(rule *api.Rule, sources []*nodes.S
 			}
 			tp.AddSrc(srcNode)
 			preprocessorOp := xstream.Transform(pp, "preprocessor_"+s, rule.Options.BufferLength)
-			preprocessorOp.SetConcurrency(rule.Options.Concurrency)
+			preprocessorOp.SetConcurrency(inputConcurrency)
 			tp.AddOperator([]api.Emitter{srcNode}, preprocessorOp)
-			inputs = append(inputs, preprocessorOp)
+			if streamStmt.StreamType == xsql.TypeTable {
+				tables = append(tables, streamStmt)
+				tableInputs = append(tableInputs, preprocessorOp)
+			} else {
+				streams = append(streams, s)
+				inputs = append(inputs, preprocessorOp)
+			}
+		}
+		if len(tables) > 0 && selectStmt.Joins == nil {
+			return nil, nil, fmt.Errorf("table can only be used in join clause")
+		}
+		if len(streams) == 0 {
+			return nil, nil, fmt.Errorf("at least one stream is required, table cannot be queried alone")
 		}
 
 		var w *xsql.Window
@@ -552,22 +618,60 @@ func (p *RuleProcessor) createTopoWithSources(rule *api.Rule, sources []*nodes.S
 					tp.AddOperator(inputs, wfilterOp)
 					inputs = []api.Emitter{wfilterOp}
 				}
-				wop, err := nodes.NewWindowOp("window", w, rule.Options.IsEventTime, rule.Options.LateTol, streamsFromStmt, rule.Options.BufferLength)
+				wop, err := nodes.NewWindowOp("window", w, rule.Options.IsEventTime, rule.Options.LateTol, streams, rule.Options.BufferLength)
 				if err != nil {
 					return nil, nil, err
 				}
+				if err := wop.SetAllowedLateness(rule.Options.AllowedLateness); err != nil {
+					return nil, nil, err
+				}
 				tp.AddOperator(inputs, wop)
 				inputs = []api.Emitter{wop}
+				// The late actions are not run with the mock sources
+				if rule.Options.IsEventTime && shouldCreateSource {
+					for i, m := range rule.LateActions {
+						for name, action := range m {
+							props, ok := action.(map[string]interface{})
+							if !ok {
+								return nil, nil, fmt.Errorf("expect map[string]interface{} type for the late action properties, but found %v", action)
+							}
+							tp.AddSink([]api.Emitter{wop.LateOutput()}, nodes.NewSinkNode(fmt.Sprintf("late_%s_%d", name, i), name, props))
+						}
+					}
+				}
+			}
+		}
+		if len(rule.LateActions) > 0 && (w == nil || !rule.Options.IsEventTime) {
+			return nil, nil, fmt.Errorf("late actions are only supported by event time window")
+		}
+
+		if len(tables) > 0 {
+			alignOp, err := nodes.NewJoinAlignNode("join_aligner", tables, rule.Options.BufferLength)
+			if err != nil {
+				return nil, nil, err
 			}
+			tp.AddOperator(append(inputs, tableInputs...), alignOp)
+			inputs = []api.Emitter{alignOp}
 		}
 
-		if w != nil && selectStmt.Joins != nil {
+		if selectStmt.Joins != nil && (w != nil || len(tables) > 0) {
 			joinOp := xstream.Transform(&plans.JoinPlan{Joins: selectStmt.Joins, From: selectStmt.Sources[0].(*xsql.Table)}, "join", rule.Options.BufferLength)
 			joinOp.SetConcurrency(rule.Options.Concurrency)
 			tp.AddOperator(inputs, joinOp)
 			inputs = []api.Emitter{joinOp}
 		}
 
+		if selectStmt.MatchRecognize != nil {
+			mp, err := plans.NewMatchPlan(selectStmt.MatchRecognize)
+			if err != nil {
+				return nil, nil, err
+			}
+			// The rows must be matched in order, so the concurrency is always 1
+			matchOp := xstream.Transform(mp, "match", rule.Options.BufferLength)
+			tp.AddOperator(inputs, matchOp)
+			inputs = []api.Emitter{matchOp}
+		}
+
 		if selectStmt.Condition != nil {
 			filterOp := xstream.Transform(&plans.FilterPlan{Condition: selectStmt.Condition}, "filter", rule.Options.BufferLength)
 			filterOp.SetConcurrency(rule.Options.Concurrency)
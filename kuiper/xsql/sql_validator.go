@@ -1,6 +1,9 @@
 package xsql
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 func Validate(stmt *SelectStatement) error {
 	if HasAggFuncs(stmt.Condition) {
@@ -11,6 +14,17 @@ func Validate(stmt *SelectStatement) error {
 		return fmt.Errorf("Not allowed to call none-aggregate functions in HAVING clause.")
 	}
 
+	if stmt.MatchRecognize != nil {
+		if stmt.Joins != nil || stmt.Dimensions.GetWindow() != nil {
+			return fmt.Errorf("Not allowed to use MATCH_RECOGNIZE with window or join.")
+		}
+	}
+	for _, n := range []Node{stmt.Fields, stmt.Condition, stmt.Having} {
+		if name := findPatternFunc(n); name != "" {
+			return fmt.Errorf("Not allowed to call %s function outside MATCH_RECOGNIZE.", name)
+		}
+	}
+
 	//Cannot GROUP BY alias fields with aggregate funcs
 	//if stmt.Dimensions != nil {
 	//	for _, d := range stmt.Dimensions {
@@ -31,3 +45,18 @@ func Validate(stmt *SelectStatement) error {
 	//}
 	return nil
 }
+
+func findPatternFunc(node Node) string {
+	if node == nil {
+		return ""
+	}
+	var r string
+	WalkFunc(node, func(n Node) {
+		if f, ok := n.(*Call); ok {
+			if _, ok := patternFuncMap[strings.ToLower(f.Name)]; ok {
+				r = f.Name
+			}
+		}
+	})
+	return r
+}
@@ -1,6 +1,10 @@
 package xsql
 
-import "strings"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 func PrintFieldType(ft FieldType) (result string) {
 	switch t := ft.(type) {
@@ -30,6 +34,64 @@ func PrintFieldType(ft FieldType) (result string) {
 	return
 }
 
+// PrintExpr returns the SQL text of the expression.
+func PrintExpr(expr Expr) (result string) {
+	switch e := expr.(type) {
+	case *BinaryExpr:
+		switch e.OP {
+		case SUBSET:
+			result = PrintExpr(e.LHS) + PrintExpr(e.RHS)
+		case ARROW:
+			result = PrintExpr(e.LHS) + "->" + PrintExpr(e.RHS)
+		default:
+			result = PrintExpr(e.LHS) + " " + e.OP.String() + " " + PrintExpr(e.RHS)
+		}
+	case *ParenExpr:
+		result = "(" + PrintExpr(e.Expr) + ")"
+	case *ArrowExpr:
+		result = PrintExpr(e.Expr)
+	case *BracketExpr:
+		result = PrintExpr(e.Expr)
+	case *IndexExpr:
+		result = fmt.Sprintf("[%d]", e.Index)
+	case *ColonExpr:
+		if e.End < 0 {
+			result = fmt.Sprintf("[%d:]", e.Start)
+		} else {
+			result = fmt.Sprintf("[%d:%d]", e.Start, e.End)
+		}
+	case *Call:
+		args := make([]string, len(e.Args))
+		for i, a := range e.Args {
+			args[i] = PrintExpr(a)
+		}
+		result = e.Name + "(" + strings.Join(args, ", ") + ")"
+	case *FieldRef:
+		result = e.Name
+		if e.StreamName != "" {
+			result = string(e.StreamName) + "." + e.Name
+		}
+	case *MetaRef:
+		result = e.Name
+		if e.StreamName != "" {
+			result = string(e.StreamName) + "." + e.Name
+		}
+	case *Wildcard:
+		result = "*"
+	case *StringLiteral:
+		result = strconv.Quote(e.Val)
+	case *IntegerLiteral:
+		result = strconv.Itoa(e.Val)
+	case *NumberLiteral:
+		result = strconv.FormatFloat(e.Val, 'g', -1, 64)
+	case *BooleanLiteral:
+		result = strconv.FormatBool(e.Val)
+	case *TimeLiteral:
+		result = e.Val.String()
+	}
+	return
+}
+
 func GetStreams(stmt *SelectStatement) (result []string) {
 	if stmt == nil {
 		return nil
@@ -3,6 +3,7 @@ package xsql
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -68,3 +69,37 @@ func TestLowercaseKeyMap(t *testing.T) {
 		}
 	}
 }
+
+func TestPrintExpr(t *testing.T) {
+	var tests = []struct {
+		s      string
+		result []string
+	}{
+		{
+			s:      `SELECT a, b AS c, 1.5 * count(*) AS d FROM tbl`,
+			result: []string{"a", "b", "1.5 * count(*)"},
+		}, {
+			s:      `SELECT tbl.a->b, c[0], d[1:], e[1:3], "str" AS s, true AS t FROM tbl`,
+			result: []string{"tbl.a->b", "c[0]", "d[1:]", "e[1:3]", `"str"`, "true"},
+		}, {
+			s:      `SELECT (a + 1) / 2 AS r, concat(a, "x") AS c, meta(topic) AS m FROM tbl`,
+			result: []string{"(a + 1) / 2", `concat(a, "x")`, "meta(topic)"},
+		},
+	}
+
+	fmt.Printf("The test bucket size is %d.\n\n", len(tests))
+	for i, tt := range tests {
+		stmt, err := NewParser(strings.NewReader(tt.s)).Parse()
+		if err != nil {
+			t.Errorf("%d. %q: %s", i, tt.s, err)
+			continue
+		}
+		result := make([]string, len(stmt.Fields))
+		for j, f := range stmt.Fields {
+			result[j] = PrintExpr(f.Expr)
+		}
+		if !reflect.DeepEqual(tt.result, result) {
+			t.Errorf("%d. %q\n\nresult mismatch:\n\nexp=%#v\n\ngot=%#v\n\n", i, tt.s, tt.result, result)
+		}
+	}
+}
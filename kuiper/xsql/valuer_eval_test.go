@@ -3,7 +3,7 @@ package xsql
 import (
 	"errors"
 	"fmt"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"reflect"
 	"strings"
 	"testing"
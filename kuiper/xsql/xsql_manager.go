@@ -42,6 +42,11 @@ func init() {
 	})
 
 	Language.Handle(CREATE, func(p *Parser) (statement Statement, e error) {
+		if stmt, err := p.parseCreateFunctionStmt(); err != nil {
+			return nil, err
+		} else if stmt != nil {
+			return stmt, nil
+		}
 		return p.ParseCreateStreamStmt()
 	})
 
@@ -50,7 +55,7 @@ func init() {
 	})
 
 	Language.Handle(EXPLAIN, func(p *Parser) (statement Statement, e error) {
-		return p.parseExplainStreamsStmt()
+		return p.parseExplainStmt()
 	})
 
 	Language.Handle(DESCRIBE, func(p *Parser) (statement Statement, e error) {
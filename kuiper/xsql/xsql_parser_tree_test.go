@@ -38,7 +38,7 @@ func TestParser_ParseTree(t *testing.T) {
 		{
 			s:    `SHOW STREAMSf`,
 			stmt: nil,
-			err:  `found "STREAMSf", expected keyword streams.`,
+			err:  `found "STREAMSf", expected keyword streams or tables.`,
 		},
 
 		{
@@ -70,6 +70,141 @@ func TestParser_ParseTree(t *testing.T) {
 			},
 			err: ``,
 		},
+
+		{
+			s: `CREATE TABLE devices (
+					id STRING,
+					location STRING,
+				) WITH (DATASOURCE="devices.json", TYPE="file", KEY="id", RETAIN_SIZE="100");`,
+			stmt: &StreamStmt{
+				Name: StreamName("devices"),
+				StreamFields: []StreamField{
+					{Name: "id", FieldType: &BasicType{Type: STRINGS}},
+					{Name: "location", FieldType: &BasicType{Type: STRINGS}},
+				},
+				Options: map[string]string{
+					"DATASOURCE":  "devices.json",
+					"TYPE":        "file",
+					"KEY":         "id",
+					"RETAIN_SIZE": "100",
+				},
+				StreamType: TypeTable,
+			},
+		},
+
+		{
+			s:    `CREATE STREAM demo (USERID BIGINT) WITH (DATASOURCE="users", RETAIN_SIZE="100");`,
+			stmt: nil,
+			err:  `option RETAIN_SIZE is only supported by table.`,
+		},
+
+		{
+			s:    `CREATE TABLE devices (id STRING) WITH (DATASOURCE="devices.json", RETAIN_SIZE="0");`,
+			stmt: nil,
+			err:  `found "0", expect positive integer value in RETAIN_SIZE option.`,
+		},
+
+		{
+			s: `create table table (id STRING) WITH (DATASOURCE="devices.json", retain_size="10");`,
+			stmt: &StreamStmt{
+				Name: StreamName("table"),
+				StreamFields: []StreamField{
+					{Name: "id", FieldType: &BasicType{Type: STRINGS}},
+				},
+				Options: map[string]string{
+					"DATASOURCE":  "devices.json",
+					"RETAIN_SIZE": "10",
+				},
+				StreamType: TypeTable,
+			},
+		},
+
+		{
+			s: `SHOW TABLES`,
+			stmt: &ShowStreamsStatement{
+				StreamType: TypeTable,
+			},
+		},
+
+		{
+			s: `show tables`,
+			stmt: &ShowStreamsStatement{
+				StreamType: TypeTable,
+			},
+		},
+
+		{
+			s: `DESCRIBE TABLE devices`,
+			stmt: &DescribeStreamStatement{
+				Name:       "devices",
+				StreamType: TypeTable,
+			},
+		},
+
+		{
+			s: `DROP TABLE devices`,
+			stmt: &DropStreamStatement{
+				Name:       "devices",
+				StreamType: TypeTable,
+			},
+		},
+
+		{
+			s: `EXPLAIN SELECT name FROM demo WHERE size > 3`,
+			stmt: &ExplainSelectStatement{
+				Select: &SelectStatement{
+					Fields:  []Field{{Expr: &FieldRef{Name: "name"}, Name: "name", AName: ""}},
+					Sources: []Source{&Table{Name: "demo"}},
+					Condition: &BinaryExpr{
+						LHS: &FieldRef{Name: "size"},
+						OP:  GT,
+						RHS: &IntegerLiteral{Val: 3},
+					},
+				},
+			},
+		},
+
+		{
+			s:    `EXPLAIN SELECT name`,
+			stmt: nil,
+			err:  `found "EOF", expected FROM.`,
+		},
+
+		{
+			s: `CREATE FUNCTION fahrenheit(c) AS c * 1.8 + 32`,
+			stmt: &CreateFunctionStatement{
+				Name:   "fahrenheit",
+				Params: []string{"c"},
+				Expr: &BinaryExpr{
+					LHS: &BinaryExpr{LHS: &FieldRef{Name: "c"}, OP: MUL, RHS: &NumberLiteral{Val: 1.8}},
+					OP:  ADD,
+					RHS: &IntegerLiteral{Val: 32},
+				},
+			},
+		},
+
+		{
+			s: `CREATE FUNCTION level(t) LANGUAGE template TIMEOUT 50 AS "{{if gt .t 30.0}}hot{{else}}cold{{end}}"`,
+			stmt: &CreateFunctionStatement{
+				Name:     "level",
+				Params:   []string{"t"},
+				Language: "template",
+				Script:   "{{if gt .t 30.0}}hot{{else}}cold{{end}}",
+				Timeout:  50,
+			},
+		},
+
+		{
+			s:    `CREATE FUNCTION f(a, b AS a`,
+			stmt: nil,
+			err:  `found "AS", expected comma or ).`,
+		},
+
+		{
+			s:    `CREATE FUNCTION f(a) TIMEOUT 10 AS a`,
+			stmt: nil,
+			err:  `TIMEOUT is only supported by the script functions.`,
+		},
 	}
 
 	fmt.Printf("The test bucket size is %d.\n\n", len(tests))
@@ -177,6 +177,19 @@ func TestParser_ParseCreateStream(t *testing.T) {
 			},
 		},
 
+		{
+			s: `CREATE STREAM demo() WITH (DATASOURCE="users", FORMAT="protobuf", SCHEMAID="sensor.Reading");`,
+			stmt: &StreamStmt{
+				Name:         StreamName("demo"),
+				StreamFields: nil,
+				Options: map[string]string{
+					"DATASOURCE": "users",
+					"FORMAT":     "protobuf",
+					"SCHEMAID":   "sensor.Reading",
+				},
+			},
+		},
+
 		{
 			s: `CREATE STREAM demo (NAME string)
 				 WITH (DATASOURCE="users", FORMAT="JSON", KEY="USERID", STRICT_VALIDATION="true1");`, //Invalid STRICT_VALIDATION value
@@ -236,7 +249,7 @@ func TestParser_ParseCreateStream(t *testing.T) {
 				StreamFields: nil,
 				Options:      nil,
 			},
-			err: `found "sources", unknown option keys(DATASOURCE|FORMAT|KEY|CONF_KEY|STRICT_VALIDATION|TYPE).`,
+			err: `found "sources", unknown option keys(DATASOURCE|FORMAT|KEY|CONF_KEY|STRICT_VALIDATION|TYPE|RETAIN_SIZE|SCHEMAID).`,
 		},
 
 		{
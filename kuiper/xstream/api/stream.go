@@ -29,6 +29,19 @@ func (t *DefaultSourceTuple) Meta() map[string]interface{} {
 	return t.meta
 }
 
+// SnapshotMetaKey is the meta key of a source which reloads all its rows
+// periodically, such as the file source. The rows of one load carry the same
+// value, so that a table replaces its rows once a new value arrives.
+const SnapshotMetaKey = "$$snapshot"
+
+// TimestampedSourceTuple is a source tuple which carries the time of its
+// event, such as a replayed row. The source node uses it as the timestamp of
+// the tuple instead of the time it is received.
+type TimestampedSourceTuple interface {
+	SourceTuple
+	Timestamp() int64
+}
+
 type Logger interface {
 	Debug(args ...interface{})
 	Info(args ...interface{})
@@ -93,6 +106,7 @@ type Rewindable interface {
 type RuleOption struct {
 	IsEventTime        bool  `json:"isEventTime" yaml:"isEventTime"`
 	LateTol            int64 `json:"lateTolerance" yaml:"lateTolerance"`
+	AllowedLateness    int64 `json:"allowedLateness" yaml:"allowedLateness"`
 	Concurrency        int   `json:"concurrency" yaml:"concurrency"`
 	BufferLength       int   `json:"bufferLength" yaml:"bufferLength"`
 	SendMetaToSink     bool  `json:"sendMetaToSink" yaml:"sendMetaToSink"`
@@ -105,7 +119,9 @@ type Rule struct {
 	Id        string                   `json:"id"`
 	Sql       string                   `json:"sql"`
 	Actions   []map[string]interface{} `json:"actions"`
-	Options   *RuleOption              `json:"options"`
+	// LateActions receive the events which arrive too late for the event time window
+	LateActions []map[string]interface{} `json:"lateActions,omitempty"`
+	Options     *RuleOption              `json:"options"`
 }
 
 type StreamContext interface {
@@ -6,7 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/edgexfoundry/go-mod-core-contracts/models"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"testing"
 )
 
@@ -0,0 +1,101 @@
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+// FileSource loads the rows of a JSON array file, it is mostly used as the
+// source of lookup tables. The datasource is the file name, relative paths
+// are resolved against the path property or the kuiper data folder.
+type FileSource struct {
+	file     string
+	interval int
+}
+
+func (fs *FileSource) Configure(fileName string, props map[string]interface{}) error {
+	if fileName == "" {
+		return fmt.Errorf("file source is missing the file name, specify it as the datasource")
+	}
+	fs.file = fileName
+	if !filepath.IsAbs(fileName) {
+		dir := ""
+		if p, ok := props["path"]; ok {
+			if v, ok := p.(string); ok {
+				dir = v
+			}
+		}
+		if dir == "" {
+			d, err := util.GetDataLoc()
+			if err != nil {
+				return err
+			}
+			dir = d
+		}
+		fs.file = filepath.Join(dir, fileName)
+	}
+	if i, ok := props["interval"]; ok {
+		if i1, ok1 := i.(int); ok1 && i1 >= 0 {
+			fs.interval = i1
+		} else {
+			return fmt.Errorf("Not valid interval value %v.", i)
+		}
+	}
+	util.Log.Infof("Initialized with configurations %#v.", fs)
+	return nil
+}
+
+func (fs *FileSource) Open(ctx api.StreamContext, consumer chan<- api.SourceTuple, errCh chan<- error) {
+	if err := fs.load(ctx, consumer); err != nil {
+		errCh <- err
+		return
+	}
+	if fs.interval <= 0 {
+		return
+	}
+	logger := ctx.GetLogger()
+	ticker := time.NewTicker(time.Millisecond * time.Duration(fs.interval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := fs.load(ctx, consumer); err != nil {
+				logger.Warnf("Found error %s when reloading file %s", err, fs.file)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (fs *FileSource) load(ctx api.StreamContext, consumer chan<- api.SourceTuple) error {
+	c, err := ioutil.ReadFile(fs.file)
+	if err != nil {
+		return fmt.Errorf("fail to read file %s: %s", fs.file, err)
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(c, &rows); err != nil {
+		return fmt.Errorf("invalid data format of file %s, expect JSON array: %s", fs.file, err)
+	}
+	snapshot := time.Now().UnixNano()
+	for _, r := range rows {
+		select {
+		case consumer <- api.NewDefaultSourceTuple(r, map[string]interface{}{"file": fs.file, api.SnapshotMetaKey: snapshot}):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	ctx.GetLogger().Debugf("load %d rows from file %s", len(rows), fs.file)
+	return nil
+}
+
+func (fs *FileSource) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing file source")
+	return nil
+}
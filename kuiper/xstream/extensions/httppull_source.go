@@ -3,7 +3,6 @@ package extensions
 import (
 	"crypto/md5"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/formats"
 )
 
 const DEFAULT_INTERVAL = 10000
@@ -27,6 +27,7 @@ type HTTPPullSource struct {
 	body        string
 	bodyType    string
 	headers     map[string]string
+	converter   formats.Converter
 
 	client *http.Client
 }
@@ -114,6 +115,19 @@ func (hps *HTTPPullSource) Configure(device string, props map[string]interface{}
 		}
 	}
 
+	var format, schemaId string
+	if f, ok := props["format"]; ok {
+		format, _ = f.(string)
+	}
+	if s, ok := props["schemaId"]; ok {
+		schemaId, _ = s.(string)
+	}
+	c, err := formats.GetConverter(format, schemaId)
+	if err != nil {
+		return err
+	}
+	hps.converter = c
+
 	util.Log.Infof("Initialized with configurations %#v.", hps)
 	return nil
 }
@@ -166,10 +180,10 @@ func (hps *HTTPPullSource) initTimerPull(ctx api.StreamContext, consumer chan<-
 					}
 				}
 
-				result := make(map[string]interface{})
 				meta := make(map[string]interface{})
-				if e := json.Unmarshal(c, &result); e != nil {
-					logger.Errorf("Invalid data format, cannot convert %s into JSON with error %s", string(c), e)
+				result, e := hps.converter.Decode(c)
+				if e != nil {
+					logger.Errorf("Invalid data format: %s", e)
 					return
 				}
 
@@ -0,0 +1,167 @@
+package extensions
+
+import (
+	"fmt"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/gogo/protobuf/proto"
+	"github.com/nats-io/nats.go"
+)
+
+const DEFAULT_NATS_URL = "nats://localhost:4222"
+
+// MainfluxSource subscribes to the channel messages published on the
+// mainflux message broker, the datasource of the stream is the channel id.
+type MainfluxSource struct {
+	server   string
+	channel  string
+	subtopic string
+
+	pubsub      broker.Nats
+	sub         *nats.Subscription
+	transformer transformers.Transformer
+}
+
+func (ms *MainfluxSource) Configure(channel string, props map[string]interface{}) error {
+	ms.server = DEFAULT_NATS_URL
+	if s, ok := props["server"]; ok {
+		if v, ok := s.(string); ok && v != "" {
+			ms.server = v
+		}
+	}
+	if st, ok := props["subtopic"]; ok {
+		if v, ok := st.(string); ok {
+			ms.subtopic = v
+		}
+	}
+	if channel == "" {
+		return fmt.Errorf("mainflux source is missing the channel, specify it as the stream datasource")
+	}
+	ms.channel = channel
+	ms.transformer = senml.New()
+	return nil
+}
+
+func (ms *MainfluxSource) subject() string {
+	if ms.channel == "*" {
+		return ">"
+	}
+	if ms.subtopic != "" {
+		return fmt.Sprintf("%s.%s", ms.channel, ms.subtopic)
+	}
+	return ms.channel
+}
+
+func (ms *MainfluxSource) Open(ctx api.StreamContext, consumer chan<- api.SourceTuple, errCh chan<- error) {
+	log := ctx.GetLogger()
+
+	pubsub, err := broker.New(ms.server)
+	if err != nil {
+		errCh <- fmt.Errorf("found error when connecting to %s: %s", ms.server, err)
+		return
+	}
+	log.Infof("The connection to server %s was established successfully", ms.server)
+	ms.pubsub = pubsub
+
+	h := func(m *nats.Msg) {
+		var msg broker.Message
+		if err := proto.Unmarshal(m.Data, &msg); err != nil {
+			log.Errorf("Invalid message format on subject %s: %s", m.Subject, err)
+			return
+		}
+		rows, err := ms.Transform(msg)
+		if err != nil {
+			log.Errorf("Invalid SenML payload from publisher %s: %s", msg.Publisher, err)
+			return
+		}
+		for _, r := range rows {
+			select {
+			case consumer <- r:
+				log.Debugf("send data to source node")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+
+	sub, err := pubsub.Subscribe(ms.subject(), h)
+	if err != nil {
+		errCh <- err
+		return
+	}
+	ms.sub = sub
+	log.Infof("Successfully subscribe to channel %s", ms.subject())
+}
+
+// Transform decodes the SenML pack of the message, records having the same
+// time are merged into one row keyed by the record names.
+func (ms *MainfluxSource) Transform(msg broker.Message) ([]api.SourceTuple, error) {
+	t, err := ms.transformer.Transform(msg)
+	if err != nil {
+		return nil, err
+	}
+	records, ok := t.([]senml.Message)
+	if !ok {
+		return nil, fmt.Errorf("unexpected transformation result %T", t)
+	}
+
+	var times []float64
+	results := make(map[float64]map[string]interface{})
+	metas := make(map[float64]map[string]interface{})
+	for _, r := range records {
+		if r.Name == "" {
+			continue
+		}
+		result, ok := results[r.Time]
+		if !ok {
+			times = append(times, r.Time)
+			result = make(map[string]interface{})
+			results[r.Time] = result
+			metas[r.Time] = map[string]interface{}{
+				"publisher": msg.Publisher,
+				"channel":   msg.Channel,
+				"subtopic":  msg.Subtopic,
+				"protocol":  msg.Protocol,
+				"time":      r.Time,
+			}
+		}
+		switch {
+		case r.Value != nil:
+			result[r.Name] = *r.Value
+		case r.StringValue != nil:
+			result[r.Name] = *r.StringValue
+		case r.BoolValue != nil:
+			result[r.Name] = *r.BoolValue
+		case r.DataValue != nil:
+			result[r.Name] = *r.DataValue
+		case r.Sum != nil:
+			result[r.Name] = *r.Sum
+		}
+		metas[r.Time][r.Name] = map[string]interface{}{
+			"unit":        r.Unit,
+			"update_time": r.UpdateTime,
+		}
+	}
+
+	tuples := make([]api.SourceTuple, 0, len(times))
+	for _, t := range times {
+		tuples = append(tuples, api.NewDefaultSourceTuple(results[t], metas[t]))
+	}
+	return tuples, nil
+}
+
+func (ms *MainfluxSource) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Mainflux Source instance %d Done", ctx.GetInstanceId())
+	if ms.sub != nil {
+		if err := ms.sub.Unsubscribe(); err != nil {
+			return err
+		}
+	}
+	if ms.pubsub != nil {
+		ms.pubsub.Close()
+	}
+	return nil
+}
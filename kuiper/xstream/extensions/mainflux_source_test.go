@@ -0,0 +1,98 @@
+package extensions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+)
+
+func TestMainfluxSource_Transform(t *testing.T) {
+	var tests = []struct {
+		payload string
+		result  []map[string]interface{}
+		meta    []map[string]interface{}
+		err     bool
+	}{
+		{
+			payload: `[{"bn":"dev:","bt":10,"n":"temperature","v":20.5,"u":"Cel"},{"n":"on","vb":true}]`,
+			result: []map[string]interface{}{
+				{"dev:temperature": 20.5, "dev:on": true},
+			},
+			meta: []map[string]interface{}{
+				{
+					"publisher":       "thing",
+					"channel":         "chan",
+					"subtopic":        "sensors",
+					"protocol":        "mqtt",
+					"time":            float64(10),
+					"dev:temperature": map[string]interface{}{"unit": "Cel", "update_time": float64(0)},
+					"dev:on":          map[string]interface{}{"unit": "", "update_time": float64(0)},
+				},
+			},
+		}, {
+			payload: `[{"n":"temperature","v":20,"t":1},{"n":"temperature","v":21,"t":2}]`,
+			result: []map[string]interface{}{
+				{"temperature": float64(20)},
+				{"temperature": float64(21)},
+			},
+			meta: []map[string]interface{}{
+				{
+					"publisher":   "thing",
+					"channel":     "chan",
+					"subtopic":    "sensors",
+					"protocol":    "mqtt",
+					"time":        float64(1),
+					"temperature": map[string]interface{}{"unit": "", "update_time": float64(0)},
+				}, {
+					"publisher":   "thing",
+					"channel":     "chan",
+					"subtopic":    "sensors",
+					"protocol":    "mqtt",
+					"time":        float64(2),
+					"temperature": map[string]interface{}{"unit": "", "update_time": float64(0)},
+				},
+			},
+		}, {
+			payload: `{"temperature": 20}`,
+			err:     true,
+		},
+	}
+
+	ms := &MainfluxSource{}
+	if err := ms.Configure("chan", map[string]interface{}{}); err != nil {
+		t.Fatalf("configure error: %s", err)
+	}
+	for i, tt := range tests {
+		msg := broker.Message{
+			Publisher: "thing",
+			Channel:   "chan",
+			Subtopic:  "sensors",
+			Protocol:  "mqtt",
+			Payload:   []byte(tt.payload),
+		}
+		tuples, err := ms.Transform(msg)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%d. expect error but got nil", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d. unexpected error: %s", i, err)
+			continue
+		}
+		if len(tuples) != len(tt.result) {
+			t.Errorf("%d. expect %d rows but got %d", i, len(tt.result), len(tuples))
+			continue
+		}
+		for j, tuple := range tuples {
+			if !reflect.DeepEqual(tt.result[j], tuple.Message()) {
+				t.Errorf("%d.%d row mismatch:\n  exp=%v\n  got=%v", i, j, tt.result[j], tuple.Message())
+			}
+			if !reflect.DeepEqual(tt.meta[j], tuple.Meta()) {
+				t.Errorf("%d.%d meta mismatch:\n  exp=%v\n  got=%v", i, j, tt.meta[j], tuple.Meta())
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package extensions
+
+import (
+	"fmt"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/memory"
+)
+
+const DEFAULT_MEMORY_BUFFER_LENGTH = 1024
+
+// MemorySource receives the rows published by the memory sink of other
+// rules, the datasource is the topic.
+type MemorySource struct {
+	topic        string
+	bufferLength int
+	id           string
+}
+
+func (ms *MemorySource) Configure(topic string, props map[string]interface{}) error {
+	if topic == "" {
+		return fmt.Errorf("memory source is missing the topic, specify it as the datasource")
+	}
+	ms.topic = topic
+	ms.bufferLength = DEFAULT_MEMORY_BUFFER_LENGTH
+	if b, ok := props["bufferLength"]; ok {
+		if b1, ok1 := b.(int); ok1 && b1 > 0 {
+			ms.bufferLength = b1
+		} else {
+			return fmt.Errorf("Not valid bufferLength value %v.", b)
+		}
+	}
+	return nil
+}
+
+func (ms *MemorySource) Open(ctx api.StreamContext, consumer chan<- api.SourceTuple, errCh chan<- error) {
+	log := ctx.GetLogger()
+	ms.id = fmt.Sprintf("%s_%s_%d", ctx.GetRuleId(), ctx.GetOpId(), ctx.GetInstanceId())
+	ch := memory.Subscribe(ms.topic, ms.id, ms.bufferLength)
+	log.Infof("Successfully subscribe to memory topic %s", ms.topic)
+	for {
+		select {
+		case r, opened := <-ch:
+			if !opened {
+				return
+			}
+			select {
+			case consumer <- api.NewDefaultSourceTuple(r, map[string]interface{}{"topic": ms.topic}):
+				log.Debugf("send data to source node")
+			case <-ctx.Done():
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ms *MemorySource) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing memory source")
+	if ms.id != "" {
+		memory.Unsubscribe(ms.topic, ms.id)
+	}
+	return nil
+}
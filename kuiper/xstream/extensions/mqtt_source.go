@@ -10,6 +10,7 @@ import (
 
 	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/formats"
 	MQTT "github.com/eclipse/paho.mqtt.golang"
 	"github.com/google/uuid"
 )
@@ -24,9 +25,10 @@ type MQTTSource struct {
 	certPath string
 	pkeyPath string
 
-	model  modelVersion
-	schema map[string]interface{}
-	conn   MQTT.Client
+	model     modelVersion
+	schema    map[string]interface{}
+	converter formats.Converter
+	conn      MQTT.Client
 }
 
 type MQTTConfig struct {
@@ -41,6 +43,8 @@ type MQTTConfig struct {
 	PrivateKPath       string   `json:"privateKeyPath"`
 	KubeedgeModelFile  string   `json:"kubeedgeModelFile"`
 	KubeedgeVersion    string   `json:"kubeedgeVersion"`
+	Format             string   `json:"format"`
+	SchemaId           string   `json:"schemaId"`
 }
 
 func (ms *MQTTSource) WithSchema(schema string) *MQTTSource {
@@ -72,6 +76,10 @@ func (ms *MQTTSource) Configure(topic string, props map[string]interface{}) erro
 	ms.certPath = cfg.Certification
 	ms.pkeyPath = cfg.PrivateKPath
 
+	if ms.converter, err = formats.GetConverter(cfg.Format, cfg.SchemaId); err != nil {
+		return err
+	}
+
 	if 0 != len(cfg.KubeedgeModelFile) {
 		conf, err := util.LoadConf(path.Join("sources", cfg.KubeedgeModelFile))
 		if nil != err {
@@ -137,7 +145,7 @@ func (ms *MQTTSource) Open(ctx api.StreamContext, consumer chan<- api.SourceTupl
 	opts.SetConnectionLostHandler(func(client MQTT.Client, e error) {
 		log.Errorf("The connection %s is disconnected due to error %s, will try to re-connect later.", ms.srv+": "+ms.clientid, e)
 		reconn = true
-		subscribe(ms.tpc, client, ctx, consumer, ms.model)
+		subscribe(ms.tpc, client, ctx, consumer, ms.model, ms.converter)
 	})
 
 	opts.SetOnConnectHandler(func(client MQTT.Client) {
@@ -152,18 +160,17 @@ func (ms *MQTTSource) Open(ctx api.StreamContext, consumer chan<- api.SourceTupl
 	}
 	log.Infof("The connection to server %s was established successfully", ms.srv)
 	ms.conn = c
-	subscribe(ms.tpc, c, ctx, consumer, ms.model)
+	subscribe(ms.tpc, c, ctx, consumer, ms.model, ms.converter)
 	log.Infof("Successfully subscribe to topic %s", ms.srv+": "+ms.clientid)
 }
 
-func subscribe(topic string, client MQTT.Client, ctx api.StreamContext, consumer chan<- api.SourceTuple, model modelVersion) {
+func subscribe(topic string, client MQTT.Client, ctx api.StreamContext, consumer chan<- api.SourceTuple, model modelVersion, converter formats.Converter) {
 	log := ctx.GetLogger()
 	h := func(client MQTT.Client, msg MQTT.Message) {
 		log.Debugf("instance %d received %s", ctx.GetInstanceId(), msg.Payload())
-		result := make(map[string]interface{})
-		//The unmarshal type can only be bool, float64, string, []interface{}, map[string]interface{}, nil
-		if e := json.Unmarshal(msg.Payload(), &result); e != nil {
-			log.Errorf("Invalid data format, cannot convert %s into JSON with error %s", string(msg.Payload()), e)
+		result, e := converter.Decode(msg.Payload())
+		if e != nil {
+			log.Errorf("Invalid data format: %s", e)
 			return
 		}
 
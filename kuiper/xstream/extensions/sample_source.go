@@ -0,0 +1,98 @@
+package extensions
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+// SampleSource replays the sample rows of a rule test. The rows are sent at
+// the intervals of their timestamps. For the event time rules, the timestamp
+// field of the stream is set, the rows carry their timestamps as the event
+// time, and the field is filled with the timestamp of the rows which have no
+// such field, so that the preprocessor reads it.
+type SampleSource struct {
+	rows           []*xsql.Tuple
+	timestampField string
+	sent           int
+	mutex          sync.Mutex
+}
+
+func NewSampleSource(rows []*xsql.Tuple, timestampField string) *SampleSource {
+	return &SampleSource{rows: rows, timestampField: timestampField}
+}
+
+func (ss *SampleSource) Configure(_ string, _ map[string]interface{}) error {
+	return nil
+}
+
+func (ss *SampleSource) Open(ctx api.StreamContext, consumer chan<- api.SourceTuple, _ chan<- error) {
+	log := ctx.GetLogger()
+	var prev int64
+	for i, r := range ss.rows {
+		if prev > 0 && r.Timestamp > prev {
+			select {
+			case <-time.After(time.Duration(r.Timestamp-prev) * time.Millisecond):
+			case <-ctx.Done():
+				return
+			}
+		}
+		prev = r.Timestamp
+		t := &sampleTuple{message: r.Message}
+		if ss.timestampField != "" {
+			t.message, t.timestamp = ss.message(r), r.Timestamp
+		}
+		select {
+		case consumer <- t:
+			log.Debugf("sample source %s is sending row %d:%s", ctx.GetOpId(), i, r)
+		case <-ctx.Done():
+			return
+		}
+		ss.mutex.Lock()
+		ss.sent = i + 1
+		ss.mutex.Unlock()
+	}
+	log.Debugf("sample source sends out all rows")
+}
+
+func (ss *SampleSource) message(r *xsql.Tuple) map[string]interface{} {
+	if _, ok := r.Message[ss.timestampField]; ok || r.Timestamp <= 0 {
+		return r.Message
+	}
+	m := make(map[string]interface{}, len(r.Message)+1)
+	for k, v := range r.Message {
+		m[k] = v
+	}
+	m[ss.timestampField] = r.Timestamp
+	return m
+}
+
+// Sent returns the number of the rows sent.
+func (ss *SampleSource) Sent() int {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	return ss.sent
+}
+
+func (ss *SampleSource) Close(_ api.StreamContext) error {
+	return nil
+}
+
+type sampleTuple struct {
+	message   map[string]interface{}
+	timestamp int64
+}
+
+func (t *sampleTuple) Message() map[string]interface{} {
+	return t.message
+}
+
+func (t *sampleTuple) Meta() map[string]interface{} {
+	return map[string]interface{}{"topic": "sample"}
+}
+
+func (t *sampleTuple) Timestamp() int64 {
+	return t.timestamp
+}
@@ -0,0 +1,69 @@
+package extensions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+)
+
+func TestSampleSource_Open(t *testing.T) {
+	rows := []*xsql.Tuple{
+		{Message: map[string]interface{}{"color": "red"}, Timestamp: 1000},
+		{Message: map[string]interface{}{"color": "blue", "ts": float64(900)}, Timestamp: 1010},
+		{Message: map[string]interface{}{"color": "yellow"}},
+	}
+	var tests = []struct {
+		timestampField string
+		messages       []map[string]interface{}
+		timestamps     []int64
+	}{
+		{
+			timestampField: "",
+			messages: []map[string]interface{}{
+				{"color": "red"},
+				{"color": "blue", "ts": float64(900)},
+				{"color": "yellow"},
+			},
+			timestamps: []int64{0, 0, 0},
+		}, {
+			timestampField: "ts",
+			messages: []map[string]interface{}{
+				{"color": "red", "ts": int64(1000)},
+				{"color": "blue", "ts": float64(900)},
+				{"color": "yellow"},
+			},
+			timestamps: []int64{1000, 1010, 0},
+		},
+	}
+	ctx := contexts.WithValue(contexts.Background(), contexts.LoggerKey, util.Log.WithField("rule", "TestSampleSource_Open"))
+	for i, tt := range tests {
+		ss := NewSampleSource(rows, tt.timestampField)
+		consumer := make(chan api.SourceTuple, len(rows))
+		ss.Open(ctx, consumer, nil)
+		if ss.Sent() != len(rows) {
+			t.Errorf("%d. expect %d rows sent but got %d", i, len(rows), ss.Sent())
+		}
+		close(consumer)
+		var (
+			messages   []map[string]interface{}
+			timestamps []int64
+		)
+		for st := range consumer {
+			messages = append(messages, st.Message())
+			timestamps = append(timestamps, st.(api.TimestampedSourceTuple).Timestamp())
+		}
+		if !reflect.DeepEqual(tt.messages, messages) {
+			t.Errorf("%d. messages mismatch:\n\nexp=%v\n\ngot=%v\n\n", i, tt.messages, messages)
+		}
+		if !reflect.DeepEqual(tt.timestamps, timestamps) {
+			t.Errorf("%d. timestamps mismatch:\n\nexp=%v\n\ngot=%v\n\n", i, tt.timestamps, timestamps)
+		}
+	}
+	if _, ok := rows[0].Message["ts"]; ok {
+		t.Errorf("expect the sample rows unchanged but got %v", rows[0].Message)
+	}
+}
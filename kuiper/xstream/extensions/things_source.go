@@ -0,0 +1,155 @@
+package extensions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+const DEFAULT_THINGS_URL = "http://localhost:8182"
+const DEFAULT_THINGS_INTERVAL = 60000
+const thingsPageLimit = 100
+
+// ThingsSource periodically loads the things and their metadata from the
+// things service, it is mostly used as the source of lookup tables. The
+// datasource is the thing name, "*" loads all the things of the user.
+type ThingsSource struct {
+	url      string
+	token    string
+	name     string
+	interval int
+
+	client *http.Client
+}
+
+type thingRes struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+type thingsPageRes struct {
+	Total  uint64     `json:"total"`
+	Offset uint64     `json:"offset"`
+	Limit  uint64     `json:"limit"`
+	Things []thingRes `json:"things"`
+}
+
+func (ts *ThingsSource) Configure(name string, props map[string]interface{}) error {
+	ts.name = name
+	ts.url = DEFAULT_THINGS_URL
+	if u, ok := props["url"]; ok {
+		if p, ok := u.(string); ok && p != "" {
+			ts.url = strings.TrimSuffix(p, "/")
+		}
+	}
+	if _, err := url.Parse(ts.url); err != nil {
+		return fmt.Errorf("Not valid url value %s: %s.", ts.url, err)
+	}
+	if t, ok := props["token"]; ok {
+		if p, ok := t.(string); ok {
+			ts.token = p
+		}
+	}
+	ts.interval = DEFAULT_THINGS_INTERVAL
+	if i, ok := props["interval"]; ok {
+		if i1, ok1 := i.(int); ok1 && i1 > 0 {
+			ts.interval = i1
+		} else {
+			return fmt.Errorf("Not valid interval value %v.", i)
+		}
+	}
+	util.Log.Infof("Initialized things source with url %s and interval %d.", ts.url, ts.interval)
+	return nil
+}
+
+func (ts *ThingsSource) Open(ctx api.StreamContext, consumer chan<- api.SourceTuple, errCh chan<- error) {
+	logger := ctx.GetLogger()
+	ts.client = &http.Client{Timeout: time.Duration(DEFAULT_TIMEOUT) * time.Millisecond}
+	if err := ts.load(ctx, consumer); err != nil {
+		logger.Warnf("Found error %s when loading things from %s", err, ts.url)
+	}
+	ticker := time.NewTicker(time.Millisecond * time.Duration(ts.interval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := ts.load(ctx, consumer); err != nil {
+				logger.Warnf("Found error %s when loading things from %s", err, ts.url)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// load reads all pages of the things and emits one row for each thing, the
+// metadata keys are flattened into the row. The thing key is never exposed.
+func (ts *ThingsSource) load(ctx api.StreamContext, consumer chan<- api.SourceTuple) error {
+	var offset uint64
+	snapshot := time.Now().UnixNano()
+	for {
+		page, err := ts.fetch(offset)
+		if err != nil {
+			return err
+		}
+		for _, th := range page.Things {
+			if ts.name != "" && ts.name != "*" && ts.name != th.Name {
+				continue
+			}
+			row := make(map[string]interface{}, len(th.Metadata)+2)
+			for k, v := range th.Metadata {
+				row[k] = v
+			}
+			row["id"] = th.ID
+			row["name"] = th.Name
+			select {
+			case consumer <- api.NewDefaultSourceTuple(row, map[string]interface{}{"url": ts.url, api.SnapshotMetaKey: snapshot}):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		offset += uint64(len(page.Things))
+		if len(page.Things) == 0 || offset >= page.Total {
+			return nil
+		}
+	}
+}
+
+func (ts *ThingsSource) fetch(offset uint64) (thingsPageRes, error) {
+	var page thingsPageRes
+	u := fmt.Sprintf("%s/things?offset=%d&limit=%d", ts.url, offset, thingsPageLimit)
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return page, err
+	}
+	req.Header.Set("Authorization", ts.token)
+	resp, err := ts.client.Do(req)
+	if err != nil {
+		return page, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return page, fmt.Errorf("http return code %d", resp.StatusCode)
+	}
+	c, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return page, err
+	}
+	if err := json.Unmarshal(c, &page); err != nil {
+		return page, fmt.Errorf("invalid things response %s: %s", c, err)
+	}
+	return page, nil
+}
+
+func (ts *ThingsSource) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing things source")
+	return nil
+}
@@ -0,0 +1,25 @@
+package formats
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+type cborConverter struct{}
+
+func (c *cborConverter) Decode(b []byte) (map[string]interface{}, error) {
+	var result interface{}
+	if err := cbor.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("cannot convert %x into CBOR with error %s", b, err)
+	}
+	m, ok := normalize(result).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expect a CBOR map but found %T", result)
+	}
+	return m, nil
+}
+
+func (c *cborConverter) Encode(d interface{}) ([]byte, error) {
+	return cbor.Marshal(d)
+}
@@ -0,0 +1,126 @@
+package formats
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/senml"
+)
+
+const (
+	JSON       = "json"
+	SENML      = "senml"
+	SENML_CBOR = "senml-cbor"
+	CBOR       = "cbor"
+	PROTOBUF   = "protobuf"
+)
+
+// Converter decodes the payload of a source into a message and encodes the
+// result of a sink into a payload. The decoded messages only contain the
+// values produced by encoding/json, that is bool, float64, string, nil,
+// []interface{} and map[string]interface{}, so that the preprocessors handle
+// them in the same way as json messages.
+type Converter interface {
+	// Decode converts a payload into a message.
+	Decode(b []byte) (map[string]interface{}, error)
+	// Encode converts a message or a slice of messages into a payload.
+	Encode(d interface{}) ([]byte, error)
+}
+
+// GetConverter returns the converter of the stream or sink format. The
+// schemaId is only used by protobuf which refers to the message type as
+// "<schema file name>.<message name>".
+func GetConverter(format string, schemaId string) (Converter, error) {
+	switch strings.ToLower(format) {
+	case "", JSON:
+		return &jsonConverter{}, nil
+	case SENML:
+		return &senmlConverter{format: senml.JSON}, nil
+	case SENML_CBOR:
+		return &senmlConverter{format: senml.CBOR}, nil
+	case CBOR:
+		return &cborConverter{}, nil
+	case PROTOBUF:
+		return newProtobufConverter(schemaId)
+	default:
+		return nil, fmt.Errorf("unsupported format %s, the value could be only json, senml, senml-cbor, cbor or protobuf", format)
+	}
+}
+
+type jsonConverter struct{}
+
+func (c *jsonConverter) Decode(b []byte) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	//The unmarshal type can only be bool, float64, string, []interface{}, map[string]interface{}, nil
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("cannot convert %s into JSON with error %s", string(b), err)
+	}
+	return result, nil
+}
+
+func (c *jsonConverter) Encode(d interface{}) ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// normalize converts the values of the other decoders into the json types.
+// Numbers become float64 and binaries become base64 strings.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			r[fmt.Sprintf("%v", k)] = normalize(e)
+		}
+		return r
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalize(e)
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalize(e)
+		}
+		return t
+	case []byte:
+		return base64.StdEncoding.EncodeToString(t)
+	case int:
+		return float64(t)
+	case int32:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint32:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case float32:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+// toRows accepts a message or a slice of messages to be encoded.
+func toRows(d interface{}) ([]map[string]interface{}, error) {
+	switch t := d.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{t}, nil
+	case []map[string]interface{}:
+		return t, nil
+	case []interface{}:
+		rows := make([]map[string]interface{}, len(t))
+		for i, e := range t {
+			r, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expect a message but found %[1]T(%[1]v)", e)
+			}
+			rows[i] = r
+		}
+		return rows, nil
+	default:
+		return nil, fmt.Errorf("expect a message or a slice of messages but found %[1]T(%[1]v)", d)
+	}
+}
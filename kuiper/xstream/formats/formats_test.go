@@ -0,0 +1,148 @@
+package formats
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/mainflux/senml"
+)
+
+func TestSenmlConverter(t *testing.T) {
+	var tests = []struct {
+		payload string
+		result  map[string]interface{}
+		err     bool
+	}{
+		{
+			payload: `[{"bn":"dev:","bt":10,"n":"temperature","v":20.5,"u":"Cel"},{"n":"on","vb":true},{"n":"label","vs":"a"}]`,
+			result:  map[string]interface{}{"dev:temperature": 20.5, "dev:on": true, "dev:label": "a"},
+		}, {
+			payload: `[{"n":"temperature","v":21,"t":2},{"n":"temperature","v":20,"t":1}]`,
+			result:  map[string]interface{}{"temperature": float64(21)},
+		}, {
+			payload: `{"n":"temperature"}`,
+			err:     true,
+		},
+	}
+	c, _ := GetConverter("SENML", "")
+	for i, tt := range tests {
+		result, err := c.Decode([]byte(tt.payload))
+		if (err != nil) != tt.err {
+			t.Errorf("%d. error mismatch: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.result, result) {
+			t.Errorf("%d. result mismatch:\n  exp=%#v\n  got=%#v\n", i, tt.result, result)
+		}
+	}
+
+	b, err := c.Encode([]interface{}{
+		map[string]interface{}{"temperature": 20.5, "on": true, "label": "a", "tags": []interface{}{"x"}, "empty": nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := `[{"n":"label","vs":"a"},{"n":"on","vb":true},{"n":"tags","vs":"[\"x\"]"},{"n":"temperature","v":20.5}]`
+	if string(b) != exp {
+		t.Errorf("encode mismatch:\n  exp=%s\n  got=%s\n", exp, b)
+	}
+}
+
+func TestSenmlCborConverter(t *testing.T) {
+	v, b := 20.5, true
+	payload, err := senml.Encode(senml.Pack{Records: []senml.Record{
+		{BaseName: "dev:", BaseTime: 10, Name: "temperature", Unit: "Cel", Value: &v},
+		{Name: "on", BoolValue: &b},
+	}}, senml.CBOR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := GetConverter("SENML-CBOR", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.Decode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{"dev:temperature": 20.5, "dev:on": true}
+	if !reflect.DeepEqual(exp, result) {
+		t.Errorf("result mismatch:\n  exp=%#v\n  got=%#v\n", exp, result)
+	}
+	if _, err := c.Decode([]byte(`[{"n":"temperature","v":20.5}]`)); err == nil {
+		t.Errorf("expect error for json payload")
+	}
+
+	msg := map[string]interface{}{"temperature": 20.5, "on": true, "label": "a"}
+	encoded, err := c.Encode(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = c.Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(msg, result) {
+		t.Errorf("round trip mismatch:\n  exp=%#v\n  got=%#v\n", msg, result)
+	}
+}
+
+func TestCborConverter(t *testing.T) {
+	payload, _ := cbor.Marshal(map[string]interface{}{
+		"id":    1,
+		"name":  "dev",
+		"temp":  -20.5,
+		"data":  []byte{1, 2},
+		"inner": map[interface{}]interface{}{"a": uint64(3), 1: true},
+		"list":  []interface{}{int64(-1), "b"},
+	})
+	c, _ := GetConverter("cbor", "")
+	result, err := c.Decode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"id":    float64(1),
+		"name":  "dev",
+		"temp":  -20.5,
+		"data":  "AQI=",
+		"inner": map[string]interface{}{"a": float64(3), "1": true},
+		"list":  []interface{}{float64(-1), "b"},
+	}
+	if !reflect.DeepEqual(exp, result) {
+		t.Errorf("decode mismatch:\n  exp=%#v\n  got=%#v\n", exp, result)
+	}
+
+	b, err := c.Encode([]interface{}{exp})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []map[string]interface{}
+	if err := cbor.Unmarshal(b, &rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || !reflect.DeepEqual(exp, normalize(rows[0])) {
+		t.Errorf("encode mismatch:\n  exp=%#v\n  got=%#v\n", exp, rows)
+	}
+
+	if _, err := c.Decode([]byte{0x01}); err == nil {
+		t.Errorf("expect error for non map payload")
+	}
+}
+
+func TestGetConverter(t *testing.T) {
+	for _, f := range []string{"", "json", "JSON", "senml", "cbor"} {
+		if _, err := GetConverter(f, ""); err != nil {
+			t.Errorf("format %s: %v", f, err)
+		}
+	}
+	for _, f := range []string{"xml", "binary"} {
+		if _, err := GetConverter(f, ""); err == nil {
+			t.Errorf("format %s: expect error", f)
+		}
+	}
+	if _, err := GetConverter("protobuf", "sensor"); err == nil {
+		t.Errorf("expect error for invalid schemaId")
+	}
+}
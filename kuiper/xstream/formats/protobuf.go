@@ -0,0 +1,77 @@
+package formats
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/pkg/protodesc"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// PROTOBUF_SCHEMA_DIR is the folder of the descriptor set files in the etc
+// folder.
+const PROTOBUF_SCHEMA_DIR = "schemas/protobuf"
+
+type protobufConverter struct {
+	descriptors *protodesc.Descriptors
+	msgType     string
+}
+
+// newProtobufConverter loads the message type referred by the schemaId which
+// is "<schema file name>.<message name>", e.g. "sensor.Reading" for the
+// message Reading in etc/schemas/protobuf/sensor.pb, the descriptor set
+// compiled from sensor.proto. The message name can be the full name or the
+// name in the package of the schema file.
+func newProtobufConverter(schemaId string) (Converter, error) {
+	i := strings.Index(schemaId, ".")
+	if i <= 0 || i == len(schemaId)-1 {
+		return nil, fmt.Errorf("invalid schemaId %q for protobuf format, expect <schema file name>.<message name>", schemaId)
+	}
+	conf, err := util.GetConfLoc()
+	if err != nil {
+		return nil, err
+	}
+	files, err := protodesc.ReadDescriptorSet(path.Join(conf, PROTOBUF_SCHEMA_DIR, schemaId[:i]+".pb"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf schema %s: %s", schemaId[:i], err)
+	}
+	return newDescriptorConverter(files, schemaId[i+1:])
+}
+
+// newDescriptorConverter finds the message in the files by the full name or
+// the name in the package of the last file, which is the compiled file as
+// protoc lists the imported files first.
+func newDescriptorConverter(files []*descriptor.FileDescriptorProto, name string) (*protobufConverter, error) {
+	d, err := protodesc.NewDescriptors(files...)
+	if err != nil {
+		return nil, err
+	}
+	if !d.Has(name) && len(files) > 0 && files[len(files)-1].GetPackage() != "" {
+		name = files[len(files)-1].GetPackage() + "." + name
+	}
+	if !d.Has(name) {
+		return nil, fmt.Errorf("message type %s is not found", name)
+	}
+	return &protobufConverter{descriptors: d, msgType: name}, nil
+}
+
+// Decode converts the payload into the message type. The fields with default
+// values which are not in the payload are set with the defaults in proto3 as
+// they are not distinguished from the missing fields. The 64 bits integers
+// are converted to float64 like the other numbers and the bytes are base64
+// strings as in the json mapping.
+func (c *protobufConverter) Decode(b []byte) (map[string]interface{}, error) {
+	return c.descriptors.DecodeJSON(c.msgType, b)
+}
+
+// Encode converts a message into the message type. The fields not defined
+// in the message type are dropped.
+func (c *protobufConverter) Encode(d interface{}) ([]byte, error) {
+	m, ok := d.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("protobuf format can only encode a message but found %T, please set sendSingle to true", d)
+	}
+	return c.descriptors.Encode(c.msgType, m)
+}
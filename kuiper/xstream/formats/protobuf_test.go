@@ -0,0 +1,334 @@
+package formats
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/pkg/protodesc"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// readingFiles describes the schema compiled from the files:
+//
+//	// reading.proto
+//	syntax = "proto3";
+//	package sensor;
+//	import "common.proto";
+//	message Reading {
+//	  enum Status { UNKNOWN = 0; OK = 1; FAIL = 2; }
+//	  message Location { double lat = 1; double lng = 2; }
+//	  string id = 1;
+//	  int64 ts = 2;
+//	  sint32 delta = 3;
+//	  repeated float values = 4;
+//	  Status status = 5;
+//	  Location location = 6;
+//	  map<string, int32> counters = 7;
+//	  bytes raw = 8;
+//	  repeated common.Tag tags = 9;
+//	  oneof reading {
+//	    double temperature = 10;
+//	    string error = 11;
+//	  }
+//	  bool ok = 12;
+//	  fixed32 seq = 13;
+//	  repeated int32 codes = 14 [packed = false];
+//	}
+//
+//	// common.proto
+//	syntax = "proto2";
+//	package common;
+//	message Tag {
+//	  required string key = 1;
+//	  optional string value = 2 [default = "none"];
+//	}
+func readingFiles() []*descriptor.FileDescriptorProto {
+	field := func(name string, number int32, typ descriptor.FieldDescriptorProto_Type, label descriptor.FieldDescriptorProto_Label, typeName string) *descriptor.FieldDescriptorProto {
+		f := &descriptor.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(number),
+			Type:   &typ,
+			Label:  &label,
+		}
+		if typeName != "" {
+			f.TypeName = proto.String(typeName)
+		}
+		return f
+	}
+	optional, repeated, required := descriptor.FieldDescriptorProto_LABEL_OPTIONAL, descriptor.FieldDescriptorProto_LABEL_REPEATED, descriptor.FieldDescriptorProto_LABEL_REQUIRED
+
+	temperature := field("temperature", 10, descriptor.FieldDescriptorProto_TYPE_DOUBLE, optional, "")
+	temperature.OneofIndex = proto.Int32(0)
+	e := field("error", 11, descriptor.FieldDescriptorProto_TYPE_STRING, optional, "")
+	e.OneofIndex = proto.Int32(0)
+	codes := field("codes", 14, descriptor.FieldDescriptorProto_TYPE_INT32, repeated, "")
+	codes.Options = &descriptor.FieldOptions{Packed: proto.Bool(false)}
+	value := field("value", 2, descriptor.FieldDescriptorProto_TYPE_STRING, optional, "")
+	value.DefaultValue = proto.String("none")
+
+	common := &descriptor.FileDescriptorProto{
+		Name:    proto.String("common.proto"),
+		Package: proto.String("common"),
+		Syntax:  proto.String("proto2"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Tag"),
+			Field: []*descriptor.FieldDescriptorProto{
+				field("key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, required, ""),
+				value,
+			},
+		}},
+	}
+	reading := &descriptor.FileDescriptorProto{
+		Name:       proto.String("reading.proto"),
+		Package:    proto.String("sensor"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"common.proto"},
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Reading"),
+			Field: []*descriptor.FieldDescriptorProto{
+				field("id", 1, descriptor.FieldDescriptorProto_TYPE_STRING, optional, ""),
+				field("ts", 2, descriptor.FieldDescriptorProto_TYPE_INT64, optional, ""),
+				field("delta", 3, descriptor.FieldDescriptorProto_TYPE_SINT32, optional, ""),
+				field("values", 4, descriptor.FieldDescriptorProto_TYPE_FLOAT, repeated, ""),
+				field("status", 5, descriptor.FieldDescriptorProto_TYPE_ENUM, optional, ".sensor.Reading.Status"),
+				field("location", 6, descriptor.FieldDescriptorProto_TYPE_MESSAGE, optional, ".sensor.Reading.Location"),
+				field("counters", 7, descriptor.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".sensor.Reading.CountersEntry"),
+				field("raw", 8, descriptor.FieldDescriptorProto_TYPE_BYTES, optional, ""),
+				field("tags", 9, descriptor.FieldDescriptorProto_TYPE_MESSAGE, repeated, ".common.Tag"),
+				temperature,
+				e,
+				field("ok", 12, descriptor.FieldDescriptorProto_TYPE_BOOL, optional, ""),
+				field("seq", 13, descriptor.FieldDescriptorProto_TYPE_FIXED32, optional, ""),
+				codes,
+			},
+			NestedType: []*descriptor.DescriptorProto{
+				{
+					Name: proto.String("Location"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("lat", 1, descriptor.FieldDescriptorProto_TYPE_DOUBLE, optional, ""),
+						field("lng", 2, descriptor.FieldDescriptorProto_TYPE_DOUBLE, optional, ""),
+					},
+				},
+				{
+					Name: proto.String("CountersEntry"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, optional, ""),
+						field("value", 2, descriptor.FieldDescriptorProto_TYPE_INT32, optional, ""),
+					},
+					Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+				},
+			},
+			EnumType: []*descriptor.EnumDescriptorProto{{
+				Name: proto.String("Status"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+					{Name: proto.String("OK"), Number: proto.Int32(1)},
+					{Name: proto.String("FAIL"), Number: proto.Int32(2)},
+				},
+			}},
+			OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("reading")}},
+		}},
+	}
+	return []*descriptor.FileDescriptorProto{common, reading}
+}
+
+// writeTestSchema writes the descriptor set of the files as protoc does.
+func writeTestSchema(t *testing.T, dir string, files ...*descriptor.FileDescriptorProto) string {
+	b, err := proto.Marshal(&descriptor.FileDescriptorSet{File: files})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := path.Join(dir, "reading.pb")
+	if err := ioutil.WriteFile(file, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func loadTestConverter(t *testing.T) *protobufConverter {
+	dir, err := ioutil.TempDir("", "protobuf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files, err := protodesc.ReadDescriptorSet(writeTestSchema(t, dir, readingFiles()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := newDescriptorConverter(files, "Reading")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestProtobufConverter_Reading(t *testing.T) {
+	c := loadTestConverter(t)
+
+	// Check the wire format with the manually encoded payload
+	b, err := c.Encode(map[string]interface{}{"id": "a", "delta": float64(-1), "unknown": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []byte{0x0a, 0x01, 'a', 0x18, 0x01}; !bytes.Equal(exp, b) {
+		t.Errorf("encode mismatch:\n  exp=%x\n  got=%x\n", exp, b)
+	}
+
+	var tests = []struct {
+		input  map[string]interface{}
+		result map[string]interface{}
+	}{
+		{
+			input: map[string]interface{}{
+				"id":          "dev1",
+				"ts":          float64(1594200000000),
+				"delta":       float64(-3),
+				"values":      []interface{}{1.5, float64(-2)},
+				"status":      "FAIL",
+				"location":    map[string]interface{}{"lat": 31.2, "lng": 121.5},
+				"counters":    map[string]interface{}{"a": float64(1), "b": float64(-2)},
+				"raw":         "AQI=",
+				"tags":        []interface{}{map[string]interface{}{"key": "k1", "value": "v1"}, map[string]interface{}{"key": "k2"}},
+				"temperature": 20.5,
+				"ok":          true,
+				"seq":         float64(7),
+				"codes":       []interface{}{float64(1), float64(2)},
+			},
+			result: map[string]interface{}{
+				"id":          "dev1",
+				"ts":          float64(1594200000000),
+				"delta":       float64(-3),
+				"values":      []interface{}{1.5, float64(-2)},
+				"status":      "FAIL",
+				"location":    map[string]interface{}{"lat": 31.2, "lng": 121.5},
+				"counters":    map[string]interface{}{"a": float64(1), "b": float64(-2)},
+				"raw":         "AQI=",
+				"tags":        []interface{}{map[string]interface{}{"key": "k1", "value": "v1"}, map[string]interface{}{"key": "k2"}},
+				"temperature": 20.5,
+				"ok":          true,
+				"seq":         float64(7),
+				"codes":       []interface{}{float64(1), float64(2)},
+			},
+		}, {
+			input: map[string]interface{}{
+				"error":  "broken",
+				"status": float64(1),
+			},
+			result: map[string]interface{}{
+				"id":       "",
+				"ts":       float64(0),
+				"delta":    float64(0),
+				"values":   []interface{}{},
+				"status":   "OK",
+				"counters": map[string]interface{}{},
+				"raw":      "",
+				"tags":     []interface{}{},
+				"error":    "broken",
+				"ok":       false,
+				"seq":      float64(0),
+				"codes":    []interface{}{},
+			},
+		},
+	}
+	for i, tt := range tests {
+		b, err := c.Encode(tt.input)
+		if err != nil {
+			t.Errorf("%d. encode error: %v", i, err)
+			continue
+		}
+		result, err := c.Decode(b)
+		if err != nil {
+			t.Errorf("%d. decode error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.result, result) {
+			t.Errorf("%d. result mismatch:\n  exp=%#v\n  got=%#v\n", i, tt.result, result)
+		}
+	}
+
+	if _, err := c.Encode(map[string]interface{}{"status": "NONE"}); err == nil {
+		t.Errorf("expect error for invalid enum value")
+	}
+	if _, err := c.Encode([]interface{}{map[string]interface{}{}}); err == nil {
+		t.Errorf("expect error for a slice of messages")
+	}
+	if _, err := c.Decode([]byte{0x0a, 0x05, 'a'}); err == nil {
+		t.Errorf("expect error for truncated payload")
+	}
+}
+
+func TestProtobufConverter_Generated(t *testing.T) {
+	fd, _ := descriptor.ForMessage(&broker.Message{})
+	c, err := newDescriptorConverter([]*descriptor.FileDescriptorProto{fd}, "broker.Message")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := broker.Message{
+		Channel:   "chan",
+		Publisher: "thing",
+		Protocol:  "mqtt",
+		Payload:   []byte("hello"),
+		Id:        "id",
+		Created:   1577836800000000000,
+		Headers:   map[string]string{"qos": "1"},
+	}
+	payload, err := proto.Marshal(&msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.Decode(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]interface{}{
+		"channel":     "chan",
+		"subtopic":    "",
+		"publisher":   "thing",
+		"protocol":    "mqtt",
+		"contentType": "",
+		"payload":     "aGVsbG8=",
+		"id":          "id",
+		"created":     float64(1577836800000000000),
+		"headers":     map[string]interface{}{"qos": "1"},
+	}
+	if !reflect.DeepEqual(exp, result) {
+		t.Errorf("decode mismatch:\n  exp=%#v\n  got=%#v\n", exp, result)
+	}
+
+	b, err := c.Encode(exp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded broker.Message
+	if err := proto.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(&msg, &decoded) {
+		t.Errorf("encode mismatch:\n  exp=%v\n  got=%v\n", msg, decoded)
+	}
+}
+
+func TestNewDescriptorConverter_Error(t *testing.T) {
+	var tests = []struct {
+		files []*descriptor.FileDescriptorProto
+		name  string
+	}{
+		// The message is not in the package of the compiled file
+		{files: readingFiles(), name: "Tag"},
+		{files: readingFiles(), name: "sensor.Unknown"},
+		// The imported file is not included
+		{files: readingFiles()[1:], name: "Reading"},
+	}
+	for i, tt := range tests {
+		if _, err := newDescriptorConverter(tt.files, tt.name); err == nil {
+			t.Errorf("%d. expect error for message %s", i, tt.name)
+		}
+	}
+}
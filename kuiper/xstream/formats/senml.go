@@ -0,0 +1,97 @@
+package formats
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mainflux/senml"
+)
+
+// senmlConverter converts between a SenML pack in json or cbor and a message
+// whose keys are the resolved record names.
+type senmlConverter struct {
+	format senml.Format
+}
+
+// Decode flattens the records by name. The records are resolved by the base
+// fields and sorted by time, so the latest value wins if a name occurs more
+// than once.
+func (c *senmlConverter) Decode(b []byte) (map[string]interface{}, error) {
+	p, err := senml.Decode(b, c.format)
+	if err != nil {
+		return nil, fmt.Errorf("cannot convert %s into SenML with error %s", c.payload(b), err)
+	}
+	p, err = senml.Normalize(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SenML pack %s: %s", c.payload(b), err)
+	}
+	result := make(map[string]interface{})
+	for _, r := range p.Records {
+		switch {
+		case r.Value != nil:
+			result[r.Name] = *r.Value
+		case r.StringValue != nil:
+			result[r.Name] = *r.StringValue
+		case r.BoolValue != nil:
+			result[r.Name] = *r.BoolValue
+		case r.DataValue != nil:
+			result[r.Name] = *r.DataValue
+		case r.Sum != nil:
+			result[r.Name] = *r.Sum
+		}
+	}
+	return result, nil
+}
+
+// Encode creates a record for each field of the messages. Numbers, strings
+// and bools are set as the value of the record while the other values are
+// set as the string value in json.
+func (c *senmlConverter) Encode(d interface{}) ([]byte, error) {
+	rows, err := toRows(d)
+	if err != nil {
+		return nil, err
+	}
+	var p senml.Pack
+	for _, row := range rows {
+		keys := make([]string, 0, len(row))
+		for k := range row {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			r := senml.Record{Name: k}
+			switch v := normalize(row[k]).(type) {
+			case nil:
+				continue
+			case float64:
+				r.Value = &v
+			case string:
+				r.StringValue = &v
+			case bool:
+				r.BoolValue = &v
+			default:
+				j, err := json.Marshal(v)
+				if err != nil {
+					return nil, fmt.Errorf("fail to encode %s: %s", k, err)
+				}
+				s := string(j)
+				r.StringValue = &s
+			}
+			p.Records = append(p.Records, r)
+		}
+	}
+	if err := senml.Validate(p); err != nil {
+		return nil, fmt.Errorf("invalid SenML pack: %s", err)
+	}
+	return senml.Encode(p, c.format)
+}
+
+// payload prints the payload in the error messages, binary payloads are
+// printed in hex.
+func (c *senmlConverter) payload(b []byte) string {
+	if c.format == senml.CBOR {
+		return fmt.Sprintf("%x", b)
+	}
+	return string(b)
+}
@@ -0,0 +1,63 @@
+// Package memory provides the in process topics used by memory sink to
+// share the rule results with the memory source of other rules.
+package memory
+
+import (
+	"sync"
+)
+
+var (
+	mu     sync.RWMutex
+	topics = make(map[string]map[string]chan map[string]interface{})
+)
+
+// Subscribe registers the subscriber identified by the id to the topic, at
+// most bufferLength rows are buffered for the subscriber.
+func Subscribe(topic, id string, bufferLength int) <-chan map[string]interface{} {
+	mu.Lock()
+	defer mu.Unlock()
+	subs, ok := topics[topic]
+	if !ok {
+		subs = make(map[string]chan map[string]interface{})
+		topics[topic] = subs
+	}
+	ch, ok := subs[id]
+	if !ok {
+		ch = make(chan map[string]interface{}, bufferLength)
+		subs[id] = ch
+	}
+	return ch
+}
+
+// Unsubscribe removes the subscriber identified by the id from the topic.
+func Unsubscribe(topic, id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if subs, ok := topics[topic]; ok {
+		if ch, ok := subs[id]; ok {
+			close(ch)
+			delete(subs, id)
+		}
+		if len(subs) == 0 {
+			delete(topics, topic)
+		}
+	}
+}
+
+// Publish delivers the rows to all subscribers of the topic. Rows are
+// dropped for the subscribers whose buffer is full.
+func Publish(topic string, rows []map[string]interface{}) int {
+	mu.RLock()
+	defer mu.RUnlock()
+	dropped := 0
+	for _, ch := range topics[topic] {
+		for _, r := range rows {
+			select {
+			case ch <- r:
+			default:
+				dropped++
+			}
+		}
+	}
+	return dropped
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPublishSubscribe(t *testing.T) {
+	ch1 := Subscribe("test", "sub1", 1)
+	ch2 := Subscribe("test", "sub2", 2)
+	rows := []map[string]interface{}{{"a": 1}, {"a": 2}}
+
+	if dropped := Publish("test", rows); dropped != 1 {
+		t.Errorf("expect 1 dropped row but got %d", dropped)
+	}
+	if r := <-ch1; !reflect.DeepEqual(rows[0], r) {
+		t.Errorf("sub1 expect %v but got %v", rows[0], r)
+	}
+	for i := range rows {
+		if r := <-ch2; !reflect.DeepEqual(rows[i], r) {
+			t.Errorf("sub2 expect %v but got %v", rows[i], r)
+		}
+	}
+
+	Unsubscribe("test", "sub1")
+	if _, opened := <-ch1; opened {
+		t.Errorf("sub1 channel should be closed")
+	}
+	if dropped := Publish("test", rows[:1]); dropped != 0 {
+		t.Errorf("expect no dropped row but got %d", dropped)
+	}
+	Unsubscribe("test", "sub2")
+	if dropped := Publish("test", rows); dropped != 0 {
+		t.Errorf("expect no dropped row without subscribers but got %d", dropped)
+	}
+}
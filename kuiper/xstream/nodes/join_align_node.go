@@ -0,0 +1,208 @@
+package nodes
+
+import (
+	"encoding/gob"
+	"fmt"
+	"strconv"
+
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+// DEFAULT_TABLE_RETAIN_SIZE limits the rows of a table without KEY option
+// when RETAIN_SIZE option is not specified.
+const DEFAULT_TABLE_RETAIN_SIZE = 1000
+
+const TABLE_ROWS_KEY = "$$tableRows"
+
+func init() {
+	gob.Register(map[string][]*xsql.Tuple{})
+}
+
+// table keeps the latest rows of a lookup table. Rows having the same KEY
+// value are replaced, otherwise the oldest rows are dropped when the size
+// exceeds the retain size. The rows of a reloading source are replaced as a
+// whole once a row of a new snapshot arrives.
+type table struct {
+	name     string
+	key      string
+	retain   int
+	snapshot interface{}
+	rows     []*xsql.Tuple
+}
+
+func newTable(stmt *xsql.StreamStmt) (*table, error) {
+	t := &table{
+		name: string(stmt.Name),
+		key:  stmt.Options["KEY"],
+	}
+	if s, ok := stmt.Options["RETAIN_SIZE"]; ok {
+		r, err := strconv.Atoi(s)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("invalid RETAIN_SIZE %s of table %s", s, stmt.Name)
+		}
+		t.retain = r
+	} else if t.key == "" {
+		t.retain = DEFAULT_TABLE_RETAIN_SIZE
+	}
+	return t, nil
+}
+
+func (t *table) add(tuple *xsql.Tuple) {
+	if s, ok := tuple.Metadata[api.SnapshotMetaKey]; ok && s != t.snapshot {
+		t.snapshot = s
+		t.rows = nil
+	}
+	if t.key != "" {
+		if k, ok := tuple.Message[t.key]; ok {
+			for i, r := range t.rows {
+				if v, ok := r.Message[t.key]; ok && v == k {
+					t.rows[i] = tuple
+					return
+				}
+			}
+		}
+	}
+	t.rows = append(t.rows, tuple)
+	if t.retain > 0 && len(t.rows) > t.retain {
+		t.rows = t.rows[len(t.rows)-t.retain:]
+	}
+}
+
+func (t *table) tuples() []xsql.Tuple {
+	result := make([]xsql.Tuple, len(t.rows))
+	for i, r := range t.rows {
+		result[i] = *r
+	}
+	return result
+}
+
+// JoinAlignNode joins the stream tuples or the window outputs with the rows
+// of the lookup tables. It emits xsql.WindowTuplesSet, so that the join
+// plan works in the same way as joining the streams inside a window.
+type JoinAlignNode struct {
+	*defaultSinkNode
+	tables      map[string]*table
+	statManager StatManager
+}
+
+func NewJoinAlignNode(name string, tables []*xsql.StreamStmt, bufferLength int) (*JoinAlignNode, error) {
+	n := &JoinAlignNode{
+		defaultSinkNode: &defaultSinkNode{
+			input: make(chan interface{}, bufferLength),
+			defaultNode: &defaultNode{
+				outputs:     make(map[string]chan<- interface{}),
+				name:        name,
+				concurrency: 1,
+			},
+		},
+		tables: make(map[string]*table),
+	}
+	for _, stmt := range tables {
+		t, err := newTable(stmt)
+		if err != nil {
+			return nil, err
+		}
+		n.tables[t.name] = t
+	}
+	return n, nil
+}
+
+// Exec is the entry point for the executor
+// input: *xsql.Tuple from preprocessor or xsql.WindowTuplesSet from windowOp
+// output: xsql.WindowTuplesSet
+func (n *JoinAlignNode) Exec(ctx api.StreamContext, errCh chan<- error) {
+	n.ctx = ctx
+	log := ctx.GetLogger()
+	log.Debugf("JoinAlignNode %s is started", n.name)
+
+	if len(n.outputs) <= 0 {
+		go func() { errCh <- fmt.Errorf("no output channel found") }()
+		return
+	}
+	stats, err := NewStatManager("op", ctx)
+	if err != nil {
+		go func() { errCh <- err }()
+		return
+	}
+	n.statManager = stats
+	n.statManagers = []StatManager{stats}
+	if s, err := ctx.GetState(TABLE_ROWS_KEY); err == nil {
+		switch st := s.(type) {
+		case map[string][]*xsql.Tuple:
+			for name, rows := range st {
+				if t, ok := n.tables[name]; ok {
+					t.rows = rows
+				}
+			}
+			log.Infof("Restore table state %+v", st)
+		case nil:
+			log.Debugf("Restore table state, nothing")
+		default:
+			errCh <- fmt.Errorf("restore table state %v error, invalid type", st)
+		}
+	} else {
+		log.Warnf("Restore table state fails: %s", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case item, opened := <-n.input:
+				processed := false
+				if item, processed = n.preprocess(item); processed {
+					break
+				}
+				n.statManager.IncTotalRecordsIn()
+				n.statManager.ProcessTimeStart()
+				if !opened {
+					n.statManager.IncTotalExceptions()
+					break
+				}
+				switch d := item.(type) {
+				case error:
+					n.Broadcast(d)
+					n.statManager.IncTotalExceptions()
+				case *xsql.Tuple:
+					if t, ok := n.tables[d.Emitter]; ok {
+						log.Debugf("JoinAlignNode receive table row %s", d.Message)
+						t.add(d)
+						ctx.PutState(TABLE_ROWS_KEY, n.tableRows())
+						n.statManager.ProcessTimeEnd()
+						n.statManager.SetBufferLength(int64(len(n.input)))
+						continue
+					}
+					log.Debugf("JoinAlignNode receive tuple %s", d.Message)
+					n.alignAndBroadcast(xsql.WindowTuplesSet{{Emitter: d.Emitter, Tuples: []xsql.Tuple{*d}}})
+				case xsql.WindowTuplesSet:
+					log.Debugf("JoinAlignNode receive window tuples %v", d)
+					n.alignAndBroadcast(d)
+				default:
+					n.Broadcast(fmt.Errorf("run JoinAlignNode error: invalid input type %[1]T(%[1]v)", d))
+					n.statManager.IncTotalExceptions()
+				}
+			case <-ctx.Done():
+				log.Infoln("Cancelling join align node....")
+				return
+			}
+		}
+	}()
+}
+
+func (n *JoinAlignNode) alignAndBroadcast(set xsql.WindowTuplesSet) {
+	for _, t := range n.tables {
+		set = append(set, xsql.WindowTuples{Emitter: t.name, Tuples: t.tuples()})
+	}
+	n.statManager.ProcessTimeEnd()
+	n.Broadcast(set)
+	n.statManager.IncTotalRecordsOut()
+	n.statManager.SetBufferLength(int64(len(n.input)))
+}
+
+func (n *JoinAlignNode) tableRows() map[string][]*xsql.Tuple {
+	result := make(map[string][]*xsql.Tuple, len(n.tables))
+	for name, t := range n.tables {
+		result[name] = t.rows
+	}
+	return result
+}
@@ -0,0 +1,242 @@
+package nodes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/xsql"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+	"github.com/cloustone/pandas/kuiper/xstream/states"
+)
+
+func colorRow(color, hex string) *xsql.Tuple {
+	return &xsql.Tuple{
+		Emitter: "colors",
+		Message: map[string]interface{}{
+			"color": color,
+			"hex":   hex,
+		},
+	}
+}
+
+func snapshotRow(color, hex string, snapshot int64) *xsql.Tuple {
+	r := colorRow(color, hex)
+	r.Metadata = xsql.Metadata{api.SnapshotMetaKey: snapshot}
+	return r
+}
+
+func TestTableAdd(t *testing.T) {
+	var tests = []struct {
+		options map[string]string
+		rows    []*xsql.Tuple
+		result  []*xsql.Tuple
+		err     string
+	}{
+		{ //0 KEY replaces the row of the same key
+			options: map[string]string{"KEY": "color"},
+			rows: []*xsql.Tuple{
+				colorRow("red", "#f00"),
+				colorRow("blue", "#00f"),
+				colorRow("red", "#ff0000"),
+			},
+			result: []*xsql.Tuple{
+				colorRow("red", "#ff0000"),
+				colorRow("blue", "#00f"),
+			},
+		},
+		{ //1 RETAIN_SIZE drops the oldest rows
+			options: map[string]string{"RETAIN_SIZE": "2"},
+			rows: []*xsql.Tuple{
+				colorRow("red", "#f00"),
+				colorRow("blue", "#00f"),
+				colorRow("red", "#ff0000"),
+			},
+			result: []*xsql.Tuple{
+				colorRow("blue", "#00f"),
+				colorRow("red", "#ff0000"),
+			},
+		},
+		{ //2 KEY with RETAIN_SIZE
+			options: map[string]string{"KEY": "color", "RETAIN_SIZE": "2"},
+			rows: []*xsql.Tuple{
+				colorRow("red", "#f00"),
+				colorRow("blue", "#00f"),
+				colorRow("red", "#ff0000"),
+				colorRow("green", "#0f0"),
+			},
+			result: []*xsql.Tuple{
+				colorRow("blue", "#00f"),
+				colorRow("green", "#0f0"),
+			},
+		},
+		{ //3 rows without the KEY field are appended
+			options: map[string]string{"KEY": "color"},
+			rows: []*xsql.Tuple{
+				colorRow("red", "#f00"),
+				{Emitter: "colors", Message: map[string]interface{}{"hex": "#fff"}},
+				{Emitter: "colors", Message: map[string]interface{}{"hex": "#fff"}},
+			},
+			result: []*xsql.Tuple{
+				colorRow("red", "#f00"),
+				{Emitter: "colors", Message: map[string]interface{}{"hex": "#fff"}},
+				{Emitter: "colors", Message: map[string]interface{}{"hex": "#fff"}},
+			},
+		},
+		{ //4 a new snapshot replaces all the rows
+			options: map[string]string{"KEY": "color"},
+			rows: []*xsql.Tuple{
+				snapshotRow("red", "#f00", 1),
+				snapshotRow("blue", "#00f", 1),
+				snapshotRow("blue", "#0000ff", 2),
+				snapshotRow("green", "#0f0", 2),
+			},
+			result: []*xsql.Tuple{
+				snapshotRow("blue", "#0000ff", 2),
+				snapshotRow("green", "#0f0", 2),
+			},
+		},
+		{ //5 a snapshot without KEY
+			options: map[string]string{},
+			rows: []*xsql.Tuple{
+				snapshotRow("red", "#f00", 1),
+				snapshotRow("red", "#f00", 2),
+				snapshotRow("blue", "#00f", 2),
+			},
+			result: []*xsql.Tuple{
+				snapshotRow("red", "#f00", 2),
+				snapshotRow("blue", "#00f", 2),
+			},
+		},
+		{ //6
+			options: map[string]string{"RETAIN_SIZE": "0"},
+			err:     "invalid RETAIN_SIZE 0 of table colors",
+		},
+		{ //7
+			options: map[string]string{"RETAIN_SIZE": "abc"},
+			err:     "invalid RETAIN_SIZE abc of table colors",
+		},
+	}
+	for i, tt := range tests {
+		tb, err := newTable(&xsql.StreamStmt{Name: "colors", StreamType: xsql.TypeTable, Options: tt.options})
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%d. error mismatch:\n  exp=%s\n  got=%v\n\n", i, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%d. unexpected error %s", i, err)
+			continue
+		}
+		for _, r := range tt.rows {
+			tb.add(r)
+		}
+		if !reflect.DeepEqual(tt.result, tb.rows) {
+			t.Errorf("%d. rows mismatch:\n  exp=%v\n  got=%v\n\n", i, tt.result, tb.rows)
+		}
+	}
+}
+
+func TestTableDefaultRetainSize(t *testing.T) {
+	tb, _ := newTable(&xsql.StreamStmt{Name: "colors", StreamType: xsql.TypeTable, Options: map[string]string{}})
+	for i := 0; i < DEFAULT_TABLE_RETAIN_SIZE+10; i++ {
+		tb.add(&xsql.Tuple{Emitter: "colors", Message: map[string]interface{}{"id": i}})
+	}
+	if len(tb.rows) != DEFAULT_TABLE_RETAIN_SIZE {
+		t.Errorf("expect %d rows but got %d", DEFAULT_TABLE_RETAIN_SIZE, len(tb.rows))
+	}
+	if id := tb.rows[0].Message["id"]; id != 10 {
+		t.Errorf("expect the oldest row 10 but got %v", id)
+	}
+}
+
+func newJoinAlignNode(t *testing.T) (*JoinAlignNode, chan interface{}) {
+	n, err := NewJoinAlignNode("join_aligner", []*xsql.StreamStmt{
+		{Name: "colors", StreamType: xsql.TypeTable, Options: map[string]string{"KEY": "color"}},
+	}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := make(chan interface{}, 10)
+	n.AddOutput(out, "test")
+	return n, out
+}
+
+func receive(t *testing.T, out chan interface{}) interface{} {
+	select {
+	case r := <-out:
+		return r
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the join aligner output")
+		return nil
+	}
+}
+
+func TestJoinAlignNode(t *testing.T) {
+	rule := "TestJoinAlignNode"
+	store, _ := states.CreateStore(rule, api.AtMostOnce)
+	ctx, cancel := contexts.Background().WithMeta(rule, "join_aligner", store).WithCancel()
+	defer cancel()
+	n, out := newJoinAlignNode(t)
+	n.Exec(ctx, make(chan error))
+
+	n.input <- colorRow("red", "#f00")
+	n.input <- colorRow("blue", "#00f")
+	n.input <- colorRow("red", "#ff0000")
+	demo := &xsql.Tuple{Emitter: "demo", Message: map[string]interface{}{"color": "red", "size": 3}}
+	n.input <- demo
+	exp := xsql.WindowTuplesSet{
+		{Emitter: "demo", Tuples: []xsql.Tuple{*demo}},
+		{Emitter: "colors", Tuples: []xsql.Tuple{*colorRow("red", "#ff0000"), *colorRow("blue", "#00f")}},
+	}
+	if r := receive(t, out); !reflect.DeepEqual(exp, r) {
+		t.Errorf("result mismatch:\n  exp=%v\n  got=%v\n\n", exp, r)
+	}
+
+	window := xsql.WindowTuplesSet{
+		{Emitter: "demo", Tuples: []xsql.Tuple{*demo, *demo}},
+	}
+	n.input <- window
+	exp = xsql.WindowTuplesSet{
+		{Emitter: "demo", Tuples: []xsql.Tuple{*demo, *demo}},
+		{Emitter: "colors", Tuples: []xsql.Tuple{*colorRow("red", "#ff0000"), *colorRow("blue", "#00f")}},
+	}
+	if r := receive(t, out); !reflect.DeepEqual(exp, r) {
+		t.Errorf("result mismatch:\n  exp=%v\n  got=%v\n\n", exp, r)
+	}
+
+	s, _ := ctx.GetState(TABLE_ROWS_KEY)
+	expState := map[string][]*xsql.Tuple{
+		"colors": {colorRow("red", "#ff0000"), colorRow("blue", "#00f")},
+	}
+	if !reflect.DeepEqual(expState, s) {
+		t.Errorf("state mismatch:\n  exp=%v\n  got=%v\n\n", expState, s)
+	}
+}
+
+func TestJoinAlignNodeRestore(t *testing.T) {
+	rule := "TestJoinAlignNodeRestore"
+	store, _ := states.CreateStore(rule, api.AtMostOnce)
+	ctx, cancel := contexts.Background().WithMeta(rule, "join_aligner", store).WithCancel()
+	defer cancel()
+	// The state restored from the checkpoint
+	ctx.PutState(TABLE_ROWS_KEY, map[string][]*xsql.Tuple{
+		"colors":  {colorRow("red", "#f00"), colorRow("blue", "#00f")},
+		"removed": {colorRow("green", "#0f0")},
+	})
+	n, out := newJoinAlignNode(t)
+	n.Exec(ctx, make(chan error))
+
+	n.input <- colorRow("blue", "#0000ff")
+	demo := &xsql.Tuple{Emitter: "demo", Message: map[string]interface{}{"color": "blue", "size": 6}}
+	n.input <- demo
+	exp := xsql.WindowTuplesSet{
+		{Emitter: "demo", Tuples: []xsql.Tuple{*demo}},
+		{Emitter: "colors", Tuples: []xsql.Tuple{*colorRow("red", "#f00"), *colorRow("blue", "#0000ff")}},
+	}
+	if r := receive(t, out); !reflect.DeepEqual(exp, r) {
+		t.Errorf("result mismatch:\n  exp=%v\n  got=%v\n\n", exp, r)
+	}
+}
@@ -11,9 +11,11 @@ const ExceptionsTotal = "exceptions_total"
 const ProcessLatencyMs = "process_latency_ms"
 const LastInvocation = "last_invocation"
 const BufferLength = "buffer_length"
+const LateRecordsTotal = "late_records_total"
+const LateDroppedTotal = "late_dropped_total"
 
 var (
-	MetricNames        = []string{RecordsInTotal, RecordsOutTotal, ExceptionsTotal, ProcessLatencyMs, BufferLength, LastInvocation}
+	MetricNames        = []string{RecordsInTotal, RecordsOutTotal, ExceptionsTotal, ProcessLatencyMs, BufferLength, LastInvocation, LateRecordsTotal, LateDroppedTotal}
 	prometheuseMetrics *PrometheusMetrics
 	mutex              sync.RWMutex
 )
@@ -33,6 +35,8 @@ type MetricGroup struct {
 	TotalExceptions *prometheus.CounterVec
 	ProcessLatency  *prometheus.GaugeVec
 	BufferLength    *prometheus.GaugeVec
+	TotalLate       *prometheus.CounterVec
+	TotalDropped    *prometheus.CounterVec
 }
 
 type PrometheusMetrics struct {
@@ -67,13 +71,23 @@ func newPrometheusMetrics() *PrometheusMetrics {
 			Name: prefix + "_" + BufferLength,
 			Help: "The length of the plan buffer which is shared by all instances of " + prefix,
 		}, labelNames)
-		prometheus.MustRegister(totalRecordsIn, totalRecordsOut, totalExceptions, processLatency, bufferLength)
+		totalLate := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + "_" + LateRecordsTotal,
+			Help: "Total number of late messages which update the results of " + prefix,
+		}, labelNames)
+		totalDropped := prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: prefix + "_" + LateDroppedTotal,
+			Help: "Total number of messages dropped for arriving too late of " + prefix,
+		}, labelNames)
+		prometheus.MustRegister(totalRecordsIn, totalRecordsOut, totalExceptions, processLatency, bufferLength, totalLate, totalDropped)
 		vecs = append(vecs, &MetricGroup{
 			TotalRecordsIn:  totalRecordsIn,
 			TotalRecordsOut: totalRecordsOut,
 			TotalExceptions: totalExceptions,
 			ProcessLatency:  processLatency,
 			BufferLength:    bufferLength,
+			TotalLate:       totalLate,
+			TotalDropped:    totalDropped,
 		})
 	}
 	return &PrometheusMetrics{vecs: vecs}
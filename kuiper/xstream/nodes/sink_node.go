@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"text/template"
 	"time"
@@ -12,6 +13,7 @@ import (
 	"github.com/cloustone/pandas/kuiper/templates"
 	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/formats"
 	"github.com/cloustone/pandas/kuiper/xstream/sinks"
 )
 
@@ -136,12 +138,7 @@ func (m *SinkNode) Open(ctx api.StreamContext, result chan<- error) {
 			if t, ok := c.(string); !ok {
 				logger.Warnf("invalid type for dateTemplate property, should be a string value.", c)
 			} else {
-				funcMap := template.FuncMap{
-					"json":   templates.JsonMarshal,
-					"base64": templates.Base64Encode,
-					"add":    templates.Add,
-				}
-				temp, err := template.New("sink").Funcs(funcMap).Parse(t)
+				temp, err := template.New("sink").Funcs(templates.FuncMap()).Parse(t)
 				if err != nil {
 					msg := fmt.Sprintf("property dataTemplate %v is invalid: %v", t, err)
 					result <- fmt.Errorf(msg)
@@ -152,6 +149,27 @@ func (m *SinkNode) Open(ctx api.StreamContext, result chan<- error) {
 				}
 			}
 		}
+		// The outputs are json by default, other formats are converted from json
+		var converter formats.Converter
+		if c, ok := m.options["format"]; ok {
+			f, ok := c.(string)
+			if !ok {
+				logger.Warnf("invalid type for format property, should be a string value.", c)
+			} else if f = strings.ToLower(f); f != formats.JSON {
+				var schemaId string
+				if s, ok := m.options["schemaId"]; ok {
+					schemaId, _ = s.(string)
+				}
+				cv, err := formats.GetConverter(f, schemaId)
+				if err != nil {
+					msg := fmt.Sprintf("property format %v is invalid: %v", f, err)
+					result <- fmt.Errorf(msg)
+					logger.Warnf(msg)
+					return
+				}
+				converter = cv
+			}
+		}
 
 		m.reset()
 		logger.Infof("open sink node %d instances", m.concurrency)
@@ -204,9 +222,9 @@ func (m *SinkNode) Open(ctx api.StreamContext, result chan<- error) {
 						}
 						stats.SetBufferLength(int64(cache.Length()))
 						if runAsync {
-							go doCollect(sink, data, stats, retryInterval, omitIfEmpty, sendSingle, tp, cache.Complete, ctx)
+							go doCollect(sink, data, stats, retryInterval, omitIfEmpty, sendSingle, tp, converter, cache.Complete, ctx)
 						} else {
-							doCollect(sink, data, stats, retryInterval, omitIfEmpty, sendSingle, tp, cache.Complete, ctx)
+							doCollect(sink, data, stats, retryInterval, omitIfEmpty, sendSingle, tp, converter, cache.Complete, ctx)
 						}
 					case <-ctx.Done():
 						logger.Infof("sink node %s instance %d done", m.name, instance)
@@ -236,7 +254,7 @@ func extractInput(v []byte) ([]map[string]interface{}, error) {
 	return j, nil
 }
 
-func doCollect(sink api.Sink, item *CacheTuple, stats StatManager, retryInterval int, omitIfEmpty bool, sendSingle bool, tp *template.Template, signalCh chan<- int, ctx api.StreamContext) {
+func doCollect(sink api.Sink, item *CacheTuple, stats StatManager, retryInterval int, omitIfEmpty bool, sendSingle bool, tp *template.Template, converter formats.Converter, signalCh chan<- int, ctx api.StreamContext) {
 	stats.IncTotalRecordsIn()
 	stats.ProcessTimeStart()
 	defer stats.ProcessTimeEnd()
@@ -295,6 +313,21 @@ func doCollect(sink api.Sink, item *CacheTuple, stats StatManager, retryInterval
 				}
 			}
 		}
+		if converter != nil {
+			for i, outdata := range outdatas {
+				var d interface{}
+				if err := json.Unmarshal(outdata, &d); err != nil {
+					logger.Warnf("sink node %s instance %d publish %s decode json error: %v", ctx.GetOpId(), ctx.GetInstanceId(), outdata, err)
+					stats.IncTotalExceptions()
+					return
+				}
+				if outdatas[i], err = converter.Encode(d); err != nil {
+					logger.Warnf("sink node %s instance %d publish %s encode error: %v", ctx.GetOpId(), ctx.GetInstanceId(), outdata, err)
+					stats.IncTotalExceptions()
+					return
+				}
+			}
+		}
 
 	case error:
 		outdatas = [][]byte{[]byte(fmt.Sprintf(`[{"error":"%s"}]`, val.Error()))}
@@ -346,6 +379,14 @@ func doGetSink(name string, action map[string]interface{}) (api.Sink, error) {
 		s = &sinks.RestSink{}
 	case "nop":
 		s = &sinks.NopSink{}
+	case "mainflux":
+		s = &sinks.MainfluxSink{}
+	case "memory":
+		s = &sinks.MemorySink{}
+	case "postgres":
+		s = &sinks.PostgresSink{}
+	case "alerts":
+		s = &sinks.AlertsSink{}
 	default:
 		s, err = plugins.GetSink(name)
 		if err != nil {
@@ -2,7 +2,7 @@ package nodes
 
 import (
 	"fmt"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/contexts"
 	"github.com/cloustone/pandas/kuiper/xstream/test"
 	"reflect"
@@ -1,6 +1,8 @@
 package nodes
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/cloustone/pandas/kuiper/plugins"
@@ -19,6 +21,8 @@ type SourceNode struct {
 
 	mutex   sync.RWMutex
 	sources []api.Source
+	// The keys of the shared sources subscribed, indexed by the subscriber id
+	subscriptions map[string]string
 }
 
 func NewSourceNode(name string, options map[string]string) *SourceNode {
@@ -81,7 +85,26 @@ func (m *SourceNode) Open(ctx api.StreamContext, errCh chan<- error) {
 				//Do open source instances
 				var source api.Source
 				var err error
-				if !m.isMock {
+				stats, err := NewStatManager("source", ctx)
+				if err != nil {
+					m.drainError(errCh, err, ctx, logger)
+					return
+				}
+				m.mutex.Lock()
+				m.statManagers = append(m.statManagers, stats)
+				m.mutex.Unlock()
+
+				buffer := NewDynamicChannelBuffer()
+				buffer.SetLimit(bl)
+				sourceErrCh := make(chan error)
+				var sub *sourceSubscriber
+				if !m.isMock && isSharedSource(m.sourceType, props) {
+					sub, err = m.subscribe(ctx, instance, props, buffer, sourceErrCh)
+					if err != nil {
+						m.drainError(errCh, err, ctx, logger)
+						return
+					}
+				} else if !m.isMock {
 					source, err = getSource(m.sourceType)
 					if err != nil {
 						m.drainError(errCh, err, ctx, logger)
@@ -99,15 +122,6 @@ func (m *SourceNode) Open(ctx api.StreamContext, errCh chan<- error) {
 					logger.Debugf("get source instance %d from %d sources", instance, len(m.sources))
 					source = m.sources[instance]
 				}
-				stats, err := NewStatManager("source", ctx)
-				if err != nil {
-					m.drainError(errCh, err, ctx, logger)
-					return
-				}
-				m.mutex.Lock()
-				m.statManagers = append(m.statManagers, stats)
-				m.mutex.Unlock()
-
 				if rw, ok := source.(api.Rewindable); ok {
 					if offset, err := ctx.GetState(OFFSET_KEY); err != nil {
 						m.drainError(errCh, err, ctx, logger)
@@ -120,10 +134,9 @@ func (m *SourceNode) Open(ctx api.StreamContext, errCh chan<- error) {
 					}
 				}
 
-				buffer := NewDynamicChannelBuffer()
-				buffer.SetLimit(bl)
-				sourceErrCh := make(chan error)
-				go source.Open(ctx.WithInstance(instance), buffer.In, sourceErrCh)
+				if source != nil {
+					go source.Open(ctx.WithInstance(instance), buffer.In, sourceErrCh)
+				}
 				logger.Infof("Start source %s instance %d successfully", m.name, instance)
 				for {
 					select {
@@ -135,9 +148,19 @@ func (m *SourceNode) Open(ctx api.StreamContext, errCh chan<- error) {
 						m.drainError(errCh, err, ctx, logger)
 						return
 					case data := <-buffer.Out:
+						// The shared source drops the tuples when the buffer is full
+						if sub != nil {
+							for n := sub.takeDropped(); n > 0; n-- {
+								stats.IncTotalExceptions()
+							}
+						}
 						stats.IncTotalRecordsIn()
 						stats.ProcessTimeStart()
-						tuple := &xsql.Tuple{Emitter: m.name, Message: data.Message(), Timestamp: util.GetNowInMilli(), Metadata: data.Meta()}
+						ts := util.GetNowInMilli()
+						if t, ok := data.(api.TimestampedSourceTuple); ok && t.Timestamp() > 0 {
+							ts = t.Timestamp()
+						}
+						tuple := &xsql.Tuple{Emitter: m.name, Message: data.Message(), Timestamp: ts, Metadata: data.Meta()}
 						stats.ProcessTimeEnd()
 						logger.Debugf("source node %s is sending tuple %+v of timestamp %d", m.name, tuple, tuple.Timestamp)
 						//blocking
@@ -167,9 +190,33 @@ func (m *SourceNode) reset() {
 	if !m.isMock {
 		m.sources = nil
 	}
+	m.subscriptions = make(map[string]string)
 	m.statManagers = nil
 }
 
+// subscribe reads the tuples from the source instance shared with the other
+// rules instead of opening a new one.
+func (m *SourceNode) subscribe(ctx api.StreamContext, instance int, props map[string]interface{}, buffer *DynamicChannelBuffer, errCh chan<- error) (*sourceSubscriber, error) {
+	key, err := sourceKey(m.sourceType, m.options["DATASOURCE"], props, instance)
+	if err != nil {
+		return nil, err
+	}
+	id := fmt.Sprintf("%s_%s_%d", ctx.GetRuleId(), m.name, instance)
+	sub := &sourceSubscriber{
+		ctx:    ctx,
+		buffer: buffer,
+		errCh:  errCh,
+	}
+	err = pool.subscribe(key, id, m.sourceType, m.options["DATASOURCE"], props, instance, sub)
+	if err != nil {
+		return nil, err
+	}
+	m.mutex.Lock()
+	m.subscriptions[id] = key
+	m.mutex.Unlock()
+	return sub, nil
+}
+
 func doGetSource(t string) (api.Source, error) {
 	var (
 		s   api.Source
@@ -180,6 +227,14 @@ func doGetSource(t string) (api.Source, error) {
 		s = &extensions.MQTTSource{}
 	case "httppull":
 		s = &extensions.HTTPPullSource{}
+	case "mainflux":
+		s = &extensions.MainfluxSource{}
+	case "file":
+		s = &extensions.FileSource{}
+	case "memory":
+		s = &extensions.MemorySource{}
+	case "things":
+		s = &extensions.ThingsSource{}
 	default:
 		s, err = plugins.GetSource(t)
 		if err != nil {
@@ -204,6 +259,12 @@ func (m *SourceNode) close(ctx api.StreamContext, logger api.Logger) {
 			logger.Warnf("close source fails: %v", err)
 		}
 	}
+	m.mutex.Lock()
+	for id, key := range m.subscriptions {
+		pool.unsubscribe(key, id)
+		delete(m.subscriptions, id)
+	}
+	m.mutex.Unlock()
 }
 
 func (m *SourceNode) getConf(ctx api.StreamContext) map[string]interface{} {
@@ -240,6 +301,13 @@ func (m *SourceNode) getConf(ctx api.StreamContext) map[string]interface{} {
 	} else {
 		logger.Warnf("config file %s.yaml is not loaded properly. Return an empty configuration", m.sourceType)
 	}
+	// The payload format is defined by the stream
+	if f, ok := m.options["FORMAT"]; ok {
+		props["format"] = strings.ToLower(f)
+	}
+	if s, ok := m.options["SCHEMAID"]; ok {
+		props["schemaId"] = s
+	}
 	logger.Debugf("get conf for %s with conf key %s: %v", m.sourceType, confkey, props)
 	return props
 }
@@ -1,7 +1,7 @@
 package nodes
 
 import (
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/contexts"
 	"reflect"
 	"testing"
@@ -0,0 +1,229 @@
+package nodes
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+	"github.com/cloustone/pandas/kuiper/xstream/states"
+)
+
+// The source types which subscribe continuously are shared by default. The
+// other sources, such as the file source which loads the data once when
+// opening, are opened by each rule. It can be overridden by the shared
+// property of the source configuration.
+var sharedSourceTypes = map[string]bool{
+	"mqtt":     true,
+	"edgex":    true,
+	"httppull": true,
+	"mainflux": true,
+	"memory":   true,
+}
+
+func isSharedSource(sourceType string, props map[string]interface{}) bool {
+	if s, ok := props["shared"].(bool); ok {
+		return s
+	}
+	return sharedSourceTypes[sourceType]
+}
+
+// sourcePool keeps the sources shared by the rules. The source instances of
+// the same type, data source and configuration are opened once and the
+// decoded tuples are sent to the buffer of each subscribed rule. The tuples
+// are dropped for a rule whose buffer is full, so a slow rule never blocks
+// the others. The source instance is closed when the last subscriber quits.
+type sourcePool struct {
+	registry map[string]*sourceSingleton
+	seq      int
+	sync.Mutex
+}
+
+var pool = &sourcePool{
+	registry: make(map[string]*sourceSingleton),
+}
+
+type sourceSingleton struct {
+	key         string
+	source      api.Source
+	ctx         api.StreamContext
+	cancel      func()
+	subscribers map[string]*sourceSubscriber
+	sync.RWMutex
+}
+
+// The tuples waiting to enter the buffer of a subscriber, the shared source
+// drops the tuples of the subscriber once they are full.
+const subscriberQueueLength = 1024
+
+type sourceSubscriber struct {
+	ctx    api.StreamContext
+	buffer *DynamicChannelBuffer
+	errCh  chan<- error
+	queue  chan api.SourceTuple
+	quit   chan struct{}
+	// The number of dropped tuples which are not counted by the source node
+	dropped int64
+}
+
+// forward moves the queued tuples to the buffer of the subscriber, which
+// blocks when the buffer is full.
+func (sub *sourceSubscriber) forward() {
+	for {
+		select {
+		case <-sub.ctx.Done():
+			return
+		case <-sub.quit:
+			return
+		case data := <-sub.queue:
+			select {
+			case sub.buffer.In <- data:
+			case <-sub.ctx.Done():
+				return
+			case <-sub.quit:
+				return
+			}
+		}
+	}
+}
+
+// send queues the tuple without blocking, the tuple is dropped for the
+// subscriber if its queue is full.
+func (sub *sourceSubscriber) send(data api.SourceTuple) {
+	select {
+	case sub.queue <- data:
+	default:
+		atomic.AddInt64(&sub.dropped, 1)
+		sub.ctx.GetLogger().Debugf("drop tuple of shared source as the buffer is full")
+	}
+}
+
+// takeDropped returns the number of tuples dropped since the last call.
+func (sub *sourceSubscriber) takeDropped() int64 {
+	return atomic.SwapInt64(&sub.dropped, 0)
+}
+
+func sourceKey(sourceType string, datasource string, props map[string]interface{}, instance int) (string, error) {
+	p, err := json.Marshal(props)
+	if err != nil {
+		return "", fmt.Errorf("invalid source properties %v: %s", props, err)
+	}
+	return fmt.Sprintf("%s|%s|%s|%d", sourceType, datasource, p, instance), nil
+}
+
+// subscribe adds the subscriber to the source instance of the key. The source
+// instance is created and opened if no rule subscribes to it yet.
+func (p *sourcePool) subscribe(key string, id string, sourceType string, datasource string, props map[string]interface{}, instance int, sub *sourceSubscriber) error {
+	p.Lock()
+	defer p.Unlock()
+	s, ok := p.registry[key]
+	if !ok {
+		source, err := getSource(sourceType)
+		if err != nil {
+			return err
+		}
+		if err := source.Configure(datasource, props); err != nil {
+			return err
+		}
+		p.seq++
+		opId := fmt.Sprintf("shared_%s_%d", sourceType, p.seq)
+		store, err := states.CreateStore(opId, api.AtMostOnce)
+		if err != nil {
+			return err
+		}
+		logger := util.Log.WithField("source", opId)
+		ctx, cancel := contexts.WithValue(contexts.Background(), contexts.LoggerKey, logger).WithMeta("", opId, store).WithCancel()
+		s = &sourceSingleton{
+			key:         key,
+			source:      source,
+			ctx:         ctx,
+			cancel:      cancel,
+			subscribers: make(map[string]*sourceSubscriber),
+		}
+		p.registry[key] = s
+		go s.run(ctx.WithInstance(instance))
+		logger.Infof("open shared source %s", key)
+	}
+	sub.queue = make(chan api.SourceTuple, subscriberQueueLength)
+	sub.quit = make(chan struct{})
+	go sub.forward()
+	s.Lock()
+	s.subscribers[id] = sub
+	s.Unlock()
+	sub.ctx.GetLogger().Infof("subscribe to shared source %s with %d subscribers", key, len(s.subscribers))
+	return nil
+}
+
+// unsubscribe removes the subscriber and closes the source instance if no one
+// subscribes to it any more.
+func (p *sourcePool) unsubscribe(key string, id string) {
+	p.Lock()
+	defer p.Unlock()
+	s, ok := p.registry[key]
+	if !ok {
+		return
+	}
+	s.Lock()
+	if sub, ok := s.subscribers[id]; ok {
+		close(sub.quit)
+		delete(s.subscribers, id)
+	}
+	l := len(s.subscribers)
+	s.Unlock()
+	if l == 0 {
+		delete(p.registry, key)
+		s.close()
+	}
+}
+
+// remove deletes the failed source instance so that the next subscriber opens
+// a new one.
+func (p *sourcePool) remove(s *sourceSingleton) {
+	p.Lock()
+	defer p.Unlock()
+	if r, ok := p.registry[s.key]; ok && r == s {
+		delete(p.registry, s.key)
+	}
+	s.close()
+}
+
+func (s *sourceSingleton) run(ctx api.StreamContext) {
+	consumer := make(chan api.SourceTuple)
+	errCh := make(chan error)
+	go s.source.Open(ctx, consumer, errCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-errCh:
+			ctx.GetLogger().Errorf("shared source %s error: %s", s.key, err)
+			s.RLock()
+			for _, sub := range s.subscribers {
+				select {
+				case sub.errCh <- err:
+				case <-sub.ctx.Done():
+				}
+			}
+			s.RUnlock()
+			pool.remove(s)
+			return
+		case data := <-consumer:
+			s.RLock()
+			for _, sub := range s.subscribers {
+				sub.send(data)
+			}
+			s.RUnlock()
+		}
+	}
+}
+
+func (s *sourceSingleton) close() {
+	s.cancel()
+	if err := s.source.Close(s.ctx); err != nil {
+		s.ctx.GetLogger().Warnf("close shared source %s fails: %v", s.key, err)
+	}
+	s.ctx.GetLogger().Infof("shared source %s is closed", s.key)
+}
@@ -0,0 +1,148 @@
+package nodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+	"github.com/cloustone/pandas/kuiper/xstream/memory"
+	"github.com/cloustone/pandas/kuiper/xstream/states"
+)
+
+func TestSourcePool(t *testing.T) {
+	props := map[string]interface{}{"bufferLength": 10}
+	key, err := sourceKey("memory", "pool", props, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffers []*DynamicChannelBuffer
+	var cancels []func()
+	for _, rule := range []string{"rule1", "rule2"} {
+		store, _ := states.CreateStore(rule, api.AtMostOnce)
+		ctx, cancel := contexts.Background().WithMeta(rule, "demo", store).WithCancel()
+		buffer := NewDynamicChannelBuffer()
+		err := pool.subscribe(key, rule, "memory", "pool", props, 0, &sourceSubscriber{ctx: ctx, buffer: buffer, errCh: make(chan error)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		buffers = append(buffers, buffer)
+		cancels = append(cancels, cancel)
+	}
+	if l := len(pool.registry); l != 1 {
+		t.Errorf("expect 1 shared source but got %d", l)
+	}
+
+	// Publish until the shared source subscribes to the topic
+	received := make([]bool, len(buffers))
+	timeout := time.After(time.Second)
+	for !received[0] || !received[1] {
+		memory.Publish("pool", []map[string]interface{}{{"a": 1}})
+		for i, b := range buffers {
+			select {
+			case d := <-b.Out:
+				if d.Message()["a"] != 1 {
+					t.Errorf("%d. unexpected message %v", i, d.Message())
+				}
+				received[i] = true
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("timeout, received %v", received)
+		default:
+		}
+	}
+
+	for i, rule := range []string{"rule1", "rule2"} {
+		cancels[i]()
+		pool.unsubscribe(key, rule)
+	}
+	if l := len(pool.registry); l != 0 {
+		t.Errorf("expect no shared source but got %d", l)
+	}
+}
+
+func TestSourcePoolSlowSubscriber(t *testing.T) {
+	props := map[string]interface{}{"bufferLength": 1}
+	key, err := sourceKey("memory", "slow", props, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subs []*sourceSubscriber
+	var cancels []func()
+	for _, rule := range []string{"stuck", "fast"} {
+		store, _ := states.CreateStore(rule, api.AtMostOnce)
+		ctx, cancel := contexts.Background().WithMeta(rule, "demo", store).WithCancel()
+		buffer := NewDynamicChannelBuffer()
+		buffer.SetLimit(1)
+		sub := &sourceSubscriber{ctx: ctx, buffer: buffer, errCh: make(chan error)}
+		if err := pool.subscribe(key, rule, "memory", "slow", props, 0, sub); err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, sub)
+		cancels = append(cancels, cancel)
+	}
+	stuck, fast := subs[0], subs[1]
+
+	// Publish until the shared source subscribes to the topic
+	timeout := time.After(time.Second)
+	for received := false; !received; {
+		memory.Publish("slow", []map[string]interface{}{{"a": -1}})
+		select {
+		case <-fast.buffer.Out:
+			received = true
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			t.Fatal("timeout waiting for the shared source")
+		}
+	}
+
+	// The stuck subscriber never reads its buffer, the other one receives all
+	// the tuples anyway
+	n := 2*subscriberQueueLength + 10
+	for i := 0; i < n; i++ {
+		memory.Publish("slow", []map[string]interface{}{{"a": i}})
+	loop:
+		for {
+			select {
+			case d := <-fast.buffer.Out:
+				if d.Message()["a"] == i {
+					break loop
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for tuple %d", i)
+			}
+		}
+	}
+	if d := stuck.takeDropped(); d == 0 {
+		t.Errorf("expect dropped tuples of the stuck subscriber")
+	}
+
+	for i, rule := range []string{"stuck", "fast"} {
+		cancels[i]()
+		pool.unsubscribe(key, rule)
+	}
+}
+
+func TestIsSharedSource(t *testing.T) {
+	var tests = []struct {
+		sourceType string
+		props      map[string]interface{}
+		shared     bool
+	}{
+		{"mqtt", map[string]interface{}{}, true},
+		{"mqtt", map[string]interface{}{"shared": false}, false},
+		{"file", map[string]interface{}{}, false},
+		{"custom", map[string]interface{}{"shared": true}, true},
+	}
+	for i, tt := range tests {
+		if r := isSharedSource(tt.sourceType, tt.props); r != tt.shared {
+			t.Errorf("%d. expect %v but got %v", i, tt.shared, r)
+		}
+	}
+	if _, err := sourceKey("mqtt", "demo", map[string]interface{}{"f": func() {}}, 0); err == nil {
+		t.Errorf("expect error for invalid properties")
+	}
+}
@@ -14,6 +14,8 @@ type StatManager interface {
 	IncTotalRecordsIn()
 	IncTotalRecordsOut()
 	IncTotalExceptions()
+	IncTotalLateRecords()
+	IncTotalLateDropped()
 	ProcessTimeStart()
 	ProcessTimeEnd()
 	SetBufferLength(l int64)
@@ -29,6 +31,8 @@ type DefaultStatManager struct {
 	processLatency  int64
 	lastInvocation  time.Time
 	bufferLength    int64
+	totalLate       int64
+	totalDropped    int64
 	//configs
 	opType           string //"source", "op", "sink"
 	prefix           string
@@ -45,6 +49,8 @@ type PrometheusStatManager struct {
 	pTotalExceptions prometheus.Counter
 	pProcessLatency  prometheus.Gauge
 	pBufferLength    prometheus.Gauge
+	pTotalLate       prometheus.Counter
+	pTotalDropped    prometheus.Counter
 }
 
 func NewStatManager(opType string, ctx api.StreamContext) (StatManager, error) {
@@ -79,6 +85,8 @@ func NewStatManager(opType string, ctx api.StreamContext) (StatManager, error) {
 		psm.pTotalExceptions = mg.TotalExceptions.WithLabelValues(ctx.GetRuleId(), opType, ctx.GetOpId(), strInId)
 		psm.pProcessLatency = mg.ProcessLatency.WithLabelValues(ctx.GetRuleId(), opType, ctx.GetOpId(), strInId)
 		psm.pBufferLength = mg.BufferLength.WithLabelValues(ctx.GetRuleId(), opType, ctx.GetOpId(), strInId)
+		psm.pTotalLate = mg.TotalLate.WithLabelValues(ctx.GetRuleId(), opType, ctx.GetOpId(), strInId)
+		psm.pTotalDropped = mg.TotalDropped.WithLabelValues(ctx.GetRuleId(), opType, ctx.GetOpId(), strInId)
 		sm = psm
 	} else {
 		sm = &DefaultStatManager{
@@ -105,6 +113,14 @@ func (sm *DefaultStatManager) IncTotalExceptions() {
 	sm.processTimeStart = t
 }
 
+func (sm *DefaultStatManager) IncTotalLateRecords() {
+	sm.totalLate++
+}
+
+func (sm *DefaultStatManager) IncTotalLateDropped() {
+	sm.totalDropped++
+}
+
 func (sm *DefaultStatManager) ProcessTimeStart() {
 	sm.lastInvocation = time.Now()
 	sm.processTimeStart = sm.lastInvocation
@@ -137,6 +153,16 @@ func (sm *PrometheusStatManager) IncTotalExceptions() {
 	sm.processTimeStart = t
 }
 
+func (sm *PrometheusStatManager) IncTotalLateRecords() {
+	sm.totalLate++
+	sm.pTotalLate.Inc()
+}
+
+func (sm *PrometheusStatManager) IncTotalLateDropped() {
+	sm.totalDropped++
+	sm.pTotalDropped.Inc()
+}
+
 func (sm *PrometheusStatManager) ProcessTimeEnd() {
 	if !sm.processTimeStart.IsZero() {
 		sm.processLatency = int64(time.Since(sm.processTimeStart) / time.Millisecond)
@@ -159,6 +185,7 @@ func (sm *DefaultStatManager) GetMetrics() []interface{} {
 	} else {
 		result = append(result, 0)
 	}
+	result = append(result, sm.totalLate, sm.totalDropped)
 
 	return result
 }
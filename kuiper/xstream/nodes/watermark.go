@@ -2,6 +2,7 @@ package nodes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/cloustone/pandas/kuiper/xsql"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
@@ -210,6 +211,10 @@ func (o *WindowOperator) execEventWindow(ctx api.StreamContext, inputs []*xsql.T
 					}
 					nextWindowEndTs = windowEndTs
 					log.Debugf("next window end %d", nextWindowEndTs)
+					if o.allowedLateness > 0 {
+						o.pruneHistory(watermarkTs)
+						ctx.PutState(WINDOW_HISTORY_KEY, o.history)
+					}
 				} else {
 					o.statManager.IncTotalRecordsIn()
 					tuple, ok := d.(*xsql.Tuple)
@@ -219,6 +224,12 @@ func (o *WindowOperator) execEventWindow(ctx api.StreamContext, inputs []*xsql.T
 					log.Debugf("event window receive tuple %s", tuple.Message)
 					if o.watermarkGenerator.track(tuple.Emitter, d.GetTimestamp(), ctx) {
 						inputs = append(inputs, tuple)
+						if o.allowedLateness > 0 {
+							o.history = append(o.history, tuple)
+							ctx.PutState(WINDOW_HISTORY_KEY, o.history)
+						}
+					} else {
+						inputs = o.processLate(inputs, tuple, ctx)
 					}
 				}
 				o.statManager.ProcessTimeEnd()
@@ -239,6 +250,117 @@ func (o *WindowOperator) execEventWindow(ctx api.StreamContext, inputs []*xsql.T
 	}
 }
 
+// processLate handles the event behind the watermark. Within the allowed
+// lateness, the event is added to the windows which are not triggered yet and
+// the triggered windows are triggered again with the updated results.
+// Otherwise, the event is dropped and sent to the side output.
+func (o *WindowOperator) processLate(inputs []*xsql.Tuple, tuple *xsql.Tuple, ctx api.StreamContext) []*xsql.Tuple {
+	log := ctx.GetLogger()
+	watermark := o.watermarkGenerator.lastWatermarkTs
+	if o.allowedLateness > 0 {
+		var (
+			pending bool
+			ends    []int64
+		)
+		for _, end := range o.windowEnds(tuple.Timestamp) {
+			if end > watermark {
+				pending = true
+			} else if end+o.allowedLateness > watermark {
+				ends = append(ends, end)
+			}
+		}
+		if pending || len(ends) > 0 {
+			log.Debugf("late event at %d updates %d triggered windows with watermark %d", tuple.Timestamp, len(ends), watermark)
+			o.statManager.IncTotalLateRecords()
+			o.history = append(o.history, tuple)
+			ctx.PutState(WINDOW_HISTORY_KEY, o.history)
+			if pending {
+				inputs = append(inputs, tuple)
+			}
+			for _, end := range ends {
+				o.fire(end, ctx)
+			}
+			return inputs
+		}
+	}
+
+	log.Debugf("drop late event at %d with watermark %d", tuple.Timestamp, watermark)
+	o.statManager.IncTotalLateDropped()
+	if len(o.late.outputs) > 0 {
+		if r, err := json.Marshal([]map[string]interface{}{tuple.Message}); err != nil {
+			log.Warnf("fail to encode late event %v: %s", tuple.Message, err)
+		} else {
+			o.late.Broadcast(r)
+		}
+	}
+	return inputs
+}
+
+// windowEnds returns the ends of all the windows containing the timestamp
+// in ascending order. Only tumbling and hopping windows are supported.
+func (o *WindowOperator) windowEnds(ts int64) []int64 {
+	length := int64(o.window.Length)
+	switch o.window.Type {
+	case xsql.TUMBLING_WINDOW:
+		if ts%length == 0 {
+			return []int64{ts}
+		}
+		return []int64{ts + length - ts%length}
+	case xsql.HOPPING_WINDOW:
+		interval := int64(o.window.Interval)
+		end := ts
+		if ts%interval != 0 {
+			end = ts + interval - ts%interval
+		}
+		var ends []int64
+		for ; end <= ts+length; end += interval {
+			ends = append(ends, end)
+		}
+		return ends
+	default:
+		return nil
+	}
+}
+
+// inWindow checks if the timestamp is in the window ending at end, in the
+// same way as scan
+func (o *WindowOperator) inWindow(ts int64, end int64) bool {
+	start := end - int64(o.window.Length)
+	if o.window.Type == xsql.HOPPING_WINDOW {
+		return ts >= start && ts <= end
+	}
+	return ts > start && ts <= end
+}
+
+// fire triggers the window ending at end again with the kept events
+func (o *WindowOperator) fire(end int64, ctx api.StreamContext) {
+	var results xsql.WindowTuplesSet = make([]xsql.WindowTuples, 0)
+	for _, t := range o.history {
+		if o.inWindow(t.Timestamp, end) {
+			results = results.AddTuple(t)
+		}
+	}
+	if len(results) > 0 {
+		results.Sort()
+		ctx.GetLogger().Debugf("window %s triggered again at %d", o.name, end)
+		o.Broadcast(results)
+		o.statManager.IncTotalRecordsOut()
+	}
+}
+
+// pruneHistory removes the events whose windows will never be triggered again
+func (o *WindowOperator) pruneHistory(watermark int64) {
+	i := 0
+	for _, t := range o.history {
+		ends := o.windowEnds(t.Timestamp)
+		if len(ends) > 0 && ends[len(ends)-1]+o.allowedLateness > watermark {
+			o.history[i] = t
+			i++
+		}
+	}
+	o.history = o.history[:i]
+}
+
 func getEarliestEventTs(inputs []*xsql.Tuple, startTs int64, endTs int64) int64 {
 	var minTs int64 = math.MaxInt64
 	for _, t := range inputs {
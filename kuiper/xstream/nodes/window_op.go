@@ -9,6 +9,7 @@ import (
 	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xsql"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/checkpoints"
 	"github.com/benbjohnson/clock"
 )
 
@@ -24,15 +25,19 @@ type WindowOperator struct {
 	interval           int
 	isEventTime        bool
 	watermarkGenerator *WatermarkGenerator //For event time only
+	allowedLateness    int64               //For event time only
+	late               *defaultNode        //The side output of the events arriving too late
 
 	statManager StatManager
 	ticker      *clock.Ticker //For processing time only
 	// states
 	triggerTime int64
 	msgCount    int
+	history     []*xsql.Tuple //The triggered events kept for the allowed lateness
 }
 
 const WINDOW_INPUTS_KEY = "$$windowInputs"
+const WINDOW_HISTORY_KEY = "$$windowHistory"
 const TRIGGER_TIME_KEY = "$$triggerTime"
 const MSG_COUNT_KEY = "$$msgCount"
 
@@ -50,6 +55,10 @@ func NewWindowOp(name string, w *xsql.Window, isEventTime bool, lateTolerance in
 			name:    name,
 		},
 	}
+	o.late = &defaultNode{
+		outputs: make(map[string]chan<- interface{}),
+		name:    name,
+	}
 	o.isEventTime = isEventTime
 	if w != nil {
 		o.window = &WindowConfig{
@@ -78,11 +87,52 @@ func NewWindowOp(name string, w *xsql.Window, isEventTime bool, lateTolerance in
 	return o, nil
 }
 
+// SetAllowedLateness keeps the triggered windows for the milliseconds after
+// the watermark passes their ends. The late events of these windows trigger
+// them again with the updated results.
+func (o *WindowOperator) SetAllowedLateness(l int64) error {
+	if l == 0 {
+		return nil
+	}
+	if l < 0 {
+		return fmt.Errorf("allowed lateness %d is invalid, require a positive integer", l)
+	}
+	if !o.isEventTime {
+		return fmt.Errorf("allowed lateness is only supported by event time window")
+	}
+	if o.window.Type != xsql.TUMBLING_WINDOW && o.window.Type != xsql.HOPPING_WINDOW {
+		return fmt.Errorf("allowed lateness is only supported by tumbling and hopping window")
+	}
+	o.allowedLateness = l
+	return nil
+}
+
+// LateOutput is the side output which receives the events arriving too late
+// to be counted in any window. The events are sent in the same format as the
+// results of the rule, that is a json array of the messages.
+func (o *WindowOperator) LateOutput() api.Emitter {
+	return o.late
+}
+
+func (o *WindowOperator) SetQos(qos api.Qos) {
+	o.defaultNode.SetQos(qos)
+	o.late.SetQos(qos)
+}
+
+func (o *WindowOperator) Broadcast(val interface{}) error {
+	//The sinks of the side output also need the barriers
+	if _, ok := val.(*checkpoints.Barrier); ok && len(o.late.outputs) > 0 {
+		o.late.Broadcast(val)
+	}
+	return o.defaultNode.Broadcast(val)
+}
+
 // Exec is the entry point for the executor
 // input: *xsql.Tuple from preprocessor
 // output: xsql.WindowTuplesSet
 func (o *WindowOperator) Exec(ctx api.StreamContext, errCh chan<- error) {
 	o.ctx = ctx
+	o.late.ctx = ctx
 	log := ctx.GetLogger()
 	log.Debugf("Window operator %s is started", o.name)
 
@@ -126,6 +176,14 @@ func (o *WindowOperator) Exec(ctx api.StreamContext, errCh chan<- error) {
 			errCh <- fmt.Errorf("restore window state `msgCount` %v error, invalid type", s)
 		}
 	}
+	o.history = nil
+	if s, err := ctx.GetState(WINDOW_HISTORY_KEY); err == nil && s != nil {
+		if si, ok := s.([]*xsql.Tuple); ok {
+			o.history = si
+		} else {
+			errCh <- fmt.Errorf("restore window state `history` %v error, invalid type", s)
+		}
+	}
 	log.Infof("Start with window state triggerTime: %d, msgCount: %d", o.triggerTime, o.msgCount)
 	if o.isEventTime {
 		go o.execEventWindow(ctx, inputs, errCh)
@@ -260,3 +260,61 @@ func TestCountWindow(t *testing.T) {
 		}
 	}
 }
+
+func TestWindowEnds(t *testing.T) {
+	var tests = []struct {
+		window *WindowConfig
+		ts     int64
+		ends   []int64
+	}{
+		{
+			window: &WindowConfig{Type: xsql.TUMBLING_WINDOW, Length: 1000},
+			ts:     1500,
+			ends:   []int64{2000},
+		}, {
+			window: &WindowConfig{Type: xsql.TUMBLING_WINDOW, Length: 1000},
+			ts:     2000,
+			ends:   []int64{2000},
+		}, {
+			window: &WindowConfig{Type: xsql.HOPPING_WINDOW, Length: 3000, Interval: 1000},
+			ts:     1500,
+			ends:   []int64{2000, 3000, 4000},
+		}, {
+			window: &WindowConfig{Type: xsql.HOPPING_WINDOW, Length: 2000, Interval: 1000},
+			ts:     1000,
+			ends:   []int64{1000, 2000, 3000},
+		},
+	}
+	for i, tt := range tests {
+		o := &WindowOperator{window: tt.window}
+		ends := o.windowEnds(tt.ts)
+		if !reflect.DeepEqual(tt.ends, ends) {
+			t.Errorf("%d. ends mismatch:\n  exp=%v\n  got=%v\n", i, tt.ends, ends)
+		}
+		for _, end := range ends {
+			if !o.inWindow(tt.ts, end) {
+				t.Errorf("%d. %d should be in the window ending at %d", i, tt.ts, end)
+			}
+		}
+	}
+}
+
+func TestSetAllowedLateness(t *testing.T) {
+	o := &WindowOperator{window: &WindowConfig{Type: xsql.SLIDING_WINDOW, Length: 1000}, isEventTime: true}
+	if err := o.SetAllowedLateness(1000); err == nil {
+		t.Errorf("expect error for sliding window")
+	}
+	o = &WindowOperator{window: &WindowConfig{Type: xsql.TUMBLING_WINDOW, Length: 1000}}
+	if err := o.SetAllowedLateness(1000); err == nil {
+		t.Errorf("expect error for processing time window")
+	}
+	o.isEventTime = true
+	if err := o.SetAllowedLateness(1000); err != nil || o.allowedLateness != 1000 {
+		t.Errorf("unexpected error %v", err)
+	}
+	o.history = []*xsql.Tuple{{Timestamp: 500}, {Timestamp: 1500}, {Timestamp: 2500}}
+	o.pruneHistory(2000)
+	if len(o.history) != 2 || o.history[0].Timestamp != 1500 {
+		t.Errorf("history mismatch: %v", o.history)
+	}
+}
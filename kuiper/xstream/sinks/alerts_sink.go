@@ -0,0 +1,235 @@
+package sinks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloustone/pandas/alerts"
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+)
+
+const alertFiring = "firing"
+
+// AlertsSink raises an alert in the alerts service for each result row, or
+// updates the alert if it is raised already. The alert name, severity and
+// labels are taken from the fields of the row.
+type AlertsSink struct {
+	url           string
+	token         string
+	group         string
+	nameField     string
+	severityField string
+	labelFields   []string
+	timeout       int
+
+	client *http.Client
+}
+
+func (as *AlertsSink) Configure(ps map[string]interface{}) error {
+	as.url = "http://localhost:8180"
+	if u, ok := ps["url"]; ok {
+		if v, ok := u.(string); ok && v != "" {
+			as.url = strings.TrimSuffix(v, "/")
+		} else {
+			return fmt.Errorf("alerts sink property url %v is invalid", u)
+		}
+	}
+	t, ok := ps["token"]
+	if !ok {
+		return fmt.Errorf("alerts sink is missing property token")
+	}
+	if as.token, ok = t.(string); !ok || as.token == "" {
+		return fmt.Errorf("alerts sink property token %v is invalid", t)
+	}
+	if g, ok := ps["group"]; ok {
+		if as.group, ok = g.(string); !ok {
+			return fmt.Errorf("alerts sink property group %v is not a string", g)
+		}
+	}
+	as.nameField = "name"
+	if n, ok := ps["nameField"]; ok {
+		if v, ok := n.(string); ok && v != "" {
+			as.nameField = v
+		} else {
+			return fmt.Errorf("alerts sink property nameField %v is invalid", n)
+		}
+	}
+	as.severityField = "severity"
+	if s, ok := ps["severityField"]; ok {
+		if v, ok := s.(string); ok && v != "" {
+			as.severityField = v
+		} else {
+			return fmt.Errorf("alerts sink property severityField %v is invalid", s)
+		}
+	}
+	if l, ok := ps["labelFields"]; ok {
+		fields, ok := l.([]interface{})
+		if !ok {
+			return fmt.Errorf("alerts sink property labelFields %v is not an array", l)
+		}
+		for _, f := range fields {
+			v, ok := f.(string)
+			if !ok {
+				return fmt.Errorf("alerts sink property labelFields %v is not a string array", l)
+			}
+			as.labelFields = append(as.labelFields, v)
+		}
+	}
+	as.timeout = 5000
+	if to, ok := ps["timeout"]; ok {
+		if v, err := util.ToInt(to); err != nil || v <= 0 {
+			return fmt.Errorf("alerts sink property timeout %v is not a positive integer", to)
+		} else {
+			as.timeout = v
+		}
+	}
+	return nil
+}
+
+func (as *AlertsSink) Open(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Opening alerts sink for rule %s with url %s.", ctx.GetRuleId(), as.url)
+	if _, err := url.Parse(as.url); err != nil {
+		return err
+	}
+	as.client = &http.Client{Timeout: time.Duration(as.timeout) * time.Millisecond}
+	return nil
+}
+
+func (as *AlertsSink) Collect(ctx api.StreamContext, item interface{}) error {
+	logger := ctx.GetLogger()
+	v, ok := item.([]byte)
+	if !ok {
+		logger.Warnf("alerts sink receive non []byte data: %v", item)
+		return nil
+	}
+	logger.Debugf("alerts sink receive %s", v)
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(v, &rows); err != nil {
+		return fmt.Errorf("alerts sink fails to decode %s: %s", v, err)
+	}
+	var errs MultiErrors
+	for _, row := range rows {
+		name, ok := row[as.nameField].(string)
+		if !ok || name == "" {
+			logger.Warnf("alerts sink drops the row without alert name field %s: %v", as.nameField, row)
+			continue
+		}
+		if err := as.raise(name, row); err != nil {
+			errs = errs.AddError(err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// raise updates the alert of the name if it exists, otherwise a new alert
+// is created.
+func (as *AlertsSink) raise(name string, row map[string]interface{}) error {
+	alert, found, err := as.retrieve(name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		alert = alerts.Alert{
+			Name:      name,
+			Group:     as.group,
+			Extension: &alerts.AlertExtension{StartAt: time.Now()},
+		}
+	}
+	if alert.Extension == nil {
+		alert.Extension = &alerts.AlertExtension{StartAt: time.Now()}
+	}
+	if alert.Extension.Labels == nil {
+		alert.Extension.Labels = make(map[string]string)
+	}
+	for k, v := range as.labels(row) {
+		alert.Extension.Labels[k] = v
+	}
+	alert.Extension.State = alertFiring
+	alert.On = true
+	alert.Metadata = row
+
+	if found {
+		_, err = as.send(http.MethodPut, "/alerts", alert)
+	} else {
+		_, err = as.send(http.MethodPost, "/alerts", alert)
+	}
+	return err
+}
+
+func (as *AlertsSink) labels(row map[string]interface{}) map[string]string {
+	labels := make(map[string]string)
+	if s, ok := row[as.severityField]; ok && s != nil {
+		labels["severity"] = fmt.Sprintf("%v", s)
+	}
+	for _, f := range as.labelFields {
+		if v, ok := row[f]; ok && v != nil {
+			labels[f] = fmt.Sprintf("%v", v)
+		}
+	}
+	return labels
+}
+
+func (as *AlertsSink) retrieve(name string) (alerts.Alert, bool, error) {
+	resp, err := as.send(http.MethodGet, "/alerts/"+url.PathEscape(name), nil)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return alerts.Alert{}, false, nil
+		}
+		return alerts.Alert{}, false, err
+	}
+	var res struct {
+		Alert alerts.Alert `json:"alert"`
+	}
+	if err := json.Unmarshal(resp.body, &res); err != nil {
+		return alerts.Alert{}, false, fmt.Errorf("alerts sink fails to decode alert %s: %s", name, err)
+	}
+	return res.Alert, res.Alert.Name != "", nil
+}
+
+type alertsResponse struct {
+	StatusCode int
+	body       []byte
+}
+
+func (as *AlertsSink) send(method string, path string, v interface{}) (*alertsResponse, error) {
+	var body []byte
+	if v != nil {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("alerts sink fails to encode %v: %s", v, err)
+		}
+		body = b
+	}
+	req, err := http.NewRequest(method, as.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", as.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := as.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alerts sink fails to %s %s: %s", method, path, err)
+	}
+	defer resp.Body.Close()
+	buf, _ := ioutil.ReadAll(resp.Body)
+	r := &alertsResponse{StatusCode: resp.StatusCode, body: buf}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return r, fmt.Errorf("alerts sink fails to %s %s with http return code: %d and error message %s", method, path, resp.StatusCode, buf)
+	}
+	return r, nil
+}
+
+func (as *AlertsSink) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing alerts sink")
+	return nil
+}
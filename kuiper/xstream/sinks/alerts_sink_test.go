@@ -0,0 +1,117 @@
+package sinks
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cloustone/pandas/alerts"
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+)
+
+func TestAlertsSink_Apply(t *testing.T) {
+	contextLogger := util.Log.WithField("rule", "TestAlertsSink_Apply")
+	ctx := contexts.WithValue(contexts.Background(), contexts.LoggerKey, contextLogger)
+
+	existing := map[string]alerts.Alert{
+		"overheat": {
+			ID:        "1",
+			Owner:     "admin",
+			Name:      "overheat",
+			Extension: &alerts.AlertExtension{Labels: map[string]string{"site": "s1"}},
+		},
+	}
+	var (
+		methods []string
+		sent    []alerts.Alert
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		if r.Header.Get("Authorization") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			a, ok := existing[r.URL.Path[len("/alerts/"):]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"alert": a})
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+			var a alerts.Alert
+			if err := json.Unmarshal(body, &a); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			sent = append(sent, a)
+		}
+	}))
+	defer ts.Close()
+
+	s := &AlertsSink{}
+	err := s.Configure(map[string]interface{}{
+		"url":         ts.URL,
+		"token":       "token",
+		"nameField":   "alert",
+		"labelFields": []interface{}{"device"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	data := []map[string]interface{}{
+		{"alert": "overheat", "severity": "critical", "device": "d1", "temperature": 80.5},
+		{"alert": "lowbattery", "severity": "warning", "device": "d2"},
+		{"severity": "warning"},
+	}
+	input, _ := json.Marshal(data)
+	if err := s.Collect(ctx, input); err != nil {
+		t.Fatal(err)
+	}
+	s.Close(ctx)
+
+	expMethods := []string{http.MethodGet, http.MethodPut, http.MethodGet, http.MethodPost}
+	if !reflect.DeepEqual(expMethods, methods) {
+		t.Errorf("methods mismatch:\n\nexp=%v\n\ngot=%v\n\n", expMethods, methods)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("expect 2 alerts sent but got %d", len(sent))
+	}
+	if sent[0].ID != "1" || !sent[0].On || sent[0].Extension.State != alertFiring {
+		t.Errorf("updated alert mismatch: %+v", sent[0])
+	}
+	expLabels := map[string]string{"site": "s1", "severity": "critical", "device": "d1"}
+	if !reflect.DeepEqual(expLabels, sent[0].Extension.Labels) {
+		t.Errorf("labels mismatch:\n\nexp=%v\n\ngot=%v\n\n", expLabels, sent[0].Extension.Labels)
+	}
+	if sent[1].Name != "lowbattery" || sent[1].Extension.Labels["severity"] != "warning" {
+		t.Errorf("created alert mismatch: %+v", sent[1])
+	}
+}
+
+func TestAlertsSink_Configure(t *testing.T) {
+	var tests = []struct {
+		props map[string]interface{}
+		err   bool
+	}{
+		{map[string]interface{}{"token": "t"}, false},
+		{map[string]interface{}{}, true},
+		{map[string]interface{}{"token": "t", "labelFields": "device"}, true},
+		{map[string]interface{}{"token": "t", "timeout": -1}, true},
+	}
+	for i, tt := range tests {
+		err := (&AlertsSink{}).Configure(tt.props)
+		if (err != nil) != tt.err {
+			t.Errorf("%d. expect error %v but got %v", i, tt.err, err)
+		}
+	}
+}
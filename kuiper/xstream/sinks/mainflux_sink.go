@@ -0,0 +1,139 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/mainflux/broker"
+	mfsenml "github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/mainflux/senml"
+)
+
+const mainfluxProtocol = "kuiper"
+
+// MainfluxSink publishes the rule results to a mainflux channel as SenML
+// messages, so that they are delivered to the channel subscribers.
+type MainfluxSink struct {
+	server    string
+	channel   string
+	subtopic  string
+	publisher string
+	baseName  string
+
+	pubsub broker.Nats
+}
+
+func (ms *MainfluxSink) Configure(ps map[string]interface{}) error {
+	ms.server = "nats://localhost:4222"
+	if srv, ok := ps["server"]; ok {
+		if v, ok := srv.(string); ok && v != "" {
+			ms.server = v
+		}
+	}
+	ch, ok := ps["channel"]
+	if !ok {
+		return fmt.Errorf("mainflux sink is missing property channel")
+	}
+	if ms.channel, ok = ch.(string); !ok || ms.channel == "" {
+		return fmt.Errorf("mainflux sink property channel %v is invalid", ch)
+	}
+	if st, ok := ps["subtopic"]; ok {
+		if v, ok := st.(string); ok {
+			ms.subtopic = v
+		}
+	}
+	if p, ok := ps["publisher"]; ok {
+		if v, ok := p.(string); ok {
+			ms.publisher = v
+		}
+	}
+	if bn, ok := ps["baseName"]; ok {
+		if v, ok := bn.(string); ok {
+			ms.baseName = v
+		}
+	}
+	return nil
+}
+
+func (ms *MainfluxSink) Open(ctx api.StreamContext) error {
+	log := ctx.GetLogger()
+	log.Infof("Opening mainflux sink for rule %s.", ctx.GetRuleId())
+	pubsub, err := broker.New(ms.server)
+	if err != nil {
+		return fmt.Errorf("Found error: %s", err)
+	}
+	log.Infof("The connection to server %s was established successfully", ms.server)
+	ms.pubsub = pubsub
+	return nil
+}
+
+func (ms *MainfluxSink) Collect(ctx api.StreamContext, item interface{}) error {
+	logger := ctx.GetLogger()
+	v, ok := item.([]byte)
+	if !ok {
+		logger.Warnf("mainflux sink receive non []byte data: %v", item)
+		return nil
+	}
+	logger.Debugf("%s publish %s", ctx.GetOpId(), v)
+
+	payload, err := ms.encode(v)
+	if err != nil {
+		return fmt.Errorf("mainflux sink fails to encode %s: %s", v, err)
+	}
+	msg := broker.Message{
+		Channel:     ms.channel,
+		Subtopic:    ms.subtopic,
+		Publisher:   ms.publisher,
+		Protocol:    mainfluxProtocol,
+		ContentType: mfsenml.JSON,
+		Payload:     payload,
+		Created:     broker.Created(),
+	}
+	if err := ms.pubsub.Publish(context.Background(), "", msg); err != nil {
+		return fmt.Errorf("publish error: %s", err)
+	}
+	return nil
+}
+
+// encode converts the result rows into a SenML pack, each numeric, string
+// or bool field of a row becomes a record.
+func (ms *MainfluxSink) encode(result []byte) ([]byte, error) {
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(result, &rows); err != nil {
+		return nil, err
+	}
+
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+	pack := senml.Pack{}
+	for _, row := range rows {
+		for k, v := range row {
+			r := senml.Record{Name: k, Time: now}
+			switch val := v.(type) {
+			case float64:
+				r.Value = &val
+			case string:
+				r.StringValue = &val
+			case bool:
+				r.BoolValue = &val
+			default:
+				continue
+			}
+			pack.Records = append(pack.Records, r)
+		}
+	}
+	if len(pack.Records) > 0 {
+		pack.Records[0].BaseName = ms.baseName
+	}
+	return senml.Encode(pack, senml.JSON)
+}
+
+func (ms *MainfluxSink) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing mainflux sink")
+	if ms.pubsub != nil {
+		ms.pubsub.Close()
+	}
+	return nil
+}
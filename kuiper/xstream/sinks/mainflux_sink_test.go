@@ -0,0 +1,125 @@
+package sinks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/xstream/extensions"
+	"github.com/cloustone/pandas/mainflux/broker"
+	mfsenml "github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/mainflux/senml"
+)
+
+// records decodes the pack into the values keyed by the resolved names.
+func records(t *testing.T, payload []byte) (senml.Pack, map[string]interface{}) {
+	p, err := senml.Decode(payload, senml.JSON)
+	if err != nil {
+		t.Fatalf("decode %s error: %s", payload, err)
+	}
+	n, err := senml.Normalize(p)
+	if err != nil {
+		t.Fatalf("normalize %s error: %s", payload, err)
+	}
+	values := make(map[string]interface{})
+	for _, r := range n.Records {
+		switch {
+		case r.Value != nil:
+			values[r.Name] = *r.Value
+		case r.StringValue != nil:
+			values[r.Name] = *r.StringValue
+		case r.BoolValue != nil:
+			values[r.Name] = *r.BoolValue
+		default:
+			values[r.Name] = nil
+		}
+	}
+	return p, values
+}
+
+func TestMainfluxSink_Encode(t *testing.T) {
+	var tests = []struct {
+		baseName string
+		result   string
+		values   map[string]interface{}
+	}{
+		{
+			result: `[{"temperature":20.5,"label":"a","on":true}]`,
+			values: map[string]interface{}{"temperature": 20.5, "label": "a", "on": true},
+		}, {
+			result: `[{"temperature":20.5,"meta":{"a":1},"tags":["x"],"empty":null}]`,
+			values: map[string]interface{}{"temperature": 20.5},
+		}, {
+			baseName: "dev:",
+			result:   `[{"temperature":20.5},{"humidity":60}]`,
+			values:   map[string]interface{}{"dev:temperature": 20.5, "dev:humidity": float64(60)},
+		}, {
+			result: `[{"meta":{"a":1}}]`,
+			values: map[string]interface{}{},
+		},
+	}
+	for i, tt := range tests {
+		ms := &MainfluxSink{}
+		if err := ms.Configure(map[string]interface{}{"channel": "chan", "baseName": tt.baseName}); err != nil {
+			t.Fatalf("%d. configure error: %s", i, err)
+		}
+		payload, err := ms.encode([]byte(tt.result))
+		if err != nil {
+			t.Errorf("%d. unexpected error: %s", i, err)
+			continue
+		}
+		p, values := records(t, payload)
+		if !reflect.DeepEqual(tt.values, values) {
+			t.Errorf("%d. records mismatch:\n\nexp=%v\n\ngot=%v\n\n", i, tt.values, values)
+		}
+		for j, r := range p.Records {
+			if j == 0 && r.BaseName != tt.baseName {
+				t.Errorf("%d. expect base name %q but got %q", i, tt.baseName, r.BaseName)
+			}
+			if j > 0 && r.BaseName != "" {
+				t.Errorf("%d.%d expect base name only in the first record but got %q", i, j, r.BaseName)
+			}
+			if r.Time == 0 {
+				t.Errorf("%d.%d expect record time but got 0", i, j)
+			}
+		}
+	}
+
+	if _, err := (&MainfluxSink{}).encode([]byte(`{"temperature":20.5}`)); err == nil {
+		t.Errorf("expect error for non array result but got nil")
+	}
+}
+
+func TestMainfluxSink_RoundTrip(t *testing.T) {
+	ms := &MainfluxSink{}
+	if err := ms.Configure(map[string]interface{}{"channel": "chan", "baseName": "dev:"}); err != nil {
+		t.Fatalf("configure error: %s", err)
+	}
+	payload, err := ms.encode([]byte(`[{"temperature":20.5,"label":"a","on":true,"tags":["x"]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &extensions.MainfluxSource{}
+	if err := src.Configure("chan", map[string]interface{}{}); err != nil {
+		t.Fatalf("configure error: %s", err)
+	}
+	tuples, err := src.Transform(broker.Message{
+		Channel:     "chan",
+		Protocol:    mainfluxProtocol,
+		ContentType: mfsenml.JSON,
+		Payload:     payload,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tuples) != 1 {
+		t.Fatalf("expect 1 row but got %d", len(tuples))
+	}
+	exp := map[string]interface{}{"dev:temperature": 20.5, "dev:label": "a", "dev:on": true}
+	if !reflect.DeepEqual(exp, tuples[0].Message()) {
+		t.Errorf("row mismatch:\n\nexp=%v\n\ngot=%v\n\n", exp, tuples[0].Message())
+	}
+	if p := tuples[0].Meta()["protocol"]; p != mainfluxProtocol {
+		t.Errorf("expect protocol %s but got %v", mainfluxProtocol, p)
+	}
+}
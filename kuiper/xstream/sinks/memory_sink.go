@@ -0,0 +1,53 @@
+package sinks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/cloustone/pandas/kuiper/xstream/memory"
+)
+
+// MemorySink publishes the rule results to an in process topic, the rules
+// using memory source with the same topic receive the results.
+type MemorySink struct {
+	topic string
+}
+
+func (ms *MemorySink) Configure(ps map[string]interface{}) error {
+	t, ok := ps["topic"]
+	if !ok {
+		return fmt.Errorf("memory sink is missing property topic")
+	}
+	if ms.topic, ok = t.(string); !ok || ms.topic == "" {
+		return fmt.Errorf("memory sink property topic %v is invalid", t)
+	}
+	return nil
+}
+
+func (ms *MemorySink) Open(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Opening memory sink for rule %s.", ctx.GetRuleId())
+	return nil
+}
+
+func (ms *MemorySink) Collect(ctx api.StreamContext, item interface{}) error {
+	logger := ctx.GetLogger()
+	v, ok := item.([]byte)
+	if !ok {
+		logger.Warnf("memory sink receive non []byte data: %v", item)
+		return nil
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(v, &rows); err != nil {
+		return fmt.Errorf("memory sink fails to decode %s: %s", v, err)
+	}
+	if dropped := memory.Publish(ms.topic, rows); dropped > 0 {
+		logger.Warnf("memory sink drops %d rows of topic %s for slow subscribers", dropped, ms.topic)
+	}
+	return nil
+}
+
+func (ms *MemorySink) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing memory sink")
+	return nil
+}
@@ -0,0 +1,320 @@
+package sinks
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/api"
+	"github.com/lib/pq" // required for DB access
+)
+
+// PostgresSink inserts the result rows into a table of PostgreSQL or
+// TimescaleDB. The table and its columns are created from the first row if
+// they do not exist, and the rows are inserted in batches.
+type PostgresSink struct {
+	url           string
+	table         string
+	batchSize     int
+	flushInterval int
+	timeColumn    string
+	hypertable    bool
+
+	db      *sql.DB
+	columns []string
+	rows    []map[string]interface{}
+	mutex   sync.Mutex
+	cancel  func()
+}
+
+func (ps *PostgresSink) Configure(props map[string]interface{}) error {
+	u, ok := props["url"]
+	if !ok {
+		return fmt.Errorf("postgres sink is missing property url")
+	}
+	if ps.url, ok = u.(string); !ok || ps.url == "" {
+		return fmt.Errorf("postgres sink property url %v is invalid", u)
+	}
+	t, ok := props["table"]
+	if !ok {
+		return fmt.Errorf("postgres sink is missing property table")
+	}
+	if ps.table, ok = t.(string); !ok || ps.table == "" {
+		return fmt.Errorf("postgres sink property table %v is invalid", t)
+	}
+	ps.batchSize = 1
+	if b, ok := props["batchSize"]; ok {
+		if v, err := util.ToInt(b); err != nil || v <= 0 {
+			return fmt.Errorf("postgres sink property batchSize %v is not a positive integer", b)
+		} else {
+			ps.batchSize = v
+		}
+	}
+	ps.flushInterval = 1000
+	if f, ok := props["flushInterval"]; ok {
+		if v, err := util.ToInt(f); err != nil || v <= 0 {
+			return fmt.Errorf("postgres sink property flushInterval %v is not a positive integer", f)
+		} else {
+			ps.flushInterval = v
+		}
+	}
+	if c, ok := props["timeColumn"]; ok {
+		if ps.timeColumn, ok = c.(string); !ok {
+			return fmt.Errorf("postgres sink property timeColumn %v is not a string", c)
+		}
+	}
+	if h, ok := props["hypertable"]; ok {
+		if ps.hypertable, ok = h.(bool); !ok {
+			return fmt.Errorf("postgres sink property hypertable %v is not a bool", h)
+		}
+	}
+	if ps.hypertable && ps.timeColumn == "" {
+		return fmt.Errorf("postgres sink property timeColumn is required to create hypertable")
+	}
+	return nil
+}
+
+func (ps *PostgresSink) Open(ctx api.StreamContext) error {
+	logger := ctx.GetLogger()
+	logger.Infof("Opening postgres sink for rule %s with table %s.", ctx.GetRuleId(), ps.table)
+	db, err := sql.Open("postgres", ps.url)
+	if err != nil {
+		return fmt.Errorf("postgres sink fails to open database: %s", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return fmt.Errorf("postgres sink fails to connect database: %s", err)
+	}
+	ps.db = db
+	ps.columns = nil
+	ps.rows = nil
+	if ps.batchSize > 1 {
+		exeCtx, cancel := ctx.WithCancel()
+		ps.cancel = cancel
+		go ps.run(exeCtx)
+	}
+	return nil
+}
+
+// run flushes the rows periodically so that a batch not filled up is not kept
+// for long.
+func (ps *PostgresSink) run(ctx api.StreamContext) {
+	ticker := time.NewTicker(time.Duration(ps.flushInterval) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ps.mutex.Lock()
+			if err := ps.flush(); err != nil {
+				ctx.GetLogger().Errorf("postgres sink fails to insert rows: %s", err)
+			}
+			ps.mutex.Unlock()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (ps *PostgresSink) Collect(ctx api.StreamContext, item interface{}) error {
+	logger := ctx.GetLogger()
+	v, ok := item.([]byte)
+	if !ok {
+		logger.Warnf("postgres sink receive non []byte data: %v", item)
+		return nil
+	}
+	logger.Debugf("postgres sink receive %s", v)
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(v, &rows); err != nil {
+		return fmt.Errorf("postgres sink fails to decode %s: %s", v, err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	if ps.columns == nil {
+		if err := ps.createTable(rows[0]); err != nil {
+			return err
+		}
+	}
+	ps.rows = append(ps.rows, rows...)
+	if len(ps.rows) >= ps.batchSize {
+		return ps.flush()
+	}
+	return nil
+}
+
+// createTable creates the table, or adds the missing columns to an existing
+// table, according to the fields of the row. The fields which do not appear in
+// the first row are ignored afterwards.
+func (ps *PostgresSink) createTable(row map[string]interface{}) error {
+	columns, defs := tableColumns(row, ps.timeColumn)
+	if ps.batchSize*len(columns) > postgresMaxParams {
+		return fmt.Errorf("postgres sink property batchSize %d is too large for %d columns, a batch can have at most %d values", ps.batchSize, len(columns), postgresMaxParams)
+	}
+	table := quoteTable(ps.table)
+	q := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s);", table, strings.Join(defs, ", "))
+	if _, err := ps.db.Exec(q); err != nil {
+		return fmt.Errorf("postgres sink fails to create table %s: %s", ps.table, err)
+	}
+	for _, def := range defs {
+		q := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s;", table, def)
+		if _, err := ps.db.Exec(q); err != nil {
+			return fmt.Errorf("postgres sink fails to add column to table %s: %s", ps.table, err)
+		}
+	}
+	if ps.hypertable {
+		q := `SELECT create_hypertable($1, $2, if_not_exists => TRUE);`
+		if _, err := ps.db.Exec(q, ps.table, ps.timeColumn); err != nil {
+			return fmt.Errorf("postgres sink fails to create hypertable %s: %s", ps.table, err)
+		}
+	}
+	ps.columns = columns
+	return nil
+}
+
+// postgresMaxBatches is the number of batches kept while the inserts fail,
+// the oldest rows beyond it are dropped.
+const postgresMaxBatches = 10
+
+// postgresMaxParams is the maximum number of parameters of a statement
+// supported by the PostgreSQL protocol.
+const postgresMaxParams = 65535
+
+// flush inserts the buffered rows. The rows are inserted in chunks so that a
+// statement never exceeds the parameter limit. The rows not inserted are kept
+// to be inserted again by the next flush if an insert fails.
+func (ps *PostgresSink) flush() error {
+	size := len(ps.rows)
+	if len(ps.columns) > 0 {
+		size = postgresMaxParams / len(ps.columns)
+	}
+	for len(ps.rows) > 0 {
+		n := size
+		if n > len(ps.rows) {
+			n = len(ps.rows)
+		}
+		q, args := insertStatement(ps.table, ps.columns, ps.timeColumn, ps.rows[:n])
+		if _, err := ps.db.Exec(q, args...); err != nil {
+			if max := ps.batchSize * postgresMaxBatches; len(ps.rows) > max {
+				dropped := len(ps.rows) - max
+				ps.rows = ps.rows[dropped:]
+				return fmt.Errorf("postgres sink fails to insert into table %s and drops %d rows: %s", ps.table, dropped, err)
+			}
+			return fmt.Errorf("postgres sink fails to insert into table %s: %s", ps.table, err)
+		}
+		ps.rows = ps.rows[n:]
+	}
+	ps.rows = nil
+	return nil
+}
+
+func (ps *PostgresSink) Close(ctx api.StreamContext) error {
+	ctx.GetLogger().Infof("Closing postgres sink")
+	if ps.cancel != nil {
+		ps.cancel()
+	}
+	if ps.db == nil {
+		return nil
+	}
+	ps.mutex.Lock()
+	err := ps.flush()
+	ps.mutex.Unlock()
+	if cerr := ps.db.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// tableColumns returns the sorted column names and their definitions for the
+// fields of the row.
+func tableColumns(row map[string]interface{}, timeColumn string) ([]string, []string) {
+	columns := make([]string, 0, len(row)+1)
+	for k := range row {
+		columns = append(columns, k)
+	}
+	if _, ok := row[timeColumn]; timeColumn != "" && !ok {
+		columns = append(columns, timeColumn)
+	}
+	sort.Strings(columns)
+	defs := make([]string, len(columns))
+	for i, c := range columns {
+		t := "TIMESTAMPTZ"
+		if c != timeColumn {
+			t = columnType(row[c])
+		}
+		defs[i] = fmt.Sprintf("%s %s", pq.QuoteIdentifier(c), t)
+	}
+	return columns, defs
+}
+
+func columnType(v interface{}) string {
+	switch v.(type) {
+	case float64:
+		return "DOUBLE PRECISION"
+	case bool:
+		return "BOOLEAN"
+	case map[string]interface{}, []interface{}:
+		return "JSONB"
+	default:
+		return "TEXT"
+	}
+}
+
+// insertStatement builds a multi rows insert statement with the parameters.
+func insertStatement(table string, columns []string, timeColumn string, rows []map[string]interface{}) (string, []interface{}) {
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = pq.QuoteIdentifier(c)
+	}
+	values := make([]string, len(rows))
+	args := make([]interface{}, 0, len(rows)*len(columns))
+	for i, row := range rows {
+		params := make([]string, len(columns))
+		for j, c := range columns {
+			args = append(args, columnValue(row[c], c == timeColumn))
+			params[j] = fmt.Sprintf("$%d", len(args))
+		}
+		values[i] = "(" + strings.Join(params, ", ") + ")"
+	}
+	q := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s;", quoteTable(table), strings.Join(names, ", "), strings.Join(values, ", "))
+	return q, args
+}
+
+func columnValue(v interface{}, isTime bool) interface{} {
+	if isTime {
+		switch t := v.(type) {
+		case float64:
+			return util.TimeFromUnixMilli(int64(t))
+		case string:
+			return t
+		default:
+			return time.Now()
+		}
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return string(b)
+	default:
+		return v
+	}
+}
+
+// quoteTable quotes the table name which may be qualified by the schema.
+func quoteTable(table string) string {
+	parts := strings.Split(table, ".")
+	for i, p := range parts {
+		parts[i] = pq.QuoteIdentifier(p)
+	}
+	return strings.Join(parts, ".")
+}
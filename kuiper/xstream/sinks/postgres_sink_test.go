@@ -0,0 +1,230 @@
+package sinks
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper/util"
+	"github.com/cloustone/pandas/kuiper/xstream/contexts"
+)
+
+// fakeDriver is a database driver which records the arguments of the executed
+// statements, or fails them when its error is set.
+type fakeDriver struct {
+	mutex sync.Mutex
+	err   error
+	args  [][]driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+func (d *fakeDriver) set(err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.err = err
+	d.args = nil
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{c.d}, nil }
+func (c *fakeConn) Close() error                              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mutex.Lock()
+	defer s.d.mutex.Unlock()
+	if s.d.err != nil {
+		return nil, s.d.err
+	}
+	s.d.args = append(s.d.args, args)
+	return driver.RowsAffected(0), nil
+}
+
+var fakeDB = &fakeDriver{}
+
+func init() {
+	sql.Register("postgres-fake", fakeDB)
+}
+
+func TestTableColumns(t *testing.T) {
+	row := map[string]interface{}{"temperature": 20.5, "device": "d1", "on": true, "tags": []interface{}{"a"}}
+	columns, defs := tableColumns(row, "ts")
+	expColumns := []string{"device", "on", "tags", "temperature", "ts"}
+	if !reflect.DeepEqual(expColumns, columns) {
+		t.Errorf("columns mismatch:\n\nexp=%v\n\ngot=%v\n\n", expColumns, columns)
+	}
+	expDefs := []string{`"device" TEXT`, `"on" BOOLEAN`, `"tags" JSONB`, `"temperature" DOUBLE PRECISION`, `"ts" TIMESTAMPTZ`}
+	if !reflect.DeepEqual(expDefs, defs) {
+		t.Errorf("definitions mismatch:\n\nexp=%v\n\ngot=%v\n\n", expDefs, defs)
+	}
+}
+
+func TestInsertStatement(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"device": "d1", "temperature": 20.5, "ts": float64(1000)},
+		{"device": "d2", "meta": map[string]interface{}{"a": 1.0}},
+	}
+	q, args := insertStatement("public.metrics", []string{"device", "temperature", "ts"}, "", rows)
+	exp := `INSERT INTO "public"."metrics" ("device", "temperature", "ts") VALUES ($1, $2, $3), ($4, $5, $6);`
+	if q != exp {
+		t.Errorf("statement mismatch:\n\nexp=%s\n\ngot=%s\n\n", exp, q)
+	}
+	expArgs := []interface{}{"d1", 20.5, float64(1000), "d2", nil, nil}
+	if !reflect.DeepEqual(expArgs, args) {
+		t.Errorf("args mismatch:\n\nexp=%v\n\ngot=%v\n\n", expArgs, args)
+	}
+	_, args = insertStatement("metrics", []string{"meta"}, "", rows[1:])
+	if args[0] != `{"a":1}` {
+		t.Errorf("expect json value but got %v", args[0])
+	}
+}
+
+func TestPostgresSink_Configure(t *testing.T) {
+	var tests = []struct {
+		props map[string]interface{}
+		err   bool
+	}{
+		{map[string]interface{}{"url": "postgres://localhost", "table": "t", "batchSize": 10}, false},
+		{map[string]interface{}{"table": "t"}, true},
+		{map[string]interface{}{"url": "postgres://localhost"}, true},
+		{map[string]interface{}{"url": "postgres://localhost", "table": "t", "batchSize": 0}, true},
+		{map[string]interface{}{"url": "postgres://localhost", "table": "t", "hypertable": true}, true},
+	}
+	for i, tt := range tests {
+		err := (&PostgresSink{}).Configure(tt.props)
+		if (err != nil) != tt.err {
+			t.Errorf("%d. expect error %v but got %v", i, tt.err, err)
+		}
+	}
+}
+
+func TestPostgresSink_Collect(t *testing.T) {
+	db, err := sql.Open("postgres-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ps := &PostgresSink{table: "t", batchSize: 2, db: db, columns: []string{"a"}}
+	ctx := contexts.WithValue(contexts.Background(), contexts.LoggerKey, util.Log.WithField("rule", "TestPostgresSink_Collect"))
+
+	var tests = []struct {
+		data []byte
+		err  error
+		rows int
+		args []interface{}
+	}{
+		{[]byte(`[{"a":1}]`), nil, 1, nil},
+		{[]byte(`[{"a":2}]`), errors.New("connection refused"), 2, nil},
+		{[]byte(`[{"a":3}]`), errors.New("connection refused"), 3, nil},
+		{[]byte(`[{"a":4}]`), nil, 0, []interface{}{1.0, 2.0, 3.0, 4.0}},
+		{[]byte(`[{"a":5},{"a":6}]`), nil, 0, []interface{}{5.0, 6.0}},
+	}
+	for i, tt := range tests {
+		fakeDB.set(tt.err)
+		err := ps.Collect(ctx, tt.data)
+		if (err != nil) != (tt.err != nil) {
+			t.Errorf("%d. expect error %v but got %v", i, tt.err, err)
+		}
+		if len(ps.rows) != tt.rows {
+			t.Errorf("%d. expect %d rows kept but got %d", i, tt.rows, len(ps.rows))
+		}
+		var args []interface{}
+		for _, a := range fakeDB.args {
+			for _, v := range a {
+				args = append(args, v)
+			}
+		}
+		if !reflect.DeepEqual(tt.args, args) {
+			t.Errorf("%d. inserted args mismatch:\n\nexp=%v\n\ngot=%v\n\n", i, tt.args, args)
+		}
+	}
+}
+
+func TestPostgresSink_FlushDrop(t *testing.T) {
+	db, err := sql.Open("postgres-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ps := &PostgresSink{table: "t", batchSize: 1, db: db, columns: []string{"a"}}
+	for i := 0; i < postgresMaxBatches+3; i++ {
+		ps.rows = append(ps.rows, map[string]interface{}{"a": float64(i)})
+	}
+
+	fakeDB.set(errors.New("connection refused"))
+	if err := ps.flush(); err == nil {
+		t.Errorf("expect error but got nil")
+	}
+	if len(ps.rows) != postgresMaxBatches {
+		t.Errorf("expect %d rows kept but got %d", postgresMaxBatches, len(ps.rows))
+	}
+	if ps.rows[0]["a"] != 3.0 {
+		t.Errorf("expect the oldest rows dropped but got first row %v", ps.rows[0])
+	}
+}
+
+func TestPostgresSink_FlushChunks(t *testing.T) {
+	db, err := sql.Open("postgres-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	columns := []string{"a", "b", "c"}
+	size := postgresMaxParams / len(columns)
+	ps := &PostgresSink{table: "t", batchSize: size * 2, db: db, columns: columns}
+	for i := 0; i < size*2+1; i++ {
+		ps.rows = append(ps.rows, map[string]interface{}{"a": float64(i), "b": "x", "c": true})
+	}
+
+	fakeDB.set(nil)
+	if err := ps.flush(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ps.rows) != 0 {
+		t.Errorf("expect no rows kept but got %d", len(ps.rows))
+	}
+	var counts []int
+	for _, a := range fakeDB.args {
+		counts = append(counts, len(a))
+	}
+	expCounts := []int{size * 3, size * 3, 3}
+	if !reflect.DeepEqual(expCounts, counts) {
+		t.Errorf("statement args mismatch:\n\nexp=%v\n\ngot=%v\n\n", expCounts, counts)
+	}
+	if last := fakeDB.args[2]; last[0] != float64(size*2) {
+		t.Errorf("expect the last row inserted last but got %v", last)
+	}
+}
+
+func TestPostgresSink_CreateTableTooLarge(t *testing.T) {
+	db, err := sql.Open("postgres-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ps := &PostgresSink{table: "t", batchSize: postgresMaxParams/3 + 1, db: db}
+
+	fakeDB.set(nil)
+	if err := ps.createTable(map[string]interface{}{"a": 1.0, "b": "x", "c": true}); err == nil {
+		t.Errorf("expect error but got nil")
+	}
+	if ps.columns != nil || len(fakeDB.args) != 0 {
+		t.Errorf("expect no table created but got columns %v", ps.columns)
+	}
+	ps.batchSize = postgresMaxParams / 3
+	if err := ps.createTable(map[string]interface{}{"a": 1.0, "b": "x", "c": true}); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
@@ -3,7 +3,7 @@ package sinks
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xstream/contexts"
 	"io/ioutil"
 	"net/http"
@@ -183,29 +183,39 @@ func (s *TopologyNew) GetCoordinator() *checkpoints.Coordinator {
 	return s.coordinator
 }
 
-func (s *TopologyNew) GetMetrics() (keys []string, values []interface{}) {
+// NodeMetrics holds the metrics of one instance of a source, operator or sink
+// in the topology, the values are ordered as nodes.MetricNames
+type NodeMetrics struct {
+	Type     string
+	Name     string
+	Instance int
+	Values   []interface{}
+}
+
+func (s *TopologyNew) GetNodeMetrics() (result []NodeMetrics) {
 	for _, node := range s.sources {
 		for ins, metrics := range node.GetMetrics() {
-			for i, v := range metrics {
-				keys = append(keys, "source_"+node.GetName()+"_"+strconv.Itoa(ins)+"_"+nodes.MetricNames[i])
-				values = append(values, v)
-			}
+			result = append(result, NodeMetrics{Type: "source", Name: node.GetName(), Instance: ins, Values: metrics})
 		}
 	}
 	for _, node := range s.ops {
 		for ins, metrics := range node.GetMetrics() {
-			for i, v := range metrics {
-				keys = append(keys, "op_"+node.GetName()+"_"+strconv.Itoa(ins)+"_"+nodes.MetricNames[i])
-				values = append(values, v)
-			}
+			result = append(result, NodeMetrics{Type: "op", Name: node.GetName(), Instance: ins, Values: metrics})
 		}
 	}
 	for _, node := range s.sinks {
 		for ins, metrics := range node.GetMetrics() {
-			for i, v := range metrics {
-				keys = append(keys, "sink_"+node.GetName()+"_"+strconv.Itoa(ins)+"_"+nodes.MetricNames[i])
-				values = append(values, v)
-			}
+			result = append(result, NodeMetrics{Type: "sink", Name: node.GetName(), Instance: ins, Values: metrics})
+		}
+	}
+	return
+}
+
+func (s *TopologyNew) GetMetrics() (keys []string, values []interface{}) {
+	for _, m := range s.GetNodeMetrics() {
+		for i, v := range m.Values {
+			keys = append(keys, m.Type+"_"+m.Name+"_"+strconv.Itoa(m.Instance)+"_"+nodes.MetricNames[i])
+			values = append(values, v)
 		}
 	}
 	return
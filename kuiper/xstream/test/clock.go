@@ -2,7 +2,7 @@ package test
 
 import (
 	"github.com/benbjohnson/clock"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 )
 
 func ResetClock(t int64) {
@@ -1,11 +1,14 @@
 package test
 
 import (
+	"sync"
+
 	"github.com/cloustone/pandas/kuiper/xstream/api"
 )
 
 type MockSink struct {
 	results [][]byte
+	sync.Mutex
 }
 
 func NewMockSink() *MockSink {
@@ -16,7 +19,9 @@ func NewMockSink() *MockSink {
 func (m *MockSink) Open(ctx api.StreamContext) error {
 	log := ctx.GetLogger()
 	log.Debugln("Opening mock sink")
+	m.Lock()
 	m.results = make([][]byte, 0)
+	m.Unlock()
 	return nil
 }
 
@@ -24,7 +29,9 @@ func (m *MockSink) Collect(ctx api.StreamContext, item interface{}) error {
 	logger := ctx.GetLogger()
 	if v, ok := item.([]byte); ok {
 		logger.Debugf("mock sink receive %s", item)
+		m.Lock()
 		m.results = append(m.results, v)
+		m.Unlock()
 	} else {
 		logger.Info("mock sink receive non byte data")
 	}
@@ -41,5 +48,7 @@ func (m *MockSink) Configure(props map[string]interface{}) error {
 }
 
 func (m *MockSink) GetResults() [][]byte {
+	m.Lock()
+	defer m.Unlock()
 	return m.results
 }
@@ -2,7 +2,7 @@ package test
 
 import (
 	"fmt"
-	"github.com/cloustone/pandas/kuiper"
+	"github.com/cloustone/pandas/kuiper/util"
 	"github.com/cloustone/pandas/kuiper/xsql"
 	"github.com/cloustone/pandas/kuiper/xstream/api"
 	"sync"
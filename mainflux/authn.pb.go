@@ -336,6 +336,110 @@ func (m *IssueReq) GetType() uint32 {
 	return 0
 }
 
+type ChannelID struct {
+	Value                string   `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChannelID) Reset()         { *m = ChannelID{} }
+func (m *ChannelID) String() string { return proto.CompactTextString(m) }
+func (*ChannelID) ProtoMessage()    {}
+func (*ChannelID) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b40bfba985381dd1, []int{6}
+}
+func (m *ChannelID) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ChannelID) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ChannelID.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ChannelID) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelID.Merge(m, src)
+}
+func (m *ChannelID) XXX_Size() int {
+	return m.Size()
+}
+func (m *ChannelID) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelID.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelID proto.InternalMessageInfo
+
+func (m *ChannelID) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// Schema is the specification the messages published to a channel are
+// validated against, its definition is encoded as JSON.
+type Schema struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Definition           []byte   `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Schema) Reset()         { *m = Schema{} }
+func (m *Schema) String() string { return proto.CompactTextString(m) }
+func (*Schema) ProtoMessage()    {}
+func (*Schema) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b40bfba985381dd1, []int{7}
+}
+func (m *Schema) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Schema) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Schema.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Schema) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schema.Merge(m, src)
+}
+func (m *Schema) XXX_Size() int {
+	return m.Size()
+}
+func (m *Schema) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schema.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schema proto.InternalMessageInfo
+
+func (m *Schema) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Schema) GetDefinition() []byte {
+	if m != nil {
+		return m.Definition
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*AccessByKeyReq)(nil), "mainflux.AccessByKeyReq")
 	proto.RegisterType((*ThingID)(nil), "mainflux.ThingID")
@@ -343,35 +447,41 @@ func init() {
 	proto.RegisterType((*Token)(nil), "mainflux.Token")
 	proto.RegisterType((*UserID)(nil), "mainflux.UserID")
 	proto.RegisterType((*IssueReq)(nil), "mainflux.IssueReq")
+	proto.RegisterType((*ChannelID)(nil), "mainflux.ChannelID")
+	proto.RegisterType((*Schema)(nil), "mainflux.Schema")
 }
 
 func init() { proto.RegisterFile("authn.proto", fileDescriptor_b40bfba985381dd1) }
 
 var fileDescriptor_b40bfba985381dd1 = []byte{
-	// 363 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xc1, 0x4e, 0xc2, 0x40,
-	0x10, 0x86, 0x5b, 0x23, 0x05, 0x47, 0x8b, 0x38, 0x31, 0xd8, 0xd4, 0x58, 0x4d, 0x4f, 0x9e, 0x16,
-	0x83, 0x89, 0x47, 0x0d, 0x58, 0x0f, 0x8d, 0x89, 0x07, 0xc4, 0x07, 0x28, 0x75, 0xa1, 0x8d, 0xb0,
-	0xc5, 0x76, 0x4b, 0xec, 0x9b, 0xf8, 0x44, 0xc6, 0xa3, 0x8f, 0x60, 0xf0, 0x45, 0x4c, 0xb7, 0xdd,
-	0x80, 0x0a, 0xde, 0xf6, 0x9f, 0x9d, 0x99, 0x7f, 0xe6, 0x1b, 0xd8, 0xf6, 0x52, 0x1e, 0x30, 0x32,
-	0x8d, 0x23, 0x1e, 0x61, 0x6d, 0xe2, 0x85, 0x6c, 0x38, 0x4e, 0x5f, 0xcc, 0xc3, 0x51, 0x14, 0x8d,
-	0xc6, 0xb4, 0x25, 0xe2, 0x83, 0x74, 0xd8, 0xa2, 0x93, 0x29, 0xcf, 0x8a, 0x34, 0xfb, 0x12, 0xea,
-	0x1d, 0xdf, 0xa7, 0x49, 0xd2, 0xcd, 0x6e, 0x69, 0xd6, 0xa3, 0xcf, 0xb8, 0x0f, 0x15, 0x1e, 0x3d,
-	0x51, 0x66, 0xa8, 0x27, 0xea, 0xe9, 0x56, 0xaf, 0x10, 0xd8, 0x04, 0xcd, 0x0f, 0x3c, 0xe6, 0x3a,
-	0xc6, 0x86, 0x08, 0x97, 0xca, 0x3e, 0x86, 0x6a, 0x3f, 0x08, 0xd9, 0xc8, 0x75, 0xf2, 0xc2, 0x99,
-	0x37, 0x4e, 0xa9, 0x2c, 0x14, 0xc2, 0xee, 0x80, 0x2e, 0x0d, 0x5c, 0x27, 0xef, 0x6f, 0x40, 0x95,
-	0x17, 0x15, 0x65, 0xa2, 0x94, 0x6b, 0x3d, 0x8e, 0xa0, 0xd2, 0x17, 0x43, 0xac, 0x76, 0xb0, 0x40,
-	0x7b, 0x48, 0x68, 0xbc, 0x76, 0x82, 0x0b, 0xa8, 0xb9, 0x49, 0x92, 0xd2, 0xdc, 0xbc, 0x09, 0x5a,
-	0x98, 0xbf, 0xe3, 0x32, 0xa5, 0x54, 0x88, 0xb0, 0xc9, 0xb3, 0x29, 0x15, 0xc6, 0x7a, 0x4f, 0xbc,
-	0xdb, 0x6f, 0x2a, 0xe8, 0x62, 0xb7, 0xe4, 0x9e, 0xc6, 0xb3, 0xd0, 0xa7, 0x78, 0x05, 0xf5, 0x6b,
-	0x8f, 0x2d, 0xf1, 0x42, 0x83, 0x48, 0xcc, 0xe4, 0x27, 0x46, 0x73, 0x6f, 0xf1, 0x53, 0x02, 0xb2,
-	0x15, 0xec, 0x82, 0xbe, 0xd4, 0xc0, 0x75, 0xf0, 0xe0, 0x6f, 0xbd, 0xa0, 0x64, 0x36, 0x49, 0x71,
-	0x35, 0x22, 0xaf, 0x46, 0x6e, 0xf2, 0xab, 0xd9, 0x0a, 0x9e, 0x41, 0xcd, 0x7d, 0xa4, 0x8c, 0x87,
-	0xc3, 0x0c, 0x77, 0x97, 0x4c, 0x72, 0x42, 0x2b, 0x5d, 0xdb, 0x11, 0xec, 0x74, 0x52, 0x1e, 0xdc,
-	0xc9, 0x35, 0x08, 0x54, 0x04, 0x10, 0xc4, 0x45, 0xb6, 0x24, 0x64, 0xfe, 0x6e, 0x69, 0x2b, 0xd8,
-	0xfa, 0xcf, 0xb1, 0xb1, 0x08, 0x14, 0x57, 0xb0, 0x95, 0x6e, 0xe3, 0x7d, 0x6e, 0xa9, 0x1f, 0x73,
-	0x4b, 0xfd, 0x9c, 0x5b, 0xea, 0xeb, 0x97, 0xa5, 0x0c, 0x34, 0xb1, 0xc6, 0xf9, 0x77, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xfa, 0x48, 0x68, 0x52, 0xa3, 0x02, 0x00, 0x00,
+	// 420 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0xc1, 0xce, 0x93, 0x40,
+	0x10, 0x06, 0x63, 0xf9, 0xfb, 0x8f, 0x3f, 0xb5, 0xae, 0xa6, 0x12, 0x8c, 0xa8, 0x7b, 0xf2, 0x44,
+	0x4d, 0x8d, 0x9e, 0x8c, 0xa6, 0x2d, 0x1e, 0x88, 0x89, 0x07, 0x5a, 0xbd, 0x53, 0x3a, 0x94, 0x8d,
+	0x74, 0xa9, 0xb0, 0x54, 0x79, 0x13, 0x1f, 0xc9, 0xa3, 0x8f, 0x60, 0xea, 0x73, 0x98, 0x18, 0x16,
+	0x28, 0xa8, 0xed, 0x7f, 0x9b, 0x99, 0xfd, 0xe6, 0xfb, 0x66, 0xe7, 0x1b, 0xb8, 0xe5, 0xe7, 0x22,
+	0xe2, 0xf6, 0x2e, 0x4d, 0x44, 0x42, 0xfa, 0x5b, 0x9f, 0xf1, 0x30, 0xce, 0xbf, 0x9a, 0x0f, 0x36,
+	0x49, 0xb2, 0x89, 0x71, 0x2c, 0xeb, 0xab, 0x3c, 0x1c, 0xe3, 0x76, 0x27, 0x8a, 0x0a, 0x46, 0x5f,
+	0xc3, 0x60, 0x1a, 0x04, 0x98, 0x65, 0xb3, 0xe2, 0x1d, 0x16, 0x1e, 0x7e, 0x26, 0xf7, 0xa0, 0x27,
+	0x92, 0x4f, 0xc8, 0x0d, 0xf5, 0xb1, 0xfa, 0xf4, 0xd2, 0xab, 0x12, 0x32, 0x02, 0x2d, 0x88, 0x7c,
+	0xee, 0x3a, 0xc6, 0x0d, 0x59, 0xae, 0x33, 0xfa, 0x08, 0x2e, 0x96, 0x11, 0xe3, 0x1b, 0xd7, 0x29,
+	0x1b, 0xf7, 0x7e, 0x9c, 0x63, 0xd3, 0x28, 0x13, 0x3a, 0x05, 0xbd, 0x11, 0x70, 0x9d, 0x92, 0xdf,
+	0x80, 0x0b, 0x51, 0x75, 0xd4, 0xc0, 0x26, 0x3d, 0xab, 0xf1, 0x10, 0x7a, 0x4b, 0x39, 0xc4, 0x69,
+	0x05, 0x0b, 0xb4, 0x0f, 0x19, 0xa6, 0x67, 0x27, 0x78, 0x09, 0x7d, 0x37, 0xcb, 0x72, 0x2c, 0xc5,
+	0x47, 0xa0, 0xb1, 0x32, 0x4e, 0x6b, 0x48, 0x9d, 0x11, 0x02, 0x37, 0x45, 0xb1, 0x43, 0x29, 0xac,
+	0x7b, 0x32, 0xa6, 0x4f, 0xe0, 0x72, 0x1e, 0xf9, 0x9c, 0x63, 0x7c, 0x96, 0xfa, 0x15, 0x68, 0x8b,
+	0x20, 0xc2, 0xad, 0x7f, 0x24, 0xa8, 0x9e, 0x65, 0x4c, 0x2c, 0x80, 0x35, 0x86, 0x8c, 0x33, 0xc1,
+	0x12, 0x2e, 0xa9, 0xaf, 0xbc, 0x4e, 0x65, 0xf2, 0x5b, 0x05, 0x5d, 0x2e, 0x2f, 0x5b, 0x60, 0xba,
+	0x67, 0x01, 0x92, 0x37, 0x30, 0x98, 0xfb, 0xbc, 0x63, 0x08, 0x31, 0xec, 0xc6, 0x47, 0xfb, 0x6f,
+	0x9f, 0xcc, 0x3b, 0xed, 0x4b, 0xed, 0x00, 0x55, 0xc8, 0x0c, 0xf4, 0x0e, 0x81, 0xeb, 0x90, 0xfb,
+	0xff, 0xf7, 0x4b, 0x1b, 0xcc, 0x91, 0x5d, 0x9d, 0x85, 0xdd, 0x9c, 0x85, 0xfd, 0xb6, 0x3c, 0x0b,
+	0xaa, 0x90, 0x67, 0xd0, 0x77, 0xd7, 0xc8, 0x05, 0x0b, 0x0b, 0x72, 0xbb, 0x23, 0x52, 0x5a, 0x70,
+	0x5a, 0xf5, 0x05, 0xc0, 0x47, 0x86, 0x5f, 0xea, 0x55, 0xdc, 0x6d, 0x21, 0xc7, 0xfd, 0x99, 0xc3,
+	0xb6, 0x58, 0xc1, 0xa8, 0x32, 0x49, 0xe0, 0x6a, 0x9a, 0x8b, 0xe8, 0x7d, 0xf3, 0x7b, 0x1b, 0x7a,
+	0xd2, 0x28, 0x42, 0x5a, 0x70, 0xe3, 0x9c, 0xf9, 0xef, 0x24, 0x54, 0x21, 0xe3, 0xeb, 0x06, 0xed,
+	0x08, 0x56, 0xd7, 0x41, 0x95, 0xd9, 0xf0, 0xfb, 0xc1, 0x52, 0x7f, 0x1c, 0x2c, 0xf5, 0xe7, 0xc1,
+	0x52, 0xbf, 0xfd, 0xb2, 0x94, 0x95, 0x26, 0x7f, 0xff, 0xfc, 0xcf, 0x00, 0xfd, 0x80, 0x7c, 0x20,
+	0x3b, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -389,6 +499,7 @@ type ThingsServiceClient interface {
 	CanAccessByKey(ctx context.Context, in *AccessByKeyReq, opts ...grpc.CallOption) (*ThingID, error)
 	CanAccessByID(ctx context.Context, in *AccessByIDReq, opts ...grpc.CallOption) (*empty.Empty, error)
 	Identify(ctx context.Context, in *Token, opts ...grpc.CallOption) (*ThingID, error)
+	ViewSchema(ctx context.Context, in *ChannelID, opts ...grpc.CallOption) (*Schema, error)
 }
 
 type thingsServiceClient struct {
@@ -426,11 +537,21 @@ func (c *thingsServiceClient) Identify(ctx context.Context, in *Token, opts ...g
 	return out, nil
 }
 
+func (c *thingsServiceClient) ViewSchema(ctx context.Context, in *ChannelID, opts ...grpc.CallOption) (*Schema, error) {
+	out := new(Schema)
+	err := c.cc.Invoke(ctx, "/mainflux.ThingsService/ViewSchema", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ThingsServiceServer is the server API for ThingsService service.
 type ThingsServiceServer interface {
 	CanAccessByKey(context.Context, *AccessByKeyReq) (*ThingID, error)
 	CanAccessByID(context.Context, *AccessByIDReq) (*empty.Empty, error)
 	Identify(context.Context, *Token) (*ThingID, error)
+	ViewSchema(context.Context, *ChannelID) (*Schema, error)
 }
 
 // UnimplementedThingsServiceServer can be embedded to have forward compatible implementations.
@@ -446,6 +567,9 @@ func (*UnimplementedThingsServiceServer) CanAccessByID(ctx context.Context, req
 func (*UnimplementedThingsServiceServer) Identify(ctx context.Context, req *Token) (*ThingID, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Identify not implemented")
 }
+func (*UnimplementedThingsServiceServer) ViewSchema(ctx context.Context, req *ChannelID) (*Schema, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ViewSchema not implemented")
+}
 
 func RegisterThingsServiceServer(s *grpc.Server, srv ThingsServiceServer) {
 	s.RegisterService(&_ThingsService_serviceDesc, srv)
@@ -505,6 +629,24 @@ func _ThingsService_Identify_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ThingsService_ViewSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsServiceServer).ViewSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mainflux.ThingsService/ViewSchema",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsServiceServer).ViewSchema(ctx, req.(*ChannelID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _ThingsService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "mainflux.ThingsService",
 	HandlerType: (*ThingsServiceServer)(nil),
@@ -521,6 +663,10 @@ var _ThingsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Identify",
 			Handler:    _ThingsService_Identify_Handler,
 		},
+		{
+			MethodName: "ViewSchema",
+			Handler:    _ThingsService_ViewSchema_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "authn.proto",
@@ -857,6 +1003,81 @@ func (m *IssueReq) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *ChannelID) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChannelID) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ChannelID) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Schema) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Schema) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Schema) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Definition) > 0 {
+		i -= len(m.Definition)
+		copy(dAtA[i:], m.Definition)
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Definition)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Type) > 0 {
+		i -= len(m.Type)
+		copy(dAtA[i:], m.Type)
+		i = encodeVarintAuthn(dAtA, i, uint64(len(m.Type)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintAuthn(dAtA []byte, offset int, v uint64) int {
 	offset -= sovAuthn(v)
 	base := offset
@@ -975,6 +1196,42 @@ func (m *IssueReq) Size() (n int) {
 	return n
 }
 
+func (m *ChannelID) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *Schema) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Type)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
+	l = len(m.Definition)
+	if l > 0 {
+		n += 1 + l + sovAuthn(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovAuthn(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1580,6 +1837,212 @@ func (m *IssueReq) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ChannelID) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAuthn
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChannelID: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChannelID: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAuthn(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Schema) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAuthn
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Schema: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Schema: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Type", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Type = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Definition", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuthn
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Definition = append(m.Definition[:0], dAtA[iNdEx:postIndex]...)
+			if m.Definition == nil {
+				m.Definition = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAuthn(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthAuthn
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipAuthn(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package broker
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Headers set by the adapters and the services.
+const (
+	// HeaderTraceID is the trace ID of the message.
+	HeaderTraceID = "trace_id"
+
+	// HeaderClientID is the ID of the client connection of the publisher.
+	HeaderClientID = "client_id"
+
+	// HeaderQoS is the delivery guarantee the message was published with.
+	HeaderQoS = "qos"
+
+	// HeaderFirmware is the firmware version of the publishing device.
+	HeaderFirmware = "firmware"
+
+	// HeaderRemoteAddr is the network address the message was received from.
+	HeaderRemoteAddr = "remote_addr"
+
+	// HeaderEventType is the type of the event the message notifies of, it's
+	// set on the events published by the services, e.g. the presence events.
+	HeaderEventType = "event_type"
+)
+
+// SubtopicCommands is the subtopic namespace of the commands sent to the
+// things and of their replies, the messages in it aren't telemetry.
+const SubtopicCommands = "commands"
+
+// IsCommand returns true if the subtopic is in the commands namespace.
+func IsCommand(subtopic string) bool {
+	return subtopic == SubtopicCommands || strings.HasPrefix(subtopic, SubtopicCommands+".")
+}
+
+// Created returns the created timestamp of the message received now.
+func Created() int64 {
+	return time.Now().UnixNano()
+}
+
+// complete sets the ID and the created timestamp of the message if the
+// adapter didn't.
+func complete(msg *Message) error {
+	if msg.Id == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return err
+		}
+		msg.Id = id.String()
+	}
+	if msg.Created == 0 {
+		msg.Created = Created()
+	}
+	return nil
+}
@@ -24,15 +24,23 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 // Message represents a message emitted by the Mainflux adapters layer.
 type Message struct {
-	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
-	Subtopic             string   `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
-	Publisher            string   `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
-	Protocol             string   `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	ContentType          string   `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
-	Payload              []byte   `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Channel     string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Subtopic    string `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	Publisher   string `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Protocol    string `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	ContentType string `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Payload     []byte `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	// ID uniquely identifies the message.
+	Id string `protobuf:"bytes,7,opt,name=id,proto3" json:"id,omitempty"`
+	// Created is the time the message was received by the adapter, in
+	// nanoseconds since the Unix epoch.
+	Created int64 `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	// Headers are the metadata of the message, e.g. trace IDs, device
+	// firmware or QoS.
+	Headers              map[string]string `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Message) Reset()         { *m = Message{} }
@@ -110,26 +118,54 @@ func (m *Message) GetPayload() []byte {
 	return nil
 }
 
+func (m *Message) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Message) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Message) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "broker.Message")
+	proto.RegisterMapType((map[string]string)(nil), "broker.Message.HeadersEntry")
 }
 
 func init() { proto.RegisterFile("broker/message.proto", fileDescriptor_6357da820a7eacc2) }
 
 var fileDescriptor_6357da820a7eacc2 = []byte{
-	// 190 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x49, 0x2a, 0xca, 0xcf,
-	0x4e, 0x2d, 0xd2, 0xcf, 0x4d, 0x2d, 0x2e, 0x4e, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9,
-	0x17, 0x62, 0x83, 0x88, 0x2a, 0x6d, 0x65, 0xe4, 0x62, 0xf7, 0x85, 0xc8, 0x08, 0x49, 0x70, 0xb1,
-	0x27, 0x67, 0x24, 0xe6, 0xe5, 0xa5, 0xe6, 0x48, 0x30, 0x2a, 0x30, 0x6a, 0x70, 0x06, 0xc1, 0xb8,
-	0x42, 0x52, 0x5c, 0x1c, 0xc5, 0xa5, 0x49, 0x25, 0xf9, 0x05, 0x99, 0xc9, 0x12, 0x4c, 0x60, 0x29,
-	0x38, 0x5f, 0x48, 0x86, 0x8b, 0xb3, 0xa0, 0x34, 0x29, 0x27, 0xb3, 0x38, 0x23, 0xb5, 0x48, 0x82,
-	0x19, 0x2c, 0x89, 0x10, 0x00, 0xe9, 0x04, 0x5b, 0x98, 0x9c, 0x9f, 0x23, 0xc1, 0x02, 0xd1, 0x09,
-	0xe3, 0x0b, 0x29, 0x70, 0x71, 0x27, 0xe7, 0xe7, 0x95, 0xa4, 0xe6, 0x95, 0x84, 0x54, 0x16, 0xa4,
-	0x4a, 0xb0, 0x82, 0xa5, 0x91, 0x85, 0x40, 0x2e, 0x2a, 0x48, 0xac, 0xcc, 0xc9, 0x4f, 0x4c, 0x91,
-	0x60, 0x53, 0x60, 0xd4, 0xe0, 0x09, 0x82, 0x71, 0x9d, 0x04, 0x4e, 0x3c, 0x92, 0x63, 0xbc, 0xf0,
-	0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x19, 0x8f, 0xe5, 0x18, 0x92, 0xd8, 0xc0, 0xe6, 0x1a,
-	0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0x12, 0xd5, 0x64, 0x47, 0xf0, 0x00, 0x00, 0x00,
+	// 280 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xd1, 0x4a, 0x84, 0x40,
+	0x14, 0x86, 0x1b, 0x6d, 0x75, 0x3d, 0xbb, 0xc4, 0x32, 0xec, 0xc5, 0xb0, 0x2c, 0x22, 0x5d, 0x79,
+	0x65, 0x50, 0x10, 0xb1, 0x97, 0x41, 0xd0, 0x4d, 0x37, 0xd2, 0x0b, 0x8c, 0x7a, 0x48, 0x59, 0x73,
+	0x64, 0x1c, 0x03, 0xdf, 0xa4, 0x47, 0xea, 0x32, 0xe8, 0x05, 0xc2, 0x5e, 0x24, 0x9c, 0x71, 0x6a,
+	0xef, 0xfc, 0xce, 0xe7, 0xcf, 0x9c, 0xff, 0xc0, 0x36, 0x93, 0xe2, 0x88, 0xf2, 0xea, 0x15, 0xbb,
+	0x8e, 0xbf, 0x60, 0xd2, 0x4a, 0xa1, 0x04, 0xf5, 0xcc, 0xf4, 0xf2, 0xcb, 0x01, 0xff, 0xc9, 0x18,
+	0xca, 0xc0, 0xcf, 0x4b, 0xde, 0x34, 0x58, 0x33, 0x12, 0x91, 0x38, 0x48, 0x2d, 0xd2, 0x1d, 0x2c,
+	0xbb, 0x3e, 0x53, 0xa2, 0xad, 0x72, 0xe6, 0x68, 0xf5, 0xc7, 0x74, 0x0f, 0x41, 0xdb, 0x67, 0x75,
+	0xd5, 0x95, 0x28, 0x99, 0xab, 0xe5, 0xff, 0x60, 0x4a, 0xea, 0x07, 0x73, 0x51, 0xb3, 0x73, 0x93,
+	0xb4, 0x4c, 0x23, 0x58, 0xe5, 0xa2, 0x51, 0xd8, 0xa8, 0xe7, 0xa1, 0x45, 0xb6, 0xd0, 0xfa, 0x74,
+	0x34, 0x6d, 0xd4, 0xf2, 0xa1, 0x16, 0xbc, 0x60, 0x5e, 0x44, 0xe2, 0x75, 0x6a, 0x91, 0x5e, 0x80,
+	0x53, 0x15, 0xcc, 0xd7, 0x11, 0xa7, 0x2a, 0xf4, 0xee, 0x12, 0xb9, 0xc2, 0x82, 0x2d, 0x23, 0x12,
+	0xbb, 0xa9, 0x45, 0x7a, 0x0b, 0x7e, 0x89, 0xbc, 0x40, 0xd9, 0xb1, 0x20, 0x72, 0xe3, 0xd5, 0xf5,
+	0x3e, 0x31, 0xdd, 0x93, 0xb9, 0x77, 0xf2, 0x68, 0xf4, 0x43, 0xa3, 0xe4, 0x90, 0xda, 0x9f, 0x77,
+	0x07, 0x58, 0x9f, 0x0a, 0xba, 0x01, 0xf7, 0x88, 0xc3, 0x7c, 0x99, 0xe9, 0x93, 0x6e, 0x61, 0xf1,
+	0xc6, 0xeb, 0x1e, 0xe7, 0x93, 0x18, 0x38, 0x38, 0x77, 0xe4, 0x7e, 0xf3, 0x31, 0x86, 0xe4, 0x73,
+	0x0c, 0xc9, 0xf7, 0x18, 0x92, 0xf7, 0x9f, 0xf0, 0x2c, 0xf3, 0x74, 0xeb, 0x9b, 0xdf, 0x01, 0x00,
+	0x04, 0xce, 0xa8, 0x1f, 0x8e, 0x01, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -156,6 +192,37 @@ func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Headers) > 0 {
+		for k := range m.Headers {
+			v := m.Headers[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMessage(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMessage(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMessage(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.Created != 0 {
+		i = encodeVarintMessage(dAtA, i, uint64(m.Created))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if len(m.Payload) > 0 {
 		i -= len(m.Payload)
 		copy(dAtA[i:], m.Payload)
@@ -242,6 +309,21 @@ func (m *Message) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Created != 0 {
+		n += 1 + sovMessage(uint64(m.Created))
+	}
+	if len(m.Headers) > 0 {
+		for k, v := range m.Headers {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMessage(uint64(mapEntrySize))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -477,6 +559,184 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			}
+			m.Created = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Created |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Headers == nil {
+				m.Headers = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessage
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMessage(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Headers[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
@@ -14,7 +14,8 @@ import (
 
 // Nats specifies a NATS message API.
 type Nats interface {
-	// Publish publishes message to the msessage broker.
+	// Publish publishes message to the msessage broker. The message is
+	// given an ID and a created timestamp unless it has them.
 	Publish(context.Context, string, Message) error
 
 	// Subscribe subscribes to a message broker subject.
@@ -59,6 +60,10 @@ func New(url string) (Nats, error) {
 }
 
 func (b broker) Publish(_ context.Context, _ string, msg Message) error {
+	if err := complete(&msg); err != nil {
+		return errors.Wrap(errNatsPub, err)
+	}
+
 	data, err := proto.Marshal(&msg)
 	if err != nil {
 		return err
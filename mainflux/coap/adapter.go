@@ -8,7 +8,6 @@ package coap
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -16,6 +15,8 @@ import (
 	"github.com/gogo/protobuf/proto"
 	"github.com/cloustone/pandas/mainflux"
 	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/schema"
+	"github.com/cloustone/pandas/pkg/errors"
 	"github.com/cloustone/pandas/pkg/logger"
 	"github.com/nats-io/nats.go"
 )
@@ -126,8 +127,10 @@ func (svc *adapterService) listenResponses(responses <-chan string) {
 
 func (svc *adapterService) Publish(ctx context.Context, token string, msg broker.Message) error {
 	if err := svc.broker.Publish(ctx, token, msg); err != nil {
-		switch err {
-		case nats.ErrConnectionClosed, nats.ErrInvalidConnection:
+		switch {
+		case errors.Contains(err, schema.ErrInvalidMessage):
+			return err
+		case err == nats.ErrConnectionClosed, err == nats.ErrInvalidConnection:
 			return ErrFailedConnection
 		default:
 			return ErrFailedMessagePublish
@@ -7,7 +7,6 @@ import (
 	"bytes"
 	"context"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"net"
 	"net/http"
@@ -20,7 +19,9 @@ import (
 	"github.com/cloustone/pandas/mainflux"
 	"github.com/cloustone/pandas/mainflux/broker"
 	"github.com/cloustone/pandas/mainflux/coap"
+	"github.com/cloustone/pandas/mainflux/schema"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
 	log "github.com/cloustone/pandas/pkg/logger"
 	gocoap "github.com/dustin/go-coap"
 	"github.com/go-zoo/bone"
@@ -87,7 +88,7 @@ func mux(svc coap.Service, responses chan<- string) gocoap.Handler {
 		case gocoap.GET:
 			return observe(svc, responses)(conn, addr, msg)
 		default:
-			return receive(svc, msg)
+			return receive(svc, addr, msg)
 		}
 	})
 }
@@ -185,7 +186,7 @@ func fmtSubtopic(msg *gocoap.Message) (string, error) {
 	return subtopic, nil
 }
 
-func receive(svc coap.Service, msg *gocoap.Message) *gocoap.Message {
+func receive(svc coap.Service, addr *net.UDPAddr, msg *gocoap.Message) *gocoap.Message {
 	// By default message is NonConfirmable, so
 	// NonConfirmable response is sent back.
 	res := &gocoap.Message{
@@ -231,6 +232,10 @@ func receive(svc coap.Service, msg *gocoap.Message) *gocoap.Message {
 		return res
 	}
 
+	qos := "non-confirmable"
+	if msg.IsConfirmable() {
+		qos = "confirmable"
+	}
 	m := broker.Message{
 		Channel:     chanID,
 		Subtopic:    subtopic,
@@ -238,10 +243,18 @@ func receive(svc coap.Service, msg *gocoap.Message) *gocoap.Message {
 		ContentType: ct,
 		Protocol:    protocol,
 		Payload:     msg.Payload,
+		Created:     broker.Created(),
+		Headers: map[string]string{
+			broker.HeaderQoS:        qos,
+			broker.HeaderRemoteAddr: addr.String(),
+		},
 	}
 
 	if err := svc.Publish(context.Background(), "", m); err != nil {
 		res.Code = gocoap.InternalServerError
+		if errors.Contains(err, schema.ErrInvalidMessage) {
+			res.Code = gocoap.BadRequest
+		}
 	}
 
 	return res
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/opentracing/opentracing-go/mocktracer"
 
@@ -17,6 +18,7 @@ import (
 	adapter "github.com/cloustone/pandas/mainflux/http"
 	"github.com/cloustone/pandas/mainflux/http/api"
 	"github.com/cloustone/pandas/mainflux/http/mocks"
+	"github.com/cloustone/pandas/mainflux/schema"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -129,3 +131,55 @@ func TestPublish(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestPublishWithSchema(t *testing.T) {
+	chanID := "1"
+	contentType := "application/senml+json"
+	token := "auth_token"
+	schemas := map[string]*mainflux.Schema{
+		chanID: {
+			Type:       schema.SenML,
+			Definition: []byte(`{"fields":{"current":{"type":"float","min":0,"max":10,"required":true}}}`),
+		},
+	}
+	thingsClient := mocks.NewThingsClientWithSchemas(map[string]string{token: chanID}, schemas)
+	pub := schema.NewPublisher(mocks.NewPublisher(), schema.NewValidator(thingsClient, time.Minute))
+	ts := newHTTPServer(adapter.New(pub, thingsClient))
+	defer ts.Close()
+
+	cases := map[string]struct {
+		msg    string
+		status int
+	}{
+		"publish valid message": {
+			msg:    `[{"n":"current","t":-1,"v":1.6}]`,
+			status: http.StatusAccepted,
+		},
+		"publish message with value out of range": {
+			msg:    `[{"n":"current","t":-1,"v":11}]`,
+			status: http.StatusBadRequest,
+		},
+		"publish message without required field": {
+			msg:    `[{"n":"voltage","t":-1,"v":220}]`,
+			status: http.StatusBadRequest,
+		},
+		"publish malformed message": {
+			msg:    `[{"n":"current","t":-1,"v":1.6}`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/messages", ts.URL, chanID),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.msg),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", desc, tc.status, res.StatusCode))
+	}
+}
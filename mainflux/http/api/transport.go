@@ -5,7 +5,6 @@ package api
 
 import (
 	"context"
-	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -17,6 +16,8 @@ import (
 	"github.com/cloustone/pandas/mainflux"
 	"github.com/cloustone/pandas/mainflux/broker"
 	adapter "github.com/cloustone/pandas/mainflux/http"
+	"github.com/cloustone/pandas/mainflux/schema"
+	"github.com/cloustone/pandas/pkg/errors"
 	kitot "github.com/go-kit/kit/tracing/opentracing"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
@@ -26,7 +27,16 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-const protocol = "http"
+const (
+	protocol = "http"
+
+	// messageIDHeader is the HTTP header of the ID of the published message.
+	messageIDHeader = "X-Message-Id"
+
+	// headerPrefix prefixes the HTTP headers carried as the message headers,
+	// e.g. X-Message-Header-Trace-Id is carried as trace_id.
+	headerPrefix = "X-Message-Header-"
+)
 
 var (
 	errMalformedData     = errors.New("malformed request data")
@@ -109,12 +119,17 @@ func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	}
 
 	ct := r.Header.Get("Content-Type")
+	headers := decodeHeaders(r.Header)
+	headers[broker.HeaderRemoteAddr] = r.RemoteAddr
 	msg := broker.Message{
 		Protocol:    protocol,
 		ContentType: ct,
 		Channel:     chanID,
 		Subtopic:    subtopic,
 		Payload:     payload,
+		Id:          r.Header.Get(messageIDHeader),
+		Created:     broker.Created(),
+		Headers:     headers,
 	}
 
 	req := publishReq{
@@ -125,6 +140,18 @@ func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeHeaders(h http.Header) map[string]string {
+	headers := make(map[string]string)
+	for k, v := range h {
+		if !strings.HasPrefix(k, headerPrefix) || len(v) == 0 {
+			continue
+		}
+		key := strings.ToLower(strings.Replace(strings.TrimPrefix(k, headerPrefix), "-", "_", -1))
+		headers[key] = v[0]
+	}
+	return headers
+}
+
 func decodePayload(body io.ReadCloser) ([]byte, error) {
 	payload, err := ioutil.ReadAll(body)
 	if err != nil {
@@ -141,6 +168,11 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 }
 
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	if errors.Contains(err, schema.ErrInvalidMessage) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	switch err {
 	case errMalformedData, errMalformedSubtopic:
 		w.WriteHeader(http.StatusBadRequest)
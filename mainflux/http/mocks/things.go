@@ -19,12 +19,19 @@ var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
 const ServiceErrToken = "unavailable"
 
 type thingsClient struct {
-	things map[string]string
+	things  map[string]string
+	schemas map[string]*mainflux.Schema
 }
 
 // NewThingsClient returns mock implementation of things service client.
 func NewThingsClient(data map[string]string) mainflux.ThingsServiceClient {
-	return &thingsClient{data}
+	return &thingsClient{things: data}
+}
+
+// NewThingsClientWithSchemas returns mock implementation of things service
+// client serving the schemas of the channels.
+func NewThingsClientWithSchemas(data map[string]string, schemas map[string]*mainflux.Schema) mainflux.ThingsServiceClient {
+	return &thingsClient{things: data, schemas: schemas}
 }
 
 func (tc thingsClient) CanAccessByKey(ctx context.Context, req *mainflux.AccessByKeyReq, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
@@ -56,3 +63,12 @@ func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, .
 func (tc thingsClient) Identify(ctx context.Context, req *mainflux.Token, opts ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (tc thingsClient) ViewSchema(ctx context.Context, req *mainflux.ChannelID, opts ...grpc.CallOption) (*mainflux.Schema, error) {
+	schema, ok := tc.schemas[req.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "entity does not exist")
+	}
+
+	return schema, nil
+}
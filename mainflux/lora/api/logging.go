@@ -117,3 +117,16 @@ func (lm loggingMiddleware) Publish(ctx context.Context, token string, m lora.Me
 
 	return lm.svc.Publish(ctx, token, m)
 }
+
+func (lm loggingMiddleware) SendDownlink(ctx context.Context, thingID, chanID string, d lora.Downlink) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("send_downlink for thing %s and channel %s took %s to complete", thingID, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SendDownlink(ctx, thingID, chanID, d)
+}
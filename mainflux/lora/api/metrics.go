@@ -90,3 +90,12 @@ func (mm *metricsMiddleware) Publish(ctx context.Context, token string, m lora.M
 
 	return mm.svc.Publish(ctx, token, m)
 }
+
+func (mm *metricsMiddleware) SendDownlink(ctx context.Context, thingID, chanID string, d lora.Downlink) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "send_downlink").Add(1)
+		mm.latency.With("method", "send_downlink").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SendDownlink(ctx, thingID, chanID, d)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloustone/pandas/mainflux/lora"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+const downlinkTopic = "application/%s/device/%s/tx"
+
+var _ lora.DownlinkPublisher = (*publisher)(nil)
+
+type publisher struct {
+	client mqtt.Client
+}
+
+// NewPublisher returns the publisher of the downlinks to the Lora MQTT
+// broker.
+func NewPublisher(client mqtt.Client) lora.DownlinkPublisher {
+	return publisher{client: client}
+}
+
+// Publish publishes the downlink to the Lora MQTT broker
+func (p publisher) Publish(appID, devEUI string, d lora.Downlink) error {
+	payload, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	t := p.client.Publish(fmt.Sprintf(downlinkTopic, appID, devEUI), 0, false, payload)
+	if t.Wait() && t.Error() != nil {
+		return t.Error()
+	}
+
+	return nil
+}
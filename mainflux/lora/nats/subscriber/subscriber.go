@@ -0,0 +1,120 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/lora"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/gogo/protobuf/proto"
+	nats "github.com/nats-io/nats.go"
+)
+
+const (
+	queue       = "lora"
+	protocol    = "lora"
+	contentType = "application/json"
+
+	// The command params carrying the downlink.
+	paramData      = "data"
+	paramFPort     = "f_port"
+	paramConfirmed = "confirmed"
+
+	defFPort = 1
+)
+
+// Subscriber is used to intercept the commands sent to the LoRa devices and
+// forward them to the Lora MQTT broker as downlinks.
+type Subscriber struct {
+	broker broker.Nats
+	svc    lora.Service
+	logger log.Logger
+}
+
+// NewSubscriber instances Subscriber strucure.
+func NewSubscriber(b broker.Nats, svc lora.Service, logger log.Logger) *Subscriber {
+	return &Subscriber{
+		broker: b,
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// Subscribe subscribes to the commands of all things.
+func (s *Subscriber) Subscribe() error {
+	_, err := s.broker.QueueSubscribe(commands.CommandsSubject, queue, s.handleMsg)
+	return err
+}
+
+func (s *Subscriber) handleMsg(m *nats.Msg) {
+	var msg broker.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
+		return
+	}
+
+	thingID, reply, ok := commands.ParseSubtopic(msg.Subtopic)
+	if !ok || reply {
+		return
+	}
+
+	var req commands.Request
+	if err := json.Unmarshal(msg.Payload, &req); err != nil {
+		s.logger.Warn(fmt.Sprintf("Malformed command of thing %s: %s", thingID, err))
+		return
+	}
+
+	err := s.svc.SendDownlink(context.Background(), thingID, msg.Channel, toDownlink(req.Params))
+	switch err {
+	case nil:
+		s.reply(msg.Channel, thingID, commands.Reply{ID: req.ID, Status: commands.StatusDelivered})
+	case lora.ErrNotFoundDev:
+		// The thing isn't a LoRa device.
+	default:
+		s.reply(msg.Channel, thingID, commands.Reply{ID: req.ID, Status: commands.StatusFailed, Error: err.Error()})
+	}
+}
+
+// reply publishes the reply to the command on behalf of the thing.
+func (s *Subscriber) reply(chanID, thingID string, r commands.Reply) {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Marshalling reply failed: %s", err))
+		return
+	}
+
+	msg := broker.Message{
+		Channel:     chanID,
+		Subtopic:    commands.ReplySubtopic(thingID),
+		Publisher:   thingID,
+		Protocol:    protocol,
+		ContentType: contentType,
+		Payload:     payload,
+	}
+	if err := s.broker.Publish(context.Background(), "", msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Publishing reply to command %s failed: %s", r.ID, err))
+	}
+}
+
+// toDownlink returns the downlink carried by the command params, the data
+// is base64 encoded.
+func toDownlink(params map[string]interface{}) lora.Downlink {
+	d := lora.Downlink{FPort: defFPort}
+	if data, ok := params[paramData].(string); ok {
+		d.Data = data
+	}
+	if fPort, ok := params[paramFPort].(float64); ok {
+		d.FPort = int(fPort)
+	}
+	if confirmed, ok := params[paramConfirmed].(bool); ok {
+		d.Confirmed = confirmed
+	}
+
+	return d
+}
@@ -53,6 +53,16 @@ func (mr *routerMap) Get(mfxID string) (string, error) {
 	return mval, nil
 }
 
+func (mr *routerMap) GetLora(mfxID string) (string, error) {
+	mKey := fmt.Sprintf("%s:%s:%s", mr.prefix, mfxMapPrefix, mfxID)
+	lval, err := mr.client.Get(mKey).Result()
+	if err != nil {
+		return "", err
+	}
+
+	return lval, nil
+}
+
 func (mr *routerMap) Remove(mfxID string) error {
 	mkey := fmt.Sprintf("%s:%s:%s", mr.prefix, mfxMapPrefix, mfxID)
 	lval, err := mr.client.Get(mkey).Result()
@@ -11,6 +11,10 @@ type RouteMapRepository interface {
 	// Channel returns mainflux channel for given lora application.
 	Get(string) (string, error)
 
+	// GetLora returns lora application or device for given mainflux
+	// channel or thing.
+	GetLora(string) (string, error)
+
 	// Removes mapping from cache.
 	Remove(string) error
 }
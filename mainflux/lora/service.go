@@ -5,14 +5,18 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"strconv"
 
 	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
 )
 
 const (
 	protocol      = "lora"
 	thingSuffix   = "thing"
 	channelSuffix = "channel"
+	headerDevEUI  = "dev_eui"
+	headerFCnt    = "f_cnt"
 )
 
 var (
@@ -53,20 +57,33 @@ type Service interface {
 
 	// Publish forwards messages from the LoRa MQTT broker to Mainflux NATS broker
 	Publish(context.Context, string, Message) error
+
+	// SendDownlink forwards the downlink of the thing, sent through the
+	// channel, to the LoRa MQTT broker
+	SendDownlink(context.Context, string, string, Downlink) error
+}
+
+// DownlinkPublisher specifies the API for publishing the downlinks to the
+// LoRa MQTT broker.
+type DownlinkPublisher interface {
+	// Publish publishes the downlink to the device of the lora application.
+	Publish(appID, devEUI string, d Downlink) error
 }
 
 var _ Service = (*adapterService)(nil)
 
 type adapterService struct {
 	broker     broker.Nats
+	downlinks  DownlinkPublisher
 	thingsRM   RouteMapRepository
 	channelsRM RouteMapRepository
 }
 
 // New instantiates the LoRa adapter implementation.
-func New(broker broker.Nats, thingsRM, channelsRM RouteMapRepository) Service {
+func New(broker broker.Nats, downlinks DownlinkPublisher, thingsRM, channelsRM RouteMapRepository) Service {
 	return &adapterService{
 		broker:     broker,
+		downlinks:  downlinks,
 		thingsRM:   thingsRM,
 		channelsRM: channelsRM,
 	}
@@ -107,14 +124,40 @@ func (as *adapterService) Publish(ctx context.Context, token string, m Message)
 	msg := broker.Message{
 		Publisher:   thing,
 		Protocol:    protocol,
-		ContentType: "Content-Type",
+		ContentType: senml.JSON,
 		Channel:     channel,
 		Payload:     payload,
+		Created:     broker.Created(),
+		Headers: map[string]string{
+			headerDevEUI: m.DevEUI,
+			headerFCnt:   strconv.Itoa(m.FCnt),
+		},
 	}
 
 	return as.broker.Publish(ctx, token, msg)
 }
 
+// SendDownlink forwards the downlink to the Lora MQTT broker
+func (as *adapterService) SendDownlink(ctx context.Context, thingID, chanID string, d Downlink) error {
+	// Get route map of mainflux thing
+	devEUI, err := as.thingsRM.GetLora(thingID)
+	if err != nil {
+		return ErrNotFoundDev
+	}
+
+	// Get route map of mainflux channel
+	appID, err := as.channelsRM.GetLora(chanID)
+	if err != nil {
+		return ErrNotFoundApp
+	}
+
+	if _, err := base64.StdEncoding.DecodeString(d.Data); d.Data == "" || err != nil {
+		return ErrMalformedMessage
+	}
+
+	return as.downlinks.Publish(appID, devEUI, d)
+}
+
 func (as *adapterService) CreateThing(mfxDevID string, loraDevEUI string) error {
 	return as.thingsRM.Save(mfxDevID, loraDevEUI)
 }
@@ -24,15 +24,23 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 // Message represents a message emitted by the Mainflux adapters layer.
 type Message struct {
-	Channel              string   `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
-	Subtopic             string   `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
-	Publisher            string   `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
-	Protocol             string   `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
-	ContentType          string   `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
-	Payload              []byte   `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Channel     string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	Subtopic    string `protobuf:"bytes,2,opt,name=subtopic,proto3" json:"subtopic,omitempty"`
+	Publisher   string `protobuf:"bytes,3,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Protocol    string `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	ContentType string `protobuf:"bytes,5,opt,name=contentType,proto3" json:"contentType,omitempty"`
+	Payload     []byte `protobuf:"bytes,6,opt,name=payload,proto3" json:"payload,omitempty"`
+	// ID uniquely identifies the message.
+	Id string `protobuf:"bytes,7,opt,name=id,proto3" json:"id,omitempty"`
+	// Created is the time the message was received by the adapter, in
+	// nanoseconds since the Unix epoch.
+	Created int64 `protobuf:"varint,8,opt,name=created,proto3" json:"created,omitempty"`
+	// Headers are the metadata of the message, e.g. trace IDs, device
+	// firmware or QoS.
+	Headers              map[string]string `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *Message) Reset()         { *m = Message{} }
@@ -110,26 +118,54 @@ func (m *Message) GetPayload() []byte {
 	return nil
 }
 
+func (m *Message) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Message) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Message) GetHeaders() map[string]string {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "mainflux.Message")
+	proto.RegisterMapType((map[string]string)(nil), "mainflux.Message.HeadersEntry")
 }
 
 func init() { proto.RegisterFile("message.proto", fileDescriptor_33c57e4bae7b9afd) }
 
 var fileDescriptor_33c57e4bae7b9afd = []byte{
-	// 189 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x4d, 0x2d, 0x2e,
-	0x4e, 0x4c, 0x4f, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0xc8, 0x4d, 0xcc, 0xcc, 0x4b,
-	0xcb, 0x29, 0xad, 0x50, 0xda, 0xca, 0xc8, 0xc5, 0xee, 0x0b, 0x91, 0x13, 0x92, 0xe0, 0x62, 0x4f,
-	0xce, 0x48, 0xcc, 0xcb, 0x4b, 0xcd, 0x91, 0x60, 0x54, 0x60, 0xd4, 0xe0, 0x0c, 0x82, 0x71, 0x85,
-	0xa4, 0xb8, 0x38, 0x8a, 0x4b, 0x93, 0x4a, 0xf2, 0x0b, 0x32, 0x93, 0x25, 0x98, 0xc0, 0x52, 0x70,
-	0xbe, 0x90, 0x0c, 0x17, 0x67, 0x41, 0x69, 0x52, 0x4e, 0x66, 0x71, 0x46, 0x6a, 0x91, 0x04, 0x33,
-	0x58, 0x12, 0x21, 0x00, 0xd2, 0x09, 0xb6, 0x32, 0x39, 0x3f, 0x47, 0x82, 0x05, 0xa2, 0x13, 0xc6,
-	0x17, 0x52, 0xe0, 0xe2, 0x4e, 0xce, 0xcf, 0x2b, 0x49, 0xcd, 0x2b, 0x09, 0xa9, 0x2c, 0x48, 0x95,
-	0x60, 0x05, 0x4b, 0x23, 0x0b, 0x81, 0x5c, 0x54, 0x90, 0x58, 0x99, 0x93, 0x9f, 0x98, 0x22, 0xc1,
-	0xa6, 0xc0, 0xa8, 0xc1, 0x13, 0x04, 0xe3, 0x3a, 0x09, 0x9c, 0x78, 0x24, 0xc7, 0x78, 0xe1, 0x91,
-	0x1c, 0xe3, 0x83, 0x47, 0x72, 0x8c, 0x33, 0x1e, 0xcb, 0x31, 0x24, 0xb1, 0x81, 0xcd, 0x35, 0x06,
-	0x04, 0x00, 0x00, 0xff, 0xff, 0x52, 0xc9, 0xcc, 0x65, 0xeb, 0x00, 0x00, 0x00,
+	// 279 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xb1, 0x4e, 0x84, 0x40,
+	0x10, 0x86, 0x5d, 0xf0, 0x0e, 0x98, 0x3b, 0xcd, 0x65, 0x63, 0xb1, 0xb9, 0x18, 0x42, 0xac, 0xa8,
+	0x28, 0xb4, 0xb9, 0x5c, 0x69, 0x62, 0x62, 0x63, 0x43, 0x7c, 0x81, 0x05, 0x46, 0x21, 0xee, 0xed,
+	0x12, 0x58, 0x8c, 0xbc, 0x89, 0x8f, 0x64, 0x69, 0x63, 0x6f, 0xf0, 0x45, 0x0c, 0x0b, 0xab, 0xd7,
+	0xed, 0x37, 0xdf, 0x4c, 0x76, 0xfe, 0x81, 0xb3, 0x03, 0xb6, 0x2d, 0x7f, 0xc6, 0xa4, 0x6e, 0x94,
+	0x56, 0xd4, 0x3f, 0xf0, 0x4a, 0x3e, 0x89, 0xee, 0xed, 0xea, 0xcb, 0x01, 0xef, 0x61, 0x72, 0x94,
+	0x81, 0x97, 0x97, 0x5c, 0x4a, 0x14, 0x8c, 0x44, 0x24, 0x0e, 0x52, 0x8b, 0x74, 0x0b, 0x7e, 0xdb,
+	0x65, 0x5a, 0xd5, 0x55, 0xce, 0x1c, 0xa3, 0xfe, 0x98, 0x5e, 0x42, 0x50, 0x77, 0x99, 0xa8, 0xda,
+	0x12, 0x1b, 0xe6, 0x1a, 0xf9, 0x5f, 0x18, 0x27, 0xcd, 0x97, 0xb9, 0x12, 0xec, 0x74, 0x9a, 0xb4,
+	0x4c, 0x23, 0x58, 0xe5, 0x4a, 0x6a, 0x94, 0xfa, 0xb1, 0xaf, 0x91, 0x2d, 0x8c, 0x3e, 0x2e, 0x8d,
+	0x1b, 0xd5, 0xbc, 0x17, 0x8a, 0x17, 0x6c, 0x19, 0x91, 0x78, 0x9d, 0x5a, 0xa4, 0xe7, 0xe0, 0x54,
+	0x05, 0xf3, 0xcc, 0x88, 0x53, 0x15, 0x66, 0xf7, 0x06, 0xb9, 0xc6, 0x82, 0xf9, 0x11, 0x89, 0xdd,
+	0xd4, 0x22, 0xdd, 0x81, 0x57, 0x22, 0x2f, 0xb0, 0x69, 0x59, 0x10, 0xb9, 0xf1, 0xea, 0x3a, 0x4c,
+	0x6c, 0xfa, 0x64, 0x4e, 0x9e, 0xdc, 0x4f, 0x0d, 0x77, 0x52, 0x37, 0x7d, 0x6a, 0xdb, 0xb7, 0x7b,
+	0x58, 0x1f, 0x0b, 0xba, 0x01, 0xf7, 0x05, 0xfb, 0xf9, 0x36, 0xe3, 0x93, 0x5e, 0xc0, 0xe2, 0x95,
+	0x8b, 0x0e, 0xe7, 0xa3, 0x4c, 0xb0, 0x77, 0x76, 0xe4, 0x76, 0xf3, 0x31, 0x84, 0xe4, 0x73, 0x08,
+	0xc9, 0xf7, 0x10, 0x92, 0xf7, 0x9f, 0xf0, 0x24, 0x5b, 0x9a, 0xdc, 0x37, 0xbf, 0x03, 0x00, 0x8d,
+	0x34, 0xe3, 0x75, 0x8b, 0x01, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -156,6 +192,37 @@ func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Headers) > 0 {
+		for k := range m.Headers {
+			v := m.Headers[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintMessage(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintMessage(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintMessage(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
+	if m.Created != 0 {
+		i = encodeVarintMessage(dAtA, i, uint64(m.Created))
+		i--
+		dAtA[i] = 0x40
+	}
+	if len(m.Id) > 0 {
+		i -= len(m.Id)
+		copy(dAtA[i:], m.Id)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Id)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if len(m.Payload) > 0 {
 		i -= len(m.Payload)
 		copy(dAtA[i:], m.Payload)
@@ -242,6 +309,21 @@ func (m *Message) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovMessage(uint64(l))
 	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Created != 0 {
+		n += 1 + sovMessage(uint64(m.Created))
+	}
+	if len(m.Headers) > 0 {
+		for k, v := range m.Headers {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovMessage(uint64(len(k))) + 1 + len(v) + sovMessage(uint64(len(v)))
+			n += mapEntrySize + 1 + sovMessage(uint64(mapEntrySize))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -477,6 +559,184 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				m.Payload = []byte{}
 			}
 			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			}
+			m.Created = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Created |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Headers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Headers == nil {
+				m.Headers = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMessage
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMessage
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipMessage(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthMessage
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Headers[mapkey] = mapvalue
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
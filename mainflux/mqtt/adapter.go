@@ -15,12 +15,24 @@ import (
 	"github.com/cloustone/pandas/mainflux/broker"
 	"github.com/cloustone/pandas/pkg/logger"
 	"github.com/cloustone/pandas/mainflux/mqtt/redis"
+	"github.com/cloustone/pandas/mainflux/schema"
 	"github.com/mainflux/mproxy/pkg/session"
 	opentracing "github.com/opentracing/opentracing-go"
 )
 
 var _ session.Event = (*Event)(nil)
 
+// The policies applied to the messages which don't conform to the schema of
+// their channel.
+const (
+	// PolicyIgnore forwards the message to the MQTT broker, but doesn't
+	// publish it to the Mainflux.
+	PolicyIgnore = "ignore"
+
+	// PolicyDisconnect disconnects the client.
+	PolicyDisconnect = "disconnect"
+)
+
 var (
 	channelRegExp         = regexp.MustCompile(`^\/?channels\/([\w\-]+)\/messages(\/[^?]*)?(\?.*)?$`)
 	ctRegExp              = regexp.MustCompile(`^(\/.*)?\/ct\/([^\/]+)$`)
@@ -41,15 +53,19 @@ type Event struct {
 	tracer opentracing.Tracer
 	logger logger.Logger
 	es     redis.EventStore
+	schema schema.Validator
+	policy string
 }
 
 // New creates new Event entity
 func New(broker broker.Nats, tc mainflux.ThingsServiceClient, es redis.EventStore,
-	logger logger.Logger, tracer opentracing.Tracer) *Event {
+	validator schema.Validator, policy string, logger logger.Logger, tracer opentracing.Tracer) *Event {
 	return &Event{
 		broker: broker,
 		tc:     tc,
 		es:     es,
+		schema: validator,
+		policy: policy,
 		tracer: tracer,
 		logger: logger,
 	}
@@ -93,7 +109,19 @@ func (e *Event) AuthPublish(c *session.Client, topic *string, payload *[]byte) e
 		return errNilTopicPub
 	}
 	e.logger.Info("AuthPublish - client ID: " + c.ID + " topic: " + *topic)
-	return e.authAccess(c.Username, *topic)
+	if err := e.authAccess(c.Username, *topic); err != nil {
+		return err
+	}
+	if e.policy != PolicyDisconnect || payload == nil {
+		return nil
+	}
+
+	msg, err := message(c, *topic, *payload)
+	if err != nil {
+		return err
+	}
+
+	return e.schema.Validate(context.TODO(), msg)
 }
 
 // AuthSubscribe is called on device publish,
@@ -133,37 +161,18 @@ func (e *Event) Publish(c *session.Client, topic *string, payload *[]byte) {
 		return
 	}
 	e.logger.Info("Publish - client ID " + c.ID + " to the topic: " + *topic)
-	// Topics are in the format:
-	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
-
-	channelParts := channelRegExp.FindStringSubmatch(*topic)
-	if len(channelParts) < 1 {
-		e.logger.Info("Error in mqtt publish %s" + errMalformedData.Error())
-		return
-	}
-
-	chanID := channelParts[1]
-	subtopic := channelParts[2]
-
-	ct := ""
-	if stParts := ctRegExp.FindStringSubmatch(subtopic); len(stParts) > 1 {
-		ct = stParts[2]
-		subtopic = stParts[1]
-	}
-
-	subtopic, err := parseSubtopic(subtopic)
+	msg, err := message(c, *topic, *payload)
 	if err != nil {
 		e.logger.Info("Error in mqtt publish: " + err.Error())
 		return
 	}
 
-	msg := broker.Message{
-		Protocol:    "mqtt",
-		ContentType: ct,
-		Channel:     chanID,
-		Subtopic:    subtopic,
-		Publisher:   c.Username,
-		Payload:     *payload,
+	// The messages are already validated on the disconnect policy.
+	if e.policy != PolicyDisconnect {
+		if err := e.schema.Validate(context.TODO(), msg); err != nil {
+			e.logger.Info("Ignoring mqtt publish: " + err.Error())
+			return
+		}
 	}
 
 	if err := e.broker.Publish(context.TODO(), "", msg); err != nil {
@@ -228,6 +237,42 @@ func (e *Event) authAccess(username string, topic string) error {
 	return nil
 }
 
+func message(c *session.Client, topic string, payload []byte) (broker.Message, error) {
+	// Topics are in the format:
+	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
+	channelParts := channelRegExp.FindStringSubmatch(topic)
+	if len(channelParts) < 1 {
+		return broker.Message{}, errMalformedData
+	}
+
+	chanID := channelParts[1]
+	subtopic := channelParts[2]
+
+	ct := ""
+	if stParts := ctRegExp.FindStringSubmatch(subtopic); len(stParts) > 1 {
+		ct = stParts[2]
+		subtopic = stParts[1]
+	}
+
+	subtopic, err := parseSubtopic(subtopic)
+	if err != nil {
+		return broker.Message{}, err
+	}
+
+	msg := broker.Message{
+		Protocol:    "mqtt",
+		ContentType: ct,
+		Channel:     chanID,
+		Subtopic:    subtopic,
+		Publisher:   c.Username,
+		Payload:     payload,
+		Created:     broker.Created(),
+		Headers:     map[string]string{broker.HeaderClientID: c.ID},
+	}
+
+	return msg, nil
+}
+
 func parseSubtopic(subtopic string) (string, error) {
 	if subtopic == "" {
 		return subtopic, nil
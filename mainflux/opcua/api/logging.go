@@ -142,3 +142,16 @@ func (lm loggingMiddleware) Browse(serverURI, namespace, identifier string) (nod
 
 	return lm.svc.Browse(serverURI, namespace, identifier)
 }
+
+func (lm loggingMiddleware) Write(mfxChanID, mfxThingID string, value interface{}) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("write channel %s and thing %s with value %v, took %s to complete", mfxChanID, mfxThingID, value, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Write(mfxChanID, mfxThingID, value)
+}
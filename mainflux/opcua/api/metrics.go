@@ -107,3 +107,12 @@ func (mm *metricsMiddleware) Browse(serverURI, namespace, identifier string) ([]
 
 	return mm.svc.Browse(serverURI, namespace, identifier)
 }
+
+func (mm *metricsMiddleware) Write(mfxChanID, mfxThingID string, value interface{}) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "write").Add(1)
+		mm.latency.With("method", "write").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Write(mfxChanID, mfxThingID, value)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/cloustone/pandas/pkg/errors"
 	"github.com/cloustone/pandas/pkg/logger"
 	"github.com/cloustone/pandas/mainflux/opcua"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	opcuaGopcua "github.com/gopcua/opcua"
 	uaGopcua "github.com/gopcua/opcua/ua"
 )
@@ -20,6 +21,11 @@ import (
 const protocol = "opcua"
 const token = ""
 
+const (
+	headerNodeID    = "node_id"
+	headerServerURI = "server_uri"
+)
+
 var (
 	errNotFoundServerURI = errors.New("route map not found for Server URI")
 	errNotFoundNodeID    = errors.New("route map not found for Node ID")
@@ -70,29 +76,9 @@ func NewSubscriber(ctx context.Context, broker broker.Nats, thingsRM, channelsRM
 
 // Subscribe subscribes to the OPC-UA Server.
 func (c client) Subscribe(cfg opcua.Config) error {
-	opts := []opcuaGopcua.Option{
-		opcuaGopcua.SecurityMode(uaGopcua.MessageSecurityModeNone),
-	}
-
-	if cfg.Mode != "" {
-		endpoints, err := opcuaGopcua.GetEndpoints(cfg.ServerURI)
-		if err != nil {
-			return errors.Wrap(errFailedFetchEndpoint, err)
-		}
-
-		ep := opcuaGopcua.SelectEndpoint(endpoints, cfg.Policy, uaGopcua.MessageSecurityModeFromString(cfg.Mode))
-		if ep == nil {
-			return errFailedFindEndpoint
-		}
-
-		opts = []opcuaGopcua.Option{
-			opcuaGopcua.SecurityPolicy(cfg.Policy),
-			opcuaGopcua.SecurityModeString(cfg.Mode),
-			opcuaGopcua.CertificateFile(cfg.CertFile),
-			opcuaGopcua.PrivateKeyFile(cfg.KeyFile),
-			opcuaGopcua.AuthAnonymous(),
-			opcuaGopcua.SecurityFromEndpoint(ep, uaGopcua.UserTokenTypeAnonymous),
-		}
+	opts, err := options(cfg)
+	if err != nil {
+		return err
 	}
 
 	oc := opcuaGopcua.NewClient(cfg.ServerURI, opts...)
@@ -230,10 +216,15 @@ func (c client) publish(token string, m message) error {
 	msg := broker.Message{
 		Publisher:   thingID,
 		Protocol:    protocol,
-		ContentType: "Content-Type",
+		ContentType: senml.JSON,
 		Channel:     chanID,
 		Payload:     payload,
 		Subtopic:    m.NodeID,
+		Created:     broker.Created(),
+		Headers: map[string]string{
+			headerNodeID:    m.NodeID,
+			headerServerURI: m.ServerURI,
+		},
 	}
 
 	if err := c.broker.Publish(c.ctx, token, msg); err != nil {
@@ -243,3 +234,32 @@ func (c client) publish(token string, m message) error {
 	c.logger.Info(fmt.Sprintf("publish from server %s and node_id %s with value %v", m.ServerURI, m.NodeID, m.Data))
 	return nil
 }
+
+// options returns the OPC-UA client options for the security policy and
+// mode of the configuration.
+func options(cfg opcua.Config) ([]opcuaGopcua.Option, error) {
+	if cfg.Mode == "" {
+		return []opcuaGopcua.Option{
+			opcuaGopcua.SecurityMode(uaGopcua.MessageSecurityModeNone),
+		}, nil
+	}
+
+	endpoints, err := opcuaGopcua.GetEndpoints(cfg.ServerURI)
+	if err != nil {
+		return nil, errors.Wrap(errFailedFetchEndpoint, err)
+	}
+
+	ep := opcuaGopcua.SelectEndpoint(endpoints, cfg.Policy, uaGopcua.MessageSecurityModeFromString(cfg.Mode))
+	if ep == nil {
+		return nil, errFailedFindEndpoint
+	}
+
+	return []opcuaGopcua.Option{
+		opcuaGopcua.SecurityPolicy(cfg.Policy),
+		opcuaGopcua.SecurityModeString(cfg.Mode),
+		opcuaGopcua.CertificateFile(cfg.CertFile),
+		opcuaGopcua.PrivateKeyFile(cfg.KeyFile),
+		opcuaGopcua.AuthAnonymous(),
+		opcuaGopcua.SecurityFromEndpoint(ep, uaGopcua.UserTokenTypeAnonymous),
+	}, nil
+}
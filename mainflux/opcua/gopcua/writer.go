@@ -0,0 +1,148 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package gopcua
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloustone/pandas/mainflux/opcua"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/pkg/logger"
+	opcuaGopcua "github.com/gopcua/opcua"
+	uaGopcua "github.com/gopcua/opcua/ua"
+)
+
+var (
+	errFailedWrite      = errors.New("failed to write")
+	errUnsupportedType  = errors.New("unsupported node value type")
+	errMismatchedValue  = errors.New("value doesn't match node value type")
+	errMissingNodeValue = errors.New("node value not found")
+)
+
+var _ opcua.Writer = (*writer)(nil)
+
+type writer struct {
+	ctx    context.Context
+	logger logger.Logger
+}
+
+// NewWriter returns new OPC-UA writer instance.
+func NewWriter(ctx context.Context, log logger.Logger) opcua.Writer {
+	return writer{
+		ctx:    ctx,
+		logger: log,
+	}
+}
+
+// Write writes the value to the node, the value is converted to the type of
+// the current node value.
+func (w writer) Write(cfg opcua.Config, value interface{}) error {
+	opts, err := options(cfg)
+	if err != nil {
+		return err
+	}
+
+	oc := opcuaGopcua.NewClient(cfg.ServerURI, opts...)
+	if err := oc.Connect(w.ctx); err != nil {
+		return errors.Wrap(errFailedConn, err)
+	}
+	defer oc.Close()
+
+	nodeID, err := uaGopcua.ParseNodeID(cfg.NodeID)
+	if err != nil {
+		return errors.Wrap(errFailedParseNodeID, err)
+	}
+
+	rres, err := oc.Read(&uaGopcua.ReadRequest{
+		NodesToRead:        []*uaGopcua.ReadValueID{{NodeID: nodeID, AttributeID: uaGopcua.AttributeIDValue}},
+		TimestampsToReturn: uaGopcua.TimestampsToReturnNeither,
+	})
+	if err != nil {
+		return errors.Wrap(errFailedRead, err)
+	}
+	if len(rres.Results) == 0 || rres.Results[0].Value == nil {
+		return errMissingNodeValue
+	}
+	if rres.Results[0].Status != uaGopcua.StatusOK {
+		return errResponseStatus
+	}
+
+	v, err := toVariant(rres.Results[0].Value.Type(), value)
+	if err != nil {
+		return err
+	}
+
+	wres, err := oc.Write(&uaGopcua.WriteRequest{
+		NodesToWrite: []*uaGopcua.WriteValue{
+			{
+				NodeID:      nodeID,
+				AttributeID: uaGopcua.AttributeIDValue,
+				Value: &uaGopcua.DataValue{
+					EncodingMask: uaGopcua.DataValueValue,
+					Value:        v,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(errFailedWrite, err)
+	}
+	if len(wres.Results) == 0 || wres.Results[0] != uaGopcua.StatusOK {
+		return errResponseStatus
+	}
+
+	w.logger.Info(fmt.Sprintf("write to server %s and node_id %s with value %v", cfg.ServerURI, cfg.NodeID, value))
+	return nil
+}
+
+// toVariant converts the JSON decoded value to the node value type.
+func toVariant(t uaGopcua.TypeID, value interface{}) (*uaGopcua.Variant, error) {
+	var v interface{}
+	switch t {
+	case uaGopcua.TypeIDBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, errMismatchedValue
+		}
+		v = b
+	case uaGopcua.TypeIDString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, errMismatchedValue
+		}
+		v = s
+	default:
+		f, ok := value.(float64)
+		if !ok {
+			return nil, errMismatchedValue
+		}
+		switch t {
+		case uaGopcua.TypeIDSByte:
+			v = int8(f)
+		case uaGopcua.TypeIDByte:
+			v = uint8(f)
+		case uaGopcua.TypeIDInt16:
+			v = int16(f)
+		case uaGopcua.TypeIDUint16:
+			v = uint16(f)
+		case uaGopcua.TypeIDInt32:
+			v = int32(f)
+		case uaGopcua.TypeIDUint32:
+			v = uint32(f)
+		case uaGopcua.TypeIDInt64:
+			v = int64(f)
+		case uaGopcua.TypeIDUint64:
+			v = uint64(f)
+		case uaGopcua.TypeIDFloat:
+			v = float32(f)
+		case uaGopcua.TypeIDDouble:
+			v = f
+		default:
+			return nil, errUnsupportedType
+		}
+	}
+
+	return uaGopcua.NewVariant(v)
+}
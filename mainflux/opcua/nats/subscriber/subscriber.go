@@ -0,0 +1,101 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package subscriber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloustone/pandas/commands"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/opcua"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/gogo/protobuf/proto"
+	nats "github.com/nats-io/nats.go"
+)
+
+const (
+	queue       = "opcua"
+	protocol    = "opcua"
+	contentType = "application/json"
+
+	// The command param carrying the value written to the node.
+	paramValue = "value"
+)
+
+// Subscriber is used to intercept the commands sent to the OPC-UA nodes and
+// write their values to the OPC-UA Servers.
+type Subscriber struct {
+	broker broker.Nats
+	svc    opcua.Service
+	logger log.Logger
+}
+
+// NewSubscriber instances Subscriber strucure.
+func NewSubscriber(b broker.Nats, svc opcua.Service, logger log.Logger) *Subscriber {
+	return &Subscriber{
+		broker: b,
+		svc:    svc,
+		logger: logger,
+	}
+}
+
+// Subscribe subscribes to the commands of all things.
+func (s *Subscriber) Subscribe() error {
+	_, err := s.broker.QueueSubscribe(commands.CommandsSubject, queue, s.handleMsg)
+	return err
+}
+
+func (s *Subscriber) handleMsg(m *nats.Msg) {
+	var msg broker.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
+		return
+	}
+
+	thingID, reply, ok := commands.ParseSubtopic(msg.Subtopic)
+	if !ok || reply {
+		return
+	}
+
+	var req commands.Request
+	if err := json.Unmarshal(msg.Payload, &req); err != nil {
+		s.logger.Warn(fmt.Sprintf("Malformed command of thing %s: %s", thingID, err))
+		return
+	}
+
+	// The write is confirmed by the OPC-UA Server, so the command is
+	// acknowledged rather than only delivered.
+	err := s.svc.Write(msg.Channel, thingID, req.Params[paramValue])
+	switch err {
+	case nil:
+		s.reply(msg.Channel, thingID, commands.Reply{ID: req.ID, Status: commands.StatusAcknowledged})
+	case opcua.ErrNotFoundThing:
+		// The thing isn't an OPC-UA node.
+	default:
+		s.reply(msg.Channel, thingID, commands.Reply{ID: req.ID, Status: commands.StatusFailed, Error: err.Error()})
+	}
+}
+
+// reply publishes the reply to the command on behalf of the thing.
+func (s *Subscriber) reply(chanID, thingID string, r commands.Reply) {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("Marshalling reply failed: %s", err))
+		return
+	}
+
+	msg := broker.Message{
+		Channel:     chanID,
+		Subtopic:    commands.ReplySubtopic(thingID),
+		Publisher:   thingID,
+		Protocol:    protocol,
+		ContentType: contentType,
+		Payload:     payload,
+	}
+	if err := s.broker.Publish(context.Background(), "", msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Publishing reply to command %s failed: %s", r.ID, err))
+	}
+}
@@ -16,6 +16,16 @@ const protocol = "opcua"
 var (
 	// ErrMalformedEntity indicates malformed entity specification.
 	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrNotFoundThing indicates a non-existent route map for a thing.
+	ErrNotFoundThing = errors.New("route map not found for this thing")
+
+	// ErrNotFoundChannel indicates a non-existent route map for a channel.
+	ErrNotFoundChannel = errors.New("route map not found for this channel")
+
+	// ErrNotConnected indicates a non-existent connection route map between
+	// a thing and a channel.
+	ErrNotConnected = errors.New("route map not found for this connection")
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -47,6 +57,10 @@ type Service interface {
 
 	// Browse browses available nodes for a given OPC-UA Server URI and NodeID
 	Browse(string, string, string) ([]BrowsedNode, error)
+
+	// Write writes the value to the NodeID of the thing, on the OPC-UA
+	// Server of the channel
+	Write(string, string, interface{}) error
 }
 
 // Config OPC-UA Server
@@ -65,6 +79,7 @@ var _ Service = (*adapterService)(nil)
 type adapterService struct {
 	subscriber Subscriber
 	browser    Browser
+	writer     Writer
 	thingsRM   RouteMapRepository
 	channelsRM RouteMapRepository
 	connectRM  RouteMapRepository
@@ -73,10 +88,11 @@ type adapterService struct {
 }
 
 // New instantiates the OPC-UA adapter implementation.
-func New(sub Subscriber, brow Browser, thingsRM, channelsRM, connectRM RouteMapRepository, cfg Config, log logger.Logger) Service {
+func New(sub Subscriber, brow Browser, wr Writer, thingsRM, channelsRM, connectRM RouteMapRepository, cfg Config, log logger.Logger) Service {
 	return &adapterService{
 		subscriber: sub,
 		browser:    brow,
+		writer:     wr,
 		thingsRM:   thingsRM,
 		channelsRM: channelsRM,
 		connectRM:  connectRM,
@@ -148,6 +164,33 @@ func (as *adapterService) Browse(serverURI, namespace, identifier string) ([]Bro
 	return nodes, nil
 }
 
+func (as *adapterService) Write(mfxChanID, mfxThingID string, value interface{}) error {
+	nodeID, err := as.thingsRM.Get(mfxThingID)
+	if err != nil {
+		return ErrNotFoundThing
+	}
+
+	serverURI, err := as.channelsRM.Get(mfxChanID)
+	if err != nil {
+		return ErrNotFoundChannel
+	}
+
+	c := fmt.Sprintf("%s:%s", mfxChanID, mfxThingID)
+	if _, err := as.connectRM.Get(c); err != nil {
+		return ErrNotConnected
+	}
+
+	if value == nil {
+		return ErrMalformedEntity
+	}
+
+	cfg := as.cfg
+	cfg.NodeID = nodeID
+	cfg.ServerURI = serverURI
+
+	return as.writer.Write(cfg, value)
+}
+
 func (as *adapterService) DisconnectThing(mfxChanID, mfxThingID string) error {
 	c := fmt.Sprintf("%s:%s", mfxChanID, mfxThingID)
 	return as.connectRM.Remove(c)
@@ -0,0 +1,10 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package opcua
+
+// Writer represents the OPC-UA Server client writing the node values.
+type Writer interface {
+	// Write writes the value to the given NodeID.
+	Write(Config, interface{}) error
+}
@@ -0,0 +1,98 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"math"
+	"sort"
+)
+
+// Aggregator buckets the values for the repositories which can't group the
+// messages by time natively. The values of a name must be added in time
+// order, so only the buckets are kept in memory rather than the messages.
+type Aggregator struct {
+	interval    float64
+	aggregation string
+	series      map[string][]Bucket
+}
+
+// Bucket contains the aggregates of the values of a name from its time.
+type Bucket struct {
+	Time  float64
+	Count float64
+	Sum   float64
+	Min   float64
+	Max   float64
+	Last  float64
+}
+
+// NewAggregator returns the aggregator of the buckets of the interval.
+func NewAggregator(interval float64, aggregation string) *Aggregator {
+	return &Aggregator{
+		interval:    interval,
+		aggregation: aggregation,
+		series:      make(map[string][]Bucket),
+	}
+}
+
+// Add adds the value of the message of the name at the time.
+func (a *Aggregator) Add(name string, time, value float64) {
+	a.Merge(name, Bucket{
+		Time:  time,
+		Count: 1,
+		Sum:   value,
+		Min:   value,
+		Max:   value,
+		Last:  value,
+	})
+}
+
+// Merge merges the bucket of the name, e.g. of a rollup, into the bucket of
+// the interval containing its time.
+func (a *Aggregator) Merge(name string, b Bucket) {
+	b.Time = math.Floor(b.Time/a.interval) * a.interval
+	buckets := a.series[name]
+	if n := len(buckets); n > 0 && buckets[n-1].Time == b.Time {
+		last := &buckets[n-1]
+		last.Count += b.Count
+		last.Sum += b.Sum
+		last.Min = math.Min(last.Min, b.Min)
+		last.Max = math.Max(last.Max, b.Max)
+		last.Last = b.Last
+		return
+	}
+	a.series[name] = append(buckets, b)
+}
+
+// Series returns the series ordered by name.
+func (a *Aggregator) Series() []Series {
+	ret := []Series{}
+	for name, buckets := range a.series {
+		s := Series{Name: name, Points: make([]Point, len(buckets))}
+		for i, b := range buckets {
+			s.Points[i] = Point{Time: b.Time, Value: b.Value(a.aggregation)}
+		}
+		ret = append(ret, s)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Value returns the aggregation of the bucket.
+func (b Bucket) Value(aggregation string) float64 {
+	switch aggregation {
+	case AggregationMin:
+		return b.Min
+	case AggregationMax:
+		return b.Max
+	case AggregationSum:
+		return b.Sum
+	case AggregationCount:
+		return b.Count
+	case AggregationLast:
+		return b.Last
+	default:
+		return b.Sum / b.Count
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/cloustone/pandas/mainflux/readers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	"github.com/go-kit/kit/endpoint"
 )
 
@@ -18,16 +19,134 @@ func listMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
 			return nil, err
 		}
 
-		page, err := svc.ReadAll(req.chanID, req.offset, req.limit, req.query)
+		if req.interval > 0 {
+			return aggregate(svc, req)
+		}
+
+		page, err := svc.ReadAll(req.chanID, req.pageMetadata())
 		if err != nil {
 			return nil, err
 		}
 
-		return pageRes{
-			Total:    page.Total,
-			Offset:   page.Offset,
-			Limit:    page.Limit,
-			Messages: page.Messages,
+		return toPageRes(page), nil
+	}
+}
+
+func listChannelsMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(listChannelsMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		pm := req.pageMetadata()
+		chanIDs := req.chanIDs
+		if req.thingID != "" {
+			var err error
+			if chanIDs, err = thingChannels(svc, req.token, req.thingID); err != nil {
+				return nil, err
+			}
+			query := map[string]string{"publisher": req.thingID}
+			for k, v := range pm.Query {
+				if k != "publisher" {
+					query[k] = v
+				}
+			}
+			pm.Query = query
+		}
+
+		page, err := svc.ReadChannels(chanIDs, pm)
+		if err != nil {
+			return nil, err
+		}
+
+		return toPageRes(page), nil
+	}
+}
+
+// thingChannels returns the channels the thing sent messages to, leaving out
+// the ones the token can't access.
+func thingChannels(svc readers.MessageRepository, token, thingID string) ([]string, error) {
+	chanIDs, err := svc.PublisherChannels(thingID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []string{}
+	for _, chanID := range chanIDs {
+		switch err := authorizeToken(token, chanID); err {
+		case nil:
+			ret = append(ret, chanID)
+		case errUnauthorizedAccess:
+		default:
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func toPageRes(page readers.MessagesPage) pageRes {
+	return pageRes{
+		Total:      page.Total,
+		Offset:     page.Offset,
+		Limit:      page.Limit,
+		Messages:   page.Messages,
+		NextCursor: page.NextCursor,
+	}
+}
+
+func exportMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportMessagesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		em := readers.ExportMetadata{
+			From:  req.from,
+			To:    req.to,
+			Order: req.order,
+			Query: req.query,
+		}
+
+		// The messages are exported while the response is encoded.
+		return exportRes{
+			chanID: req.chanID,
+			format: req.format,
+			gzip:   req.gzip,
+			export: func(fn func(senml.Message) error) error {
+				return svc.Export(req.chanID, em, fn)
+			},
 		}, nil
 	}
 }
+
+func aggregate(svc readers.MessageRepository, req listMessagesReq) (interface{}, error) {
+	am := readers.AggregationMetadata{
+		From:        req.from,
+		To:          req.to,
+		Interval:    req.interval,
+		Aggregation: req.aggregation,
+		Query:       req.query,
+	}
+	series, err := svc.Aggregate(req.chanID, am)
+	if err != nil {
+		return nil, err
+	}
+
+	res := seriesPageRes{
+		Interval:    req.interval,
+		Aggregation: req.aggregation,
+		Series:      []seriesRes{},
+	}
+	for _, s := range series {
+		sr := seriesRes{Name: s.Name, Points: []pointRes{}}
+		for _, p := range s.Points {
+			sr.Points = append(sr.Points, pointRes{Time: p.Time, Value: p.Value})
+		}
+		res.Series = append(res.Series, sr)
+	}
+	return res, nil
+}
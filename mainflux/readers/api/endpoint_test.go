@@ -4,7 +4,11 @@
 package api_test
 
 import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -15,6 +19,7 @@ import (
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	"github.com/cloustone/pandas/mainflux"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -23,6 +28,7 @@ const (
 	invalid       = "invalid"
 	numOfMessages = 42
 	chanID        = "1"
+	otherChanID   = "2"
 	valueFields   = 5
 )
 
@@ -72,10 +78,11 @@ func newServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient)
 }
 
 type testRequest struct {
-	client *http.Client
-	method string
-	url    string
-	token  string
+	client   *http.Client
+	method   string
+	url      string
+	token    string
+	encoding string
 }
 
 func (tr testRequest) make() (*http.Response, error) {
@@ -86,6 +93,9 @@ func (tr testRequest) make() (*http.Response, error) {
 	if tr.token != "" {
 		req.Header.Set("Authorization", tr.token)
 	}
+	if tr.encoding != "" {
+		req.Header.Set("Accept-Encoding", tr.encoding)
+	}
 
 	return tr.client.Do(req)
 }
@@ -166,6 +176,293 @@ func TestReadAll(t *testing.T) {
 			token:  token,
 			status: http.StatusOK,
 		},
+		"read page with time range": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=10&to=20", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read page with non-numeric from": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=abc", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page with from after to": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?from=20&to=10", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read page in ascending order": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?order=asc", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read page with invalid order": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?order=%s", ts.URL, chanID, invalid),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read aggregated series": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?interval=5m&agg=max", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read aggregated series with default aggregation": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?interval=5m", ts.URL, chanID),
+			token:  token,
+			status: http.StatusOK,
+		},
+		"read aggregated series with invalid aggregation": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?interval=5m&agg=%s", ts.URL, chanID, invalid),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read aggregated series with invalid interval": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?interval=%s", ts.URL, chanID, invalid),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read aggregated series without interval": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?agg=avg", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestExport(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url         string
+		token       string
+		encoding    string
+		status      int
+		contentType string
+		records     int
+	}{
+		"export messages as ndjson by default": {
+			url:         fmt.Sprintf("%s/channels/%s/messages/export", ts.URL, chanID),
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "application/x-ndjson",
+			records:     numOfMessages,
+		},
+		"export messages as csv": {
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?format=csv", ts.URL, chanID),
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			records:     numOfMessages + 1,
+		},
+		"export messages as senml": {
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?format=senml", ts.URL, chanID),
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "application/senml+json",
+			records:     numOfMessages,
+		},
+		"export gzipped messages": {
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?format=csv&order=asc", ts.URL, chanID),
+			token:       token,
+			encoding:    "gzip",
+			status:      http.StatusOK,
+			contentType: "text/csv",
+			records:     numOfMessages + 1,
+		},
+		"export messages of empty time range": {
+			url:         fmt.Sprintf("%s/channels/%s/messages/export?format=senml&from=10&to=20", ts.URL, chanID),
+			token:       token,
+			status:      http.StatusOK,
+			contentType: "application/senml+json",
+			records:     0,
+		},
+		"export messages with invalid format": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?format=%s", ts.URL, chanID, invalid),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"export messages with invalid order": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?order=%s", ts.URL, chanID, invalid),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"export messages with from after to": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/export?from=20&to=10", ts.URL, chanID),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"export messages with invalid token": {
+			url:    fmt.Sprintf("%s/channels/%s/messages/export", ts.URL, chanID),
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client:   ts.Client(),
+			method:   http.MethodGet,
+			url:      tc.url,
+			token:    tc.token,
+			encoding: tc.encoding,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		assert.Equal(t, tc.contentType, res.Header.Get("Content-Type"), fmt.Sprintf("%s: expected content type %s got %s", desc, tc.contentType, res.Header.Get("Content-Type")))
+
+		var body io.Reader = res.Body
+		if tc.encoding == "gzip" {
+			body, err = gzip.NewReader(res.Body)
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		}
+
+		records := 0
+		switch tc.contentType {
+		case "application/senml+json":
+			var pack []map[string]interface{}
+			err = json.NewDecoder(body).Decode(&pack)
+			records = len(pack)
+		case "text/csv":
+			var rows [][]string
+			rows, err = csv.NewReader(body).ReadAll()
+			records = len(rows)
+		default:
+			dec := json.NewDecoder(body)
+			for dec.More() {
+				var msg senml.Message
+				if err = dec.Decode(&msg); err != nil {
+					break
+				}
+				records++
+			}
+		}
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.records, records, fmt.Sprintf("%s: expected %d records got %d", desc, tc.records, records))
+	}
+}
+
+func TestReadChannels(t *testing.T) {
+	msgs := []senml.Message{}
+	for i := 0; i < numOfMessages; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: "1",
+			Protocol:  "mqtt",
+			Time:      float64(i),
+		}
+		if i%2 == 1 {
+			msg.Channel = otherChanID
+		}
+		if i%3 == 0 {
+			msg.Publisher = "2"
+		}
+		msgs = append(msgs, msg)
+	}
+	messages := map[string][]senml.Message{}
+	for _, msg := range msgs {
+		messages[msg.Channel] = append(messages[msg.Channel], msg)
+	}
+	// The messages of the channel the token can't access are left out of
+	// the messages of the publisher.
+	messages[invalid] = []senml.Message{{Channel: invalid, Publisher: "1"}}
+
+	publisherMsgs := 0
+	for _, msg := range msgs {
+		if msg.Publisher == "1" {
+			publisherMsgs++
+		}
+	}
+
+	svc := mocks.NewMessageRepository(messages)
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url    string
+		token  string
+		status int
+		total  uint64
+	}{
+		"read messages of channels": {
+			url:    fmt.Sprintf("%s/messages?channel=%s&channel=%s&limit=%d", ts.URL, chanID, otherChanID, numOfMessages),
+			token:  token,
+			status: http.StatusOK,
+			total:  numOfMessages,
+		},
+		"read messages of single channel": {
+			url:    fmt.Sprintf("%s/messages?channel=%s", ts.URL, otherChanID),
+			token:  token,
+			status: http.StatusOK,
+			total:  numOfMessages / 2,
+		},
+		"read messages of channels filtered by publisher": {
+			url:    fmt.Sprintf("%s/messages?channel=%s&channel=%s&publisher=1", ts.URL, chanID, otherChanID),
+			token:  token,
+			status: http.StatusOK,
+			total:  uint64(publisherMsgs),
+		},
+		"read messages without channels": {
+			url:    fmt.Sprintf("%s/messages", ts.URL),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read messages of empty channel": {
+			url:    fmt.Sprintf("%s/messages?channel=", ts.URL),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read messages of inaccessible channel": {
+			url:    fmt.Sprintf("%s/messages?channel=%s&channel=%s", ts.URL, chanID, invalid),
+			token:  token,
+			status: http.StatusForbidden,
+		},
+		"read messages of channels with invalid token": {
+			url:    fmt.Sprintf("%s/messages?channel=%s", ts.URL, chanID),
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+		"read messages of thing": {
+			url:    fmt.Sprintf("%s/things/1/messages?limit=%d", ts.URL, numOfMessages),
+			token:  token,
+			status: http.StatusOK,
+			total:  uint64(publisherMsgs),
+		},
+		"read messages of thing without messages": {
+			url:    fmt.Sprintf("%s/things/3/messages", ts.URL),
+			token:  token,
+			status: http.StatusOK,
+			total:  0,
+		},
+		"read messages of thing with invalid page": {
+			url:    fmt.Sprintf("%s/things/1/messages?limit=0", ts.URL),
+			token:  token,
+			status: http.StatusBadRequest,
+		},
+		"read messages of thing with empty token": {
+			url:    fmt.Sprintf("%s/things/1/messages", ts.URL),
+			token:  "",
+			status: http.StatusForbidden,
+		},
 	}
 
 	for desc, tc := range cases {
@@ -176,7 +473,85 @@ func TestReadAll(t *testing.T) {
 			token:  tc.token,
 		}
 		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var page pageRes
+		err = json.NewDecoder(res.Body).Decode(&page)
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, tc.total, page.Total))
+	}
+}
+
+func TestReadAllCursor(t *testing.T) {
+	svc := newService()
+	tc := mocks.NewThingsService()
+	ts := newServer(svc, tc)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		url    string
+		status int
+	}{
+		"read page with invalid cursor": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s", ts.URL, chanID, invalid),
+			status: http.StatusBadRequest,
+		},
+		"read page with cursor and offset": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&offset=10", ts.URL, chanID, invalid),
+			status: http.StatusBadRequest,
+		},
+		"read aggregated series with cursor": {
+			url:    fmt.Sprintf("%s/channels/%s/messages?cursor=%s&interval=5m", ts.URL, chanID, invalid),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
 	}
+
+	// Follow the cursors from the first page to the last.
+	read := 0
+	url := fmt.Sprintf("%s/channels/%s/messages?limit=10", ts.URL, chanID)
+	for pages := 0; url != ""; pages++ {
+		require.True(t, pages <= numOfMessages/10, "read pages: expected the cursors to end")
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    url,
+			token:  token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("read pages: unexpected error %s", err))
+		require.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("read pages: expected %d got %d", http.StatusOK, res.StatusCode))
+
+		var page pageRes
+		err = json.NewDecoder(res.Body).Decode(&page)
+		require.Nil(t, err, fmt.Sprintf("read pages: unexpected error %s", err))
+		read += len(page.Messages)
+
+		url = ""
+		if page.NextCursor != "" {
+			url = fmt.Sprintf("%s/channels/%s/messages?limit=10&cursor=%s", ts.URL, chanID, page.NextCursor)
+		}
+	}
+	assert.Equal(t, numOfMessages, read, fmt.Sprintf("read pages: expected %d messages got %d", numOfMessages, read))
+}
+
+type pageRes struct {
+	Total      uint64          `json:"total"`
+	Messages   []senml.Message `json:"messages"`
+	NextCursor string          `json:"next_cursor"`
 }
@@ -0,0 +1,249 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	msenml "github.com/mainflux/senml"
+)
+
+// The formats of the exported messages.
+const (
+	csvFormat    = "csv"
+	ndjsonFormat = "ndjson"
+	senmlFormat  = "senml"
+
+	defFormat = ndjsonFormat
+)
+
+var csvHeader = []string{
+	"channel", "subtopic", "publisher", "protocol", "name", "unit", "time",
+	"update_time", "value", "string_value", "bool_value", "data_value", "sum",
+}
+
+// exportEncoder writes the messages of an export in a format.
+type exportEncoder interface {
+	// contentType returns the content type of the format.
+	contentType() string
+
+	// extension returns the file extension of the format.
+	extension() string
+
+	// begin writes the content preceding the messages.
+	begin(io.Writer) error
+
+	// encode writes the message.
+	encode(io.Writer, senml.Message) error
+
+	// end writes the content following the messages.
+	end(io.Writer) error
+}
+
+func newExportEncoder(format string) (exportEncoder, bool) {
+	switch format {
+	case csvFormat:
+		return &csvEncoder{}, true
+	case ndjsonFormat:
+		return ndjsonEncoder{}, true
+	case senmlFormat:
+		return &senmlEncoder{}, true
+	}
+	return nil, false
+}
+
+// encodeExport streams the messages of the export. The headers are written
+// along with the first message, so the errors preceding it are still encoded
+// as the status of the response. Once the response has started the export
+// can't fail gracefully, so the response is aborted rather than leaving the
+// client with a truncated file which looks complete.
+func encodeExport(w http.ResponseWriter, res exportRes) error {
+	enc, ok := newExportEncoder(res.format)
+	if !ok {
+		return errInvalidRequest
+	}
+
+	var out io.Writer
+	var zw *gzip.Writer
+	start := func() error {
+		w.Header().Set("Content-Type", enc.contentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, res.chanID, enc.extension()))
+		w.Header().Set("Vary", "Accept-Encoding")
+		out = w
+		if res.gzip {
+			w.Header().Set("Content-Encoding", "gzip")
+			zw = gzip.NewWriter(w)
+			out = zw
+		}
+		w.WriteHeader(http.StatusOK)
+		return enc.begin(out)
+	}
+
+	err := res.export(func(msg senml.Message) error {
+		if out == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return enc.encode(out, msg)
+	})
+	if err == nil && out == nil {
+		err = start()
+	}
+	if err == nil {
+		err = enc.end(out)
+	}
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+
+	if err != nil && out != nil {
+		panic(http.ErrAbortHandler)
+	}
+	return err
+}
+
+type ndjsonEncoder struct{}
+
+func (ndjsonEncoder) contentType() string {
+	return "application/x-ndjson"
+}
+
+func (ndjsonEncoder) extension() string {
+	return ndjsonFormat
+}
+
+func (ndjsonEncoder) begin(io.Writer) error {
+	return nil
+}
+
+func (ndjsonEncoder) encode(w io.Writer, msg senml.Message) error {
+	return json.NewEncoder(w).Encode(msg)
+}
+
+func (ndjsonEncoder) end(io.Writer) error {
+	return nil
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (enc *csvEncoder) contentType() string {
+	return "text/csv"
+}
+
+func (enc *csvEncoder) extension() string {
+	return csvFormat
+}
+
+func (enc *csvEncoder) begin(w io.Writer) error {
+	enc.w = csv.NewWriter(w)
+	return enc.w.Write(csvHeader)
+}
+
+func (enc *csvEncoder) encode(_ io.Writer, msg senml.Message) error {
+	return enc.w.Write([]string{
+		msg.Channel,
+		msg.Subtopic,
+		msg.Publisher,
+		msg.Protocol,
+		msg.Name,
+		msg.Unit,
+		formatFloat(msg.Time),
+		formatFloat(msg.UpdateTime),
+		formatFloatPtr(msg.Value),
+		formatStringPtr(msg.StringValue),
+		formatBoolPtr(msg.BoolValue),
+		formatStringPtr(msg.DataValue),
+		formatFloatPtr(msg.Sum),
+	})
+}
+
+func (enc *csvEncoder) end(io.Writer) error {
+	enc.w.Flush()
+	return enc.w.Error()
+}
+
+// senmlEncoder writes the messages as the records of a SenML pack.
+type senmlEncoder struct {
+	empty bool
+}
+
+func (enc *senmlEncoder) contentType() string {
+	return "application/senml+json"
+}
+
+func (enc *senmlEncoder) extension() string {
+	return "json"
+}
+
+func (enc *senmlEncoder) begin(w io.Writer) error {
+	enc.empty = true
+	_, err := io.WriteString(w, "[")
+	return err
+}
+
+func (enc *senmlEncoder) encode(w io.Writer, msg senml.Message) error {
+	if !enc.empty {
+		if _, err := io.WriteString(w, ","); err != nil {
+			return err
+		}
+	}
+	enc.empty = false
+
+	b, err := json.Marshal(msenml.Record{
+		Name:        msg.Name,
+		Unit:        msg.Unit,
+		Time:        msg.Time,
+		UpdateTime:  msg.UpdateTime,
+		Value:       msg.Value,
+		StringValue: msg.StringValue,
+		BoolValue:   msg.BoolValue,
+		DataValue:   msg.DataValue,
+		Sum:         msg.Sum,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (enc *senmlEncoder) end(w io.Writer) error {
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatFloatPtr(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return formatFloat(*f)
+}
+
+func formatStringPtr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatBoolPtr(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
@@ -7,10 +7,12 @@ package api
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloustone/pandas/pkg/logger"
 	"github.com/cloustone/pandas/mainflux/readers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
 )
 
 var _ readers.MessageRepository = (*loggingMiddleware)(nil)
@@ -28,9 +30,9 @@ func LoggingMiddleware(svc readers.MessageRepository, logger logger.Logger) read
 	}
 }
 
-func (lm *loggingMiddleware) ReadAll(chanID string, offset, limit uint64, query map[string]string) (page readers.MessagesPage, err error) {
+func (lm *loggingMiddleware) ReadAll(chanID string, pm readers.PageMetadata) (page readers.MessagesPage, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method read_all for channel %s with offset %d and limit %d took %s to complete", chanID, offset, limit, time.Since(begin))
+		message := fmt.Sprintf("Method read_all for channel %s with offset %d and limit %d took %s to complete", chanID, pm.Offset, pm.Limit, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -38,5 +40,57 @@ func (lm *loggingMiddleware) ReadAll(chanID string, offset, limit uint64, query
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ReadAll(chanID, offset, limit, query)
+	return lm.svc.ReadAll(chanID, pm)
+}
+
+func (lm *loggingMiddleware) ReadChannels(chanIDs []string, pm readers.PageMetadata) (page readers.MessagesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method read_channels for channels %s with offset %d and limit %d took %s to complete", strings.Join(chanIDs, ", "), pm.Offset, pm.Limit, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReadChannels(chanIDs, pm)
+}
+
+func (lm *loggingMiddleware) PublisherChannels(publisher string) (chanIDs []string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method publisher_channels for publisher %s took %s to complete", publisher, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PublisherChannels(publisher)
+}
+
+func (lm *loggingMiddleware) Aggregate(chanID string, am readers.AggregationMetadata) (series []readers.Series, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method aggregate for channel %s with %s of interval %gs took %s to complete", chanID, am.Aggregation, am.Interval, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Aggregate(chanID, am)
+}
+
+func (lm *loggingMiddleware) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Export(chanID, em, fn)
 }
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/cloustone/pandas/mainflux/readers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	"github.com/go-kit/kit/metrics"
 )
 
@@ -30,11 +31,47 @@ func MetricsMiddleware(svc readers.MessageRepository, counter metrics.Counter, l
 	}
 }
 
-func (mm *metricsMiddleware) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+func (mm *metricsMiddleware) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "read_all").Add(1)
 		mm.latency.With("method", "read_all").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return mm.svc.ReadAll(chanID, offset, limit, query)
+	return mm.svc.ReadAll(chanID, pm)
+}
+
+func (mm *metricsMiddleware) ReadChannels(chanIDs []string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "read_channels").Add(1)
+		mm.latency.With("method", "read_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ReadChannels(chanIDs, pm)
+}
+
+func (mm *metricsMiddleware) PublisherChannels(publisher string) ([]string, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "publisher_channels").Add(1)
+		mm.latency.With("method", "publisher_channels").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.PublisherChannels(publisher)
+}
+
+func (mm *metricsMiddleware) Aggregate(chanID string, am readers.AggregationMetadata) ([]readers.Series, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "aggregate").Add(1)
+		mm.latency.With("method", "aggregate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Aggregate(chanID, am)
+}
+
+func (mm *metricsMiddleware) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "export").Add(1)
+		mm.latency.With("method", "export").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Export(chanID, em, fn)
 }
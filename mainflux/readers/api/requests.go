@@ -3,21 +3,126 @@
 
 package api
 
+import "github.com/cloustone/pandas/mainflux/readers"
+
 type apiReq interface {
 	validate() error
 }
 
-type listMessagesReq struct {
-	chanID string
+// pageReq contains the paging, ordering and filtering shared by the message
+// listings.
+type pageReq struct {
 	offset uint64
 	limit  uint64
+	from   float64
+	to     float64
+	order  string
+	cursor string
 	query  map[string]string
 }
 
-func (req listMessagesReq) validate() error {
+func (req pageReq) validate() error {
 	if req.limit < 1 {
 		return errInvalidRequest
 	}
 
+	if req.from < 0 || req.to < 0 || (req.to > 0 && req.from >= req.to) {
+		return errInvalidRequest
+	}
+
+	if !readers.ValidOrder(req.order) {
+		return errInvalidRequest
+	}
+
+	if req.cursor != "" && req.offset > 0 {
+		return errInvalidRequest
+	}
+
+	return nil
+}
+
+func (req pageReq) pageMetadata() readers.PageMetadata {
+	return readers.PageMetadata{
+		Offset: req.offset,
+		Limit:  req.limit,
+		From:   req.from,
+		To:     req.to,
+		Order:  req.order,
+		Cursor: req.cursor,
+		Query:  req.query,
+	}
+}
+
+type listMessagesReq struct {
+	pageReq
+	chanID      string
+	interval    float64
+	aggregation string
+}
+
+func (req listMessagesReq) validate() error {
+	if err := req.pageReq.validate(); err != nil {
+		return err
+	}
+
+	if req.interval < 0 || (req.interval == 0 && req.aggregation != "") {
+		return errInvalidRequest
+	}
+
+	if req.interval > 0 && req.cursor != "" {
+		return errInvalidRequest
+	}
+
+	if req.interval > 0 && !readers.ValidAggregation(req.aggregation) {
+		return errInvalidRequest
+	}
+
+	return nil
+}
+
+// listChannelsMessagesReq lists the messages of the channels, or of the
+// channels the thing sent messages to.
+type listChannelsMessagesReq struct {
+	pageReq
+	token   string
+	chanIDs []string
+	thingID string
+}
+
+func (req listChannelsMessagesReq) validate() error {
+	if err := req.pageReq.validate(); err != nil {
+		return err
+	}
+
+	if (len(req.chanIDs) == 0) == (req.thingID == "") {
+		return errInvalidRequest
+	}
+
+	return nil
+}
+
+type exportMessagesReq struct {
+	chanID string
+	from   float64
+	to     float64
+	order  string
+	format string
+	gzip   bool
+	query  map[string]string
+}
+
+func (req exportMessagesReq) validate() error {
+	if req.from < 0 || req.to < 0 || (req.to > 0 && req.from >= req.to) {
+		return errInvalidRequest
+	}
+
+	if !readers.ValidOrder(req.order) {
+		return errInvalidRequest
+	}
+
+	if _, ok := newExportEncoder(req.format); !ok {
+		return errInvalidRequest
+	}
+
 	return nil
 }
@@ -10,13 +10,17 @@ import (
 	"github.com/cloustone/pandas/mainflux"
 )
 
-var _ mainflux.Response = (*pageRes)(nil)
+var (
+	_ mainflux.Response = (*pageRes)(nil)
+	_ mainflux.Response = (*seriesPageRes)(nil)
+)
 
 type pageRes struct {
-	Total    uint64          `json:"total"`
-	Offset   uint64          `json:"offset"`
-	Limit    uint64          `json:"limit"`
-	Messages []senml.Message `json:"messages"`
+	Total      uint64          `json:"total"`
+	Offset     uint64          `json:"offset"`
+	Limit      uint64          `json:"limit"`
+	Messages   []senml.Message `json:"messages"`
+	NextCursor string          `json:"next_cursor,omitempty"`
 }
 
 func (res pageRes) Headers() map[string]string {
@@ -30,3 +34,39 @@ func (res pageRes) Code() int {
 func (res pageRes) Empty() bool {
 	return false
 }
+
+type pointRes struct {
+	Time  float64 `json:"time"`
+	Value float64 `json:"value"`
+}
+
+type seriesRes struct {
+	Name   string     `json:"name"`
+	Points []pointRes `json:"points"`
+}
+
+type seriesPageRes struct {
+	Interval    float64     `json:"interval"`
+	Aggregation string      `json:"aggregation"`
+	Series      []seriesRes `json:"series"`
+}
+
+func (res seriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res seriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res seriesPageRes) Empty() bool {
+	return false
+}
+
+// exportRes streams the exported messages instead of being encoded as JSON.
+type exportRes struct {
+	chanID string
+	format string
+	gzip   bool
+	export func(func(senml.Message) error) error
+}
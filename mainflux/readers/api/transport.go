@@ -9,6 +9,7 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloustone/pandas"
@@ -25,6 +26,8 @@ const (
 	contentType = "application/json"
 	defLimit    = 10
 	defOffset   = 0
+
+	defAggregation = readers.AggregationAvg
 )
 
 var (
@@ -50,6 +53,27 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 		opts...,
 	))
 
+	mux.Get("/messages", kithttp.NewServer(
+		listChannelsMessagesEndpoint(svc),
+		decodeListChannels,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/things/:thingID/messages", kithttp.NewServer(
+		listChannelsMessagesEndpoint(svc),
+		decodeListThing,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/channels/:chanID/messages/export", kithttp.NewServer(
+		exportMessagesEndpoint(svc),
+		decodeExport,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.GetFunc("/version", pandas.Version(svcName))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -66,34 +90,178 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	offset, err := getQuery(r, "offset", defOffset)
+	page, err := decodePage(r)
 	if err != nil {
 		return nil, err
 	}
 
-	limit, err := getQuery(r, "limit", defLimit)
+	interval, err := getDurationQuery(r, "interval")
 	if err != nil {
 		return nil, err
 	}
 
-	query := map[string]string{}
-	for _, name := range queryFields {
-		if value := bone.GetQuery(r, name); len(value) == 1 {
-			query[name] = value[0]
-		}
+	agg, err := getStringQuery(r, "agg")
+	if err != nil {
+		return nil, err
+	}
+	if interval > 0 && agg == "" {
+		agg = defAggregation
 	}
 
 	req := listMessagesReq{
-		chanID: chanID,
+		pageReq:     page,
+		chanID:      chanID,
+		interval:    interval.Seconds(),
+		aggregation: agg,
+	}
+
+	return req, nil
+}
+
+// decodeListChannels decodes the listing of the channels of the query, each
+// of which has to be accessible.
+func decodeListChannels(_ context.Context, r *http.Request) (interface{}, error) {
+	chanIDs := bone.GetQuery(r, "channel")
+	for _, chanID := range chanIDs {
+		if chanID == "" {
+			return nil, errInvalidRequest
+		}
+		if err := authorize(r, chanID); err != nil {
+			return nil, err
+		}
+	}
+
+	page, err := decodePage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listChannelsMessagesReq{
+		pageReq: page,
+		token:   r.Header.Get("Authorization"),
+		chanIDs: chanIDs,
+	}
+
+	return req, nil
+}
+
+// decodeListThing decodes the listing of the messages of the thing, its
+// channels are authorized once they are known.
+func decodeListThing(_ context.Context, r *http.Request) (interface{}, error) {
+	token := r.Header.Get("Authorization")
+	if token == "" {
+		return nil, errUnauthorizedAccess
+	}
+
+	page, err := decodePage(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listChannelsMessagesReq{
+		pageReq: page,
+		token:   token,
+		thingID: bone.GetValue(r, "thingID"),
+	}
+
+	return req, nil
+}
+
+func decodePage(r *http.Request) (pageReq, error) {
+	offset, err := getQuery(r, "offset", defOffset)
+	if err != nil {
+		return pageReq{}, err
+	}
+
+	limit, err := getQuery(r, "limit", defLimit)
+	if err != nil {
+		return pageReq{}, err
+	}
+
+	from, err := getFloatQuery(r, "from")
+	if err != nil {
+		return pageReq{}, err
+	}
+
+	to, err := getFloatQuery(r, "to")
+	if err != nil {
+		return pageReq{}, err
+	}
+
+	order, err := getStringQuery(r, "order")
+	if err != nil {
+		return pageReq{}, err
+	}
+
+	cursor, err := getStringQuery(r, "cursor")
+	if err != nil {
+		return pageReq{}, err
+	}
+
+	req := pageReq{
 		offset: offset,
 		limit:  limit,
-		query:  query,
+		from:   from,
+		to:     to,
+		order:  order,
+		cursor: cursor,
+		query:  getFilters(r),
+	}
+
+	return req, nil
+}
+
+func decodeExport(_ context.Context, r *http.Request) (interface{}, error) {
+	chanID := bone.GetValue(r, "chanID")
+	if chanID == "" {
+		return nil, errInvalidRequest
+	}
+
+	if err := authorize(r, chanID); err != nil {
+		return nil, err
+	}
+
+	from, err := getFloatQuery(r, "from")
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := getFloatQuery(r, "to")
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := getStringQuery(r, "order")
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := getStringQuery(r, "format")
+	if err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = defFormat
+	}
+
+	req := exportMessagesReq{
+		chanID: chanID,
+		from:   from,
+		to:     to,
+		order:  order,
+		format: format,
+		gzip:   strings.Contains(r.Header.Get("Accept-Encoding"), "gzip"),
+		query:  getFilters(r),
 	}
 
 	return req, nil
 }
 
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	if res, ok := response.(exportRes); ok {
+		return encodeExport(w, res)
+	}
+
 	w.Header().Set("Content-Type", contentType)
 
 	if ar, ok := response.(mainflux.Response); ok {
@@ -114,7 +282,7 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch err {
 	case nil:
-	case errInvalidRequest:
+	case errInvalidRequest, readers.ErrInvalidCursor:
 		w.WriteHeader(http.StatusBadRequest)
 	case errUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
@@ -124,7 +292,10 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 }
 
 func authorize(r *http.Request, chanID string) error {
-	token := r.Header.Get("Authorization")
+	return authorizeToken(r.Header.Get("Authorization"), chanID)
+}
+
+func authorizeToken(token, chanID string) error {
 	if token == "" {
 		return errUnauthorizedAccess
 	}
@@ -144,6 +315,16 @@ func authorize(r *http.Request, chanID string) error {
 	return nil
 }
 
+func getFilters(req *http.Request) map[string]string {
+	query := map[string]string{}
+	for _, name := range queryFields {
+		if value := bone.GetQuery(req, name); len(value) == 1 {
+			query[name] = value[0]
+		}
+	}
+	return query
+}
+
 func getQuery(req *http.Request, name string, fallback uint64) (uint64, error) {
 	vals := bone.GetQuery(req, name)
 	if len(vals) == 0 {
@@ -161,3 +342,48 @@ func getQuery(req *http.Request, name string, fallback uint64) (uint64, error) {
 
 	return uint64(val), nil
 }
+
+func getFloatQuery(req *http.Request, name string) (float64, error) {
+	vals := bone.GetQuery(req, name)
+	if len(vals) == 0 {
+		return 0, nil
+	}
+
+	if len(vals) > 1 {
+		return 0, errInvalidRequest
+	}
+
+	val, err := strconv.ParseFloat(vals[0], 64)
+	if err != nil {
+		return 0, errInvalidRequest
+	}
+
+	return val, nil
+}
+
+func getStringQuery(req *http.Request, name string) (string, error) {
+	vals := bone.GetQuery(req, name)
+	if len(vals) > 1 {
+		return "", errInvalidRequest
+	}
+
+	if len(vals) == 0 {
+		return "", nil
+	}
+
+	return vals[0], nil
+}
+
+func getDurationQuery(req *http.Request, name string) (time.Duration, error) {
+	val, err := getStringQuery(req, name)
+	if err != nil || val == "" {
+		return 0, err
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return 0, errInvalidRequest
+	}
+
+	return d, nil
+}
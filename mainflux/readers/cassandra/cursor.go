@@ -0,0 +1,118 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cassandra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloustone/pandas/mainflux/readers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/gocql/gocql"
+)
+
+// pageCursor is the position of a message in the merged channels.
+type pageCursor struct {
+	time   float64
+	chanID string
+	id     gocql.UUID
+}
+
+func encodeCursor(c pageCursor) string {
+	return readers.EncodeCursor(readers.Cursor{
+		Time: c.time,
+		ID:   fmt.Sprintf("%s/%s", c.chanID, c.id),
+	})
+}
+
+func decodeCursor(token string) (pageCursor, error) {
+	c, err := readers.DecodeCursor(token)
+	if err != nil {
+		return pageCursor{}, err
+	}
+
+	i := strings.LastIndex(c.ID, "/")
+	if i < 0 {
+		return pageCursor{}, readers.ErrInvalidCursor
+	}
+	id, err := gocql.ParseUUID(c.ID[i+1:])
+	if err != nil {
+		return pageCursor{}, readers.ErrInvalidCursor
+	}
+
+	return pageCursor{
+		time:   c.Time,
+		chanID: c.ID[:i],
+		id:     id,
+	}, nil
+}
+
+// channelRows iterates over the messages of a channel following the cursor.
+type channelRows struct {
+	chanID  string
+	scanner gocql.Scanner
+	cursor  *pageCursor
+	passed  bool
+	closed  bool
+
+	ok  bool
+	msg senml.Message
+	id  gocql.UUID
+}
+
+// next advances to the next message, skipping the ones at the time of the
+// cursor which precede it.
+func (r *channelRows) next() error {
+	for r.scanner.Next() {
+		var id gocql.UUID
+		msg, err := scanMessage(r.scanner, &id)
+		if err != nil {
+			return err
+		}
+		if r.skip(msg, id) {
+			continue
+		}
+
+		r.ok, r.msg, r.id = true, msg, id
+		return nil
+	}
+
+	r.ok = false
+	return r.close()
+}
+
+func (r *channelRows) skip(msg senml.Message, id gocql.UUID) bool {
+	c := r.cursor
+	if c == nil || msg.Time != c.time || r.chanID > c.chanID {
+		return false
+	}
+	if r.chanID < c.chanID {
+		return true
+	}
+	if r.passed {
+		return false
+	}
+	r.passed = id == c.id
+	return true
+}
+
+// precedes checks whether the current message of the rows precedes the one
+// of the other rows.
+func (r *channelRows) precedes(other *channelRows, asc bool) bool {
+	if r.msg.Time != other.msg.Time {
+		if asc {
+			return r.msg.Time < other.msg.Time
+		}
+		return r.msg.Time > other.msg.Time
+	}
+	return r.chanID < other.chanID
+}
+
+func (r *channelRows) close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.scanner.Err()
+}
@@ -5,12 +5,17 @@ package cassandra
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	"github.com/gocql/gocql"
 )
 
+const messageColumns = `channel, subtopic, publisher, protocol, name, unit,
+	value, string_value, bool_value, data_value, sum, time, update_time`
+
 var _ readers.MessageRepository = (*cassandraRepository)(nil)
 
 type cassandraRepository struct {
@@ -24,80 +29,302 @@ func New(session *gocql.Session) readers.MessageRepository {
 	}
 }
 
-func (cr cassandraRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
-	names := []string{}
-	vals := []interface{}{chanID}
-	for name, val := range query {
-		names = append(names, name)
-		vals = append(vals, val)
-	}
-	vals = append(vals, offset+limit)
-
-	selectCQL := buildSelectQuery(chanID, offset, limit, names)
-	countCQL := buildCountQuery(chanID, names)
+func (cr cassandraRepository) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	return cr.ReadChannels([]string{chanID}, pm)
+}
 
-	iter := cr.session.Query(selectCQL, vals...).Iter()
-	defer iter.Close()
-	scanner := iter.Scanner()
+// ReadChannels merges the messages of the channels, since CQL orders the
+// messages only within the partition of a channel. The messages sharing the
+// time are ordered by the channel and then by the clustering order.
+func (cr cassandraRepository) ReadChannels(chanIDs []string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	chanIDs = append([]string{}, chanIDs...)
+	sort.Strings(chanIDs)
+	asc := pm.Order == readers.AscOrder
 
-	// skip first OFFSET rows
-	for i := uint64(0); i < offset; i++ {
-		if !scanner.Next() {
-			break
+	var cur *pageCursor
+	offset := pm.Offset
+	if pm.Cursor != "" {
+		c, err := decodeCursor(pm.Cursor)
+		if err != nil {
+			return readers.MessagesPage{}, err
 		}
+		cur = &c
+		offset = 0
 	}
 
 	page := readers.MessagesPage{
 		Offset:   offset,
-		Limit:    limit,
+		Limit:    pm.Limit,
 		Messages: []senml.Message{},
 	}
 
-	for scanner.Next() {
-		var msg senml.Message
-		err := scanner.Scan(&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
-			&msg.Name, &msg.Unit, &msg.Value, &msg.StringValue, &msg.BoolValue,
-			&msg.DataValue, &msg.Sum, &msg.Time, &msg.UpdateTime)
+	rows := make([]*channelRows, len(chanIDs))
+	defer func() {
+		for _, r := range rows {
+			if r != nil {
+				r.close()
+			}
+		}
+	}()
+	for i, chanID := range chanIDs {
+		r, err := cr.readChannel(chanID, pm, offset, cur, asc)
 		if err != nil {
 			return readers.MessagesPage{}, err
 		}
-		page.Messages = append(page.Messages, msg)
+		rows[i] = r
+	}
+
+	var last *channelRows
+	for skipped := uint64(0); uint64(len(page.Messages)) < pm.Limit; {
+		var next *channelRows
+		for _, r := range rows {
+			if r.ok && (next == nil || r.precedes(next, asc)) {
+				next = r
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		if skipped < offset {
+			skipped++
+		} else {
+			page.Messages = append(page.Messages, next.msg)
+			last = next
+		}
+		if uint64(len(page.Messages)) == pm.Limit {
+			break
+		}
+		if err := next.next(); err != nil {
+			return readers.MessagesPage{}, err
+		}
 	}
 
-	if err := cr.session.Query(countCQL, vals[:len(vals)-1]...).Scan(&page.Total); err != nil {
-		return readers.MessagesPage{}, err
+	if last != nil && uint64(len(page.Messages)) == pm.Limit {
+		page.NextCursor = encodeCursor(pageCursor{
+			time:   last.msg.Time,
+			chanID: last.chanID,
+			id:     last.id,
+		})
+	}
+
+	if cur != nil {
+		return page, nil
+	}
+
+	for _, chanID := range chanIDs {
+		condCQL, vals := buildCondition(chanID, pm.From, pm.To, pm.Query)
+		countCQL := fmt.Sprintf(`SELECT COUNT(*) FROM messages WHERE %s ALLOW FILTERING`, condCQL)
+
+		var total uint64
+		if err := cr.session.Query(countCQL, vals...).Scan(&total); err != nil {
+			return readers.MessagesPage{}, err
+		}
+		page.Total += total
 	}
 
 	return page, nil
 }
 
-func buildSelectQuery(chanID string, offset, limit uint64, names []string) string {
-	var condCQL string
-	cql := `SELECT channel, subtopic, publisher, protocol, name, unit,
-	        value, string_value, bool_value, data_value, sum, time,
-			update_time FROM messages WHERE channel = ? %s LIMIT ?
-			ALLOW FILTERING`
+// readChannel queries the messages of the channel which may belong to the
+// page. The cursor replaces the time bound on its side, since it lies within
+// the time range.
+func (cr cassandraRepository) readChannel(chanID string, pm readers.PageMetadata, offset uint64, cur *pageCursor, asc bool) (*channelRows, error) {
+	from, to := pm.From, pm.To
+	switch {
+	case cur != nil && asc:
+		from = 0
+	case cur != nil:
+		to = 0
+	}
+	condCQL, vals := buildCondition(chanID, from, to, pm.Query)
 
-	for _, name := range names {
-		switch name {
-		case
-			"channel",
-			"subtopic",
-			"publisher",
-			"name",
-			"protocol":
-			condCQL = fmt.Sprintf(`%s AND %s = ?`, condCQL, name)
+	limit := ""
+	switch {
+	case cur != nil && asc:
+		condCQL = fmt.Sprintf(`%s AND time >= ?`, condCQL)
+		vals = append(vals, cur.time)
+	case cur != nil:
+		condCQL = fmt.Sprintf(`%s AND time <= ?`, condCQL)
+		vals = append(vals, cur.time)
+	default:
+		limit = "LIMIT ?"
+		vals = append(vals, offset+pm.Limit)
+	}
+
+	// The messages of a channel are clustered by time in descending order
+	order := ""
+	if asc {
+		order = "ORDER BY time ASC"
+	}
+	cql := fmt.Sprintf(`SELECT %s, id FROM messages WHERE %s %s %s ALLOW FILTERING`,
+		messageColumns, condCQL, order, limit)
+
+	r := &channelRows{
+		chanID:  chanID,
+		scanner: cr.session.Query(cql, vals...).Iter().Scanner(),
+		cursor:  cur,
+	}
+	if err := r.next(); err != nil {
+		r.close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Export relies on the driver to fetch the next page of the rows when the
+// current one is iterated over.
+func (cr cassandraRepository) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) error {
+	condCQL, vals := buildCondition(chanID, em.From, em.To, em.Query)
+
+	order := ""
+	if em.Order == readers.AscOrder {
+		order = "ORDER BY time ASC"
+	}
+	cql := fmt.Sprintf(`SELECT %s FROM messages WHERE %s %s ALLOW FILTERING`,
+		messageColumns, condCQL, order)
+
+	iter := cr.session.Query(cql, vals...).Iter()
+	scanner := iter.Scanner()
+	for scanner.Next() {
+		msg, err := scanMessage(scanner)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		if err := fn(msg); err != nil {
+			iter.Close()
+			return err
+		}
+	}
+
+	return iter.Close()
+}
+
+// Aggregate buckets the values while paging through the messages in time
+// order, since CQL only groups by the primary key columns.
+// PublisherChannels filters all the messages, since the publisher isn't a
+// part of the primary key.
+func (cr cassandraRepository) PublisherChannels(publisher string) ([]string, error) {
+	iter := cr.session.Query(`SELECT channel FROM messages WHERE publisher = ? ALLOW FILTERING`, publisher).Iter()
+
+	set := map[string]bool{}
+	var chanID string
+	for iter.Scan(&chanID) {
+		set[chanID] = true
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	chanIDs := []string{}
+	for chanID := range set {
+		chanIDs = append(chanIDs, chanID)
+	}
+	sort.Strings(chanIDs)
+
+	return chanIDs, nil
+}
+
+// Aggregate reads the values from the longest rollup up to the end of its
+// last bucket, and from the raw messages after it, which may since be
+// deleted from before.
+func (cr cassandraRepository) Aggregate(chanID string, am readers.AggregationMetadata) ([]readers.Series, error) {
+	agg := readers.NewAggregator(am.Interval, am.Aggregation)
+
+	raw := am
+	for _, r := range readers.RollupsOf(am) {
+		end, err := cr.rollupEnd(chanID, r)
+		if err != nil {
+			return nil, err
+		}
+		if end == 0 {
+			continue
+		}
+
+		rm, rest, ok := r.Split(am, end)
+		if ok {
+			if err := cr.aggregateRollup(agg, chanID, r, rm); err != nil {
+				return nil, err
+			}
+		}
+		raw = rest
+		break
+	}
+
+	if raw.Empty() {
+		return agg.Series(), nil
+	}
+
+	condCQL, vals := buildCondition(chanID, raw.From, raw.To, raw.Query)
+	cql := fmt.Sprintf(`SELECT name, value, time FROM messages WHERE %s
+			ORDER BY time ASC ALLOW FILTERING`, condCQL)
+
+	iter := cr.session.Query(cql, vals...).Iter()
+	scanner := iter.Scanner()
+
+	for scanner.Next() {
+		var name string
+		var value *float64
+		var t float64
+		if err := scanner.Scan(&name, &value, &t); err != nil {
+			iter.Close()
+			return nil, err
+		}
+		if value != nil {
+			agg.Add(name, t, *value)
 		}
 	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
 
-	return fmt.Sprintf(cql, condCQL)
+	return agg.Series(), nil
 }
 
-func buildCountQuery(chanID string, names []string) string {
-	var condCQL string
-	cql := `SELECT COUNT(*) FROM messages WHERE channel = ? %s ALLOW FILTERING`
+func (cr cassandraRepository) aggregateRollup(agg *readers.Aggregator, chanID string, r readers.Rollup, am readers.AggregationMetadata) error {
+	condCQL, vals := buildCondition(chanID, am.From, am.To, am.Query)
+	cql := fmt.Sprintf(`SELECT name, time, count, sum, min, max, last FROM messages_%s
+			WHERE %s ORDER BY time ASC ALLOW FILTERING`, r.Name, condCQL)
+
+	iter := cr.session.Query(cql, vals...).Iter()
+	scanner := iter.Scanner()
+
+	for scanner.Next() {
+		var name string
+		var b readers.Bucket
+		if err := scanner.Scan(&name, &b.Time, &b.Count, &b.Sum, &b.Min, &b.Max, &b.Last); err != nil {
+			iter.Close()
+			return err
+		}
+		agg.Merge(name, b)
+	}
 
-	for _, name := range names {
+	return iter.Close()
+}
+
+// rollupEnd returns the end of the last bucket of the rollup of the channel,
+// or 0 if the channel isn't rolled up.
+func (cr cassandraRepository) rollupEnd(chanID string, r readers.Rollup) (float64, error) {
+	cql := fmt.Sprintf(`SELECT time FROM messages_%s WHERE channel = ? LIMIT 1`, r.Name)
+
+	var last float64
+	switch err := cr.session.Query(cql, chanID).Scan(&last); {
+	case err == nil:
+		return last + r.Interval, nil
+	case err == gocql.ErrNotFound,
+		// The rollup tables are created by the writer.
+		strings.Contains(err.Error(), "unconfigured table"):
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+func buildCondition(chanID string, from, to float64, query map[string]string) (string, []interface{}) {
+	condCQL := `channel = ?`
+	vals := []interface{}{chanID}
+	for name, val := range query {
 		switch name {
 		case
 			"channel",
@@ -106,8 +333,27 @@ func buildCountQuery(chanID string, names []string) string {
 			"name",
 			"protocol":
 			condCQL = fmt.Sprintf(`%s AND %s = ?`, condCQL, name)
+			vals = append(vals, val)
 		}
 	}
+	if from > 0 {
+		condCQL = fmt.Sprintf(`%s AND time >= ?`, condCQL)
+		vals = append(vals, from)
+	}
+	if to > 0 {
+		condCQL = fmt.Sprintf(`%s AND time < ?`, condCQL)
+		vals = append(vals, to)
+	}
+
+	return condCQL, vals
+}
 
-	return fmt.Sprintf(cql, condCQL)
+// scanMessage scans the message columns followed by the extra ones.
+func scanMessage(scanner gocql.Scanner, extra ...interface{}) (senml.Message, error) {
+	var msg senml.Message
+	dest := []interface{}{&msg.Channel, &msg.Subtopic, &msg.Publisher, &msg.Protocol,
+		&msg.Name, &msg.Unit, &msg.Value, &msg.StringValue, &msg.BoolValue,
+		&msg.DataValue, &msg.Sum, &msg.Time, &msg.UpdateTime}
+	err := scanner.Scan(append(dest, extra...)...)
+	return msg, err
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	creaders "github.com/cloustone/pandas/mainflux/readers/cassandra"
+	"github.com/cloustone/pandas/mainflux/readers/readerstest"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	cwriters "github.com/cloustone/pandas/mainflux/writers/cassandra"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +20,7 @@ import (
 const (
 	keyspace    = "mainflux"
 	chanID      = "1"
+	confChanID  = "2"
 	subtopic    = "subtopic"
 	msgsNum     = 42
 	valueFields = 5
@@ -33,6 +35,12 @@ var (
 	}
 )
 
+// The channels of the publisher of the queries across the channels.
+var (
+	channelsPubID   = "2"
+	channelsChanIDs = []string{"3", "4"}
+)
+
 var (
 	v       float64 = 5
 	stringV         = "value"
@@ -153,9 +161,49 @@ func TestReadAll(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		result, err := reader.ReadAll(tc.chanID, tc.offset, tc.limit, tc.query)
+		pm := readers.PageMetadata{
+			Offset: tc.offset,
+			Limit:  tc.limit,
+			Query:  tc.query,
+		}
+		result, err := reader.ReadAll(tc.chanID, pm)
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
 		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Messages, result.Messages))
 		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
 	}
 }
+
+func TestReadAllConformance(t *testing.T) {
+	session, err := creaders.Connect(creaders.DBConfig{
+		Hosts:    []string{addr},
+		Keyspace: keyspace,
+	})
+	require.Nil(t, err, fmt.Sprintf("failed to connect to Cassandra: %s", err))
+	defer session.Close()
+
+	messages := readerstest.Messages(confChanID, "1")
+	err = cwriters.New(session).Save(messages...)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	readerstest.Run(t, creaders.New(session), confChanID, messages)
+}
+
+func TestReadChannelsConformance(t *testing.T) {
+	session, err := creaders.Connect(creaders.DBConfig{
+		Hosts:    []string{addr},
+		Keyspace: keyspace,
+	})
+	require.Nil(t, err, fmt.Sprintf("failed to connect to Cassandra: %s", err))
+	defer session.Close()
+	writer := cwriters.New(session)
+
+	messages := []senml.Message{}
+	for _, chanID := range channelsChanIDs {
+		msgs := readerstest.Messages(chanID, channelsPubID)
+		err = writer.Save(msgs...)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+		messages = append(messages, msgs...)
+	}
+
+	readerstest.RunChannels(t, creaders.New(session), channelsPubID, channelsChanIDs, messages)
+}
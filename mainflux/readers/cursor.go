@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor indicates that the page cursor is malformed.
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// Cursor is the position of a message in the ordered result set. The ID
+// breaks the ties of the messages sharing the time, its meaning is up to the
+// repository.
+type Cursor struct {
+	Time float64 `json:"t"`
+	ID   string  `json:"id"`
+}
+
+// EncodeCursor returns the opaque token of the cursor.
+func EncodeCursor(c Cursor) string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor returns the cursor of the token.
+func DecodeCursor(token string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
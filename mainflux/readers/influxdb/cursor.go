@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb
+
+import (
+	"fmt"
+
+	"github.com/cloustone/pandas/mainflux/readers"
+)
+
+// pageCursor is the time of a message in nanoseconds along with the number
+// of the messages of the time up to and including it.
+type pageCursor struct {
+	time int64
+	skip uint64
+}
+
+func encodeCursor(c pageCursor) string {
+	return readers.EncodeCursor(readers.Cursor{
+		Time: float64(c.time) / 1e9,
+		ID:   fmt.Sprintf("%d:%d", c.time, c.skip),
+	})
+}
+
+func decodeCursor(token string) (pageCursor, error) {
+	c, err := readers.DecodeCursor(token)
+	if err != nil {
+		return pageCursor{}, err
+	}
+
+	var pc pageCursor
+	if _, err := fmt.Sscanf(c.ID, "%d:%d", &pc.time, &pc.skip); err != nil {
+		return pageCursor{}, readers.ErrInvalidCursor
+	}
+	return pc, nil
+}
@@ -2,8 +2,10 @@ package influxdb
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,7 +16,15 @@ import (
 	influxdata "github.com/influxdata/influxdb/client/v2"
 )
 
-const countCol = "count"
+const (
+	countCol = "count"
+	timeCol  = "time"
+
+	// exportBatchSize is the number of messages queried at once by export.
+	exportBatchSize = 1000
+)
+
+var errMissingTime = errors.New("missing message time")
 
 var _ readers.MessageRepository = (*influxRepository)(nil)
 
@@ -31,16 +41,47 @@ func New(client influxdata.Client, database string) readers.MessageRepository {
 	}
 }
 
-func (repo *influxRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
-	condition := fmtCondition(chanID, query)
-	cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time DESC LIMIT %d OFFSET %d`, condition, limit, offset)
+func (repo *influxRepository) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	return repo.ReadChannels([]string{chanID}, pm)
+}
+
+// ReadChannels pages by the time when the cursor is given. Since the
+// messages lack an ID, the cursor holds the number of the messages of its
+// time which are already read, and these are skipped by the offset.
+func (repo *influxRepository) ReadChannels(chanIDs []string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	page := readers.MessagesPage{
+		Offset:   pm.Offset,
+		Limit:    pm.Limit,
+		Messages: []senml.Message{},
+	}
+	if len(chanIDs) == 0 {
+		return page, nil
+	}
+
+	order, cmp, before := "DESC", "<=", ">"
+	if pm.Order == readers.AscOrder {
+		order, cmp, before = "ASC", ">=", "<"
+	}
+	condition := fmtCondition(chanIDs, pm.From, pm.To, pm.Query)
+
+	pageCond, offset := condition, pm.Offset
+	var cur pageCursor
+	if pm.Cursor != "" {
+		var err error
+		if cur, err = decodeCursor(pm.Cursor); err != nil {
+			return readers.MessagesPage{}, err
+		}
+		pageCond = fmt.Sprintf(`%s AND time %s %d`, condition, cmp, cur.time)
+		offset = cur.skip
+		page.Offset = 0
+	}
+
+	cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time %s LIMIT %d OFFSET %d`, pageCond, order, pm.Limit, offset)
 	q := influxdata.Query{
 		Command:  cmd,
 		Database: repo.database,
 	}
 
-	ret := []senml.Message{}
-
 	resp, err := repo.client.Query(q)
 	if err != nil {
 		return readers.MessagesPage{}, err
@@ -49,26 +90,271 @@ func (repo *influxRepository) ReadAll(chanID string, offset, limit uint64, query
 		return readers.MessagesPage{}, resp.Error()
 	}
 
+	// The number of the messages of the page sharing the time of the last.
+	var last int64
+	tied := uint64(0)
+	if len(resp.Results) > 0 && len(resp.Results[0].Series) > 0 {
+		result := resp.Results[0].Series[0]
+		for _, v := range result.Values {
+			t, err := rowTime(result.Columns, v)
+			if err != nil {
+				return readers.MessagesPage{}, err
+			}
+			if t != last {
+				last, tied = t, 0
+			}
+			tied++
+			page.Messages = append(page.Messages, parseMessage(result.Columns, v))
+		}
+	}
+
+	if pm.Limit > 0 && uint64(len(page.Messages)) == pm.Limit {
+		next := pageCursor{time: last, skip: tied}
+		if tied == pm.Limit {
+			// The messages of the time may precede the page as well.
+			switch {
+			case pm.Cursor != "" && last == cur.time:
+				next.skip += cur.skip
+			case pm.Cursor == "" && pm.Offset > 0:
+				preceding, err := repo.count(fmt.Sprintf(`%s AND time %s %d`, condition, before, last))
+				if err != nil {
+					return readers.MessagesPage{}, err
+				}
+				next.skip = pm.Offset + pm.Limit - preceding
+			}
+		}
+		page.NextCursor = encodeCursor(next)
+	}
+
+	if pm.Cursor != "" {
+		return page, nil
+	}
+
+	if page.Total, err = repo.count(condition); err != nil {
+		return readers.MessagesPage{}, err
+	}
+
+	return page, nil
+}
+
+func (repo *influxRepository) PublisherChannels(publisher string) ([]string, error) {
+	cmd := fmt.Sprintf(`SHOW TAG VALUES FROM messages WITH KEY = "channel" WHERE publisher='%s'`,
+		strings.Replace(publisher, "'", "\\'", -1))
+	q := influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	}
+
+	resp, err := repo.client.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error() != nil {
+		return nil, resp.Error()
+	}
+
+	chanIDs := []string{}
 	if len(resp.Results) < 1 || len(resp.Results[0].Series) < 1 {
-		return readers.MessagesPage{}, nil
+		return chanIDs, nil
+	}
+	for _, v := range resp.Results[0].Series[0].Values {
+		if len(v) < 2 {
+			continue
+		}
+		if chanID, ok := v[1].(string); ok {
+			chanIDs = append(chanIDs, chanID)
+		}
+	}
+	sort.Strings(chanIDs)
+
+	return chanIDs, nil
+}
+
+// Export pages through the messages by time instead of offset, so the query
+// doesn't slow down towards the end of the result set. Since the messages may
+// share the time, the ones of the last time of a page which are already
+// exported are skipped by the offset of the next page.
+func (repo *influxRepository) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) error {
+	order, cmp := "DESC", "<="
+	if em.Order == readers.AscOrder {
+		order, cmp = "ASC", ">="
+	}
+	condition := fmtCondition([]string{chanID}, em.From, em.To, em.Query)
+
+	cond := condition
+	var last int64
+	skip := 0
+	for {
+		cmd := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time %s LIMIT %d OFFSET %d`, cond, order, exportBatchSize, skip)
+		q := influxdata.Query{
+			Command:  cmd,
+			Database: repo.database,
+		}
+
+		resp, err := repo.client.Query(q)
+		if err != nil {
+			return err
+		}
+		if resp.Error() != nil {
+			return resp.Error()
+		}
+		if len(resp.Results) < 1 || len(resp.Results[0].Series) < 1 {
+			return nil
+		}
+
+		result := resp.Results[0].Series[0]
+		for _, v := range result.Values {
+			t, err := rowTime(result.Columns, v)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(parseMessage(result.Columns, v)); err != nil {
+				return err
+			}
+
+			if t == last {
+				skip++
+				continue
+			}
+			last, skip = t, 1
+		}
+
+		if len(result.Values) < exportBatchSize {
+			return nil
+		}
+		cond = fmt.Sprintf(`%s AND time %s %d`, condition, cmp, last)
+	}
+}
+
+// Aggregate reads the values from the longest rollup up to the end of its
+// last bucket, and from the raw messages after it, which may since be
+// deleted from before.
+func (repo *influxRepository) Aggregate(chanID string, am readers.AggregationMetadata) ([]readers.Series, error) {
+	agg := readers.NewAggregator(am.Interval, am.Aggregation)
+
+	raw := am
+	rollups := readers.RollupsOf(am)
+	if _, ok := am.Query["protocol"]; ok {
+		// The protocol is a field, so it's not kept by the rollups.
+		rollups = nil
+	}
+	for _, r := range rollups {
+		end, err := repo.rollupEnd(chanID, r)
+		if err != nil {
+			return nil, err
+		}
+		if end == 0 {
+			continue
+		}
+
+		rm, rest, ok := r.Split(am, end)
+		if ok {
+			sel := `SELECT SUM("count"), SUM("sum"), MIN("min"), MAX("max"), LAST("last")`
+			if err := repo.aggregate(agg, sel, fmt.Sprintf("messages_%s", r.Name), chanID, rm); err != nil {
+				return nil, err
+			}
+		}
+		raw = rest
+		break
 	}
 
-	result := resp.Results[0].Series[0]
-	for _, v := range result.Values {
-		ret = append(ret, parseMessage(result.Columns, v))
+	if !raw.Empty() {
+		sel := `SELECT COUNT(value), SUM(value), MIN(value), MAX(value), LAST(value)`
+		if err := repo.aggregate(agg, sel, "messages", chanID, raw); err != nil {
+			return nil, err
+		}
 	}
 
-	total, err := repo.count(condition)
+	return agg.Series(), nil
+}
+
+// aggregate merges the buckets selected from the measurement into the
+// aggregator.
+func (repo *influxRepository) aggregate(agg *readers.Aggregator, sel, measurement, chanID string, am readers.AggregationMetadata) error {
+	cmd := fmt.Sprintf(`%s FROM %s WHERE %s GROUP BY time(%dns), "name" fill(none)`,
+		sel, measurement, fmtCondition([]string{chanID}, am.From, am.To, am.Query), int64(am.Interval*1e9))
+	q := influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	}
+
+	resp, err := repo.client.Query(q)
 	if err != nil {
-		return readers.MessagesPage{}, err
+		return err
+	}
+	if resp.Error() != nil {
+		return resp.Error()
+	}
+	if len(resp.Results) < 1 {
+		return nil
+	}
+
+	for _, row := range resp.Results[0].Series {
+		for _, v := range row.Values {
+			if len(v) < 6 {
+				continue
+			}
+			ts, ok := v[0].(string)
+			if !ok {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339Nano, ts)
+			if err != nil {
+				return err
+			}
+
+			vals := make([]float64, 5)
+			for i := range vals {
+				num, ok := v[i+1].(json.Number)
+				if !ok {
+					continue
+				}
+				if vals[i], err = num.Float64(); err != nil {
+					return err
+				}
+			}
+			agg.Merge(row.Tags["name"], readers.Bucket{
+				Time:  float64(t.UnixNano()) / 1e9,
+				Count: vals[0],
+				Sum:   vals[1],
+				Min:   vals[2],
+				Max:   vals[3],
+				Last:  vals[4],
+			})
+		}
 	}
 
-	return readers.MessagesPage{
-		Total:    total,
-		Offset:   offset,
-		Limit:    limit,
-		Messages: ret,
-	}, nil
+	return nil
+}
+
+// rollupEnd returns the end of the last bucket of the rollup of the channel,
+// or 0 if the channel isn't rolled up.
+func (repo *influxRepository) rollupEnd(chanID string, r readers.Rollup) (float64, error) {
+	cmd := fmt.Sprintf(`SELECT LAST("count") FROM messages_%s WHERE %s`, r.Name, fmtCondition([]string{chanID}, 0, 0, nil))
+	q := influxdata.Query{
+		Command:  cmd,
+		Database: repo.database,
+	}
+
+	resp, err := repo.client.Query(q)
+	if err != nil {
+		return 0, err
+	}
+	if resp.Error() != nil {
+		return 0, resp.Error()
+	}
+	if len(resp.Results) < 1 || len(resp.Results[0].Series) < 1 || len(resp.Results[0].Series[0].Values) < 1 {
+		return 0, nil
+	}
+
+	row := resp.Results[0].Series[0]
+	t, err := rowTime(row.Columns, row.Values[0])
+	if err != nil {
+		return 0, err
+	}
+
+	return float64(t)/1e9 + r.Interval, nil
 }
 
 func (repo *influxRepository) count(condition string) (uint64, error) {
@@ -113,8 +399,12 @@ func (repo *influxRepository) count(condition string) (uint64, error) {
 	return strconv.ParseUint(count.String(), 10, 64)
 }
 
-func fmtCondition(chanID string, query map[string]string) string {
-	condition := fmt.Sprintf(`channel='%s'`, chanID)
+func fmtCondition(chanIDs []string, from, to float64, query map[string]string) string {
+	channels := make([]string, len(chanIDs))
+	for i, chanID := range chanIDs {
+		channels[i] = fmt.Sprintf(`channel='%s'`, strings.Replace(chanID, "'", "\\'", -1))
+	}
+	condition := fmt.Sprintf(`(%s)`, strings.Join(channels, " OR "))
 	for name, value := range query {
 		switch name {
 		case
@@ -130,9 +420,34 @@ func fmtCondition(chanID string, query map[string]string) string {
 				strings.Replace(value, "\"", "\\\"", -1))
 		}
 	}
+	if from > 0 {
+		condition = fmt.Sprintf(`%s AND time >= %d`, condition, int64(from*1e9))
+	}
+	if to > 0 {
+		condition = fmt.Sprintf(`%s AND time < %d`, condition, int64(to*1e9))
+	}
 	return condition
 }
 
+// rowTime returns the time of the row in nanoseconds.
+func rowTime(names []string, fields []interface{}) (int64, error) {
+	for i, name := range names {
+		if name != timeCol {
+			continue
+		}
+		ts, ok := fields[i].(string)
+		if !ok {
+			return 0, errMissingTime
+		}
+		t, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return 0, err
+		}
+		return t.UnixNano(), nil
+	}
+	return 0, errMissingTime
+}
+
 // ParseMessage and parseValues are util methods. Since InfluxDB client returns
 // results in form of rows and columns, this obscure message conversion is needed
 // to return actual []broker.Message from the query result.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	reader "github.com/cloustone/pandas/mainflux/readers/influxdb"
+	"github.com/cloustone/pandas/mainflux/readers/readerstest"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	writer "github.com/cloustone/pandas/mainflux/writers/influxdb"
 	influxdata "github.com/influxdata/influxdb/client/v2"
@@ -18,10 +19,17 @@ import (
 )
 
 const (
-	testDB   = "test"
-	chanID   = "1"
-	subtopic = "topic"
-	msgsNum  = 101
+	testDB     = "test"
+	chanID     = "1"
+	confChanID = "2"
+	subtopic   = "topic"
+	msgsNum    = 101
+)
+
+// The channels of the publisher of the queries across the channels.
+var (
+	channelsPubID   = "2"
+	channelsChanIDs = []string{"3", "4"}
 )
 
 var (
@@ -158,10 +166,37 @@ func TestReadAll(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		result, err := reader.ReadAll(tc.chanID, tc.offset, tc.limit, tc.query)
+		pm := readers.PageMetadata{
+			Offset: tc.offset,
+			Limit:  tc.limit,
+			Query:  tc.query,
+		}
+		result, err := reader.ReadAll(tc.chanID, pm)
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
 		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: expected: %v \n-------------\n got: %v", desc, tc.page.Messages, result.Messages))
 
 		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %d got %d", desc, tc.page.Total, result.Total))
 	}
 }
+
+func TestReadAllConformance(t *testing.T) {
+	messages := readerstest.Messages(confChanID, "1")
+	err := writer.New(client, testDB).Save(messages...)
+	require.Nil(t, err, fmt.Sprintf("Save operation expected to succeed: %s.\n", err))
+
+	readerstest.Run(t, reader.New(client, testDB), confChanID, messages)
+}
+
+func TestReadChannelsConformance(t *testing.T) {
+	writer := writer.New(client, testDB)
+
+	messages := []senml.Message{}
+	for _, chanID := range channelsChanIDs {
+		msgs := readerstest.Messages(chanID, channelsPubID)
+		err := writer.Save(msgs...)
+		require.Nil(t, err, fmt.Sprintf("Save operation expected to succeed: %s.\n", err))
+		messages = append(messages, msgs...)
+	}
+
+	readerstest.RunChannels(t, reader.New(client, testDB), channelsPubID, channelsChanIDs, messages)
+}
@@ -12,11 +12,95 @@ import (
 // ErrNotFound indicates that requested entity doesn't exist.
 var ErrNotFound = errors.New("entity not found")
 
+const (
+	// DescOrder orders the messages from the newest to the oldest.
+	DescOrder = "desc"
+	// AscOrder orders the messages from the oldest to the newest.
+	AscOrder = "asc"
+)
+
+// The aggregations of the numeric values in a time bucket.
+const (
+	AggregationAvg   = "avg"
+	AggregationMin   = "min"
+	AggregationMax   = "max"
+	AggregationSum   = "sum"
+	AggregationCount = "count"
+	AggregationLast  = "last"
+)
+
 // MessageRepository specifies message reader API.
 type MessageRepository interface {
 	// ReadAll skips given number of messages for given channel and returns next
 	// limited number of messages.
-	ReadAll(string, uint64, uint64, map[string]string) (MessagesPage, error)
+	ReadAll(string, PageMetadata) (MessagesPage, error)
+
+	// ReadChannels reads the messages of given channels as if they were
+	// sent to a single one.
+	ReadChannels([]string, PageMetadata) (MessagesPage, error)
+
+	// PublisherChannels returns the channels given publisher sent messages
+	// to.
+	PublisherChannels(string) ([]string, error)
+
+	// Aggregate aggregates the numeric values of the messages of given
+	// channel into the time buckets of the interval. A series is returned
+	// for each message name.
+	Aggregate(string, AggregationMetadata) ([]Series, error)
+
+	// Export passes each message of given channel to the callback, iterating
+	// over the result set rather than loading it into memory. The iteration
+	// stops at the first error returned by the callback.
+	Export(string, ExportMetadata, func(senml.Message) error) error
+}
+
+// PageMetadata contains the paging, ordering and filtering of the messages.
+type PageMetadata struct {
+	Offset uint64
+	Limit  uint64
+
+	// Cursor is the NextCursor of the previous page. If it's given, the
+	// page follows the cursor instead of skipping Offset messages, and the
+	// messages aren't counted.
+	Cursor string
+
+	// From and To bound the time of the messages in seconds, From is
+	// inclusive and To is exclusive. Zero leaves the bound open.
+	From float64
+	To   float64
+
+	// Order is either DescOrder or AscOrder, messages are ordered from the
+	// newest if empty.
+	Order string
+
+	// Query contains the equality filters of the message fields.
+	Query map[string]string
+}
+
+// AggregationMetadata contains the time buckets, the aggregation and the
+// filtering of the messages.
+type AggregationMetadata struct {
+	From float64
+	To   float64
+
+	// Interval is the length of the buckets in seconds. The buckets are
+	// aligned to the Unix epoch.
+	Interval float64
+
+	// Aggregation is one of the aggregations applied to the values of a
+	// bucket.
+	Aggregation string
+
+	Query map[string]string
+}
+
+// ExportMetadata contains the ordering and filtering of the exported
+// messages.
+type ExportMetadata struct {
+	From  float64
+	To    float64
+	Order string
+	Query map[string]string
 }
 
 // MessagesPage contains page related metadata as well as list of messages that
@@ -26,4 +110,36 @@ type MessagesPage struct {
 	Offset   uint64
 	Limit    uint64
 	Messages []senml.Message
+
+	// NextCursor points past the last message of a full page, it's empty
+	// once there are no more messages.
+	NextCursor string
+}
+
+// Series contains the aggregated values of the messages having the name,
+// ordered by time. Empty buckets are left out.
+type Series struct {
+	Name   string
+	Points []Point
+}
+
+// Point is the aggregated value of a bucket starting at the time.
+type Point struct {
+	Time  float64
+	Value float64
+}
+
+// ValidOrder checks whether the order is supported, the default order is
+// valid.
+func ValidOrder(order string) bool {
+	return order == "" || order == DescOrder || order == AscOrder
+}
+
+// ValidAggregation checks whether the aggregation is supported.
+func ValidAggregation(agg string) bool {
+	switch agg {
+	case AggregationAvg, AggregationMin, AggregationMax, AggregationSum, AggregationCount, AggregationLast:
+		return true
+	}
+	return false
 }
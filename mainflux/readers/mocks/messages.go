@@ -4,6 +4,8 @@
 package mocks
 
 import (
+	"sort"
+	"strconv"
 	"sync"
 
 	"github.com/cloustone/pandas/mainflux/readers"
@@ -25,29 +27,157 @@ func NewMessageRepository(messages map[string][]senml.Message) readers.MessageRe
 	}
 }
 
-func (repo *messageRepositoryMock) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+func (repo *messageRepositoryMock) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	return repo.ReadChannels([]string{chanID}, pm)
+}
+
+// ReadChannels uses the position of the message in the result set as the
+// cursor, the messages are only appended in the tests.
+func (repo *messageRepositoryMock) ReadChannels(chanIDs []string, pm readers.PageMetadata) (readers.MessagesPage, error) {
 	repo.mutex.Lock()
 	defer repo.mutex.Unlock()
 
-	end := offset + limit
+	msgs := []senml.Message{}
+	for _, chanID := range chanIDs {
+		msgs = append(msgs, filter(repo.messages[chanID], pm.From, pm.To, pm.Query)...)
+	}
+	sort.SliceStable(msgs, func(i, j int) bool {
+		if pm.Order == readers.AscOrder {
+			return msgs[i].Time < msgs[j].Time
+		}
+		return msgs[i].Time > msgs[j].Time
+	})
 
-	numOfMessages := uint64(len(repo.messages[chanID]))
-	if offset < 0 || offset >= numOfMessages {
-		return readers.MessagesPage{}, nil
+	numOfMessages := uint64(len(msgs))
+	offset := pm.Offset
+	if pm.Cursor != "" {
+		c, err := readers.DecodeCursor(pm.Cursor)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		pos, err := strconv.ParseUint(c.ID, 10, 64)
+		if err != nil {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		offset = pos + 1
+		numOfMessages = 0
 	}
 
-	if limit < 1 {
-		return readers.MessagesPage{}, nil
+	page := readers.MessagesPage{
+		Total:    numOfMessages,
+		Limit:    pm.Limit,
+		Offset:   pm.Offset,
+		Messages: []senml.Message{},
+	}
+	if pm.Cursor != "" {
+		page.Offset = 0
+	}
+	if offset >= uint64(len(msgs)) || pm.Limit < 1 {
+		return page, nil
 	}
 
-	if offset+limit > numOfMessages {
-		end = numOfMessages
+	end := offset + pm.Limit
+	if end > uint64(len(msgs)) {
+		end = uint64(len(msgs))
 	}
+	page.Messages = msgs[offset:end]
 
-	return readers.MessagesPage{
-		Total:    numOfMessages,
-		Limit:    limit,
-		Offset:   offset,
-		Messages: repo.messages[chanID][offset:end],
-	}, nil
+	if end-offset == pm.Limit {
+		page.NextCursor = readers.EncodeCursor(readers.Cursor{
+			Time: msgs[end-1].Time,
+			ID:   strconv.FormatUint(end-1, 10),
+		})
+	}
+
+	return page, nil
+}
+
+func (repo *messageRepositoryMock) PublisherChannels(publisher string) ([]string, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	chanIDs := []string{}
+	for chanID, msgs := range repo.messages {
+		for _, msg := range msgs {
+			if msg.Publisher == publisher {
+				chanIDs = append(chanIDs, chanID)
+				break
+			}
+		}
+	}
+	sort.Strings(chanIDs)
+
+	return chanIDs, nil
+}
+
+func (repo *messageRepositoryMock) Aggregate(chanID string, am readers.AggregationMetadata) ([]readers.Series, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	msgs := filter(repo.messages[chanID], am.From, am.To, am.Query)
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].Time < msgs[j].Time })
+
+	agg := readers.NewAggregator(am.Interval, am.Aggregation)
+	for _, msg := range msgs {
+		if msg.Value != nil {
+			agg.Add(msg.Name, msg.Time, *msg.Value)
+		}
+	}
+	return agg.Series(), nil
+}
+
+func (repo *messageRepositoryMock) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	msgs := filter(repo.messages[chanID], em.From, em.To, em.Query)
+	sort.SliceStable(msgs, func(i, j int) bool {
+		if em.Order == readers.AscOrder {
+			return msgs[i].Time < msgs[j].Time
+		}
+		return msgs[i].Time > msgs[j].Time
+	})
+
+	for _, msg := range msgs {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func filter(msgs []senml.Message, from, to float64, query map[string]string) []senml.Message {
+	ret := []senml.Message{}
+	for _, msg := range msgs {
+		if from > 0 && msg.Time < from || to > 0 && msg.Time >= to {
+			continue
+		}
+		if !matches(msg, query) {
+			continue
+		}
+		ret = append(ret, msg)
+	}
+	return ret
+}
+
+func matches(msg senml.Message, query map[string]string) bool {
+	for name, value := range query {
+		var field string
+		switch name {
+		case "subtopic":
+			field = msg.Subtopic
+		case "publisher":
+			field = msg.Publisher
+		case "name":
+			field = msg.Name
+		case "protocol":
+			field = msg.Protocol
+		default:
+			continue
+		}
+		if field != value {
+			return false
+		}
+	}
+	return true
 }
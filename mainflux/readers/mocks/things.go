@@ -35,6 +35,10 @@ func (svc thingsServiceMock) CanAccessByKey(ctx context.Context, in *mainflux.Ac
 		return nil, errUnauthorized
 	}
 
+	if in.GetChanID() == "invalid" {
+		return nil, errUnauthorized
+	}
+
 	return &mainflux.ThingID{Value: token}, nil
 }
 
@@ -45,3 +49,7 @@ func (svc thingsServiceMock) CanAccessByID(context.Context, *mainflux.AccessByID
 func (svc thingsServiceMock) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (svc thingsServiceMock) ViewSchema(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Schema, error) {
+	panic("not implemented")
+}
@@ -5,15 +5,24 @@ package mongodb
 
 import (
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const collection = "mainflux"
+const (
+	collection = "mainflux"
+
+	// exportBatchSize is the number of documents fetched at once by the
+	// export cursor.
+	exportBatchSize = 1000
+)
 
 var _ readers.MessageRepository = (*mongoRepository)(nil)
 
@@ -23,19 +32,20 @@ type mongoRepository struct {
 
 // Message struct is used as a MongoDB representation of Mainflux message.
 type message struct {
-	Channel     string   `bson:"channel,omitempty"`
-	Subtopic    string   `bson:"subtopic,omitempty"`
-	Publisher   string   `bson:"publisher,omitempty"`
-	Protocol    string   `bson:"protocol,omitempty"`
-	Name        string   `bson:"name,omitempty"`
-	Unit        string   `bson:"unit,omitempty"`
-	Value       *float64 `bson:"value,omitempty"`
-	StringValue *string  `bson:"stringValue,omitempty"`
-	BoolValue   *bool    `bson:"boolValue,omitempty"`
-	DataValue   *string  `bson:"dataValue,omitempty"`
-	Sum         *float64 `bson:"sum,omitempty"`
-	Time        float64  `bson:"time,omitempty"`
-	UpdateTime  float64  `bson:"updateTime,omitempty"`
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Channel     string             `bson:"channel,omitempty"`
+	Subtopic    string             `bson:"subtopic,omitempty"`
+	Publisher   string             `bson:"publisher,omitempty"`
+	Protocol    string             `bson:"protocol,omitempty"`
+	Name        string             `bson:"name,omitempty"`
+	Unit        string             `bson:"unit,omitempty"`
+	Value       *float64           `bson:"value,omitempty"`
+	StringValue *string            `bson:"stringValue,omitempty"`
+	BoolValue   *bool              `bson:"boolValue,omitempty"`
+	DataValue   *string            `bson:"dataValue,omitempty"`
+	Sum         *float64           `bson:"sum,omitempty"`
+	Time        float64            `bson:"time,omitempty"`
+	UpdateTime  float64            `bson:"updateTime,omitempty"`
 }
 
 // New returns new MongoDB reader.
@@ -45,73 +55,291 @@ func New(db *mongo.Database) readers.MessageRepository {
 	}
 }
 
-func (repo mongoRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
+func (repo mongoRepository) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	return repo.ReadChannels([]string{chanID}, pm)
+}
+
+// ReadChannels orders the messages by the time and the ID, so the messages
+// following the cursor are found by the index rather than skipped.
+func (repo mongoRepository) ReadChannels(chanIDs []string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	if len(chanIDs) == 0 {
+		return readers.MessagesPage{Offset: pm.Offset, Limit: pm.Limit, Messages: []senml.Message{}}, nil
+	}
+
 	col := repo.db.Collection(collection)
-	sortMap := map[string]interface{}{
-		"time": -1,
+	dir, cmp := -1, "$lt"
+	if pm.Order == readers.AscOrder {
+		dir, cmp = 1, "$gt"
 	}
+	order := bson.D{{Key: "time", Value: dir}, {Key: "_id", Value: dir}}
 
-	filter := fmtCondition(chanID, query)
-	cursor, err := col.Find(context.Background(), filter, options.Find().SetSort(sortMap).SetLimit(int64(limit)).SetSkip(int64(offset)))
+	filter := fmtCondition(chanIDs, pm.From, pm.To, pm.Query)
+	pageFilter := *filter
+	offset := pm.Offset
+	if pm.Cursor != "" {
+		c, err := readers.DecodeCursor(pm.Cursor)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		id, err := primitive.ObjectIDFromHex(c.ID)
+		if err != nil {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		pageFilter = append(pageFilter, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"time": bson.M{cmp: c.Time}},
+			bson.M{"time": c.Time, "_id": bson.M{cmp: id}},
+		}})
+		offset = 0
+	}
+
+	opts := options.Find().SetSort(order).SetLimit(int64(pm.Limit)).SetSkip(int64(offset))
+	cursor, err := col.Find(context.Background(), pageFilter, opts)
 	if err != nil {
 		return readers.MessagesPage{}, err
 	}
 	defer cursor.Close(context.Background())
 
-	messages := []senml.Message{}
+	page := readers.MessagesPage{
+		Offset:   offset,
+		Limit:    pm.Limit,
+		Messages: []senml.Message{},
+	}
+	var last message
 	for cursor.Next(context.Background()) {
 		var m message
 		if err := cursor.Decode(&m); err != nil {
 			return readers.MessagesPage{}, err
 		}
 
-		msg := senml.Message{
-			Channel:    m.Channel,
-			Subtopic:   m.Subtopic,
-			Publisher:  m.Publisher,
-			Protocol:   m.Protocol,
-			Name:       m.Name,
-			Unit:       m.Unit,
-			Time:       m.Time,
-			UpdateTime: m.UpdateTime,
-			Sum:        m.Sum,
-		}
+		page.Messages = append(page.Messages, toMessage(m))
+		last = m
+	}
+	if err := cursor.Err(); err != nil {
+		return readers.MessagesPage{}, err
+	}
 
-		switch {
-		case m.Value != nil:
-			msg.Value = m.Value
-		case m.StringValue != nil:
-			msg.StringValue = m.StringValue
-		case m.DataValue != nil:
-			msg.DataValue = m.DataValue
-		case m.BoolValue != nil:
-			msg.BoolValue = m.BoolValue
-		}
+	if pm.Limit > 0 && uint64(len(page.Messages)) == pm.Limit {
+		page.NextCursor = readers.EncodeCursor(readers.Cursor{Time: last.Time, ID: last.ID.Hex()})
+	}
 
-		messages = append(messages, msg)
+	if pm.Cursor != "" {
+		return page, nil
 	}
 
 	total, err := col.CountDocuments(context.Background(), filter)
 	if err != nil {
 		return readers.MessagesPage{}, err
 	}
-	if total < 0 {
-		return readers.MessagesPage{}, nil
+	if total > 0 {
+		page.Total = uint64(total)
 	}
 
-	return readers.MessagesPage{
-		Total:    uint64(total),
-		Offset:   offset,
-		Limit:    limit,
-		Messages: messages,
-	}, nil
+	return page, nil
+}
+
+func (repo mongoRepository) PublisherChannels(publisher string) ([]string, error) {
+	col := repo.db.Collection(collection)
+	vals, err := col.Distinct(context.Background(), "channel", bson.D{{Key: "publisher", Value: publisher}})
+	if err != nil {
+		return nil, err
+	}
+
+	chanIDs := []string{}
+	for _, val := range vals {
+		if chanID, ok := val.(string); ok {
+			chanIDs = append(chanIDs, chanID)
+		}
+	}
+	sort.Strings(chanIDs)
+
+	return chanIDs, nil
+}
+
+func (repo mongoRepository) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) error {
+	col := repo.db.Collection(collection)
+	sortMap := map[string]interface{}{
+		"time": -1,
+	}
+	if em.Order == readers.AscOrder {
+		sortMap["time"] = 1
+	}
+
+	filter := fmtCondition([]string{chanID}, em.From, em.To, em.Query)
+	opts := options.Find().SetSort(sortMap).SetBatchSize(exportBatchSize)
+	cursor, err := col.Find(context.Background(), filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var m message
+		if err := cursor.Decode(&m); err != nil {
+			return err
+		}
+
+		if err := fn(toMessage(m)); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+func toMessage(m message) senml.Message {
+	msg := senml.Message{
+		Channel:    m.Channel,
+		Subtopic:   m.Subtopic,
+		Publisher:  m.Publisher,
+		Protocol:   m.Protocol,
+		Name:       m.Name,
+		Unit:       m.Unit,
+		Time:       m.Time,
+		UpdateTime: m.UpdateTime,
+		Sum:        m.Sum,
+	}
+
+	switch {
+	case m.Value != nil:
+		msg.Value = m.Value
+	case m.StringValue != nil:
+		msg.StringValue = m.StringValue
+	case m.DataValue != nil:
+		msg.DataValue = m.DataValue
+	case m.BoolValue != nil:
+		msg.BoolValue = m.BoolValue
+	}
+
+	return msg
+}
+
+// bucket is the result of the aggregation pipeline.
+type bucket struct {
+	ID struct {
+		Name  string  `bson:"name"`
+		Start float64 `bson:"start"`
+	} `bson:"_id"`
+	Count float64 `bson:"count"`
+	Sum   float64 `bson:"sum"`
+	Min   float64 `bson:"min"`
+	Max   float64 `bson:"max"`
+	Last  float64 `bson:"last"`
+}
+
+// Aggregate reads the values from the longest rollup up to the end of its
+// last bucket, and from the raw messages after it, which may since be
+// deleted from before.
+func (repo mongoRepository) Aggregate(chanID string, am readers.AggregationMetadata) ([]readers.Series, error) {
+	agg := readers.NewAggregator(am.Interval, am.Aggregation)
+
+	raw := am
+	for _, r := range readers.RollupsOf(am) {
+		col := repo.db.Collection(fmt.Sprintf("%s_%s", collection, r.Name))
+		end, err := rollupEnd(col, chanID, r)
+		if err != nil {
+			return nil, err
+		}
+		if end == 0 {
+			continue
+		}
+
+		rm, rest, ok := r.Split(am, end)
+		if ok {
+			group := bson.M{
+				"count": bson.M{"$sum": "$count"},
+				"sum":   bson.M{"$sum": "$sum"},
+				"min":   bson.M{"$min": "$min"},
+				"max":   bson.M{"$max": "$max"},
+				"last":  bson.M{"$last": "$last"},
+			}
+			filter := fmtCondition([]string{chanID}, rm.From, rm.To, rm.Query)
+			if err := aggregate(agg, col, *filter, group, rm.Interval); err != nil {
+				return nil, err
+			}
+		}
+		raw = rest
+		break
+	}
+
+	if !raw.Empty() {
+		group := bson.M{
+			"count": bson.M{"$sum": 1},
+			"sum":   bson.M{"$sum": "$value"},
+			"min":   bson.M{"$min": "$value"},
+			"max":   bson.M{"$max": "$value"},
+			"last":  bson.M{"$last": "$value"},
+		}
+		filter := fmtCondition([]string{chanID}, raw.From, raw.To, raw.Query)
+		*filter = append(*filter, bson.E{Key: "value", Value: bson.M{"$exists": true}})
+		if err := aggregate(agg, repo.db.Collection(collection), *filter, group, raw.Interval); err != nil {
+			return nil, err
+		}
+	}
+
+	return agg.Series(), nil
 }
 
-func fmtCondition(chanID string, query map[string]string) *bson.D {
+// aggregate merges the buckets of the documents matching the filter into the
+// aggregator.
+func aggregate(agg *readers.Aggregator, col *mongo.Collection, filter bson.D, group bson.M, interval float64) error {
+	group["_id"] = bson.M{
+		"name":  bson.M{"$ifNull": bson.A{"$name", ""}},
+		"start": bson.M{"$subtract": bson.A{"$time", bson.M{"$mod": bson.A{"$time", interval}}}},
+	}
+	pipeline := []bson.M{
+		{"$match": filter},
+		// Sorted by time so that the last value of a bucket is the newest
+		{"$sort": bson.M{"time": 1}},
+		{"$group": group},
+		{"$sort": bson.D{{Key: "_id.name", Value: 1}, {Key: "_id.start", Value: 1}}},
+	}
+
+	cursor, err := col.Aggregate(context.Background(), pipeline)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.Background())
+
+	for cursor.Next(context.Background()) {
+		var b bucket
+		if err := cursor.Decode(&b); err != nil {
+			return err
+		}
+		agg.Merge(b.ID.Name, readers.Bucket{
+			Time:  b.ID.Start,
+			Count: b.Count,
+			Sum:   b.Sum,
+			Min:   b.Min,
+			Max:   b.Max,
+			Last:  b.Last,
+		})
+	}
+
+	return cursor.Err()
+}
+
+// rollupEnd returns the end of the last bucket of the rollup of the channel,
+// or 0 if the channel isn't rolled up.
+func rollupEnd(col *mongo.Collection, chanID string, r readers.Rollup) (float64, error) {
+	var last struct {
+		Time float64 `bson:"time"`
+	}
+	err := col.FindOne(context.Background(), bson.M{"channel": chanID}, options.FindOne().SetSort(bson.M{"time": -1})).Decode(&last)
+	switch err {
+	case nil:
+		return last.Time + r.Interval, nil
+	case mongo.ErrNoDocuments:
+		return 0, nil
+	default:
+		return 0, err
+	}
+}
+
+func fmtCondition(chanIDs []string, from, to float64, query map[string]string) *bson.D {
 	filter := bson.D{
 		bson.E{
 			Key:   "channel",
-			Value: chanID,
+			Value: bson.M{"$in": chanIDs},
 		},
 	}
 	for name, value := range query {
@@ -125,6 +353,16 @@ func fmtCondition(chanID string, query map[string]string) *bson.D {
 			filter = append(filter, bson.E{Key: name, Value: value})
 		}
 	}
+	timeRange := bson.M{}
+	if from > 0 {
+		timeRange["$gte"] = from
+	}
+	if to > 0 {
+		timeRange["$lt"] = to
+	}
+	if len(timeRange) > 0 {
+		filter = append(filter, bson.E{Key: "time", Value: timeRange})
+	}
 
 	return &filter
 }
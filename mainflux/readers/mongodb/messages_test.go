@@ -12,6 +12,7 @@ import (
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	mreaders "github.com/cloustone/pandas/mainflux/readers/mongodb"
+	"github.com/cloustone/pandas/mainflux/readers/readerstest"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	mwriters "github.com/cloustone/pandas/mainflux/writers/mongodb"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,7 @@ const (
 	testDB      = "test"
 	collection  = "mainflux"
 	chanID      = "1"
+	confChanID  = "2"
 	subtopic    = "subtopic"
 	msgsNum     = 42
 	valueFields = 5
@@ -41,6 +43,13 @@ var (
 	}
 	testLog, _ = log.New(os.Stdout, log.Info.String())
 )
+
+// The channels of the publisher of the queries across the channels.
+var (
+	channelsPubID   = "2"
+	channelsChanIDs = []string{"3", "4"}
+)
+
 var (
 	v       float64 = 5
 	stringV         = "value"
@@ -155,9 +164,43 @@ func TestReadAll(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		result, err := reader.ReadAll(tc.chanID, tc.offset, tc.limit, tc.query)
+		pm := readers.PageMetadata{
+			Offset: tc.offset,
+			Limit:  tc.limit,
+			Query:  tc.query,
+		}
+		result, err := reader.ReadAll(tc.chanID, pm)
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
 		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Messages, result.Messages))
 		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
 	}
 }
+
+func TestReadAllConformance(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+	db := client.Database(testDB)
+
+	messages := readerstest.Messages(confChanID, "1")
+	err = mwriters.New(db).Save(messages...)
+	require.Nil(t, err, fmt.Sprintf("Save operation expected to succeed: %s.\n", err))
+
+	readerstest.Run(t, mreaders.New(db), confChanID, messages)
+}
+
+func TestReadChannelsConformance(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+	db := client.Database(testDB)
+	writer := mwriters.New(db)
+
+	messages := []senml.Message{}
+	for _, chanID := range channelsChanIDs {
+		msgs := readerstest.Messages(chanID, channelsPubID)
+		err = writer.Save(msgs...)
+		require.Nil(t, err, fmt.Sprintf("Save operation expected to succeed: %s.\n", err))
+		messages = append(messages, msgs...)
+	}
+
+	readerstest.RunChannels(t, mreaders.New(db), channelsPubID, channelsChanIDs, messages)
+}
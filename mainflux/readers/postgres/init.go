@@ -70,6 +70,52 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE messages",
 				},
 			},
+			{
+				Id: "messages_2",
+				Up: []string{
+					`CREATE INDEX IF NOT EXISTS messages_channel_time ON messages (channel, time)`,
+					`CREATE TABLE IF NOT EXISTS messages_hour (
+                        channel    UUID,
+                        subtopic   VARCHAR(254),
+                        publisher  TEXT,
+                        protocol   TEXT,
+                        name       TEXT,
+                        time       FLOAT,
+                        count      FLOAT,
+                        sum        FLOAT,
+                        min        FLOAT,
+                        max        FLOAT,
+                        last       FLOAT,
+                        PRIMARY KEY (channel, time, subtopic, publisher, protocol, name)
+                    )`,
+					`CREATE TABLE IF NOT EXISTS messages_day (
+                        channel    UUID,
+                        subtopic   VARCHAR(254),
+                        publisher  TEXT,
+                        protocol   TEXT,
+                        name       TEXT,
+                        time       FLOAT,
+                        count      FLOAT,
+                        sum        FLOAT,
+                        min        FLOAT,
+                        max        FLOAT,
+                        last       FLOAT,
+                        PRIMARY KEY (channel, time, subtopic, publisher, protocol, name)
+                    )`,
+					`CREATE TABLE IF NOT EXISTS retention_policies (
+                        channel        VARCHAR(254),
+                        retention_days INTEGER,
+                        downsampling   TEXT[],
+                        PRIMARY KEY (channel)
+                    )`,
+				},
+				Down: []string{
+					"DROP TABLE retention_policies",
+					"DROP TABLE messages_day",
+					"DROP TABLE messages_hour",
+					"DROP INDEX messages_channel_time",
+				},
+			},
 		},
 	}
 
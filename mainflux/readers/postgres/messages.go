@@ -4,12 +4,16 @@
 package postgres
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx" // required for DB access
+	"github.com/lib/pq"
 )
 
 const errInvalid = "invalid_text_representation"
@@ -29,59 +33,231 @@ func New(db *sqlx.DB) readers.MessageRepository {
 	}
 }
 
-func (tr postgresRepository) ReadAll(chanID string, offset, limit uint64, query map[string]string) (readers.MessagesPage, error) {
-	q := fmt.Sprintf(`SELECT * FROM messages
-    WHERE %s ORDER BY time DESC
-    LIMIT :limit OFFSET :offset;`, fmtCondition(chanID, query))
+func (tr postgresRepository) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	return tr.ReadChannels([]string{chanID}, pm)
+}
 
-	params := map[string]interface{}{
-		"channel":   chanID,
-		"limit":     limit,
-		"offset":    offset,
-		"subtopic":  query["subtopic"],
-		"publisher": query["publisher"],
-		"name":      query["name"],
-		"protocol":  query["protocol"],
+// ReadChannels orders the messages by the time and the ID, so the messages
+// following the cursor are found by the index rather than skipped.
+func (tr postgresRepository) ReadChannels(chanIDs []string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	if len(chanIDs) == 0 {
+		return readers.MessagesPage{Offset: pm.Offset, Limit: pm.Limit, Messages: []senml.Message{}}, nil
+	}
+
+	order, cmp := "DESC", "<"
+	if pm.Order == readers.AscOrder {
+		order, cmp = "ASC", ">"
+	}
+	condition := fmtCondition(chanIDs, pm.From, pm.To, pm.Query)
+	params := fmtParams(chanIDs, pm.From, pm.To, pm.Query)
+	params["limit"] = pm.Limit
+	params["offset"] = pm.Offset
+
+	page := readers.MessagesPage{
+		Offset:   pm.Offset,
+		Limit:    pm.Limit,
+		Messages: []senml.Message{},
+	}
+
+	pageCond := condition
+	if pm.Cursor != "" {
+		c, err := readers.DecodeCursor(pm.Cursor)
+		if err != nil {
+			return readers.MessagesPage{}, err
+		}
+		if _, err := uuid.FromString(c.ID); err != nil {
+			return readers.MessagesPage{}, readers.ErrInvalidCursor
+		}
+		pageCond = fmt.Sprintf(`%s AND (time, id) %s (:cursor_time, CAST(:cursor_id AS UUID))`, condition, cmp)
+		params["cursor_time"] = c.Time
+		params["cursor_id"] = c.ID
+		params["offset"] = 0
+		page.Offset = 0
 	}
 
+	q := fmt.Sprintf(`SELECT * FROM messages
+    WHERE %s ORDER BY time %s, id %s
+    LIMIT :limit OFFSET :offset;`, pageCond, order, order)
+
 	rows, err := tr.db.NamedQuery(q, params)
 	if err != nil {
 		return readers.MessagesPage{}, err
 	}
 	defer rows.Close()
 
-	page := readers.MessagesPage{
-		Offset:   offset,
-		Limit:    limit,
-		Messages: []senml.Message{},
-	}
+	var last dbMessage
 	for rows.Next() {
-		dbm := dbMessage{Channel: chanID}
+		var dbm dbMessage
 		if err := rows.StructScan(&dbm); err != nil {
 			return readers.MessagesPage{}, err
 		}
 
-		msg := toMessage(dbm)
-		page.Messages = append(page.Messages, msg)
+		page.Messages = append(page.Messages, toMessage(dbm))
+		last = dbm
+	}
+	if err := rows.Err(); err != nil {
+		return readers.MessagesPage{}, err
 	}
 
-	q = `SELECT COUNT(*) FROM messages WHERE channel = $1;`
-	qParams := []interface{}{chanID}
+	if pm.Limit > 0 && uint64(len(page.Messages)) == pm.Limit {
+		page.NextCursor = readers.EncodeCursor(readers.Cursor{Time: last.Time, ID: last.ID})
+	}
 
-	if query["subtopic"] != "" {
-		q = `SELECT COUNT(*) FROM messages WHERE channel = $1 AND subtopic = $2;`
-		qParams = append(qParams, query["subtopic"])
+	if pm.Cursor != "" {
+		return page, nil
 	}
 
-	if err := tr.db.QueryRow(q, qParams...).Scan(&page.Total); err != nil {
+	q, args, err := tr.db.BindNamed(fmt.Sprintf(`SELECT COUNT(*) FROM messages WHERE %s;`, condition), params)
+	if err != nil {
+		return readers.MessagesPage{}, err
+	}
+	if err := tr.db.QueryRow(q, args...).Scan(&page.Total); err != nil {
 		return readers.MessagesPage{}, err
 	}
 
 	return page, nil
 }
 
-func fmtCondition(chanID string, query map[string]string) string {
-	condition := `channel = :channel`
+func (tr postgresRepository) PublisherChannels(publisher string) ([]string, error) {
+	q := `SELECT DISTINCT channel FROM messages WHERE publisher = $1 ORDER BY channel;`
+
+	chanIDs := []string{}
+	if err := tr.db.Select(&chanIDs, q, publisher); err != nil {
+		// The publisher isn't a valid UUID, so there are no messages of it.
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	return chanIDs, nil
+}
+
+func (tr postgresRepository) Export(chanID string, em readers.ExportMetadata, fn func(senml.Message) error) error {
+	order := "DESC"
+	if em.Order == readers.AscOrder {
+		order = "ASC"
+	}
+	chanIDs := []string{chanID}
+	q := fmt.Sprintf(`SELECT * FROM messages WHERE %s ORDER BY time %s;`,
+		fmtCondition(chanIDs, em.From, em.To, em.Query), order)
+
+	// The rows are read from the connection as they are iterated over.
+	rows, err := tr.db.NamedQuery(q, fmtParams(chanIDs, em.From, em.To, em.Query))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dbm dbMessage
+		if err := rows.StructScan(&dbm); err != nil {
+			return err
+		}
+
+		if err := fn(toMessage(dbm)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Aggregate reads the values from the longest rollup up to the end of its
+// last bucket, and from the raw messages after it, which may since be
+// deleted from before.
+func (tr postgresRepository) Aggregate(chanID string, am readers.AggregationMetadata) ([]readers.Series, error) {
+	agg := readers.NewAggregator(am.Interval, am.Aggregation)
+
+	raw := am
+	for _, r := range readers.RollupsOf(am) {
+		end, err := tr.rollupEnd(chanID, r)
+		if err != nil {
+			return nil, err
+		}
+		if end == 0 {
+			continue
+		}
+
+		rm, rest, ok := r.Split(am, end)
+		if ok {
+			q := `SELECT name, FLOOR(time / :interval) * :interval AS bucket,
+        SUM(count), SUM(sum), MIN(min), MAX(max), (ARRAY_AGG(last ORDER BY time DESC))[1]`
+			table := fmt.Sprintf("messages_%s", r.Name)
+			if err := tr.aggregate(agg, q, table, chanID, rm); err != nil {
+				return nil, err
+			}
+		}
+		raw = rest
+		break
+	}
+
+	if !raw.Empty() {
+		q := `SELECT COALESCE(name, '') AS name, FLOOR(time / :interval) * :interval AS bucket,
+        COUNT(value), SUM(value), MIN(value), MAX(value), (ARRAY_AGG(value ORDER BY time DESC))[1]`
+		if err := tr.aggregate(agg, q, "messages", chanID, raw); err != nil {
+			return nil, err
+		}
+	}
+
+	return agg.Series(), nil
+}
+
+// aggregate merges the buckets selected from the table into the aggregator.
+func (tr postgresRepository) aggregate(agg *readers.Aggregator, sel, table, chanID string, am readers.AggregationMetadata) error {
+	chanIDs := []string{chanID}
+	cond := fmtCondition(chanIDs, am.From, am.To, am.Query)
+	if table == "messages" {
+		cond = fmt.Sprintf("%s AND value IS NOT NULL", cond)
+	}
+	q := fmt.Sprintf(`%s FROM %s WHERE %s GROUP BY 1, 2 ORDER BY 1, 2;`, sel, table, cond)
+
+	params := fmtParams(chanIDs, am.From, am.To, am.Query)
+	params["interval"] = am.Interval
+
+	rows, err := tr.db.NamedQuery(q, params)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var b readers.Bucket
+		if err := rows.Scan(&name, &b.Time, &b.Count, &b.Sum, &b.Min, &b.Max, &b.Last); err != nil {
+			return err
+		}
+		agg.Merge(name, b)
+	}
+
+	return rows.Err()
+}
+
+// rollupEnd returns the end of the last bucket of the rollup of the channel,
+// or 0 if the channel isn't rolled up.
+func (tr postgresRepository) rollupEnd(chanID string, r readers.Rollup) (float64, error) {
+	q := fmt.Sprintf(`SELECT MAX(time) FROM messages_%s WHERE channel = $1`, r.Name)
+
+	var last sql.NullFloat64
+	if err := tr.db.QueryRow(q, chanID).Scan(&last); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errInvalid {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if !last.Valid {
+		return 0, nil
+	}
+
+	return last.Float64 + r.Interval, nil
+}
+
+func fmtCondition(chanIDs []string, from, to float64, query map[string]string) string {
+	names := make([]string, len(chanIDs))
+	for i := range chanIDs {
+		names[i] = fmt.Sprintf(":channel_%d", i)
+	}
+	condition := fmt.Sprintf(`channel IN (%s)`, strings.Join(names, ", "))
 	for name := range query {
 		switch name {
 		case
@@ -92,9 +268,30 @@ func fmtCondition(chanID string, query map[string]string) string {
 			condition = fmt.Sprintf(`%s AND %s = :%s`, condition, name, name)
 		}
 	}
+	if from > 0 {
+		condition = fmt.Sprintf(`%s AND time >= :from`, condition)
+	}
+	if to > 0 {
+		condition = fmt.Sprintf(`%s AND time < :to`, condition)
+	}
 	return condition
 }
 
+func fmtParams(chanIDs []string, from, to float64, query map[string]string) map[string]interface{} {
+	params := map[string]interface{}{
+		"subtopic":  query["subtopic"],
+		"publisher": query["publisher"],
+		"name":      query["name"],
+		"protocol":  query["protocol"],
+		"from":      from,
+		"to":        to,
+	}
+	for i, chanID := range chanIDs {
+		params[fmt.Sprintf("channel_%d", i)] = chanID
+	}
+	return params
+}
+
 type dbMessage struct {
 	ID          string   `db:"id"`
 	Channel     string   `db:"channel"`
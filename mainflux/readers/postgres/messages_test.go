@@ -10,6 +10,7 @@ import (
 
 	"github.com/cloustone/pandas/mainflux/readers"
 	preader "github.com/cloustone/pandas/mainflux/readers/postgres"
+	"github.com/cloustone/pandas/mainflux/readers/readerstest"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	pwriter "github.com/cloustone/pandas/mainflux/writers/postgres"
 	"github.com/gofrs/uuid"
@@ -162,9 +163,49 @@ func TestMessageReadAll(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		result, err := reader.ReadAll(tc.chanID, tc.offset, tc.limit, tc.query)
+		pm := readers.PageMetadata{
+			Offset: tc.offset,
+			Limit:  tc.limit,
+			Query:  tc.query,
+		}
+		result, err := reader.ReadAll(tc.chanID, pm)
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
 		assert.ElementsMatch(t, tc.page.Messages, result.Messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Messages, result.Messages))
 		assert.Equal(t, tc.page.Total, result.Total, fmt.Sprintf("%s: expected %v got %v", desc, tc.page.Total, result.Total))
 	}
 }
+
+func TestMessageReadAllConformance(t *testing.T) {
+	chanID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	messages := readerstest.Messages(chanID.String(), pubID.String())
+	err = pwriter.New(db).Save(messages...)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	readerstest.Run(t, preader.New(db), chanID.String(), messages)
+}
+
+func TestMessageReadChannelsConformance(t *testing.T) {
+	writer := pwriter.New(db)
+
+	pubID, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	chanIDs := []string{}
+	messages := []senml.Message{}
+	for i := 0; i < 2; i++ {
+		chanID, err := uuid.NewV4()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		chanIDs = append(chanIDs, chanID.String())
+
+		msgs := readerstest.Messages(chanID.String(), pubID.String())
+		err = writer.Save(msgs...)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+		messages = append(messages, msgs...)
+	}
+
+	readerstest.RunChannels(t, preader.New(db), pubID.String(), chanIDs, messages)
+}
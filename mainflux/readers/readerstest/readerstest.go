@@ -0,0 +1,448 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package readerstest contains the conformance tests of the message
+// repositories, which are run against each of the readers.
+package readerstest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/readers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// Start is the time of the first message in seconds, it's aligned to
+	// the aggregation interval.
+	Start = 1500000000
+
+	msgsNum  = 60
+	interval = 10
+	delta    = 1e-6
+)
+
+var names = []string{"temperature", "humidity"}
+
+// Messages returns the messages of the channel saved by the writer before
+// Run is called. A message is sent each second from Start, the names
+// alternate and every fifth message has a string value, which must be left
+// out of the aggregation.
+func Messages(chanID, pubID string) []senml.Message {
+	msgs := []senml.Message{}
+	for i := 0; i < msgsNum; i++ {
+		msg := senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  "mqtt",
+			Name:      names[i%len(names)],
+			Time:      float64(Start + i),
+		}
+		if i%5 == 0 {
+			s := fmt.Sprintf("%d", i)
+			msg.StringValue = &s
+		} else {
+			v := float64(i)
+			msg.Value = &v
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// Run runs the conformance tests of the repository against the saved
+// messages of the channel.
+func Run(t *testing.T, repo readers.MessageRepository, chanID string, msgs []senml.Message) {
+	t.Run("time range", func(t *testing.T) { testTimeRange(t, repo, chanID, msgs) })
+	t.Run("order", func(t *testing.T) { testOrder(t, repo, chanID, msgs) })
+	t.Run("cursor", func(t *testing.T) { testCursor(t, repo, chanID, msgs) })
+	t.Run("aggregate", func(t *testing.T) { testAggregate(t, repo, chanID, msgs) })
+	t.Run("export", func(t *testing.T) { testExport(t, repo, chanID, msgs) })
+}
+
+// RunChannels runs the conformance tests of the queries across the channels
+// against the saved messages of the channels, all of which are sent by the
+// publisher.
+func RunChannels(t *testing.T, repo readers.MessageRepository, pubID string, chanIDs []string, msgs []senml.Message) {
+	t.Run("publisher channels", func(t *testing.T) {
+		channels, err := repo.PublisherChannels(pubID)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.ElementsMatch(t, chanIDs, channels, fmt.Sprintf("expected %v got %v", chanIDs, channels))
+	})
+
+	t.Run("read channels", func(t *testing.T) {
+		pm := readers.PageMetadata{Limit: uint64(len(msgs))}
+		page, err := repo.ReadChannels(chanIDs, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		assert.Equal(t, uint64(len(msgs)), page.Total, fmt.Sprintf("expected total %d got %d", len(msgs), page.Total))
+		assert.ElementsMatch(t, msgs, page.Messages, fmt.Sprintf("expected %v got %v", msgs, page.Messages))
+	})
+
+	t.Run("read channels by cursor", func(t *testing.T) {
+		for _, order := range []string{readers.AscOrder, readers.DescOrder} {
+			// Since the channels share the times of the messages, the
+			// cursors have to break the ties.
+			read := readPages(t, repo, chanIDs, readers.PageMetadata{Limit: 7, Order: order})
+			assert.ElementsMatch(t, msgs, read, fmt.Sprintf("%s order: expected %v got %v", order, msgs, read))
+			assert.True(t, ordered(read, order), fmt.Sprintf("%s order: messages out of order %v", order, read))
+		}
+	})
+}
+
+// readPages reads the pages following the cursors from the first page.
+func readPages(t *testing.T, repo readers.MessageRepository, chanIDs []string, pm readers.PageMetadata) []senml.Message {
+	read := []senml.Message{}
+	for pages := 0; ; pages++ {
+		require.True(t, pages <= msgsNum*len(chanIDs), "expected the cursors to end")
+
+		page, err := repo.ReadChannels(chanIDs, pm)
+		require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+		read = append(read, page.Messages...)
+
+		if page.NextCursor == "" {
+			return read
+		}
+		pm.Cursor = page.NextCursor
+	}
+}
+
+func ordered(msgs []senml.Message, order string) bool {
+	return sort.SliceIsSorted(msgs, func(i, j int) bool {
+		if order == readers.AscOrder {
+			return msgs[i].Time < msgs[j].Time
+		}
+		return msgs[i].Time > msgs[j].Time
+	})
+}
+
+func testTimeRange(t *testing.T, repo readers.MessageRepository, chanID string, msgs []senml.Message) {
+	cases := map[string]struct {
+		from float64
+		to   float64
+	}{
+		"read messages from time": {
+			from: Start + 50,
+		},
+		"read messages to time": {
+			to: Start + 10,
+		},
+		"read messages between times": {
+			from: Start + 10,
+			to:   Start + 20,
+		},
+		"read messages out of range": {
+			from: Start + msgsNum,
+		},
+	}
+
+	for desc, tc := range cases {
+		expected := []senml.Message{}
+		for _, msg := range msgs {
+			if msg.Time >= tc.from && (tc.to == 0 || msg.Time < tc.to) {
+				expected = append(expected, msg)
+			}
+		}
+
+		pm := readers.PageMetadata{
+			Limit: msgsNum,
+			From:  tc.from,
+			To:    tc.to,
+		}
+		page, err := repo.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, uint64(len(expected)), page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, len(expected), page.Total))
+		assert.ElementsMatch(t, expected, page.Messages, fmt.Sprintf("%s: expected %v got %v", desc, expected, page.Messages))
+	}
+}
+
+func testOrder(t *testing.T, repo readers.MessageRepository, chanID string, msgs []senml.Message) {
+	asc := append([]senml.Message{}, msgs...)
+	sort.Slice(asc, func(i, j int) bool { return asc[i].Time < asc[j].Time })
+	desc := make([]senml.Message, len(asc))
+	for i, msg := range asc {
+		desc[len(asc)-1-i] = msg
+	}
+
+	cases := map[string]struct {
+		order    string
+		offset   uint64
+		limit    uint64
+		messages []senml.Message
+	}{
+		"read messages in default order": {
+			limit:    msgsNum,
+			messages: desc,
+		},
+		"read messages in descending order": {
+			order:    readers.DescOrder,
+			limit:    msgsNum,
+			messages: desc,
+		},
+		"read messages in ascending order": {
+			order:    readers.AscOrder,
+			limit:    msgsNum,
+			messages: asc,
+		},
+		"read page in ascending order": {
+			order:    readers.AscOrder,
+			offset:   10,
+			limit:    5,
+			messages: asc[10:15],
+		},
+		"read page in descending order": {
+			order:    readers.DescOrder,
+			offset:   10,
+			limit:    5,
+			messages: desc[10:15],
+		},
+	}
+
+	for desc, tc := range cases {
+		pm := readers.PageMetadata{
+			Offset: tc.offset,
+			Limit:  tc.limit,
+			Order:  tc.order,
+		}
+		page, err := repo.ReadAll(chanID, pm)
+		require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, uint64(len(msgs)), page.Total, fmt.Sprintf("%s: expected total %d got %d", desc, len(msgs), page.Total))
+		assert.Equal(t, tc.messages, page.Messages, fmt.Sprintf("%s: expected %v got %v", desc, tc.messages, page.Messages))
+	}
+}
+
+func testExport(t *testing.T, repo readers.MessageRepository, chanID string, msgs []senml.Message) {
+	cases := map[string]readers.ExportMetadata{
+		"export messages in default order": {},
+		"export messages in ascending order": {
+			Order: readers.AscOrder,
+		},
+		"export messages between times": {
+			From:  Start + 10,
+			To:    Start + 20,
+			Order: readers.AscOrder,
+		},
+		"export messages by name": {
+			Order: readers.DescOrder,
+			Query: map[string]string{"name": names[1]},
+		},
+	}
+
+	for desc, em := range cases {
+		expected := []senml.Message{}
+		for _, msg := range msgs {
+			if msg.Time < em.From || (em.To > 0 && msg.Time >= em.To) {
+				continue
+			}
+			if n, ok := em.Query["name"]; ok && n != msg.Name {
+				continue
+			}
+			expected = append(expected, msg)
+		}
+		sort.Slice(expected, func(i, j int) bool {
+			if em.Order == readers.AscOrder {
+				return expected[i].Time < expected[j].Time
+			}
+			return expected[i].Time > expected[j].Time
+		})
+
+		exported := []senml.Message{}
+		err := repo.Export(chanID, em, func(msg senml.Message) error {
+			exported = append(exported, msg)
+			return nil
+		})
+		require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assert.Equal(t, expected, exported, fmt.Sprintf("%s: expected %v got %v", desc, expected, exported))
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	err := repo.Export(chanID, readers.ExportMetadata{}, func(senml.Message) error {
+		calls++
+		return errStop
+	})
+	assert.Equal(t, errStop, err, fmt.Sprintf("export stopped by callback: expected %s got %s", errStop, err))
+	assert.Equal(t, 1, calls, fmt.Sprintf("export stopped by callback: expected 1 call got %d", calls))
+}
+
+func testCursor(t *testing.T, repo readers.MessageRepository, chanID string, msgs []senml.Message) {
+	asc := append([]senml.Message{}, msgs...)
+	sort.Slice(asc, func(i, j int) bool { return asc[i].Time < asc[j].Time })
+	desc := make([]senml.Message, len(asc))
+	for i, msg := range asc {
+		desc[len(asc)-1-i] = msg
+	}
+	between := []senml.Message{}
+	for _, msg := range asc {
+		if msg.Time >= Start+10 && msg.Time < Start+30 {
+			between = append(between, msg)
+		}
+	}
+
+	cases := map[string]struct {
+		pm       readers.PageMetadata
+		messages []senml.Message
+	}{
+		"read pages in default order": {
+			pm:       readers.PageMetadata{Limit: 7},
+			messages: desc,
+		},
+		"read pages in ascending order": {
+			pm:       readers.PageMetadata{Limit: 7, Order: readers.AscOrder},
+			messages: asc,
+		},
+		"read pages following offset": {
+			pm:       readers.PageMetadata{Offset: 10, Limit: 7, Order: readers.AscOrder},
+			messages: asc[10:],
+		},
+		"read pages between times": {
+			pm:       readers.PageMetadata{Limit: 7, From: Start + 10, To: Start + 30, Order: readers.AscOrder},
+			messages: between,
+		},
+		"read pages of full last page": {
+			pm:       readers.PageMetadata{Limit: 5, From: Start + 10, To: Start + 30, Order: readers.AscOrder},
+			messages: between,
+		},
+	}
+
+	for desc, tc := range cases {
+		read := readPages(t, repo, []string{chanID}, tc.pm)
+		assert.Equal(t, tc.messages, read, fmt.Sprintf("%s: expected %v got %v", desc, tc.messages, read))
+	}
+
+	pm := readers.PageMetadata{Limit: 7, Cursor: "invalid"}
+	_, err := repo.ReadAll(chanID, pm)
+	assert.Equal(t, readers.ErrInvalidCursor, err, fmt.Sprintf("read page with invalid cursor: expected %s got %s", readers.ErrInvalidCursor, err))
+
+	page, err := repo.ReadAll(chanID, readers.PageMetadata{Limit: 7})
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	pm = readers.PageMetadata{Limit: 7, Cursor: page.NextCursor}
+	page, err = repo.ReadAll(chanID, pm)
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("read page by cursor: expected messages not to be counted got %d", page.Total))
+}
+
+func testAggregate(t *testing.T, repo readers.MessageRepository, chanID string, msgs []senml.Message) {
+	aggregations := []string{
+		readers.AggregationAvg,
+		readers.AggregationMin,
+		readers.AggregationMax,
+		readers.AggregationSum,
+		readers.AggregationCount,
+		readers.AggregationLast,
+	}
+
+	for _, agg := range aggregations {
+		cases := map[string]readers.AggregationMetadata{
+			"aggregate all messages": {
+				Interval:    interval,
+				Aggregation: agg,
+			},
+			"aggregate messages between times": {
+				From:        Start + 15,
+				To:          Start + 35,
+				Interval:    interval,
+				Aggregation: agg,
+			},
+			"aggregate messages by name": {
+				Interval:    interval,
+				Aggregation: agg,
+				Query:       map[string]string{"name": names[0]},
+			},
+			"aggregate messages of non-existent name": {
+				Interval:    interval,
+				Aggregation: agg,
+				Query:       map[string]string{"name": "not-present"},
+			},
+		}
+
+		for desc, am := range cases {
+			desc = fmt.Sprintf("%s with %s", desc, agg)
+			expected := expectedSeries(msgs, am)
+
+			series, err := repo.Aggregate(chanID, am)
+			require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+			require.Len(t, series, len(expected), fmt.Sprintf("%s: expected %v got %v", desc, expected, series))
+			for i, s := range series {
+				assert.Equal(t, expected[i].Name, s.Name, fmt.Sprintf("%s: expected name %s got %s", desc, expected[i].Name, s.Name))
+				require.Len(t, s.Points, len(expected[i].Points), fmt.Sprintf("%s: expected %v got %v", desc, expected[i].Points, s.Points))
+				for j, p := range s.Points {
+					assert.InDelta(t, expected[i].Points[j].Time, p.Time, delta, fmt.Sprintf("%s: expected %v got %v", desc, expected[i].Points[j], p))
+					assert.InDelta(t, expected[i].Points[j].Value, p.Value, delta, fmt.Sprintf("%s: expected %v got %v", desc, expected[i].Points[j], p))
+				}
+			}
+		}
+	}
+}
+
+// expectedSeries aggregates the messages the long way, independently of
+// readers.Aggregator.
+func expectedSeries(msgs []senml.Message, am readers.AggregationMetadata) []readers.Series {
+	ret := []readers.Series{}
+	for _, name := range names {
+		if n, ok := am.Query["name"]; ok && n != name {
+			continue
+		}
+
+		points := []readers.Point{}
+		for start := float64(Start); start < Start+msgsNum; start += am.Interval {
+			values := []float64{}
+			for _, msg := range msgs {
+				if msg.Name != name || msg.Value == nil {
+					continue
+				}
+				if msg.Time < start || msg.Time >= start+am.Interval {
+					continue
+				}
+				if msg.Time < am.From || (am.To > 0 && msg.Time >= am.To) {
+					continue
+				}
+				values = append(values, *msg.Value)
+			}
+			if len(values) > 0 {
+				points = append(points, readers.Point{Time: start, Value: aggregate(values, am.Aggregation)})
+			}
+		}
+		if len(points) > 0 {
+			ret = append(ret, readers.Series{Name: name, Points: points})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+func aggregate(values []float64, agg string) float64 {
+	ret := values[0]
+	switch agg {
+	case readers.AggregationMin:
+		for _, v := range values {
+			if v < ret {
+				ret = v
+			}
+		}
+	case readers.AggregationMax:
+		for _, v := range values {
+			if v > ret {
+				ret = v
+			}
+		}
+	case readers.AggregationSum, readers.AggregationAvg:
+		ret = 0
+		for _, v := range values {
+			ret += v
+		}
+		if agg == readers.AggregationAvg {
+			ret /= float64(len(values))
+		}
+	case readers.AggregationCount:
+		ret = float64(len(values))
+	case readers.AggregationLast:
+		ret = values[len(values)-1]
+	}
+	return ret
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readerstest_test
+
+import (
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/readers/mocks"
+	"github.com/cloustone/pandas/mainflux/readers/readerstest"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+)
+
+const (
+	chanID = "1"
+	pubID  = "1"
+)
+
+func TestMessageRepository(t *testing.T) {
+	msgs := readerstest.Messages(chanID, pubID)
+	repo := mocks.NewMessageRepository(map[string][]senml.Message{
+		chanID: msgs,
+	})
+
+	readerstest.Run(t, repo, chanID, msgs)
+}
+
+func TestMessageRepositoryChannels(t *testing.T) {
+	chanIDs := []string{"2", "3"}
+	messages := map[string][]senml.Message{}
+	msgs := []senml.Message{}
+	for _, chanID := range chanIDs {
+		messages[chanID] = readerstest.Messages(chanID, "2")
+		msgs = append(msgs, messages[chanID]...)
+	}
+	messages[chanID] = readerstest.Messages(chanID, pubID)
+	repo := mocks.NewMessageRepository(messages)
+
+	readerstest.RunChannels(t, repo, "2", chanIDs, msgs)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import "math"
+
+// Rollup is a downsampling of the numeric values of the messages, kept by
+// the writers once their raw messages may be deleted. Its buckets are keyed
+// by channel, subtopic, publisher, protocol and name.
+type Rollup struct {
+	// Name is the suffix of the table of the rollup.
+	Name string
+
+	// Interval is the length of the buckets in seconds.
+	Interval float64
+}
+
+// Rollups lists the rollups from the longest interval.
+var Rollups = []Rollup{
+	{Name: "day", Interval: 86400},
+	{Name: "hour", Interval: 3600},
+}
+
+// RollupsOf returns the rollups the aggregation can be read from, i.e. the
+// ones its interval is a multiple of if it filters by the rollup keys only.
+func RollupsOf(am AggregationMetadata) []Rollup {
+	for name := range am.Query {
+		switch name {
+		case "subtopic", "publisher", "protocol", "name":
+		default:
+			return nil
+		}
+	}
+
+	ret := []Rollup{}
+	for _, r := range Rollups {
+		if am.Interval >= r.Interval && math.Mod(am.Interval, r.Interval) == 0 {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+// Split splits the aggregation into the one read from the rollup, whose last
+// bucket ends at the end, and the one read from the raw messages after it.
+// The rollup buckets overlapping the range of the aggregation are read whole,
+// since they can't be split. It returns false if the rollup isn't read.
+func (r Rollup) Split(am AggregationMetadata, end float64) (AggregationMetadata, AggregationMetadata, bool) {
+	if end == 0 || am.From >= end {
+		return AggregationMetadata{}, am, false
+	}
+
+	rm, raw := am, am
+	rm.From = math.Floor(am.From/r.Interval) * r.Interval
+	if am.To == 0 || am.To > end {
+		rm.To = end
+	}
+	raw.From = end
+
+	return rm, raw, true
+}
+
+// Empty returns true if the range of the aggregation is empty.
+func (am AggregationMetadata) Empty() bool {
+	return am.To != 0 && am.From >= am.To
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema contains the validation of the messages published to the
+// channels against the schemas attached to the channels through the things
+// service.
+package schema
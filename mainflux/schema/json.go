@@ -0,0 +1,55 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+)
+
+var errNotJSON = errors.New("payload is not JSON")
+
+var _ Schema = (*jsonSchema)(nil)
+
+type jsonSchema struct {
+	schema *spec.Schema
+}
+
+func newJSONSchema(definition []byte) (Schema, error) {
+	var s spec.Schema
+	if err := json.Unmarshal(definition, &s); err != nil {
+		return nil, errors.Wrap(ErrMalformedSchema, err)
+	}
+	if err := spec.ExpandSchema(&s, nil, nil); err != nil {
+		return nil, errors.Wrap(ErrMalformedSchema, err)
+	}
+
+	return jsonSchema{schema: &s}, nil
+}
+
+// Validate validates the payloads of the JSON content types, e.g. JSON SenML,
+// the messages of the other content types are invalid.
+func (js jsonSchema) Validate(msg broker.Message) error {
+	ct := transformers.MediaType(msg.ContentType)
+	if ct != "" && !strings.HasSuffix(ct, "json") {
+		return errors.Wrap(ErrInvalidMessage, errNotJSON)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(msg.Payload, &data); err != nil {
+		return errors.Wrap(ErrInvalidMessage, err)
+	}
+	if err := validate.AgainstSchema(js.schema, data, strfmt.Default); err != nil {
+		return errors.Wrap(ErrInvalidMessage, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ Validator = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter   metrics.Counter
+	validator Validator
+}
+
+// MetricsMiddleware counts the messages rejected by the validator, labeled
+// by their protocol.
+func MetricsMiddleware(validator Validator, counter metrics.Counter) Validator {
+	return &metricsMiddleware{
+		counter:   counter,
+		validator: validator,
+	}
+}
+
+func (mm *metricsMiddleware) Validate(ctx context.Context, msg broker.Message) error {
+	err := mm.validator.Validate(ctx, msg)
+	if errors.Contains(err, ErrInvalidMessage) {
+		mm.counter.With("protocol", msg.GetProtocol()).Add(1)
+	}
+
+	return err
+}
@@ -0,0 +1,34 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+)
+
+var _ broker.Nats = (*publisher)(nil)
+
+type publisher struct {
+	broker.Nats
+	validator Validator
+}
+
+// NewPublisher returns the broker which validates the messages before
+// publishing them, the invalid messages aren't published.
+func NewPublisher(pub broker.Nats, validator Validator) broker.Nats {
+	return publisher{
+		Nats:      pub,
+		validator: validator,
+	}
+}
+
+func (pub publisher) Publish(ctx context.Context, token string, msg broker.Message) error {
+	if err := pub.validator.Validate(ctx, msg); err != nil {
+		return err
+	}
+
+	return pub.Nats.Publish(ctx, token, msg)
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/pkg/errors"
+)
+
+const (
+	// JSON is the type of the JSON Schema (draft 4) the whole payload is
+	// validated against.
+	JSON = "json"
+
+	// SenML is the type of the field specification the SenML records of the
+	// payload are validated against.
+	SenML = "senml"
+)
+
+var (
+	// ErrInvalidMessage indicates the message doesn't conform to the schema
+	// of its channel.
+	ErrInvalidMessage = errors.New("message doesn't conform to the channel schema")
+
+	// ErrMalformedSchema indicates a malformed schema definition.
+	ErrMalformedSchema = errors.New("malformed schema definition")
+
+	// ErrUnsupportedSchema indicates an unknown schema type.
+	ErrUnsupportedSchema = errors.New("unsupported schema type")
+)
+
+// Schema validates the payloads of the messages published to a channel.
+type Schema interface {
+	// Validate returns ErrInvalidMessage, wrapping the violation, if the
+	// message doesn't conform to the schema.
+	Validate(broker.Message) error
+}
+
+// New compiles the JSON encoded schema definition of the given type.
+func New(typ string, definition []byte) (Schema, error) {
+	switch typ {
+	case JSON:
+		return newJSONSchema(definition)
+	case SenML:
+		return newSenMLSchema(definition)
+	default:
+		return nil, ErrUnsupportedSchema
+	}
+}
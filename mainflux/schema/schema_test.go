@@ -0,0 +1,171 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/schema"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	jsonDef  = `{"type":"object","required":["temp"],"properties":{"temp":{"type":"number","maximum":100}}}`
+	senmlDef = `{"fields":{"temp":{"type":"float","unit":"Cel","min":-40,"max":100,"required":true},"state":{"type":"bool"}},"strict":true}`
+)
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		desc       string
+		typ        string
+		definition string
+		err        error
+	}{
+		{
+			desc:       "create JSON schema",
+			typ:        schema.JSON,
+			definition: jsonDef,
+			err:        nil,
+		},
+		{
+			desc:       "create SenML schema",
+			typ:        schema.SenML,
+			definition: senmlDef,
+			err:        nil,
+		},
+		{
+			desc:       "create schema of unknown type",
+			typ:        "xml",
+			definition: jsonDef,
+			err:        schema.ErrUnsupportedSchema,
+		},
+		{
+			desc:       "create JSON schema with malformed definition",
+			typ:        schema.JSON,
+			definition: `{"type":`,
+			err:        schema.ErrMalformedSchema,
+		},
+		{
+			desc:       "create SenML schema with unknown field type",
+			typ:        schema.SenML,
+			definition: `{"fields":{"temp":{"type":"int"}}}`,
+			err:        schema.ErrMalformedSchema,
+		},
+		{
+			desc:       "create SenML schema with min greater than max",
+			typ:        schema.SenML,
+			definition: `{"fields":{"temp":{"min":10,"max":0}}}`,
+			err:        schema.ErrMalformedSchema,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := schema.New(tc.typ, []byte(tc.definition))
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	s, err := schema.New(schema.JSON, []byte(jsonDef))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		msg  broker.Message
+		err  error
+	}{
+		{
+			desc: "validate conforming message",
+			msg:  broker.Message{ContentType: "application/json", Payload: []byte(`{"temp":21.5}`)},
+			err:  nil,
+		},
+		{
+			desc: "validate conforming message without content type",
+			msg:  broker.Message{Payload: []byte(`{"temp":21.5}`)},
+			err:  nil,
+		},
+		{
+			desc: "validate message with value out of range",
+			msg:  broker.Message{ContentType: "application/json", Payload: []byte(`{"temp":121.5}`)},
+			err:  schema.ErrInvalidMessage,
+		},
+		{
+			desc: "validate message without required property",
+			msg:  broker.Message{ContentType: "application/json", Payload: []byte(`{"hum":40}`)},
+			err:  schema.ErrInvalidMessage,
+		},
+		{
+			desc: "validate malformed message",
+			msg:  broker.Message{ContentType: "application/json", Payload: []byte(`{"temp":`)},
+			err:  schema.ErrInvalidMessage,
+		},
+		{
+			desc: "validate message of non JSON content type",
+			msg:  broker.Message{ContentType: "application/senml+cbor", Payload: []byte(`{"temp":21.5}`)},
+			err:  schema.ErrInvalidMessage,
+		},
+	}
+
+	for _, tc := range cases {
+		err := s.Validate(tc.msg)
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestValidateSenML(t *testing.T) {
+	s, err := schema.New(schema.SenML, []byte(senmlDef))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc    string
+		payload string
+		err     error
+	}{
+		{
+			desc:    "validate conforming message",
+			payload: `[{"bn":"dev:","n":"temp","u":"Cel","v":21.5},{"n":"state","vb":true}]`,
+			err:     nil,
+		},
+		{
+			desc:    "validate message with value out of range",
+			payload: `[{"n":"temp","u":"Cel","v":121.5}]`,
+			err:     schema.ErrInvalidMessage,
+		},
+		{
+			desc:    "validate message with invalid unit",
+			payload: `[{"n":"temp","u":"K","v":294.6}]`,
+			err:     schema.ErrInvalidMessage,
+		},
+		{
+			desc:    "validate message with invalid value type",
+			payload: `[{"n":"temp","u":"Cel","vs":"hot"}]`,
+			err:     schema.ErrInvalidMessage,
+		},
+		{
+			desc:    "validate message without required field",
+			payload: `[{"n":"state","vb":true}]`,
+			err:     schema.ErrInvalidMessage,
+		},
+		{
+			desc:    "validate message with unknown field",
+			payload: `[{"n":"temp","u":"Cel","v":21.5},{"n":"hum","v":40}]`,
+			err:     schema.ErrInvalidMessage,
+		},
+		{
+			desc:    "validate malformed message",
+			payload: `[{"n":"temp"`,
+			err:     schema.ErrInvalidMessage,
+		},
+	}
+
+	for _, tc := range cases {
+		msg := broker.Message{ContentType: "application/senml+json", Payload: []byte(tc.payload)}
+		err := s.Validate(msg)
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
+)
+
+// The kinds of the SenML record values.
+const (
+	FloatValue  = "float"
+	StringValue = "string"
+	BoolValue   = "bool"
+	DataValue   = "data"
+)
+
+// Field specifies the SenML records of a measurement.
+type Field struct {
+	// Type is the kind of the record values, any kind is allowed if it's
+	// empty.
+	Type string `json:"type,omitempty"`
+
+	// Unit is the unit of the records, any unit is allowed if it's empty.
+	Unit string `json:"unit,omitempty"`
+
+	// Min and Max bound the float values of the records.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+
+	// Required fields are present in every published message.
+	Required bool `json:"required,omitempty"`
+}
+
+// Fields is the definition of the SenML schemas, the fields are keyed by the
+// resolved record names.
+type Fields struct {
+	Fields map[string]Field `json:"fields"`
+
+	// Strict rejects the records of the unspecified fields.
+	Strict bool `json:"strict,omitempty"`
+}
+
+var _ Schema = (*senmlSchema)(nil)
+
+type senmlSchema struct {
+	fields   Fields
+	required []string
+}
+
+func newSenMLSchema(definition []byte) (Schema, error) {
+	var fields Fields
+	if err := json.Unmarshal(definition, &fields); err != nil {
+		return nil, errors.Wrap(ErrMalformedSchema, err)
+	}
+
+	required := []string{}
+	for name, f := range fields.Fields {
+		switch f.Type {
+		case "", FloatValue, StringValue, BoolValue, DataValue:
+		default:
+			return nil, errors.Wrap(ErrMalformedSchema, fmt.Errorf("unknown type %s of field %s", f.Type, name))
+		}
+		if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+			return nil, errors.Wrap(ErrMalformedSchema, fmt.Errorf("min greater than max of field %s", name))
+		}
+		if f.Required {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+
+	return senmlSchema{fields: fields, required: required}, nil
+}
+
+func (ss senmlSchema) Validate(msg broker.Message) error {
+	t, err := senml.New().Transform(msg)
+	if err != nil {
+		return errors.Wrap(ErrInvalidMessage, err)
+	}
+
+	present := map[string]bool{}
+	for _, r := range t.([]senml.Message) {
+		present[r.Name] = true
+
+		f, ok := ss.fields.Fields[r.Name]
+		if !ok {
+			if ss.fields.Strict {
+				return errors.Wrap(ErrInvalidMessage, fmt.Errorf("unknown field %s", r.Name))
+			}
+			continue
+		}
+		if err := f.validate(r); err != nil {
+			return errors.Wrap(ErrInvalidMessage, fmt.Errorf("field %s: %s", r.Name, err))
+		}
+	}
+
+	for _, name := range ss.required {
+		if !present[name] {
+			return errors.Wrap(ErrInvalidMessage, fmt.Errorf("missing field %s", name))
+		}
+	}
+
+	return nil
+}
+
+func (f Field) validate(r senml.Message) error {
+	if kind := valueKind(r); f.Type != "" && kind != f.Type {
+		return fmt.Errorf("expected %s value got %s", f.Type, kind)
+	}
+	if f.Unit != "" && r.Unit != f.Unit {
+		return fmt.Errorf("expected unit %s got %s", f.Unit, r.Unit)
+	}
+	if r.Value == nil {
+		return nil
+	}
+	if f.Min != nil && *r.Value < *f.Min {
+		return fmt.Errorf("value %v less than %v", *r.Value, *f.Min)
+	}
+	if f.Max != nil && *r.Value > *f.Max {
+		return fmt.Errorf("value %v greater than %v", *r.Value, *f.Max)
+	}
+
+	return nil
+}
+
+func valueKind(r senml.Message) string {
+	switch {
+	case r.Value != nil:
+		return FloatValue
+	case r.StringValue != nil:
+		return StringValue
+	case r.BoolValue != nil:
+		return BoolValue
+	case r.DataValue != nil:
+		return DataValue
+	default:
+		return "none"
+	}
+}
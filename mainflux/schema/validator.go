@@ -0,0 +1,134 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Validator validates the messages against the schemas of their channels.
+type Validator interface {
+	// Validate returns ErrInvalidMessage if the message doesn't conform to
+	// the schema of its channel. The messages of the channels without a
+	// schema, and the well-formed replies of the things to their own
+	// commands, are valid.
+	Validate(context.Context, broker.Message) error
+}
+
+var _ Validator = (*validator)(nil)
+
+type cached struct {
+	schema  Schema
+	expires time.Time
+}
+
+type validator struct {
+	things  mainflux.ThingsServiceClient
+	ttl     time.Duration
+	mu      sync.RWMutex
+	schemas map[string]cached
+}
+
+// NewValidator returns the validator fetching the channel schemas from the
+// things service, the fetched schemas are cached for the ttl.
+func NewValidator(things mainflux.ThingsServiceClient, ttl time.Duration) Validator {
+	return &validator{
+		things:  things,
+		ttl:     ttl,
+		schemas: make(map[string]cached),
+	}
+}
+
+func (v *validator) Validate(ctx context.Context, msg broker.Message) error {
+	if isCommandReply(msg) {
+		return nil
+	}
+
+	s, err := v.schema(ctx, msg.GetChannel())
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return nil
+	}
+
+	return s.Validate(msg)
+}
+
+func (v *validator) schema(ctx context.Context, chanID string) (Schema, error) {
+	v.mu.RLock()
+	c, ok := v.schemas[chanID]
+	v.mu.RUnlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.schema, nil
+	}
+
+	var s Schema
+	res, err := v.things.ViewSchema(ctx, &mainflux.ChannelID{Value: chanID})
+	switch {
+	case err == nil:
+		if s, err = New(res.GetType(), res.GetDefinition()); err != nil {
+			return nil, err
+		}
+	case status.Code(err) != codes.NotFound:
+		return nil, err
+	}
+
+	v.mu.Lock()
+	v.schemas[chanID] = cached{schema: s, expires: time.Now().Add(v.ttl)}
+	v.mu.Unlock()
+
+	return s, nil
+}
+
+// commandReply is the shape of the replies of the things to their commands,
+// it mirrors the commands service Reply.
+type commandReply struct {
+	ID     string                 `json:"id"`
+	Status string                 `json:"status"`
+	Result map[string]interface{} `json:"result,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+var replyStatuses = map[string]bool{
+	"delivered":    true,
+	"acknowledged": true,
+	"failed":       true,
+}
+
+// isCommandReply returns true if the message is the reply of the thing to
+// its command, i.e. the thing published it on commands.<thing_id>.reply and
+// the payload is a command reply. The replies are validated by the commands
+// service, the other messages are validated against the channel schema.
+func isCommandReply(msg broker.Message) bool {
+	parts := strings.Split(msg.GetSubtopic(), ".")
+	if len(parts) != 3 || parts[0] != broker.SubtopicCommands || parts[2] != "reply" {
+		return false
+	}
+	if parts[1] == "" || parts[1] != msg.GetPublisher() {
+		return false
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(msg.GetPayload()))
+	dec.DisallowUnknownFields()
+	var r commandReply
+	if err := dec.Decode(&r); err != nil {
+		return false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return false
+	}
+	return r.ID != "" && replyStatuses[r.Status]
+}
@@ -0,0 +1,190 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package schema_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/schema"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	chanID    = "1"
+	noneID    = "2"
+	failingID = "3"
+)
+
+var _ mainflux.ThingsServiceClient = (*thingsClient)(nil)
+
+type thingsClient struct {
+	schemas map[string]*mainflux.Schema
+	calls   int
+}
+
+func (tc *thingsClient) CanAccessByKey(context.Context, *mainflux.AccessByKeyReq, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
+	panic("not implemented")
+}
+
+func (tc *thingsClient) ViewSchema(_ context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Schema, error) {
+	tc.calls++
+	if req.GetValue() == failingID {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	s, ok := tc.schemas[req.GetValue()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "entity does not exist")
+	}
+
+	return s, nil
+}
+
+func TestValidatorValidate(t *testing.T) {
+	tc := &thingsClient{
+		schemas: map[string]*mainflux.Schema{
+			chanID: {Type: schema.JSON, Definition: []byte(jsonDef)},
+		},
+	}
+	v := schema.NewValidator(tc, time.Minute)
+
+	cases := []struct {
+		desc      string
+		chanID    string
+		subtopic  string
+		publisher string
+		payload   string
+		err       error
+		calls     int
+	}{
+		{
+			desc:    "validate conforming message",
+			chanID:  chanID,
+			payload: `{"temp":21.5}`,
+			err:     nil,
+			calls:   1,
+		},
+		{
+			desc:    "validate non conforming message with cached schema",
+			chanID:  chanID,
+			payload: `{"temp":121.5}`,
+			err:     schema.ErrInvalidMessage,
+			calls:   1,
+		},
+		{
+			desc:      "validate command reply with cached schema",
+			chanID:    chanID,
+			subtopic:  "commands.thing.reply",
+			publisher: "thing",
+			payload:   `{"id":"1","status":"acknowledged","result":{"temp":121.5}}`,
+			err:       nil,
+			calls:     1,
+		},
+		{
+			desc:      "validate command reply of another thing",
+			chanID:    chanID,
+			subtopic:  "commands.thing.reply",
+			publisher: "other",
+			payload:   `{"id":"1","status":"delivered"}`,
+			err:       schema.ErrInvalidMessage,
+			calls:     1,
+		},
+		{
+			desc:      "validate command reply with unknown status",
+			chanID:    chanID,
+			subtopic:  "commands.thing.reply",
+			publisher: "thing",
+			payload:   `{"id":"1","status":"done"}`,
+			err:       schema.ErrInvalidMessage,
+			calls:     1,
+		},
+		{
+			desc:      "validate command reply with unknown fields",
+			chanID:    chanID,
+			subtopic:  "commands.thing.reply",
+			publisher: "thing",
+			payload:   `{"id":"1","status":"delivered","temp":121.5}`,
+			err:       schema.ErrInvalidMessage,
+			calls:     1,
+		},
+		{
+			desc:      "validate command reply with trailing data",
+			chanID:    chanID,
+			subtopic:  "commands.thing.reply",
+			publisher: "thing",
+			payload:   `{"id":"1","status":"delivered"} {"temp":121.5}`,
+			err:       schema.ErrInvalidMessage,
+			calls:     1,
+		},
+		{
+			desc:      "validate non conforming message on commands subtopic",
+			chanID:    chanID,
+			subtopic:  "commands.thing",
+			publisher: "thing",
+			payload:   `{"temp":121.5}`,
+			err:       schema.ErrInvalidMessage,
+			calls:     1,
+		},
+		{
+			desc:    "validate message of channel without schema",
+			chanID:  noneID,
+			payload: `{"temp":121.5}`,
+			err:     nil,
+			calls:   2,
+		},
+		{
+			desc:    "validate message of channel without schema once again",
+			chanID:  noneID,
+			payload: `{"temp":121.5}`,
+			err:     nil,
+			calls:   2,
+		},
+	}
+
+	for _, c := range cases {
+		msg := broker.Message{Channel: c.chanID, Subtopic: c.subtopic, Publisher: c.publisher, Payload: []byte(c.payload)}
+		err := v.Validate(context.Background(), msg)
+		assert.True(t, errors.Contains(err, c.err) || err == c.err, fmt.Sprintf("%s: expected %s got %s", c.desc, c.err, err))
+		assert.Equal(t, c.calls, tc.calls, fmt.Sprintf("%s: expected %d schema fetches got %d", c.desc, c.calls, tc.calls))
+	}
+
+	err := v.Validate(context.Background(), broker.Message{Channel: failingID})
+	assert.Equal(t, codes.Internal, status.Code(err), fmt.Sprintf("validate message on things failure: expected internal error got %s", err))
+}
+
+func TestValidatorExpiration(t *testing.T) {
+	tc := &thingsClient{}
+	v := schema.NewValidator(tc, time.Millisecond)
+	msg := broker.Message{Channel: chanID, Payload: []byte(`{"temp":121.5}`)}
+
+	err := v.Validate(context.Background(), msg)
+	assert.Nil(t, err, fmt.Sprintf("validate message of channel without schema: unexpected error %s", err))
+
+	tc.schemas = map[string]*mainflux.Schema{
+		chanID: {Type: schema.JSON, Definition: []byte(jsonDef)},
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	err = v.Validate(context.Background(), msg)
+	assert.True(t, errors.Contains(err, schema.ErrInvalidMessage), fmt.Sprintf("validate message after schema expiration: expected %s got %s", schema.ErrInvalidMessage, err))
+	assert.Equal(t, 2, tc.calls, fmt.Sprintf("expected 2 schema fetches got %d", tc.calls))
+}
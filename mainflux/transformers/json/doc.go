@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package json contains JSON transformer, which maps arbitrary JSON payloads
+// to messages by the schemas of their channels.
+package json
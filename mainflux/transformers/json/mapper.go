@@ -0,0 +1,196 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package json
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
+)
+
+// separator separates the keys of the path names of the flattened values.
+const separator = "."
+
+var (
+	errPath = errors.New("invalid schema path")
+	errTime = errors.New("invalid payload time")
+)
+
+// path is a compiled JSONPath.
+type path func(context.Context, interface{}) (interface{}, error)
+
+type field struct {
+	path path
+	name string
+	unit string
+}
+
+type schema struct {
+	time   path
+	fields []field
+}
+
+// Mapper maps the decoded payloads, made of maps, slices and scalars, to
+// messages by the schemas of their channels.
+type Mapper struct {
+	schemas  map[string]schema
+	channels map[string]string
+}
+
+// NewMapper compiles the paths of the schemas into a mapper.
+func NewMapper(schemas transformers.Schemas) (*Mapper, error) {
+	m := &Mapper{
+		schemas:  make(map[string]schema),
+		channels: schemas.Channels,
+	}
+
+	for name, s := range schemas.Schemas {
+		var cs schema
+		if s.Time != "" {
+			p, err := compile(name, s.Time)
+			if err != nil {
+				return nil, err
+			}
+			cs.time = p
+		}
+		for _, f := range s.Fields {
+			p, err := compile(name, f.Path)
+			if err != nil {
+				return nil, err
+			}
+			n := f.Name
+			if n == "" {
+				n = strings.TrimPrefix(strings.TrimPrefix(f.Path, "$"), separator)
+			}
+			cs.fields = append(cs.fields, field{path: p, name: n, unit: f.Unit})
+		}
+		m.schemas[name] = cs
+	}
+
+	return m, nil
+}
+
+func compile(schema, p string) (path, error) {
+	ev, err := jsonpath.New(p)
+	if err != nil {
+		return nil, errors.Wrap(errPath, errors.New(fmt.Sprintf("schema %s: %s", schema, err)))
+	}
+	return path(ev), nil
+}
+
+// Map maps the decoded payload of the message to messages. The payload is
+// flattened if its channel has no schema.
+func (m *Mapper) Map(msg broker.Message, payload interface{}) ([]senml.Message, error) {
+	s := m.schemas[m.channels[msg.Channel]]
+
+	t, err := payloadTime(s.time, payload)
+	if err != nil {
+		return nil, err
+	}
+	base := senml.Message{
+		Channel:   msg.Channel,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Time:      t,
+	}
+
+	msgs := []senml.Message{}
+	if len(s.fields) == 0 {
+		return flatten(msgs, base, "", payload), nil
+	}
+
+	for _, f := range s.fields {
+		// The values missing from the payload are skipped.
+		v, err := f.path(context.Background(), payload)
+		if err != nil {
+			continue
+		}
+		fb := base
+		fb.Unit = f.unit
+		msgs = flatten(msgs, fb, f.name, v)
+	}
+
+	return msgs, nil
+}
+
+func payloadTime(p path, payload interface{}) (float64, error) {
+	if p == nil {
+		return float64(time.Now().UnixNano()) / 1e9, nil
+	}
+
+	v, err := p(context.Background(), payload)
+	if err != nil {
+		return 0, errors.Wrap(errTime, err)
+	}
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case string:
+		pt, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return 0, errors.Wrap(errTime, err)
+		}
+		return float64(pt.UnixNano()) / 1e9, nil
+	default:
+		return 0, errTime
+	}
+}
+
+// flatten appends the messages of the scalars of the value, named by their
+// path under the name, in the order of their keys.
+func flatten(msgs []senml.Message, base senml.Message, name string, value interface{}) []senml.Message {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			msgs = flatten(msgs, base, join(name, k), v[k])
+		}
+		return msgs
+	case []interface{}:
+		for i, e := range v {
+			msgs = flatten(msgs, base, join(name, strconv.Itoa(i)), e)
+		}
+		return msgs
+	}
+
+	msg := base
+	msg.Name = name
+	switch v := value.(type) {
+	case float64:
+		msg.Value = &v
+	case bool:
+		msg.BoolValue = &v
+	case string:
+		msg.StringValue = &v
+	case []byte:
+		data := base64.StdEncoding.EncodeToString(v)
+		msg.DataValue = &data
+	default:
+		return msgs
+	}
+
+	return append(msgs, msg)
+}
+
+func join(name, key string) string {
+	if name == "" {
+		return key
+	}
+	return name + separator + key
+}
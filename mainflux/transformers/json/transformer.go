@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package json
+
+import (
+	"encoding/json"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/pkg/errors"
+)
+
+// ContentType is the content type of JSON messages.
+const ContentType = "application/json"
+
+var errDecode = errors.New("failed to decode JSON payload")
+
+var _ transformers.Transformer = (*transformer)(nil)
+
+type transformer struct {
+	mapper *Mapper
+}
+
+// New returns transformer service implementation for JSON messages.
+func New(schemas transformers.Schemas) (transformers.Transformer, error) {
+	mapper, err := NewMapper(schemas)
+	if err != nil {
+		return nil, err
+	}
+	return transformer{mapper: mapper}, nil
+}
+
+func (t transformer) Transform(msg broker.Message) (interface{}, error) {
+	var payload interface{}
+	if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+		return nil, errors.Wrap(errDecode, err)
+	}
+
+	return t.mapper.Map(msg, payload)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package json_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/json"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mappedChannel = "mapped"
+	payload       = `{"ts": 1500000000.5, "main": {"temp": 21.5, "ok": true}, "tags": ["a", "b"], "id": "dev"}`
+)
+
+func TestTransform(t *testing.T) {
+	schemas := transformers.Schemas{
+		Schemas: map[string]transformers.Schema{
+			"weather": {
+				Time: "$.ts",
+				Fields: []transformers.Field{
+					{Path: "$.main.temp", Name: "temperature", Unit: "Cel"},
+					{Path: "$.main.humidity", Name: "humidity", Unit: "%RH"},
+					{Path: "$.tags"},
+				},
+			},
+		},
+		Channels: map[string]string{mappedChannel: "weather"},
+	}
+	tr, err := json.New(schemas)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := broker.Message{
+		Channel:     "flattened",
+		Subtopic:    "subtopic",
+		Publisher:   "publisher",
+		Protocol:    "http",
+		ContentType: json.ContentType,
+		Payload:     []byte(payload),
+	}
+	mapped := msg
+	mapped.Channel = mappedChannel
+
+	temp, ok, ts := 21.5, true, 1500000000.5
+	a, b, id := "a", "b", "dev"
+	base := senml.Message{Subtopic: "subtopic", Publisher: "publisher", Protocol: "http"}
+	record := func(chanID, name, unit string, update func(*senml.Message)) senml.Message {
+		m := base
+		m.Channel, m.Name, m.Unit, m.Time = chanID, name, unit, ts
+		update(&m)
+		return m
+	}
+
+	cases := []struct {
+		desc string
+		msg  broker.Message
+		msgs []senml.Message
+		err  error
+	}{
+		{
+			desc: "transform flattened JSON",
+			msg:  msg,
+			msgs: []senml.Message{
+				record(msg.Channel, "id", "", func(m *senml.Message) { m.StringValue = &id }),
+				record(msg.Channel, "main.ok", "", func(m *senml.Message) { m.BoolValue = &ok }),
+				record(msg.Channel, "main.temp", "", func(m *senml.Message) { m.Value = &temp }),
+				record(msg.Channel, "tags.0", "", func(m *senml.Message) { m.StringValue = &a }),
+				record(msg.Channel, "tags.1", "", func(m *senml.Message) { m.StringValue = &b }),
+				record(msg.Channel, "ts", "", func(m *senml.Message) { m.Value = &ts }),
+			},
+			err: nil,
+		},
+		{
+			desc: "transform mapped JSON",
+			msg:  mapped,
+			msgs: []senml.Message{
+				record(mappedChannel, "temperature", "Cel", func(m *senml.Message) { m.Value = &temp }),
+				record(mappedChannel, "tags.0", "", func(m *senml.Message) { m.StringValue = &a }),
+				record(mappedChannel, "tags.1", "", func(m *senml.Message) { m.StringValue = &b }),
+			},
+			err: nil,
+		},
+		{
+			desc: "transform invalid JSON",
+			msg:  broker.Message{Channel: "flattened", Payload: []byte("{")},
+			msgs: nil,
+			err:  errors.New("failed to decode JSON payload"),
+		},
+		{
+			desc: "transform JSON without time",
+			msg:  broker.Message{Channel: mappedChannel, Payload: []byte(`{"main": {"temp": 21.5}}`)},
+			msgs: nil,
+			err:  errors.New("invalid payload time"),
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := tr.Transform(tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		msgs, ok := res.([]senml.Message)
+		require.True(t, ok, fmt.Sprintf("%s: expected SenML messages got %T", tc.desc, res))
+		if tc.msg.Channel != mappedChannel {
+			// The flattened payload has no time path, so the messages are
+			// timed when transformed.
+			for i := range msgs {
+				assert.NotZero(t, msgs[i].Time, fmt.Sprintf("%s: expected message time", tc.desc))
+				msgs[i].Time = ts
+			}
+		}
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.msgs, msgs))
+	}
+}
+
+func TestNewInvalidPath(t *testing.T) {
+	schemas := transformers.Schemas{
+		Schemas: map[string]transformers.Schema{
+			"invalid": {Fields: []transformers.Field{{Path: "$.[", Name: "invalid"}}},
+		},
+	}
+	_, err := json.New(schemas)
+	assert.True(t, errors.Contains(err, errors.New("invalid schema path")), fmt.Sprintf("expected invalid schema path got %s", err))
+}
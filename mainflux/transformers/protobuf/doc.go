@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protobuf contains protobuf transformer, which decodes the payloads
+// by the registered message descriptors and maps them to messages by the
+// schemas of their channels.
+package protobuf
@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf
+
+import (
+	"mime"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/json"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/pkg/protodesc"
+)
+
+const (
+	// ContentType is the content type of protobuf messages.
+	ContentType = "application/x-protobuf"
+
+	// MessageTypeParam is the content type parameter of the full name of
+	// the message type of the payload, e.g.
+	// application/x-protobuf; messageType=acme.Reading.
+	MessageTypeParam = "messagetype"
+)
+
+var errNoMessageType = errors.New("missing protobuf message type")
+
+var _ transformers.Transformer = (*transformer)(nil)
+
+type transformer struct {
+	descriptors *protodesc.Descriptors
+	schemas     transformers.Schemas
+	mapper      *json.Mapper
+}
+
+// New returns transformer service implementation for protobuf messages. The
+// message type of a payload is the one of its content type, or the one of
+// the schema of its channel.
+func New(descriptors *protodesc.Descriptors, schemas transformers.Schemas) (transformers.Transformer, error) {
+	mapper, err := json.NewMapper(schemas)
+	if err != nil {
+		return nil, err
+	}
+	return transformer{
+		descriptors: descriptors,
+		schemas:     schemas,
+		mapper:      mapper,
+	}, nil
+}
+
+func (t transformer) Transform(msg broker.Message) (interface{}, error) {
+	var msgType string
+	if s, ok := t.schemas.Of(msg.Channel); ok {
+		msgType = s.Message
+	}
+	if _, params, err := mime.ParseMediaType(msg.ContentType); err == nil && params[MessageTypeParam] != "" {
+		msgType = params[MessageTypeParam]
+	}
+	if msgType == "" {
+		return nil, errNoMessageType
+	}
+
+	payload, err := t.descriptors.Decode(msgType, msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.mapper.Map(msg, payload)
+}
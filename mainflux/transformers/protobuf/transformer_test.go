@@ -0,0 +1,283 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package protobuf_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/protobuf"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/pkg/protodesc"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mappedChannel = "mapped"
+	readingType   = "acme.Reading"
+)
+
+func field(name string, num int32, typ descriptor.FieldDescriptorProto_Type, typeName string, repeated bool) *descriptor.FieldDescriptorProto {
+	label := descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptor.FieldDescriptorProto_LABEL_REPEATED
+	}
+	f := &descriptor.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(num),
+		Label:  &label,
+		Type:   &typ,
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+// readingFile describes the acme.Reading message type:
+//
+//	message Reading {
+//	  enum Status { OK = 0; FAILED = 1; }
+//	  message Location { float lat = 1; float lon = 2; }
+//	  double temp = 1;
+//	  sint32 delta = 2;
+//	  repeated int32 samples = 3;
+//	  string id = 4;
+//	  Status status = 5;
+//	  Location location = 6;
+//	  map<string, double> extra = 7;
+//	  bytes raw = 8;
+//	  uint64 ts = 9;
+//	}
+func readingFile() *descriptor.FileDescriptorProto {
+	return &descriptor.FileDescriptorProto{
+		Name:    proto.String("reading.proto"),
+		Package: proto.String("acme"),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Reading"),
+			Field: []*descriptor.FieldDescriptorProto{
+				field("temp", 1, descriptor.FieldDescriptorProto_TYPE_DOUBLE, "", false),
+				field("delta", 2, descriptor.FieldDescriptorProto_TYPE_SINT32, "", false),
+				field("samples", 3, descriptor.FieldDescriptorProto_TYPE_INT32, "", true),
+				field("id", 4, descriptor.FieldDescriptorProto_TYPE_STRING, "", false),
+				field("status", 5, descriptor.FieldDescriptorProto_TYPE_ENUM, ".acme.Reading.Status", false),
+				field("location", 6, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".acme.Reading.Location", false),
+				field("extra", 7, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".acme.Reading.ExtraEntry", true),
+				field("raw", 8, descriptor.FieldDescriptorProto_TYPE_BYTES, "", false),
+				field("ts", 9, descriptor.FieldDescriptorProto_TYPE_UINT64, "", false),
+			},
+			NestedType: []*descriptor.DescriptorProto{
+				{
+					Name: proto.String("Location"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("lat", 1, descriptor.FieldDescriptorProto_TYPE_FLOAT, "", false),
+						field("lon", 2, descriptor.FieldDescriptorProto_TYPE_FLOAT, "", false),
+					},
+				},
+				{
+					Name: proto.String("ExtraEntry"),
+					Field: []*descriptor.FieldDescriptorProto{
+						field("key", 1, descriptor.FieldDescriptorProto_TYPE_STRING, "", false),
+						field("value", 2, descriptor.FieldDescriptorProto_TYPE_DOUBLE, "", false),
+					},
+					Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+				},
+			},
+			EnumType: []*descriptor.EnumDescriptorProto{{
+				Name: proto.String("Status"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					{Name: proto.String("OK"), Number: proto.Int32(0)},
+					{Name: proto.String("FAILED"), Number: proto.Int32(1)},
+				},
+			}},
+		}},
+	}
+}
+
+func key(b *proto.Buffer, num, wire uint64) {
+	b.EncodeVarint(num<<3 | wire)
+}
+
+func readingPayload() []byte {
+	loc := proto.NewBuffer(nil)
+	key(loc, 1, proto.WireFixed32)
+	loc.EncodeFixed32(uint64(math.Float32bits(1.5)))
+	key(loc, 2, proto.WireFixed32)
+	loc.EncodeFixed32(uint64(math.Float32bits(-2.5)))
+
+	entry := proto.NewBuffer(nil)
+	key(entry, 1, proto.WireBytes)
+	entry.EncodeStringBytes("battery")
+	key(entry, 2, proto.WireFixed64)
+	entry.EncodeFixed64(math.Float64bits(3.3))
+
+	samples := proto.NewBuffer(nil)
+	for _, s := range []uint64{1, 2} {
+		samples.EncodeVarint(s)
+	}
+
+	delta := int32(-3)
+	b := proto.NewBuffer(nil)
+	key(b, 1, proto.WireFixed64)
+	b.EncodeFixed64(math.Float64bits(21.5))
+	key(b, 2, proto.WireVarint)
+	b.EncodeZigzag32(uint64(delta))
+	key(b, 3, proto.WireBytes)
+	b.EncodeRawBytes(samples.Bytes())
+	key(b, 3, proto.WireVarint)
+	b.EncodeVarint(3)
+	key(b, 4, proto.WireBytes)
+	b.EncodeStringBytes("dev")
+	key(b, 5, proto.WireVarint)
+	b.EncodeVarint(1)
+	key(b, 6, proto.WireBytes)
+	b.EncodeRawBytes(loc.Bytes())
+	key(b, 7, proto.WireBytes)
+	b.EncodeRawBytes(entry.Bytes())
+	key(b, 8, proto.WireBytes)
+	b.EncodeRawBytes([]byte{1, 2})
+	// An unknown field is skipped.
+	key(b, 15, proto.WireVarint)
+	b.EncodeVarint(7)
+	key(b, 9, proto.WireVarint)
+	b.EncodeVarint(1500000000)
+
+	return b.Bytes()
+}
+
+func TestTransform(t *testing.T) {
+	dir, err := ioutil.TempDir("", "protobuf")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	set, err := proto.Marshal(&descriptor.FileDescriptorSet{File: []*descriptor.FileDescriptorProto{readingFile()}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	path := filepath.Join(dir, "reading.pb")
+	require.Nil(t, ioutil.WriteFile(path, set, 0644), "unexpected error writing descriptors")
+
+	descriptors, err := protodesc.LoadDescriptors(path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	schemas := transformers.Schemas{
+		Schemas: map[string]transformers.Schema{
+			"reading": {
+				Message: readingType,
+				Time:    "$.ts",
+				Fields: []transformers.Field{
+					{Path: "$.temp", Name: "temperature", Unit: "Cel"},
+					{Path: "$.location"},
+				},
+			},
+		},
+		Channels: map[string]string{mappedChannel: "reading"},
+	}
+	tr, err := protobuf.New(descriptors, schemas)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := broker.Message{
+		Channel:     "flattened",
+		Publisher:   "publisher",
+		Protocol:    "mqtt",
+		ContentType: protobuf.ContentType + "; messageType=" + readingType,
+		Payload:     readingPayload(),
+	}
+	mapped := msg
+	mapped.Channel = mappedChannel
+	mapped.ContentType = protobuf.ContentType
+
+	temp, delta, battery, lat, lon, ts := 21.5, -3.0, 3.3, 1.5, -2.5, 1500000000.0
+	s1, s2, s3 := 1.0, 2.0, 3.0
+	id, status := "dev", "FAILED"
+	raw := base64.StdEncoding.EncodeToString([]byte{1, 2})
+	record := func(chanID, name, unit string, update func(*senml.Message)) senml.Message {
+		m := senml.Message{Channel: chanID, Publisher: "publisher", Protocol: "mqtt", Name: name, Unit: unit, Time: ts}
+		update(&m)
+		return m
+	}
+
+	cases := []struct {
+		desc string
+		msg  broker.Message
+		msgs []senml.Message
+		err  error
+	}{
+		{
+			desc: "transform flattened protobuf",
+			msg:  msg,
+			msgs: []senml.Message{
+				record(msg.Channel, "delta", "", func(m *senml.Message) { m.Value = &delta }),
+				record(msg.Channel, "extra.battery", "", func(m *senml.Message) { m.Value = &battery }),
+				record(msg.Channel, "id", "", func(m *senml.Message) { m.StringValue = &id }),
+				record(msg.Channel, "location.lat", "", func(m *senml.Message) { m.Value = &lat }),
+				record(msg.Channel, "location.lon", "", func(m *senml.Message) { m.Value = &lon }),
+				record(msg.Channel, "raw", "", func(m *senml.Message) { m.DataValue = &raw }),
+				record(msg.Channel, "samples.0", "", func(m *senml.Message) { m.Value = &s1 }),
+				record(msg.Channel, "samples.1", "", func(m *senml.Message) { m.Value = &s2 }),
+				record(msg.Channel, "samples.2", "", func(m *senml.Message) { m.Value = &s3 }),
+				record(msg.Channel, "status", "", func(m *senml.Message) { m.StringValue = &status }),
+				record(msg.Channel, "temp", "", func(m *senml.Message) { m.Value = &temp }),
+				record(msg.Channel, "ts", "", func(m *senml.Message) { m.Value = &ts }),
+			},
+			err: nil,
+		},
+		{
+			desc: "transform mapped protobuf",
+			msg:  mapped,
+			msgs: []senml.Message{
+				record(mappedChannel, "temperature", "Cel", func(m *senml.Message) { m.Value = &temp }),
+				record(mappedChannel, "location.lat", "", func(m *senml.Message) { m.Value = &lat }),
+				record(mappedChannel, "location.lon", "", func(m *senml.Message) { m.Value = &lon }),
+			},
+			err: nil,
+		},
+		{
+			desc: "transform protobuf without message type",
+			msg:  broker.Message{Channel: "flattened", ContentType: protobuf.ContentType, Payload: readingPayload()},
+			msgs: nil,
+			err:  errors.New("missing protobuf message type"),
+		},
+		{
+			desc: "transform protobuf of unknown message type",
+			msg:  broker.Message{Channel: "flattened", ContentType: protobuf.ContentType + "; messageType=acme.Unknown", Payload: readingPayload()},
+			msgs: nil,
+			err:  errors.New("unknown protobuf message type"),
+		},
+		{
+			desc: "transform malformed protobuf",
+			msg:  broker.Message{Channel: mappedChannel, Payload: readingPayload()[:5]},
+			msgs: nil,
+			err:  errors.New("malformed protobuf payload"),
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := tr.Transform(tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		msgs, ok := res.([]senml.Message)
+		require.True(t, ok, fmt.Sprintf("%s: expected SenML messages got %T", tc.desc, res))
+		if tc.msg.Channel != mappedChannel {
+			// The flattened payload has no time path, so the messages are
+			// timed when transformed.
+			for i := range msgs {
+				assert.NotZero(t, msgs[i].Time, fmt.Sprintf("%s: expected message time", tc.desc))
+				msgs[i].Time = ts
+			}
+		}
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.msgs, msgs))
+	}
+}
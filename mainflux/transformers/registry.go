@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"mime"
+	"strings"
+	"sync"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/pkg/errors"
+)
+
+// ErrUnsupportedContentType indicates a message of a content type without a
+// transformer.
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Registry is a transformer which transforms the messages by the transformer
+// registered for their content type.
+type Registry interface {
+	Transformer
+
+	// Register registers the transformer of the content type, replacing the
+	// one registered before.
+	Register(contentType string, t Transformer)
+}
+
+var _ Registry = (*registry)(nil)
+
+type registry struct {
+	mu           sync.RWMutex
+	defaultType  string
+	transformers map[string]Transformer
+}
+
+// NewRegistry returns an empty registry, which transforms the messages
+// without a content type as the ones of the default content type.
+func NewRegistry(defaultType string) Registry {
+	return &registry{
+		defaultType:  defaultType,
+		transformers: make(map[string]Transformer),
+	}
+}
+
+func (r *registry) Register(contentType string, t Transformer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.transformers[MediaType(contentType)] = t
+}
+
+func (r *registry) Transform(msg broker.Message) (interface{}, error) {
+	ct := msg.ContentType
+	if ct == "" {
+		ct = r.defaultType
+	}
+
+	r.mu.RLock()
+	t, ok := r.transformers[MediaType(ct)]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, errors.Wrap(ErrUnsupportedContentType, errors.New(ct))
+	}
+
+	return t.Transform(msg)
+}
+
+// MediaType returns the media type of the content type without its
+// parameters.
+func MediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return mt
+}
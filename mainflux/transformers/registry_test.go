@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// transformerMock transforms the messages to its name.
+type transformerMock string
+
+func (t transformerMock) Transform(broker.Message) (interface{}, error) {
+	return string(t), nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := transformers.NewRegistry("application/senml+json")
+	r.Register("application/senml+json", transformerMock("senml"))
+	r.Register("application/json", transformerMock("old json"))
+	r.Register("Application/JSON", transformerMock("json"))
+
+	cases := map[string]struct {
+		contentType string
+		res         interface{}
+		err         error
+	}{
+		"transform registered content type": {
+			contentType: "application/json",
+			res:         "json",
+			err:         nil,
+		},
+		"transform content type with parameters": {
+			contentType: "application/json; charset=utf-8",
+			res:         "json",
+			err:         nil,
+		},
+		"transform without content type": {
+			contentType: "",
+			res:         "senml",
+			err:         nil,
+		},
+		"transform unsupported content type": {
+			contentType: "application/xml",
+			res:         nil,
+			err:         transformers.ErrUnsupportedContentType,
+		},
+	}
+
+	for desc, tc := range cases {
+		res, err := r.Transform(broker.Message{ContentType: tc.contentType})
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v", desc, tc.res, res))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+// Field maps a value of the payloads to the messages of its name.
+type Field struct {
+	// Path is the JSONPath of the value in the payload.
+	Path string `toml:"path"`
+
+	// Name is the name of the messages of the value, its path if empty.
+	Name string `toml:"name"`
+
+	// Unit is the unit of the value.
+	Unit string `toml:"unit"`
+}
+
+// Schema specifies how the payloads are mapped to messages. The payloads
+// are flattened into the messages of each of their values, named by their
+// path, if the schema has no fields.
+type Schema struct {
+	// Message is the full name of the protobuf message type of the payloads.
+	Message string `toml:"message"`
+
+	// Time is the JSONPath of the time of the payload, in seconds or in
+	// RFC3339 format. The time the payload is transformed at is used if
+	// it's empty.
+	Time string `toml:"time"`
+
+	// Fields lists the values mapped to messages.
+	Fields []Field `toml:"fields"`
+}
+
+// Schemas maps the channels to the schemas of their payloads.
+type Schemas struct {
+	// Schemas are the schemas by name.
+	Schemas map[string]Schema `toml:"schemas"`
+
+	// Channels are the schema names by channel.
+	Channels map[string]string `toml:"channels"`
+}
+
+// Of returns the schema of the payloads of the channel.
+func (s Schemas) Of(chanID string) (Schema, bool) {
+	name, ok := s.Channels[chanID]
+	if !ok {
+		return Schema{}, false
+	}
+	schema, ok := s.Schemas[name]
+	return schema, ok
+}
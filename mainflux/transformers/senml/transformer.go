@@ -22,9 +22,14 @@ func New() transformers.Transformer {
 }
 
 func (n transformer) Transform(msg broker.Message) (interface{}, error) {
-	format, ok := formats[msg.ContentType]
-	if !ok {
-		format = senml.JSON
+	// The messages without a content type are JSON SenML.
+	format := senml.JSON
+	if msg.ContentType != "" {
+		f, ok := formats[transformers.MediaType(msg.ContentType)]
+		if !ok {
+			return nil, transformers.ErrUnsupportedContentType
+		}
+		format = f
 	}
 
 	raw, err := senml.Decode(msg.Payload, format)
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
 	"github.com/cloustone/pandas/mainflux/transformers/senml"
 	mfsenml "github.com/mainflux/senml"
 	"github.com/stretchr/testify/assert"
@@ -47,6 +48,9 @@ func TestTransform(t *testing.T) {
 	cborPld.ContentType = senml.CBOR
 	cborPld.Payload = cborBytes
 
+	unsupportedMsg := msg
+	unsupportedMsg.ContentType = "application/xml"
+
 	tooManyMsg := msg
 	tooManyMsg.ContentType = senml.CBOR
 	tooManyMsg.Payload = tooManyBytes
@@ -91,6 +95,18 @@ func TestTransform(t *testing.T) {
 			msgs: msgs,
 			err:  nil,
 		},
+		{
+			desc: "test normalize JSON with parameters",
+			msg:  broker.Message{Channel: "channel", Subtopic: "subtopic", Publisher: "publisher", Protocol: "protocol", ContentType: senml.JSON + "; charset=utf-8", Payload: jsonBytes},
+			msgs: msgs,
+			err:  nil,
+		},
+		{
+			desc: "test unsupported content type",
+			msg:  unsupportedMsg,
+			msgs: nil,
+			err:  transformers.ErrUnsupportedContentType,
+		},
 		{
 			desc: "test invalid payload",
 			msg:  tooManyMsg,
@@ -0,0 +1,80 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func savePolicyEndpoint(svc writers.RetentionService) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(savePolicyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p := req.policy()
+		if err := svc.SavePolicy(p); err != nil {
+			return nil, err
+		}
+
+		return policyRes{p}, nil
+	}
+}
+
+func viewPolicyEndpoint(svc writers.RetentionService) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(policyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.ViewPolicy(req.chanID)
+		if err != nil {
+			return nil, err
+		}
+
+		return policyRes{p}, nil
+	}
+}
+
+func listPoliciesEndpoint(svc writers.RetentionService) endpoint.Endpoint {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		policies, err := svc.ListPolicies()
+		if err != nil {
+			return nil, err
+		}
+
+		return policiesRes{Policies: policies}, nil
+	}
+}
+
+func removePolicyEndpoint(svc writers.RetentionService) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(policyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemovePolicy(req.chanID); err != nil {
+			return nil, err
+		}
+
+		return emptyRes{code: http.StatusNoContent}, nil
+	}
+}
+
+func enforceEndpoint(svc writers.RetentionService) endpoint.Endpoint {
+	return func(_ context.Context, _ interface{}) (interface{}, error) {
+		if err := svc.Enforce(); err != nil {
+			return nil, err
+		}
+
+		return emptyRes{code: http.StatusNoContent}, nil
+	}
+}
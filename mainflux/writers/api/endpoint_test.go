@@ -0,0 +1,387 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/cloustone/pandas/mainflux/writers/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	svcName     = "test-service"
+	adminKey    = "admin"
+	invalid     = "invalid"
+	contentType = "application/json"
+	chanID      = "1"
+)
+
+var _ writers.RetentionService = (*retentionServiceMock)(nil)
+
+type retentionServiceMock struct {
+	mu       sync.Mutex
+	policies map[string]writers.Policy
+	enforced int
+}
+
+func (svc *retentionServiceMock) SavePolicy(p writers.Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.policies[p.Channel] = p
+	return nil
+}
+
+func (svc *retentionServiceMock) ViewPolicy(chanID string) (writers.Policy, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	p, ok := svc.policies[chanID]
+	if !ok {
+		return writers.Policy{}, writers.ErrNotFound
+	}
+	return p, nil
+}
+
+func (svc *retentionServiceMock) ListPolicies() ([]writers.Policy, error) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	policies := []writers.Policy{}
+	for _, p := range svc.policies {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (svc *retentionServiceMock) RemovePolicy(chanID string) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	delete(svc.policies, chanID)
+	return nil
+}
+
+func (svc *retentionServiceMock) Enforce() error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.enforced++
+	return nil
+}
+
+func newService() *retentionServiceMock {
+	return &retentionServiceMock{
+		policies: map[string]writers.Policy{
+			chanID: {Channel: chanID, RetentionDays: 7},
+		},
+	}
+}
+
+func newServer(svc writers.RetentionService, key string) *httptest.Server {
+	return httptest.NewServer(api.MakeHandler(svcName, svc, key))
+}
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+func TestSavePolicy(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc, adminKey)
+	defer ts.Close()
+
+	valid := `{"retention_days": 30, "downsampling": ["hour", "day"]}`
+
+	cases := map[string]struct {
+		chanID      string
+		contentType string
+		token       string
+		body        string
+		status      int
+	}{
+		"save valid policy": {
+			chanID:      chanID,
+			contentType: contentType,
+			token:       adminKey,
+			body:        valid,
+			status:      http.StatusOK,
+		},
+		"save default policy": {
+			chanID:      writers.DefaultPolicy,
+			contentType: contentType,
+			token:       adminKey,
+			body:        `{"retention_days": 1}`,
+			status:      http.StatusOK,
+		},
+		"save policy without key": {
+			chanID:      writers.DefaultPolicy,
+			contentType: contentType,
+			token:       "",
+			body:        `{"retention_days": 1}`,
+			status:      http.StatusForbidden,
+		},
+		"save policy with invalid key": {
+			chanID:      writers.DefaultPolicy,
+			contentType: contentType,
+			token:       invalid,
+			body:        `{"retention_days": 1}`,
+			status:      http.StatusForbidden,
+		},
+		"save policy with invalid content type": {
+			chanID:      chanID,
+			contentType: "text/plain",
+			token:       adminKey,
+			body:        valid,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		"save policy with malformed body": {
+			chanID:      chanID,
+			contentType: contentType,
+			token:       adminKey,
+			body:        `{`,
+			status:      http.StatusBadRequest,
+		},
+		"save policy with unknown rollup": {
+			chanID:      chanID,
+			contentType: contentType,
+			token:       adminKey,
+			body:        `{"retention_days": 7, "downsampling": ["week"]}`,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/retention/policies/%s", ts.URL, tc.chanID),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+	}
+
+	p, err := svc.ViewPolicy(writers.DefaultPolicy)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, 1, p.RetentionDays, "unauthorized requests changed the default policy")
+}
+
+func TestViewPolicy(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc, adminKey)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		chanID string
+		token  string
+		status int
+		res    writers.Policy
+	}{
+		"view existing policy": {
+			chanID: chanID,
+			token:  adminKey,
+			status: http.StatusOK,
+			res:    writers.Policy{Channel: chanID, RetentionDays: 7},
+		},
+		"view non-existing policy": {
+			chanID: "2",
+			token:  adminKey,
+			status: http.StatusNotFound,
+		},
+		"view policy without key": {
+			chanID: chanID,
+			token:  "",
+			status: http.StatusForbidden,
+		},
+		"view policy with invalid key": {
+			chanID: chanID,
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/retention/policies/%s", ts.URL, tc.chanID),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		var p writers.Policy
+		err = json.NewDecoder(res.Body).Decode(&p)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.res, p, fmt.Sprintf("%s: expected %v got %v", desc, tc.res, p))
+	}
+}
+
+func TestRemovePolicy(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc, adminKey)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		token  string
+		status int
+	}{
+		"remove policy without key": {
+			token:  "",
+			status: http.StatusForbidden,
+		},
+		"remove policy with invalid key": {
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+		"remove policy": {
+			token:  adminKey,
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, desc := range []string{"remove policy without key", "remove policy with invalid key", "remove policy"} {
+		tc := cases[desc]
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/retention/policies/%s", ts.URL, chanID),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		_, err = svc.ViewPolicy(chanID)
+		assert.Equal(t, tc.status == http.StatusNoContent, err == writers.ErrNotFound, fmt.Sprintf("%s: unexpected policy state %s", desc, err))
+	}
+}
+
+func TestListPolicies(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc, adminKey)
+	defer ts.Close()
+
+	cases := map[string]struct {
+		token  string
+		status int
+	}{
+		"list policies": {
+			token:  adminKey,
+			status: http.StatusOK,
+		},
+		"list policies without key": {
+			token:  "",
+			status: http.StatusForbidden,
+		},
+		"list policies with invalid key": {
+			token:  invalid,
+			status: http.StatusForbidden,
+		},
+	}
+
+	for desc, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/retention/policies", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		var body struct {
+			Policies []writers.Policy `json:"policies"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Len(t, body.Policies, 1, fmt.Sprintf("%s: expected 1 policy got %d", desc, len(body.Policies)))
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	cases := map[string]struct {
+		key      string
+		token    string
+		status   int
+		enforced int
+	}{
+		"enforce policies": {
+			key:      adminKey,
+			token:    adminKey,
+			status:   http.StatusNoContent,
+			enforced: 1,
+		},
+		"enforce policies without key": {
+			key:      adminKey,
+			token:    "",
+			status:   http.StatusForbidden,
+			enforced: 0,
+		},
+		"enforce policies with invalid key": {
+			key:      adminKey,
+			token:    invalid,
+			status:   http.StatusForbidden,
+			enforced: 0,
+		},
+		"enforce policies with disabled API": {
+			key:      "",
+			token:    "",
+			status:   http.StatusForbidden,
+			enforced: 0,
+		},
+	}
+
+	for desc, tc := range cases {
+		svc := newService()
+		ts := newServer(svc, tc.key)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodPost,
+			url:    fmt.Sprintf("%s/retention/enforce", ts.URL),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		ts.Close()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.enforced, svc.enforced, fmt.Sprintf("%s: expected %d enforcements got %d", desc, tc.enforced, svc.enforced))
+	}
+}
@@ -38,3 +38,80 @@ func (lm *loggingMiddleware) Save(msgs ...senml.Message) (err error) {
 
 	return lm.svc.Save(msgs...)
 }
+
+var _ writers.RetentionService = (*retentionLoggingMiddleware)(nil)
+
+type retentionLoggingMiddleware struct {
+	logger log.Logger
+	svc    writers.RetentionService
+}
+
+// RetentionLoggingMiddleware adds logging facilities to the retention service.
+func RetentionLoggingMiddleware(svc writers.RetentionService, logger log.Logger) writers.RetentionService {
+	return &retentionLoggingMiddleware{logger, svc}
+}
+
+func (lm *retentionLoggingMiddleware) SavePolicy(p writers.Policy) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_policy for channel %s took %s to complete", p.Channel, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SavePolicy(p)
+}
+
+func (lm *retentionLoggingMiddleware) ViewPolicy(chanID string) (p writers.Policy, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_policy for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewPolicy(chanID)
+}
+
+func (lm *retentionLoggingMiddleware) ListPolicies() (policies []writers.Policy, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_policies took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListPolicies()
+}
+
+func (lm *retentionLoggingMiddleware) RemovePolicy(chanID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_policy for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemovePolicy(chanID)
+}
+
+func (lm *retentionLoggingMiddleware) Enforce() (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method enforce took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Enforce()
+}
@@ -34,3 +34,66 @@ func (mm *metricsMiddleware) Save(msgs ...senml.Message) error {
 	}(time.Now())
 	return mm.repo.Save(msgs...)
 }
+
+var _ writers.RetentionService = (*retentionMetricsMiddleware)(nil)
+
+type retentionMetricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     writers.RetentionService
+}
+
+// RetentionMetricsMiddleware instruments the retention service by tracking
+// request count and latency.
+func RetentionMetricsMiddleware(svc writers.RetentionService, counter metrics.Counter, latency metrics.Histogram) writers.RetentionService {
+	return &retentionMetricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *retentionMetricsMiddleware) SavePolicy(p writers.Policy) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "save_policy").Add(1)
+		mm.latency.With("method", "save_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.SavePolicy(p)
+}
+
+func (mm *retentionMetricsMiddleware) ViewPolicy(chanID string) (writers.Policy, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "view_policy").Add(1)
+		mm.latency.With("method", "view_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ViewPolicy(chanID)
+}
+
+func (mm *retentionMetricsMiddleware) ListPolicies() ([]writers.Policy, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "list_policies").Add(1)
+		mm.latency.With("method", "list_policies").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.ListPolicies()
+}
+
+func (mm *retentionMetricsMiddleware) RemovePolicy(chanID string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "remove_policy").Add(1)
+		mm.latency.With("method", "remove_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.RemovePolicy(chanID)
+}
+
+func (mm *retentionMetricsMiddleware) Enforce() error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "enforce").Add(1)
+		mm.latency.With("method", "enforce").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Enforce()
+}
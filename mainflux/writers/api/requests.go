@@ -0,0 +1,35 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/cloustone/pandas/mainflux/writers"
+
+type savePolicyReq struct {
+	chanID        string
+	RetentionDays int      `json:"retention_days"`
+	Downsampling  []string `json:"downsampling,omitempty"`
+}
+
+func (req savePolicyReq) validate() error {
+	return req.policy().Validate()
+}
+
+func (req savePolicyReq) policy() writers.Policy {
+	return writers.Policy{
+		Channel:       req.chanID,
+		RetentionDays: req.RetentionDays,
+		Downsampling:  req.Downsampling,
+	}
+}
+
+type policyReq struct {
+	chanID string
+}
+
+func (req policyReq) validate() error {
+	if req.chanID == "" {
+		return writers.ErrMalformedEntity
+	}
+	return nil
+}
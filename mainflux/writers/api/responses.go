@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/writers"
+)
+
+var (
+	_ mainflux.Response = (*policyRes)(nil)
+	_ mainflux.Response = (*policiesRes)(nil)
+	_ mainflux.Response = (*emptyRes)(nil)
+)
+
+type policyRes struct {
+	writers.Policy
+}
+
+func (res policyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res policyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res policyRes) Empty() bool {
+	return false
+}
+
+type policiesRes struct {
+	Policies []writers.Policy `json:"policies"`
+}
+
+func (res policiesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res policiesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res policiesRes) Empty() bool {
+	return false
+}
+
+type emptyRes struct {
+	code int
+}
+
+func (res emptyRes) Code() int {
+	return res.code
+}
+
+func (res emptyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res emptyRes) Empty() bool {
+	return true
+}
@@ -6,18 +6,157 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"net/http"
+	"strings"
 
 	"github.com/cloustone/pandas"
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/cloustone/pandas/pkg/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// MakeHandler returns a HTTP API handler with version and metrics.
-func MakeHandler(svcName string) http.Handler {
+const contentType = "application/json"
+
+var (
+	errUnsupportedContentType = errors.New("unsupported content type")
+	errUnauthorizedAccess     = errors.New("missing or invalid credentials provided")
+	adminKey                  string
+)
+
+// MakeHandler returns a HTTP API handler with version, metrics and the
+// retention policies API. The policies apply to the messages of any channel,
+// so the retention API requires the admin key in the Authorization header and
+// it is disabled if the key is empty.
+func MakeHandler(svcName string, svc writers.RetentionService, key string) http.Handler {
+	adminKey = key
+
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
 	r := bone.New()
+
+	r.Put("/retention/policies/:chanID", kithttp.NewServer(
+		savePolicyEndpoint(svc),
+		decodeSavePolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/retention/policies/:chanID", kithttp.NewServer(
+		viewPolicyEndpoint(svc),
+		decodePolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/retention/policies/:chanID", kithttp.NewServer(
+		removePolicyEndpoint(svc),
+		decodePolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/retention/policies", kithttp.NewServer(
+		listPoliciesEndpoint(svc),
+		decodeAdmin,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/retention/enforce", kithttp.NewServer(
+		enforceEndpoint(svc),
+		decodeAdmin,
+		encodeResponse,
+		opts...,
+	))
+
 	r.GetFunc("/version", pandas.Version(svcName))
 	r.Handle("/metrics", promhttp.Handler())
 
 	return r
 }
+
+func decodeSavePolicy(_ context.Context, r *http.Request) (interface{}, error) {
+	if err := authorize(r); err != nil {
+		return nil, err
+	}
+
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := savePolicyReq{chanID: bone.GetValue(r, "chanID")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, writers.ErrMalformedEntity
+	}
+
+	return req, nil
+}
+
+func decodePolicy(_ context.Context, r *http.Request) (interface{}, error) {
+	if err := authorize(r); err != nil {
+		return nil, err
+	}
+
+	req := policyReq{chanID: bone.GetValue(r, "chanID")}
+	return req, nil
+}
+
+func decodeAdmin(_ context.Context, r *http.Request) (interface{}, error) {
+	if err := authorize(r); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func authorize(r *http.Request) error {
+	key := r.Header.Get("Authorization")
+	if adminKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(adminKey)) != 1 {
+		return errUnauthorizedAccess
+	}
+
+	return nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch {
+	case errors.Contains(err, writers.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, writers.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, errUnauthorizedAccess):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, errUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/logger"
+	"github.com/go-kit/kit/metrics"
+)
+
+// Config contains the batching of the saved messages and the handling of the
+// failed ones.
+type Config struct {
+	// BatchSize is the number of the messages saved at once.
+	BatchSize int
+
+	// BatchLinger is the longest time a received message waits for its
+	// batch to fill up.
+	BatchLinger time.Duration
+
+	// BufferSize is the number of the received messages waiting to be
+	// saved. Once the buffer is full, the consumer blocks until a batch is
+	// saved, which leaves the messages pending in NATS.
+	BufferSize int
+
+	// Retries is the number of the times a failed batch is saved again.
+	Retries int
+
+	// RetryBackoff is the delay of the first retry, it doubles with each
+	// retry up to MaxBackoff.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+
+	// DeadLetter keeps the messages which still fail to be saved, they are
+	// dropped if it's nil.
+	DeadLetter DeadLetter
+
+	// DedupSize is the number of the last received message IDs which are
+	// remembered to drop the duplicates, deduplication is disabled if it's
+	// not positive.
+	DedupSize int
+
+	// Latency observes the seconds from the creation of each saved message
+	// by its adapter, it's not observed if it's nil.
+	Latency metrics.Histogram
+}
+
+// entry is a received message along with its transformed messages.
+type entry struct {
+	subject string
+	data    []byte
+	created int64
+	msgs    []senml.Message
+}
+
+// batcher saves the received messages in batches.
+type batcher struct {
+	repo    MessageRepository
+	cfg     Config
+	logger  logger.Logger
+	entries chan entry
+	done    chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newBatcher(repo MessageRepository, cfg Config, logger logger.Logger) *batcher {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1
+	}
+	if cfg.BufferSize < 0 {
+		cfg.BufferSize = 0
+	}
+
+	b := &batcher{
+		repo:    repo,
+		cfg:     cfg,
+		logger:  logger,
+		entries: make(chan entry, cfg.BufferSize),
+		done:    make(chan struct{}),
+	}
+	go b.run()
+
+	return b
+}
+
+// add buffers the entry, blocking while the buffer is full.
+func (b *batcher) add(e entry) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		b.deadLetter(e, errConsumerClosed)
+		return
+	}
+	b.entries <- e
+}
+
+// close saves the buffered entries and waits for them to be saved.
+func (b *batcher) close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.entries)
+	}
+	b.mu.Unlock()
+
+	<-b.done
+}
+
+func (b *batcher) run() {
+	defer close(b.done)
+
+	var batch []entry
+	size := 0
+	var linger <-chan time.Time
+	for {
+		select {
+		case e, ok := <-b.entries:
+			if !ok {
+				b.flush(batch)
+				return
+			}
+			if len(batch) == 0 && b.cfg.BatchLinger > 0 {
+				linger = time.After(b.cfg.BatchLinger)
+			}
+			batch = append(batch, e)
+			size += len(e.msgs)
+			if size < b.cfg.BatchSize && b.cfg.BatchLinger > 0 {
+				continue
+			}
+		case <-linger:
+		}
+
+		b.flush(batch)
+		batch, size, linger = nil, 0, nil
+	}
+}
+
+// flush saves the batch. If it still fails after the retries, the entries are
+// saved one by one, so only the ones which fail on their own are dead
+// lettered.
+func (b *batcher) flush(batch []entry) {
+	if len(batch) == 0 {
+		return
+	}
+
+	msgs := []senml.Message{}
+	for _, e := range batch {
+		msgs = append(msgs, e.msgs...)
+	}
+
+	err := b.save(msgs)
+	if err == nil {
+		b.observe(batch...)
+		return
+	}
+	if len(batch) == 1 {
+		b.deadLetter(batch[0], err)
+		return
+	}
+
+	b.logger.Warn(fmt.Sprintf("Failed to save batch of %d messages, saving them one by one: %s", len(msgs), err))
+	for _, e := range batch {
+		if err := b.repo.Save(e.msgs...); err != nil {
+			b.deadLetter(e, err)
+			continue
+		}
+		b.observe(e)
+	}
+}
+
+// observe records the end-to-end latency of the saved entries.
+func (b *batcher) observe(entries ...entry) {
+	if b.cfg.Latency == nil {
+		return
+	}
+
+	now := time.Now().UnixNano()
+	for _, e := range entries {
+		if e.created > 0 {
+			b.cfg.Latency.Observe(float64(now-e.created) / float64(time.Second))
+		}
+	}
+}
+
+func (b *batcher) save(msgs []senml.Message) error {
+	backoff := b.cfg.RetryBackoff
+	err := b.repo.Save(msgs...)
+	for i := 0; err != nil && i < b.cfg.Retries; i++ {
+		b.logger.Warn(fmt.Sprintf("Failed to save %d messages, retrying in %s: %s", len(msgs), backoff, err))
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if b.cfg.MaxBackoff > 0 && backoff > b.cfg.MaxBackoff {
+			backoff = b.cfg.MaxBackoff
+		}
+		err = b.repo.Save(msgs...)
+	}
+	return err
+}
+
+func (b *batcher) deadLetter(e entry, err error) {
+	if b.cfg.DeadLetter == nil {
+		b.logger.Warn(fmt.Sprintf("Failed to save message: %s", err))
+		return
+	}
+
+	l := Letter{
+		Subject: e.subject,
+		Error:   err.Error(),
+		Time:    time.Now(),
+		Message: e.data,
+	}
+	if err := b.cfg.DeadLetter.Put(l); err != nil {
+		b.logger.Error(fmt.Sprintf("Failed to dead letter message of subject %s: %s", e.subject, err))
+	}
+}
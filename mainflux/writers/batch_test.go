@@ -0,0 +1,229 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/go-kit/kit/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+var errSave = errors.New("save failed")
+
+// repoMock saves the messages, failing the first failures saves and the
+// saves containing a message of the bad publisher.
+type repoMock struct {
+	mu       sync.Mutex
+	failures int
+	bad      string
+	saves    [][]senml.Message
+}
+
+func (repo *repoMock) Save(msgs ...senml.Message) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if repo.failures > 0 {
+		repo.failures--
+		return errSave
+	}
+	for _, msg := range msgs {
+		if repo.bad != "" && msg.Publisher == repo.bad {
+			return errSave
+		}
+	}
+	repo.saves = append(repo.saves, msgs)
+	return nil
+}
+
+func (repo *repoMock) saved() (batches, msgs int) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	for _, save := range repo.saves {
+		msgs += len(save)
+	}
+	return len(repo.saves), msgs
+}
+
+type deadLetterMock struct {
+	mu      sync.Mutex
+	letters []Letter
+}
+
+func (dl *deadLetterMock) Put(l Letter) error {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	dl.letters = append(dl.letters, l)
+	return nil
+}
+
+func (dl *deadLetterMock) Close() error {
+	return nil
+}
+
+type histogramMock struct {
+	mu     sync.Mutex
+	values []float64
+}
+
+func (h *histogramMock) With(labelValues ...string) metrics.Histogram {
+	return h
+}
+
+func (h *histogramMock) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.values = append(h.values, value)
+}
+
+func newEntry(pub string, n int) entry {
+	msgs := make([]senml.Message, n)
+	for i := range msgs {
+		msgs[i] = senml.Message{Publisher: pub}
+	}
+	return entry{subject: "channels.1", data: []byte(pub), msgs: msgs}
+}
+
+func TestBatcher(t *testing.T) {
+	logger, _ := log.New(ioutil.Discard, log.Error.String())
+
+	cases := map[string]struct {
+		cfg      Config
+		repo     *repoMock
+		entries  []entry
+		batches  int
+		saved    int
+		dead     []string
+		closeNow bool
+	}{
+		"save full batches": {
+			cfg:     Config{BatchSize: 4, BatchLinger: time.Hour, BufferSize: 10},
+			repo:    &repoMock{},
+			entries: []entry{newEntry("a", 2), newEntry("b", 2), newEntry("c", 3), newEntry("d", 1)},
+			batches: 2,
+			saved:   8,
+		},
+		"save lingering batch": {
+			cfg:     Config{BatchSize: 100, BatchLinger: 10 * time.Millisecond, BufferSize: 10},
+			repo:    &repoMock{},
+			entries: []entry{newEntry("a", 1), newEntry("b", 1)},
+			batches: 1,
+			saved:   2,
+		},
+		"save buffered messages on close": {
+			cfg:      Config{BatchSize: 100, BatchLinger: time.Hour, BufferSize: 10},
+			repo:     &repoMock{},
+			entries:  []entry{newEntry("a", 1), newEntry("b", 1)},
+			batches:  1,
+			saved:    2,
+			closeNow: true,
+		},
+		"save without buffering": {
+			cfg:     Config{BatchSize: 1},
+			repo:    &repoMock{},
+			entries: []entry{newEntry("a", 1), newEntry("b", 1)},
+			batches: 2,
+			saved:   2,
+		},
+		"save after retries": {
+			cfg:     Config{BatchSize: 2, BatchLinger: time.Hour, BufferSize: 10, Retries: 2, RetryBackoff: time.Millisecond},
+			repo:    &repoMock{failures: 2},
+			entries: []entry{newEntry("a", 1), newEntry("b", 1)},
+			batches: 1,
+			saved:   2,
+		},
+		"dead letter failing message": {
+			cfg:     Config{BatchSize: 3, BatchLinger: time.Hour, BufferSize: 10, Retries: 1, RetryBackoff: time.Millisecond},
+			repo:    &repoMock{bad: "b"},
+			entries: []entry{newEntry("a", 1), newEntry("b", 1), newEntry("c", 1)},
+			batches: 2,
+			saved:   2,
+			dead:    []string{"b"},
+		},
+		"dead letter after retries": {
+			cfg:     Config{BatchSize: 1, Retries: 1, RetryBackoff: time.Millisecond},
+			repo:    &repoMock{failures: 2},
+			entries: []entry{newEntry("a", 1)},
+			dead:    []string{"a"},
+		},
+	}
+
+	for desc, tc := range cases {
+		dl := &deadLetterMock{}
+		tc.cfg.DeadLetter = dl
+		b := newBatcher(tc.repo, tc.cfg, logger)
+		for _, e := range tc.entries {
+			b.add(e)
+		}
+		if !tc.closeNow {
+			assert.Eventually(t, func() bool {
+				_, saved := tc.repo.saved()
+				dl.mu.Lock()
+				defer dl.mu.Unlock()
+				return saved+len(dl.letters) >= tc.saved+len(tc.dead)
+			}, time.Second, time.Millisecond, fmt.Sprintf("%s: messages not saved", desc))
+		}
+		b.close()
+
+		batches, saved := tc.repo.saved()
+		assert.Equal(t, tc.batches, batches, fmt.Sprintf("%s: expected %d batches got %d", desc, tc.batches, batches))
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %d saved messages got %d", desc, tc.saved, saved))
+
+		dead := []string{}
+		for _, l := range dl.letters {
+			dead = append(dead, string(l.Message))
+			assert.Equal(t, "channels.1", l.Subject, fmt.Sprintf("%s: expected dead letter subject channels.1 got %s", desc, l.Subject))
+			assert.Equal(t, errSave.Error(), l.Error, fmt.Sprintf("%s: expected dead letter error %s got %s", desc, errSave, l.Error))
+		}
+		if tc.dead == nil {
+			tc.dead = []string{}
+		}
+		assert.Equal(t, tc.dead, dead, fmt.Sprintf("%s: expected dead letters %v got %v", desc, tc.dead, dead))
+	}
+}
+
+func TestBatcherClosed(t *testing.T) {
+	logger, _ := log.New(ioutil.Discard, log.Error.String())
+	repo := &repoMock{}
+	dl := &deadLetterMock{}
+	b := newBatcher(repo, Config{BatchSize: 1, DeadLetter: dl}, logger)
+	b.close()
+	b.add(newEntry("a", 1))
+
+	_, saved := repo.saved()
+	assert.Equal(t, 0, saved, fmt.Sprintf("expected no saved messages got %d", saved))
+	assert.Len(t, dl.letters, 1, "expected message added after close to be dead lettered")
+}
+
+func TestBatcherLatency(t *testing.T) {
+	logger, _ := log.New(ioutil.Discard, log.Error.String())
+	repo := &repoMock{bad: "b"}
+	latency := &histogramMock{}
+	b := newBatcher(repo, Config{BatchSize: 1, Latency: latency}, logger)
+
+	created := newEntry("a", 1)
+	created.created = time.Now().Add(-time.Second).UnixNano()
+	b.add(created)
+	b.add(newEntry("c", 1))
+	failed := newEntry("b", 1)
+	failed.created = time.Now().UnixNano()
+	b.add(failed)
+	b.close()
+
+	assert.Len(t, latency.values, 1, "expected latency of the saved message with creation time only")
+	if len(latency.values) == 1 {
+		assert.True(t, latency.values[0] >= 1, fmt.Sprintf("expected latency of at least 1s got %f", latency.values[0]))
+	}
+}
@@ -3,7 +3,12 @@
 
 package cassandra
 
-import "github.com/gocql/gocql"
+import (
+	"fmt"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/gocql/gocql"
+)
 
 const table = `CREATE TABLE IF NOT EXISTS messages (
         id uuid,
@@ -23,6 +28,28 @@ const table = `CREATE TABLE IF NOT EXISTS messages (
         PRIMARY KEY (channel, time, id)
 	) WITH CLUSTERING ORDER BY (time DESC)`
 
+const rollupTable = `CREATE TABLE IF NOT EXISTS messages_%s (
+        channel text,
+        time double,
+        subtopic text,
+        publisher text,
+        protocol text,
+        name text,
+        count double,
+        sum double,
+        min double,
+        max double,
+        last double,
+        PRIMARY KEY (channel, time, subtopic, publisher, protocol, name)
+	) WITH CLUSTERING ORDER BY (time DESC)`
+
+const policiesTable = `CREATE TABLE IF NOT EXISTS retention_policies (
+        channel text,
+        retention_days int,
+        downsampling list<text>,
+        PRIMARY KEY (channel)
+	)`
+
 // DBConfig contains Cassandra DB specific parameters.
 type DBConfig struct {
 	Hosts    []string
@@ -51,6 +78,14 @@ func Connect(cfg DBConfig) (*gocql.Session, error) {
 	if err := session.Query(table).Exec(); err != nil {
 		return nil, err
 	}
+	for name := range writers.Rollups {
+		if err := session.Query(fmt.Sprintf(rollupTable, name)).Exec(); err != nil {
+			return nil, err
+		}
+	}
+	if err := session.Query(policiesTable).Exec(); err != nil {
+		return nil, err
+	}
 
 	return session, nil
 }
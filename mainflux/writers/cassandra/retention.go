@@ -0,0 +1,194 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cassandra
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/gocql/gocql"
+)
+
+var (
+	_ writers.RetentionRepository = (*retentionRepository)(nil)
+	_ writers.PolicyRepository    = (*policyRepository)(nil)
+)
+
+type retentionRepository struct {
+	session *gocql.Session
+}
+
+// NewRetentionRepository instantiates Cassandra retention repository.
+func NewRetentionRepository(session *gocql.Session) writers.RetentionRepository {
+	return &retentionRepository{session}
+}
+
+func (rr *retentionRepository) Channels() ([]string, error) {
+	iter := rr.session.Query(`SELECT DISTINCT channel FROM messages`).Iter()
+
+	chanIDs := []string{}
+	var chanID string
+	for iter.Scan(&chanID) {
+		chanIDs = append(chanIDs, chanID)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return chanIDs, nil
+}
+
+// rollupKey is the key of the rollup buckets of a time.
+type rollupKey struct {
+	subtopic  string
+	publisher string
+	protocol  string
+	name      string
+}
+
+type rollupBucket struct {
+	count float64
+	sum   float64
+	min   float64
+	max   float64
+	last  float64
+}
+
+// Downsample buckets the values in memory, since Cassandra can't group by an
+// expression. The messages are read in time order, so only the buckets of a
+// time are kept at once.
+func (rr *retentionRepository) Downsample(chanID, rollup string, interval, before float64) error {
+	if _, ok := writers.Rollups[rollup]; !ok {
+		return writers.ErrMalformedEntity
+	}
+	table := fmt.Sprintf("messages_%s", rollup)
+
+	// The rollup continues from the end of its last bucket.
+	from := 0.0
+	var last float64
+	cql := fmt.Sprintf(`SELECT time FROM %s WHERE channel = ? LIMIT 1`, table)
+	switch err := rr.session.Query(cql, chanID).Scan(&last); err {
+	case nil:
+		from = last + interval
+	case gocql.ErrNotFound:
+	default:
+		return err
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s (channel, time, subtopic, publisher, protocol, name,
+			count, sum, min, max, last) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`, table)
+	flush := func(start float64, buckets map[rollupKey]*rollupBucket) error {
+		for k, b := range buckets {
+			err := rr.session.Query(insert, chanID, start, k.subtopic, k.publisher, k.protocol, k.name,
+				b.count, b.sum, b.min, b.max, b.last).Exec()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cql = `SELECT subtopic, publisher, protocol, name, value, time FROM messages
+			WHERE channel = ? AND time >= ? AND time < ? ORDER BY time ASC`
+	iter := rr.session.Query(cql, chanID, from, before).Iter()
+	scanner := iter.Scanner()
+
+	start := math.NaN()
+	buckets := make(map[rollupKey]*rollupBucket)
+	for scanner.Next() {
+		var k rollupKey
+		var value *float64
+		var t float64
+		if err := scanner.Scan(&k.subtopic, &k.publisher, &k.protocol, &k.name, &value, &t); err != nil {
+			iter.Close()
+			return err
+		}
+		if value == nil {
+			continue
+		}
+
+		if s := math.Floor(t/interval) * interval; s != start {
+			if err := flush(start, buckets); err != nil {
+				iter.Close()
+				return err
+			}
+			start = s
+			buckets = make(map[rollupKey]*rollupBucket)
+		}
+
+		v := *value
+		b, ok := buckets[k]
+		if !ok {
+			buckets[k] = &rollupBucket{count: 1, sum: v, min: v, max: v, last: v}
+			continue
+		}
+		b.count++
+		b.sum += v
+		b.min = math.Min(b.min, v)
+		b.max = math.Max(b.max, v)
+		b.last = v
+	}
+	if err := iter.Close(); err != nil {
+		return err
+	}
+
+	return flush(start, buckets)
+}
+
+func (rr *retentionRepository) Delete(chanID string, before float64) error {
+	return rr.session.Query(`DELETE FROM messages WHERE channel = ? AND time < ?`, chanID, before).Exec()
+}
+
+type policyRepository struct {
+	session *gocql.Session
+}
+
+// NewPolicyRepository instantiates Cassandra retention policy repository.
+func NewPolicyRepository(session *gocql.Session) writers.PolicyRepository {
+	return &policyRepository{session}
+}
+
+func (pr *policyRepository) Save(p writers.Policy) error {
+	cql := `INSERT INTO retention_policies (channel, retention_days, downsampling) VALUES (?, ?, ?)`
+	return pr.session.Query(cql, p.Channel, p.RetentionDays, p.Downsampling).Exec()
+}
+
+func (pr *policyRepository) Retrieve(chanID string) (writers.Policy, error) {
+	cql := `SELECT channel, retention_days, downsampling FROM retention_policies WHERE channel = ?`
+
+	var p writers.Policy
+	if err := pr.session.Query(cql, chanID).Scan(&p.Channel, &p.RetentionDays, &p.Downsampling); err != nil {
+		if err == gocql.ErrNotFound {
+			return writers.Policy{}, writers.ErrNotFound
+		}
+		return writers.Policy{}, err
+	}
+
+	return p, nil
+}
+
+func (pr *policyRepository) RetrieveAll() ([]writers.Policy, error) {
+	iter := pr.session.Query(`SELECT channel, retention_days, downsampling FROM retention_policies`).Iter()
+	scanner := iter.Scanner()
+
+	policies := []writers.Policy{}
+	for scanner.Next() {
+		var p writers.Policy
+		if err := scanner.Scan(&p.Channel, &p.RetentionDays, &p.Downsampling); err != nil {
+			iter.Close()
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	if err := iter.Close(); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+func (pr *policyRepository) Remove(chanID string) error {
+	return pr.session.Query(`DELETE FROM retention_policies WHERE channel = ?`, chanID).Exec()
+}
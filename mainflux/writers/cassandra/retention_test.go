@@ -0,0 +1,36 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cassandra_test
+
+import (
+	"fmt"
+	"testing"
+
+	reader "github.com/cloustone/pandas/mainflux/readers/cassandra"
+	"github.com/cloustone/pandas/mainflux/writers/cassandra"
+	"github.com/cloustone/pandas/mainflux/writers/writerstest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicies(t *testing.T) {
+	session, err := cassandra.Connect(cassandra.DBConfig{
+		Hosts:    []string{addr},
+		Keyspace: keyspace,
+	})
+	require.Nil(t, err, fmt.Sprintf("failed to connect to Cassandra: %s", err))
+	defer session.Close()
+
+	writerstest.RunPolicies(t, cassandra.NewPolicyRepository(session), "policies")
+}
+
+func TestRetention(t *testing.T) {
+	session, err := cassandra.Connect(cassandra.DBConfig{
+		Hosts:    []string{addr},
+		Keyspace: keyspace,
+	})
+	require.Nil(t, err, fmt.Sprintf("failed to connect to Cassandra: %s", err))
+	defer session.Close()
+
+	writerstest.RunRetention(t, cassandra.New(session), cassandra.NewRetentionRepository(session), reader.New(session), "retention", "1")
+}
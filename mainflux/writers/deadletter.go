@@ -0,0 +1,116 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/nats-io/nats.go"
+)
+
+var (
+	errConsumerClosed = errors.New("consumer closed")
+)
+
+// Letter is a received message which couldn't be saved.
+type Letter struct {
+	// Subject is the subject the message was received on.
+	Subject string `json:"subject"`
+
+	// Error is the error of the last save of the message.
+	Error string `json:"error"`
+
+	Time time.Time `json:"time"`
+
+	// Message is the marshaled broker message.
+	Message []byte `json:"message"`
+}
+
+// DeadLetter keeps the messages which couldn't be saved.
+type DeadLetter interface {
+	// Put keeps the letter.
+	Put(Letter) error
+
+	// Close releases the resources of the dead letter.
+	Close() error
+}
+
+var _ DeadLetter = (*natsDeadLetter)(nil)
+
+type natsDeadLetter struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNatsDeadLetter returns the dead letter publishing the letters to the
+// NATS subject. The letters are lost unless the subject is subscribed to.
+func NewNatsDeadLetter(url, subject string) (DeadLetter, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return natsDeadLetter{
+		conn:    conn,
+		subject: subject,
+	}, nil
+}
+
+func (dl natsDeadLetter) Put(l Letter) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return dl.conn.Publish(dl.subject, data)
+}
+
+// Close publishes the pending letters before closing the connection.
+func (dl natsDeadLetter) Close() error {
+	defer dl.conn.Close()
+	return dl.conn.Flush()
+}
+
+var _ DeadLetter = (*fileDeadLetter)(nil)
+
+type fileDeadLetter struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileDeadLetter returns the dead letter appending the letters to the
+// file, a JSON letter per line.
+func NewFileDeadLetter(path string) (DeadLetter, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileDeadLetter{file: file}, nil
+}
+
+func (dl *fileDeadLetter) Put(l Letter) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	if _, err := dl.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return dl.file.Sync()
+}
+
+func (dl *fileDeadLetter) Close() error {
+	dl.mu.Lock()
+	defer dl.mu.Unlock()
+
+	return dl.file.Close()
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import "sync"
+
+// dedup remembers the IDs of the last size received messages, so the
+// messages redelivered by NATS or published twice by the adapters are
+// saved only once.
+type dedup struct {
+	mu   sync.Mutex
+	ids  map[string]struct{}
+	ring []string
+	next int
+}
+
+func newDedup(size int) *dedup {
+	if size < 1 {
+		return nil
+	}
+
+	return &dedup{
+		ids:  make(map[string]struct{}, size),
+		ring: make([]string, size),
+	}
+}
+
+// seen reports whether the message ID was already received, remembering it
+// otherwise. Messages without an ID are never duplicates.
+func (d *dedup) seen(id string) bool {
+	if d == nil || id == "" {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.ids[id]; ok {
+		return true
+	}
+
+	if old := d.ring[d.next]; old != "" {
+		delete(d.ids, old)
+	}
+	d.ring[d.next] = id
+	d.ids[id] = struct{}{}
+	d.next = (d.next + 1) % len(d.ring)
+
+	return false
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedup(t *testing.T) {
+	cases := []struct {
+		desc string
+		id   string
+		seen bool
+	}{
+		{desc: "receive new message", id: "1", seen: false},
+		{desc: "receive duplicate message", id: "1", seen: true},
+		{desc: "receive message without ID", id: "", seen: false},
+		{desc: "receive message without ID again", id: "", seen: false},
+		{desc: "receive second message", id: "2", seen: false},
+		{desc: "receive third message evicting the first", id: "3", seen: false},
+		{desc: "receive evicted message", id: "1", seen: false},
+		{desc: "receive remembered message", id: "3", seen: true},
+	}
+
+	d := newDedup(2)
+	for _, tc := range cases {
+		seen := d.seen(tc.id)
+		assert.Equal(t, tc.seen, seen, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.seen, seen))
+	}
+}
+
+func TestDedupDisabled(t *testing.T) {
+	d := newDedup(0)
+	d.seen("1")
+	seen := d.seen("1")
+	assert.False(t, seen, "expected disabled deduplication not to drop messages")
+}
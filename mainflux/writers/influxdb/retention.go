@@ -0,0 +1,220 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	influxdata "github.com/influxdata/influxdb/client/v2"
+	"github.com/influxdata/influxdb/models"
+)
+
+const policiesPointName = "retention_policies"
+
+var (
+	_ writers.RetentionRepository = (*retentionRepo)(nil)
+	_ writers.PolicyRepository    = (*policyRepo)(nil)
+)
+
+type retentionRepo struct {
+	client   influxdata.Client
+	database string
+}
+
+// NewRetentionRepository returns new InfluxDB retention repository.
+func NewRetentionRepository(client influxdata.Client, database string) writers.RetentionRepository {
+	return &retentionRepo{
+		client:   client,
+		database: database,
+	}
+}
+
+func (repo *retentionRepo) Channels() ([]string, error) {
+	cmd := fmt.Sprintf(`SHOW TAG VALUES FROM %s WITH KEY = "channel"`, pointName)
+	rows, err := query(repo.client, repo.database, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	chanIDs := []string{}
+	for _, row := range rows {
+		for _, v := range row.Values {
+			// The rows consist of the tag key and the tag value.
+			if len(v) < 2 {
+				continue
+			}
+			if chanID, ok := v[1].(string); ok {
+				chanIDs = append(chanIDs, chanID)
+			}
+		}
+	}
+	return chanIDs, nil
+}
+
+// Downsample rolls up the values by a continuous query run once, which keeps
+// the channel, subtopic, publisher and name tags of the messages.
+func (repo *retentionRepo) Downsample(chanID, rollup string, interval, before float64) error {
+	if _, ok := writers.Rollups[rollup]; !ok {
+		return writers.ErrMalformedEntity
+	}
+	measurement := fmt.Sprintf("%s_%s", pointName, rollup)
+
+	// The rollup continues from the end of its last bucket.
+	cmd := fmt.Sprintf(`SELECT LAST("count") FROM %s WHERE %s`, measurement, channelCondition(chanID))
+	rows, err := query(repo.client, repo.database, cmd)
+	if err != nil {
+		return err
+	}
+	from := int64(0)
+	if len(rows) > 0 && len(rows[0].Values) > 0 && len(rows[0].Values[0]) > 0 {
+		last, err := parseTime(rows[0].Values[0][0])
+		if err != nil {
+			return err
+		}
+		from = last + int64(interval*1e9)
+	}
+
+	cmd = fmt.Sprintf(`SELECT COUNT(value) AS "count", SUM(value) AS "sum", MIN(value) AS "min",
+		MAX(value) AS "max", LAST(value) AS "last" INTO %s FROM %s
+		WHERE %s AND time >= %d AND time < %d GROUP BY time(%dns), * fill(none)`,
+		measurement, pointName, channelCondition(chanID), from, int64(before*1e9), int64(interval*1e9))
+	_, err = query(repo.client, repo.database, cmd)
+	return err
+}
+
+func (repo *retentionRepo) Delete(chanID string, before float64) error {
+	cmd := fmt.Sprintf(`DELETE FROM %s WHERE %s AND time < %d`, pointName, channelCondition(chanID), int64(before*1e9))
+	_, err := query(repo.client, repo.database, cmd)
+	return err
+}
+
+// policyRepo keeps the policies as the points of their channel tag, the
+// newest of which is the policy of the channel.
+type policyRepo struct {
+	client   influxdata.Client
+	database string
+}
+
+// NewPolicyRepository returns new InfluxDB retention policy repository.
+func NewPolicyRepository(client influxdata.Client, database string) writers.PolicyRepository {
+	return &policyRepo{
+		client:   client,
+		database: database,
+	}
+}
+
+func (repo *policyRepo) Save(p writers.Policy) error {
+	pts, err := influxdata.NewBatchPoints(influxdata.BatchPointsConfig{Database: repo.database})
+	if err != nil {
+		return err
+	}
+
+	tgs := tags{"channel": p.Channel}
+	flds := fields{
+		"retention_days": p.RetentionDays,
+		"downsampling":   strings.Join(p.Downsampling, ","),
+	}
+	pt, err := influxdata.NewPoint(policiesPointName, tgs, flds, time.Now())
+	if err != nil {
+		return err
+	}
+	pts.AddPoint(pt)
+
+	return repo.client.Write(pts)
+}
+
+func (repo *policyRepo) Retrieve(chanID string) (writers.Policy, error) {
+	cmd := fmt.Sprintf(`SELECT "retention_days", "downsampling" FROM %s WHERE %s GROUP BY "channel" ORDER BY time DESC LIMIT 1`,
+		policiesPointName, channelCondition(chanID))
+	policies, err := repo.retrieve(cmd)
+	if err != nil {
+		return writers.Policy{}, err
+	}
+	if len(policies) == 0 {
+		return writers.Policy{}, writers.ErrNotFound
+	}
+
+	return policies[0], nil
+}
+
+func (repo *policyRepo) RetrieveAll() ([]writers.Policy, error) {
+	cmd := fmt.Sprintf(`SELECT "retention_days", "downsampling" FROM %s GROUP BY "channel" ORDER BY time DESC LIMIT 1`, policiesPointName)
+	return repo.retrieve(cmd)
+}
+
+func (repo *policyRepo) retrieve(cmd string) ([]writers.Policy, error) {
+	rows, err := query(repo.client, repo.database, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := []writers.Policy{}
+	for _, row := range rows {
+		if len(row.Values) < 1 || len(row.Values[0]) < 3 {
+			continue
+		}
+
+		p := writers.Policy{Channel: row.Tags["channel"]}
+		if num, ok := row.Values[0][1].(json.Number); ok {
+			days, err := num.Int64()
+			if err != nil {
+				return nil, err
+			}
+			p.RetentionDays = int(days)
+		}
+		if ds, ok := row.Values[0][2].(string); ok && ds != "" {
+			p.Downsampling = strings.Split(ds, ",")
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func (repo *policyRepo) Remove(chanID string) error {
+	cmd := fmt.Sprintf(`DROP SERIES FROM %s WHERE %s`, policiesPointName, channelCondition(chanID))
+	_, err := query(repo.client, repo.database, cmd)
+	return err
+}
+
+func query(client influxdata.Client, database, cmd string) ([]models.Row, error) {
+	q := influxdata.Query{
+		Command:  cmd,
+		Database: database,
+	}
+
+	resp, err := client.Query(q)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error() != nil {
+		return nil, resp.Error()
+	}
+	if len(resp.Results) < 1 {
+		return nil, nil
+	}
+
+	return resp.Results[0].Series, nil
+}
+
+func channelCondition(chanID string) string {
+	return fmt.Sprintf(`channel='%s'`, strings.Replace(chanID, "'", "\\'", -1))
+}
+
+// parseTime returns the time of the RFC3339 value in nanoseconds.
+func parseTime(value interface{}) (int64, error) {
+	ts, ok := value.(string)
+	if !ok {
+		return 0, writers.ErrMalformedEntity
+	}
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixNano(), nil
+}
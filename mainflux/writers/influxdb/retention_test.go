@@ -0,0 +1,21 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package influxdb_test
+
+import (
+	"testing"
+
+	reader "github.com/cloustone/pandas/mainflux/readers/influxdb"
+	writer "github.com/cloustone/pandas/mainflux/writers/influxdb"
+	"github.com/cloustone/pandas/mainflux/writers/writerstest"
+)
+
+func TestPolicies(t *testing.T) {
+	writerstest.RunPolicies(t, writer.NewPolicyRepository(client, testDB), "policies")
+}
+
+func TestRetention(t *testing.T) {
+	repo := writer.New(client, testDB)
+	writerstest.RunRetention(t, repo, writer.NewRetentionRepository(client, testDB), reader.New(client, testDB), "retention", "1")
+}
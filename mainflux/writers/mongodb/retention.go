@@ -0,0 +1,230 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	policiesCollection = "retention_policies"
+
+	// rollupBatchSize is the number of the rollup buckets written at once.
+	rollupBatchSize = 1000
+)
+
+var (
+	_ writers.RetentionRepository = (*retentionRepo)(nil)
+	_ writers.PolicyRepository    = (*policyRepo)(nil)
+)
+
+// rollup is a bucket of the values of the messages of the rollup keys.
+type rollup struct {
+	Channel   string  `bson:"channel"`
+	Subtopic  string  `bson:"subtopic"`
+	Publisher string  `bson:"publisher"`
+	Protocol  string  `bson:"protocol"`
+	Name      string  `bson:"name"`
+	Time      float64 `bson:"time"`
+	Count     float64 `bson:"count"`
+	Sum       float64 `bson:"sum"`
+	Min       float64 `bson:"min"`
+	Max       float64 `bson:"max"`
+	Last      float64 `bson:"last"`
+}
+
+type retentionRepo struct {
+	db *mongo.Database
+}
+
+// NewRetentionRepository returns new MongoDB retention repository.
+func NewRetentionRepository(db *mongo.Database) writers.RetentionRepository {
+	return &retentionRepo{db: db}
+}
+
+func (repo retentionRepo) Channels() ([]string, error) {
+	vals, err := repo.db.Collection(collectionName).Distinct(context.Background(), "channel", bson.D{})
+	if err != nil {
+		return nil, err
+	}
+
+	chanIDs := []string{}
+	for _, val := range vals {
+		if chanID, ok := val.(string); ok {
+			chanIDs = append(chanIDs, chanID)
+		}
+	}
+	return chanIDs, nil
+}
+
+func (repo retentionRepo) Downsample(chanID, name string, interval, before float64) error {
+	if _, ok := writers.Rollups[name]; !ok {
+		return writers.ErrMalformedEntity
+	}
+	ctx := context.Background()
+	rollups := repo.db.Collection(fmt.Sprintf("%s_%s", collectionName, name))
+
+	// The rollup continues from the end of its last bucket.
+	from := 0.0
+	var last rollup
+	err := rollups.FindOne(ctx, bson.M{"channel": chanID}, options.FindOne().SetSort(bson.M{"time": -1})).Decode(&last)
+	switch err {
+	case nil:
+		from = last.Time + interval
+	case mongo.ErrNoDocuments:
+	default:
+		return err
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"channel": chanID,
+			"value":   bson.M{"$exists": true},
+			"time":    bson.M{"$gte": from, "$lt": before},
+		}},
+		// Sorted by time so that the last value of a bucket is the newest
+		{"$sort": bson.M{"time": 1}},
+		{"$group": bson.M{
+			"_id": bson.M{
+				"subtopic":  bson.M{"$ifNull": bson.A{"$subtopic", ""}},
+				"publisher": bson.M{"$ifNull": bson.A{"$publisher", ""}},
+				"protocol":  bson.M{"$ifNull": bson.A{"$protocol", ""}},
+				"name":      bson.M{"$ifNull": bson.A{"$name", ""}},
+				"time":      bson.M{"$subtract": bson.A{"$time", bson.M{"$mod": bson.A{"$time", interval}}}},
+			},
+			"count": bson.M{"$sum": 1},
+			"sum":   bson.M{"$sum": "$value"},
+			"min":   bson.M{"$min": "$value"},
+			"max":   bson.M{"$max": "$value"},
+			"last":  bson.M{"$last": "$value"},
+		}},
+	}
+	cursor, err := repo.db.Collection(collectionName).Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	models := []mongo.WriteModel{}
+	for cursor.Next(ctx) {
+		var b struct {
+			ID    rollup `bson:"_id"`
+			Count float64
+			Sum   float64
+			Min   float64
+			Max   float64
+			Last  float64
+		}
+		if err := cursor.Decode(&b); err != nil {
+			return err
+		}
+
+		r := b.ID
+		r.Channel = chanID
+		r.Count, r.Sum, r.Min, r.Max, r.Last = b.Count, b.Sum, b.Min, b.Max, b.Last
+		filter := bson.M{
+			"channel":   r.Channel,
+			"subtopic":  r.Subtopic,
+			"publisher": r.Publisher,
+			"protocol":  r.Protocol,
+			"name":      r.Name,
+			"time":      r.Time,
+		}
+		models = append(models, mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(r).SetUpsert(true))
+
+		if len(models) == rollupBatchSize {
+			if _, err := rollups.BulkWrite(ctx, models); err != nil {
+				return err
+			}
+			models = models[:0]
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(models) > 0 {
+		if _, err := rollups.BulkWrite(ctx, models); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (repo retentionRepo) Delete(chanID string, before float64) error {
+	filter := bson.M{
+		"channel": chanID,
+		"time":    bson.M{"$lt": before},
+	}
+	_, err := repo.db.Collection(collectionName).DeleteMany(context.Background(), filter)
+	return err
+}
+
+// policy is the MongoDB representation of the retention policy.
+type policy struct {
+	Channel       string   `bson:"_id"`
+	RetentionDays int      `bson:"retention_days"`
+	Downsampling  []string `bson:"downsampling,omitempty"`
+}
+
+type policyRepo struct {
+	db *mongo.Database
+}
+
+// NewPolicyRepository returns new MongoDB retention policy repository.
+func NewPolicyRepository(db *mongo.Database) writers.PolicyRepository {
+	return &policyRepo{db: db}
+}
+
+func (repo policyRepo) Save(p writers.Policy) error {
+	coll := repo.db.Collection(policiesCollection)
+	_, err := coll.ReplaceOne(context.Background(), bson.M{"_id": p.Channel}, policy(p), options.Replace().SetUpsert(true))
+	return err
+}
+
+func (repo policyRepo) Retrieve(chanID string) (writers.Policy, error) {
+	coll := repo.db.Collection(policiesCollection)
+
+	var p policy
+	if err := coll.FindOne(context.Background(), bson.M{"_id": chanID}).Decode(&p); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return writers.Policy{}, writers.ErrNotFound
+		}
+		return writers.Policy{}, err
+	}
+
+	return writers.Policy(p), nil
+}
+
+func (repo policyRepo) RetrieveAll() ([]writers.Policy, error) {
+	coll := repo.db.Collection(policiesCollection)
+	cursor, err := coll.Find(context.Background(), bson.D{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	policies := []writers.Policy{}
+	for cursor.Next(context.Background()) {
+		var p policy
+		if err := cursor.Decode(&p); err != nil {
+			return nil, err
+		}
+		policies = append(policies, writers.Policy(p))
+	}
+
+	return policies, cursor.Err()
+}
+
+func (repo policyRepo) Remove(chanID string) error {
+	coll := repo.db.Collection(policiesCollection)
+	_, err := coll.DeleteOne(context.Background(), bson.M{"_id": chanID})
+	return err
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mongodb_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	reader "github.com/cloustone/pandas/mainflux/readers/mongodb"
+	"github.com/cloustone/pandas/mainflux/writers/mongodb"
+	"github.com/cloustone/pandas/mainflux/writers/writerstest"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestPolicies(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+
+	db := client.Database(testDB)
+	writerstest.RunPolicies(t, mongodb.NewPolicyRepository(db), "policies")
+}
+
+func TestRetention(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
+	require.Nil(t, err, fmt.Sprintf("Creating new MongoDB client expected to succeed: %s.\n", err))
+
+	db := client.Database(testDB)
+	writerstest.RunRetention(t, mongodb.New(db), mongodb.NewRetentionRepository(db), reader.New(db), "retention", "1")
+}
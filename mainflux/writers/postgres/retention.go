@@ -0,0 +1,149 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	_ writers.RetentionRepository = (*retentionRepo)(nil)
+	_ writers.PolicyRepository    = (*policyRepo)(nil)
+)
+
+type retentionRepo struct {
+	db *sqlx.DB
+}
+
+// NewRetentionRepository returns new PostgreSQL retention repository.
+func NewRetentionRepository(db *sqlx.DB) writers.RetentionRepository {
+	return &retentionRepo{db: db}
+}
+
+func (rr retentionRepo) Channels() ([]string, error) {
+	chanIDs := []string{}
+	if err := rr.db.Select(&chanIDs, `SELECT DISTINCT channel FROM messages WHERE channel IS NOT NULL`); err != nil {
+		return nil, err
+	}
+	return chanIDs, nil
+}
+
+func (rr retentionRepo) Downsample(chanID, rollup string, interval, before float64) error {
+	if _, ok := writers.Rollups[rollup]; !ok {
+		return writers.ErrMalformedEntity
+	}
+
+	// The rollup continues from the end of its last bucket, the last value
+	// of a bucket is the one of its newest message.
+	table := fmt.Sprintf("messages_%s", rollup)
+	q := fmt.Sprintf(`INSERT INTO %s (channel, subtopic, publisher, protocol, name, time, count, sum, min, max, last)
+    SELECT channel, COALESCE(subtopic, ''), COALESCE(CAST(publisher AS TEXT), ''),
+        COALESCE(protocol, ''), COALESCE(name, ''), FLOOR(time / $2) * $2,
+        COUNT(value), SUM(value), MIN(value), MAX(value),
+        (ARRAY_AGG(value ORDER BY time DESC))[1]
+    FROM messages
+    WHERE channel = $1 AND value IS NOT NULL AND time < $3
+        AND time >= COALESCE((SELECT MAX(time) + $2 FROM %s WHERE channel = $1), 0)
+    GROUP BY 1, 2, 3, 4, 5, 6
+    ON CONFLICT (channel, time, subtopic, publisher, protocol, name) DO UPDATE SET
+        count = EXCLUDED.count, sum = EXCLUDED.sum, min = EXCLUDED.min,
+        max = EXCLUDED.max, last = EXCLUDED.last;`, table, table)
+
+	_, err := rr.db.Exec(q, chanID, interval, before)
+	return err
+}
+
+func (rr retentionRepo) Delete(chanID string, before float64) error {
+	_, err := rr.db.Exec(`DELETE FROM messages WHERE channel = $1 AND time < $2`, chanID, before)
+	return err
+}
+
+type policyRepo struct {
+	db *sqlx.DB
+}
+
+// NewPolicyRepository returns new PostgreSQL retention policy repository.
+func NewPolicyRepository(db *sqlx.DB) writers.PolicyRepository {
+	return &policyRepo{db: db}
+}
+
+func (pr policyRepo) Save(p writers.Policy) error {
+	q := `INSERT INTO retention_policies (channel, retention_days, downsampling)
+    VALUES (:channel, :retention_days, :downsampling)
+    ON CONFLICT (channel) DO UPDATE SET
+        retention_days = EXCLUDED.retention_days, downsampling = EXCLUDED.downsampling;`
+
+	_, err := pr.db.NamedExec(q, toDBPolicy(p))
+	return err
+}
+
+func (pr policyRepo) Retrieve(chanID string) (writers.Policy, error) {
+	q := `SELECT channel, retention_days, downsampling FROM retention_policies WHERE channel = $1`
+
+	var dbp dbPolicy
+	if err := pr.db.QueryRowx(q, chanID).StructScan(&dbp); err != nil {
+		if err == sql.ErrNoRows {
+			return writers.Policy{}, writers.ErrNotFound
+		}
+		return writers.Policy{}, err
+	}
+
+	return toPolicy(dbp), nil
+}
+
+func (pr policyRepo) RetrieveAll() ([]writers.Policy, error) {
+	q := `SELECT channel, retention_days, downsampling FROM retention_policies ORDER BY channel`
+
+	rows, err := pr.db.Queryx(q)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []writers.Policy{}
+	for rows.Next() {
+		var dbp dbPolicy
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, err
+		}
+		policies = append(policies, toPolicy(dbp))
+	}
+
+	return policies, rows.Err()
+}
+
+func (pr policyRepo) Remove(chanID string) error {
+	_, err := pr.db.Exec(`DELETE FROM retention_policies WHERE channel = $1`, chanID)
+	return err
+}
+
+type dbPolicy struct {
+	Channel       string         `db:"channel"`
+	RetentionDays int            `db:"retention_days"`
+	Downsampling  pq.StringArray `db:"downsampling"`
+}
+
+func toDBPolicy(p writers.Policy) dbPolicy {
+	return dbPolicy{
+		Channel:       p.Channel,
+		RetentionDays: p.RetentionDays,
+		Downsampling:  pq.StringArray(p.Downsampling),
+	}
+}
+
+func toPolicy(dbp dbPolicy) writers.Policy {
+	p := writers.Policy{
+		Channel:       dbp.Channel,
+		RetentionDays: dbp.RetentionDays,
+	}
+	if len(dbp.Downsampling) > 0 {
+		p.Downsampling = []string(dbp.Downsampling)
+	}
+	return p
+}
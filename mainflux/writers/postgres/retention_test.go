@@ -0,0 +1,31 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	reader "github.com/cloustone/pandas/mainflux/readers/postgres"
+	"github.com/cloustone/pandas/mainflux/writers/postgres"
+	"github.com/cloustone/pandas/mainflux/writers/writerstest"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicies(t *testing.T) {
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	writerstest.RunPolicies(t, postgres.NewPolicyRepository(db), chid.String())
+}
+
+func TestRetention(t *testing.T) {
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	pubid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	writerstest.RunRetention(t, postgres.New(db), postgres.NewRetentionRepository(db), reader.New(db), chid.String(), pubid.String())
+}
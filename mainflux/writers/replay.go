@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/nats-io/nats.go"
+)
+
+var errInvalidLetter = errors.New("invalid dead letter")
+
+// Publisher publishes the data to the NATS subject.
+type Publisher interface {
+	Publish(string, []byte) error
+}
+
+var _ Publisher = (*nats.Conn)(nil)
+
+// ReplaySubject returns the subject the writer receives the replayed letters
+// on.
+func ReplaySubject(writer string) string {
+	return fmt.Sprintf("writers.%s.replay", writer)
+}
+
+// Replay resubmits the letters read from the reader, a JSON letter per line,
+// to the writer. It returns the number of the resubmitted letters.
+func Replay(pub Publisher, writer string, r io.Reader) (int, error) {
+	subject := ReplaySubject(writer)
+
+	count := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var l Letter
+		if err := json.Unmarshal(line, &l); err != nil {
+			return count, errors.Wrap(errInvalidLetter, err)
+		}
+		if err := pub.Publish(subject, line); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, scanner.Err()
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type publisherMock struct {
+	subjects []string
+	letters  []writers.Letter
+}
+
+func (pub *publisherMock) Publish(subject string, data []byte) error {
+	var l writers.Letter
+	if err := json.Unmarshal(data, &l); err != nil {
+		return err
+	}
+	pub.subjects = append(pub.subjects, subject)
+	pub.letters = append(pub.letters, l)
+	return nil
+}
+
+func TestReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writers")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dead-letters")
+	dl, err := writers.NewFileDeadLetter(path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	letters := []writers.Letter{}
+	for i := 0; i < 3; i++ {
+		l := writers.Letter{
+			Subject: fmt.Sprintf("channels.%d", i),
+			Error:   "save failed",
+			Time:    time.Unix(int64(i), 0).UTC(),
+			Message: []byte{byte(i), 0, 1},
+		}
+		letters = append(letters, l)
+		err := dl.Put(l)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	require.Nil(t, dl.Close(), "unexpected error closing dead letter")
+
+	f, err := os.Open(path)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer f.Close()
+
+	pub := &publisherMock{}
+	n, err := writers.Replay(pub, "postgres-writer", f)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s", err))
+	assert.Equal(t, len(letters), n, fmt.Sprintf("expected %d replayed letters got %d", len(letters), n))
+	assert.Equal(t, letters, pub.letters, fmt.Sprintf("expected letters %v got %v", letters, pub.letters))
+	for _, subject := range pub.subjects {
+		assert.Equal(t, "writers.postgres-writer.replay", subject, fmt.Sprintf("expected replay subject got %s", subject))
+	}
+
+	pub = &publisherMock{}
+	n, err = writers.Replay(pub, "postgres-writer", strings.NewReader("{\"subject\":\"channels.1\"}\n\nnot a letter\n"))
+	assert.True(t, errors.Contains(err, errors.New("invalid dead letter")), fmt.Sprintf("expected invalid dead letter error got %s", err))
+	assert.Equal(t, 1, n, fmt.Sprintf("expected 1 replayed letter got %d", n))
+}
@@ -0,0 +1,210 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/pkg/logger"
+)
+
+// DefaultPolicy is the channel of the policy of the channels without a
+// policy of their own.
+const DefaultPolicy = "default"
+
+const day = 24 * time.Hour
+
+// Rollups are the intervals, in seconds, the raw messages are downsampled
+// into. The readers aggregate the values from the rollups transparently.
+var Rollups = map[string]float64{
+	"hour": 3600,
+	"day":  86400,
+}
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	errEnforce = errors.New("failed to enforce retention policy")
+)
+
+// Policy is the retention policy of the messages of a channel.
+type Policy struct {
+	// Channel is the channel of the policy, or DefaultPolicy.
+	Channel string `json:"channel"`
+
+	// RetentionDays is the number of days the raw messages are kept for,
+	// they are kept forever if it's 0.
+	RetentionDays int `json:"retention_days"`
+
+	// Downsampling lists the rollups the raw messages are downsampled into
+	// before they are deleted.
+	Downsampling []string `json:"downsampling,omitempty"`
+}
+
+// Validate returns an error if the policy is malformed.
+func (p Policy) Validate() error {
+	if p.Channel == "" || p.RetentionDays < 0 {
+		return ErrMalformedEntity
+	}
+	for _, r := range p.Downsampling {
+		if _, ok := Rollups[r]; !ok {
+			return ErrMalformedEntity
+		}
+	}
+	return nil
+}
+
+// PolicyRepository specifies retention policy persistence API.
+type PolicyRepository interface {
+	// Save saves the policy, replacing the existing policy of its channel.
+	Save(Policy) error
+
+	// Retrieve retrieves the policy of the channel.
+	Retrieve(string) (Policy, error)
+
+	// RetrieveAll retrieves all the policies.
+	RetrieveAll() ([]Policy, error)
+
+	// Remove removes the policy of the channel.
+	Remove(string) error
+}
+
+// RetentionRepository specifies the retention API of the stored messages.
+type RetentionRepository interface {
+	// Channels returns the channels of the stored messages.
+	Channels() ([]string, error)
+
+	// Downsample rolls up the numeric values of the raw messages of the
+	// channel older than the time into the buckets of the rollup of the
+	// interval, starting from the end of its last bucket.
+	Downsample(chanID, rollup string, interval, before float64) error
+
+	// Delete removes the raw messages of the channel older than the time.
+	Delete(chanID string, before float64) error
+}
+
+// RetentionService specifies an API to manage and enforce the retention
+// policies.
+type RetentionService interface {
+	// SavePolicy saves the policy.
+	SavePolicy(Policy) error
+
+	// ViewPolicy retrieves the policy of the channel.
+	ViewPolicy(string) (Policy, error)
+
+	// ListPolicies retrieves all the policies.
+	ListPolicies() ([]Policy, error)
+
+	// RemovePolicy removes the policy of the channel.
+	RemovePolicy(string) error
+
+	// Enforce downsamples and deletes the raw messages of every channel as
+	// its policy, or the default one, specifies.
+	Enforce() error
+}
+
+var _ RetentionService = (*retentionService)(nil)
+
+type retentionService struct {
+	policies PolicyRepository
+	messages RetentionRepository
+}
+
+// NewRetentionService instantiates the retention service implementation.
+func NewRetentionService(policies PolicyRepository, messages RetentionRepository) RetentionService {
+	return &retentionService{
+		policies: policies,
+		messages: messages,
+	}
+}
+
+func (rs *retentionService) SavePolicy(p Policy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	return rs.policies.Save(p)
+}
+
+func (rs *retentionService) ViewPolicy(chanID string) (Policy, error) {
+	return rs.policies.Retrieve(chanID)
+}
+
+func (rs *retentionService) ListPolicies() ([]Policy, error) {
+	return rs.policies.RetrieveAll()
+}
+
+func (rs *retentionService) RemovePolicy(chanID string) error {
+	return rs.policies.Remove(chanID)
+}
+
+func (rs *retentionService) Enforce() error {
+	policies, err := rs.policies.RetrieveAll()
+	if err != nil {
+		return err
+	}
+	byChannel := make(map[string]Policy)
+	for _, p := range policies {
+		byChannel[p.Channel] = p
+	}
+
+	chanIDs, err := rs.messages.Channels()
+	if err != nil {
+		return err
+	}
+
+	// The rest of the channels are enforced if one of them fails, the
+	// first error is returned.
+	var ret error
+	now := time.Now()
+	for _, chanID := range chanIDs {
+		p, ok := byChannel[chanID]
+		if !ok {
+			p = byChannel[DefaultPolicy]
+		}
+		if err := rs.enforce(chanID, p, now); err != nil && ret == nil {
+			ret = errors.Wrap(errEnforce, errors.New(fmt.Sprintf("channel %s: %s", chanID, err)))
+		}
+	}
+
+	return ret
+}
+
+func (rs *retentionService) enforce(chanID string, p Policy, now time.Time) error {
+	// Only the complete buckets are downsampled, so the raw messages are
+	// never deleted before they're rolled up as long as they're kept for
+	// longer than a bucket.
+	sec := float64(now.UnixNano()) / 1e9
+	for _, r := range p.Downsampling {
+		interval := Rollups[r]
+		before := math.Floor(sec/interval) * interval
+		if err := rs.messages.Downsample(chanID, r, interval, before); err != nil {
+			return err
+		}
+	}
+
+	if p.RetentionDays == 0 {
+		return nil
+	}
+	before := now.Add(-time.Duration(p.RetentionDays) * day)
+	return rs.messages.Delete(chanID, float64(before.UnixNano())/1e9)
+}
+
+// EnforcePolicies enforces the retention policies every interval.
+func EnforcePolicies(svc RetentionService, interval time.Duration, logger logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := svc.Enforce(); err != nil {
+			logger.Error(fmt.Sprintf("Failed to enforce retention policies: %s", err))
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers_test
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const failingChannel = "failing"
+
+var errDownsample = errors.New("downsample failed")
+
+type policyRepoMock struct {
+	mu       sync.Mutex
+	policies map[string]writers.Policy
+}
+
+func (repo *policyRepoMock) Save(p writers.Policy) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.policies[p.Channel] = p
+	return nil
+}
+
+func (repo *policyRepoMock) Retrieve(chanID string) (writers.Policy, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	p, ok := repo.policies[chanID]
+	if !ok {
+		return writers.Policy{}, writers.ErrNotFound
+	}
+	return p, nil
+}
+
+func (repo *policyRepoMock) RetrieveAll() ([]writers.Policy, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	policies := []writers.Policy{}
+	for _, p := range repo.policies {
+		policies = append(policies, p)
+	}
+	return policies, nil
+}
+
+func (repo *policyRepoMock) Remove(chanID string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	delete(repo.policies, chanID)
+	return nil
+}
+
+// retentionRepoMock records the calls of each channel in order.
+type retentionRepoMock struct {
+	channels []string
+	calls    map[string][]string
+	before   map[string]float64
+}
+
+func (repo *retentionRepoMock) Channels() ([]string, error) {
+	return repo.channels, nil
+}
+
+func (repo *retentionRepoMock) Downsample(chanID, rollup string, interval, before float64) error {
+	if chanID == failingChannel {
+		return errDownsample
+	}
+	repo.calls[chanID] = append(repo.calls[chanID], "downsample "+rollup)
+	repo.before[chanID+" "+rollup] = before
+	return nil
+}
+
+func (repo *retentionRepoMock) Delete(chanID string, before float64) error {
+	repo.calls[chanID] = append(repo.calls[chanID], "delete")
+	repo.before[chanID] = before
+	return nil
+}
+
+func newRetentionService(channels ...string) (writers.RetentionService, *retentionRepoMock) {
+	messages := &retentionRepoMock{
+		channels: channels,
+		calls:    make(map[string][]string),
+		before:   make(map[string]float64),
+	}
+	policies := &policyRepoMock{policies: make(map[string]writers.Policy)}
+	return writers.NewRetentionService(policies, messages), messages
+}
+
+func TestSavePolicy(t *testing.T) {
+	svc, _ := newRetentionService()
+
+	cases := map[string]struct {
+		policy writers.Policy
+		err    error
+	}{
+		"save valid policy": {
+			policy: writers.Policy{Channel: "1", RetentionDays: 7, Downsampling: []string{"hour", "day"}},
+			err:    nil,
+		},
+		"save default policy": {
+			policy: writers.Policy{Channel: writers.DefaultPolicy, RetentionDays: 30},
+			err:    nil,
+		},
+		"save policy without channel": {
+			policy: writers.Policy{RetentionDays: 7},
+			err:    writers.ErrMalformedEntity,
+		},
+		"save policy with negative retention": {
+			policy: writers.Policy{Channel: "1", RetentionDays: -1},
+			err:    writers.ErrMalformedEntity,
+		},
+		"save policy with unknown rollup": {
+			policy: writers.Policy{Channel: "1", RetentionDays: 7, Downsampling: []string{"minute"}},
+			err:    writers.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.SavePolicy(tc.policy)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
+
+func TestViewPolicy(t *testing.T) {
+	svc, _ := newRetentionService()
+	p := writers.Policy{Channel: "1", RetentionDays: 7}
+	err := svc.SavePolicy(p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		chanID string
+		policy writers.Policy
+		err    error
+	}{
+		"view existing policy": {
+			chanID: p.Channel,
+			policy: p,
+			err:    nil,
+		},
+		"view non-existent policy": {
+			chanID: "2",
+			policy: writers.Policy{},
+			err:    writers.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		policy, err := svc.ViewPolicy(tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		assert.Equal(t, tc.policy, policy, fmt.Sprintf("%s: expected %v got %v", desc, tc.policy, policy))
+	}
+
+	err = svc.RemovePolicy(p.Channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	policies, err := svc.ListPolicies()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, policies, fmt.Sprintf("expected no policies got %v", policies))
+}
+
+func TestEnforce(t *testing.T) {
+	svc, messages := newRetentionService("1", "2", failingChannel)
+	policies := []writers.Policy{
+		{Channel: writers.DefaultPolicy, RetentionDays: 30},
+		{Channel: "1", RetentionDays: 7, Downsampling: []string{"hour", "day"}},
+		{Channel: failingChannel, RetentionDays: 1, Downsampling: []string{"hour"}},
+	}
+	for _, p := range policies {
+		err := svc.SavePolicy(p)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	now := float64(time.Now().UnixNano()) / 1e9
+	err := svc.Enforce()
+	assert.True(t, errors.Contains(err, errors.New("failed to enforce retention policy")), fmt.Sprintf("expected enforce error got %s", err))
+
+	cases := map[string]struct {
+		chanID string
+		calls  []string
+		days   float64
+	}{
+		"enforce channel policy": {
+			chanID: "1",
+			calls:  []string{"downsample hour", "downsample day", "delete"},
+			days:   7,
+		},
+		"enforce default policy": {
+			chanID: "2",
+			calls:  []string{"delete"},
+			days:   30,
+		},
+		"enforce failing channel policy": {
+			chanID: failingChannel,
+			calls:  nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		calls := messages.calls[tc.chanID]
+		assert.Equal(t, tc.calls, calls, fmt.Sprintf("%s: expected calls %v got %v", desc, tc.calls, calls))
+		if tc.days == 0 {
+			continue
+		}
+		before := messages.before[tc.chanID]
+		assert.InDelta(t, now-tc.days*86400, before, 60, fmt.Sprintf("%s: expected deletion %v days ago got %f", desc, tc.days, before))
+	}
+
+	// Only the complete buckets are downsampled.
+	for rollup, interval := range writers.Rollups {
+		before := messages.before["1 "+rollup]
+		assert.Equal(t, math.Floor(before/interval)*interval, before, fmt.Sprintf("expected %s rollup to end at a bucket got %f", rollup, before))
+		assert.True(t, before <= now && now-before < interval+60, fmt.Sprintf("expected %s rollup to end at the last complete bucket got %f", rollup, before))
+	}
+}
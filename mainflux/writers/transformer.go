@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/json"
+	"github.com/cloustone/pandas/mainflux/transformers/protobuf"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/pkg/protodesc"
+)
+
+// protobufAlias is the registered alternative protobuf content type.
+const protobufAlias = "application/protobuf"
+
+var errLoadTransformers = errors.New("Unable to load transformers configuration")
+
+type protobufConfig struct {
+	Descriptors []string `toml:"descriptors"`
+}
+
+type transformersConfig struct {
+	Protobuf protobufConfig                 `toml:"protobuf"`
+	Schemas  map[string]transformers.Schema `toml:"schemas"`
+	Channels map[string]string              `toml:"channels"`
+}
+
+// NewTransformer returns the transformer of the content types the writers
+// support: SenML, JSON and protobuf, the latter two mapped by the schemas
+// of their channels. The schemas and the protobuf descriptor set files are
+// configured by the TOML file, if it exists. The messages without a content
+// type are transformed as JSON SenML.
+func NewTransformer(cfgPath string) (transformers.Transformer, error) {
+	var cfg transformersConfig
+	data, err := ioutil.ReadFile(cfgPath)
+	switch {
+	case err == nil:
+		if err := toml.Unmarshal(data, &cfg); err != nil {
+			return nil, errors.Wrap(errLoadTransformers, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, errors.Wrap(errLoadTransformers, err)
+	}
+
+	schemas := transformers.Schemas{
+		Schemas:  cfg.Schemas,
+		Channels: cfg.Channels,
+	}
+	jt, err := json.New(schemas)
+	if err != nil {
+		return nil, errors.Wrap(errLoadTransformers, err)
+	}
+	descriptors, err := protodesc.LoadDescriptors(cfg.Protobuf.Descriptors...)
+	if err != nil {
+		return nil, errors.Wrap(errLoadTransformers, err)
+	}
+	pt, err := protobuf.New(descriptors, schemas)
+	if err != nil {
+		return nil, errors.Wrap(errLoadTransformers, err)
+	}
+
+	r := transformers.NewRegistry(senml.JSON)
+	st := senml.New()
+	r.Register(senml.JSON, st)
+	r.Register(senml.CBOR, st)
+	r.Register(json.ContentType, jt)
+	r.Register(protobuf.ContentType, pt)
+	r.Register(protobufAlias, pt)
+
+	return r, nil
+}
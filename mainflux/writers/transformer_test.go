@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const transformersConfig = `
+[schemas.weather]
+time = "$.ts"
+
+  [[schemas.weather.fields]]
+  path = "$.main.temp"
+  name = "temperature"
+  unit = "Cel"
+
+[channels]
+"1" = "weather"
+`
+
+func TestNewTransformer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "writers")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "transformers.toml")
+	require.Nil(t, ioutil.WriteFile(path, []byte(transformersConfig), 0644), "unexpected error writing config")
+	invalid := filepath.Join(dir, "invalid.toml")
+	require.Nil(t, ioutil.WriteFile(invalid, []byte("[protobuf]\ndescriptors = [\"missing.pb\"]\n"), 0644), "unexpected error writing config")
+
+	temp := 21.5
+	cases := map[string]struct {
+		cfgPath string
+		msg     broker.Message
+		msgs    []senml.Message
+		err     error
+	}{
+		"transform JSON by channel schema": {
+			cfgPath: path,
+			msg: broker.Message{
+				Channel:     "1",
+				ContentType: "application/json",
+				Payload:     []byte(`{"ts": 100, "main": {"temp": 21.5}}`),
+			},
+			msgs: []senml.Message{{Channel: "1", Name: "temperature", Unit: "Cel", Time: 100, Value: &temp}},
+			err:  nil,
+		},
+		"transform SenML without content type": {
+			cfgPath: path,
+			msg:     broker.Message{Channel: "1", Payload: []byte(`[{"n": "temp", "t": 100, "v": 21.5}]`)},
+			msgs:    []senml.Message{{Channel: "1", Name: "temp", Time: 100, Value: &temp}},
+			err:     nil,
+		},
+		"transform without config": {
+			cfgPath: filepath.Join(dir, "missing.toml"),
+			msg:     broker.Message{Channel: "1", ContentType: senml.JSON, Payload: []byte(`[{"n": "temp", "t": 100, "v": 21.5}]`)},
+			msgs:    []senml.Message{{Channel: "1", Name: "temp", Time: 100, Value: &temp}},
+			err:     nil,
+		},
+		"transform unsupported content type": {
+			cfgPath: path,
+			msg:     broker.Message{Channel: "1", ContentType: "text/plain", Payload: []byte("21.5")},
+			msgs:    nil,
+			err:     transformers.ErrUnsupportedContentType,
+		},
+	}
+
+	for desc, tc := range cases {
+		tr, err := writers.NewTransformer(tc.cfgPath)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+
+		res, err := tr.Transform(tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, tc.msgs, res, fmt.Sprintf("%s: expected %v got %v", desc, tc.msgs, res))
+	}
+
+	_, err = writers.NewTransformer(invalid)
+	assert.True(t, errors.Contains(err, errors.New("Unable to load transformers configuration")), fmt.Sprintf("expected configuration error got %s", err))
+}
@@ -4,6 +4,7 @@
 package writers
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 
@@ -24,19 +25,31 @@ var (
 
 type consumer struct {
 	broker      broker.Nats
-	repo        MessageRepository
 	transformer transformers.Transformer
+	batcher     *batcher
+	dedup       *dedup
 	logger      logger.Logger
+	subs        []*nats.Subscription
+}
+
+// Consumer consumes the messages received from NATS.
+type Consumer interface {
+	// Close stops consuming and waits for the buffered messages to be saved.
+	Close() error
 }
 
 // Start method starts consuming messages received from NATS.
-// This method transforms messages to SenML format before
-// using MessageRepository to store them.
-func Start(broker broker.Nats, repo MessageRepository, transformer transformers.Transformer, queue string, subjectsCfgPath string, logger logger.Logger) error {
-	c := consumer{
+// This method transforms messages to SenML messages by the transformer
+// before using MessageRepository to store them in batches. The messages whose
+// ID was already received are dropped, and so are the commands and their
+// replies, which aren't telemetry. The letters replayed to the writer are
+// received on the ReplaySubject of the queue and are never dropped.
+func Start(broker broker.Nats, repo MessageRepository, transformer transformers.Transformer, queue string, subjectsCfgPath string, cfg Config, logger logger.Logger) (Consumer, error) {
+	c := &consumer{
 		broker:      broker,
-		repo:        repo,
 		transformer: transformer,
+		batcher:     newBatcher(repo, cfg, logger),
+		dedup:       newDedup(cfg.DedupSize),
 		logger:      logger,
 	}
 
@@ -46,21 +59,65 @@ func Start(broker broker.Nats, repo MessageRepository, transformer transformers.
 	}
 
 	for _, subject := range subjects {
-		_, err := broker.QueueSubscribe(subject, queue, c.consume)
+		sub, err := broker.QueueSubscribe(subject, queue, c.consume)
 		if err != nil {
-			return err
+			c.Close()
+			return nil, err
+		}
+		c.subs = append(c.subs, sub)
+	}
+
+	sub, err := broker.QueueSubscribe(ReplaySubject(queue), queue, c.replay)
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	c.subs = append(c.subs, sub)
+
+	return c, nil
+}
+
+func (c *consumer) Close() error {
+	var err error
+	for _, sub := range c.subs {
+		if e := sub.Unsubscribe(); e != nil && err == nil {
+			err = e
 		}
 	}
-	return nil
+	c.batcher.close()
+	return err
 }
 
 func (c *consumer) consume(m *nats.Msg) {
+	c.handle(m.Subject, m.Data, true)
+}
+
+func (c *consumer) replay(m *nats.Msg) {
+	var l Letter
+	if err := json.Unmarshal(m.Data, &l); err != nil {
+		c.logger.Warn(fmt.Sprintf("Failed to unmarshal replayed letter: %s", err))
+		return
+	}
+	c.handle(l.Subject, l.Message, false)
+}
+
+func (c *consumer) handle(subject string, data []byte, dedupe bool) {
 	var msg broker.Message
-	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+	if err := proto.Unmarshal(data, &msg); err != nil {
 		c.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
 		return
 	}
 
+	if broker.IsCommand(msg.Subtopic) {
+		c.logger.Debug(fmt.Sprintf("Dropped command message %s", msg.Id))
+		return
+	}
+
+	if dedupe && c.dedup.seen(msg.Id) {
+		c.logger.Debug(fmt.Sprintf("Dropped duplicate message %s", msg.Id))
+		return
+	}
+
 	t, err := c.transformer.Transform(msg)
 	if err != nil {
 		c.logger.Warn(fmt.Sprintf("Failed to tranform received message: %s", err))
@@ -72,10 +129,12 @@ func (c *consumer) consume(m *nats.Msg) {
 		return
 	}
 
-	if err := c.repo.Save(msgs...); err != nil {
-		c.logger.Warn(fmt.Sprintf("Failed to save message: %s", err))
-		return
-	}
+	c.batcher.add(entry{
+		subject: subject,
+		data:    data,
+		created: msg.Created,
+		msgs:    msgs,
+	})
 }
 
 type filterConfig struct {
@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package writers
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerHandle(t *testing.T) {
+	logger, _ := log.New(ioutil.Discard, log.Error.String())
+	payload := []byte(`[{"bn":"thing:","n":"temp","v":21.5}]`)
+
+	cases := map[string]struct {
+		subtopic string
+		payload  []byte
+		saved    int
+	}{
+		"handle telemetry": {
+			subtopic: "",
+			payload:  payload,
+			saved:    1,
+		},
+		"handle telemetry of subtopic": {
+			subtopic: "commandsx",
+			payload:  payload,
+			saved:    1,
+		},
+		"handle command": {
+			subtopic: "commands.thing",
+			payload:  payload,
+			saved:    0,
+		},
+		"handle command reply": {
+			subtopic: "commands.thing.reply",
+			payload:  payload,
+			saved:    0,
+		},
+	}
+
+	for desc, tc := range cases {
+		repo := &repoMock{}
+		c := &consumer{
+			transformer: senml.New(),
+			batcher:     newBatcher(repo, Config{BatchSize: 1}, logger),
+			dedup:       newDedup(10),
+			logger:      logger,
+		}
+		msg := broker.Message{
+			Id:          "1",
+			Channel:     "1",
+			Subtopic:    tc.subtopic,
+			Publisher:   "thing",
+			Protocol:    "mqtt",
+			ContentType: senml.JSON,
+			Payload:     tc.payload,
+			Created:     time.Now().UnixNano(),
+		}
+		data, err := proto.Marshal(&msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", desc, err))
+
+		c.handle("channel.1", data, true)
+		c.batcher.close()
+		_, saved := repo.saved()
+		assert.Equal(t, tc.saved, saved, fmt.Sprintf("%s: expected %d saved messages got %d", desc, tc.saved, saved))
+	}
+}
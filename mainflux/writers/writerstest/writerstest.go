@@ -0,0 +1,165 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package writerstest contains the conformance tests of the retention
+// repositories, which are run against each of the writers.
+package writerstest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cloustone/pandas/mainflux/readers"
+	"github.com/cloustone/pandas/mainflux/transformers/senml"
+	"github.com/cloustone/pandas/mainflux/writers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// start is the time of the first message in seconds, it's aligned to
+	// the rollup intervals.
+	start = 1500076800
+	hour  = 3600
+
+	msgsNum = 36
+	step    = 600
+	delta   = 1e-6
+)
+
+var names = []string{"temperature", "humidity"}
+
+// RunPolicies runs the conformance tests of the policy repository.
+func RunPolicies(t *testing.T, repo writers.PolicyRepository, chanID string) {
+	policies := []writers.Policy{
+		{Channel: writers.DefaultPolicy, RetentionDays: 30},
+		{Channel: chanID, RetentionDays: 7, Downsampling: []string{"hour", "day"}},
+	}
+	for _, p := range policies {
+		err := repo.Save(p)
+		require.Nil(t, err, fmt.Sprintf("saving policy %s expected to succeed: %s", p.Channel, err))
+	}
+
+	updated := writers.Policy{Channel: chanID, RetentionDays: 14, Downsampling: []string{"day"}}
+	err := repo.Save(updated)
+	require.Nil(t, err, fmt.Sprintf("updating policy expected to succeed: %s", err))
+
+	cases := map[string]struct {
+		chanID string
+		policy writers.Policy
+		err    error
+	}{
+		"retrieve default policy": {
+			chanID: writers.DefaultPolicy,
+			policy: policies[0],
+		},
+		"retrieve updated channel policy": {
+			chanID: chanID,
+			policy: updated,
+		},
+		"retrieve non-existent policy": {
+			chanID: "non-existent",
+			err:    writers.ErrNotFound,
+		},
+	}
+	for desc, tc := range cases {
+		p, err := repo.Retrieve(tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		assert.Equal(t, tc.policy, p, fmt.Sprintf("%s: expected %v got %v", desc, tc.policy, p))
+	}
+
+	all, err := repo.RetrieveAll()
+	require.Nil(t, err, fmt.Sprintf("retrieving policies expected to succeed: %s", err))
+	assert.ElementsMatch(t, []writers.Policy{policies[0], updated}, all, fmt.Sprintf("expected policies %v got %v", policies, all))
+
+	err = repo.Remove(chanID)
+	require.Nil(t, err, fmt.Sprintf("removing policy expected to succeed: %s", err))
+	_, err = repo.Retrieve(chanID)
+	assert.Equal(t, writers.ErrNotFound, err, fmt.Sprintf("retrieving removed policy: expected %s got %s", writers.ErrNotFound, err))
+}
+
+// RunRetention saves the messages of the channel, rolls up and deletes the
+// older ones, and checks that the reader aggregates the values the same
+// from the rollups as from the raw messages.
+func RunRetention(t *testing.T, repo writers.MessageRepository, rr writers.RetentionRepository, reader readers.MessageRepository, chanID, pubID string) {
+	msgs := []senml.Message{}
+	for i := 0; i < msgsNum; i++ {
+		v := float64(i)
+		msgs = append(msgs, senml.Message{
+			Channel:   chanID,
+			Publisher: pubID,
+			Protocol:  "mqtt",
+			Name:      names[i%len(names)],
+			Value:     &v,
+			Time:      float64(start + i*step),
+		})
+	}
+	err := repo.Save(msgs...)
+	require.Nil(t, err, fmt.Sprintf("saving messages expected to succeed: %s", err))
+
+	chanIDs, err := rr.Channels()
+	require.Nil(t, err, fmt.Sprintf("retrieving channels expected to succeed: %s", err))
+	assert.Contains(t, chanIDs, chanID, fmt.Sprintf("expected channels to contain %s", chanID))
+
+	aggregations := map[string]readers.AggregationMetadata{
+		"aggregate hours": {
+			Interval:    hour,
+			Aggregation: readers.AggregationAvg,
+		},
+		"aggregate hour pairs by count": {
+			Interval:    2 * hour,
+			Aggregation: readers.AggregationCount,
+		},
+		"aggregate last of day": {
+			Interval:    24 * hour,
+			Aggregation: readers.AggregationLast,
+		},
+		// The rolled up hour the range starts in is read whole, its maximum
+		// is the same since the values grow.
+		"aggregate hours of range by max": {
+			From:        start + hour/2,
+			To:          start + 3*hour,
+			Interval:    hour,
+			Aggregation: readers.AggregationMax,
+		},
+	}
+	expected := make(map[string][]readers.Series)
+	for desc, am := range aggregations {
+		series, err := reader.Aggregate(chanID, am)
+		require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		expected[desc] = series
+	}
+
+	// The messages of the first four hours are rolled up, the ones of the
+	// first three are deleted, and the rolled up hours aren't rolled up
+	// twice.
+	for i := 0; i < 2; i++ {
+		err = rr.Downsample(chanID, "hour", hour, start+4*hour)
+		require.Nil(t, err, fmt.Sprintf("downsampling expected to succeed: %s", err))
+	}
+	err = rr.Delete(chanID, start+3*hour)
+	require.Nil(t, err, fmt.Sprintf("deleting expected to succeed: %s", err))
+
+	page, err := reader.ReadAll(chanID, readers.PageMetadata{Limit: msgsNum})
+	require.Nil(t, err, fmt.Sprintf("reading messages expected to succeed: %s", err))
+	assert.Equal(t, msgsNum/2, len(page.Messages), fmt.Sprintf("expected %d raw messages got %d", msgsNum/2, len(page.Messages)))
+
+	for desc, am := range aggregations {
+		series, err := reader.Aggregate(chanID, am)
+		require.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", desc, err))
+		assertSeries(t, desc, expected[desc], series)
+	}
+}
+
+func assertSeries(t *testing.T, desc string, expected, series []readers.Series) {
+	require.Equal(t, len(expected), len(series), fmt.Sprintf("%s: expected %d series got %d", desc, len(expected), len(series)))
+	for i, s := range series {
+		assert.Equal(t, expected[i].Name, s.Name, fmt.Sprintf("%s: expected series %s got %s", desc, expected[i].Name, s.Name))
+		require.Equal(t, len(expected[i].Points), len(s.Points), fmt.Sprintf("%s: expected %d points of %s got %d", desc, len(expected[i].Points), s.Name, len(s.Points)))
+		for j, p := range s.Points {
+			e := expected[i].Points[j]
+			assert.InDelta(t, e.Time, p.Time, delta, fmt.Sprintf("%s: expected point time %f got %f", desc, e.Time, p.Time))
+			assert.InDelta(t, e.Value, p.Value, delta, fmt.Sprintf("%s: expected point value %f got %f", desc, e.Value, p.Value))
+		}
+	}
+}
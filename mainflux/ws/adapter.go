@@ -7,13 +7,13 @@ package ws
 
 import (
 	"context"
-
-	"errors"
 	"fmt"
 	"sync"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/mainflux/schema"
+	"github.com/cloustone/pandas/pkg/errors"
 	"github.com/cloustone/pandas/pkg/logger"
 	"github.com/nats-io/nats.go"
 )
@@ -94,8 +94,10 @@ func New(broker broker.Nats, log logger.Logger) Service {
 
 func (as *adapterService) Publish(ctx context.Context, token string, msg broker.Message) error {
 	if err := as.broker.Publish(ctx, token, msg); err != nil {
-		switch err {
-		case nats.ErrConnectionClosed, nats.ErrInvalidConnection:
+		switch {
+		case errors.Contains(err, schema.ErrInvalidMessage):
+			return err
+		case err == nats.ErrConnectionClosed, err == nats.ErrInvalidConnection:
 			return ErrFailedConnection
 		default:
 			return ErrFailedMessagePublish
@@ -229,6 +229,8 @@ func (sub subscription) broadcast(svc ws.Service, contentType string) {
 			Publisher:   sub.pubID,
 			Protocol:    protocol,
 			Payload:     payload,
+			Created:     broker.Created(),
+			Headers:     map[string]string{broker.HeaderRemoteAddr: sub.conn.RemoteAddr().String()},
 		}
 		if err := svc.Publish(context.Background(), "", msg); err != nil {
 			logger.Warn(fmt.Sprintf("Failed to publish message to NATS: %s", err))
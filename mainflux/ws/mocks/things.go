@@ -57,3 +57,7 @@ func (tc thingsClient) CanAccessByID(context.Context, *mainflux.AccessByIDReq, .
 func (tc thingsClient) Identify(context.Context, *mainflux.Token, ...grpc.CallOption) (*mainflux.ThingID, error) {
 	panic("not implemented")
 }
+
+func (tc thingsClient) ViewSchema(context.Context, *mainflux.ChannelID, ...grpc.CallOption) (*mainflux.Schema, error) {
+	return nil, status.Error(codes.NotFound, "entity does not exist")
+}
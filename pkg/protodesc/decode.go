@@ -0,0 +1,276 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package protodesc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// Decode decodes the payload of the message type into the map of the values
+// of its fields by name. The numeric values are decoded as float64, the
+// enums as their names, the bytes as []byte, and the unknown fields are
+// skipped. Only the fields in the payload are set.
+func (d *Descriptors) Decode(msgType string, data []byte) (map[string]interface{}, error) {
+	m, err := d.lookup(msgType)
+	if err != nil {
+		return nil, err
+	}
+	return decoder{d: d}.decode(m, data, false)
+}
+
+// DecodeJSON decodes the payload as Decode does, with the types of the JSON
+// mapping: the bytes are base64 strings, and the missing fields of the
+// proto3 messages are set with their defaults, as they are not distinguished
+// from the fields with the default values, except for the fields of the
+// messages and the oneofs.
+func (d *Descriptors) DecodeJSON(msgType string, data []byte) (map[string]interface{}, error) {
+	m, err := d.lookup(msgType)
+	if err != nil {
+		return nil, err
+	}
+	return decoder{d: d, json: true}.decode(m, data, m.proto3)
+}
+
+type decoder struct {
+	d    *Descriptors
+	json bool
+}
+
+func (dec decoder) decode(m *message, data []byte, defaults bool) (map[string]interface{}, error) {
+	ret := make(map[string]interface{})
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, malformed("invalid tag in message %s", m.name)
+		}
+		data = data[n:]
+
+		wire := key & 7
+		x, b, rest, err := readValue(wire, data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		f, ok := m.fields[int32(key>>3)]
+		if !ok {
+			continue
+		}
+
+		if entry := dec.d.mapEntry(f); entry != nil {
+			if wire != wireBytes {
+				return nil, malformed("field %s has wire type %d", f.GetName(), wire)
+			}
+			// The missing key or value are the defaults in any syntax
+			e, err := dec.decode(entry, b, true)
+			if err != nil {
+				return nil, err
+			}
+			entries, _ := ret[f.GetName()].(map[string]interface{})
+			if entries == nil {
+				entries = make(map[string]interface{})
+				ret[f.GetName()] = entries
+			}
+			if _, ok := e["value"]; !ok {
+				e["value"] = map[string]interface{}{}
+			}
+			entries[mapKey(e["key"])] = e["value"]
+			continue
+		}
+
+		var vals []interface{}
+		switch expected := wireType(f.GetType()); {
+		case wire == wireBytes && expected != wireBytes:
+			vals, err = dec.unpack(f, expected, b)
+		case wire == expected:
+			var v interface{}
+			v, err = dec.value(f, x, b)
+			vals = []interface{}{v}
+		default:
+			err = malformed("field %s has wire type %d", f.GetName(), wire)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if f.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED {
+			list, _ := ret[f.GetName()].([]interface{})
+			if list == nil {
+				list = []interface{}{}
+			}
+			ret[f.GetName()] = append(list, vals...)
+			continue
+		}
+		v := vals[len(vals)-1]
+		if old, ok := ret[f.GetName()].(map[string]interface{}); ok && f.GetType() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			// The embedded messages of a field are merged
+			for k, e := range v.(map[string]interface{}) {
+				old[k] = e
+			}
+			continue
+		}
+		ret[f.GetName()] = v
+	}
+
+	if defaults {
+		if err := dec.defaults(m, ret); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (dec decoder) defaults(m *message, ret map[string]interface{}) error {
+	for _, f := range m.desc.GetField() {
+		if _, ok := ret[f.GetName()]; ok || f.OneofIndex != nil {
+			continue
+		}
+		switch {
+		case dec.d.mapEntry(f) != nil:
+			ret[f.GetName()] = map[string]interface{}{}
+		case f.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED:
+			ret[f.GetName()] = []interface{}{}
+		case f.GetType() != descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+			v, err := dec.value(f, 0, nil)
+			if err != nil {
+				return err
+			}
+			ret[f.GetName()] = v
+		}
+	}
+	return nil
+}
+
+// readValue reads the value of the wire type, either its raw number or its
+// bytes, and returns the rest of the data.
+func readValue(wire uint64, data []byte) (uint64, []byte, []byte, error) {
+	switch wire {
+	case wireVarint:
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, nil, nil, malformed("invalid varint")
+		}
+		return v, nil, data[n:], nil
+	case wireFixed64:
+		if len(data) < 8 {
+			return 0, nil, nil, malformed("invalid fixed64")
+		}
+		return binary.LittleEndian.Uint64(data), nil, data[8:], nil
+	case wireFixed32:
+		if len(data) < 4 {
+			return 0, nil, nil, malformed("invalid fixed32")
+		}
+		return uint64(binary.LittleEndian.Uint32(data)), nil, data[4:], nil
+	case wireBytes:
+		l, n := binary.Uvarint(data)
+		if n <= 0 || l > uint64(len(data)-n) {
+			return 0, nil, nil, malformed("invalid length")
+		}
+		end := n + int(l)
+		return 0, data[n:end], data[end:], nil
+	default:
+		return 0, nil, nil, malformed("unsupported wire type %d", wire)
+	}
+}
+
+// unpack decodes the packed repeated values of the field.
+func (dec decoder) unpack(f *descriptor.FieldDescriptorProto, wire uint64, data []byte) ([]interface{}, error) {
+	vals := []interface{}{}
+	for len(data) > 0 {
+		x, _, rest, err := readValue(wire, data)
+		if err != nil {
+			return nil, err
+		}
+		data = rest
+
+		v, err := dec.value(f, x, nil)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+	return vals, nil
+}
+
+func (dec decoder) value(f *descriptor.FieldDescriptorProto, x uint64, b []byte) (interface{}, error) {
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return math.Float64frombits(x), nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return float64(math.Float32frombits(uint32(x))), nil
+	case descriptor.FieldDescriptorProto_TYPE_INT64, descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return float64(int64(x)), nil
+	case descriptor.FieldDescriptorProto_TYPE_INT32, descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return float64(int32(x)), nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64, descriptor.FieldDescriptorProto_TYPE_FIXED64:
+		return float64(x), nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32, descriptor.FieldDescriptorProto_TYPE_FIXED32:
+		return float64(uint32(x)), nil
+	case descriptor.FieldDescriptorProto_TYPE_SINT32:
+		return float64(int32(uint32(x)>>1) ^ -int32(x&1)), nil
+	case descriptor.FieldDescriptorProto_TYPE_SINT64:
+		return float64(int64(x>>1) ^ -int64(x&1)), nil
+	case descriptor.FieldDescriptorProto_TYPE_BOOL:
+		return x != 0, nil
+	case descriptor.FieldDescriptorProto_TYPE_ENUM:
+		if name, ok := dec.d.enum(f).names[int32(x)]; ok {
+			return name, nil
+		}
+		return float64(int32(x)), nil
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		return string(b), nil
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		if dec.json {
+			return base64.StdEncoding.EncodeToString(b), nil
+		}
+		return append([]byte{}, b...), nil
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		m := dec.d.message(f)
+		return dec.decode(m, b, dec.json && m.proto3)
+	default:
+		return nil, malformed("unsupported type %s of field %s", f.GetType(), f.GetName())
+	}
+}
+
+// mapKey formats the key of a map entry as in the JSON mapping.
+func mapKey(k interface{}) string {
+	if f, ok := k.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(k)
+}
+
+// wireType returns the wire type of the unpacked values of the field type.
+func wireType(t descriptor.FieldDescriptorProto_Type) uint64 {
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE, descriptor.FieldDescriptorProto_TYPE_FIXED64,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return wireFixed64
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT, descriptor.FieldDescriptorProto_TYPE_FIXED32,
+		descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return wireFixed32
+	case descriptor.FieldDescriptorProto_TYPE_STRING, descriptor.FieldDescriptorProto_TYPE_BYTES,
+		descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		return wireBytes
+	default:
+		return wireVarint
+	}
+}
+
+func malformed(format string, args ...interface{}) error {
+	return errors.Wrap(ErrMalformed, errors.New(fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package protodesc decodes and encodes the protobuf messages by the
+// descriptors of their types, as compiled by protoc, instead of generated
+// code.
+package protodesc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+var (
+	// ErrLoadDescriptors indicates a descriptor set file which can't be read.
+	ErrLoadDescriptors = errors.New("failed to load protobuf descriptors")
+
+	// ErrInvalidDescriptors indicates descriptors which can't be resolved.
+	ErrInvalidDescriptors = errors.New("invalid protobuf descriptors")
+
+	// ErrUnknownType indicates a message type which is not registered.
+	ErrUnknownType = errors.New("unknown protobuf message type")
+
+	// ErrMalformed indicates a payload which is not of the message type.
+	ErrMalformed = errors.New("malformed protobuf payload")
+
+	// ErrInvalidValue indicates a value which can't be encoded as the type
+	// of its field.
+	ErrInvalidValue = errors.New("invalid protobuf field value")
+)
+
+type message struct {
+	name   string
+	desc   *descriptor.DescriptorProto
+	proto3 bool
+	fields map[int32]*descriptor.FieldDescriptorProto
+}
+
+type enum struct {
+	name    string
+	names   map[int32]string
+	numbers map[string]int32
+}
+
+// Descriptors are the registered protobuf message types.
+type Descriptors struct {
+	messages map[string]*message
+	enums    map[string]*enum
+}
+
+// ReadDescriptorSet reads the files of the descriptor set file, as written
+// by protoc with the --descriptor_set_out option.
+func ReadDescriptorSet(path string) ([]*descriptor.FileDescriptorProto, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(ErrLoadDescriptors, err)
+	}
+	var set descriptor.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, errors.Wrap(ErrLoadDescriptors, err)
+	}
+	return set.GetFile(), nil
+}
+
+// LoadDescriptors registers the message types of the descriptor set files.
+// The files imported by the message types must be in the sets as well,
+// which is the --include_imports option of protoc.
+func LoadDescriptors(paths ...string) (*Descriptors, error) {
+	files := []*descriptor.FileDescriptorProto{}
+	for _, p := range paths {
+		f, err := ReadDescriptorSet(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f...)
+	}
+
+	return NewDescriptors(files...)
+}
+
+// NewDescriptors registers the message types of the files. It fails if a
+// field refers to a type which is not in the files, or is a proto2 group.
+func NewDescriptors(files ...*descriptor.FileDescriptorProto) (*Descriptors, error) {
+	d := &Descriptors{
+		messages: make(map[string]*message),
+		enums:    make(map[string]*enum),
+	}
+	for _, f := range files {
+		d.addEnums(f.GetPackage(), f.GetEnumType())
+		if err := d.addMessages(f.GetPackage(), f.GetMessageType(), f.GetSyntax() == "proto3"); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range d.messages {
+		if err := d.validate(m); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func (d *Descriptors) addMessages(scope string, msgs []*descriptor.DescriptorProto, proto3 bool) error {
+	for _, md := range msgs {
+		m := &message{
+			name:   fullName(scope, md.GetName()),
+			desc:   md,
+			proto3: proto3,
+			fields: make(map[int32]*descriptor.FieldDescriptorProto),
+		}
+		if _, ok := d.messages[m.name]; ok {
+			return invalid("message %s is defined more than once", m.name)
+		}
+		for _, f := range md.GetField() {
+			if _, ok := m.fields[f.GetNumber()]; ok {
+				return invalid("field number %d is used more than once in message %s", f.GetNumber(), m.name)
+			}
+			m.fields[f.GetNumber()] = f
+		}
+		d.messages[m.name] = m
+		d.addEnums(m.name, md.GetEnumType())
+		if err := d.addMessages(m.name, md.GetNestedType(), proto3); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Descriptors) addEnums(scope string, enums []*descriptor.EnumDescriptorProto) {
+	for _, ed := range enums {
+		e := &enum{
+			name:    fullName(scope, ed.GetName()),
+			names:   make(map[int32]string),
+			numbers: make(map[string]int32),
+		}
+		for _, v := range ed.GetValue() {
+			// The first name of the aliases is the one decoded
+			if _, ok := e.names[v.GetNumber()]; !ok {
+				e.names[v.GetNumber()] = v.GetName()
+			}
+			e.numbers[v.GetName()] = v.GetNumber()
+		}
+		d.enums[e.name] = e
+	}
+}
+
+func (d *Descriptors) validate(m *message) error {
+	for _, f := range m.desc.GetField() {
+		switch f.GetType() {
+		case descriptor.FieldDescriptorProto_TYPE_GROUP:
+			return invalid("group %s of message %s is not supported", f.GetName(), m.name)
+		case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+			if d.message(f) == nil {
+				return invalid("type %s of field %s is not defined", f.GetTypeName(), f.GetName())
+			}
+		case descriptor.FieldDescriptorProto_TYPE_ENUM:
+			if d.enum(f) == nil {
+				return invalid("type %s of field %s is not defined", f.GetTypeName(), f.GetName())
+			}
+		}
+	}
+	if m.desc.GetOptions().GetMapEntry() && (m.fields[1] == nil || m.fields[2] == nil) {
+		return invalid("map entry %s has no key or value", m.name)
+	}
+	return nil
+}
+
+// Has tells if the message type of the full name is registered.
+func (d *Descriptors) Has(msgType string) bool {
+	_, ok := d.messages[strings.TrimPrefix(msgType, ".")]
+	return ok
+}
+
+func (d *Descriptors) lookup(msgType string) (*message, error) {
+	m, ok := d.messages[strings.TrimPrefix(msgType, ".")]
+	if !ok {
+		return nil, errors.Wrap(ErrUnknownType, errors.New(msgType))
+	}
+	return m, nil
+}
+
+// message returns the message type of the field, which is resolved by
+// protoc to the full name.
+func (d *Descriptors) message(f *descriptor.FieldDescriptorProto) *message {
+	return d.messages[strings.TrimPrefix(f.GetTypeName(), ".")]
+}
+
+func (d *Descriptors) enum(f *descriptor.FieldDescriptorProto) *enum {
+	return d.enums[strings.TrimPrefix(f.GetTypeName(), ".")]
+}
+
+// mapEntry returns the entry type of the map field, or nil if the field is
+// not a map.
+func (d *Descriptors) mapEntry(f *descriptor.FieldDescriptorProto) *message {
+	if f.GetType() != descriptor.FieldDescriptorProto_TYPE_MESSAGE || f.GetLabel() != descriptor.FieldDescriptorProto_LABEL_REPEATED {
+		return nil
+	}
+	if m := d.message(f); m != nil && m.desc.GetOptions().GetMapEntry() {
+		return m
+	}
+	return nil
+}
+
+func fullName(scope, name string) string {
+	if scope == "" {
+		return name
+	}
+	return scope + "." + name
+}
+
+func invalid(format string, args ...interface{}) error {
+	return errors.Wrap(ErrInvalidDescriptors, errors.New(fmt.Sprintf(format, args...)))
+}
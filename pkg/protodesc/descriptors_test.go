@@ -0,0 +1,333 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package protodesc_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/pkg/protodesc"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pointType = "geo.Point"
+
+func field(name string, num int32, typ descriptor.FieldDescriptorProto_Type, typeName string, label descriptor.FieldDescriptorProto_Label) *descriptor.FieldDescriptorProto {
+	f := &descriptor.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(num),
+		Label:  &label,
+		Type:   &typ,
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+// pointFile describes the geo.Point message type:
+//
+//	message Point {
+//	  enum Kind { FIXED = 0; MOVING = 1; }
+//	  double lat = 1;
+//	  sint64 alt = 2;
+//	  repeated uint32 samples = 3;
+//	  Kind kind = 4;
+//	  bytes raw = 5;
+//	  map<int32, Point> near = 6;
+//	  oneof source { string gps = 7; }
+//	}
+func pointFile(syntax string) *descriptor.FileDescriptorProto {
+	optional, repeated := descriptor.FieldDescriptorProto_LABEL_OPTIONAL, descriptor.FieldDescriptorProto_LABEL_REPEATED
+	gps := field("gps", 7, descriptor.FieldDescriptorProto_TYPE_STRING, "", optional)
+	gps.OneofIndex = proto.Int32(0)
+	return &descriptor.FileDescriptorProto{
+		Name:    proto.String("point.proto"),
+		Package: proto.String("geo"),
+		Syntax:  proto.String(syntax),
+		MessageType: []*descriptor.DescriptorProto{{
+			Name: proto.String("Point"),
+			Field: []*descriptor.FieldDescriptorProto{
+				field("lat", 1, descriptor.FieldDescriptorProto_TYPE_DOUBLE, "", optional),
+				field("alt", 2, descriptor.FieldDescriptorProto_TYPE_SINT64, "", optional),
+				field("samples", 3, descriptor.FieldDescriptorProto_TYPE_UINT32, "", repeated),
+				field("kind", 4, descriptor.FieldDescriptorProto_TYPE_ENUM, ".geo.Point.Kind", optional),
+				field("raw", 5, descriptor.FieldDescriptorProto_TYPE_BYTES, "", optional),
+				field("near", 6, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".geo.Point.NearEntry", repeated),
+				gps,
+			},
+			NestedType: []*descriptor.DescriptorProto{{
+				Name: proto.String("NearEntry"),
+				Field: []*descriptor.FieldDescriptorProto{
+					field("key", 1, descriptor.FieldDescriptorProto_TYPE_INT32, "", optional),
+					field("value", 2, descriptor.FieldDescriptorProto_TYPE_MESSAGE, ".geo.Point", optional),
+				},
+				Options: &descriptor.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			EnumType: []*descriptor.EnumDescriptorProto{{
+				Name: proto.String("Kind"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					{Name: proto.String("FIXED"), Number: proto.Int32(0)},
+					{Name: proto.String("MOVING"), Number: proto.Int32(1)},
+				},
+			}},
+			OneofDecl: []*descriptor.OneofDescriptorProto{{Name: proto.String("source")}},
+		}},
+	}
+}
+
+func TestLoadDescriptors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "protodesc")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	set, err := proto.Marshal(&descriptor.FileDescriptorSet{File: []*descriptor.FileDescriptorProto{pointFile("proto3")}})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	valid := filepath.Join(dir, "point.pb")
+	require.Nil(t, ioutil.WriteFile(valid, set, 0644), "unexpected error writing descriptors")
+	invalid := filepath.Join(dir, "point.proto")
+	require.Nil(t, ioutil.WriteFile(invalid, []byte(`syntax = "proto3";`), 0644), "unexpected error writing descriptors")
+
+	cases := map[string]struct {
+		path string
+		err  error
+	}{
+		"load descriptor set":              {path: valid, err: nil},
+		"load missing descriptor set":      {path: filepath.Join(dir, "missing.pb"), err: protodesc.ErrLoadDescriptors},
+		"load file which is not a set":     {path: invalid, err: protodesc.ErrLoadDescriptors},
+		"load descriptor set without path": {path: "", err: protodesc.ErrLoadDescriptors},
+	}
+
+	for desc, tc := range cases {
+		d, err := protodesc.LoadDescriptors(tc.path)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		if tc.err == nil {
+			assert.True(t, d.Has(pointType), fmt.Sprintf("%s: expected %s registered", desc, pointType))
+		}
+	}
+}
+
+func TestNewDescriptors(t *testing.T) {
+	cases := map[string]struct {
+		update func(f *descriptor.FileDescriptorProto)
+		err    error
+	}{
+		"register valid descriptors": {
+			update: func(f *descriptor.FileDescriptorProto) {},
+			err:    nil,
+		},
+		"register undefined field type": {
+			update: func(f *descriptor.FileDescriptorProto) {
+				f.MessageType[0].Field[3].TypeName = proto.String(".geo.Unknown")
+			},
+			err: protodesc.ErrInvalidDescriptors,
+		},
+		"register duplicated field number": {
+			update: func(f *descriptor.FileDescriptorProto) {
+				f.MessageType[0].Field[1].Number = proto.Int32(1)
+			},
+			err: protodesc.ErrInvalidDescriptors,
+		},
+		"register group": {
+			update: func(f *descriptor.FileDescriptorProto) {
+				typ := descriptor.FieldDescriptorProto_TYPE_GROUP
+				f.MessageType[0].Field[0].Type = &typ
+			},
+			err: protodesc.ErrInvalidDescriptors,
+		},
+		"register duplicated message": {
+			update: func(f *descriptor.FileDescriptorProto) {
+				f.MessageType = append(f.MessageType, f.MessageType[0])
+			},
+			err: protodesc.ErrInvalidDescriptors,
+		},
+	}
+
+	for desc, tc := range cases {
+		f := pointFile("proto3")
+		tc.update(f)
+		_, err := protodesc.NewDescriptors(f)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	point := map[string]interface{}{
+		"lat":     31.5,
+		"alt":     float64(-12),
+		"samples": []interface{}{float64(1), float64(4294967295)},
+		"kind":    "MOVING",
+		"raw":     []byte{1, 2},
+		"near":    map[string]interface{}{"-1": map[string]interface{}{"lat": 1.5}},
+		"gps":     "fix",
+	}
+
+	cases := map[string]struct {
+		syntax string
+		msg    map[string]interface{}
+		raw    map[string]interface{}
+		json   map[string]interface{}
+	}{
+		"encode and decode proto3 message": {
+			syntax: "proto3",
+			msg:    point,
+			raw:    point,
+			json: map[string]interface{}{
+				"lat":     31.5,
+				"alt":     float64(-12),
+				"samples": []interface{}{float64(1), float64(4294967295)},
+				"kind":    "MOVING",
+				"raw":     "AQI=",
+				"near": map[string]interface{}{"-1": map[string]interface{}{
+					"lat":     1.5,
+					"alt":     float64(0),
+					"samples": []interface{}{},
+					"kind":    "FIXED",
+					"raw":     "",
+					"near":    map[string]interface{}{},
+				}},
+				"gps": "fix",
+			},
+		},
+		"encode and decode proto2 message": {
+			syntax: "proto2",
+			msg:    point,
+			raw:    point,
+			json: map[string]interface{}{
+				"lat":     31.5,
+				"alt":     float64(-12),
+				"samples": []interface{}{float64(1), float64(4294967295)},
+				"kind":    "MOVING",
+				"raw":     "AQI=",
+				"near":    map[string]interface{}{"-1": map[string]interface{}{"lat": 1.5}},
+				"gps":     "fix",
+			},
+		},
+		"encode and decode empty proto3 message": {
+			syntax: "proto3",
+			msg:    map[string]interface{}{"unknown": 1},
+			raw:    map[string]interface{}{},
+			json: map[string]interface{}{
+				"lat":     float64(0),
+				"alt":     float64(0),
+				"samples": []interface{}{},
+				"kind":    "FIXED",
+				"raw":     "",
+				"near":    map[string]interface{}{},
+			},
+		},
+	}
+
+	for desc, tc := range cases {
+		d, err := protodesc.NewDescriptors(pointFile(tc.syntax))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		b, err := d.Encode(pointType, tc.msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+
+		raw, err := d.Decode(pointType, b)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.raw, raw, fmt.Sprintf("%s: expected %v got %v", desc, tc.raw, raw))
+		json, err := d.DecodeJSON(pointType, b)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, tc.json, json, fmt.Sprintf("%s: expected %v got %v", desc, tc.json, json))
+
+		// The JSON values are encoded back into the same message
+		jb, err := d.Encode(pointType, json)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		decoded, err := d.DecodeJSON(pointType, jb)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", desc, err))
+		assert.Equal(t, json, decoded, fmt.Sprintf("%s: expected %v got %v", desc, json, decoded))
+	}
+}
+
+func TestDecodePacked(t *testing.T) {
+	d, err := protodesc.NewDescriptors(pointFile("proto2"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The repeated scalars are accepted both packed and unpacked
+	b := proto.NewBuffer(nil)
+	b.EncodeVarint(3<<3 | proto.WireBytes)
+	b.EncodeRawBytes([]byte{1, 2})
+	b.EncodeVarint(3<<3 | proto.WireVarint)
+	b.EncodeVarint(3)
+	res, err := d.Decode(pointType, b.Bytes())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []interface{}{float64(1), float64(2), float64(3)}, res["samples"], fmt.Sprintf("expected packed and unpacked samples got %v", res["samples"]))
+}
+
+func TestDecodeErrors(t *testing.T) {
+	d, err := protodesc.NewDescriptors(pointFile("proto3"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		msgType string
+		data    []byte
+		err     error
+	}{
+		"decode unknown message type": {
+			msgType: "geo.Unknown",
+			data:    []byte{},
+			err:     protodesc.ErrUnknownType,
+		},
+		"decode truncated payload": {
+			msgType: pointType,
+			data:    []byte{0x0a, 0x05, 'a'},
+			err:     protodesc.ErrMalformed,
+		},
+		"decode field of wrong wire type": {
+			msgType: pointType,
+			data:    []byte{0x08, 0x01},
+			err:     protodesc.ErrMalformed,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := d.Decode(tc.msgType, tc.data)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+		_, err = d.DecodeJSON(tc.msgType, tc.data)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	d, err := protodesc.NewDescriptors(pointFile("proto3"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		msg map[string]interface{}
+		err error
+	}{
+		"encode unknown enum value": {
+			msg: map[string]interface{}{"kind": "STOPPED"},
+			err: protodesc.ErrInvalidValue,
+		},
+		"encode invalid number": {
+			msg: map[string]interface{}{"lat": true},
+			err: protodesc.ErrInvalidValue,
+		},
+		"encode invalid base64 bytes": {
+			msg: map[string]interface{}{"raw": "!"},
+			err: protodesc.ErrInvalidValue,
+		},
+		"encode invalid repeated field": {
+			msg: map[string]interface{}{"samples": float64(1)},
+			err: protodesc.ErrInvalidValue,
+		},
+		"encode invalid map field": {
+			msg: map[string]interface{}{"near": []interface{}{}},
+			err: protodesc.ErrInvalidValue,
+		},
+	}
+
+	for desc, tc := range cases {
+		_, err := d.Encode(pointType, tc.msg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", desc, tc.err, err))
+	}
+}
@@ -0,0 +1,255 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package protodesc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// Encode encodes the map of the values of the fields by name into a payload
+// of the message type. It accepts the values as decoded by Decode or
+// DecodeJSON, and the numbers of any Go numeric type or strings. The fields
+// not defined in the message type are dropped.
+func (d *Descriptors) Encode(msgType string, v map[string]interface{}) ([]byte, error) {
+	m, err := d.lookup(msgType)
+	if err != nil {
+		return nil, err
+	}
+	return d.encode(nil, m, v)
+}
+
+func (d *Descriptors) encode(b []byte, m *message, v map[string]interface{}) ([]byte, error) {
+	for _, f := range m.desc.GetField() {
+		fv, ok := v[f.GetName()]
+		if !ok || fv == nil {
+			continue
+		}
+		var err error
+		switch entry := d.mapEntry(f); {
+		case entry != nil:
+			if b, err = d.encodeMap(b, f, entry, fv); err != nil {
+				return nil, err
+			}
+		case f.GetLabel() == descriptor.FieldDescriptorProto_LABEL_REPEATED:
+			list, ok := fv.([]interface{})
+			if !ok {
+				return nil, invalidValue("expect array for field %s but found %[2]T(%[2]v)", f.GetName(), fv)
+			}
+			if isPacked(f, m.proto3) && len(list) > 0 {
+				var packed []byte
+				for _, e := range list {
+					if packed, err = d.appendScalar(packed, f, e); err != nil {
+						return nil, err
+					}
+				}
+				b = appendTag(b, f.GetNumber(), wireBytes)
+				b = appendVarint(b, uint64(len(packed)))
+				b = append(b, packed...)
+				continue
+			}
+			for _, e := range list {
+				if b, err = d.encodeValue(b, f, e); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			if b, err = d.encodeValue(b, f, fv); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+// encodeMap appends the entries of the map field sorted by key.
+func (d *Descriptors) encodeMap(b []byte, f *descriptor.FieldDescriptorProto, entry *message, v interface{}) ([]byte, error) {
+	entries, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, invalidValue("expect map for field %s but found %[2]T(%[2]v)", f.GetName(), v)
+	}
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		var key interface{} = k
+		if entry.fields[1].GetType() == descriptor.FieldDescriptorProto_TYPE_BOOL {
+			key = k == "true"
+		}
+		e, err := d.encodeValue(nil, entry.fields[1], key)
+		if err != nil {
+			return nil, err
+		}
+		if e, err = d.encodeValue(e, entry.fields[2], entries[k]); err != nil {
+			return nil, err
+		}
+		b = appendTag(b, f.GetNumber(), wireBytes)
+		b = appendVarint(b, uint64(len(e)))
+		b = append(b, e...)
+	}
+	return b, nil
+}
+
+// isPacked tells if the repeated field is packed, which is the default of
+// the scalars in proto3 only.
+func isPacked(f *descriptor.FieldDescriptorProto, proto3 bool) bool {
+	if wireType(f.GetType()) == wireBytes {
+		return false
+	}
+	if o := f.GetOptions(); o != nil && o.Packed != nil {
+		return o.GetPacked()
+	}
+	return proto3
+}
+
+// encodeValue appends a single value with the tag of the field.
+func (d *Descriptors) encodeValue(b []byte, f *descriptor.FieldDescriptorProto, v interface{}) ([]byte, error) {
+	wire := wireType(f.GetType())
+	b = appendTag(b, f.GetNumber(), wire)
+	if wire != wireBytes {
+		return d.appendScalar(b, f, v)
+	}
+	var raw []byte
+	switch f.GetType() {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, invalidValue("expect map for field %s but found %[2]T(%[2]v)", f.GetName(), v)
+		}
+		var err error
+		if raw, err = d.encode(nil, d.message(f), m); err != nil {
+			return nil, err
+		}
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		switch t := v.(type) {
+		case string:
+			raw = []byte(t)
+		case []byte:
+			raw = t
+		default:
+			raw = []byte(fmt.Sprint(v))
+		}
+	default:
+		switch t := v.(type) {
+		case []byte:
+			raw = t
+		case string:
+			var err error
+			if raw, err = base64.StdEncoding.DecodeString(t); err != nil {
+				return nil, invalidValue("expect base64 string for bytes field %s: %s", f.GetName(), err)
+			}
+		default:
+			return nil, invalidValue("expect bytes for field %s but found %[2]T(%[2]v)", f.GetName(), v)
+		}
+	}
+	b = appendVarint(b, uint64(len(raw)))
+	return append(b, raw...), nil
+}
+
+// appendScalar appends the varint or fixed value without the tag.
+func (d *Descriptors) appendScalar(b []byte, f *descriptor.FieldDescriptorProto, v interface{}) ([]byte, error) {
+	t := f.GetType()
+	if s, ok := v.(string); ok && t == descriptor.FieldDescriptorProto_TYPE_ENUM {
+		e := d.enum(f)
+		n, ok := e.numbers[s]
+		if !ok {
+			return nil, invalidValue("%s is not a value of enum %s", s, e.name)
+		}
+		return appendVarint(b, uint64(int64(n))), nil
+	}
+	if t == descriptor.FieldDescriptorProto_TYPE_BOOL {
+		bv, ok := v.(bool)
+		if !ok {
+			return nil, invalidValue("expect bool for field %s but found %[2]T(%[2]v)", f.GetName(), v)
+		}
+		if bv {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	}
+	x, err := toNumber(v)
+	if err != nil {
+		return nil, invalidValue("field %s: %s", f.GetName(), err)
+	}
+	switch t {
+	case descriptor.FieldDescriptorProto_TYPE_DOUBLE:
+		return appendFixed64(b, math.Float64bits(x)), nil
+	case descriptor.FieldDescriptorProto_TYPE_FLOAT:
+		return appendFixed32(b, math.Float32bits(float32(x))), nil
+	case descriptor.FieldDescriptorProto_TYPE_FIXED64, descriptor.FieldDescriptorProto_TYPE_SFIXED64:
+		return appendFixed64(b, uint64(int64(x))), nil
+	case descriptor.FieldDescriptorProto_TYPE_FIXED32, descriptor.FieldDescriptorProto_TYPE_SFIXED32:
+		return appendFixed32(b, uint32(int32(x))), nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT64:
+		return appendVarint(b, uint64(x)), nil
+	case descriptor.FieldDescriptorProto_TYPE_UINT32:
+		return appendVarint(b, uint64(uint32(x))), nil
+	case descriptor.FieldDescriptorProto_TYPE_SINT32:
+		n := int32(x)
+		return appendVarint(b, uint64(uint32(n<<1^n>>31))), nil
+	case descriptor.FieldDescriptorProto_TYPE_SINT64:
+		n := int64(x)
+		return appendVarint(b, uint64(n<<1^n>>63)), nil
+	default:
+		// int32, int64 and enum numbers are sign extended
+		return appendVarint(b, uint64(int64(x))), nil
+	}
+}
+
+func toNumber(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case int:
+		return float64(t), nil
+	case int32:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint32:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case string:
+		return strconv.ParseFloat(t, 64)
+	default:
+		return 0, fmt.Errorf("expect number but found %[1]T(%[1]v)", v)
+	}
+}
+
+func appendVarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	return append(b, buf[:n]...)
+}
+
+func appendFixed64(b []byte, x uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendFixed32(b []byte, x uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], x)
+	return append(b, buf[:]...)
+}
+
+func appendTag(b []byte, number int32, wire uint64) []byte {
+	return appendVarint(b, uint64(number)<<3|wire)
+}
+
+func invalidValue(format string, args ...interface{}) error {
+	return errors.Wrap(ErrInvalidValue, errors.New(fmt.Sprintf(format, args...)))
+}
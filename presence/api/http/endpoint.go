@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/presence"
+	"github.com/go-kit/kit/endpoint"
+)
+
+func viewPresenceEndpoint(svc presence.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewPresenceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.ViewPresence(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toPresenceRes(p), nil
+	}
+}
+
+func listPresenceEndpoint(svc presence.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listPresenceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListPresence(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := presencePageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Presences: []presenceRes{},
+		}
+		for _, p := range page.Presences {
+			res.Presences = append(res.Presences, toPresenceRes(p))
+		}
+
+		return res, nil
+	}
+}
+
+func updateTimeoutEndpoint(svc presence.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateTimeoutReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		timeout := time.Duration(*req.Timeout) * time.Second
+		if err := svc.UpdateTimeout(ctx, req.token, req.id, timeout); err != nil {
+			return nil, err
+		}
+
+		return updateTimeoutRes{}, nil
+	}
+}
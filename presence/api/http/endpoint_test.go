@@ -0,0 +1,343 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/presence"
+	httpapi "github.com/cloustone/pandas/presence/api/http"
+	"github.com/cloustone/pandas/presence/mocks"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/cloustone/pandas/things"
+	thingsapi "github.com/cloustone/pandas/things/api/things/http"
+	thmocks "github.com/cloustone/pandas/things/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType = "application/json"
+	token       = "token"
+	wrongValue  = "wrong_value"
+	email       = "user@example.com"
+	protocol    = "http"
+	remoteAddr  = "10.0.0.1:50000"
+	timeout     = time.Minute
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+type presenceRes struct {
+	ThingID    string `json:"thing_id"`
+	Online     bool   `json:"online"`
+	Protocol   string `json:"protocol"`
+	RemoteAddr string `json:"remote_addr"`
+	Timeout    int64  `json:"timeout"`
+}
+
+type presencePageRes struct {
+	Total     uint64        `json:"total"`
+	Presences []presenceRes `json:"presences"`
+}
+
+func newThingsService(tokens map[string]string) things.Service {
+	auth := thmocks.NewAuthService(tokens)
+	conns := make(chan thmocks.Connection)
+	thingsRepo := thmocks.NewThingRepository(conns)
+	channelsRepo := thmocks.NewChannelRepository(thingsRepo, conns)
+	chanCache := thmocks.NewChannelCache()
+	thingCache := thmocks.NewThingCache()
+	idp := thmocks.NewIdentityProvider()
+
+	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+}
+
+func newService(url string) presence.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{BaseURL: url})
+	return presence.New(mocks.NewPresenceRepository(), sdk, mocks.NewPublisher(), timeout)
+}
+
+func newServer(svc presence.Service) *httptest.Server {
+	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	return httptest.NewServer(mux)
+}
+
+func createThing(t *testing.T, svc things.Service) string {
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "thing"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return ths[0].ID
+}
+
+func TestViewPresence(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ths := httptest.NewServer(thingsapi.MakeHandler(mocktracer.New(), tsvc))
+	defer ths.Close()
+	svc := newService(ths.URL)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	id := createThing(t, tsvc)
+	err := svc.Seen(context.Background(), presence.Activity{ThingID: id, Protocol: protocol, RemoteAddr: remoteAddr, Time: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    presenceRes
+	}{
+		{
+			desc:   "view presence",
+			id:     id,
+			auth:   token,
+			status: http.StatusOK,
+			res:    presenceRes{ThingID: id, Online: true, Protocol: protocol, RemoteAddr: remoteAddr, Timeout: int64(timeout / time.Second)},
+		},
+		{
+			desc:   "view presence with invalid token",
+			id:     id,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    presenceRes{},
+		},
+		{
+			desc:   "view presence with empty token",
+			id:     id,
+			auth:   "",
+			status: http.StatusForbidden,
+			res:    presenceRes{},
+		},
+		{
+			desc:   "view presence of non-existing thing",
+			id:     wrongValue,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    presenceRes{},
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/presence/%s", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body presenceRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, body))
+	}
+}
+
+func TestListPresence(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ths := httptest.NewServer(thingsapi.MakeHandler(mocktracer.New(), tsvc))
+	defer ths.Close()
+	svc := newService(ths.URL)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	n := 3
+	for i := 0; i < n; i++ {
+		createThing(t, tsvc)
+	}
+
+	cases := []struct {
+		desc   string
+		auth   string
+		query  string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list presences",
+			auth:   token,
+			query:  "",
+			status: http.StatusOK,
+			size:   n,
+		},
+		{
+			desc:   "list presences with offset and limit",
+			auth:   token,
+			query:  "?offset=1&limit=1",
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list presences with invalid token",
+			auth:   wrongValue,
+			query:  "",
+			status: http.StatusForbidden,
+			size:   0,
+		},
+		{
+			desc:   "list presences with zero limit",
+			auth:   token,
+			query:  "?limit=0",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list presences with limit greater than max",
+			auth:   token,
+			query:  "?limit=101",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+		{
+			desc:   "list presences with invalid offset",
+			auth:   token,
+			query:  "?offset=e",
+			status: http.StatusBadRequest,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/presence%s", ts.URL, tc.query),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body presencePageRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.size, len(body.Presences), fmt.Sprintf("%s: expected %d presences got %d", tc.desc, tc.size, len(body.Presences)))
+	}
+}
+
+func TestUpdateTimeout(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ths := httptest.NewServer(thingsapi.MakeHandler(mocktracer.New(), tsvc))
+	defer ths.Close()
+	svc := newService(ths.URL)
+	ts := newServer(svc)
+	defer ts.Close()
+
+	id := createThing(t, tsvc)
+
+	cases := []struct {
+		desc        string
+		id          string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "update timeout",
+			id:          id,
+			req:         `{"timeout":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update timeout with negative value",
+			id:          id,
+			req:         `{"timeout":-1}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update timeout without value",
+			id:          id,
+			req:         `{}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update timeout with malformed request",
+			id:          id,
+			req:         `{"timeout":`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update timeout with invalid content type",
+			id:          id,
+			req:         `{"timeout":3600}`,
+			contentType: "text/plain",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "update timeout with invalid token",
+			id:          id,
+			req:         `{"timeout":3600}`,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update timeout of non-existing thing",
+			id:          wrongValue,
+			req:         `{"timeout":3600}`,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/presence/%s/timeout", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	p, err := svc.ViewPresence(context.Background(), token, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, time.Hour, p.Timeout, fmt.Sprintf("expected timeout %s got %s", time.Hour, p.Timeout))
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import "github.com/cloustone/pandas/presence"
+
+const maxLimitSize = 100
+
+type apiReq interface {
+	validate() error
+}
+
+type viewPresenceReq struct {
+	token string
+	id    string
+}
+
+func (req viewPresenceReq) validate() error {
+	if req.token == "" {
+		return presence.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return presence.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listPresenceReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listPresenceReq) validate() error {
+	if req.token == "" {
+		return presence.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return presence.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type updateTimeoutReq struct {
+	token   string
+	id      string
+	Timeout *int64 `json:"timeout"`
+}
+
+func (req updateTimeoutReq) validate() error {
+	if req.token == "" {
+		return presence.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Timeout == nil || *req.Timeout < 0 {
+		return presence.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/presence"
+)
+
+var (
+	_ mainflux.Response = (*presenceRes)(nil)
+	_ mainflux.Response = (*presencePageRes)(nil)
+	_ mainflux.Response = (*updateTimeoutRes)(nil)
+)
+
+type presenceRes struct {
+	ThingID     string     `json:"thing_id"`
+	Online      bool       `json:"online"`
+	Protocol    string     `json:"protocol,omitempty"`
+	RemoteAddr  string     `json:"remote_addr,omitempty"`
+	ConnectedAt *time.Time `json:"connected_at,omitempty"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+	Timeout     int64      `json:"timeout"`
+}
+
+func (res presenceRes) Code() int {
+	return http.StatusOK
+}
+
+func (res presenceRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res presenceRes) Empty() bool {
+	return false
+}
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type presencePageRes struct {
+	pageRes
+	Presences []presenceRes `json:"presences"`
+}
+
+func (res presencePageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res presencePageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res presencePageRes) Empty() bool {
+	return false
+}
+
+type updateTimeoutRes struct{}
+
+func (res updateTimeoutRes) Code() int {
+	return http.StatusOK
+}
+
+func (res updateTimeoutRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res updateTimeoutRes) Empty() bool {
+	return true
+}
+
+func toPresenceRes(p presence.Presence) presenceRes {
+	res := presenceRes{
+		ThingID:    p.ThingID,
+		Online:     p.Online,
+		Protocol:   p.Protocol,
+		RemoteAddr: p.RemoteAddr,
+		Timeout:    int64(p.Timeout / time.Second),
+	}
+	if !p.ConnectedAt.IsZero() {
+		connectedAt := p.ConnectedAt
+		res.ConnectedAt = &connectedAt
+	}
+	if !p.LastSeen.IsZero() {
+		lastSeen := p.LastSeen
+		res.LastSeen = &lastSeen
+	}
+
+	return res
+}
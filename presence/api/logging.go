@@ -0,0 +1,131 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/cloustone/pandas/presence"
+)
+
+var _ presence.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    presence.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc presence.Service, logger log.Logger) presence.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) ViewPresence(ctx context.Context, token, id string) (p presence.Presence, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_presence for token %s and thing %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewPresence(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListPresence(ctx context.Context, token string, offset, limit uint64) (page presence.Page, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_presence for token %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListPresence(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) UpdateTimeout(ctx context.Context, token, id string, timeout time.Duration) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_timeout for token %s and thing %s with timeout %s took %s to complete", token, id, timeout, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateTimeout(ctx, token, id, timeout)
+}
+
+func (lm *loggingMiddleware) Connect(ctx context.Context, a presence.Activity) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method connect for thing %s took %s to complete", a.ThingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Connect(ctx, a)
+}
+
+func (lm *loggingMiddleware) Disconnect(ctx context.Context, a presence.Activity) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disconnect for thing %s took %s to complete", a.ThingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Disconnect(ctx, a)
+}
+
+func (lm *loggingMiddleware) Seen(ctx context.Context, a presence.Activity) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method seen for thing %s took %s to complete", a.ThingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Seen(ctx, a)
+}
+
+func (lm *loggingMiddleware) CheckInactivity(ctx context.Context, now time.Time) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method check_inactivity took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CheckInactivity(ctx, now)
+}
+
+func (lm *loggingMiddleware) Remove(ctx context.Context, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove for thing %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Remove(ctx, id)
+}
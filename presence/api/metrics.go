@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloustone/pandas/presence"
+	"github.com/go-kit/kit/metrics"
+)
+
+var _ presence.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     presence.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and
+// latency.
+func MetricsMiddleware(svc presence.Service, counter metrics.Counter, latency metrics.Histogram) presence.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) ViewPresence(ctx context.Context, token, id string) (p presence.Presence, err error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_presence").Add(1)
+		ms.latency.With("method", "view_presence").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewPresence(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListPresence(ctx context.Context, token string, offset, limit uint64) (page presence.Page, err error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_presence").Add(1)
+		ms.latency.With("method", "list_presence").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListPresence(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) UpdateTimeout(ctx context.Context, token, id string, timeout time.Duration) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_timeout").Add(1)
+		ms.latency.With("method", "update_timeout").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateTimeout(ctx, token, id, timeout)
+}
+
+func (ms *metricsMiddleware) Connect(ctx context.Context, a presence.Activity) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "connect").Add(1)
+		ms.latency.With("method", "connect").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Connect(ctx, a)
+}
+
+func (ms *metricsMiddleware) Disconnect(ctx context.Context, a presence.Activity) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disconnect").Add(1)
+		ms.latency.With("method", "disconnect").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Disconnect(ctx, a)
+}
+
+func (ms *metricsMiddleware) Seen(ctx context.Context, a presence.Activity) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "seen").Add(1)
+		ms.latency.With("method", "seen").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Seen(ctx, a)
+}
+
+func (ms *metricsMiddleware) CheckInactivity(ctx context.Context, now time.Time) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "check_inactivity").Add(1)
+		ms.latency.With("method", "check_inactivity").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CheckInactivity(ctx, now)
+}
+
+func (ms *metricsMiddleware) Remove(ctx context.Context, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove").Add(1)
+		ms.latency.With("method", "remove").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Remove(ctx, id)
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package presence contains the domain concept definitions needed to support
+// Mainflux presence service functionality. The service tracks whether the
+// things are online, when they were seen last and how they are connected,
+// and publishes the changes of their presence as events.
+package presence
@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cloustone/pandas/presence"
+)
+
+var _ presence.Repository = (*presenceRepositoryMock)(nil)
+
+type presenceRepositoryMock struct {
+	mu        sync.Mutex
+	presences map[string]presence.Presence
+}
+
+// NewPresenceRepository creates in-memory presence repository.
+func NewPresenceRepository() presence.Repository {
+	return &presenceRepositoryMock{
+		presences: make(map[string]presence.Presence),
+	}
+}
+
+func (prm *presenceRepositoryMock) Save(_ context.Context, p presence.Presence) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	prm.presences[p.ThingID] = p
+	return nil
+}
+
+func (prm *presenceRepositoryMock) RetrieveByID(_ context.Context, id string) (presence.Presence, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	p, ok := prm.presences[id]
+	if !ok {
+		return presence.Presence{}, presence.ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (prm *presenceRepositoryMock) RetrieveAll(_ context.Context, ids ...string) ([]presence.Presence, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	ps := []presence.Presence{}
+	for _, id := range ids {
+		if p, ok := prm.presences[id]; ok {
+			ps = append(ps, p)
+		}
+	}
+
+	return ps, nil
+}
+
+func (prm *presenceRepositoryMock) RetrieveOnline(context.Context) ([]presence.Presence, error) {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	ps := []presence.Presence{}
+	for _, p := range prm.presences {
+		if p.Online {
+			ps = append(ps, p)
+		}
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].ThingID < ps[j].ThingID
+	})
+
+	return ps, nil
+}
+
+func (prm *presenceRepositoryMock) Remove(_ context.Context, id string) error {
+	prm.mu.Lock()
+	defer prm.mu.Unlock()
+
+	delete(prm.presences, id)
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloustone/pandas/presence"
+)
+
+var _ presence.Publisher = (*Publisher)(nil)
+
+// Publisher is the presence events publisher which records the published
+// events.
+type Publisher struct {
+	mu     sync.Mutex
+	events []presence.Event
+}
+
+// NewPublisher returns the recording presence events publisher.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the event.
+func (pub *Publisher) Publish(_ context.Context, event presence.Event) error {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	pub.events = append(pub.events, event)
+	return nil
+}
+
+// Events returns the recorded events and clears them.
+func (pub *Publisher) Events() []presence.Event {
+	pub.mu.Lock()
+	defer pub.mu.Unlock()
+
+	events := pub.events
+	pub.events = nil
+	return events
+}
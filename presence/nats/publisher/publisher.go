@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/presence"
+)
+
+const contentType = "application/json"
+
+var _ presence.Publisher = (*publisher)(nil)
+
+type publisher struct {
+	broker    broker.Nats
+	channelID string
+}
+
+// NewPublisher returns the publisher of the presence events to the channel.
+// The events aren't published if the channel isn't set.
+func NewPublisher(b broker.Nats, chID string) presence.Publisher {
+	return publisher{
+		broker:    b,
+		channelID: chID,
+	}
+}
+
+// Publish publishes the event on behalf of the thing, the event type is
+// carried by the event type header.
+func (pub publisher) Publish(ctx context.Context, event presence.Event) error {
+	if pub.channelID == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(toEventRes(event))
+	if err != nil {
+		return err
+	}
+
+	msg := broker.Message{
+		Channel:     pub.channelID,
+		Publisher:   event.Presence.ThingID,
+		Protocol:    event.Presence.Protocol,
+		ContentType: contentType,
+		Payload:     payload,
+		Headers:     map[string]string{broker.HeaderEventType: event.Type},
+	}
+
+	return pub.broker.Publish(ctx, "", msg)
+}
+
+type eventRes struct {
+	Event       string `json:"event"`
+	ThingID     string `json:"thing_id"`
+	Online      bool   `json:"online"`
+	Protocol    string `json:"protocol,omitempty"`
+	RemoteAddr  string `json:"remote_addr,omitempty"`
+	ConnectedAt int64  `json:"connected_at,omitempty"`
+	LastSeen    int64  `json:"last_seen,omitempty"`
+	Timeout     uint64 `json:"timeout"`
+}
+
+func toEventRes(event presence.Event) eventRes {
+	p := event.Presence
+	res := eventRes{
+		Event:      event.Type,
+		ThingID:    p.ThingID,
+		Online:     p.Online,
+		Protocol:   p.Protocol,
+		RemoteAddr: p.RemoteAddr,
+		Timeout:    uint64(p.Timeout / time.Second),
+	}
+	if !p.ConnectedAt.IsZero() {
+		res.ConnectedAt = p.ConnectedAt.Unix()
+	}
+	if !p.LastSeen.IsZero() {
+		res.LastSeen = p.LastSeen.Unix()
+	}
+
+	return res
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package subscriber
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux/broker"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/cloustone/pandas/presence"
+	"github.com/gogo/protobuf/proto"
+	nats "github.com/nats-io/nats.go"
+)
+
+const queue = "presence"
+
+// Subscriber is used to intercept the messages published by the things and
+// update the last seen time of their publishers.
+type Subscriber struct {
+	broker    broker.Nats
+	logger    log.Logger
+	svc       presence.Service
+	channelID string
+}
+
+// NewSubscriber instances Subscriber strucure. The messages of the channel
+// the presence events are published to are skipped.
+func NewSubscriber(b broker.Nats, chID string, svc presence.Service, logger log.Logger) *Subscriber {
+	return &Subscriber{
+		broker:    b,
+		logger:    logger,
+		svc:       svc,
+		channelID: chID,
+	}
+}
+
+// Subscribe subscribes to the messages of all channels.
+func (s *Subscriber) Subscribe() error {
+	_, err := s.broker.QueueSubscribe(broker.SubjectAllChannels, queue, s.handleMsg)
+	return err
+}
+
+func (s *Subscriber) handleMsg(m *nats.Msg) {
+	var msg broker.Message
+	if err := proto.Unmarshal(m.Data, &msg); err != nil {
+		s.logger.Warn(fmt.Sprintf("Unmarshalling failed: %s", err))
+		return
+	}
+
+	// The messages published by the services rather than by the things
+	// through the adapters don't have a protocol.
+	if msg.Channel == s.channelID || msg.Publisher == "" || msg.Protocol == "" {
+		return
+	}
+
+	t := time.Now()
+	if msg.Created > 0 {
+		t = time.Unix(0, msg.Created)
+	}
+	a := presence.Activity{
+		ThingID:    msg.Publisher,
+		Protocol:   msg.Protocol,
+		RemoteAddr: msg.Headers[broker.HeaderRemoteAddr],
+		Time:       t,
+	}
+	if err := s.svc.Seen(context.Background(), a); err != nil {
+		s.logger.Error(fmt.Sprintf("Presence update failed: %s", err))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence
+
+import (
+	"context"
+	"time"
+)
+
+// Presence represents the connectivity of a thing.
+type Presence struct {
+	ThingID     string
+	Online      bool
+	Protocol    string
+	RemoteAddr  string
+	ConnectedAt time.Time
+	LastSeen    time.Time
+
+	// Timeout is the inactivity timeout of the thing, the default timeout
+	// of the service applies if it's zero.
+	Timeout time.Duration
+}
+
+// PageMetadata contains page metadata that helps navigation.
+type PageMetadata struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+}
+
+// Page contains page related metadata as well as the presences of the
+// things that belong to this page.
+type Page struct {
+	PageMetadata
+	Presences []Presence
+}
+
+// Activity is a connection, a disconnection or a message of a thing observed
+// by an adapter.
+type Activity struct {
+	ThingID    string
+	Protocol   string
+	RemoteAddr string
+	Time       time.Time
+}
+
+// Types of the presence events.
+const (
+	// ConnectEvent is published when a thing connects.
+	ConnectEvent = "connect"
+
+	// DisconnectEvent is published when a thing disconnects.
+	DisconnectEvent = "disconnect"
+
+	// ActivityEvent is published when an offline thing publishes a message.
+	ActivityEvent = "activity"
+
+	// InactivityEvent is published when a thing isn't seen for longer than
+	// its inactivity timeout.
+	InactivityEvent = "inactivity"
+)
+
+// Event is a change of the presence of a thing.
+type Event struct {
+	Type     string
+	Presence Presence
+}
+
+// Repository specifies a presence persistence API.
+type Repository interface {
+	// Save persists the presence of the thing.
+	Save(context.Context, Presence) error
+
+	// RetrieveByID retrieves the presence of the thing identified by the
+	// provided ID, ErrNotFound is returned if the thing was never seen.
+	RetrieveByID(context.Context, string) (Presence, error)
+
+	// RetrieveAll retrieves the presences of the things identified by the
+	// provided IDs, the things which were never seen are omitted.
+	RetrieveAll(context.Context, ...string) ([]Presence, error)
+
+	// RetrieveOnline retrieves the presences of the online things.
+	RetrieveOnline(context.Context) ([]Presence, error)
+
+	// Remove removes the presence of the thing identified by the provided
+	// ID.
+	Remove(context.Context, string) error
+}
+
+// Publisher specifies the presence events publishing API.
+type Publisher interface {
+	// Publish publishes the presence event.
+	Publish(context.Context, Event) error
+}
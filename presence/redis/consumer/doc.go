@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package consumer contains events consumer for the connection events
+// published by MQTT adapter and the events published by Things service.
+package consumer
@@ -0,0 +1,124 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloustone/pandas/pkg/logger"
+	"github.com/cloustone/pandas/presence"
+	"github.com/go-redis/redis"
+)
+
+const (
+	// MQTTStream is the stream of the MQTT adapter connection events.
+	MQTTStream = "mainflux.mqtt"
+
+	// ThingsStream is the stream of the Things service events.
+	ThingsStream = "mainflux.things"
+
+	group = "mainflux.presence"
+
+	mqttConnect    = "connect"
+	mqttDisconnect = "disconnect"
+	mqttProtocol   = "mqtt"
+
+	thingRemove = "thing.remove"
+
+	exists = "BUSYGROUP Consumer Group name already exists"
+)
+
+// Subscriber represents event source for things connectivity and
+// provisioning.
+type Subscriber interface {
+	// Subscribes to given stream and receives events.
+	Subscribe(string) error
+}
+
+type eventStore struct {
+	svc      presence.Service
+	client   *redis.Client
+	consumer string
+	logger   logger.Logger
+}
+
+// NewEventStore returns new event store instance.
+func NewEventStore(svc presence.Service, client *redis.Client, consumer string, log logger.Logger) Subscriber {
+	return eventStore{
+		svc:      svc,
+		client:   client,
+		consumer: consumer,
+		logger:   log,
+	}
+}
+
+func (es eventStore) Subscribe(stream string) error {
+	err := es.client.XGroupCreateMkStream(stream, group, "$").Err()
+	if err != nil && err.Error() != exists {
+		return err
+	}
+
+	for {
+		streams, err := es.client.XReadGroup(&redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: es.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    100,
+		}).Result()
+		if err != nil || len(streams) == 0 {
+			continue
+		}
+
+		for _, msg := range streams[0].Messages {
+			if err := es.handle(msg.Values); err != nil {
+				es.logger.Warn(fmt.Sprintf("Failed to handle event sourcing: %s", err.Error()))
+				break
+			}
+			es.client.XAck(stream, group, msg.ID)
+		}
+	}
+}
+
+func (es eventStore) handle(event map[string]interface{}) error {
+	ctx := context.Background()
+
+	if read(event, "operation", "") == thingRemove {
+		return es.svc.Remove(ctx, read(event, "id", ""))
+	}
+
+	switch read(event, "event_type", "") {
+	case mqttConnect:
+		return es.svc.Connect(ctx, decodeMQTTEvent(event))
+	case mqttDisconnect:
+		return es.svc.Disconnect(ctx, decodeMQTTEvent(event))
+	default:
+		return nil
+	}
+}
+
+func decodeMQTTEvent(event map[string]interface{}) presence.Activity {
+	t := time.Now()
+	if sec, err := strconv.ParseInt(read(event, "timestamp", ""), 10, 64); err == nil {
+		t = time.Unix(sec, 0)
+	}
+
+	// The MQTT proxy doesn't expose the remote addresses of the clients.
+	return presence.Activity{
+		ThingID:  read(event, "thing_id", ""),
+		Protocol: mqttProtocol,
+		Time:     t,
+	}
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
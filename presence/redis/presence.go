@@ -0,0 +1,159 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cloustone/pandas/presence"
+	"github.com/go-redis/redis"
+)
+
+const (
+	keyPrefix = "presence:thing"
+	onlineKey = "presence:online"
+
+	onlineField      = "online"
+	protocolField    = "protocol"
+	remoteAddrField  = "remote_addr"
+	connectedAtField = "connected_at"
+	lastSeenField    = "last_seen"
+	timeoutField     = "timeout"
+)
+
+var _ presence.Repository = (*presenceRepository)(nil)
+
+type presenceRepository struct {
+	client *redis.Client
+}
+
+// NewPresenceRepository returns redis presence repository implementation.
+// The presence of each thing is stored in a hash, and the IDs of the online
+// things in a set.
+func NewPresenceRepository(client *redis.Client) presence.Repository {
+	return &presenceRepository{
+		client: client,
+	}
+}
+
+func (pr *presenceRepository) Save(_ context.Context, p presence.Presence) error {
+	fields := map[string]interface{}{
+		onlineField:      strconv.FormatBool(p.Online),
+		protocolField:    p.Protocol,
+		remoteAddrField:  p.RemoteAddr,
+		connectedAtField: toUnixNano(p.ConnectedAt),
+		lastSeenField:    toUnixNano(p.LastSeen),
+		timeoutField:     int64(p.Timeout),
+	}
+
+	_, err := pr.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HMSet(key(p.ThingID), fields)
+		if p.Online {
+			pipe.SAdd(onlineKey, p.ThingID)
+			return nil
+		}
+		pipe.SRem(onlineKey, p.ThingID)
+		return nil
+	})
+
+	return err
+}
+
+func (pr *presenceRepository) RetrieveByID(_ context.Context, id string) (presence.Presence, error) {
+	fields, err := pr.client.HGetAll(key(id)).Result()
+	if err != nil {
+		return presence.Presence{}, err
+	}
+	if len(fields) == 0 {
+		return presence.Presence{}, presence.ErrNotFound
+	}
+
+	return toPresence(id, fields), nil
+}
+
+func (pr *presenceRepository) RetrieveAll(_ context.Context, ids ...string) ([]presence.Presence, error) {
+	if len(ids) == 0 {
+		return []presence.Presence{}, nil
+	}
+
+	cmds := make([]*redis.StringStringMapCmd, len(ids))
+	_, err := pr.client.Pipelined(func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(key(id))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ps := []presence.Presence{}
+	for i, cmd := range cmds {
+		fields := cmd.Val()
+		if len(fields) == 0 {
+			continue
+		}
+		ps = append(ps, toPresence(ids[i], fields))
+	}
+
+	return ps, nil
+}
+
+func (pr *presenceRepository) RetrieveOnline(ctx context.Context) ([]presence.Presence, error) {
+	ids, err := pr.client.SMembers(onlineKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return pr.RetrieveAll(ctx, ids...)
+}
+
+func (pr *presenceRepository) Remove(_ context.Context, id string) error {
+	_, err := pr.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(key(id))
+		pipe.SRem(onlineKey, id)
+		return nil
+	})
+
+	return err
+}
+
+func key(id string) string {
+	return fmt.Sprintf("%s:%s", keyPrefix, id)
+}
+
+func toUnixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.UnixNano()
+}
+
+func fromUnixNano(s string) time.Time {
+	ns, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || ns == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, ns)
+}
+
+func toPresence(id string, fields map[string]string) presence.Presence {
+	online, _ := strconv.ParseBool(fields[onlineField])
+	timeout, _ := strconv.ParseInt(fields[timeoutField], 10, 64)
+
+	return presence.Presence{
+		ThingID:     id,
+		Online:      online,
+		Protocol:    fields[protocolField],
+		RemoteAddr:  fields[remoteAddrField],
+		ConnectedAt: fromUnixNano(fields[connectedAtField]),
+		LastSeen:    fromUnixNano(fields[lastSeenField]),
+		Timeout:     time.Duration(timeout),
+	}
+}
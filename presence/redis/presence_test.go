@@ -0,0 +1,176 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/presence"
+	"github.com/cloustone/pandas/presence/redis"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPresence(t *testing.T, online bool) presence.Presence {
+	id, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	now := time.Unix(0, time.Now().UnixNano())
+	return presence.Presence{
+		ThingID:     id.String(),
+		Online:      online,
+		Protocol:    "http",
+		RemoteAddr:  "10.0.0.1:50000",
+		ConnectedAt: now,
+		LastSeen:    now,
+		Timeout:     time.Minute,
+	}
+}
+
+func TestPresenceSave(t *testing.T) {
+	repo := redis.NewPresenceRepository(redisClient)
+	p := newPresence(t, true)
+
+	cases := []struct {
+		desc     string
+		presence presence.Presence
+	}{
+		{
+			desc:     "save online presence",
+			presence: p,
+		},
+		{
+			desc: "save offline presence",
+			presence: func() presence.Presence {
+				off := p
+				off.Online = false
+				return off
+			}(),
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Save(context.Background(), tc.presence)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		saved, err := repo.RetrieveByID(context.Background(), tc.presence.ThingID)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.presence, saved, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.presence, saved))
+	}
+}
+
+func TestPresenceRetrieveByID(t *testing.T) {
+	repo := redis.NewPresenceRepository(redisClient)
+	p := newPresence(t, true)
+	err := repo.Save(context.Background(), p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{
+			desc: "retrieve existing presence",
+			id:   p.ThingID,
+			err:  nil,
+		},
+		{
+			desc: "retrieve non-existing presence",
+			id:   "non-existing",
+			err:  presence.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := repo.RetrieveByID(context.Background(), tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestPresenceRetrieveAll(t *testing.T) {
+	repo := redis.NewPresenceRepository(redisClient)
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		p := newPresence(t, true)
+		err := repo.Save(context.Background(), p)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		ids = append(ids, p.ThingID)
+	}
+
+	cases := []struct {
+		desc string
+		ids  []string
+		size int
+	}{
+		{
+			desc: "retrieve all presences",
+			ids:  ids,
+			size: 3,
+		},
+		{
+			desc: "retrieve presences with non-existing ones",
+			ids:  append([]string{"non-existing"}, ids[:2]...),
+			size: 2,
+		},
+		{
+			desc: "retrieve presences without IDs",
+			ids:  nil,
+			size: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		ps, err := repo.RetrieveAll(context.Background(), tc.ids...)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.size, len(ps), fmt.Sprintf("%s: expected %d presences got %d", tc.desc, tc.size, len(ps)))
+	}
+}
+
+func TestPresenceRetrieveOnline(t *testing.T) {
+	repo := redis.NewPresenceRepository(redisClient)
+	online := newPresence(t, true)
+	offline := newPresence(t, false)
+	for _, p := range []presence.Presence{online, offline} {
+		err := repo.Save(context.Background(), p)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	ps, err := repo.RetrieveOnline(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	found := map[string]bool{}
+	for _, p := range ps {
+		assert.True(t, p.Online, fmt.Sprintf("retrieve online presences: expected thing %s to be online", p.ThingID))
+		found[p.ThingID] = true
+	}
+	assert.True(t, found[online.ThingID], "retrieve online presences: expected online thing to be retrieved")
+	assert.False(t, found[offline.ThingID], "retrieve online presences: expected offline thing not to be retrieved")
+}
+
+func TestPresenceRemove(t *testing.T) {
+	repo := redis.NewPresenceRepository(redisClient)
+	p := newPresence(t, true)
+	err := repo.Save(context.Background(), p)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for i := 0; i < 2; i++ {
+		err := repo.Remove(context.Background(), p.ThingID)
+		assert.Nil(t, err, fmt.Sprintf("#%d: remove presence: unexpected error: %s", i, err))
+
+		_, err = repo.RetrieveByID(context.Background(), p.ThingID)
+		assert.Equal(t, presence.ErrNotFound, err, fmt.Sprintf("#%d: remove presence: expected %s got %s", i, presence.ErrNotFound, err))
+	}
+
+	ps, err := repo.RetrieveOnline(context.Background())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	for _, o := range ps {
+		assert.NotEqual(t, p.ThingID, o.ThingID, "remove presence: expected removed thing not to be online")
+	}
+}
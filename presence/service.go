@@ -0,0 +1,301 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// negative inactivity timeout).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrThings indicates failure to communicate with Mainflux Things service.
+	ErrThings = errors.New("failed to receive response from Things service")
+)
+
+// Service specifies an API that must be fullfiled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// ViewPresence retrieves the presence of the thing identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	ViewPresence(context.Context, string, string) (Presence, error)
+
+	// ListPresence retrieves the presences of the subset of things that
+	// belong to the user identified by the provided key.
+	ListPresence(context.Context, string, uint64, uint64) (Page, error)
+
+	// UpdateTimeout sets the inactivity timeout of the thing identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key. The zero timeout restores the default one.
+	UpdateTimeout(context.Context, string, string, time.Duration) error
+
+	// Connect marks the thing online and publishes the connect event.
+	Connect(context.Context, Activity) error
+
+	// Disconnect marks the thing offline and publishes the disconnect event.
+	Disconnect(context.Context, Activity) error
+
+	// Seen updates the last seen time of the thing. The activity event is
+	// published if the thing was offline.
+	Seen(context.Context, Activity) error
+
+	// CheckInactivity marks offline the things which weren't seen for longer
+	// than their inactivity timeout at the given time, and publishes the
+	// inactivity events.
+	CheckInactivity(context.Context, time.Time) error
+
+	// Remove removes the presence of the removed thing identified by the
+	// provided ID.
+	Remove(context.Context, string) error
+}
+
+var _ Service = (*presenceService)(nil)
+
+type presenceService struct {
+	presences Repository
+	sdk       mfsdk.SDK
+	publisher Publisher
+	timeout   time.Duration
+	mu        sync.Mutex
+}
+
+// New instantiates the presence service implementation. The things are
+// inactive if they aren't seen for the timeout, unless their own timeout is
+// set.
+func New(presences Repository, sdk mfsdk.SDK, publisher Publisher, timeout time.Duration) Service {
+	return &presenceService{
+		presences: presences,
+		sdk:       sdk,
+		publisher: publisher,
+		timeout:   timeout,
+	}
+}
+
+func (ps *presenceService) ViewPresence(ctx context.Context, token, id string) (Presence, error) {
+	if err := ps.authorize(token, id); err != nil {
+		return Presence{}, err
+	}
+
+	p, err := ps.presence(ctx, id)
+	if err != nil {
+		return Presence{}, err
+	}
+
+	return ps.effective(p), nil
+}
+
+func (ps *presenceService) ListPresence(ctx context.Context, token string, offset, limit uint64) (Page, error) {
+	tp, err := ps.sdk.Things(token, offset, limit, "")
+	if err != nil {
+		return Page{}, thingsError(err)
+	}
+
+	ids := make([]string, len(tp.Things))
+	for i, th := range tp.Things {
+		ids[i] = th.ID
+	}
+
+	saved, err := ps.presences.RetrieveAll(ctx, ids...)
+	if err != nil {
+		return Page{}, err
+	}
+	byID := make(map[string]Presence, len(saved))
+	for _, p := range saved {
+		byID[p.ThingID] = p
+	}
+
+	page := Page{
+		PageMetadata: PageMetadata{
+			Total:  tp.Total,
+			Offset: tp.Offset,
+			Limit:  tp.Limit,
+		},
+		Presences: []Presence{},
+	}
+	for _, id := range ids {
+		p, ok := byID[id]
+		if !ok {
+			p = Presence{ThingID: id}
+		}
+		page.Presences = append(page.Presences, ps.effective(p))
+	}
+
+	return page, nil
+}
+
+func (ps *presenceService) UpdateTimeout(ctx context.Context, token, id string, timeout time.Duration) error {
+	if timeout < 0 {
+		return ErrMalformedEntity
+	}
+
+	if err := ps.authorize(token, id); err != nil {
+		return err
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	p, err := ps.presence(ctx, id)
+	if err != nil {
+		return err
+	}
+	p.Timeout = timeout
+
+	return ps.presences.Save(ctx, p)
+}
+
+func (ps *presenceService) Connect(ctx context.Context, a Activity) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	p, err := ps.presence(ctx, a.ThingID)
+	if err != nil {
+		return err
+	}
+	p.Online = true
+	p.Protocol = a.Protocol
+	p.RemoteAddr = a.RemoteAddr
+	p.ConnectedAt = a.Time
+	if a.Time.After(p.LastSeen) {
+		p.LastSeen = a.Time
+	}
+
+	return ps.save(ctx, ConnectEvent, p)
+}
+
+func (ps *presenceService) Disconnect(ctx context.Context, a Activity) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	p, err := ps.presence(ctx, a.ThingID)
+	if err != nil {
+		return err
+	}
+	p.Online = false
+	if a.Time.After(p.LastSeen) {
+		p.LastSeen = a.Time
+	}
+
+	return ps.save(ctx, DisconnectEvent, p)
+}
+
+func (ps *presenceService) Seen(ctx context.Context, a Activity) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	p, err := ps.presence(ctx, a.ThingID)
+	if err != nil {
+		return err
+	}
+	// The late messages don't change the presence.
+	if a.Time.Before(p.LastSeen) {
+		return nil
+	}
+
+	p.Protocol = a.Protocol
+	p.RemoteAddr = a.RemoteAddr
+	p.LastSeen = a.Time
+	if p.Online {
+		return ps.presences.Save(ctx, p)
+	}
+
+	p.Online = true
+	p.ConnectedAt = a.Time
+	return ps.save(ctx, ActivityEvent, p)
+}
+
+func (ps *presenceService) CheckInactivity(ctx context.Context, now time.Time) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	online, err := ps.presences.RetrieveOnline(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range online {
+		if now.Sub(p.LastSeen) <= ps.effective(p).Timeout {
+			continue
+		}
+
+		p.Online = false
+		if err := ps.save(ctx, InactivityEvent, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ps *presenceService) Remove(ctx context.Context, id string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return ps.presences.Remove(ctx, id)
+}
+
+// save persists the presence and publishes the event of its change.
+func (ps *presenceService) save(ctx context.Context, typ string, p Presence) error {
+	if err := ps.presences.Save(ctx, p); err != nil {
+		return err
+	}
+
+	return ps.publisher.Publish(ctx, Event{Type: typ, Presence: ps.effective(p)})
+}
+
+// presence retrieves the presence of the thing, the things which were never
+// seen are offline.
+func (ps *presenceService) presence(ctx context.Context, id string) (Presence, error) {
+	p, err := ps.presences.RetrieveByID(ctx, id)
+	if err == ErrNotFound {
+		return Presence{ThingID: id}, nil
+	}
+
+	return p, err
+}
+
+// effective sets the timeout of the presence to the default one unless the
+// thing has its own timeout.
+func (ps *presenceService) effective(p Presence) Presence {
+	if p.Timeout == 0 {
+		p.Timeout = ps.timeout
+	}
+
+	return p
+}
+
+func (ps *presenceService) authorize(token, id string) error {
+	if _, err := ps.sdk.Thing(id, token); err != nil {
+		return thingsError(err)
+	}
+
+	return nil
+}
+
+func thingsError(err error) error {
+	switch err {
+	case mfsdk.ErrInvalidArgs:
+		return ErrMalformedEntity
+	case mfsdk.ErrUnauthorized:
+		return ErrUnauthorizedAccess
+	case mfsdk.ErrNotFound:
+		return ErrNotFound
+	default:
+		return errors.Wrap(ErrThings, err)
+	}
+}
@@ -0,0 +1,358 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package presence_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/presence"
+	"github.com/cloustone/pandas/presence/mocks"
+	mfsdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/cloustone/pandas/things"
+	httpapi "github.com/cloustone/pandas/things/api/things/http"
+	thmocks "github.com/cloustone/pandas/things/mocks"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	wrongValue = "wrong-value"
+	email      = "user@example.com"
+	protocol   = "http"
+	remoteAddr = "10.0.0.1:50000"
+	timeout    = time.Minute
+)
+
+func newThingsService(tokens map[string]string) things.Service {
+	auth := thmocks.NewAuthService(tokens)
+	conns := make(chan thmocks.Connection)
+	thingsRepo := thmocks.NewThingRepository(conns)
+	channelsRepo := thmocks.NewChannelRepository(thingsRepo, conns)
+	chanCache := thmocks.NewChannelCache()
+	thingCache := thmocks.NewThingCache()
+	idp := thmocks.NewIdentityProvider()
+
+	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idp)
+}
+
+func newThingsServer(svc things.Service) *httptest.Server {
+	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	return httptest.NewServer(mux)
+}
+
+func newService(url string, pub presence.Publisher) presence.Service {
+	sdk := mfsdk.NewSDK(mfsdk.Config{BaseURL: url})
+	return presence.New(mocks.NewPresenceRepository(), sdk, pub, timeout)
+}
+
+func createThing(t *testing.T, svc things.Service) string {
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "thing"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	return ths[0].ID
+}
+
+func TestViewPresence(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(tsvc)
+	defer ts.Close()
+	svc := newService(ts.URL, mocks.NewPublisher())
+
+	seenID := createThing(t, tsvc)
+	unseenID := createThing(t, tsvc)
+	now := time.Now().Round(0)
+	err := svc.Seen(context.Background(), presence.Activity{ThingID: seenID, Protocol: protocol, RemoteAddr: remoteAddr, Time: now})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		id       string
+		presence presence.Presence
+		err      error
+	}{
+		{
+			desc:     "view presence of seen thing",
+			token:    token,
+			id:       seenID,
+			presence: presence.Presence{ThingID: seenID, Online: true, Protocol: protocol, RemoteAddr: remoteAddr, ConnectedAt: now, LastSeen: now, Timeout: timeout},
+			err:      nil,
+		},
+		{
+			desc:     "view presence of never seen thing",
+			token:    token,
+			id:       unseenID,
+			presence: presence.Presence{ThingID: unseenID, Timeout: timeout},
+			err:      nil,
+		},
+		{
+			desc:     "view presence with wrong credentials",
+			token:    wrongValue,
+			id:       seenID,
+			presence: presence.Presence{},
+			err:      presence.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "view presence of non-existing thing",
+			token:    token,
+			id:       wrongValue,
+			presence: presence.Presence{},
+			err:      presence.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := svc.ViewPresence(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.presence, p, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.presence, p))
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestListPresence(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(tsvc)
+	defer ts.Close()
+	svc := newService(ts.URL, mocks.NewPublisher())
+
+	n := 5
+	for i := 0; i < n; i++ {
+		id := createThing(t, tsvc)
+		if i%2 == 0 {
+			err := svc.Seen(context.Background(), presence.Activity{ThingID: id, Protocol: protocol, Time: time.Now()})
+			require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		}
+	}
+
+	cases := []struct {
+		desc   string
+		token  string
+		offset uint64
+		limit  uint64
+		size   int
+		online int
+		err    error
+	}{
+		{
+			desc:   "list all presences",
+			token:  token,
+			offset: 0,
+			limit:  10,
+			size:   n,
+			online: 3,
+			err:    nil,
+		},
+		{
+			desc:   "list last presences",
+			token:  token,
+			offset: 3,
+			limit:  10,
+			size:   2,
+			online: 1,
+			err:    nil,
+		},
+		{
+			desc:   "list presences with wrong credentials",
+			token:  wrongValue,
+			offset: 0,
+			limit:  10,
+			size:   0,
+			online: 0,
+			err:    presence.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListPresence(context.Background(), tc.token, tc.offset, tc.limit)
+		online := 0
+		for _, p := range page.Presences {
+			if p.Online {
+				online++
+			}
+		}
+		assert.Equal(t, tc.size, len(page.Presences), fmt.Sprintf("%s: expected %d presences got %d", tc.desc, tc.size, len(page.Presences)))
+		assert.Equal(t, tc.online, online, fmt.Sprintf("%s: expected %d online things got %d", tc.desc, tc.online, online))
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestUpdateTimeout(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(tsvc)
+	defer ts.Close()
+	svc := newService(ts.URL, mocks.NewPublisher())
+
+	id := createThing(t, tsvc)
+
+	cases := []struct {
+		desc    string
+		token   string
+		id      string
+		timeout time.Duration
+		want    time.Duration
+		err     error
+	}{
+		{
+			desc:    "update timeout",
+			token:   token,
+			id:      id,
+			timeout: time.Hour,
+			want:    time.Hour,
+			err:     nil,
+		},
+		{
+			desc:    "restore default timeout",
+			token:   token,
+			id:      id,
+			timeout: 0,
+			want:    timeout,
+			err:     nil,
+		},
+		{
+			desc:    "update timeout with negative value",
+			token:   token,
+			id:      id,
+			timeout: -time.Second,
+			want:    timeout,
+			err:     presence.ErrMalformedEntity,
+		},
+		{
+			desc:    "update timeout with wrong credentials",
+			token:   wrongValue,
+			id:      id,
+			timeout: time.Hour,
+			want:    timeout,
+			err:     presence.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateTimeout(context.Background(), tc.token, tc.id, tc.timeout)
+		assert.True(t, errors.Contains(err, tc.err) || err == tc.err, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		p, err := svc.ViewPresence(context.Background(), token, id)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.want, p.Timeout, fmt.Sprintf("%s: expected timeout %s got %s", tc.desc, tc.want, p.Timeout))
+	}
+}
+
+func TestEvents(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(tsvc)
+	defer ts.Close()
+	pub := mocks.NewPublisher()
+	svc := newService(ts.URL, pub)
+
+	id := createThing(t, tsvc)
+	err := svc.UpdateTimeout(context.Background(), token, id, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	other := createThing(t, tsvc)
+	start := time.Now()
+
+	cases := []struct {
+		desc   string
+		apply  func() error
+		events []string
+		online bool
+	}{
+		{
+			desc: "connect thing",
+			apply: func() error {
+				return svc.Connect(context.Background(), presence.Activity{ThingID: id, Protocol: "mqtt", Time: start})
+			},
+			events: []string{presence.ConnectEvent},
+			online: true,
+		},
+		{
+			desc: "see online thing",
+			apply: func() error {
+				return svc.Seen(context.Background(), presence.Activity{ThingID: id, Protocol: "mqtt", Time: start.Add(time.Minute)})
+			},
+			events: nil,
+			online: true,
+		},
+		{
+			desc: "check inactivity before thing timeout",
+			apply: func() error {
+				return svc.CheckInactivity(context.Background(), start.Add(30*time.Minute))
+			},
+			events: nil,
+			online: true,
+		},
+		{
+			desc: "check inactivity after thing timeout",
+			apply: func() error {
+				return svc.CheckInactivity(context.Background(), start.Add(2*time.Hour))
+			},
+			events: []string{presence.InactivityEvent},
+			online: false,
+		},
+		{
+			desc: "see inactive thing",
+			apply: func() error {
+				return svc.Seen(context.Background(), presence.Activity{ThingID: id, Protocol: protocol, Time: start.Add(3 * time.Hour)})
+			},
+			events: []string{presence.ActivityEvent},
+			online: true,
+		},
+		{
+			desc: "see thing with late message",
+			apply: func() error {
+				return svc.Seen(context.Background(), presence.Activity{ThingID: id, Protocol: protocol, Time: start})
+			},
+			events: nil,
+			online: true,
+		},
+		{
+			desc: "disconnect thing",
+			apply: func() error {
+				return svc.Disconnect(context.Background(), presence.Activity{ThingID: id, Protocol: "mqtt", Time: start.Add(4 * time.Hour)})
+			},
+			events: []string{presence.DisconnectEvent},
+			online: false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.apply()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		var events []string
+		for _, e := range pub.Events() {
+			assert.Equal(t, id, e.Presence.ThingID, fmt.Sprintf("%s: expected event of thing %s got %s", tc.desc, id, e.Presence.ThingID))
+			events = append(events, e.Type)
+		}
+		assert.Equal(t, tc.events, events, fmt.Sprintf("%s: expected events %v got %v", tc.desc, tc.events, events))
+
+		p, err := svc.ViewPresence(context.Background(), token, id)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.online, p.Online, fmt.Sprintf("%s: expected online %t got %t", tc.desc, tc.online, p.Online))
+	}
+
+	p, err := svc.ViewPresence(context.Background(), token, other)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.False(t, p.Online, "expected never seen thing to stay offline")
+}
+
+func TestRemove(t *testing.T) {
+	tsvc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(tsvc)
+	defer ts.Close()
+	svc := newService(ts.URL, mocks.NewPublisher())
+
+	id := createThing(t, tsvc)
+	err := svc.Seen(context.Background(), presence.Activity{ThingID: id, Protocol: protocol, Time: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.Remove(context.Background(), id)
+	assert.Nil(t, err, fmt.Sprintf("remove presence: unexpected error: %s", err))
+
+	p, err := svc.ViewPresence(context.Background(), token, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.False(t, p.Online, "expected removed presence to be offline")
+}
@@ -71,7 +71,7 @@ func listRuleChainEndpoint(svc rulechain.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		page, err := svc.ListRuleChain(ctx, req.token, req.limit, req.offset)
+		page, err := svc.ListRuleChain(ctx, req.token, req.offset, req.limit)
 		if err != nil {
 			return nil, err
 		}
@@ -106,3 +106,62 @@ func updateRuleChainStatusEndpoint(svc rulechain.Service) endpoint.Endpoint {
 		return addRuleChainResponse{}, nil
 	}
 }
+
+func validateRuleChainEndpoint(svc rulechain.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(validateRuleChainReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.ValidateRuleChain(ctx, req.token, req.payload); err != nil {
+			return nil, err
+		}
+		return validateRuleChainRes{}, nil
+	}
+}
+
+func listNodeCategoriesEndpoint(svc rulechain.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listNodeCategoriesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		categories, err := svc.ListNodeCategories(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+		return nodeCategoriesRes{Categories: categories}, nil
+	}
+}
+
+func viewNodeConfigEndpoint(svc rulechain.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewNodeConfigReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		config, err := svc.ViewNodeConfig(ctx, req.token, req.name)
+		if err != nil {
+			return nil, err
+		}
+		return nodeConfigRes{Name: req.name, Config: config}, nil
+	}
+}
+
+func listDebugEventsEndpoint(svc rulechain.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDebugEventsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		events, err := svc.ListDebugEvents(ctx, req.token, req.RuleChainID, req.after)
+		if err != nil {
+			return nil, err
+		}
+		return debugEventsRes{Events: events}, nil
+	}
+}
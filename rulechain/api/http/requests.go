@@ -41,7 +41,7 @@ type updateRuleChainReq struct {
 }
 
 func (req updateRuleChainReq) validate() error {
-	if req.rulechain.UserID == "" {
+	if req.token == "" {
 		return rulechain.ErrUnauthorizedAccess
 	}
 	if req.rulechain.ID == "" {
@@ -62,3 +62,60 @@ func (req listRuleChainReq) validate() error {
 	}
 	return nil
 }
+
+type validateRuleChainReq struct {
+	token   string
+	payload []byte
+}
+
+func (req validateRuleChainReq) validate() error {
+	if req.token == "" {
+		return rulechain.ErrUnauthorizedAccess
+	}
+	if len(req.payload) == 0 {
+		return rulechain.ErrMalformedEntity
+	}
+	return nil
+}
+
+type listNodeCategoriesReq struct {
+	token string
+}
+
+func (req listNodeCategoriesReq) validate() error {
+	if req.token == "" {
+		return rulechain.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type viewNodeConfigReq struct {
+	token string
+	name  string
+}
+
+func (req viewNodeConfigReq) validate() error {
+	if req.token == "" {
+		return rulechain.ErrUnauthorizedAccess
+	}
+	if req.name == "" {
+		return rulechain.ErrMalformedEntity
+	}
+	return nil
+}
+
+type listDebugEventsReq struct {
+	token       string
+	RuleChainID string
+	after       uint64
+}
+
+func (req listDebugEventsReq) validate() error {
+	if req.token == "" {
+		return rulechain.ErrUnauthorizedAccess
+	}
+	if req.RuleChainID == "" {
+		return rulechain.ErrMalformedEntity
+	}
+	return nil
+}
@@ -24,7 +24,7 @@ func (res addRuleChainResponse) Headers() map[string]string { return map[string]
 func (res addRuleChainResponse) Empty() bool                { return true }
 
 type updateRuleChainResponse struct {
-	RuleChain rulechain.RuleChain `json:"rulechain,omitempty`
+	RuleChain rulechain.RuleChain `json:"rulechain,omitempty"`
 }
 
 func (res updateRuleChainResponse) Code() int                  { return http.StatusOK }
@@ -32,7 +32,7 @@ func (res updateRuleChainResponse) Headers() map[string]string { return map[stri
 func (res updateRuleChainResponse) Empty() bool                { return true }
 
 type rulechainResponse struct {
-	RuleChain rulechain.RuleChain `json:"rulechain,omitempty`
+	RuleChain rulechain.RuleChain `json:"rulechain,omitempty"`
 }
 
 func (r rulechainResponse) Code() int                  { return http.StatusOK }
@@ -41,12 +41,43 @@ func (r rulechainResponse) Empty() bool                { return r.RuleChain.ID =
 
 type rulechainPageRes struct {
 	pageRes
-	RuleChains []rulechain.RuleChain
+	RuleChains []rulechain.RuleChain `json:"rulechains"`
 }
 
 func (r rulechainPageRes) Code() int                  { return http.StatusOK }
 func (r rulechainPageRes) Headers() map[string]string { return map[string]string{} }
-func (r rulechainPageRes) Empty() bool                { return true }
+func (r rulechainPageRes) Empty() bool                { return false }
+
+type validateRuleChainRes struct{}
+
+func (res validateRuleChainRes) Code() int                  { return http.StatusOK }
+func (res validateRuleChainRes) Headers() map[string]string { return map[string]string{} }
+func (res validateRuleChainRes) Empty() bool                { return true }
+
+type nodeCategoriesRes struct {
+	Categories map[string][]string `json:"categories"`
+}
+
+func (res nodeCategoriesRes) Code() int                  { return http.StatusOK }
+func (res nodeCategoriesRes) Headers() map[string]string { return map[string]string{} }
+func (res nodeCategoriesRes) Empty() bool                { return false }
+
+type nodeConfigRes struct {
+	Name   string `json:"name"`
+	Config string `json:"config"`
+}
+
+func (res nodeConfigRes) Code() int                  { return http.StatusOK }
+func (res nodeConfigRes) Headers() map[string]string { return map[string]string{} }
+func (res nodeConfigRes) Empty() bool                { return false }
+
+type debugEventsRes struct {
+	Events []rulechain.DebugEvent `json:"events"`
+}
+
+func (res debugEventsRes) Code() int                  { return http.StatusOK }
+func (res debugEventsRes) Headers() map[string]string { return map[string]string{} }
+func (res debugEventsRes) Empty() bool                { return false }
 
 type errorRes struct {
 	Err string `json:"error"`
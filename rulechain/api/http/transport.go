@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/cloustone/pandas"
@@ -26,7 +28,15 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-const contentType = "application/json"
+const (
+	contentType = "application/json"
+
+	offsetKey = "offset"
+	limitKey  = "limit"
+	afterKey  = "after"
+	defOffset = 0
+	defLimit  = 10
+)
 
 var (
 	// ErrUnsupportedContentType indicates unacceptable or lack of Content-Type
@@ -34,6 +44,7 @@ var (
 	errMissingRefererHeader   = errors.New("missing referer header")
 	errInvalidToken           = errors.New("invalid token")
 	errNoTokenSupplied        = errors.New("no token supplied")
+	errInvalidQueryParams     = errors.New("invalid query params")
 	// ErrFailedDecode indicates failed to decode request body
 	ErrFailedDecode = errors.New("failed to decode request body")
 	logger          log.Logger
@@ -63,6 +74,34 @@ func MakeHandler(svc rulechain.Service, tracer opentracing.Tracer, l log.Logger)
 		opts...,
 	))
 
+	mux.Post("/rulechain/validate", kithttp.NewServer(
+		kitot.TraceServer(tracer, "validate_rulechain")(validateRuleChainEndpoint(svc)),
+		decodeValidateRuleChainRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/rulechain/:id/events", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_debug_events")(listDebugEventsEndpoint(svc)),
+		decodeListDebugEventsRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/nodes", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_node_categories")(listNodeCategoriesEndpoint(svc)),
+		decodeListNodeCategoriesRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/nodes/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_node_config")(viewNodeConfigEndpoint(svc)),
+		decodeViewNodeConfigRequest,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/rulechain/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "rulechain_info")(rulechainInfoEndpoint(svc)),
 		decodeRuleChainRequest,
@@ -160,8 +199,20 @@ func decodeNewRuleChainRequest(_ context.Context, r *http.Request) (interface{},
 }
 
 func decodeListRuleChainRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := readUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := readUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
 	req := listRuleChainReq{
-		token: r.Header.Get("Authorization"),
+		token:  r.Header.Get("Authorization"),
+		offset: o,
+		limit:  l,
 	}
 	return req, nil
 }
@@ -169,7 +220,7 @@ func decodeListRuleChainRequest(_ context.Context, r *http.Request) (interface{}
 func decodeRuleChainRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	req := RuleChainInfoRequest{
 		token:       r.Header.Get("Authorization"),
-		RuleChainID: r.Header.Get("RuleChainID"),
+		RuleChainID: bone.GetValue(r, "id"),
 	}
 	return req, nil
 }
@@ -177,18 +228,65 @@ func decodeRuleChainRequest(_ context.Context, r *http.Request) (interface{}, er
 func decodeUpdateRuleChainStatusRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	req := updateRuleChainStatusRequest{
 		token:        r.Header.Get("Authorization"),
-		RuleChainID:  r.Header.Get("RuleChainID"),
+		RuleChainID:  bone.GetValue(r, "id"),
 		updatestatus: r.Header.Get("updatestatus"),
 	}
 	return req, nil
 }
 
+func decodeValidateRuleChainRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, ErrUnsupportedContentType
+	}
+
+	payload, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedDecode, err)
+	}
+
+	req := validateRuleChainReq{
+		token:   r.Header.Get("Authorization"),
+		payload: payload,
+	}
+	return req, nil
+}
+
+func decodeListNodeCategoriesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listNodeCategoriesReq{
+		token: r.Header.Get("Authorization"),
+	}
+	return req, nil
+}
+
+func decodeViewNodeConfigRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewNodeConfigReq{
+		token: r.Header.Get("Authorization"),
+		name:  bone.GetValue(r, "name"),
+	}
+	return req, nil
+}
+
+func decodeListDebugEventsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	after, err := readUintQuery(r, afterKey, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listDebugEventsReq{
+		token:       r.Header.Get("Authorization"),
+		RuleChainID: bone.GetValue(r, "id"),
+		after:       after,
+	}
+	return req, nil
+}
+
 func decodeUpdateRuleChainRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	ruleChain := rulechain.RuleChain{}
 	if err := json.NewDecoder(r.Body).Decode(&ruleChain); err != nil {
 		logger.Warn(fmt.Sprintf("Failed to decode rulechain: %s", err))
 		return nil, err
 	}
+	ruleChain.ID = bone.GetValue(r, "id")
 	req := updateRuleChainReq{
 		token:     r.Header.Get("Authorization"),
 		rulechain: ruleChain,
@@ -196,6 +294,24 @@ func decodeUpdateRuleChainRequest(_ context.Context, r *http.Request) (interface
 	return req, nil
 }
 
+func readUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
+	vals := bone.GetQuery(r, key)
+	if len(vals) > 1 {
+		return 0, errInvalidQueryParams
+	}
+
+	if len(vals) == 0 {
+		return def, nil
+	}
+
+	val, err := strconv.ParseUint(vals[0], 10, 64)
+	if err != nil {
+		return 0, errInvalidQueryParams
+	}
+
+	return val, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	if ar, ok := response.(mainflux.Response); ok {
 		for k, v := range ar.Headers() {
@@ -216,6 +332,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	switch errorVal := err.(type) {
 	case errors.Error:
 		w.Header().Set("Content-Type", contentType)
+		msg := errorVal.Msg()
 		switch {
 		case errors.Contains(errorVal, rulechain.ErrMalformedEntity):
 			w.WriteHeader(http.StatusBadRequest)
@@ -235,11 +352,21 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 			w.WriteHeader(http.StatusBadRequest)
 		case errors.Contains(errorVal, rulechain.ErrRuleChainNotFound):
 			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, rulechain.ErrInvalidManifest):
+			w.WriteHeader(http.StatusBadRequest)
+			// Report the reasons so that users are able to fix the manifest
+			msg = errorVal.Error()
+		case errors.Contains(errorVal, errInvalidQueryParams):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, rulechain.ErrNodeNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Contains(errorVal, rulechain.ErrRuleChainNotStarted):
+			w.WriteHeader(http.StatusConflict)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
-		if errorVal.Msg() != "" {
-			if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+		if msg != "" {
+			if err := json.NewEncoder(w).Encode(errorRes{Err: msg}); err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 		}
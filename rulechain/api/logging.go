@@ -104,6 +104,58 @@ func (lm *loggingMiddleware) UpdateRuleChainStatus(ctx context.Context, token st
 	return lm.svc.UpdateRuleChainStatus(ctx, token, RuleChainID, updatestatus)
 }
 
+func (lm *loggingMiddleware) ValidateRuleChain(ctx context.Context, token string, payload []byte) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method validaterulechain took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ValidateRuleChain(ctx, token, payload)
+}
+
+func (lm *loggingMiddleware) ListNodeCategories(ctx context.Context, token string) (categories map[string][]string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method listnodecategories took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListNodeCategories(ctx, token)
+}
+
+func (lm *loggingMiddleware) ViewNodeConfig(ctx context.Context, token string, name string) (config string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method viewnodeconfig for node %s took %s to complete", name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewNodeConfig(ctx, token, name)
+}
+
+func (lm *loggingMiddleware) ListDebugEvents(ctx context.Context, token string, RuleChainID string, after uint64) (events []rulechain.DebugEvent, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method listdebugevents for rulechain %s took %s to complete", RuleChainID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDebugEvents(ctx, token, RuleChainID, after)
+}
+
 func (lm *loggingMiddleware) SaveStates(msg *mainflux.Message) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method savesates took %s to complete", time.Since(begin))
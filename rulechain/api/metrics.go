@@ -84,6 +84,42 @@ func (ms *metricsMiddleware) UpdateRuleChainStatus(ctx context.Context, token st
 	return ms.svc.UpdateRuleChainStatus(ctx, token, RuleChainID, updatestatus)
 }
 
+func (ms *metricsMiddleware) ValidateRuleChain(ctx context.Context, token string, payload []byte) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "validaterulechain").Add(1)
+		ms.latency.With("method", "validaterulechain").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ValidateRuleChain(ctx, token, payload)
+}
+
+func (ms *metricsMiddleware) ListNodeCategories(ctx context.Context, token string) (map[string][]string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "listnodecategories").Add(1)
+		ms.latency.With("method", "listnodecategories").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListNodeCategories(ctx, token)
+}
+
+func (ms *metricsMiddleware) ViewNodeConfig(ctx context.Context, token string, name string) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "viewnodeconfig").Add(1)
+		ms.latency.With("method", "viewnodeconfig").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewNodeConfig(ctx, token, name)
+}
+
+func (ms *metricsMiddleware) ListDebugEvents(ctx context.Context, token string, RuleChainID string, after uint64) ([]rulechain.DebugEvent, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "listdebugevents").Add(1)
+		ms.latency.With("method", "listdebugevents").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDebugEvents(ctx, token, RuleChainID, after)
+}
+
 func (ms *metricsMiddleware) SaveStates(msg *mainflux.Message) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "savestates").Add(1)
@@ -14,8 +14,11 @@ package rulechain
 import (
 	"fmt"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/cloustone/pandas/rulechain/manifest"
+	"github.com/cloustone/pandas/rulechain/message"
 	"github.com/cloustone/pandas/rulechain/nodes"
 	"github.com/sirupsen/logrus"
 )
@@ -32,8 +35,14 @@ type ruleChainInstance struct {
 	subTopic        string
 	configuration   map[string]interface{}
 	nodes           map[string]nodes.Node
+	mutex           sync.Mutex
+	events          []DebugEvent
+	seq             uint64
 }
 
+// maxDebugEvents is the number of debug events kept for each rulechain
+const maxDebugEvents = 256
+
 func newRuleChainInstance(Channel string, SubTopic string, data []byte) (*ruleChainInstance, []error) {
 	errors := []error{}
 
@@ -107,3 +116,45 @@ func newInstanceWithManifest(Channel string, SubTopic string, m *manifest.Manife
 
 	return r, errs
 }
+
+// addDebugEvent record the message handled by node when the rulechain is in
+// debug mode, the oldest events are dropped when buffer is full
+func (r *ruleChainInstance) addDebugEvent(rulechainID string, node string, msg message.Message, err error) {
+	if !r.debugMode {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.seq++
+	event := DebugEvent{
+		Seq:         r.seq,
+		RuleChainID: rulechainID,
+		Node:        node,
+		MessageID:   msg.GetID(),
+		MessageType: msg.GetType(),
+		Payload:     string(msg.GetPayload()),
+		Created:     time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if len(r.events) >= maxDebugEvents {
+		r.events = r.events[1:]
+	}
+	r.events = append(r.events, event)
+}
+
+// debugEvents return debug events whose sequence is after the specified one
+func (r *ruleChainInstance) debugEvents(after uint64) []DebugEvent {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	events := []DebugEvent{}
+	for _, event := range r.events {
+		if event.Seq > after {
+			events = append(events, event)
+		}
+	}
+	return events
+}
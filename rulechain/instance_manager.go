@@ -16,7 +16,10 @@ import (
 	"sync"
 
 	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/presence"
 	"github.com/cloustone/pandas/rulechain/message"
+	"github.com/cloustone/pandas/rulechain/nodes"
 	logr "github.com/sirupsen/logrus"
 )
 
@@ -80,11 +83,60 @@ func (c *instanceManager) deleteRuleChain(rulechain *RuleChain) error {
 	return nil
 }
 
+// debugEvents return the debug events recorded by a running rule chain
+func (c *instanceManager) debugEvents(rulechainID string, after uint64) ([]DebugEvent, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	instance, found := c.rulechains[rulechainID]
+	if !found {
+		return nil, fmt.Errorf("rule chain '%s' no exist", rulechainID)
+	}
+	return instance.debugEvents(after), nil
+}
+
+// newRuleChainMessage converts the received message to the rule chain one,
+// its headers and creation time become the message metadata.
+// The presence events are typed by their event type header, the other
+// messages are telemetry.
+func newRuleChainMessage(msg *mainflux.Message) message.Message {
+	metadata := message.NewMetadata()
+	for key, val := range msg.GetHeaders() {
+		metadata.SetKeyValue(key, val)
+	}
+	if msg.GetCreated() > 0 {
+		metadata.SetKeyValue(message.MetadataTimestamp, msg.GetCreated())
+	}
+	return message.NewMessageWithDetail(msg.GetId(), msg.GetPublisher(), messageType(msg), msg.GetPayload(), metadata)
+}
+
+var presenceMessageTypes = map[string]string{
+	presence.ConnectEvent:    message.MessageTypeConnectEvent,
+	presence.DisconnectEvent: message.MessageTypeDisconnectEvent,
+	presence.ActivityEvent:   message.MessageTypeActivityEvent,
+	presence.InactivityEvent: message.MessageTypeInactivityEvent,
+}
+
+func messageType(msg *mainflux.Message) string {
+	if typ, ok := presenceMessageTypes[msg.GetHeaders()[broker.HeaderEventType]]; ok {
+		return typ
+	}
+	return message.MessageTypePostTelemetryRequest
+}
+
+// HandleMessage passes the message to the first node of the rule chains
+// started on the message channel and subtopic
 func (c *instanceManager) HandleMessage(rulechainmessage message.Message, msg *mainflux.Message) error {
-	for _, rulechaininstance := range c.rulechains {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for id, rulechaininstance := range c.rulechains {
 		if rulechaininstance.channel == msg.GetChannel() && rulechaininstance.subTopic == msg.GetSubtopic() {
 			if node, found := rulechaininstance.nodes[rulechaininstance.firstRuleNodeId]; found {
-				go node.Handle(rulechainmessage)
+				go func(id string, instance *ruleChainInstance, node nodes.Node) {
+					err := node.Handle(rulechainmessage)
+					instance.addDebugEvent(id, node.Name(), rulechainmessage, err)
+				}(id, rulechaininstance, node)
 			}
 		}
 	}
@@ -12,8 +12,52 @@
 package rulechain
 
 import (
+	"fmt"
 	"testing"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/broker"
+	"github.com/cloustone/pandas/presence"
+	"github.com/cloustone/pandas/rulechain/message"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestOnboradcast(t *testing.T) {
 }
+
+func TestNewRuleChainMessage(t *testing.T) {
+	msg := &mainflux.Message{
+		Id:        "1",
+		Publisher: "thing",
+		Payload:   []byte(`[{"n":"temp","v":20}]`),
+		Created:   1584000000000000000,
+		Headers:   map[string]string{"trace_id": "abc"},
+	}
+
+	m := newRuleChainMessage(msg)
+	assert.Equal(t, msg.Id, m.GetID(), "expected message ID to be kept")
+	assert.Equal(t, msg.Publisher, m.GetOriginator(), "expected publisher to be the originator")
+	assert.Equal(t, msg.Payload, m.GetPayload(), "expected payload to be kept")
+	assert.Equal(t, "abc", m.GetMetadata().GetKeyValue("trace_id"), "expected headers in metadata")
+	assert.Equal(t, msg.Created, m.GetMetadata().GetKeyValue(message.MetadataTimestamp), "expected creation time in metadata")
+	assert.Equal(t, message.MessageTypePostTelemetryRequest, m.GetType(), "expected telemetry message type")
+}
+
+func TestNewRuleChainPresenceMessage(t *testing.T) {
+	cases := map[string]string{
+		presence.ConnectEvent:    message.MessageTypeConnectEvent,
+		presence.DisconnectEvent: message.MessageTypeDisconnectEvent,
+		presence.ActivityEvent:   message.MessageTypeActivityEvent,
+		presence.InactivityEvent: message.MessageTypeInactivityEvent,
+		"unknown":                message.MessageTypePostTelemetryRequest,
+	}
+
+	for event, typ := range cases {
+		msg := &mainflux.Message{
+			Publisher: "thing",
+			Headers:   map[string]string{broker.HeaderEventType: event},
+		}
+		m := newRuleChainMessage(msg)
+		assert.Equal(t, typ, m.GetType(), fmt.Sprintf("%s: expected %s message type got %s", event, typ, m.GetType()))
+	}
+}
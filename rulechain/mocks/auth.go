@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/rulechain"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.AuthNServiceClient = (*authServiceMock)(nil)
+
+type authServiceMock struct {
+	users map[string]string
+}
+
+// NewAuthService creates mock of users service.
+func NewAuthService(users map[string]string) mainflux.AuthNServiceClient {
+	return &authServiceMock{users}
+}
+
+func (svc authServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserID, error) {
+	if id, ok := svc.users[in.Value]; ok {
+		return &mainflux.UserID{Value: id}, nil
+	}
+	return nil, rulechain.ErrUnauthorizedAccess
+}
+
+func (svc authServiceMock) Issue(ctx context.Context, in *mainflux.IssueReq, opts ...grpc.CallOption) (*mainflux.Token, error) {
+	if id, ok := svc.users[in.GetIssuer()]; ok {
+		return &mainflux.Token{Value: id}, nil
+	}
+	return nil, rulechain.ErrUnauthorizedAccess
+}
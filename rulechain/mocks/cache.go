@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloustone/pandas/rulechain"
+)
+
+var _ rulechain.RuleChainCache = (*ruleChainCacheMock)(nil)
+
+type ruleChainCacheMock struct {
+	mu         sync.Mutex
+	rulechains map[string]string
+}
+
+// NewRuleChainCache returns mock cache instance.
+func NewRuleChainCache() rulechain.RuleChainCache {
+	return &ruleChainCacheMock{
+		rulechains: make(map[string]string),
+	}
+}
+
+func (rcc *ruleChainCacheMock) Save(_ context.Context, key, id string) error {
+	rcc.mu.Lock()
+	defer rcc.mu.Unlock()
+
+	rcc.rulechains[key] = id
+	return nil
+}
+
+func (rcc *ruleChainCacheMock) ID(_ context.Context, key string) (string, error) {
+	rcc.mu.Lock()
+	defer rcc.mu.Unlock()
+
+	id, ok := rcc.rulechains[key]
+	if !ok {
+		return "", rulechain.ErrNotFound
+	}
+	return id, nil
+}
+
+func (rcc *ruleChainCacheMock) Remove(_ context.Context, id string) error {
+	rcc.mu.Lock()
+	defer rcc.mu.Unlock()
+
+	for key, val := range rcc.rulechains {
+		if val == id {
+			delete(rcc.rulechains, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/cloustone/pandas/rulechain"
+)
+
+var _ rulechain.RuleChainRepository = (*ruleChainRepositoryMock)(nil)
+
+type ruleChainRepositoryMock struct {
+	mu         sync.Mutex
+	rulechains map[string]rulechain.RuleChain
+}
+
+// NewRuleChainRepository creates in-memory rulechain repository.
+func NewRuleChainRepository() rulechain.RuleChainRepository {
+	return &ruleChainRepositoryMock{
+		rulechains: make(map[string]rulechain.RuleChain),
+	}
+}
+
+func (repo *ruleChainRepositoryMock) Save(_ context.Context, rc rulechain.RuleChain) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := rc.UserID + "/" + rc.ID
+	if _, ok := repo.rulechains[key]; ok {
+		return rulechain.ErrConflict
+	}
+	repo.rulechains[key] = rc
+	return nil
+}
+
+func (repo *ruleChainRepositoryMock) Update(_ context.Context, rc rulechain.RuleChain) (rulechain.RuleChain, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	key := rc.UserID + "/" + rc.ID
+	if _, ok := repo.rulechains[key]; !ok {
+		return rulechain.RuleChain{}, rulechain.ErrNotFound
+	}
+	repo.rulechains[key] = rc
+	return rc, nil
+}
+
+func (repo *ruleChainRepositoryMock) Retrieve(_ context.Context, userID, id string) (rulechain.RuleChain, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	rc, ok := repo.rulechains[userID+"/"+id]
+	if !ok {
+		return rulechain.RuleChain{}, rulechain.ErrNotFound
+	}
+	return rc, nil
+}
+
+func (repo *ruleChainRepositoryMock) Revoke(_ context.Context, userID, id string) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	delete(repo.rulechains, userID+"/"+id)
+	return nil
+}
+
+func (repo *ruleChainRepositoryMock) List(_ context.Context, userID string, offset, limit uint64) (rulechain.RuleChainPage, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	items := []rulechain.RuleChain{}
+	for _, rc := range repo.rulechains {
+		if rc.UserID == userID {
+			items = append(items, rc)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+
+	page := rulechain.RuleChainPage{
+		PageMetadata: rulechain.PageMetadata{
+			Total:  uint64(len(items)),
+			Offset: offset,
+			Limit:  limit,
+		},
+		RuleChains: []rulechain.RuleChain{},
+	}
+	for i := offset; i < uint64(len(items)) && i < offset+limit; i++ {
+		page.RuleChains = append(page.RuleChains, items[i])
+	}
+	return page, nil
+}
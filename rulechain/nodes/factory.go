@@ -15,8 +15,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
-
-	"github.com/sirupsen/logrus"
+	"sync"
 )
 
 const (
@@ -45,8 +44,10 @@ var (
 	// allNodeCategories hold node's metadata by category
 	allNodeCategories map[string][]string = make(map[string][]string)
 
-	// allNodeConfigs hold node's config data using map to index node's metadata directlly
+	// allNodeConfigs hold node's config data using map to index node's metadata directlly,
+	// the configs are loaded from AssetPath when they are first requested
 	allNodeConfigs map[string]string = make(map[string]string)
+	configsMutex   sync.Mutex
 )
 
 // RegisterFactory add a new node factory and classify its category for
@@ -58,12 +59,6 @@ func RegisterFactory(f Factory) {
 		allNodeCategories[f.Category()] = []string{}
 	}
 	allNodeCategories[f.Category()] = append(allNodeCategories[f.Category()], f.Name())
-	configFile := AssetPath + "/" + f.Name() + ".js"
-	buf, err := ioutil.ReadFile(configFile)
-	if err != nil {
-		logrus.Fatalf("asset file '%s' no exist", configFile)
-	}
-	allNodeConfigs[f.Name()] = string(buf)
 }
 
 // NewNode is the only way to create a new node
@@ -75,15 +70,35 @@ func NewNode(nodeType string, id string, meta Metadata) (Node, error) {
 }
 
 // GetAllNodeConfigs returan all node's static description used by user to list nodes
-func GetAllNodeConfigs() map[string]string { return allNodeConfigs }
+func GetAllNodeConfigs() map[string]string {
+	configs := make(map[string]string)
+	for name := range allNodeFactories {
+		if c, err := GetNodeConfigs(name); err == nil {
+			configs[name] = c
+		}
+	}
+	return configs
+}
 
 // GetCategoryNodes return specified category's all nodes
 func GetCategoryNodes() map[string][]string { return allNodeCategories }
 
 // GetNodeMeta return a node's static metadata
 func GetNodeConfigs(name string) (string, error) {
+	if _, found := allNodeFactories[name]; !found {
+		return "", errors.New("not found")
+	}
+
+	configsMutex.Lock()
+	defer configsMutex.Unlock()
 	if c, found := allNodeConfigs[name]; found {
 		return c, nil
 	}
-	return "", errors.New("not found")
+	configFile := AssetPath + "/" + name + ".js"
+	buf, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return "", fmt.Errorf("asset file '%s' no exist", configFile)
+	}
+	allNodeConfigs[name] = string(buf)
+	return allNodeConfigs[name], nil
 }
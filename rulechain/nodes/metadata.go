@@ -44,6 +44,9 @@ func NewMetadataWithString(vals string) Metadata {
 }
 
 func NewMetadataWithValues(vals map[string]interface{}) Metadata {
+	if vals == nil {
+		vals = make(map[string]interface{})
+	}
 	return &nodeMetadata{
 		keypairs: vals,
 	}
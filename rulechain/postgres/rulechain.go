@@ -87,7 +87,7 @@ func (rr rulechainRepository) Revoke(ctx context.Context, UserID string, RuleCha
 }
 
 func (rr rulechainRepository) List(ctx context.Context, UserID string, offset uint64, limit uint64) (rulechain.RuleChainPage, error) {
-	q := `SELECT name, description, debugmode, userid, status, payload, root, createat, lastupdateat
+	q := `SELECT name, id, description, debugmode, userid, status, payload, root, createat, lastupdateat
 	FROM rulechain
 	WHERE userid = :userid ORDER BY id LIMIT :limit OFFSET :offset;`
 
@@ -38,6 +38,18 @@ type RuleChainPage struct {
 	RuleChains []RuleChain
 }
 
+// DebugEvent is a trace record produced by a rulechain running in debug mode
+type DebugEvent struct {
+	Seq         uint64    `json:"seq"`
+	RuleChainID string    `json:"rulechain_id"`
+	Node        string    `json:"node"`
+	MessageID   string    `json:"message_id"`
+	MessageType string    `json:"message_type"`
+	Payload     string    `json:"payload,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
 // Validate returns an error if representtation is invalid
 func (r RuleChain) Validate() error {
 	if r.ID == "" || r.UserID == "" {
@@ -2,9 +2,12 @@ package rulechain
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/cloustone/pandas/mainflux"
 	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/rulechain/nodes"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -43,6 +46,15 @@ var (
 
 	// ErrUnauthorizedPrincipal indicate the pricipal can not be recognized
 	ErrUnauthorizedPrincipal = errors.New("unauthorized principal")
+
+	// ErrInvalidManifest indicates the rulechain manifest can not be built
+	ErrInvalidManifest = errors.New("invalid rulechain manifest")
+
+	// ErrNodeNotFound indicates a non-existent node type request.
+	ErrNodeNotFound = errors.New("non-existent node type")
+
+	// ErrRuleChainNotStarted indicates the rulechain has no running instance
+	ErrRuleChainNotStarted = errors.New("rulechain not started")
 )
 
 //Service service
@@ -53,6 +65,10 @@ type Service interface {
 	RevokeRuleChain(context.Context, string, string) error
 	ListRuleChain(context.Context, string, uint64, uint64) (RuleChainPage, error)
 	UpdateRuleChainStatus(context.Context, string, string, string) error
+	ValidateRuleChain(context.Context, string, []byte) error
+	ListNodeCategories(context.Context, string) (map[string][]string, error)
+	ViewNodeConfig(context.Context, string, string) (string, error)
+	ListDebugEvents(context.Context, string, string, uint64) ([]DebugEvent, error)
 	SaveStates(*mainflux.Message) error
 }
 
@@ -62,12 +78,12 @@ type rulechainService struct {
 	auth       mainflux.AuthNServiceClient
 	rulechains RuleChainRepository
 	//mutex      sync.RWMutex
-	instanceManager instanceManager
+	instanceManager *instanceManager
 	rulechainsCache RuleChainCache
 }
 
 //New new
-func New(auth mainflux.AuthNServiceClient, rulechains RuleChainRepository, instancemanager instanceManager, rulechainscache RuleChainCache) Service {
+func New(auth mainflux.AuthNServiceClient, rulechains RuleChainRepository, instancemanager *instanceManager, rulechainscache RuleChainCache) Service {
 	return &rulechainService{
 		auth:            auth,
 		rulechains:      rulechains,
@@ -76,15 +92,20 @@ func New(auth mainflux.AuthNServiceClient, rulechains RuleChainRepository, insta
 	}
 }
 
-func (svc rulechainService) AddNewRuleChain(ctx context.Context, token string, rulechain RuleChain) error {
-	_, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+func (svc *rulechainService) AddNewRuleChain(ctx context.Context, token string, rulechain RuleChain) error {
+	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return err
 	}
+	rulechain.UserID = res.GetValue()
+	rulechain.Status = RULE_STATUS_CREATED
+	rulechain.CreateAt = time.Now()
+	rulechain.LastUpdateAt = rulechain.CreateAt
+
 	return svc.rulechains.Save(ctx, rulechain)
 }
 
-func (svc rulechainService) GetRuleChainInfo(ctx context.Context, token string, RuleChainID string) (RuleChain, error) {
+func (svc *rulechainService) GetRuleChainInfo(ctx context.Context, token string, RuleChainID string) (RuleChain, error) {
 	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return RuleChain{}, err
@@ -97,7 +118,7 @@ func (svc rulechainService) GetRuleChainInfo(ctx context.Context, token string,
 	return rulechain, nil
 }
 
-func (svc rulechainService) UpdateRuleChain(ctx context.Context, token string, rulechain RuleChain) (RuleChain, error) {
+func (svc *rulechainService) UpdateRuleChain(ctx context.Context, token string, rulechain RuleChain) (RuleChain, error) {
 
 	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -111,11 +132,15 @@ func (svc rulechainService) UpdateRuleChain(ctx context.Context, token string, r
 	if old_rulechain.Status == RULE_STATUS_STARTED {
 		return RuleChain{}, status.Error(codes.FailedPrecondition, "")
 	}
+	rulechain.UserID = res.GetValue()
+	rulechain.Status = old_rulechain.Status
+	rulechain.CreateAt = old_rulechain.CreateAt
+	rulechain.LastUpdateAt = time.Now()
 
 	return svc.rulechains.Update(ctx, rulechain)
 }
 
-func (svc rulechainService) RevokeRuleChain(ctx context.Context, token string, RuleChainID string) error {
+func (svc *rulechainService) RevokeRuleChain(ctx context.Context, token string, RuleChainID string) error {
 
 	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -133,7 +158,7 @@ func (svc rulechainService) RevokeRuleChain(ctx context.Context, token string, R
 	return svc.rulechains.Revoke(ctx, res.GetValue(), RuleChainID)
 }
 
-func (svc rulechainService) ListRuleChain(ctx context.Context, token string, offset uint64, limit uint64) (RuleChainPage, error) {
+func (svc *rulechainService) ListRuleChain(ctx context.Context, token string, offset uint64, limit uint64) (RuleChainPage, error) {
 
 	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
@@ -143,7 +168,7 @@ func (svc rulechainService) ListRuleChain(ctx context.Context, token string, off
 	return svc.rulechains.List(ctx, res.GetValue(), offset, limit)
 }
 
-func (svc rulechainService) UpdateRuleChainStatus(ctx context.Context, token string, RuleChainID string, updatestatus string) error {
+func (svc *rulechainService) UpdateRuleChainStatus(ctx context.Context, token string, RuleChainID string, updatestatus string) error {
 	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return err
@@ -159,25 +184,78 @@ func (svc rulechainService) UpdateRuleChainStatus(ctx context.Context, token str
 			return status.Error(codes.FailedPrecondition, "")
 		}
 
-		return svc.instanceManager.startRuleChain(&rulechain)
+		if err := svc.instanceManager.startRuleChain(&rulechain); err != nil {
+			return err
+		}
 	case UPDATE_RULE_STATUS_STOP:
 		if rulechain.Status != RULE_STATUS_STARTED {
 			return status.Error(codes.FailedPrecondition, "")
 		}
 
-		return svc.instanceManager.stopRuleChain(&rulechain)
+		if err := svc.instanceManager.stopRuleChain(&rulechain); err != nil {
+			return err
+		}
+	default:
+		return nil
 	}
-	return nil
+
+	// The instance manager only changes the status of the retrieved copy
+	rulechain.LastUpdateAt = time.Now()
+	_, err = svc.rulechains.Update(ctx, rulechain)
+	return err
 }
 
-func (svc rulechainService) SaveStates(msg *mainflux.Message) error {
-	/*
-		rulechainmessage := message.NewMessage()
-		if err := rulechainmessage.UnmarshalBinary(msg.GetPayload()); err != nil {
-			logrus.WithError(err).Errorf("rulechain instance receive message failed")
-			return err
+func (svc *rulechainService) ValidateRuleChain(ctx context.Context, token string, payload []byte) error {
+	if _, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
+		return err
+	}
+
+	_, errs := newRuleChainInstance("", "", payload)
+	if len(errs) > 0 {
+		reasons := []string{}
+		for _, err := range errs {
+			reasons = append(reasons, err.Error())
 		}
-		return svc.instanceManager.HandleMessage(rulechainmessage, msg)
-	*/
+		return errors.Wrap(ErrInvalidManifest, errors.New(strings.Join(reasons, "; ")))
+	}
 	return nil
 }
+
+func (svc *rulechainService) ListNodeCategories(ctx context.Context, token string) (map[string][]string, error) {
+	if _, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
+		return nil, err
+	}
+	return nodes.GetCategoryNodes(), nil
+}
+
+func (svc *rulechainService) ViewNodeConfig(ctx context.Context, token string, name string) (string, error) {
+	if _, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
+		return "", err
+	}
+
+	config, err := nodes.GetNodeConfigs(name)
+	if err != nil {
+		return "", errors.Wrap(ErrNodeNotFound, err)
+	}
+	return config, nil
+}
+
+func (svc *rulechainService) ListDebugEvents(ctx context.Context, token string, RuleChainID string, after uint64) ([]DebugEvent, error) {
+	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := svc.rulechains.Retrieve(ctx, res.GetValue(), RuleChainID); err != nil {
+		return nil, errors.Wrap(ErrRuleChainNotFound, err)
+	}
+
+	events, err := svc.instanceManager.debugEvents(RuleChainID, after)
+	if err != nil {
+		return nil, errors.Wrap(ErrRuleChainNotStarted, err)
+	}
+	return events, nil
+}
+
+func (svc *rulechainService) SaveStates(msg *mainflux.Message) error {
+	return svc.instanceManager.HandleMessage(newRuleChainMessage(msg), msg)
+}
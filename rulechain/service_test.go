@@ -0,0 +1,250 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package rulechain_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/pkg/errors"
+	"github.com/cloustone/pandas/rulechain"
+	"github.com/cloustone/pandas/rulechain/mocks"
+	"github.com/cloustone/pandas/rulechain/nodes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	token     = "token"
+	wrong     = "wrong-value"
+	email     = "user@example.com"
+	chanID    = "1"
+	subtopic  = "temperature"
+	assetPath = "assets"
+)
+
+const manifest = `{
+	"ruleChain": {"name": "test", "firstRuleNodeId": "0", "debugMode": true},
+	"metadata": {"nodes": [{"type": "InputNode", "name": "0"}]}
+}`
+
+func newService(tokens map[string]string) rulechain.Service {
+	nodes.AssetPath = assetPath
+	auth := mocks.NewAuthService(tokens)
+	repo := mocks.NewRuleChainRepository()
+	cache := mocks.NewRuleChainCache()
+	return rulechain.New(auth, repo, rulechain.NewInstanceManager(), cache)
+}
+
+func TestValidateRuleChain(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	cases := map[string]struct {
+		payload []byte
+		token   string
+		err     error
+	}{
+		"validate valid manifest": {
+			payload: []byte(manifest),
+			token:   token,
+			err:     nil,
+		},
+		"validate malformed manifest": {
+			payload: []byte("{"),
+			token:   token,
+			err:     rulechain.ErrInvalidManifest,
+		},
+		"validate manifest with unknown node type": {
+			payload: []byte(`{"metadata": {"nodes": [{"type": "UnknownNode", "name": "0"}]}}`),
+			token:   token,
+			err:     rulechain.ErrInvalidManifest,
+		},
+		"validate manifest with invalid token": {
+			payload: []byte(manifest),
+			token:   wrong,
+			err:     rulechain.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.ValidateRuleChain(context.Background(), tc.token, tc.payload)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestListNodeCategories(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	categories, err := svc.ListNodeCategories(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, categories[nodes.NODE_CATEGORY_OTHERS], "InputNode", "expected input node in others category")
+
+	_, err = svc.ListNodeCategories(context.Background(), wrong)
+	assert.True(t, errors.Contains(err, rulechain.ErrUnauthorizedAccess), fmt.Sprintf("expected %s got %s\n", rulechain.ErrUnauthorizedAccess, err))
+}
+
+func TestViewNodeConfig(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	cases := map[string]struct {
+		name  string
+		token string
+		err   error
+	}{
+		"view existing node config": {
+			name:  "LogNode",
+			token: token,
+			err:   nil,
+		},
+		"view non-existing node config": {
+			name:  "UnknownNode",
+			token: token,
+			err:   rulechain.ErrNodeNotFound,
+		},
+		"view node config with invalid token": {
+			name:  "LogNode",
+			token: wrong,
+			err:   rulechain.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		config, err := svc.ViewNodeConfig(context.Background(), tc.token, tc.name)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if tc.err == nil {
+			assert.NotEmpty(t, config, fmt.Sprintf("%s: expected node config", desc))
+		}
+	}
+}
+
+func TestListDebugEvents(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	rc := rulechain.RuleChain{ID: "1", Name: "test", DebugMode: true, Channel: chanID, SubTopic: subtopic, Payload: []byte(manifest)}
+	err := svc.AddNewRuleChain(context.Background(), token, rc)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	stopped := rulechain.RuleChain{ID: "2", Name: "stopped", Payload: []byte(manifest)}
+	err = svc.AddNewRuleChain(context.Background(), token, stopped)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = svc.UpdateRuleChainStatus(context.Background(), token, rc.ID, rulechain.UPDATE_RULE_STATUS_START)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := &mainflux.Message{Id: "msg", Channel: chanID, Subtopic: subtopic, Publisher: "thing", Payload: []byte(`{"v":1}`)}
+	err = svc.SaveStates(msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	// Messages of other channels are not handled by the rulechain
+	err = svc.SaveStates(&mainflux.Message{Id: "other", Channel: "2", Subtopic: subtopic})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var events []rulechain.DebugEvent
+	assert.Eventually(t, func() bool {
+		events, err = svc.ListDebugEvents(context.Background(), token, rc.ID, 0)
+		return err == nil && len(events) > 0
+	}, time.Second, 10*time.Millisecond, "expected debug events of the handled message")
+	require.Len(t, events, 1, fmt.Sprintf("expected 1 event got %d", len(events)))
+	assert.Equal(t, msg.Id, events[0].MessageID, fmt.Sprintf("expected event of message %s got %s", msg.Id, events[0].MessageID))
+	assert.Equal(t, string(msg.Payload), events[0].Payload, "expected message payload in the event")
+
+	cases := map[string]struct {
+		id    string
+		token string
+		after uint64
+		size  int
+		err   error
+	}{
+		"list events after the last one": {
+			id:    rc.ID,
+			token: token,
+			after: events[0].Seq,
+			size:  0,
+			err:   nil,
+		},
+		"list events of stopped rulechain": {
+			id:    stopped.ID,
+			token: token,
+			err:   rulechain.ErrRuleChainNotStarted,
+		},
+		"list events of non-existing rulechain": {
+			id:    "3",
+			token: token,
+			err:   rulechain.ErrRuleChainNotFound,
+		},
+		"list events with invalid token": {
+			id:    rc.ID,
+			token: wrong,
+			err:   rulechain.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		events, err := svc.ListDebugEvents(context.Background(), tc.token, tc.id, tc.after)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Len(t, events, tc.size, fmt.Sprintf("%s: expected %d events got %d", desc, tc.size, len(events)))
+	}
+}
+
+func TestUpdateRuleChainStatus(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+
+	rc := rulechain.RuleChain{ID: "1", Name: "test", Channel: chanID, SubTopic: subtopic, Payload: []byte(manifest)}
+	err := svc.AddNewRuleChain(context.Background(), token, rc)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	// The cases run in order, each one starts from the status saved by
+	// the previous one
+	cases := []struct {
+		desc   string
+		update string
+		code   codes.Code
+		status string
+	}{
+		{
+			desc:   "start created rulechain",
+			update: rulechain.UPDATE_RULE_STATUS_START,
+			code:   codes.OK,
+			status: rulechain.RULE_STATUS_STARTED,
+		},
+		{
+			desc:   "start started rulechain",
+			update: rulechain.UPDATE_RULE_STATUS_START,
+			code:   codes.FailedPrecondition,
+			status: rulechain.RULE_STATUS_STARTED,
+		},
+		{
+			desc:   "stop started rulechain",
+			update: rulechain.UPDATE_RULE_STATUS_STOP,
+			code:   codes.OK,
+			status: rulechain.RULE_STATUS_STOPPED,
+		},
+		{
+			desc:   "stop stopped rulechain",
+			update: rulechain.UPDATE_RULE_STATUS_STOP,
+			code:   codes.FailedPrecondition,
+			status: rulechain.RULE_STATUS_STOPPED,
+		},
+		{
+			desc:   "start stopped rulechain",
+			update: rulechain.UPDATE_RULE_STATUS_START,
+			code:   codes.OK,
+			status: rulechain.RULE_STATUS_STARTED,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateRuleChainStatus(context.Background(), token, rc.ID, tc.update)
+		assert.Equal(t, tc.code, status.Code(err), fmt.Sprintf("%s: expected code %s got %s\n", tc.desc, tc.code, err))
+		saved, err := svc.GetRuleChainInfo(context.Background(), token, rc.ID)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.status, saved.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, saved.Status))
+	}
+
+	err = svc.UpdateRuleChainStatus(context.Background(), wrong, rc.ID, rulechain.UPDATE_RULE_STATUS_STOP)
+	assert.NotNil(t, err, "stop rulechain with invalid token: expected error got nil")
+}
@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/cloustone/pandas/kuiper"
 )
@@ -15,8 +18,9 @@ const kuiperEndpoint = "kuiper"
 type KuiperPluginType string
 
 const (
-	KuiperPluginSink   KuiperPluginType = "sink"
-	KuiperPluginSource KuiperPluginType = "source"
+	KuiperPluginSink     KuiperPluginType = "sinks"
+	KuiperPluginSource   KuiperPluginType = "sources"
+	KuiperPluginFunction KuiperPluginType = "functions"
 )
 
 // CreateKuiperStream create a stream in kuiper
@@ -219,46 +223,175 @@ func (sdk mfSDK) KuiperRules(token string) (string, error) {
 
 }
 
-// KupirRuleStatus return a rule's status
-func (sdk mfSDK) KuiperRuleStatus(ruleName, token string) (string, error) {
-	endpoint := fmt.Sprintf("rules/%s/status", ruleName)
+// KuiperRuleStatus return a rule's running state and per node metrics
+func (sdk mfSDK) KuiperRuleStatus(ruleID, token string) (kuiper.RuleStatus, error) {
+	endpoint := fmt.Sprintf("rules/%s/status", ruleID)
 	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return "", err
+		return kuiper.RuleStatus{}, err
 	}
 
 	resp, err := sdk.sendRequest(req, token, string(CTJSON))
 	if err != nil {
-		return "", err
+		return kuiper.RuleStatus{}, err
 	}
+	defer resp.Body.Close()
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return kuiper.RuleStatus{}, err
 	}
 
-	if resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK {
 		switch resp.StatusCode {
 		case http.StatusBadRequest:
-			return string(body), ErrInvalidArgs
+			return kuiper.RuleStatus{}, ErrInvalidArgs
 		case http.StatusForbidden:
-			return string(body), ErrUnauthorized
+			return kuiper.RuleStatus{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return kuiper.RuleStatus{}, ErrNotFound
 		default:
-			return string(body), ErrFailedCreation
+			return kuiper.RuleStatus{}, ErrFetchFailed
 		}
 	}
-	return string(body), nil
+
+	var status kuiper.RuleStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return kuiper.RuleStatus{}, err
+	}
+	return status, nil
 }
 
-func buildPluginEndpoint(pluginType KuiperPluginType) string {
-	if pluginType == KuiperPluginSource {
-		return "plugin/sources"
-	} else {
-		return "plugin/sinks"
+// KuiperRuleTopo return a rule's topology
+func (sdk mfSDK) KuiperRuleTopo(ruleID, token string) (kuiper.RuleTopo, error) {
+	endpoint := fmt.Sprintf("rules/%s/topo", ruleID)
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return kuiper.RuleTopo{}, err
 	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return kuiper.RuleTopo{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return kuiper.RuleTopo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return kuiper.RuleTopo{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return kuiper.RuleTopo{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return kuiper.RuleTopo{}, ErrNotFound
+		default:
+			return kuiper.RuleTopo{}, ErrFetchFailed
+		}
+	}
+
+	var topo kuiper.RuleTopo
+	if err := json.Unmarshal(body, &topo); err != nil {
+		return kuiper.RuleTopo{}, err
+	}
+	return topo, nil
 }
 
-// KuiperPlugin return specified plugin info
+// KuiperExplainRule return the logical plan of an EXPLAIN SELECT statement
+func (sdk mfSDK) KuiperExplainRule(sql, token string) (kuiper.RulePlan, error) {
+	data, err := json.Marshal(map[string]string{"sql": sql})
+	if err != nil {
+		return kuiper.RulePlan{}, ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, "rules/explain")
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return kuiper.RulePlan{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return kuiper.RulePlan{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return kuiper.RulePlan{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return kuiper.RulePlan{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return kuiper.RulePlan{}, ErrUnauthorized
+		default:
+			return kuiper.RulePlan{}, ErrFetchFailed
+		}
+	}
+
+	var plan kuiper.RulePlan
+	if err := json.Unmarshal(body, &plan); err != nil {
+		return kuiper.RulePlan{}, err
+	}
+	return plan, nil
+}
+
+// KuiperTestRule run a SQL against sample rows and return the outputs
+func (sdk mfSDK) KuiperTestRule(t kuiper.RuleTest, token string) (kuiper.RuleTestResult, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return kuiper.RuleTestResult{}, ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, "rules/test")
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return kuiper.RuleTestResult{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return kuiper.RuleTestResult{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return kuiper.RuleTestResult{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return kuiper.RuleTestResult{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return kuiper.RuleTestResult{}, ErrUnauthorized
+		default:
+			return kuiper.RuleTestResult{}, ErrFetchFailed
+		}
+	}
+
+	var result kuiper.RuleTestResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return kuiper.RuleTestResult{}, err
+	}
+	return result, nil
+}
+
+func buildPluginEndpoint(pluginType KuiperPluginType) string {
+	return fmt.Sprintf("plugins/%s", pluginType)
+}
+
+// KuiperPlugins return built-in plugins and plugins installed by the user
 func (sdk mfSDK) KuiperPlugins(pluginType KuiperPluginType, token string) (string, error) {
 	endpoint := buildPluginEndpoint(pluginType)
 	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
@@ -271,28 +404,30 @@ func (sdk mfSDK) KuiperPlugins(pluginType KuiperPluginType, token string) (strin
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
 	}
 
-	if resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK {
 		switch resp.StatusCode {
 		case http.StatusBadRequest:
 			return string(body), ErrInvalidArgs
 		case http.StatusForbidden:
 			return string(body), ErrUnauthorized
 		default:
-			return string(body), ErrFailedCreation
+			return string(body), ErrFetchFailed
 		}
 	}
 	return string(body), nil
 }
 
 // KuiperPlugin return specified plugin info
-func (sdk mfSDK) KuiperPlugin(pluginType KuiperPluginType, id string, token string) (string, error) {
+func (sdk mfSDK) KuiperPlugin(pluginType KuiperPluginType, name string, token string) (string, error) {
 	endpoint := buildPluginEndpoint(pluginType)
-	endpoint = fmt.Sprintf("%s/%s", endpoint, id)
+	endpoint = fmt.Sprintf("%s/%s", endpoint, name)
 
 	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
@@ -304,24 +439,107 @@ func (sdk mfSDK) KuiperPlugin(pluginType KuiperPluginType, id string, token stri
 	if err != nil {
 		return "", err
 	}
+	defer resp.Body.Close()
+
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
 	}
 
-	if resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK {
 		switch resp.StatusCode {
 		case http.StatusBadRequest:
 			return string(body), ErrInvalidArgs
 		case http.StatusForbidden:
 			return string(body), ErrUnauthorized
+		case http.StatusNotFound:
+			return string(body), ErrNotFound
 		default:
-			return string(body), ErrFailedCreation
+			return string(body), ErrFetchFailed
 		}
 	}
 	return string(body), nil
 }
 
+// InstallKuiperPlugin install plugin from the zip file located at url
+func (sdk mfSDK) InstallKuiperPlugin(pluginType KuiperPluginType, name, file, token string) error {
+	data, err := json.Marshal(map[string]string{
+		"name": name,
+		"file": file,
+	})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, buildPluginEndpoint(pluginType))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return pluginInstallError(resp.StatusCode)
+}
+
+// UploadKuiperPlugin install plugin from the local zip file
+func (sdk mfSDK) UploadKuiperPlugin(pluginType KuiperPluginType, name, file, token string) error {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("name", name); err != nil {
+		return err
+	}
+	fw, err := w.CreateFormFile("file", filepath.Base(file))
+	if err != nil {
+		return err
+	}
+	if _, err := fw.Write(content); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/upload", buildPluginEndpoint(pluginType))
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+	req, err := http.NewRequest(http.MethodPost, url, &body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, w.FormDataContentType())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return pluginInstallError(resp.StatusCode)
+}
+
+func pluginInstallError(code int) error {
+	switch code {
+	case http.StatusCreated:
+		return nil
+	case http.StatusBadRequest:
+		return ErrInvalidArgs
+	case http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusUnprocessableEntity:
+		return ErrConflict
+	default:
+		return ErrFailedCreation
+	}
+}
+
 // DeleteKuiperStream remove kuiper stream
 func (sdk mfSDK) DeleteKuiperStream(streamName string, token string) error {
 	endpoint := fmt.Sprintf("streams/%s", streamName)
@@ -378,9 +596,35 @@ func (sdk mfSDK) DeleteKuiperRule(ruleName string, token string) error {
 	return nil
 }
 
-// DeleteKuiperPlugin remove kuiper plugin
-func (sdk mfSDK) DeleteKuiperPlugin(desc kuiper.Plugin, token string) error {
-	return ErrNotFound
+// DeleteKuiperPlugin remove kuiper plugin installed by the user
+func (sdk mfSDK) DeleteKuiperPlugin(pluginType KuiperPluginType, name, token string) error {
+	endpoint := fmt.Sprintf("%s/%s", buildPluginEndpoint(pluginType), name)
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+	return nil
 }
 
 // StartKuiperRule start an already existed rule in kuiper
@@ -465,3 +709,182 @@ func (sdk mfSDK) RestartKuiperRule(ruleName string, token string) error {
 	return nil
 
 }
+
+// CreateKuiperFunction define a function with the CREATE FUNCTION statement
+func (sdk mfSDK) CreateKuiperFunction(sql, token string) error {
+	data, err := json.Marshal(map[string]string{"sql": sql})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+	return sdk.createKuiperEntity("functions", data, token)
+}
+
+// KuiperFunctions return the functions defined by the user
+func (sdk mfSDK) KuiperFunctions(token string) (string, error) {
+	return sdk.kuiperEntity("functions", token)
+}
+
+// KuiperFunction return specified function info
+func (sdk mfSDK) KuiperFunction(name, token string) (string, error) {
+	return sdk.kuiperEntity(fmt.Sprintf("functions/%s", name), token)
+}
+
+// DeleteKuiperFunction remove kuiper function defined by the user
+func (sdk mfSDK) DeleteKuiperFunction(name, token string) error {
+	return sdk.deleteKuiperEntity(fmt.Sprintf("functions/%s", name), token)
+}
+
+// CreateKuiperRuleTemplate register a rule template, the json is a text
+// template of the rule json
+func (sdk mfSDK) CreateKuiperRuleTemplate(name, tmpl, token string) error {
+	data, err := json.Marshal(map[string]string{"name": name, "json": tmpl})
+	if err != nil {
+		return ErrInvalidArgs
+	}
+	return sdk.createKuiperEntity("templates", data, token)
+}
+
+// KuiperRuleTemplates return the rule templates of the user
+func (sdk mfSDK) KuiperRuleTemplates(token string) (string, error) {
+	return sdk.kuiperEntity("templates", token)
+}
+
+// KuiperRuleTemplate return specified rule template info
+func (sdk mfSDK) KuiperRuleTemplate(name, token string) (string, error) {
+	return sdk.kuiperEntity(fmt.Sprintf("templates/%s", name), token)
+}
+
+// DeleteKuiperRuleTemplate remove kuiper rule template
+func (sdk mfSDK) DeleteKuiperRuleTemplate(name, token string) error {
+	return sdk.deleteKuiperEntity(fmt.Sprintf("templates/%s", name), token)
+}
+
+// InstantiateKuiperRuleTemplate create a rule from the rule template with the
+// parameters and return the rule id
+func (sdk mfSDK) InstantiateKuiperRuleTemplate(template, ruleName string, params map[string]interface{}, token string) (string, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"name":   ruleName,
+		"params": params,
+	})
+	if err != nil {
+		return "", ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("templates/%s/rules", template)
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return "", ErrInvalidArgs
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		case http.StatusNotFound:
+			return "", ErrNotFound
+		default:
+			return "", ErrFailedCreation
+		}
+	}
+
+	id := strings.TrimPrefix(resp.Header.Get("Location"), "/rules/")
+	return id, nil
+}
+
+func (sdk mfSDK) createKuiperEntity(endpoint string, data []byte, token string) error {
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusUnprocessableEntity:
+			return ErrConflict
+		default:
+			return ErrFailedCreation
+		}
+	}
+	return nil
+}
+
+func (sdk mfSDK) kuiperEntity(endpoint, token string) (string, error) {
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return string(body), ErrInvalidArgs
+		case http.StatusForbidden:
+			return string(body), ErrUnauthorized
+		case http.StatusNotFound:
+			return string(body), ErrNotFound
+		default:
+			return string(body), ErrFetchFailed
+		}
+	}
+	return string(body), nil
+}
+
+func (sdk mfSDK) deleteKuiperEntity(endpoint, token string) error {
+	url := createURL(sdk.baseURL, sdk.kuiperPrefix, endpoint)
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedRemoval
+		}
+	}
+	return nil
+}
@@ -1 +1,161 @@
-package sdk
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloustone/pandas/kuiper"
+	kuiperapi "github.com/cloustone/pandas/kuiper/api/http"
+	kuipermocks "github.com/cloustone/pandas/kuiper/mocks"
+	sdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	ruleStatus = kuiper.RuleStatus{
+		ID:    "1",
+		Name:  "rule1",
+		State: "Running",
+		Metrics: []kuiper.NodeMetrics{
+			{
+				Type:             "source",
+				Name:             "demo",
+				RecordsInTotal:   5,
+				RecordsOutTotal:  5,
+				ProcessLatencyMs: 1,
+				LastInvocation:   "2020-06-01T10:00:00.123456",
+			},
+			{
+				Type:             "op",
+				Name:             "window",
+				RecordsInTotal:   5,
+				RecordsOutTotal:  1,
+				ExceptionsTotal:  1,
+				BufferLength:     2,
+				LateRecordsTotal: 3,
+				LateDroppedTotal: 1,
+			},
+		},
+	}
+	ruleTopo = kuiper.RuleTopo{
+		Sources: []string{"source_demo"},
+		Edges: map[string][]string{
+			"source_demo":          {"op_preprocessor_demo"},
+			"op_preprocessor_demo": {"op_project"},
+			"op_project":           {"sink_log_0"},
+		},
+	}
+)
+
+func newKuiperServer() *httptest.Server {
+	svc := kuipermocks.NewRuleStatusService(token,
+		map[string]kuiper.RuleStatus{ruleStatus.ID: ruleStatus},
+		map[string]kuiper.RuleTopo{ruleStatus.ID: ruleTopo})
+	mux := kuiperapi.MakeHandler(mocktracer.New(), svc)
+	return httptest.NewServer(mux)
+}
+
+func TestKuiperRuleStatus(t *testing.T) {
+	ts := newKuiperServer()
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		BaseURL:         ts.URL,
+		KuiperPrefix:    "",
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+
+	cases := []struct {
+		desc     string
+		ruleID   string
+		token    string
+		err      error
+		response kuiper.RuleStatus
+	}{
+		{
+			desc:     "get status of existing rule",
+			ruleID:   ruleStatus.ID,
+			token:    token,
+			err:      nil,
+			response: ruleStatus,
+		},
+		{
+			desc:     "get status of non-existing rule",
+			ruleID:   wrongValue,
+			token:    token,
+			err:      sdk.ErrNotFound,
+			response: kuiper.RuleStatus{},
+		},
+		{
+			desc:     "get status of rule with invalid token",
+			ruleID:   ruleStatus.ID,
+			token:    wrongValue,
+			err:      sdk.ErrUnauthorized,
+			response: kuiper.RuleStatus{},
+		},
+	}
+
+	for _, tc := range cases {
+		respStatus, err := mainfluxSDK.KuiperRuleStatus(tc.ruleID, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.response, respStatus, fmt.Sprintf("%s: expected response status %v, got %v", tc.desc, tc.response, respStatus))
+	}
+}
+
+func TestKuiperRuleTopo(t *testing.T) {
+	ts := newKuiperServer()
+	defer ts.Close()
+
+	sdkConf := sdk.Config{
+		BaseURL:         ts.URL,
+		KuiperPrefix:    "",
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+
+	cases := []struct {
+		desc     string
+		ruleID   string
+		token    string
+		err      error
+		response kuiper.RuleTopo
+	}{
+		{
+			desc:     "get topo of existing rule",
+			ruleID:   ruleStatus.ID,
+			token:    token,
+			err:      nil,
+			response: ruleTopo,
+		},
+		{
+			desc:     "get topo of non-existing rule",
+			ruleID:   wrongValue,
+			token:    token,
+			err:      sdk.ErrNotFound,
+			response: kuiper.RuleTopo{},
+		},
+		{
+			desc:     "get topo of rule with invalid token",
+			ruleID:   ruleStatus.ID,
+			token:    wrongValue,
+			err:      sdk.ErrUnauthorized,
+			response: kuiper.RuleTopo{},
+		},
+	}
+
+	for _, tc := range cases {
+		respTopo, err := mainfluxSDK.KuiperRuleTopo(tc.ruleID, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.response, respTopo, fmt.Sprintf("%s: expected response topo %v, got %v", tc.desc, tc.response, respTopo))
+	}
+}
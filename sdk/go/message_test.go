@@ -17,12 +17,12 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func newMessageService(cc mainflux.ThingsServiceClient) mainflux.MessagePublisher {
+func newMessageService(cc mainflux.ThingsServiceClient) adapter.Service {
 	pub := mocks.NewPublisher()
 	return adapter.New(pub, cc)
 }
 
-func newMessageServer(pub mainflux.MessagePublisher) *httptest.Server {
+func newMessageServer(pub adapter.Service) *httptest.Server {
 	mux := api.MakeHandler(pub, mocktracer.New())
 	return httptest.NewServer(mux)
 }
@@ -40,3 +40,30 @@ type MessagesPage struct {
 	Messages []senml.Message `json:"messages,omitempty"`
 	pageRes
 }
+
+// RuleChainsPage contains list of rulechains in a page with proper metadata.
+type RuleChainsPage struct {
+	RuleChains []RuleChain `json:"rulechains"`
+	pageRes
+}
+
+type ruleChainRes struct {
+	RuleChain RuleChain `json:"rulechain"`
+}
+
+type nodeCategoriesRes struct {
+	Categories map[string][]string `json:"categories"`
+}
+
+type nodeConfigRes struct {
+	Name   string `json:"name"`
+	Config string `json:"config"`
+}
+
+type ruleChainEventsRes struct {
+	Events []RuleChainEvent `json:"events"`
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
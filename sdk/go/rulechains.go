@@ -0,0 +1,425 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+const (
+	rulechainsEndpoint = "rulechain"
+	nodesEndpoint      = "nodes"
+
+	ruleChainStatusStart = "start"
+	ruleChainStatusStop  = "stop"
+
+	// RuleChainStarted is the status of a running rulechain.
+	RuleChainStarted = "started"
+)
+
+// RuleChain represents pandas rulechain, the payload holds rulechain's
+// manifest.
+type RuleChain struct {
+	Name         string
+	ID           string
+	Description  string
+	DebugMode    bool
+	UserID       string
+	Status       string
+	Payload      []byte
+	Root         bool
+	Channel      string
+	SubTopic     string
+	CreateAt     time.Time
+	LastUpdateAt time.Time
+}
+
+// RuleChainEvent represents a message traced by rulechain in debug mode.
+type RuleChainEvent struct {
+	Seq         uint64    `json:"seq"`
+	RuleChainID string    `json:"rulechain_id"`
+	Node        string    `json:"node"`
+	MessageID   string    `json:"message_id"`
+	MessageType string    `json:"message_type"`
+	Payload     string    `json:"payload,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Created     time.Time `json:"created"`
+}
+
+func (sdk mfSDK) CreateRuleChain(rc RuleChain, token string) error {
+	data, err := json.Marshal(rc)
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, rulechainsEndpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusConflict:
+			return ErrConflict
+		default:
+			return ErrFailedCreation
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) RuleChain(id, token string) (RuleChain, error) {
+	endpoint := fmt.Sprintf("%s/%s", rulechainsEndpoint, id)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return RuleChain{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return RuleChain{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return RuleChain{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return RuleChain{}, ErrNotFound
+		case http.StatusForbidden:
+			return RuleChain{}, ErrUnauthorized
+		case http.StatusNotFound:
+			return RuleChain{}, ErrNotFound
+		default:
+			return RuleChain{}, ErrFetchFailed
+		}
+	}
+
+	var rr ruleChainRes
+	if err := json.Unmarshal(body, &rr); err != nil {
+		return RuleChain{}, err
+	}
+	if rr.RuleChain.ID == "" {
+		return RuleChain{}, ErrNotFound
+	}
+
+	return rr.RuleChain, nil
+}
+
+func (sdk mfSDK) RuleChains(token string, offset, limit uint64) (RuleChainsPage, error) {
+	endpoint := fmt.Sprintf("%s?offset=%d&limit=%d", rulechainsEndpoint, offset, limit)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return RuleChainsPage{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return RuleChainsPage{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return RuleChainsPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return RuleChainsPage{}, ErrInvalidArgs
+		case http.StatusForbidden:
+			return RuleChainsPage{}, ErrUnauthorized
+		default:
+			return RuleChainsPage{}, ErrFetchFailed
+		}
+	}
+
+	var rp RuleChainsPage
+	if err := json.Unmarshal(body, &rp); err != nil {
+		return RuleChainsPage{}, err
+	}
+
+	return rp, nil
+}
+
+func (sdk mfSDK) UpdateRuleChain(rc RuleChain, token string) error {
+	data, err := json.Marshal(rc)
+	if err != nil {
+		return ErrInvalidArgs
+	}
+
+	endpoint := fmt.Sprintf("%s/%s", rulechainsEndpoint, rc.ID)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		case http.StatusNotFound:
+			return ErrNotFound
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) DeleteRuleChain(id, token string) error {
+	endpoint := fmt.Sprintf("%s/%s", rulechainsEndpoint, id)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		default:
+			return ErrFailedRemoval
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) StartRuleChain(id, token string) error {
+	return sdk.updateRuleChainStatus(id, ruleChainStatusStart, token)
+}
+
+func (sdk mfSDK) StopRuleChain(id, token string) error {
+	return sdk.updateRuleChainStatus(id, ruleChainStatusStop, token)
+}
+
+func (sdk mfSDK) updateRuleChainStatus(id, status, token string) error {
+	endpoint := fmt.Sprintf("updateRulechainStatus/%s", id)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPut, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("updatestatus", status)
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return ErrInvalidArgs
+		case http.StatusForbidden:
+			return ErrUnauthorized
+		default:
+			return ErrFailedUpdate
+		}
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) ValidateRuleChain(manifest []byte, token string) (string, error) {
+	endpoint := fmt.Sprintf("%s/validate", rulechainsEndpoint)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(manifest))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+		var er errorRes
+		if err := json.Unmarshal(body, &er); err != nil {
+			er.Err = string(body)
+		}
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return er.Err, ErrInvalidArgs
+		case http.StatusForbidden:
+			return er.Err, ErrUnauthorized
+		default:
+			return er.Err, ErrFetchFailed
+		}
+	}
+
+	return "", nil
+}
+
+func (sdk mfSDK) RuleChainNodes(token string) (map[string][]string, error) {
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, nodesEndpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return nil, ErrUnauthorized
+		default:
+			return nil, ErrFetchFailed
+		}
+	}
+
+	var nr nodeCategoriesRes
+	if err := json.Unmarshal(body, &nr); err != nil {
+		return nil, err
+	}
+
+	return nr.Categories, nil
+}
+
+func (sdk mfSDK) RuleChainNode(name, token string) (string, error) {
+	endpoint := fmt.Sprintf("%s/%s", nodesEndpoint, name)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusForbidden:
+			return "", ErrUnauthorized
+		case http.StatusNotFound:
+			return "", ErrNotFound
+		default:
+			return "", ErrFetchFailed
+		}
+	}
+
+	var nr nodeConfigRes
+	if err := json.Unmarshal(body, &nr); err != nil {
+		return "", err
+	}
+
+	return nr.Config, nil
+}
+
+func (sdk mfSDK) RuleChainEvents(id string, after uint64, token string) ([]RuleChainEvent, error) {
+	endpoint := fmt.Sprintf("%s/%s/events?after=%d", rulechainsEndpoint, id, after)
+	url := createURL(sdk.baseURL, sdk.rulechainPrefix, endpoint)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return nil, ErrNotFound
+		case http.StatusForbidden:
+			return nil, ErrUnauthorized
+		case http.StatusConflict:
+			return nil, ErrRuleChainNotStarted
+		default:
+			return nil, ErrFetchFailed
+		}
+	}
+
+	var er ruleChainEventsRes
+	if err := json.Unmarshal(body, &er); err != nil {
+		return nil, err
+	}
+
+	return er.Events, nil
+}
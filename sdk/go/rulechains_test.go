@@ -0,0 +1,333 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloustone/pandas/mainflux"
+	log "github.com/cloustone/pandas/pkg/logger"
+	"github.com/cloustone/pandas/rulechain"
+	rchttpapi "github.com/cloustone/pandas/rulechain/api/http"
+	rcmocks "github.com/cloustone/pandas/rulechain/mocks"
+	"github.com/cloustone/pandas/rulechain/nodes"
+	sdk "github.com/cloustone/pandas/sdk/go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rcChanID     = "1"
+	rcSubtopic   = "temperature"
+	rcAssetPath  = "../../rulechain/assets"
+	rcManifest   = `{"ruleChain": {"name": "test", "firstRuleNodeId": "0", "debugMode": true}, "metadata": {"nodes": [{"type": "InputNode", "name": "0"}]}}`
+	rcBadManifst = `{"metadata": {"nodes": [{"type": "UnknownNode", "name": "0"}]}}`
+)
+
+func newRuleChainService(tokens map[string]string) rulechain.Service {
+	nodes.AssetPath = rcAssetPath
+	auth := rcmocks.NewAuthService(tokens)
+	repo := rcmocks.NewRuleChainRepository()
+	cache := rcmocks.NewRuleChainCache()
+	return rulechain.New(auth, repo, rulechain.NewInstanceManager(), cache)
+}
+
+func newRuleChainServer(svc rulechain.Service) *httptest.Server {
+	logger, _ := log.New(ioutil.Discard, "error")
+	mux := rchttpapi.MakeHandler(svc, mocktracer.New(), logger)
+	return httptest.NewServer(mux)
+}
+
+func newRuleChainSDK(url string) sdk.SDK {
+	return sdk.NewSDK(sdk.Config{
+		BaseURL:         url,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	})
+}
+
+func TestCreateRuleChain(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	rc := sdk.RuleChain{ID: "1", Name: "test", Payload: []byte(rcManifest)}
+
+	cases := []struct {
+		desc  string
+		rc    sdk.RuleChain
+		token string
+		err   error
+	}{
+		{
+			desc:  "create new rulechain",
+			rc:    rc,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "create existing rulechain",
+			rc:    rc,
+			token: token,
+			err:   sdk.ErrConflict,
+		},
+		{
+			desc:  "create rulechain with invalid token",
+			rc:    sdk.RuleChain{ID: "2", Name: "test"},
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		err := mainfluxSDK.CreateRuleChain(tc.rc, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestRuleChain(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	rc := sdk.RuleChain{ID: "1", Name: "test", Description: "desc", Payload: []byte(rcManifest)}
+	err := mainfluxSDK.CreateRuleChain(rc, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "get existing rulechain",
+			id:    rc.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "get non-existing rulechain",
+			id:    badID,
+			token: token,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "get rulechain with invalid token",
+			id:    rc.ID,
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := mainfluxSDK.RuleChain(tc.id, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, rc.Name, res.Name, fmt.Sprintf("%s: expected name %s got %s", tc.desc, rc.Name, res.Name))
+		assert.Equal(t, rc.Description, res.Description, fmt.Sprintf("%s: expected description %s got %s", tc.desc, rc.Description, res.Description))
+		assert.Equal(t, rc.Payload, res.Payload, fmt.Sprintf("%s: expected manifest %s got %s", tc.desc, rc.Payload, res.Payload))
+		assert.Equal(t, email, res.UserID, fmt.Sprintf("%s: expected owner %s got %s", tc.desc, email, res.UserID))
+	}
+}
+
+func TestRuleChainStatus(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	rc := sdk.RuleChain{ID: "1", Name: "test", Payload: []byte(rcManifest)}
+	err := mainfluxSDK.CreateRuleChain(rc, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = mainfluxSDK.StartRuleChain(rc.ID, wrongValue)
+	assert.Equal(t, sdk.ErrUnauthorized, err, fmt.Sprintf("start rulechain with invalid token: expected error %s, got %s", sdk.ErrUnauthorized, err))
+
+	err = mainfluxSDK.StartRuleChain(badID, token)
+	assert.Equal(t, sdk.ErrInvalidArgs, err, fmt.Sprintf("start non-existing rulechain: expected error %s, got %s", sdk.ErrInvalidArgs, err))
+
+	err = mainfluxSDK.StartRuleChain(rc.ID, token)
+	assert.Nil(t, err, fmt.Sprintf("start rulechain: unexpected error %s", err))
+}
+
+func TestValidateRuleChain(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	cases := []struct {
+		desc     string
+		manifest string
+		token    string
+		reason   bool
+		err      error
+	}{
+		{
+			desc:     "validate valid manifest",
+			manifest: rcManifest,
+			token:    token,
+			err:      nil,
+		},
+		{
+			desc:     "validate manifest with unknown node type",
+			manifest: rcBadManifst,
+			token:    token,
+			reason:   true,
+			err:      sdk.ErrInvalidArgs,
+		},
+		{
+			desc:     "validate manifest with invalid token",
+			manifest: rcManifest,
+			token:    wrongValue,
+			err:      sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		reason, err := mainfluxSDK.ValidateRuleChain([]byte(tc.manifest), tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if tc.reason {
+			assert.Contains(t, reason, "UnknownNode", fmt.Sprintf("%s: expected the reason to name the node type got %s", tc.desc, reason))
+		}
+	}
+}
+
+func TestRuleChainNodes(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	categories, err := mainfluxSDK.RuleChainNodes(token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, categories[nodes.NODE_CATEGORY_OTHERS], "InputNode", "expected input node in others category")
+
+	_, err = mainfluxSDK.RuleChainNodes(wrongValue)
+	assert.Equal(t, sdk.ErrUnauthorized, err, fmt.Sprintf("expected error %s, got %s", sdk.ErrUnauthorized, err))
+}
+
+func TestRuleChainNode(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	cases := []struct {
+		desc  string
+		name  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "view existing node config",
+			name:  "LogNode",
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "view non-existing node config",
+			name:  "UnknownNode",
+			token: token,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "view node config with invalid token",
+			name:  "LogNode",
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		config, err := mainfluxSDK.RuleChainNode(tc.name, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.NotEmpty(t, config, fmt.Sprintf("%s: expected node config", tc.desc))
+		}
+	}
+}
+
+func TestRuleChainEvents(t *testing.T) {
+	svc := newRuleChainService(map[string]string{token: email})
+	ts := newRuleChainServer(svc)
+	defer ts.Close()
+	mainfluxSDK := newRuleChainSDK(ts.URL)
+
+	rc := sdk.RuleChain{ID: "1", Name: "test", DebugMode: true, Channel: rcChanID, SubTopic: rcSubtopic, Payload: []byte(rcManifest)}
+	err := mainfluxSDK.CreateRuleChain(rc, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	stopped := sdk.RuleChain{ID: "2", Name: "stopped", Payload: []byte(rcManifest)}
+	err = mainfluxSDK.CreateRuleChain(stopped, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = mainfluxSDK.StartRuleChain(rc.ID, token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := &mainflux.Message{Id: "msg", Channel: rcChanID, Subtopic: rcSubtopic, Publisher: "thing", Payload: []byte(`{"v":1}`)}
+	err = svc.SaveStates(msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var events []sdk.RuleChainEvent
+	assert.Eventually(t, func() bool {
+		events, err = mainfluxSDK.RuleChainEvents(rc.ID, 0, token)
+		return err == nil && len(events) > 0
+	}, time.Second, 10*time.Millisecond, "expected debug events of the handled message")
+	require.Len(t, events, 1, fmt.Sprintf("expected 1 event got %d", len(events)))
+	assert.Equal(t, uint64(1), events[0].Seq, fmt.Sprintf("expected event sequence 1 got %d", events[0].Seq))
+	assert.Equal(t, rc.ID, events[0].RuleChainID, fmt.Sprintf("expected rulechain %s got %s", rc.ID, events[0].RuleChainID))
+	assert.Equal(t, msg.Id, events[0].MessageID, fmt.Sprintf("expected message %s got %s", msg.Id, events[0].MessageID))
+	assert.Equal(t, string(msg.Payload), events[0].Payload, fmt.Sprintf("expected payload %s got %s", msg.Payload, events[0].Payload))
+
+	cases := []struct {
+		desc  string
+		id    string
+		after uint64
+		token string
+		size  int
+		err   error
+	}{
+		{
+			desc:  "list events after the last one",
+			id:    rc.ID,
+			after: events[0].Seq,
+			token: token,
+			size:  0,
+			err:   nil,
+		},
+		{
+			desc:  "list events of stopped rulechain",
+			id:    stopped.ID,
+			token: token,
+			err:   sdk.ErrRuleChainNotStarted,
+		},
+		{
+			desc:  "list events of non-existing rulechain",
+			id:    badID,
+			token: token,
+			err:   sdk.ErrNotFound,
+		},
+		{
+			desc:  "list events with invalid token",
+			id:    rc.ID,
+			token: wrongValue,
+			err:   sdk.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := mainfluxSDK.RuleChainEvents(tc.id, tc.after, tc.token)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Len(t, res, tc.size, fmt.Sprintf("%s: expected %d events got %d", tc.desc, tc.size, len(res)))
+	}
+}
@@ -91,6 +91,9 @@ var (
 
 	// ErrFailedUserAdd failed to add user to a group.
 	ErrFailedUserAdd = errors.New("failed to add user to group")
+
+	// ErrRuleChainNotStarted indicates that rulechain has no running instance.
+	ErrRuleChainNotStarted = errors.New("rulechain not started")
 )
 
 // ContentType represents all possible content types.
@@ -221,9 +224,18 @@ type SDK interface {
 	// KuiperRule return specified rule info
 	KuiperRules(token string) (string, error)
 
-	// KuiperPlugin return specified plugin info
+	// KuiperPlugins return built-in plugins and plugins installed by the user
 	KuiperPlugins(pluginType KuiperPluginType, token string) (string, error)
 
+	// InstallKuiperPlugin install plugin from the zip file located at url
+	InstallKuiperPlugin(pluginType KuiperPluginType, name, file, token string) error
+
+	// UploadKuiperPlugin install plugin from the local zip file
+	UploadKuiperPlugin(pluginType KuiperPluginType, name, file, token string) error
+
+	// DeleteKuiperPlugin remove kuiper plugin installed by the user
+	DeleteKuiperPlugin(pluginType KuiperPluginType, name, token string) error
+
 	// DeleteKuiperStream remove kuiper stream
 	DeleteKuiperStream(streamName, token string) error
 
@@ -239,8 +251,80 @@ type SDK interface {
 	// RestartKuiperRule restart an already existed rule in kuiper
 	RestartKuiperRule(ruleName, token string) error
 
-	// KupirRuleStatus return a rule's status
-	KuiperRuleStatus(ruleName, token string) (string, error)
+	// KuiperRuleStatus return a rule's running state and per node metrics
+	KuiperRuleStatus(ruleID, token string) (kuiper.RuleStatus, error)
+
+	// KuiperRuleTopo return a rule's topology
+	KuiperRuleTopo(ruleID, token string) (kuiper.RuleTopo, error)
+
+	// KuiperExplainRule return the logical plan of an EXPLAIN SELECT statement
+	KuiperExplainRule(sql, token string) (kuiper.RulePlan, error)
+
+	// KuiperTestRule run a SQL against sample rows and return the outputs
+	KuiperTestRule(t kuiper.RuleTest, token string) (kuiper.RuleTestResult, error)
+
+	// CreateKuiperFunction define a function with the CREATE FUNCTION statement
+	CreateKuiperFunction(sql, token string) error
+
+	// KuiperFunctions return the functions defined by the user
+	KuiperFunctions(token string) (string, error)
+
+	// KuiperFunction return specified function info
+	KuiperFunction(name, token string) (string, error)
+
+	// DeleteKuiperFunction remove kuiper function defined by the user
+	DeleteKuiperFunction(name, token string) error
+
+	// CreateKuiperRuleTemplate register a rule template
+	CreateKuiperRuleTemplate(name, tmpl, token string) error
+
+	// KuiperRuleTemplates return the rule templates of the user
+	KuiperRuleTemplates(token string) (string, error)
+
+	// KuiperRuleTemplate return specified rule template info
+	KuiperRuleTemplate(name, token string) (string, error)
+
+	// DeleteKuiperRuleTemplate remove kuiper rule template
+	DeleteKuiperRuleTemplate(name, token string) error
+
+	// InstantiateKuiperRuleTemplate create a rule from the rule template and
+	// return the rule id
+	InstantiateKuiperRuleTemplate(template, ruleName string, params map[string]interface{}, token string) (string, error)
+
+	// CreateRuleChain registers new rulechain.
+	CreateRuleChain(rc RuleChain, token string) error
+
+	// RuleChain returns rulechain by id.
+	RuleChain(id, token string) (RuleChain, error)
+
+	// RuleChains returns page of rulechains.
+	RuleChains(token string, offset, limit uint64) (RuleChainsPage, error)
+
+	// UpdateRuleChain updates existing rulechain.
+	UpdateRuleChain(rc RuleChain, token string) error
+
+	// DeleteRuleChain removes existing rulechain.
+	DeleteRuleChain(id, token string) error
+
+	// StartRuleChain starts receiving incoming data with rulechain.
+	StartRuleChain(id, token string) error
+
+	// StopRuleChain stops a running rulechain.
+	StopRuleChain(id, token string) error
+
+	// ValidateRuleChain checks rulechain manifest and returns the reasons
+	// if the manifest is invalid.
+	ValidateRuleChain(manifest []byte, token string) (string, error)
+
+	// RuleChainNodes returns node types supported by rulechain by category.
+	RuleChainNodes(token string) (map[string][]string, error)
+
+	// RuleChainNode returns node's static configuration description.
+	RuleChainNode(name, token string) (string, error)
+
+	// RuleChainEvents returns debug events of a running rulechain after
+	// specified sequence.
+	RuleChainEvents(id string, after uint64, token string) ([]RuleChainEvent, error)
 
 	// AddBootstrap add bootstrap configuration
 	AddBootstrap(token string, cfg BootstrapConfig) (string, error)
@@ -283,6 +367,7 @@ type mfSDK struct {
 	thingsPrefix      string
 	channelsPrefix    string
 	kuiperPrefix      string
+	rulechainPrefix   string
 	certsPrefix       string
 	httpAdapterPrefix string
 	msgContentType    ContentType
@@ -300,6 +385,7 @@ type Config struct {
 	UsersPrefix       string
 	ThingsPrefix      string
 	KuiperPrefix      string
+	RuleChainPrefix   string
 	HTTPAdapterPrefix string
 	BootstrapPrefix   string
 	MsgContentType    ContentType
@@ -317,6 +403,7 @@ func NewSDK(conf Config) SDK {
 		usersPrefix:       conf.UsersPrefix,
 		thingsPrefix:      conf.ThingsPrefix,
 		kuiperPrefix:      conf.KuiperPrefix,
+		rulechainPrefix:   conf.RuleChainPrefix,
 		httpAdapterPrefix: conf.HTTPAdapterPrefix,
 		msgContentType:    conf.MsgContentType,
 		bootstrapPrefix:   conf.BootstrapPrefix,
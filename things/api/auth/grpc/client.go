@@ -23,6 +23,7 @@ type grpcClient struct {
 	canAccessByKey endpoint.Endpoint
 	canAccessByID  endpoint.Endpoint
 	identify       endpoint.Endpoint
+	viewSchema     endpoint.Endpoint
 }
 
 // NewClient returns new gRPC client instance.
@@ -55,6 +56,14 @@ func NewClient(conn *grpc.ClientConn, tracer opentracing.Tracer, timeout time.Du
 			decodeIdentityResponse,
 			mainflux.ThingID{},
 		).Endpoint()),
+		viewSchema: kitot.TraceClient(tracer, "view_schema")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ViewSchema",
+			encodeViewSchemaRequest,
+			decodeSchemaResponse,
+			mainflux.Schema{},
+		).Endpoint()),
 	}
 }
 
@@ -99,6 +108,19 @@ func (client grpcClient) Identify(ctx context.Context, req *mainflux.Token, _ ..
 	return &mainflux.ThingID{Value: ir.id}, ir.err
 }
 
+func (client grpcClient) ViewSchema(ctx context.Context, req *mainflux.ChannelID, _ ...grpc.CallOption) (*mainflux.Schema, error) {
+	ctx, cancel := context.WithTimeout(ctx, client.timeout)
+	defer cancel()
+
+	res, err := client.viewSchema(ctx, viewSchemaReq{chanID: req.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+
+	sr := res.(schemaRes)
+	return &mainflux.Schema{Type: sr.schemaType, Definition: sr.definition}, sr.err
+}
+
 func encodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(AccessByKeyReq)
 	return &mainflux.AccessByKeyReq{Token: req.thingKey, ChanID: req.chanID}, nil
@@ -114,6 +136,11 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return &mainflux.Token{Value: req.key}, nil
 }
 
+func encodeViewSchemaRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(viewSchemaReq)
+	return &mainflux.ChannelID{Value: req.chanID}, nil
+}
+
 func decodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.ThingID)
 	return identityRes{id: res.GetValue(), err: nil}, nil
@@ -122,3 +149,8 @@ func decodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}
 func decodeEmptyResponse(_ context.Context, _ interface{}) (interface{}, error) {
 	return emptyRes{}, nil
 }
+
+func decodeSchemaResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.Schema)
+	return schemaRes{schemaType: res.GetType(), definition: res.GetDefinition()}, nil
+}
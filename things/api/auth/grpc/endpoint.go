@@ -46,3 +46,18 @@ func identifyEndpoint(svc things.Service) endpoint.Endpoint {
 		return identityRes{id: id, err: nil}, nil
 	}
 }
+
+func viewSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewSchemaReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema, err := svc.ChannelSchema(ctx, req.chanID)
+		if err != nil {
+			return schemaRes{err: err}, err
+		}
+		return schemaRes{schemaType: schema.Type, definition: schema.Definition}, nil
+	}
+}
@@ -173,3 +173,49 @@ func TestIdentify(t *testing.T) {
 		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
 	}
 }
+
+func TestViewSchema(t *testing.T) {
+	schs, _ := svc.CreateChannels(context.Background(), token, channel, channel)
+	sch, other := schs[0], schs[1]
+	schema := things.Schema{Type: "json", Definition: []byte(`{"type":"object"}`)}
+	svc.SaveSchema(context.Background(), token, sch.ID, schema)
+
+	usersAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(usersAddr, grpc.WithInsecure())
+	cli := grpcapi.NewClient(conn, mocktracer.New(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	cases := map[string]struct {
+		chanID string
+		schema things.Schema
+		code   codes.Code
+	}{
+		"view existing schema": {
+			chanID: sch.ID,
+			schema: schema,
+			code:   codes.OK,
+		},
+		"view schema of channel without schema": {
+			chanID: other.ID,
+			code:   codes.NotFound,
+		},
+		"view schema of non-existent channel": {
+			chanID: "non-existent",
+			code:   codes.NotFound,
+		},
+		"view schema without channel ID": {
+			chanID: wrongID,
+			code:   codes.InvalidArgument,
+		},
+	}
+
+	for desc, tc := range cases {
+		s, err := cli.ViewSchema(ctx, &mainflux.ChannelID{Value: tc.chanID})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "OK expected to be true")
+		assert.Equal(t, tc.schema.Type, s.GetType(), fmt.Sprintf("%s: expected %s got %s", desc, tc.schema.Type, s.GetType()))
+		assert.Equal(t, tc.schema.Definition, s.GetDefinition(), fmt.Sprintf("%s: expected %s got %s", desc, tc.schema.Definition, s.GetDefinition()))
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", desc, tc.code, e.Code()))
+	}
+}
@@ -42,3 +42,15 @@ func (req identifyReq) validate() error {
 
 	return nil
 }
+
+type viewSchemaReq struct {
+	chanID string
+}
+
+func (req viewSchemaReq) validate() error {
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
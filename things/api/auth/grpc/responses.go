@@ -11,3 +11,9 @@ type identityRes struct {
 type emptyRes struct {
 	err error
 }
+
+type schemaRes struct {
+	schemaType string
+	definition []byte
+	err        error
+}
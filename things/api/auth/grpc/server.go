@@ -21,6 +21,7 @@ type grpcServer struct {
 	canAccessByKey kitgrpc.Handler
 	canAccessByID  kitgrpc.Handler
 	identify       kitgrpc.Handler
+	viewSchema     kitgrpc.Handler
 }
 
 // NewServer returns new ThingsServiceServer instance.
@@ -41,6 +42,11 @@ func NewServer(tracer opentracing.Tracer, svc things.Service) mainflux.ThingsSer
 			decodeIdentifyRequest,
 			encodeIdentityResponse,
 		),
+		viewSchema: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "view_schema")(viewSchemaEndpoint(svc)),
+			decodeViewSchemaRequest,
+			encodeSchemaResponse,
+		),
 	}
 }
 
@@ -71,6 +77,15 @@ func (gs *grpcServer) Identify(ctx context.Context, req *mainflux.Token) (*mainf
 	return res.(*mainflux.ThingID), nil
 }
 
+func (gs *grpcServer) ViewSchema(ctx context.Context, req *mainflux.ChannelID) (*mainflux.Schema, error) {
+	_, res, err := gs.viewSchema.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+
+	return res.(*mainflux.Schema), nil
+}
+
 func decodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessByKeyReq)
 	return AccessByKeyReq{thingKey: req.GetToken(), chanID: req.GetChanID()}, nil
@@ -86,6 +101,11 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 	return identifyReq{key: req.GetValue()}, nil
 }
 
+func decodeViewSchemaRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.ChannelID)
+	return viewSchemaReq{chanID: req.GetValue()}, nil
+}
+
 func encodeIdentityResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
 	return &mainflux.ThingID{Value: res.id}, encodeError(res.err)
@@ -96,6 +116,11 @@ func encodeEmptyResponse(_ context.Context, grpcRes interface{}) (interface{}, e
 	return &empty.Empty{}, encodeError(res.err)
 }
 
+func encodeSchemaResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(schemaRes)
+	return &mainflux.Schema{Type: res.schemaType, Definition: res.definition}, encodeError(res.err)
+}
+
 func encodeError(err error) error {
 	switch err {
 	case nil:
@@ -104,6 +129,8 @@ func encodeError(err error) error {
 		return status.Error(codes.InvalidArgument, "received invalid can access request")
 	case things.ErrUnauthorizedAccess:
 		return status.Error(codes.PermissionDenied, "missing or invalid credentials provided")
+	case things.ErrNotFound:
+		return status.Error(codes.NotFound, "entity does not exist")
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
@@ -264,3 +264,55 @@ func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id strin
 
 	return lm.svc.Identify(ctx, key)
 }
+
+func (lm *loggingMiddleware) SaveSchema(ctx context.Context, token, chanID string, schema things.Schema) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_schema for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SaveSchema(ctx, token, chanID, schema)
+}
+
+func (lm *loggingMiddleware) ViewSchema(ctx context.Context, token, chanID string) (_ things.Schema, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_schema for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewSchema(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) RemoveSchema(ctx context.Context, token, chanID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_schema for token %s and channel %s took %s to complete", token, chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveSchema(ctx, token, chanID)
+}
+
+func (lm *loggingMiddleware) ChannelSchema(ctx context.Context, chanID string) (_ things.Schema, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method channel_schema for channel %s took %s to complete", chanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ChannelSchema(ctx, chanID)
+}
@@ -192,3 +192,39 @@ func (ms *metricsMiddleware) Identify(ctx context.Context, key string) (string,
 
 	return ms.svc.Identify(ctx, key)
 }
+
+func (ms *metricsMiddleware) SaveSchema(ctx context.Context, token, chanID string, schema things.Schema) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "save_schema").Add(1)
+		ms.latency.With("method", "save_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SaveSchema(ctx, token, chanID, schema)
+}
+
+func (ms *metricsMiddleware) ViewSchema(ctx context.Context, token, chanID string) (things.Schema, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_schema").Add(1)
+		ms.latency.With("method", "view_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewSchema(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) RemoveSchema(ctx context.Context, token, chanID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_schema").Add(1)
+		ms.latency.With("method", "remove_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveSchema(ctx, token, chanID)
+}
+
+func (ms *metricsMiddleware) ChannelSchema(ctx context.Context, chanID string) (things.Schema, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "channel_schema").Add(1)
+		ms.latency.With("method", "channel_schema").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ChannelSchema(ctx, chanID)
+}
@@ -485,3 +485,61 @@ func disconnectEndpoint(svc things.Service) endpoint.Endpoint {
 		return disconnectionRes{}, nil
 	}
 }
+
+func saveSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(saveSchemaReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema := things.Schema{
+			Type:       req.Type,
+			Definition: req.Definition,
+		}
+		if err := svc.SaveSchema(ctx, req.token, req.id, schema); err != nil {
+			return nil, err
+		}
+
+		return saveSchemaRes{}, nil
+	}
+}
+
+func viewSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		schema, err := svc.ViewSchema(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewSchemaRes{
+			Type:       schema.Type,
+			Definition: schema.Definition,
+		}
+
+		return res, nil
+	}
+}
+
+func removeSchemaEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveSchema(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
@@ -1745,6 +1745,250 @@ func TestRemoveChannel(t *testing.T) {
 	}
 }
 
+func TestSaveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	schs, _ := svc.CreateChannels(context.Background(), token, channel)
+	sch := schs[0]
+
+	data := `{"type":"senml","definition":{"fields":{"temp":{"type":"float","unit":"Cel"}}}}`
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "save valid schema",
+			req:         data,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "save schema with invalid token",
+			req:         data,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save schema with empty token",
+			req:         data,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "save schema of non-existent channel",
+			req:         data,
+			id:          strconv.FormatUint(wrongID, 10),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "save schema of unknown type",
+			req:         `{"type":"xml","definition":{}}`,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save schema with malformed definition",
+			req:         `{"type":"senml","definition":{"fields":{"temp":{"type":"int"}}}}`,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save schema without definition",
+			req:         `{"type":"senml"}`,
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save schema with invalid request format",
+			req:         "}",
+			id:          sch.ID,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "save schema without content type",
+			req:         data,
+			id:          sch.ID,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/channels/%s/schema", ts.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestViewSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	schs, _ := svc.CreateChannels(context.Background(), token, channel, channel)
+	sch, other := schs[0], schs[1]
+
+	schema := things.Schema{
+		Type:       "json",
+		Definition: []byte(`{"type":"object","required":["temp"]}`),
+	}
+	err := svc.SaveSchema(context.Background(), token, sch.ID, schema)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	data := `{"type":"json","definition":{"type":"object","required":["temp"]}}`
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+		res    string
+	}{
+		{
+			desc:   "view existing schema",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusOK,
+			res:    data,
+		},
+		{
+			desc:   "view schema of channel without schema",
+			id:     other.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view schema of non-existent channel",
+			id:     strconv.FormatUint(wrongID, 10),
+			auth:   token,
+			status: http.StatusNotFound,
+			res:    "",
+		},
+		{
+			desc:   "view schema with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+			res:    "",
+		},
+		{
+			desc:   "view schema with empty token",
+			id:     sch.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+			res:    "",
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/schema", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body := strings.Trim(string(data), "\n")
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, body))
+	}
+}
+
+func TestRemoveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	schs, _ := svc.CreateChannels(context.Background(), token, channel)
+	sch := schs[0]
+
+	schema := things.Schema{
+		Type:       "json",
+		Definition: []byte(`{"type":"object"}`),
+	}
+	err := svc.SaveSchema(context.Background(), token, sch.ID, schema)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove schema with invalid token",
+			id:     sch.ID,
+			auth:   wrongValue,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "remove existing schema",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed schema",
+			id:     sch.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove schema with empty token",
+			id:     sch.ID,
+			auth:   "",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/schema", ts.URL, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
 func TestConnect(t *testing.T) {
 	otherToken := "other_token"
 	otherEmail := "other_user@example.com"
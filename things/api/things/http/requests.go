@@ -3,7 +3,11 @@
 
 package http
 
-import "github.com/cloustone/pandas/things"
+import (
+	"encoding/json"
+
+	"github.com/cloustone/pandas/things"
+)
 
 const maxLimitSize = 100
 const maxNameSize = 1024
@@ -269,3 +273,22 @@ func (req createConnectionsReq) validate() error {
 
 	return nil
 }
+
+type saveSchemaReq struct {
+	token      string
+	id         string
+	Type       string          `json:"type"`
+	Definition json.RawMessage `json:"definition"`
+}
+
+func (req saveSchemaReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Type == "" || len(req.Definition) == 0 {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
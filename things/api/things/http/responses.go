@@ -4,6 +4,7 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -20,6 +21,8 @@ var (
 	_ mainflux.Response = (*channelsPageRes)(nil)
 	_ mainflux.Response = (*connectionRes)(nil)
 	_ mainflux.Response = (*disconnectionRes)(nil)
+	_ mainflux.Response = (*saveSchemaRes)(nil)
+	_ mainflux.Response = (*viewSchemaRes)(nil)
 )
 
 type removeRes struct{}
@@ -262,3 +265,34 @@ type pageRes struct {
 	Offset uint64 `json:"offset"`
 	Limit  uint64 `json:"limit"`
 }
+
+type saveSchemaRes struct{}
+
+func (res saveSchemaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res saveSchemaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res saveSchemaRes) Empty() bool {
+	return true
+}
+
+type viewSchemaRes struct {
+	Type       string          `json:"type"`
+	Definition json.RawMessage `json:"definition"`
+}
+
+func (res viewSchemaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewSchemaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewSchemaRes) Empty() bool {
+	return false
+}
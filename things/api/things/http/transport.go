@@ -146,6 +146,27 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Put("/channels/:id/schema", kithttp.NewServer(
+		kitot.TraceServer(tracer, "save_schema")(saveSchemaEndpoint(svc)),
+		decodeSchemaSave,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/schema", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_schema")(viewSchemaEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/schema", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_schema")(removeSchemaEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/channels", kithttp.NewServer(
 		kitot.TraceServer(tracer, "list_channels")(listChannelsEndpoint(svc)),
 		decodeList,
@@ -327,6 +348,22 @@ func decodeChannelUpdate(_ context.Context, r *http.Request) (interface{}, error
 	return req, nil
 }
 
+func decodeSchemaSave(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := saveSchemaReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
 func decodeView(_ context.Context, r *http.Request) (interface{}, error) {
 	req := viewResourceReq{
 		token: r.Header.Get("Authorization"),
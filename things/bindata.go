@@ -100,7 +100,7 @@ func distStaticJsRedocStandaloneJs() (*asset, error) {
 	return a, nil
 }
 
-var _distStaticSwaggerSwaggerYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xec\x5c\x4b\x73\x1b\xb9\x11\xbe\xf3\x57\x74\x29\xa9\x72\x52\x25\x51\xf4\x66\xf7\x10\xde\xb4\x56\x5c\x61\xe2\x57\xd9\x52\xae\x29\x70\xd0\x24\x61\xce\x00\x23\x00\x23\x99\xfb\xf8\xef\x5b\x78\x0e\x86\x1c\x92\x43\x9a\x94\x68\xaf\x74\x58\x69\x49\x4c\xa3\xd1\xe8\xfe\xfa\x39\x56\x0f\x64\x3a\x45\x39\x84\xb3\x1f\xfa\x83\xb3\x1e\xe3\x13\x31\xec\x01\x68\xa6\x73\x1c\xc2\x5b\xc2\xf8\x24\xaf\xbe\x80\x9e\x31\x3e\x55\xa0\x50\xde\xb3\x0c\x7b\x00\x14\x55\x26\x59\xa9\x99\xe0\x43\xf8\xf7\xcd\xcd\x07\xb8\xfa\x30\x82\x89\x90\x50\x10\x4e\xa6\x8c\x4f\xa1\xcc\x89\x9e\x08\x59\x84\x87\x09\xa7\x90\xcd\x08\xe7\x98\xab\x7e\x0f\xe0\x1e\xa5\xb2\x8f\x9f\xbd\xec\x0f\xcc\xe6\x99\xe0\xaa\x2a\x50\x19\x06\x2e\xe0\x8c\x94\x65\xce\x32\x62\xb6\xb8\xfc\xac\x04\x3f\xeb\x95\x52\xd0\x2a\xdb\xb4\x80\xe8\x99\xfd\xf6\xd2\xed\x69\xfe\x04\x28\x85\xd2\xee\x2f\x00\x55\x15\x05\x91\x8b\x21\x5c\x51\xaa\x80\xe3\x83\xe3\xce\x7f\xdb\x38\xd5\x6f\xfe\x43\x58\x5a\x0b\x5a\x80\x9e\x21\xe4\x4c\x69\x10\x93\x70\x3c\xf1\xc0\x91\xc2\x78\x01\x95\x42\x09\x8c\x22\xd7\x6c\xc2\x90\x42\xa5\xea\x0d\xc0\x3e\x59\x4a\x71\xcf\x28\x52\x20\x59\x86\x4a\x81\x16\x73\xe4\x7d\xbf\x44\x93\xc0\xb7\xf9\xb9\xf0\xe4\xfd\x07\x25\x91\xa4\x40\x8d\xb2\xb1\xe4\xaf\x12\x27\x43\x38\xfb\xcb\x65\xfd\xf5\xe5\x55\xa5\x67\x42\xb2\x5f\xac\x78\xce\x92\xc5\x9c\x14\x38\x6c\x9c\x7a\xe5\xe4\xff\xf9\xf4\xfe\xdd\x85\xb9\x3a\xa2\x35\x52\xa0\x22\xab\x0a\xe4\xda\x2f\x1a\x5b\x19\xcc\xb0\x96\x48\x3f\x21\xc4\xf8\x10\xc6\x82\x2e\x92\x8f\x54\x36\xc3\x82\x0c\x93\x4f\xa0\x66\x99\xe2\x84\x71\x66\xf6\x55\x97\xaf\x24\x12\x8d\x37\x86\xe4\x47\xbc\x3b\x4b\x1e\x90\x78\x57\x31\x89\x74\x08\x5a\x56\xd8\x0b\x1f\xaa\x52\x70\x85\x89\x2c\x7e\x18\xbc\x1c\xae\x3b\x95\xa5\x0b\x12\xa7\x4c\x69\x94\x48\x53\xae\x67\x48\x68\x43\xa8\xe6\xe7\x8d\x70\xca\xd5\xfc\x14\x40\x2f\x4a\x1c\x82\xd2\xb2\x29\xc2\x95\x0d\xdd\x71\xa8\x93\xd1\x0b\x05\x12\x73\xa2\xd9\x3d\xc2\xed\xc7\x37\xf0\x37\xd6\xc7\x7e\xd0\xd3\xcb\x5f\xed\xef\x11\xfd\xfd\xef\x35\x57\x3f\x0e\x06\x6b\xcf\xf2\x9a\xb0\xdc\xdc\x4c\x85\x46\x1b\x0b\x92\x9b\xdb\x42\x6a\x6f\x2e\xa5\xf0\x8f\xb5\x14\xde\x32\x65\xf4\x12\x84\x04\xc6\xef\x49\xce\x9a\xda\x18\x75\x34\xa1\xf6\xf2\xa7\x5d\xa8\x65\x82\x6b\xa3\x34\x46\x5a\x35\x91\x9f\x9a\x87\x8a\x6a\x10\xef\xf2\xf2\x93\xc3\x98\x7f\x49\x29\xa4\x53\x81\x29\xae\x9a\xef\x47\xd4\x92\xe1\x3d\x2a\x87\x37\x41\xca\x6a\xb3\x21\xd7\x4f\x91\x68\xbe\xcd\xe7\xfb\x70\xed\x64\x5a\xa2\xb4\x16\xc0\x33\x34\x47\xc9\x50\x72\x75\x0e\x94\x68\x12\xa9\x31\x73\xa5\x8e\x20\x05\xc6\x41\x55\x63\x85\x5a\xf5\xe1\x66\x86\x16\x0e\x3d\x32\x14\x95\xd2\x80\x5c\x55\x12\x41\xcf\x88\xb6\xc6\x63\xe0\x41\x62\xa4\x65\x08\x2b\xd4\x86\xa6\x87\x41\x0a\xc8\xf4\x0c\xa5\x81\x94\x82\xcc\x8d\x74\xed\x06\x77\x95\x91\xaa\x31\x08\x54\x5a\x9d\x1b\x91\x8f\x6b\x6b\x63\x3c\x93\x48\x54\x30\x51\xc7\x12\x28\xf6\x0b\x3a\xa8\x42\xb0\xd6\x46\xf2\x40\xe1\xb1\x60\xa7\x6d\xf1\x1b\x56\x30\xbd\x6d\xd1\xfb\xc9\x44\xe1\xd6\x55\xef\x48\x81\xdb\xd6\xbc\x45\x4d\x8c\x9c\xcf\x36\xe1\xc7\x7a\x9b\xbb\x26\x9a\xd4\x17\xde\xdf\x07\xe0\x2c\x04\xa9\x0f\x64\x9a\xf0\xba\x8f\x99\xdf\x55\x28\x17\xc9\xad\x1c\xcb\xe4\x77\xb7\xd6\x00\x68\xe3\x2a\x9f\x6f\xf0\xbe\x3f\x57\xf9\xdc\xed\x67\x42\x80\xc4\xb9\x6e\x31\xe0\xa6\x27\x56\xdf\x8f\x2b\x56\x87\xf2\xc5\xea\x1b\x72\xc6\xaa\xd5\x1b\x3f\xfb\x3d\x67\x49\x21\x56\xbe\xfc\xd5\xfc\x35\xa2\xbf\x37\x82\xda\xcd\x4e\x71\xc9\x20\x32\xc1\x39\x66\x36\x16\x11\xa0\x4a\xcc\x9c\x39\xf8\x1d\xba\xfa\xcc\x9d\x88\xc2\x03\xd3\x33\x28\x4d\x2a\x60\xf5\x3e\xd2\x2b\x3c\x0a\x1f\xd8\xc8\x5e\x59\x19\x1d\xc6\x99\x34\xfc\xd2\xb3\x97\x38\x9e\x97\x88\x61\x6f\x17\x9d\x76\xc9\x97\xc9\x50\x9f\x30\x64\xb9\x71\x0c\x3f\xb1\x72\x7c\x44\x75\x76\x2c\xb4\x1b\xfc\xb8\x25\x83\xa2\x02\x15\x70\xa1\x01\xbf\xb0\x3a\x7e\xdc\x2f\xb4\x2f\xab\xd5\x1b\xbf\x2d\x29\xd1\x6d\xf7\xbd\x06\xa0\xdc\x7a\x13\x39\xfb\x98\xdd\xf9\x7e\x89\x65\x4e\xb2\xe0\x1f\xb3\x4a\x4a\x17\x37\x2b\x51\xc9\x0c\x6d\xc0\xed\x30\xea\x9e\xe4\x15\xd6\xfe\x37\x46\x04\x8c\x03\x09\x51\x32\x94\x64\x91\x0b\x42\xfb\xf0\x4e\xe8\x24\x8a\x0f\xa9\x9d\x01\x7b\x5b\xe0\x18\x5d\x47\x42\x19\xe1\x46\x4a\x63\xb4\x90\x38\xad\x85\x7c\x02\x7a\x7b\xd8\x42\x40\x65\x6f\x80\x1e\xb6\x18\xe0\xae\xf5\xeb\xe2\x8f\xf5\x96\xe8\x54\xd9\x33\x7e\xc2\xb1\xc7\xde\xd6\xf8\x64\xd9\x3a\xc5\x1c\x35\xb6\xe0\x78\x21\x5c\xe2\xdd\xa1\xe0\xb6\xb4\xd8\x65\xd4\xbe\xf8\x08\x0f\x2c\xcf\x57\xd2\x69\x69\x9f\xa0\x01\x34\x6a\x73\xa6\x4c\xd5\x81\xca\x44\x8a\x02\x48\x9e\x87\x5c\xd8\xca\xcc\x3c\x90\xd6\x25\xe1\x34\x4c\xb4\x55\xa1\xb7\x69\x83\x17\xc3\xd7\x29\x74\x00\xb5\xd1\xf5\x09\x47\x0c\x97\x73\x5c\xf8\x04\x93\xe8\x6c\xb6\xc5\x8b\xcc\x71\xf1\x95\x4e\x24\x38\x90\x39\x2e\x9c\xdb\x20\x36\xe7\x12\x1c\x4f\x48\x67\x6a\x58\xaf\x0f\xbc\x72\xe8\x0e\xa0\x1e\x00\x7d\x8e\x8b\xc3\xc2\xf9\x7f\x71\x71\x34\x30\x37\x37\xf3\x3d\x03\xfa\xe0\x9f\x6b\x9f\xfc\x14\xb3\x2f\x23\x04\x92\x4b\x24\x74\xe1\x08\xa8\x27\x77\x09\x31\x91\xdd\x50\x0f\x72\xc5\x06\x57\xda\xe9\x94\x95\xb6\x3c\xd0\x87\xdb\xa5\x72\xcf\x78\xb1\xbe\xc6\x63\xdd\x48\x24\x37\x46\x17\x25\x3a\x4a\x2f\x5c\x09\x49\xae\xb3\xec\x70\xa0\xc3\xd9\xb6\x33\xda\xe6\xc9\x57\x4e\xbf\x43\x29\x28\x88\xe4\x40\xc6\xfb\xca\x91\x3b\x42\x1d\xc8\x53\x86\xcc\xf5\x4a\x1e\xad\x27\x53\xdf\x74\x5b\x57\x66\xa5\xf4\xf2\xdc\x96\xd9\xb7\x2d\xb3\x64\x2b\x7b\x34\x66\x62\x6c\xf6\xdc\x9a\x81\x23\xc1\xcf\x23\x36\x67\x1e\xbf\x62\xe2\x21\xe6\xfb\x2e\xa8\x45\xc4\xda\xa3\xf1\xd2\xcd\x44\x63\xeb\x25\x2c\x5f\x6e\xbe\xc4\xcf\x8f\xd5\x7e\x39\xb2\xdb\x3d\x44\x0b\x66\x39\x8f\x84\x83\x34\x61\x82\x06\x1f\xcf\xff\x3e\x77\x62\x76\xea\xc4\x74\x29\x57\x87\x3e\xc8\x86\x82\xf5\xe3\x00\x79\xb3\x33\xf2\x64\xf8\xfb\x44\x35\xeb\x10\x60\x3e\x56\xd5\xba\xe5\xda\x4f\xb0\x6e\x5d\x87\xbf\xa3\x6b\x57\x50\x73\xa5\xea\x48\x8d\x4c\x26\xb6\x64\xf6\xb4\x31\xc8\x6a\x53\xef\x90\x89\x52\xa8\x72\x9c\x58\xb2\xb4\xde\x18\x83\x2e\x7f\xd3\x55\x8e\x6d\x06\x79\xba\x85\xeb\x8e\xed\xf2\xa5\xe5\x1d\x8a\xd7\x26\x15\x30\x8a\x69\xe2\xa0\xb2\xb4\x93\xb2\x49\x94\xe4\x86\x67\x25\x42\xc5\x93\x85\xb6\x94\x9d\x56\xbe\x97\xb4\xf8\x14\x0c\x76\xc7\x12\x76\xd0\x8c\x83\x14\xb1\x53\x84\x3b\xa1\x38\x7d\xa5\x8c\xdd\x2c\x8e\x75\x9b\xea\x88\x91\xf6\x9a\x11\x8c\x4e\x1d\x96\xbd\x88\xee\x3d\xd5\xb1\xb7\xfa\xb5\x54\xb8\xbf\xd9\xc1\x8e\x3f\x57\x26\xba\x34\xb6\xb4\x3c\xec\xd1\x16\x44\xbd\x72\xaa\xa7\xea\xde\x7a\x48\x32\x77\x2a\x0a\x7b\x0d\x66\x82\xc3\x18\xf5\x03\x22\x0f\x9d\x44\xdb\xa3\x4f\xa0\xf9\xbd\xaf\x21\x45\x85\x6f\x64\xa7\x7e\xd9\x79\x0a\xc2\x24\xcf\xc5\x83\xb3\x0d\xfc\xe2\x1a\xfb\x50\xa0\x52\x64\x6a\x9b\x4d\x52\x54\xd3\x19\xb0\x27\xae\xdd\x74\x1b\x86\xfa\xba\x59\x16\xd7\xad\x88\xb2\x7b\xca\x50\x42\x48\x7f\xbb\x87\x0c\xf2\xd7\x84\x03\xd7\xb1\x9f\x9c\x6a\x69\xf4\xc6\x3b\xc5\x08\xfb\xe9\xa9\x59\x9e\x34\xb8\xbd\xb7\xf4\x4a\x0a\x19\xe1\xc0\x05\xe4\x82\x4f\x51\x6e\x50\xd1\x84\xd9\xef\x45\x57\xb7\x76\xd6\x92\x51\x80\xef\x4c\x5d\x5b\x50\xd7\x31\xba\xa9\xe7\x36\xc3\x6c\xae\x80\xf9\x81\x4e\x98\x11\x55\x9f\x2e\x51\xbe\x2d\x98\x1b\xa9\x90\x34\x44\x20\x49\xe4\x30\xc7\x85\x23\xce\x1b\xf4\xe3\x82\x48\x2b\xe4\xce\x46\xf9\xd9\x04\x98\x36\x8f\x9d\x9b\xdf\x12\x75\x25\xb9\x0a\x21\xb3\x45\x63\xb6\x36\x39\x75\xbb\xec\xac\xb6\xeb\xb2\x4d\x7b\xd7\xbb\xe6\x9a\x96\x53\x93\x9c\x10\xc6\x93\x29\x84\x43\x25\x99\x23\x5b\x55\xd5\xc7\xe8\xa6\xff\xd6\xd8\xfd\xa6\x45\x37\x6c\xce\xb2\x32\xf0\x6b\x6e\xad\x46\xc4\xd1\xb5\x6f\xc2\x54\x92\xef\x19\x2e\x85\x33\x76\x2b\x1b\xb5\xb1\x9d\xbc\x72\x68\x9d\xb7\xb1\xb5\x08\x01\xe7\xb5\x21\x5a\x95\x6d\x2a\xac\xb1\x4d\xfe\x42\x37\x88\x9e\x46\xa6\xba\xd6\xd6\x2f\xc6\x8b\x0b\x46\x4f\xc1\xe2\x47\xd7\x3b\x19\xfc\x9f\xd8\x8e\xaf\xec\x11\x7f\x5e\x8c\xae\x8f\x36\x17\xd3\xc1\x76\xbb\x79\xc3\x03\x5a\xd8\xe8\xfa\x94\x0d\xcc\xb7\xac\x16\x1b\x4c\xe9\x7f\x66\xc7\x7a\xc0\xec\x85\x72\x93\x37\x9c\x46\x57\xc5\xb4\xab\xae\xb2\x89\xfd\x8a\x29\xb0\x5c\x6e\x31\xae\x9d\xe8\x36\x11\xcb\x8d\xfb\x44\x4a\xd6\x89\x2e\x6d\xba\x6c\x60\xcc\x23\xec\x46\x13\x3b\x5d\xe3\x39\xa2\x13\xbc\x09\x4e\xec\x11\x3d\xd8\xcd\x16\x5f\x74\x12\xe6\xd1\x6b\x2a\x49\x23\xd4\x77\x84\x9c\xbe\x34\xbe\xe8\xad\xf0\x77\xab\x50\xbe\x50\x2d\x6d\x5f\xa3\x0f\x6e\xf2\xc7\xfe\xef\xca\x5c\xcf\xca\xe5\x3a\xb4\x4f\xb7\x76\x5e\xb1\x65\x4f\xce\xee\x2a\xac\x3b\x34\xa1\x31\x2d\xeb\x9d\x4b\xa2\x67\xc9\xbe\x8c\x6b\x9c\x7a\x4e\x0a\xc6\x59\x51\x15\x43\x78\xd9\xce\x87\xcf\x50\x52\x46\x7c\xc1\x63\x2d\x27\x7e\x58\xf9\xb0\x7c\xd8\x1a\x57\xca\x45\x6e\x3e\x58\xe5\xe1\x53\x32\x78\xe2\x67\x51\xb4\x88\x05\xae\x9a\x15\x5b\x6a\x5a\xc3\x0b\xc5\x09\xa9\x72\x3d\x84\x97\x03\xc7\x1b\xf9\xe2\x79\x1b\x0c\x36\x33\x3b\x21\xb9\x32\xdc\xba\xba\x5d\xca\xae\xb0\x9f\xac\xf2\xfb\xae\x2a\xc6\x28\x0d\xc7\x4c\x63\x61\x7d\x99\x9a\xb3\x12\x68\x25\xdd\xe4\xb3\x65\x9c\xe4\x3b\x72\xbe\xc4\xe7\x60\x0d\x9f\xef\x0c\x6f\x09\x97\xe6\xbf\x2d\x3c\x92\x02\x61\xc2\x72\x8d\xb2\x0f\xaf\xed\x6f\x37\x8e\x9e\xb4\xf8\x88\xed\x0d\x10\x85\x17\x8c\x2b\xe4\x8a\xd9\xe1\xb3\x92\x48\x3b\xfa\x53\x10\x9d\xcd\xd6\x1f\x21\x31\x84\xad\x2c\x87\xd7\x9d\x53\xb6\x43\x99\x76\x95\xf5\xb0\x7a\x1b\xfb\x96\xc1\xd0\x41\x8b\x58\xe0\x50\x2b\x50\x07\xc1\x41\x8b\x12\x72\xbc\xc7\xbc\x0f\x1f\xe2\x2a\xa6\xe0\xb3\x12\xfc\x10\xe7\xeb\x35\x00\x3d\xc5\xa8\x61\x9b\xbd\xe1\x97\xd2\x95\xf8\x14\xca\x7b\x94\x17\x8a\x51\x04\x34\xcb\x41\x64\xb6\xc9\x4a\xfb\xbd\x5e\x82\xdd\x86\xca\xca\xf4\xc5\x30\x61\x95\x48\x49\x1c\xeb\x56\x1f\x03\x88\x76\x68\x09\xa6\x65\xe0\x94\xa2\x18\x7f\xc6\xcc\x69\x7e\x29\x45\x89\x52\xb3\xda\x5d\x35\xfb\x04\x6d\x6c\x78\xa1\x8d\x2c\x37\x5e\x6a\xe6\xa7\xb2\x60\xe3\x3f\x4e\x3c\xe2\x12\xe3\xdb\x99\x8f\xfd\x7b\x2d\x34\xc9\x97\x19\x49\xad\x6b\xd5\xb5\x99\x27\x80\x37\x4d\x38\x38\x21\x91\x60\x40\x47\x7a\xbb\x81\x01\x38\x0c\xdc\x69\x87\xb7\x0e\xca\x96\x79\xf6\x18\x59\x49\x0e\x8c\x83\xe0\xc6\x06\xa6\x1e\x2d\xa3\x8a\x7a\x8a\x8d\x0a\x5a\x2d\xc5\xce\x57\xce\xe8\x32\xc7\x4b\xf3\xad\xdd\xbc\x1b\x4c\x91\xa3\xb4\x9d\x6e\x3f\x10\xed\x9b\x91\x41\x38\x3c\x62\x5b\xb7\x9d\x5e\x4b\x44\x1b\xed\xc5\xcd\x0c\x85\x40\xad\x68\xc0\x4e\xeb\x51\x57\x28\x5e\xc9\x31\xd3\x92\xc8\xc5\xb9\x5f\x76\x81\x3c\x13\xb4\xd1\xc6\xab\xbb\x4b\xab\x72\x66\x34\x95\xf0\x5d\x67\x09\x7f\x5b\x27\xaf\xdf\x0b\xee\x7c\xc0\xf4\x8d\x71\x78\x44\xcc\x58\x7e\x4b\xf5\x19\x31\x3a\x22\x46\x7c\x69\x28\x48\xf0\xb8\x68\xb1\x1c\x81\x1e\x11\x2b\xdc\x56\xa9\xbd\xc4\x37\xb8\xba\x91\xba\xaa\xb4\xb8\xa8\xf9\xf3\xa9\x43\x92\x4e\x1e\xd6\xfe\x42\xfe\xbd\x15\x77\x20\x66\xc9\xf1\x7f\xdc\xdb\x57\xcd\x7f\x41\xa3\xf3\x45\xee\x28\x96\xb4\x2a\x53\xbf\x08\x65\x33\x6f\xa6\xa0\x52\x48\xed\x3f\xcb\xe6\xdb\x4a\x95\x9e\xf5\x61\x64\xa3\x7e\x89\x69\x1b\x09\x6c\xe9\xc6\xaa\x67\x18\x24\x6b\xcc\xac\x04\xc1\xdb\x25\x8c\xc3\xed\x6d\xf2\xaa\x33\x80\x4b\xfe\x8f\xa1\x25\xc7\xbd\xd5\xe6\x5b\xc6\x8f\xe0\x38\x9e\xe6\x80\xee\xbd\xbb\x63\xa9\x60\x37\xb5\xeb\x01\x24\x05\x9b\xee\x2e\x4c\xcc\x91\x1f\x85\x9b\x56\xfc\xf5\x65\xae\x46\x5d\x76\x37\x77\xfb\xff\xdd\x90\x38\x56\x9a\xc6\x0b\x78\x98\xb1\x6c\x16\xdf\x61\xf6\x5e\x38\x5f\x24\x33\xf4\xa9\x14\x8f\xe4\x1a\xfc\xab\xf1\xce\x41\xa4\xc5\x89\x3f\x02\x00\x00\xff\xff\x0e\xe6\xb7\x40\x2c\x52\x00\x00")
+var _distStaticSwaggerSwaggerYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x02\xff\xed\x5c\xdd\x73\x1b\xb9\x0d\x7f\xd7\x5f\xc1\x51\x3b\x93\xbb\x19\x4b\x56\xae\xc9\x4c\xab\x37\x5f\xdc\x4c\xdd\xc6\x89\x1b\xcb\xed\x63\x87\xda\xa5\x24\x9e\xf7\x43\x21\xb9\x76\x74\xb9\xfc\xef\x05\xf8\xb5\xdc\xd5\x4a\x5a\xc9\x92\xad\x24\xf6\x83\x2d\x53\x24\x08\x82\xc0\x0f\x20\x88\x5d\x79\x4f\xa7\x53\x26\x86\xa4\xfb\x4b\x7f\xd0\xed\xf0\x6c\x92\x0f\x3b\x84\x28\xae\x12\x36\x24\x97\x14\x1a\x92\xe2\x33\x51\x33\x9e\x4d\x25\x91\x4c\xdc\xf1\x88\x41\x87\x98\xc9\x48\xf0\xb9\xe2\x79\x36\x24\xff\x18\x8d\xae\xc8\xd9\xd5\x05\x99\xe4\x82\xa4\x34\xa3\x53\xe8\x4d\xe6\x09\x55\xd0\x90\xba\xc1\x34\x8b\x49\x34\xa3\x59\xc6\x12\xd9\x07\x12\x77\x4c\x48\x3d\xbc\xfb\xb2\x3f\xc0\xc9\xa3\x3c\x93\x45\xca\x24\x32\xd0\x23\x5d\x3a\x9f\x27\x3c\xa2\x38\xc5\xe9\x6f\x32\xcf\xba\x9d\xb9\xc8\xe3\x22\x5a\xd7\x81\xaa\x99\xfe\xf6\xd4\xcc\x89\x1f\x09\x99\xe7\x52\x99\x4f\x84\xc0\x04\x29\x15\x8b\x21\x39\x8b\x63\x49\x32\x76\x6f\xb8\xb3\xdf\x56\x56\xf5\x87\x6d\x24\xb5\xbe\x44\xe5\xf0\x81\x91\x84\x4b\x45\xf2\x89\x5b\x5e\x7e\x9f\xb1\x98\x8c\x17\xa4\x00\x29\x11\x1e\xb3\x4c\xf1\x09\x87\xa6\x42\x96\x13\x10\x3d\x12\xd6\x71\x07\x1d\x62\x42\x23\x58\x8d\x04\x82\xb7\x2c\xeb\xdb\x2e\x8a\x3a\xbe\xf1\xa7\x67\xc9\xdb\x86\x39\x15\x34\x65\x0a\x24\x17\x76\xf9\xb3\x60\x13\x10\xe3\x9f\x4e\xcb\xaf\x4f\xcf\x0a\x35\xcb\x05\xff\x5d\x8b\xa7\x1b\x74\xce\xa0\xc7\xb0\xb2\xea\xa5\x95\xff\xf3\xfa\xc3\xfb\x1e\x6e\x1d\x55\x0a\xb8\x8c\xf3\x08\xb6\x25\x53\xb6\xd3\x58\xcb\x00\x96\xe1\x25\xd2\x0f\x08\x71\x18\x3f\xce\xe3\x45\xd0\x24\xa3\x19\x4b\xe9\x30\x68\x21\x25\xcb\x31\x9b\xf0\x8c\xe3\xbc\xf2\xf4\x8d\x60\x54\xb1\x11\x92\xfc\xc8\x3e\x75\x83\x01\x82\x7d\x2a\xb8\x60\x31\x30\x2e\x0a\xd6\x71\x8d\x72\x0e\xc3\x58\x20\x8b\x5f\x06\x2f\x87\xab\x56\xa5\xe9\xc2\xa0\x29\xec\x1b\x03\x5a\x21\xd7\x33\x46\xe3\x8a\x50\xf1\xe7\x5d\x6e\x94\xab\xda\x0a\x3b\xb4\x98\x83\x04\xa5\x12\x55\x11\x2e\x4d\x68\x96\x13\x1b\x19\xbd\x90\x30\x35\x58\x04\xbf\x63\xe4\xe6\xe3\x3b\xf2\x13\xef\xb3\xbe\xd3\xd3\xd3\x2f\xfa\xef\x45\xfc\xf5\xe7\x92\xab\x57\x83\xc1\xca\xb5\xbc\xa5\x3c\xc1\x9d\x29\x18\x6a\x63\x4a\x13\xdc\x2d\x68\xc0\x9d\x0b\x29\xfc\x65\x25\x85\x4b\x2e\x51\x2f\x09\x58\x2c\xcf\xee\x68\xc2\xab\xda\xe8\x75\x34\xa0\xf6\xf2\xf5\x36\xd4\xc0\x9a\x15\x2a\x0d\x4a\xab\x24\xf2\xba\xba\x28\xaf\x06\x7e\x2f\x4f\xaf\x0d\xc6\xfc\x5d\x88\x5c\x18\x15\x98\xb2\x65\xf3\xfd\xc8\x40\xfc\xec\x8e\x49\x83\x37\x4e\xca\x72\xbd\x21\x97\xa3\xa8\x37\xdf\xea\xf8\x3e\x39\x37\x32\x9d\x33\xa1\x2d\x20\x8b\x18\x2e\x25\x62\x22\x93\x27\x24\xa6\x8a\x7a\x6a\x1c\xb7\xd4\x10\x8c\x61\xd9\xc0\xdc\x58\x32\x05\x24\x46\x60\x1c\x08\x87\x16\x19\xd2\x02\x66\x62\x80\x6d\x02\x28\xcf\xa8\xd2\xc6\x83\xf0\x20\x98\xa7\x85\x84\x61\x30\xd2\xb4\x30\x18\x13\xc6\xa1\xa3\x40\x48\x49\xe9\x2d\x4a\x57\x4f\xf0\xa9\x40\xa9\xa2\x41\x30\xa9\x80\xa5\x1c\x7b\x94\x3c\x65\x11\x68\x9d\x74\x26\x6a\x58\x22\x92\xff\xce\x0c\x54\x31\xa2\xad\x8d\x26\x8e\xc2\x63\xc1\x4e\x53\xe7\x77\x3c\xe5\x6a\x53\xa7\x0f\x93\x09\x2c\x61\x53\xaf\xf7\xf0\x61\x53\x9f\x4b\xa6\x28\xca\xb9\xbb\x0e\x3f\x56\xdb\xdc\x39\x0c\x2d\x37\xbc\xbf\x0b\xc0\x69\x08\x92\x57\xa0\x6f\xdd\x07\x99\x39\xec\x9c\x58\x04\xbb\x72\x28\x93\xdf\xde\x5a\x1d\xa0\x8d\x8b\xe4\x76\x8d\xf7\xfd\x15\xbe\x36\xf3\x61\x08\x10\x38\x57\xb9\x8d\x27\x96\xdf\x8f\x2b\x96\xfb\xf2\xc5\xf2\x1b\x72\xc6\xb2\xd1\x1b\x3f\xfb\x3d\x63\x49\x2e\x56\x3e\xfd\x82\x9f\x20\x36\xa8\x04\xb5\xeb\x9d\x62\xcd\x20\x80\xa7\x8c\x45\x3a\x16\xc9\x89\x9c\xb3\xc8\x98\x83\x9d\xa1\xad\xcf\xdc\x8a\x28\xb9\x07\xef\x05\x46\x02\x47\x01\xad\xf7\x9e\x5e\x6a\x51\x78\xcf\x46\xf6\x46\xcb\x68\x3f\xce\xa4\xe2\x97\x9e\xbd\xc4\xe1\xbc\x84\x0f\x7b\xdb\xe8\xb4\x39\x7c\xe1\x09\xf5\x09\x43\x96\x91\x61\xf8\x89\x95\xe3\x23\x93\xdd\x43\xa1\xdd\xe0\xd5\x86\x13\x54\x9c\xc3\x66\x64\x39\x84\xb4\x9f\x79\x19\x3f\xee\x16\xda\xcf\x8b\xe5\x1d\xbf\x99\x03\x3c\x34\xed\xf7\x0a\x80\x32\xfd\x31\x72\xb6\x31\xbb\xf1\xfd\x82\xcd\x13\x1a\x39\xff\x18\x15\x42\x98\xb8\x59\xe6\x85\x80\x98\x1e\x21\xc8\x60\x14\x88\x05\x02\x61\x4f\xce\x47\x04\x10\xd1\x53\x17\x25\x83\x3a\x2d\x92\x9c\xc6\x7d\xf2\x3e\x57\x41\x14\xef\x8e\x76\x08\xf6\x3a\xc1\x71\x71\xee\x09\x45\x80\x83\x20\xa5\x31\xd3\x90\x38\x2d\x85\x7c\x04\x7a\xbb\xdf\x44\x40\xa1\x77\x20\xde\x6f\x32\xc0\x6c\xeb\xc3\xe2\x8f\xc1\x06\x55\xb6\x8c\x1f\x71\xec\xb1\xb3\x35\x3e\xd9\x69\x3d\x66\x09\xa8\x5c\x03\x8e\xa7\xb9\x39\x78\xb7\x48\xb8\xd5\x3a\x9b\x13\xb5\x4d\x3e\x82\xd1\x26\xc9\xd2\x71\x5a\xe8\x11\xb1\x03\x8d\xd2\x9c\x63\x2e\xcb\x40\x65\x22\xf2\x94\x50\x18\x6e\xcf\xc2\x5a\x66\x38\x20\xcc\x4b\x1e\xb3\x6b\x79\xb5\x31\xbb\xa5\xc5\xf0\x30\x85\x76\xa0\x76\x71\x7e\xc4\x11\xc3\xe9\x2d\x5b\xd8\x03\x26\x55\xd1\x6c\x83\x17\x81\xce\x0f\x74\x22\xce\x81\x00\x25\xe3\x36\xa8\x3e\x73\xe5\x19\x3b\x4a\x58\x2f\x17\xbc\x0b\xa8\x3b\x40\x07\x2a\xfb\x85\xf3\x7f\xb1\xc5\xc1\xc0\x1c\x77\xe6\x7b\x06\xf4\xc1\xdf\x56\x8e\xbc\xf6\xa7\x2f\x14\x02\x4d\xe0\xe8\x1e\x2f\x0c\x01\x79\x3c\x07\xd9\x35\xf9\x20\x93\x6c\x30\xa9\x9d\x56\xa7\xd2\x86\x01\x7d\x72\x53\x4b\xf7\x80\x01\xaf\xcc\xf1\x68\x37\xe2\xc9\x8d\x99\x89\x12\x0d\xa5\x17\x26\x85\x24\x56\x59\xb6\x5b\xd0\xbe\x33\x41\xd5\x95\x3f\x24\x15\xe4\x44\xb2\xaf\x5c\x90\x21\x77\x80\x3c\x90\xa5\x4c\x22\x73\x57\xf2\x68\x77\x32\xe5\x4e\x37\xdd\xca\x2c\xa5\x5e\x9e\xaf\x65\x76\xbd\x96\xa9\xd9\xca\x0e\x17\x33\x3e\x36\x7b\xbe\x9a\x39\x14\xfc\x3c\xe2\xe5\xcc\xe3\x67\x4c\x2c\xc4\x7c\xdf\x09\x35\x8f\x58\x3b\x5c\xbc\xb4\x33\x51\x7f\xf5\xe2\xba\xd7\x2f\x5f\x7c\xfb\xa1\xae\x5f\x0e\xec\x76\xe5\xfe\xfc\xee\x9e\x2f\x61\x9c\x06\x1f\xce\xff\x3e\xdf\xc4\x6c\x75\x13\xd3\x26\x5d\xed\xee\x41\xd6\x24\xac\x1f\x07\xc8\xab\x37\x23\x4f\x86\xbf\x4f\x94\xb3\x76\x01\xe6\x63\x65\xad\x1b\xb6\xfd\x08\xf3\xd6\x65\xf8\x7b\x71\x6e\x12\x6a\x26\x55\xed\xa9\xd1\xc9\x44\xa7\xcc\xfa\x47\xa4\xba\xfb\x3e\x28\xb9\x2c\xc7\x91\x1d\x96\x06\x1b\x75\xf9\x9b\xce\x72\x6c\x32\xc8\xe3\x4d\x5c\xb7\xbc\x2e\xaf\x75\x6f\x91\xbc\xc6\xa3\x00\x2a\x26\xc6\x41\xf3\xb9\xae\x94\x0d\xa2\x24\x53\x3c\x0b\xfd\x8b\x2c\xe8\xa8\x53\xd9\x61\xe6\xbb\xa6\xc5\x47\xeb\x6b\x36\x6b\xc6\x5e\x92\xd8\x21\xc2\x1d\x63\x9c\xee\x8b\x3a\x42\x5c\x69\x17\x51\x98\x11\x6d\x0f\xd4\x5a\xc1\xf4\x08\xfd\x31\x85\x05\xc1\x41\x08\x9c\x4e\x31\x86\xc8\x7d\x66\xca\x37\x02\x9f\x80\xaa\xe6\xc9\x68\x31\x68\x90\xa4\x53\xca\x33\x70\x28\x36\xa7\x46\x63\x3a\x0f\x8f\x40\x3f\x66\x68\x73\xad\x3b\x3e\x65\x58\x83\xd4\x94\x74\x1b\x7c\xe8\x20\xe7\x4c\x29\x0a\x33\xf9\xf9\xaa\x9a\xb3\xe1\xfc\xe8\xc6\x86\x0a\x59\x19\x7f\x52\x8b\x7d\xa8\x19\x11\xeb\x4b\x16\x44\x51\x4f\x0b\x1f\x38\x38\x21\x97\xff\x1e\x8d\x4e\xc8\x9b\xfc\xec\x4a\x5f\xbd\xff\x97\x8d\xaf\xf3\xe8\x96\x29\xaf\x9c\x40\xef\x37\x88\x62\xcc\x49\xd3\xab\xbb\xb3\x00\x4f\xed\x7e\xc6\xa3\x19\xc8\x2e\x7b\xa1\xd0\x9d\x98\x67\x16\xf2\x80\xcf\xe3\x0c\x82\x2a\x30\xb0\x6b\x0c\x54\x5d\xe0\x43\x43\x9f\xba\x3d\xec\x27\xec\x31\x54\xbd\x3a\x3c\x3c\xec\x41\xab\x71\x16\xe3\xb9\x7f\x8e\x85\x36\xc7\x42\xe7\xac\x6a\xff\x3e\x08\xa9\x22\xc0\x37\x18\x7b\x5c\x3b\x75\x58\xd6\xb1\x63\xbb\xf8\xae\x5e\xa7\xb5\xab\x03\xf5\xb9\xb9\x15\x45\x9b\xad\x6a\x32\x76\x22\xba\x73\x1d\xe8\xce\x4a\xd3\x70\x27\xfe\xcd\x96\x82\xfe\x58\xb9\xeb\x5a\xa1\x73\xbd\x3c\xb4\x29\x22\x79\x63\x54\x4f\x96\xd5\x78\x5b\x05\x25\xee\x1a\xd9\x6a\x30\x7c\x4b\xc6\x4c\xdd\x33\x96\xb9\xda\x23\x1d\x5a\x04\x87\xb9\x0f\xf6\xd6\xc9\x2b\x7c\x25\x9f\xed\x23\x99\xe0\xd8\x46\x93\x24\xbf\x37\xb6\xc1\x3e\x9b\x52\xc0\x32\x0a\x57\x33\x91\x17\xd3\x19\x04\x71\xc7\x16\x64\xec\xbf\xfa\xd5\xd4\x37\x78\xd9\xf5\x9f\x36\x6c\x56\x7b\x2f\x66\x5d\xe5\x34\x7d\x05\x5a\xa8\xa5\x2b\x5c\xe7\x86\xac\xc2\x6e\x7a\x8a\xdd\x83\x92\x38\x7b\xbe\xb6\x4a\x8a\x75\xaa\x20\x01\x92\xe4\xa0\x98\x62\x8d\x8a\x06\xcc\x7e\x2f\xba\xba\xb1\x16\x27\x28\x1e\xfc\xce\xd4\xb5\x01\x75\x0d\xa3\xeb\xaa\x74\x66\x2c\xba\x95\x84\xdb\x47\x40\xc8\x8c\xca\x72\x75\x81\xf2\x6d\xc0\x5c\x4f\x85\x86\x21\x02\x0d\x22\x07\xac\x62\xd2\xc4\xb3\x0a\x7d\xdf\xa1\xac\xb1\x76\x39\x0b\x50\x7e\x20\xc8\x15\x0e\x3b\xc1\xbf\xe0\x77\x0b\x91\x49\x97\x64\xd3\x68\xcc\x57\xa6\xb3\xcd\x2c\x7b\x78\xe8\xc4\x16\x55\xe3\x5e\x6f\x7b\x32\xd3\x9c\x62\x08\x8f\x89\x96\xb2\x6e\x71\x5f\x67\xb3\x0b\x7d\x0f\xab\x0e\x51\x7f\xf7\x47\x65\xf6\x51\x83\x6e\xe8\xa3\xe6\xd2\x23\x42\xb8\x6b\x25\x22\x5e\x9c\xdb\xb2\x0d\xd8\xb7\x1d\xc3\x25\xb7\xc6\x76\x19\x99\x26\xb6\x83\x97\x14\x68\xe7\x8d\xb6\xe6\x21\xe0\xa4\x34\x44\xad\xb2\x55\x85\x45\xdb\xcc\x5e\xa8\x0a\xd1\xe3\x38\xcf\xad\xb4\xf5\xde\x78\xd1\xe3\xf1\x31\x58\x3c\x6c\xfe\x36\x06\xff\x03\xdb\xf1\x99\x5e\xe2\xaf\x8b\x8b\xf3\x83\x55\xd2\xb6\xb0\xdd\xfe\x63\x5b\x18\x28\xc8\x11\x1b\x98\x2d\x72\x59\xac\x31\xa5\xff\xd8\x54\xba\xf4\xf5\xf5\xba\x56\x17\xa4\xe1\x5c\x15\x57\xe6\x3e\x16\xec\x04\xbf\x02\x30\xd4\x5c\x6e\x30\xae\xad\xe8\x56\x11\xcb\x14\x08\x97\x77\xbe\xe8\x44\x6b\x93\xd6\x0d\x8c\x5b\x84\x5d\x6b\x62\x3f\xa4\x13\x1c\x39\x27\xf6\x88\x1e\x6c\xb4\xc1\x17\x1d\x85\x79\x74\xaa\x4a\x52\x09\xf5\x0d\x21\xa3\x2f\x95\x2f\x3a\x4b\xfc\x61\x71\xf7\x0b\xd9\x50\x28\x86\xfa\x60\x6a\x85\x3b\x8d\x95\xc0\x4b\x9b\x6b\xd0\x3e\x9c\xda\x78\xc5\x86\x39\x33\xfe\xa9\x28\x6f\xc6\x7c\x29\x9b\x28\x67\xc6\x57\x0e\x05\xf3\x72\x90\xd8\xd4\x72\x92\xc2\xae\xa6\x45\x3a\x24\x2f\x9b\xf9\xb0\x27\x94\x90\x11\x9b\xf0\x58\xc9\x89\x7d\xbc\x69\xbf\x7c\xe8\x1c\x57\xc8\x45\x82\x0d\xcb\x3c\x5c\x07\xa5\xaa\xb6\x7a\x15\xdc\x83\x4b\x70\x95\xac\xe8\x54\xd3\x0a\x5e\x40\xd7\x69\x91\x28\xe0\x65\x60\x78\xa3\x9f\x2d\x6f\x83\xc1\x7a\x66\x27\x34\x91\xc8\xad\xc9\xdb\x85\xec\xe6\xba\x65\x99\xdf\xf7\x45\x3a\x86\x13\x6d\x8e\xe7\x02\x96\x6a\x5f\x26\x6f\xf9\x9c\xc4\x85\x30\xcf\x4a\x69\xc6\x69\xb2\x25\xe7\x35\x3e\x07\x2b\xf8\xc4\xa2\xd7\x90\x4b\xfc\xdd\xc0\x23\xb4\x92\x09\x4f\xc0\x3a\xfa\xe4\xad\xfe\x6b\x1e\x60\x0b\x8a\x82\xa8\xae\x26\xa0\x92\xf5\x00\x13\x59\x26\xb9\x2e\x57\x07\xab\xd2\xc5\xc2\x29\x3e\xfe\xb4\x7a\x09\x81\x21\x6c\x64\xd9\xbd\x20\x25\x64\xdb\xa5\x69\x97\x59\x77\xbd\x37\xb1\xaf\x19\x74\xf7\x4d\x1e\x0b\x0c\x6a\x39\xea\x24\xcf\x60\x7b\xe6\x24\x01\x45\x4a\xfa\xe4\xca\xf7\x02\x4a\xf8\x6a\xaf\x7d\xac\xaf\x53\x01\xf4\x10\xa3\x86\x4d\xf6\xc6\x3e\xcf\x4d\x8a\x0f\x4b\x37\x98\xe8\x49\xb0\x3a\xc2\xb0\x3b\xc9\x23\x5d\x96\x05\x08\xdf\x09\xb0\x1b\xa9\x2c\xd5\x6b\x0e\x03\x56\xa9\x10\xd4\xb0\xae\xf5\xd1\x81\x68\x8b\x22\xa2\x30\x0d\x1c\x52\xcc\xc7\x78\xbf\xd9\xb1\xe5\x5f\x20\x72\xc5\x4b\x77\x55\xbd\x27\x68\x62\xc3\x0a\xed\x42\x73\x63\xa5\x86\x3f\x85\x06\x1b\xdb\x1c\x78\xc4\x1a\xe3\x5b\x54\xfc\xa9\x5c\xd1\xa4\xce\x48\x68\x5d\xcb\xae\x0d\x47\x90\xac\x6a\xc2\xce\x09\xe5\x01\x06\xb4\xa4\xb7\x1d\x18\x10\x83\x81\x5b\xcd\x70\x69\xa0\xac\xce\xb3\xc5\x48\x08\x09\xb0\x32\x2f\xcf\xd0\x06\xa6\x16\x2d\xbd\x8a\x76\x1a\x32\x68\xa5\x14\x5b\x6f\x39\x8f\xeb\x1c\xd7\x9e\x88\x69\xe7\xdd\xc8\x94\x65\x4c\xe8\xb2\x0f\x5b\xee\x61\xcb\x97\x9c\x70\x32\x8f\x6d\xed\x66\x7a\x2b\x18\xd3\xd1\x9e\x9f\x0c\x29\x38\x6a\x69\x05\x76\x1a\x97\xba\x44\xf1\x4c\x8c\xb9\x12\x10\x58\x9f\xd8\x6e\x3d\x96\x45\x79\x5c\x29\xfc\x29\x6f\x97\x96\xe5\xcc\xe3\x50\xc2\x9f\x5a\x4b\xf8\xdb\x5a\xf9\x75\x10\x76\xb6\x58\x9c\xee\xb2\x7e\x71\x0c\xb4\x3b\xb4\xff\x9e\x86\xe7\x4a\x03\xb8\xa8\x34\xe9\x6c\x3c\x0f\x8e\xf0\x95\x09\x2e\x9a\xd0\x7c\x9e\xb8\xc7\x6a\xa8\xb9\xa5\xb7\x77\xb2\x3f\xc5\x82\x4e\x14\x79\xf5\xb3\xeb\x7d\x3f\xcb\x13\x16\x50\xb2\x35\xae\x18\xb9\xfa\x94\x81\x79\x4a\xcc\x8d\xb8\x66\xd9\xe5\x3b\xf0\x53\x2c\x89\x65\x79\x9c\x72\x80\xb5\x8d\xf4\xc3\x15\x04\x4c\xda\x73\x6b\x75\x26\x8c\xc4\x4b\x13\x12\x2c\xca\x45\xac\xb7\x5f\xc2\x4a\xfb\x95\x17\x25\x7c\xe9\x9a\x21\xdd\x21\x7c\x04\xd4\x98\x9b\x0f\xc0\x0e\x7c\xe8\x4e\x60\x79\xaa\x7b\x42\xba\x00\xcc\x0a\x1b\xde\xb0\x04\xff\x05\xec\x86\xff\x7a\xaf\x06\x27\x01\xa9\x2e\x84\x54\xd0\xfa\xd7\xd7\xd0\xc1\xe9\x7d\xd7\xc0\xf8\xd7\xaf\xd0\x86\x1b\x1a\x29\xd7\xb2\xca\x3e\x70\x6a\xff\x4f\x29\x2a\x17\xb5\x6e\xe7\x89\xc2\xf7\x16\x3d\xa6\x1f\xaa\xbf\x2b\xe5\xd9\x0b\xb5\xf4\x42\xfe\xd1\x75\x27\xc1\xc3\x7a\xa0\xfa\xa9\xe6\x80\xfe\xc7\x4c\x15\x62\xb0\x7f\x8f\x40\x3b\x52\x70\x4a\xcd\x7b\x25\x7f\xf6\x38\x1a\xa4\x28\xf6\x8b\xe9\x2e\xa7\xb3\xd1\x97\x95\x99\x17\xff\x8f\x79\x07\x40\xf5\x3d\x6e\xad\x37\x72\x4b\xb1\x54\x90\xdd\x3f\x8e\xaf\xb3\x39\x10\xc2\x17\x12\x2b\x96\x7d\x6a\x8f\xc2\x49\xbf\x4f\x2e\x34\x36\x0b\x16\x5e\x4d\x12\x9d\x0e\xd4\xea\xe9\x1e\x67\xa8\x54\x4e\x3b\xc1\xeb\x2e\xa0\xc8\x37\x37\xc1\x0b\x77\x08\x31\x09\xa5\x43\x68\xc9\x61\x77\xb5\xfa\xae\x9b\x47\x08\x46\x9e\x66\x81\xe6\xed\x0f\x87\x52\xc1\x76\x6a\x07\x63\x83\x24\x60\x7b\x17\x86\xf9\xa6\x83\x70\xd3\x88\xbf\x36\x75\x5a\xc9\xf5\x6f\xe7\x6e\xff\xb7\x1d\x12\xfb\xec\x25\x00\xae\xa9\x87\x75\x6f\xd2\xb1\x5e\x38\x59\x04\x4f\x72\x86\x52\x3c\x90\x6b\xb0\x2f\x68\x32\x0e\x22\x4c\x78\xfd\x1f\x06\x31\xea\x8a\xb2\x5c\x00\x00")
 
 func distStaticSwaggerSwaggerYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -115,7 +115,7 @@ func distStaticSwaggerSwaggerYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "dist/static/swagger/swagger.yaml", size: 21036, mode: os.FileMode(436), modTime: time.Unix(1587694201, 0)}
+	info := bindataFileInfo{name: "dist/static/swagger/swagger.yaml", size: 23730, mode: os.FileMode(436), modTime: time.Unix(1792368202, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
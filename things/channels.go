@@ -14,6 +14,13 @@ type Channel struct {
 	Metadata map[string]interface{}
 }
 
+// Schema is the specification the messages published to a channel are
+// validated against by the adapters, its definition is encoded as JSON.
+type Schema struct {
+	Type       string
+	Definition []byte
+}
+
 // ChannelsPage contains page related metadata as well as list of channels that
 // belong to this page.
 type ChannelsPage struct {
@@ -63,6 +70,17 @@ type ChannelRepository interface {
 	// "connected" to the specified channel. If that's the case, then
 	// returned error will be nil.
 	HasThingByID(context.Context, string, string) error
+
+	// SaveSchema attaches the schema to the channel owned by the specified
+	// user, replacing the attached one.
+	SaveSchema(context.Context, string, string, Schema) error
+
+	// RetrieveSchema retrieves the schema attached to the channel.
+	RetrieveSchema(context.Context, string) (Schema, error)
+
+	// RemoveSchema detaches the schema from the channel owned by the
+	// specified user.
+	RemoveSchema(context.Context, string, string) error
 }
 
 // ChannelCache contains channel-thing connection caching interface.
@@ -31,6 +31,7 @@ type channelRepositoryMock struct {
 	tconns   chan Connection                      // used for syncronization with thing repo
 	cconns   map[string]map[string]things.Channel // used to track connections
 	things   things.ThingRepository
+	schemas  map[string]things.Schema
 }
 
 // NewChannelRepository creates in-memory channel repository.
@@ -40,6 +41,7 @@ func NewChannelRepository(repo things.ThingRepository, tconns chan Connection) t
 		tconns:   tconns,
 		cconns:   make(map[string]map[string]things.Channel),
 		things:   repo,
+		schemas:  make(map[string]things.Schema),
 	}
 }
 
@@ -237,6 +239,41 @@ func (crm *channelRepositoryMock) HasThingByID(_ context.Context, chanID, thingI
 	return nil
 }
 
+func (crm *channelRepositoryMock) SaveSchema(_ context.Context, owner, chanID string, schema things.Schema) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	if _, ok := crm.channels[key(owner, chanID)]; !ok {
+		return things.ErrNotFound
+	}
+
+	crm.schemas[chanID] = schema
+	return nil
+}
+
+func (crm *channelRepositoryMock) RetrieveSchema(_ context.Context, chanID string) (things.Schema, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	schema, ok := crm.schemas[chanID]
+	if !ok {
+		return things.Schema{}, things.ErrNotFound
+	}
+
+	return schema, nil
+}
+
+func (crm *channelRepositoryMock) RemoveSchema(_ context.Context, owner, chanID string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	if _, ok := crm.channels[key(owner, chanID)]; ok {
+		delete(crm.schemas, chanID)
+	}
+
+	return nil
+}
+
 type channelCacheMock struct {
 	mu       sync.Mutex
 	channels map[string]string
@@ -384,6 +384,79 @@ func (m dbMetadata) Value() (driver.Value, error) {
 	return b, err
 }
 
+func (cr channelRepository) SaveSchema(ctx context.Context, owner, chanID string, schema things.Schema) error {
+	q := `INSERT INTO channel_schemas (channel_id, channel_owner, type, definition)
+		  VALUES (:channel_id, :channel_owner, :type, :definition)
+		  ON CONFLICT (channel_id) DO UPDATE SET type = :type, definition = :definition
+		  WHERE channel_schemas.channel_owner = :channel_owner;`
+
+	dbs := dbSchema{
+		Channel:    chanID,
+		Owner:      owner,
+		Type:       schema.Type,
+		Definition: string(schema.Definition),
+	}
+	res, err := cr.db.NamedExecContext(ctx, q, dbs)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errFK, errInvalid:
+				return things.ErrNotFound
+			case errTruncation:
+				return things.ErrMalformedEntity
+			}
+		}
+		return err
+	}
+
+	// The schema of the channel of another owner isn't replaced.
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if cnt == 0 {
+		return things.ErrNotFound
+	}
+
+	return nil
+}
+
+func (cr channelRepository) RetrieveSchema(ctx context.Context, chanID string) (things.Schema, error) {
+	q := `SELECT type, definition FROM channel_schemas WHERE channel_id = $1;`
+
+	var dbs dbSchema
+	if err := cr.db.QueryRowxContext(ctx, q, chanID).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Schema{}, things.ErrNotFound
+		}
+		return things.Schema{}, err
+	}
+
+	return things.Schema{
+		Type:       dbs.Type,
+		Definition: []byte(dbs.Definition),
+	}, nil
+}
+
+func (cr channelRepository) RemoveSchema(ctx context.Context, owner, chanID string) error {
+	dbs := dbSchema{
+		Channel: chanID,
+		Owner:   owner,
+	}
+	q := `DELETE FROM channel_schemas WHERE channel_id = :channel_id AND channel_owner = :channel_owner`
+	cr.db.NamedExecContext(ctx, q, dbs)
+	return nil
+}
+
+type dbSchema struct {
+	Channel    string `db:"channel_id"`
+	Owner      string `db:"channel_owner"`
+	Type       string `db:"type"`
+	Definition string `db:"definition"`
+}
+
 type dbChannel struct {
 	ID       string     `db:"id"`
 	Owner    string     `db:"owner"`
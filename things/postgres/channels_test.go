@@ -456,6 +456,174 @@ func TestChannelRemoval(t *testing.T) {
 	}
 }
 
+func TestSchemaSave(t *testing.T) {
+	email := "schema-save@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	schs, err := chanRepo.Save(context.Background(), things.Channel{
+		ID:    chid,
+		Owner: email,
+	})
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chanID := schs[0].ID
+
+	nonexistentChanID, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	schema := things.Schema{
+		Type:       "json",
+		Definition: []byte(`{"type": "object"}`),
+	}
+
+	cases := []struct {
+		desc   string
+		owner  string
+		chanID string
+		schema things.Schema
+		err    error
+	}{
+		{
+			desc:   "save schema",
+			owner:  email,
+			chanID: chanID,
+			schema: schema,
+			err:    nil,
+		},
+		{
+			desc:   "replace schema",
+			owner:  email,
+			chanID: chanID,
+			schema: things.Schema{Type: "senml", Definition: []byte(`{"fields": {}}`)},
+			err:    nil,
+		},
+		{
+			desc:   "save schema of channel of another owner",
+			owner:  wrongValue,
+			chanID: chanID,
+			schema: schema,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "save schema of non-existing channel",
+			owner:  email,
+			chanID: nonexistentChanID,
+			schema: schema,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "save schema of channel with invalid ID",
+			owner:  email,
+			chanID: wrongID,
+			schema: schema,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := chanRepo.SaveSchema(context.Background(), tc.owner, tc.chanID, tc.schema)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestSchemaRetrieval(t *testing.T) {
+	email := "schema-retrieval@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chs := []things.Channel{}
+	for i := 0; i < 2; i++ {
+		chid, err := uuid.New().ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		chs = append(chs, things.Channel{ID: chid, Owner: email})
+	}
+	schs, err := chanRepo.Save(context.Background(), chs...)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	schema := things.Schema{
+		Type:       "json",
+		Definition: []byte(`{"type": "object"}`),
+	}
+	err = chanRepo.SaveSchema(context.Background(), email, schs[0].ID, schema)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		chanID string
+		schema things.Schema
+		err    error
+	}{
+		"retrieve existing schema": {
+			chanID: schs[0].ID,
+			schema: schema,
+			err:    nil,
+		},
+		"retrieve schema of channel without schema": {
+			chanID: schs[1].ID,
+			err:    things.ErrNotFound,
+		},
+		"retrieve schema of channel with invalid ID": {
+			chanID: wrongID,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		s, err := chanRepo.RetrieveSchema(context.Background(), tc.chanID)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.schema.Type, s.Type, fmt.Sprintf("%s: expected type %s got %s\n", desc, tc.schema.Type, s.Type))
+		if tc.err == nil {
+			assert.JSONEq(t, string(tc.schema.Definition), string(s.Definition), fmt.Sprintf("%s: unexpected definition\n", desc))
+		}
+	}
+}
+
+func TestSchemaRemoval(t *testing.T) {
+	email := "schema-removal@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	chid, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	schs, _ := chanRepo.Save(context.Background(), things.Channel{
+		ID:    chid,
+		Owner: email,
+	})
+	chanID := schs[0].ID
+
+	schema := things.Schema{
+		Type:       "json",
+		Definition: []byte(`{"type": "object"}`),
+	}
+	err = chanRepo.SaveSchema(context.Background(), email, chanID, schema)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// the schema isn't removed by another owner
+	err = chanRepo.RemoveSchema(context.Background(), wrongValue, chanID)
+	require.Nil(t, err, fmt.Sprintf("failed to remove schema due to: %s", err))
+	_, err = chanRepo.RetrieveSchema(context.Background(), chanID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	// show that the removal works the same for both existing and non-existing
+	// (removed) schema
+	for i := 0; i < 2; i++ {
+		err := chanRepo.RemoveSchema(context.Background(), email, chanID)
+		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove schema due to: %s", i, err))
+
+		_, err = chanRepo.RetrieveSchema(context.Background(), chanID)
+		require.Equal(t, things.ErrNotFound, err, fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+
+	// the schema is removed along with its channel
+	err = chanRepo.SaveSchema(context.Background(), email, chanID, schema)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = chanRepo.Remove(context.Background(), email, chanID)
+	require.Nil(t, err, fmt.Sprintf("failed to remove channel due to: %s", err))
+	_, err = chanRepo.RetrieveSchema(context.Background(), chanID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("expected %s got %s", things.ErrNotFound, err))
+}
+
 func TestConnect(t *testing.T) {
 	email := "channel-connect@example.com"
 	dbMiddleware := postgres.NewDatabase(db)
@@ -95,6 +95,22 @@ func migrateDB(db *sqlx.DB) error {
 					`,
 				},
 			},
+			{
+				Id: "things_4",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS channel_schemas (
+						channel_id    UUID,
+						channel_owner VARCHAR(254),
+						type          VARCHAR(32) NOT NULL,
+						definition    JSONB NOT NULL,
+						FOREIGN KEY (channel_id, channel_owner) REFERENCES channels (id, owner) ON DELETE CASCADE ON UPDATE CASCADE,
+						PRIMARY KEY (channel_id)
+					)`,
+				},
+				Down: []string{
+					"DROP TABLE channel_schemas",
+				},
+			},
 		},
 	}
 
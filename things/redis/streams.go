@@ -239,3 +239,19 @@ func (es eventStore) CanAccessByID(ctx context.Context, chanID string, thingID s
 func (es eventStore) Identify(ctx context.Context, key string) (string, error) {
 	return es.svc.Identify(ctx, key)
 }
+
+func (es eventStore) SaveSchema(ctx context.Context, token, chanID string, schema things.Schema) error {
+	return es.svc.SaveSchema(ctx, token, chanID, schema)
+}
+
+func (es eventStore) ViewSchema(ctx context.Context, token, chanID string) (things.Schema, error) {
+	return es.svc.ViewSchema(ctx, token, chanID)
+}
+
+func (es eventStore) RemoveSchema(ctx context.Context, token, chanID string) error {
+	return es.svc.RemoveSchema(ctx, token, chanID)
+}
+
+func (es eventStore) ChannelSchema(ctx context.Context, chanID string) (things.Schema, error) {
+	return es.svc.ChannelSchema(ctx, chanID)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/cloustone/pandas/mainflux"
+	"github.com/cloustone/pandas/mainflux/schema"
 )
 
 var (
@@ -100,6 +101,23 @@ type Service interface {
 
 	// Identify returns thing ID for given thing key.
 	Identify(context.Context, string) (string, error)
+
+	// SaveSchema attaches the schema to the channel identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	SaveSchema(context.Context, string, string, Schema) error
+
+	// ViewSchema retrieves the schema attached to the channel identified by
+	// the provided ID, that belongs to the user identified by the provided
+	// key.
+	ViewSchema(context.Context, string, string) (Schema, error)
+
+	// RemoveSchema detaches the schema from the channel identified by the
+	// provided ID, that belongs to the user identified by the provided key.
+	RemoveSchema(context.Context, string, string) error
+
+	// ChannelSchema retrieves the schema the messages published to the
+	// channel are validated against.
+	ChannelSchema(context.Context, string) (Schema, error)
 }
 
 // PageMetadata contains page metadata that helps navigation.
@@ -346,6 +364,45 @@ func (ts *thingsService) Identify(ctx context.Context, key string) (string, erro
 	return id, nil
 }
 
+func (ts *thingsService) SaveSchema(ctx context.Context, token, chanID string, s Schema) error {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	if _, err := schema.New(s.Type, s.Definition); err != nil {
+		return ErrMalformedEntity
+	}
+
+	return ts.channels.SaveSchema(ctx, res.GetValue(), chanID, s)
+}
+
+func (ts *thingsService) ViewSchema(ctx context.Context, token, chanID string) (Schema, error) {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Schema{}, ErrUnauthorizedAccess
+	}
+
+	if _, err := ts.channels.RetrieveByID(ctx, res.GetValue(), chanID); err != nil {
+		return Schema{}, err
+	}
+
+	return ts.channels.RetrieveSchema(ctx, chanID)
+}
+
+func (ts *thingsService) RemoveSchema(ctx context.Context, token, chanID string) error {
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+
+	return ts.channels.RemoveSchema(ctx, res.GetValue(), chanID)
+}
+
+func (ts *thingsService) ChannelSchema(ctx context.Context, chanID string) (Schema, error) {
+	return ts.channels.RetrieveSchema(ctx, chanID)
+}
+
 func (ts *thingsService) hasThing(ctx context.Context, chanID, key string) (string, error) {
 	thingID, err := ts.thingCache.ID(ctx, key)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cloustone/pandas/mainflux/schema"
 	"github.com/cloustone/pandas/things"
 	"github.com/cloustone/pandas/things/mocks"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +26,15 @@ const (
 var (
 	thing   = things.Thing{Name: "test"}
 	channel = things.Channel{Name: "test"}
+
+	jsonSchema = things.Schema{
+		Type:       schema.JSON,
+		Definition: []byte(`{"type":"object","required":["temp"]}`),
+	}
+	senmlSchema = things.Schema{
+		Type:       schema.SenML,
+		Definition: []byte(`{"fields":{"temp":{"type":"float","unit":"Cel"}}}`),
+	}
 )
 
 func newService(tokens map[string]string) things.Service {
@@ -745,6 +755,183 @@ func TestRemoveChannel(t *testing.T) {
 	}
 }
 
+func TestSaveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	schs, _ := svc.CreateChannels(context.Background(), token, channel)
+	sch := schs[0]
+
+	cases := []struct {
+		desc   string
+		id     string
+		token  string
+		schema things.Schema
+		err    error
+	}{
+		{
+			desc:   "save JSON schema",
+			id:     sch.ID,
+			token:  token,
+			schema: jsonSchema,
+			err:    nil,
+		},
+		{
+			desc:   "save SenML schema",
+			id:     sch.ID,
+			token:  token,
+			schema: senmlSchema,
+			err:    nil,
+		},
+		{
+			desc:   "save schema with wrong credentials",
+			id:     sch.ID,
+			token:  wrongValue,
+			schema: jsonSchema,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "save schema of unknown type",
+			id:     sch.ID,
+			token:  token,
+			schema: things.Schema{Type: "xml", Definition: jsonSchema.Definition},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save schema with malformed definition",
+			id:     sch.ID,
+			token:  token,
+			schema: things.Schema{Type: schema.JSON, Definition: []byte(`{"type":`)},
+			err:    things.ErrMalformedEntity,
+		},
+		{
+			desc:   "save schema of non-existing channel",
+			id:     wrongID,
+			token:  token,
+			schema: jsonSchema,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.SaveSchema(context.Background(), tc.token, tc.id, tc.schema)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestViewSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	schs, _ := svc.CreateChannels(context.Background(), token, channel, channel)
+	sch, other := schs[0], schs[1]
+	err := svc.SaveSchema(context.Background(), token, sch.ID, jsonSchema)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id     string
+		token  string
+		schema things.Schema
+		err    error
+	}{
+		"view existing schema": {
+			id:     sch.ID,
+			token:  token,
+			schema: jsonSchema,
+			err:    nil,
+		},
+		"view schema with wrong credentials": {
+			id:    sch.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		"view schema of channel without schema": {
+			id:    other.ID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+		"view schema of non-existing channel": {
+			id:    wrongID,
+			token: token,
+			err:   things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		s, err := svc.ViewSchema(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.schema, s, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.schema, s))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestRemoveSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	schs, _ := svc.CreateChannels(context.Background(), token, channel)
+	sch := schs[0]
+	err := svc.SaveSchema(context.Background(), token, sch.ID, jsonSchema)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+	}{
+		{
+			desc:  "remove schema with wrong credentials",
+			id:    sch.ID,
+			token: wrongValue,
+			err:   things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "remove existing schema",
+			id:    sch.ID,
+			token: token,
+			err:   nil,
+		},
+		{
+			desc:  "remove removed schema",
+			id:    sch.ID,
+			token: token,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveSchema(context.Background(), tc.token, tc.id)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.ChannelSchema(context.Background(), sch.ID)
+	assert.Equal(t, things.ErrNotFound, err, fmt.Sprintf("retrieve removed schema: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestChannelSchema(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	schs, _ := svc.CreateChannels(context.Background(), token, channel, channel)
+	sch, other := schs[0], schs[1]
+	err := svc.SaveSchema(context.Background(), token, sch.ID, senmlSchema)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := map[string]struct {
+		id     string
+		schema things.Schema
+		err    error
+	}{
+		"retrieve existing schema": {
+			id:     sch.ID,
+			schema: senmlSchema,
+			err:    nil,
+		},
+		"retrieve schema of channel without schema": {
+			id:  other.ID,
+			err: things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		s, err := svc.ChannelSchema(context.Background(), tc.id)
+		assert.Equal(t, tc.schema, s, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.schema, s))
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
 func TestConnect(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 
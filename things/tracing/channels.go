@@ -22,6 +22,9 @@ const (
 	disconnectOp              = "disconnect"
 	hasThingOp                = "has_thing"
 	hasThingByIDOp            = "has_thing_by_id"
+	saveSchemaOp              = "save_schema"
+	retrieveSchemaOp          = "retrieve_schema"
+	removeSchemaOp            = "remove_schema"
 )
 
 var (
@@ -123,6 +126,30 @@ func (crm channelRepositoryMiddleware) HasThingByID(ctx context.Context, chanID,
 	return crm.repo.HasThingByID(ctx, chanID, thingID)
 }
 
+func (crm channelRepositoryMiddleware) SaveSchema(ctx context.Context, owner, chanID string, schema things.Schema) error {
+	span := createSpan(ctx, crm.tracer, saveSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.SaveSchema(ctx, owner, chanID, schema)
+}
+
+func (crm channelRepositoryMiddleware) RetrieveSchema(ctx context.Context, chanID string) (things.Schema, error) {
+	span := createSpan(ctx, crm.tracer, retrieveSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveSchema(ctx, chanID)
+}
+
+func (crm channelRepositoryMiddleware) RemoveSchema(ctx context.Context, owner, chanID string) error {
+	span := createSpan(ctx, crm.tracer, removeSchemaOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RemoveSchema(ctx, owner, chanID)
+}
+
 type channelCacheMiddleware struct {
 	tracer opentracing.Tracer
 	cache  things.ChannelCache